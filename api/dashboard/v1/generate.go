@@ -0,0 +1,4 @@
+// Package dashboardv1 holds the gRPC API definitions and the code generated from them
+package dashboardv1
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative dashboard.proto
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"crypto-dashboard/internal/config"
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/push"
+	"crypto-dashboard/internal/infrastructure/sheets"
+)
+
+// alertTestTimeout bounds the delivery of a test alert to every channel
+const alertTestTimeout = 30 * time.Second
+
+// runAlert dispatches the alert subcommands
+func runAlert(args []string) {
+	if len(args) > 0 && args[0] == "test" {
+		runAlertTest(args[1:])
+		return
+	}
+	fmt.Fprintln(os.Stderr, "usage: server alert test [flags]")
+	os.Exit(exitUsage)
+}
+
+// alertTestFlags are the flags of the alert test command
+type alertTestFlags struct {
+	global      *globalFlags
+	channelList *string
+	coin        *string
+	kind        *string
+	severity    *string
+	message     *string
+	to          *string
+}
+
+func newAlertTestFlags() (*commandFlags, alertTestFlags) {
+	fs, g := newFlagSet("alert test", "")
+	f := alertTestFlags{global: g}
+	f.channelList = fs.String("channel", "", "comma separated channels to test: ntfy, gotify, matrix, whatsapp or sheets (default every configured one)")
+	f.coin = fs.String("coin", "bitcoin", "coin ID of the test alert")
+	f.kind = fs.String("kind", string(models.AlertPriceAbove), "alert kind of the test alert")
+	f.severity = fs.String("severity", string(models.SeverityInfo), "severity of the test alert: info, warning or critical")
+	f.message = fs.String("message", "Test alert from the crypto dashboard; no threshold was crossed.", "message of the test alert")
+	f.to = fs.String("to", "", "WhatsApp number to send the test to; the server's opt-ins are not known here")
+	return fs, f
+}
+
+// runAlertTest sends a synthetic alert through the configured notification
+// channels to check their credentials and message templates
+func runAlertTest(args []string) {
+	fs, f := newAlertTestFlags()
+	parseArgs(fs, args)
+
+	e := load(f.global, nil)
+	switch sev := models.AlertSeverity(*f.severity); sev {
+	case models.SeverityInfo, models.SeverityWarning, models.SeverityCritical:
+	default:
+		fatal(e.logger, "invalid flag", invalid(fmt.Errorf("unknown alert severity: %q", sev)))
+	}
+
+	channels, err := alertTestChannels(e.cfg, *f.to)
+	if err != nil {
+		fatal(e.logger, "failed to set up notification channels", err)
+	}
+	if *f.channelList != "" {
+		wanted := strings.Split(*f.channelList, ",")
+		for _, name := range wanted {
+			if !slices.ContainsFunc(channels, func(c notifyChannel) bool { return c.name == strings.TrimSpace(name) }) {
+				fatal(e.logger, "invalid flag", invalid(fmt.Errorf("channel %q is not configured", name)))
+			}
+		}
+		channels = slices.DeleteFunc(channels, func(c notifyChannel) bool {
+			return !slices.ContainsFunc(wanted, func(name string) bool { return strings.TrimSpace(name) == c.name })
+		})
+	}
+	if len(channels) == 0 {
+		fatal(e.logger, "nothing to test", invalid(errors.New("no notification channel is configured under notify or sheets.alerts_sheet")))
+	}
+
+	alert := models.Alert{
+		RuleID:      "test",
+		CryptoID:    *f.coin,
+		Kind:        models.AlertKind(*f.kind),
+		Severity:    models.AlertSeverity(*f.severity),
+		Message:     *f.message,
+		TriggeredAt: time.Now(),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), alertTestTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, c := range channels {
+		if err := c.notifier.Notify(ctx, alert); err != nil {
+			fmt.Printf("  %-10s failed: %v\n", c.name, err)
+			errs = append(errs, fmt.Errorf("%s: %w", c.name, err))
+			continue
+		}
+		fmt.Printf("  %-10s sent\n", c.name)
+	}
+	if err := errors.Join(errs...); err != nil {
+		fatal(e.logger, "test alert not delivered", err)
+	}
+}
+
+// alertTestChannels returns the configured channels a test alert can reach.
+// WhatsApp needs a recipient given on the command line since opt-ins live in
+// the running server.
+func alertTestChannels(cfg *config.Config, to string) ([]notifyChannel, error) {
+	var recipients push.Recipients
+	if to != "" {
+		optIn := models.OptIn{Channel: models.ChannelWhatsApp, Recipient: to}
+		optIn.Normalize()
+		recipients = staticRecipients{optIn}
+	}
+	channels := slices.DeleteFunc(pushChannels(cfg.Notify, recipients), func(c notifyChannel) bool {
+		return c.name == "whatsapp" && to == ""
+	})
+
+	if cfg.Sheets.Enabled() && cfg.Sheets.AlertsSheet != "" {
+		account, err := sheets.LoadServiceAccount(cfg.Sheets.CredentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load Google credentials: %w", err)
+		}
+		client, err := sheets.NewClient(account, cfg.Sheets.SpreadsheetID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Google Sheets client: %w", err)
+		}
+		channels = append(channels, notifyChannel{"sheets", sheets.AlertNotifier{Appender: client, Sheet: cfg.Sheets.AlertsSheet}})
+	}
+	return channels, nil
+}
+
+// staticRecipients are the recipients of every channel
+type staticRecipients []models.OptIn
+
+// Recipients implements push.Recipients
+func (r staticRecipients) Recipients(models.OptInChannel) ([]models.OptIn, error) {
+	return r, nil
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"crypto-dashboard/internal/application/manifest"
+	"crypto-dashboard/internal/config"
+)
+
+// dashboardClient calls the manifest endpoints of a running server
+type dashboardClient struct {
+	baseURL string
+	session string
+	token   string
+	http    *http.Client
+}
+
+// clientFlags registers the flags that locate a running server
+func clientFlags(fs *flag.FlagSet) (baseURL, session, token *string) {
+	baseURL = fs.String("url", "", "URL of the running server (default http://localhost:<server.port>)")
+	session = fs.String("session", "", "session whose watchlists, theme and charts are managed (default the shared session)")
+	token = fs.String("token", os.Getenv("DASHBOARD_API_TOKEN"), "API token when the server requires one")
+	return baseURL, session, token
+}
+
+func newDashboardClient(cfg *config.Config, baseURL, session, token string) *dashboardClient {
+	if baseURL == "" {
+		baseURL = fmt.Sprintf("http://localhost:%d", cfg.Server.Port)
+	}
+	return &dashboardClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		session: session,
+		token:   token,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// do sends a request and returns the body of a successful response, or the
+// server's error message
+func (c *dashboardClient) do(method, path string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if c.session != "" {
+		req.Header.Set("X-Session-ID", c.session)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error != "" {
+			return nil, &serverError{status: resp.StatusCode, msg: apiErr.Error}
+		}
+		return nil, &serverError{status: resp.StatusCode, msg: fmt.Sprintf("%s %s returned %s", method, path, resp.Status)}
+	}
+	return data, nil
+}
+
+// manifestFlags are shared by plan and apply
+type manifestFlags struct {
+	global                  *globalFlags
+	file                    *string
+	prune                   *bool
+	baseURL, session, token *string
+}
+
+func newManifestFlags(name string) (*commandFlags, manifestFlags) {
+	fs, g := newFlagSet(name, "-f <manifest.yaml>")
+	f := manifestFlags{global: g}
+	f.file = fs.String("f", "", "manifest file, - for stdin")
+	f.prune = fs.Bool("prune", false, "delete resources missing from the sections the manifest declares")
+	f.baseURL, f.session, f.token = clientFlags(fs.FlagSet)
+	return fs, f
+}
+
+// applyFlags are the flags of apply, which can skip the confirmation
+type applyFlags struct {
+	manifestFlags
+	autoApprove *bool
+}
+
+func newApplyFlags() (*commandFlags, applyFlags) {
+	fs, f := newManifestFlags("apply")
+	return fs, applyFlags{manifestFlags: f, autoApprove: fs.Bool("auto-approve", false, "apply without asking for confirmation")}
+}
+
+// readManifest reads the manifest file and checks it locally so syntax errors name the file
+func readManifest(path string) ([]byte, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := manifest.Parse(bytes.NewReader(data)); err != nil {
+		return nil, invalid(fmt.Errorf("%s: %w", path, err))
+	}
+	return data, nil
+}
+
+// sendManifest asks the server for the plan of a manifest, applying it unless dryRun is set
+func sendManifest(client *dashboardClient, data []byte, prune, dryRun bool) (manifest.Plan, error) {
+	query := url.Values{}
+	query.Set("dry_run", strconv.FormatBool(dryRun))
+	query.Set("prune", strconv.FormatBool(prune))
+	body, err := client.do(http.MethodPut, "/api/v1/manifest?"+query.Encode(), bytes.NewReader(data))
+	if err != nil {
+		return manifest.Plan{}, err
+	}
+	var plan manifest.Plan
+	if err := json.Unmarshal(body, &plan); err != nil {
+		return manifest.Plan{}, fmt.Errorf("unexpected server response: %w", err)
+	}
+	return plan, nil
+}
+
+// runPlan prints what applying a manifest would change, including drift, without changing anything
+func runPlan(args []string) {
+	fs, f := newManifestFlags("plan")
+	parseArgs(fs, args)
+	if *f.file == "" {
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+
+	e := load(f.global, nil)
+	data, err := readManifest(*f.file)
+	if err != nil {
+		fatal(e.logger, "invalid manifest", err)
+	}
+	client := newDashboardClient(e.cfg, *f.baseURL, *f.session, *f.token)
+	plan, err := sendManifest(client, data, *f.prune, true)
+	if err != nil {
+		fatal(e.logger, "failed to plan manifest", err)
+	}
+	printPlan(os.Stdout, plan)
+}
+
+// runApply shows the plan of a manifest and applies it once confirmed
+func runApply(args []string) {
+	fs, f := newApplyFlags()
+	parseArgs(fs, args)
+	if *f.file == "" {
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+
+	e := load(f.global, nil)
+	logger := e.logger
+	data, err := readManifest(*f.file)
+	if err != nil {
+		fatal(logger, "invalid manifest", err)
+	}
+	client := newDashboardClient(e.cfg, *f.baseURL, *f.session, *f.token)
+
+	if !*f.autoApprove {
+		if *f.file == "-" {
+			fatal(logger, "cannot confirm a manifest read from stdin", invalid(errors.New("run plan first and pass -auto-approve")))
+		}
+		plan, err := sendManifest(client, data, *f.prune, true)
+		if err != nil {
+			fatal(logger, "failed to plan manifest", err)
+		}
+		printPlan(os.Stdout, plan)
+		if len(plan.Changes) == 0 {
+			return
+		}
+		fmt.Print("\nApply these changes? Only 'yes' is accepted: ")
+		var answer string
+		fmt.Scanln(&answer)
+		if answer != "yes" {
+			fmt.Println("Apply cancelled.")
+			return
+		}
+	}
+
+	plan, err := sendManifest(client, data, *f.prune, false)
+	if err != nil {
+		fatal(logger, "failed to apply manifest", err)
+	}
+	fmt.Printf("Applied: %s.\n", planCounts(plan))
+}
+
+// manifestExportFlags are the flags of the manifest command
+type manifestExportFlags struct {
+	global                  *globalFlags
+	output                  *string
+	baseURL, session, token *string
+}
+
+func newManifestExportFlags() (*commandFlags, manifestExportFlags) {
+	fs, g := newFlagSet("manifest", "")
+	f := manifestExportFlags{global: g}
+	f.output = fs.String("out", "", "output file (default stdout)")
+	f.baseURL, f.session, f.token = clientFlags(fs.FlagSet)
+	return fs, f
+}
+
+// runManifest prints the watchlists, alert rules, theme and charts of a running server as YAML
+func runManifest(args []string) {
+	fs, f := newManifestExportFlags()
+	parseArgs(fs, args)
+
+	e := load(f.global, nil)
+	client := newDashboardClient(e.cfg, *f.baseURL, *f.session, *f.token)
+	data, err := client.do(http.MethodGet, "/api/v1/manifest", nil)
+	if err != nil {
+		fatal(e.logger, "failed to export manifest", err)
+	}
+
+	if *f.output == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*f.output, data, 0o644); err != nil {
+		fatal(e.logger, "failed to write manifest", err)
+	}
+}
+
+// printPlan lists the changes like a diff, + created, ~ updated, - deleted,
+// with the settings an update changes below it
+func printPlan(w io.Writer, plan manifest.Plan) {
+	marks := map[manifest.Action]string{manifest.ActionCreate: "+", manifest.ActionUpdate: "~", manifest.ActionDelete: "-"}
+	for _, c := range plan.Changes {
+		note := ""
+		if c.Drifted {
+			note = " (changed outside the manifest)"
+		}
+		fmt.Fprintf(w, "%s %s %q%s\n", marks[c.Action], strings.ReplaceAll(c.Kind, "_", " "), c.Name, note)
+		printDiff(w, c.Diff)
+	}
+	if len(plan.Changes) == 0 {
+		fmt.Fprintln(w, "No changes. The server matches the manifest.")
+	} else {
+		fmt.Fprintf(w, "\nPlan: %s.\n", planCounts(plan))
+	}
+
+	if len(plan.Drift) > 0 {
+		fmt.Fprintln(w, "\nThe server runs with other providers than declared; change its configuration and restart it:")
+		for _, c := range plan.Drift {
+			fmt.Fprintf(w, "! %s %q\n", c.Kind, c.Name)
+			printDiff(w, c.Diff)
+		}
+	}
+}
+
+func printDiff(w io.Writer, diff []manifest.FieldDiff) {
+	for _, d := range diff {
+		fmt.Fprintf(w, "    %s: %s -> %s\n", d.Field, orNone(d.From), orNone(d.To))
+	}
+}
+
+func orNone(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+	return v
+}
+
+// planCounts summarizes a plan, e.g. "1 to create, 2 to update, 0 to delete, 4 unchanged"
+func planCounts(plan manifest.Plan) string {
+	counts := map[manifest.Action]int{}
+	for _, c := range plan.Changes {
+		counts[c.Action]++
+	}
+	return fmt.Sprintf("%d to create, %d to update, %d to delete, %d unchanged",
+		counts[manifest.ActionCreate], counts[manifest.ActionUpdate], counts[manifest.ActionDelete], plan.Unchanged)
+}
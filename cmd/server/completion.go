@@ -0,0 +1,435 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"crypto-dashboard/internal/config"
+	"crypto-dashboard/internal/infrastructure/api"
+)
+
+// completeCommand is the hidden command completion scripts call for coin IDs
+const completeCommand = "__complete"
+
+// subcommands lists the first argument of the commands that dispatch on it
+var subcommands = map[string][]string{
+	"alert":     {"test"},
+	"export":    {"prices", "holdings", "history", "ticks", "coins"},
+	"portfolio": {"value", "backfill", "rotate-key"},
+}
+
+// commandFlagSets builds the flag set of every command, or of every
+// subcommand as "export prices", the way the command itself does, so the
+// schema describes them without running anything
+var commandFlagSets = map[string]func() *commandFlags{
+	"prices":               flagsOf(newPricesFlags),
+	"watch":                flagsOf(newWatchFlags),
+	"portfolio value":      flagsOf(newPortfolioValueFlags),
+	"portfolio backfill":   flagsOf(newPortfolioBackfillFlags),
+	"portfolio rotate-key": flagsOf(newRotateKeyFlags),
+	"export prices":        exportFlagsOf("prices"),
+	"export holdings":      exportFlagsOf("holdings"),
+	"export history":       exportFlagsOf("history"),
+	"export ticks":         exportFlagsOf("ticks"),
+	"export coins":         exportFlagsOf("coins"),
+	"summary":              flagsOf(newSummaryFlags),
+	"serve":                flagsOf(newServeFlags),
+	"plan":                 func() *commandFlags { fs, _ := newManifestFlags("plan"); return fs },
+	"apply":                flagsOf(newApplyFlags),
+	"manifest":             flagsOf(newManifestExportFlags),
+	"alert test":           flagsOf(newAlertTestFlags),
+	"completion":           flagsOf(newCompletionFlags),
+}
+
+// flagsOf drops the values of a flag set builder
+func flagsOf[T any](build func() (*commandFlags, T)) func() *commandFlags {
+	return func() *commandFlags {
+		fs, _ := build()
+		return fs
+	}
+}
+
+func exportFlagsOf(dataset string) func() *commandFlags {
+	return func() *commandFlags {
+		fs, _ := newExportFlags(dataset)
+		return fs
+	}
+}
+
+// commandSchema describes a command, its positional arguments and flags
+type commandSchema struct {
+	Name        string          `json:"name"`
+	Summary     string          `json:"summary,omitempty"`
+	Args        string          `json:"args,omitempty"`
+	Flags       []flagSchema    `json:"flags,omitempty"`
+	Subcommands []commandSchema `json:"subcommands,omitempty"`
+}
+
+// flagSchema describes a flag. Type is bool, int, float, duration or string.
+type flagSchema struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Default string `json:"default"`
+	Usage   string `json:"usage"`
+	Global  bool   `json:"global,omitempty"`
+}
+
+// coinArgs returns what the positional arguments are completed with:
+// "tickers" for coin IDs or tickers, "coins" for coin IDs, or nothing
+func (c commandSchema) coinArgs() string {
+	switch {
+	case strings.Contains(c.Args, "ticker"):
+		return "tickers"
+	case strings.Contains(c.Args, "coin-id"):
+		return "coins"
+	}
+	return ""
+}
+
+// valueFlags lists the flags that take a value, with their dash
+func (c commandSchema) valueFlags() []string {
+	var names []string
+	for _, f := range c.Flags {
+		if f.Type != "bool" {
+			names = append(names, "-"+f.Name)
+		}
+	}
+	return names
+}
+
+func (c commandSchema) flagNames() []string {
+	names := make([]string, len(c.Flags))
+	for i, f := range c.Flags {
+		names[i] = "-" + f.Name
+	}
+	return names
+}
+
+// describe builds the flag set of a command or subcommand to capture its
+// positional arguments and flags
+func describe(name string) commandSchema {
+	build, ok := commandFlagSets[name]
+	if !ok {
+		return commandSchema{}
+	}
+	fs := build()
+	schema := commandSchema{Args: fs.args}
+	schema.capture(fs.FlagSet)
+	return schema
+}
+
+// secretFlags default to environment variables, which the schema leaves out
+var secretFlags = map[string]bool{"token": true}
+
+// capture records the flags of a flag set
+func (c *commandSchema) capture(fs *flag.FlagSet) {
+	fs.VisitAll(func(f *flag.Flag) {
+		def := f.DefValue
+		if secretFlags[f.Name] {
+			def = ""
+		}
+		c.Flags = append(c.Flags, flagSchema{
+			Name:    f.Name,
+			Type:    flagType(f),
+			Default: def,
+			Usage:   f.Usage,
+			Global:  f.Name == "config" || f.Name == "currency" || f.Name == "quiet",
+		})
+	})
+}
+
+func flagType(f *flag.Flag) string {
+	getter, ok := f.Value.(flag.Getter)
+	if !ok {
+		return "string"
+	}
+	switch getter.Get().(type) {
+	case bool:
+		return "bool"
+	case int, int64, uint, uint64:
+		return "int"
+	case float64:
+		return "float"
+	case time.Duration:
+		return "duration"
+	}
+	return "string"
+}
+
+// schema describes every command
+func schema() []commandSchema {
+	schemas := make([]commandSchema, 0, len(commands))
+	for _, cmd := range commands {
+		s := commandSchema{Name: cmd.name, Summary: cmd.summary}
+		if subs, ok := subcommands[cmd.name]; ok {
+			for _, sub := range subs {
+				subSchema := describe(cmd.name + " " + sub)
+				subSchema.Name = sub
+				s.Subcommands = append(s.Subcommands, subSchema)
+			}
+		} else {
+			described := describe(cmd.name)
+			s.Args, s.Flags = described.Args, described.Flags
+		}
+		schemas = append(schemas, s)
+	}
+	return schemas
+}
+
+// printSchema writes the commands and their flags as JSON for wrapper tools
+func printSchema(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Commands  []commandSchema `json:"commands"`
+		ExitCodes map[string]int  `json:"exit_codes"`
+	}{schema(), exitCodes})
+}
+
+// completionFlags are the flags of the completion command
+type completionFlags struct {
+	name *string
+}
+
+func newCompletionFlags() (*commandFlags, completionFlags) {
+	fs, _ := newFlagSet("completion", "<bash|zsh|fish>")
+	return fs, completionFlags{name: fs.String("name", "server", "name the CLI is installed as")}
+}
+
+// runCompletion prints the completion script of a shell
+func runCompletion(args []string) {
+	fs, f := newCompletionFlags()
+	positional := parseArgs(fs, args)
+	if len(positional) != 1 {
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+
+	tmpl, ok := completionScripts[positional[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown shell %q, expected bash, zsh or fish\n", positional[0])
+		os.Exit(exitUsage)
+	}
+	data := struct {
+		Name     string
+		Func     string
+		Complete string
+		Commands []commandSchema
+	}{*f.name, "_" + strings.NewReplacer("-", "_", ".", "_").Replace(*f.name), completeCommand, schema()}
+	if err := tmpl.Execute(os.Stdout, data); err != nil {
+		fatal(slog.Default(), "failed to write the completion script", err)
+	}
+}
+
+var completionFuncs = template.FuncMap{
+	"join":       strings.Join,
+	"flagNames":  commandSchema.flagNames,
+	"valueFlags": commandSchema.valueFlags,
+	"coinArgs":   commandSchema.coinArgs,
+	"subnames": func(c commandSchema) string {
+		names := make([]string, len(c.Subcommands))
+		for i, sub := range c.Subcommands {
+			names[i] = sub.Name
+		}
+		return strings.Join(names, " ")
+	},
+	"leaves": func(c commandSchema) []commandSchema {
+		if len(c.Subcommands) == 0 {
+			return []commandSchema{c}
+		}
+		return c.Subcommands
+	},
+	"quote": func(s string) string {
+		return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+	},
+}
+
+// The bash script completes commands, subcommands, the flags of each and coin
+// IDs for coin arguments and -id; other flag values complete file names.
+const bashCompletion = `# bash completion for {{.Name}}; load with: source <({{.Name}} completion bash)
+` + bashFunctions
+
+const bashFunctions = `{{.Func}}_coins() {
+	COMPREPLY=($(compgen -W "$({{.Name}} {{.Complete}} "$1" 2>/dev/null)" -- "$2"))
+}
+
+{{.Func}}() {
+	local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+	COMPREPLY=()
+	if [[ $COMP_CWORD -eq 1 ]]; then
+		COMPREPLY=($(compgen -W "{{range .Commands}}{{.Name}} {{end}}help" -- "$cur"))
+		return
+	fi
+
+	local key="${COMP_WORDS[1]}"
+	case "$key" in
+{{- range .Commands}}{{if .Subcommands}}
+	{{.Name}})
+		if [[ $COMP_CWORD -eq 2 ]]; then
+			COMPREPLY=($(compgen -W "{{subnames .}}" -- "$cur"))
+			return
+		fi
+		key="$key ${COMP_WORDS[2]}" ;;
+{{- end}}{{end}}
+	esac
+
+	local flags="" valued="" coins=""
+	case "$key" in
+{{- range .Commands}}{{$parent := .}}{{range leaves .}}
+	{{if eq .Name $parent.Name}}{{quote .Name}}{{else}}{{quote (printf "%s %s" $parent.Name .Name)}}{{end}})
+		flags="{{join (flagNames .) " "}}"
+		valued="{{join (valueFlags .) " "}}"
+		{{- with coinArgs .}}
+		coins={{.}}{{end}} ;;
+{{- end}}{{end}}
+	esac
+
+	if [[ " $valued " == *" $prev "* ]]; then
+		[[ $prev == -id ]] && {{.Func}}_coins coins "$cur"
+		return
+	fi
+	if [[ $cur == -* ]]; then
+		COMPREPLY=($(compgen -W "$flags" -- "$cur"))
+	elif [[ -n $coins ]]; then
+		{{.Func}}_coins "$coins" "$cur"
+	fi
+}
+complete -o default -F {{.Func}} {{.Name}}
+`
+
+// The zsh script reuses the bash one through bashcompinit
+const zshCompletion = `# zsh completion for {{.Name}}; load with: source <({{.Name}} completion zsh)
+autoload -U +X bashcompinit && bashcompinit
+` + bashFunctions
+
+// The fish script describes every command and flag. Go flags take a single
+// dash, which fish calls old style options.
+const fishCompletion = `# fish completion for {{.Name}}; load with: {{.Name}} completion fish | source
+complete -c {{.Name}} -f
+complete -c {{.Name}} -n __fish_use_subcommand -a help -d 'show the commands'
+{{- range .Commands}}{{$parent := .}}
+complete -c {{$.Name}} -n __fish_use_subcommand -a {{.Name}} -d {{quote .Summary}}
+{{- if .Subcommands}}
+complete -c {{$.Name}} -n '__fish_seen_subcommand_from {{.Name}}; and not __fish_seen_subcommand_from {{subnames .}}' -a {{quote (subnames .)}}
+{{- end}}
+{{- range leaves .}}{{$cond := printf "__fish_seen_subcommand_from %s" $parent.Name}}{{if ne .Name $parent.Name}}{{$cond = printf "%s; and __fish_seen_subcommand_from %s" $cond .Name}}{{end}}
+{{- range .Flags}}
+complete -c {{$.Name}} -n {{quote $cond}} -o {{.Name}}{{if ne .Type "bool"}} -r{{if or (eq .Name "config") (eq .Name "f") (eq .Name "out") (eq .Name "ledger")}} -F{{end}}{{end}}{{if eq .Name "id"}} -a '({{$.Name}} {{$.Complete}} coins 2>/dev/null)'{{end}} -d {{quote .Usage}}
+{{- end}}
+{{- with coinArgs .}}
+complete -c {{$.Name}} -n {{quote $cond}} -a '({{$.Name}} {{$.Complete}} {{.}} 2>/dev/null)'
+{{- end}}
+{{- end}}
+{{- end}}
+`
+
+var completionScripts = map[string]*template.Template{
+	"bash": template.Must(template.New("bash").Funcs(completionFuncs).Parse(bashCompletion)),
+	"zsh":  template.Must(template.New("zsh").Funcs(completionFuncs).Parse(zshCompletion)),
+	"fish": template.Must(template.New("fish").Funcs(completionFuncs).Parse(fishCompletion)),
+}
+
+// coinCacheAge is how long the coins completed by the shell are kept before
+// they are fetched again
+const coinCacheAge = 24 * time.Hour
+
+// coinRetryDelay is how long after a failed refresh of the coin cache the
+// next one is tried, so each TAB does not wait on an unreachable provider
+const coinRetryDelay = 15 * time.Minute
+
+// coinCacheSize is the number of coins by market cap offered for completion
+const coinCacheSize = 250
+
+// cachedCoin is a coin of the completion cache
+type cachedCoin struct {
+	ID     string `json:"id"`
+	Symbol string `json:"symbol"`
+}
+
+// runComplete prints the coin IDs, or with "tickers" the IDs and tickers,
+// offered by completion scripts. They come from a cache of the top coins by
+// market cap that is refreshed daily; a stale cache is used when refreshing
+// fails, so completion never waits long on the network.
+func runComplete(args []string) {
+	if len(args) == 0 {
+		return
+	}
+	coins := completionCoins()
+	for _, coin := range coins {
+		fmt.Println(coin.ID)
+	}
+	if args[0] == "tickers" {
+		for _, coin := range coins {
+			fmt.Println(coin.Symbol)
+		}
+	}
+}
+
+// completionCoins reads the coin cache, refreshing it when it is old
+func completionCoins() []cachedCoin {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil
+	}
+	path := filepath.Join(dir, "crypto-dashboard", "coins.json")
+
+	var coins []cachedCoin
+	info, err := os.Stat(path)
+	if err == nil {
+		if data, err := os.ReadFile(path); err == nil {
+			json.Unmarshal(data, &coins)
+		}
+		if time.Since(info.ModTime()) < coinCacheAge {
+			return coins
+		}
+	}
+
+	// A failed refresh is recorded by the time of a marker file, which works
+	// whether or not there is a cache to fall back on
+	failed := path + ".failed"
+	if info, err := os.Stat(failed); err == nil && time.Since(info.ModTime()) < coinRetryDelay {
+		return coins
+	}
+	fresh, err := fetchCompletionCoins()
+	if err != nil {
+		if os.MkdirAll(filepath.Dir(path), 0o755) == nil {
+			os.WriteFile(failed, nil, 0o644)
+		}
+		return coins
+	}
+	if data, err := json.Marshal(fresh); err == nil && os.MkdirAll(filepath.Dir(path), 0o755) == nil {
+		os.WriteFile(path, data, 0o644)
+	}
+	os.Remove(failed)
+	return fresh
+}
+
+// fetchCompletionCoins fetches the top coins with the configured provider.
+// Configuration errors and logs are kept quiet since the shell shows stderr.
+func fetchCompletionCoins() ([]cachedCoin, error) {
+	cfg, err := config.Load("")
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		return nil, err
+	}
+	client := newClient(cfg, cfg.Logger(io.Discard), api.WithTimeout(3*time.Second))
+	prices, err := client.GetTopNCryptos(coinCacheSize, cfg.Currency())
+	if err != nil {
+		return nil, err
+	}
+	coins := make([]cachedCoin, len(prices))
+	for i, p := range prices {
+		coins[i] = cachedCoin{ID: p.ID, Symbol: strings.ToLower(p.Symbol)}
+	}
+	return coins, nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/api"
+)
+
+// Exit codes, so scripts can tell failures apart. A failure matching several
+// kinds reports the most transient one: rate limited, then provider, then
+// not found.
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitUsage       = 2
+	exitValidation  = 3
+	exitProvider    = 4
+	exitNotFound    = 5
+	exitRateLimited = 6
+	exitStale       = 7
+)
+
+// exitCodes documents the exit codes in the command schema
+var exitCodes = map[string]int{
+	"ok":           exitOK,
+	"error":        exitError,
+	"usage":        exitUsage,
+	"validation":   exitValidation,
+	"provider":     exitProvider,
+	"not_found":    exitNotFound,
+	"rate_limited": exitRateLimited,
+	"stale":        exitStale,
+}
+
+// validationError is an invalid configuration, flag or input file
+type validationError struct{ err error }
+
+func (e validationError) Error() string { return e.err.Error() }
+func (e validationError) Unwrap() error { return e.err }
+
+// invalid marks an error as a validation failure
+func invalid(err error) error {
+	return validationError{err}
+}
+
+// serverError is an error response of a running dashboard server
+type serverError struct {
+	status int
+	msg    string
+}
+
+func (e *serverError) Error() string { return e.msg }
+
+// errStale is reported by -fail-on-stale
+var errStale = errors.New("prices are stale")
+
+// exitCode returns the exit code of the error
+func exitCode(err error) int {
+	var validation validationError
+	var server *serverError
+	var network *url.Error
+	switch {
+	case errors.Is(err, errStale):
+		return exitStale
+	case errors.As(err, &validation):
+		return exitValidation
+	case errors.As(err, &server):
+		switch {
+		case server.status == http.StatusTooManyRequests:
+			return exitRateLimited
+		case server.status == http.StatusNotFound:
+			return exitNotFound
+		case server.status >= 500:
+			return exitProvider
+		case server.status == http.StatusBadRequest, server.status == http.StatusUnprocessableEntity:
+			return exitValidation
+		}
+	case errors.Is(err, api.ErrRateLimited):
+		return exitRateLimited
+	case errors.Is(err, api.ErrProviderUnavailable), errors.Is(err, api.ErrCircuitOpen), errors.As(err, &network):
+		return exitProvider
+	case errors.Is(err, api.ErrNotFound), errors.Is(err, os.ErrNotExist):
+		return exitNotFound
+	}
+	return exitError
+}
+
+// fatal logs the error and exits with the code matching it
+func fatal(logger *slog.Logger, msg string, err error) {
+	code := exitCode(err)
+	logger.Error(msg, "error", err, "exit_code", code)
+	os.Exit(code)
+}
+
+// checkStale fails with errStale when a price is marked stale or was last
+// updated more than maxAge ago; zero disables the check
+func checkStale(prices []models.CryptoPrice, maxAge time.Duration) error {
+	if maxAge <= 0 {
+		return nil
+	}
+	var stale []string
+	for _, p := range prices {
+		updated, ok := p.LastUpdatedTime()
+		if p.Stale || (ok && time.Since(updated) > maxAge) {
+			stale = append(stale, p.ID)
+		}
+	}
+	if len(stale) > 0 {
+		return fmt.Errorf("%w: %s not updated within %s", errStale, strings.Join(stale, ", "), maxAge)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"crypto-dashboard/internal/application/coins"
+	"crypto-dashboard/internal/application/replay"
+	"crypto-dashboard/internal/config"
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/api"
+	"crypto-dashboard/internal/infrastructure/coinseed"
+	"crypto-dashboard/internal/infrastructure/export"
+)
+
+const exportUsage = `usage: server export <prices|holdings|history|ticks|coins> [flags]
+
+  prices    current prices of the tracked coins, the -ids list or the -top N by market cap
+  holdings  positions replayed from a -ledger file, valued at current prices
+  history   the price range of -id between -from and -to
+  ticks     the -day of the tracked coins or the -ids list as a recording for serve -replay
+  coins     the -top N coins by market cap as the gzipped coin snapshot bundled in the binary
+`
+
+// exportFlags are the flags of every export dataset; each one uses some of them
+type exportFlags struct {
+	global      *globalFlags
+	formatName  *string
+	columnList  *string
+	output      *string
+	ids         *string
+	top         *int
+	failOnStale *time.Duration
+	ledgerPath  *string
+	id          *string
+	from        *string
+	to          *string
+	dayName     *string
+}
+
+func newExportFlags(dataset string) (*commandFlags, exportFlags) {
+	fs, g := newFlagSet("export "+dataset, "")
+	f := exportFlags{global: g}
+	f.formatName = fs.String("format", "csv", "output format (csv, json)")
+	f.columnList = fs.String("columns", "", "comma separated columns to export (default all)")
+	f.output = fs.String("out", "", "output file (default stdout)")
+	f.ids = fs.String("ids", "", "prices: comma separated coin IDs (default tracked coins)")
+	f.top = fs.Int("top", 0, "prices: export the top N coins by market cap instead; coins: the number of coins (default 500)")
+	f.failOnStale = fs.Duration("fail-on-stale", 0, "prices: exit with code 7 after exporting when a price is older than this")
+	f.ledgerPath = fs.String("ledger", "transactions.json", "holdings: JSON file containing the transaction ledger")
+	f.id = fs.String("id", "bitcoin", "history: coin ID")
+	f.from = fs.String("from", time.Now().AddDate(0, 0, -30).Format(time.DateOnly), "history: start date (YYYY-MM-DD)")
+	f.to = fs.String("to", time.Now().Format(time.DateOnly), "history: end date (YYYY-MM-DD)")
+	f.dayName = fs.String("day", time.Now().AddDate(0, 0, -1).Format(time.DateOnly), "ticks: day to record (YYYY-MM-DD)")
+	return fs, f
+}
+
+// runExport writes prices, holdings or a historical range as CSV or JSON
+func runExport(args []string) {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, exportUsage)
+		os.Exit(exitUsage)
+	}
+	dataset := args[0]
+
+	fs, f := newExportFlags(dataset)
+	parseArgs(fs, args[1:])
+
+	e := load(f.global, nil)
+	cfg, currency, logger := e.cfg, e.currency, e.logger
+	client := newClient(cfg, logger)
+
+	format, err := export.ParseFormat(*f.formatName)
+	if err != nil {
+		fatal(logger, "invalid format", invalid(err))
+	}
+	var columns []string
+	if *f.columnList != "" {
+		columns = strings.Split(*f.columnList, ",")
+	}
+
+	var write func(io.Writer) error
+	var stale error
+	switch dataset {
+	case "prices":
+		prices, err := exportPrices(cfg, client, currency, *f.ids, *f.top)
+		if err != nil {
+			fatal(logger, "failed to fetch prices", err)
+		}
+		write = exportWriter(format, export.PriceColumns, columns, prices)
+		stale = checkStale(prices, *f.failOnStale)
+	case "holdings":
+		holdings, err := valueHoldings(client, currency, openLedger(cfg, *f.ledgerPath))
+		if err != nil {
+			fatal(logger, "failed to value holdings", err)
+		}
+		write = exportWriter(format, export.HoldingColumns, columns, holdings)
+	case "history":
+		points, err := exportHistory(client, currency, *f.id, *f.from, *f.to)
+		if err != nil {
+			fatal(logger, "failed to fetch history", err)
+		}
+		write = exportWriter(format, export.HistoryColumns, columns, points)
+	case "ticks":
+		ticks, err := exportTicks(cfg, client, currency, *f.ids, *f.dayName)
+		if err != nil {
+			fatal(logger, "failed to fetch ticks", err)
+		}
+		// Recordings are always JSON lines, whatever the format
+		write = func(w io.Writer) error { return replay.WriteTicks(w, ticks) }
+	case "coins":
+		ranking, err := client.GetTopNCryptos(cmp.Or(*f.top, coins.CatalogSize), currency)
+		if err != nil {
+			fatal(logger, "failed to fetch coins", err)
+		}
+		// Snapshots are always gzipped JSON, whatever the format
+		write = func(w io.Writer) error { return coinseed.Write(w, coins.CatalogOf(ranking)) }
+	default:
+		fmt.Fprintf(os.Stderr, "unknown export dataset %q\n\n%s", dataset, exportUsage)
+		os.Exit(exitUsage)
+	}
+
+	if *f.output == "" {
+		err = write(os.Stdout)
+	} else {
+		err = writeExportFile(*f.output, write)
+	}
+	if err != nil {
+		fatal(logger, "failed to write export", err)
+	}
+	if stale != nil {
+		fatal(logger, "stale prices", stale)
+	}
+}
+
+// exportWriter validates the column selection up front so a typo fails before any output is written
+func exportWriter[T any](format export.Format, available []export.Column[T], names []string, records []T) func(io.Writer) error {
+	columns, err := export.SelectColumns(available, names)
+	if err != nil {
+		return func(io.Writer) error { return invalid(err) }
+	}
+	return func(w io.Writer) error {
+		return export.Write(w, format, columns, records)
+	}
+}
+
+func writeExportFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func exportPrices(cfg *config.Config, client *api.CoinGeckoClient, currency models.Currency, ids string, top int) ([]models.CryptoPrice, error) {
+	if top > 0 {
+		return client.GetTopNCryptos(top, currency)
+	}
+	coins := cfg.Poller.Coins
+	if ids != "" {
+		coins = strings.Split(ids, ",")
+	}
+	return client.FetchCryptoPrices(coins, currency)
+}
+
+func exportHistory(client *api.CoinGeckoClient, currency models.Currency, id, from, to string) ([]export.HistoryPoint, error) {
+	start, err := time.Parse(time.DateOnly, from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from date: %w", err)
+	}
+	end, err := time.Parse(time.DateOnly, to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to date: %w", err)
+	}
+	if !end.After(start) {
+		return nil, errors.New("to must be after from")
+	}
+
+	points, err := client.GetPriceRange(id, currency, start, end.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	history := make([]export.HistoryPoint, len(points))
+	for i, p := range points {
+		history[i] = export.HistoryPoint{CryptoID: id, Currency: currency, PricePoint: p}
+	}
+	return history, nil
+}
+
+// exportTicks records a day of provider history as replay ticks
+func exportTicks(cfg *config.Config, client *api.CoinGeckoClient, currency models.Currency, ids, dayName string) ([]replay.Tick, error) {
+	day, err := time.Parse(time.DateOnly, dayName)
+	if err != nil {
+		return nil, invalid(fmt.Errorf("invalid day: %w", err))
+	}
+	coins := cfg.Poller.Coins
+	if ids != "" {
+		coins = strings.Split(ids, ",")
+	}
+	var ticks []replay.Tick
+	for _, id := range coins {
+		points, err := client.GetPriceRange(id, currency, day, day.AddDate(0, 0, 1))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", id, err)
+		}
+		ticks = append(ticks, replay.TicksFromHistory(id, currency, points)...)
+	}
+	if len(ticks) == 0 {
+		return nil, replay.ErrEmptyRecording
+	}
+	return ticks, nil
+}
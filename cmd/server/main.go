@@ -1,28 +1,207 @@
+// Command server is the crypto dashboard CLI. Every subcommand shares the
+// configuration file, DASHBOARD_* environment variables and the CoinGecko
+// provider; command flags override the configuration.
 package main
 
 import (
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
 
+	"crypto-dashboard/internal/config"
+	"crypto-dashboard/internal/domain/models"
 	"crypto-dashboard/internal/infrastructure/api"
 )
 
+// command is a CLI subcommand
+type command struct {
+	name    string
+	summary string
+	run     func(args []string)
+}
+
+// commands is set by init since completion describes every command
+var commands []command
+
+func init() {
+	commands = []command{
+		{"prices", "print current prices of the top coins or the given coin IDs", runPrices},
+		{"watch", "refresh the prices of the given coins in the terminal", runWatch},
+		{"portfolio", "value the positions of a transaction ledger", runPortfolio},
+		{"export", "write prices, holdings or a price history as CSV or JSON", runExport},
+		{"summary", "print a weekly Markdown market summary to post to a chat room", runSummary},
+		{"serve", "serve the HTTP API and web dashboard", runServe},
+		{"plan", "show what applying a YAML manifest would change on a running server, including drift", runPlan},
+		{"apply", "reconcile a running server with a YAML manifest of watchlists, alert rules and settings", runApply},
+		{"manifest", "print the watchlists, alert rules and settings of a running server as YAML", runManifest},
+		{"alert", "send a test alert through the configured notification channels", runAlert},
+		{"completion", "print the bash, zsh or fish completion script", runCompletion},
+	}
+}
+
 func main() {
-	// Create API client
-	client := api.NewCoinGeckoClient()
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(exitUsage)
+	}
+
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		if slices.Contains(os.Args[2:], "-json") || slices.Contains(os.Args[2:], "--json") {
+			if err := printSchema(os.Stdout); err != nil {
+				fatal(slog.Default(), "failed to write the command schema", err)
+			}
+			return
+		}
+		usage()
+		return
+	}
+	if name == completeCommand {
+		runComplete(os.Args[2:])
+		return
+	}
+	for _, cmd := range commands {
+		if cmd.name == name {
+			cmd.run(os.Args[2:])
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	usage()
+	os.Exit(exitUsage)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: server <command> [flags]\n\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun 'server <command> -h' for the flags of a command, or 'server help -json'\nfor every command and flag as JSON.")
+	fmt.Fprintln(os.Stderr, "\nExit codes: 0 ok, 1 error, 2 usage, 3 invalid configuration or input,\n4 provider unavailable, 5 not found, 6 rate limited, 7 stale prices (-fail-on-stale).")
+}
+
+// globalFlags are accepted by every command
+type globalFlags struct {
+	config   string
+	currency string
+	quiet    bool
+}
+
+// commandFlags is the flag set of a command with the positional arguments its
+// usage shows
+type commandFlags struct {
+	*flag.FlagSet
+	args string
+}
+
+// newFlagSet creates the flag set of a command with the global flags registered
+func newFlagSet(name, args string) (*commandFlags, *globalFlags) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), strings.TrimSpace("usage: server "+name+" [flags] "+args))
+		fs.PrintDefaults()
+	}
 
-	// Fetch top 20 cryptocurrencies
-	prices, err := client.GetTopNCryptos(20)
+	g := &globalFlags{}
+	fs.StringVar(&g.config, "config", "", "path to a YAML configuration file")
+	fs.StringVar(&g.currency, "currency", "", "fiat currency used to display prices (overrides config)")
+	fs.BoolVar(&g.quiet, "quiet", false, "only log errors, e.g. from cron jobs that check the exit code")
+	return &commandFlags{FlagSet: fs, args: args}, g
+}
+
+// parseArgs parses flags placed before, between or after positional arguments,
+// so "watch bitcoin --interval 30s" works like "watch --interval 30s bitcoin"
+func parseArgs(fs *commandFlags, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			return positional
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// env is what every command needs once its configuration is loaded
+type env struct {
+	cfg      *config.Config
+	logger   *slog.Logger
+	currency models.Currency
+}
+
+// load reads the configuration, applies the command flag overrides and
+// validates the result
+func load(g *globalFlags, override func(cfg *config.Config)) env {
+	cfg, err := config.Load(g.config)
 	if err != nil {
-		log.Fatalf("Error fetching top cryptos: %v", err)
+		fatal(slog.Default(), "failed to load configuration", invalid(err))
+	}
+	if g.currency != "" {
+		cfg.Poller.Currency = strings.ToLower(g.currency)
+	}
+	if g.quiet {
+		cfg.Log.Level = "error"
+	}
+	if override != nil {
+		override(cfg)
 	}
+	if err := cfg.Validate(); err != nil {
+		fatal(slog.Default(), "invalid configuration", invalid(err))
+	}
+
+	logger := cfg.Logger(os.Stderr)
+	slog.SetDefault(logger)
+	return env{cfg: cfg, logger: logger, currency: cfg.Currency()}
+}
+
+// newClient creates the CoinGecko client described by the configuration
+func newClient(cfg *config.Config, logger *slog.Logger, opts ...api.Option) *api.CoinGeckoClient {
+	return api.NewCoinGeckoClient(append([]api.Option{
+		api.WithBaseURL(cfg.API.BaseURL),
+		api.WithTransport(providerTransport(cfg, logger)),
+		api.WithAPIKey(cfg.API.APIKey),
+		api.WithTimeout(cfg.API.Timeout),
+		api.WithConcurrency(cfg.API.Concurrency),
+		api.WithBatchSize(cfg.API.BatchSize),
+		api.WithFixtures(fixtures(cfg)),
+		api.WithLogger(logger),
+	}, opts...)...)
+}
 
-	// Print results with more detailed information
-	for i, price := range prices {
-		fmt.Printf("%2d. %-20s (%s) $%.2f\n",
-			i+1,
-			price.Name,
-			price.Symbol,
-			price.CurrentPrice)
+// providerTransport returns the transport of provider requests with the
+// configured proxy, TLS and DNS settings
+func providerTransport(cfg *config.Config, logger *slog.Logger) http.RoundTripper {
+	minVersion, _ := api.ParseTLSVersion(cfg.API.TLS.MinVersion)
+	var resolver *api.Resolver
+	if cfg.API.DNS.Enabled() {
+		resolver = api.NewResolver(cfg.API.DNS.CacheTTL, cfg.API.DNS.MaxStale, cfg.API.DNS.Fallback...)
 	}
+	transport, err := api.NewTransport(api.TransportConfig{
+		Proxy:              cfg.API.Proxy.URL,
+		NoProxy:            cfg.API.Proxy.NoProxy,
+		CAFile:             cfg.API.TLS.CAFile,
+		CertFile:           cfg.API.TLS.CertFile,
+		KeyFile:            cfg.API.TLS.KeyFile,
+		MinVersion:         minVersion,
+		InsecureSkipVerify: cfg.API.TLS.InsecureSkipVerify,
+		Resolver:           resolver,
+	})
+	if err != nil {
+		fatal(logger, "invalid provider transport", invalid(err))
+	}
+	if cfg.API.TLS.InsecureSkipVerify {
+		logger.Warn("provider certificates are not verified", "setting", "api.tls.insecure_skip_verify")
+	}
+	return transport
+}
+
+// fixtures returns the configured fixture mode; it was checked by Validate
+func fixtures(cfg *config.Config) api.Fixtures {
+	mode, _ := api.ParseFixtureMode(cfg.API.Fixtures.Mode)
+	return api.Fixtures{Dir: cfg.API.Fixtures.Dir, Mode: mode}
 }
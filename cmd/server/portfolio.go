@@ -0,0 +1,267 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/fx"
+	"crypto-dashboard/internal/application/portfolio"
+	"crypto-dashboard/internal/application/pricehistory"
+	"crypto-dashboard/internal/config"
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/api"
+	"crypto-dashboard/internal/infrastructure/envelope"
+	"crypto-dashboard/internal/infrastructure/export"
+	"crypto-dashboard/internal/infrastructure/repository/jsonfile"
+	"crypto-dashboard/internal/infrastructure/repository/memory"
+)
+
+// runPortfolio dispatches the portfolio subcommands
+func runPortfolio(args []string) {
+	if len(args) > 0 {
+		switch args[0] {
+		case "value":
+			runPortfolioValue(args[1:])
+			return
+		case "backfill":
+			runPortfolioBackfill(args[1:])
+			return
+		case "rotate-key":
+			runPortfolioRotateKey(args[1:])
+			return
+		}
+	}
+	fmt.Fprintln(os.Stderr, "usage: server portfolio <value|backfill|rotate-key> [flags]")
+	os.Exit(exitUsage)
+}
+
+// portfolioValueFlags are the flags of the portfolio value command
+type portfolioValueFlags struct {
+	global     *globalFlags
+	ledgerPath *string
+}
+
+func newPortfolioValueFlags() (*commandFlags, portfolioValueFlags) {
+	fs, g := newFlagSet("portfolio value", "")
+	f := portfolioValueFlags{global: g}
+	f.ledgerPath = fs.String("ledger", "transactions.json", "JSON file containing the transaction ledger")
+	return fs, f
+}
+
+// runPortfolioValue prints the open positions of the ledger valued at current prices
+func runPortfolioValue(args []string) {
+	fs, f := newPortfolioValueFlags()
+	parseArgs(fs, args)
+
+	e := load(f.global, nil)
+	holdings, err := valueHoldings(newClient(e.cfg, e.logger), e.currency, openLedger(e.cfg, *f.ledgerPath))
+	if err != nil {
+		fatal(e.logger, "failed to value holdings", err)
+	}
+
+	code := strings.ToUpper(string(e.currency))
+	fmt.Printf("%-20s %14s %14s %14s %16s %16s\n", "COIN", "QUANTITY", "AVG COST", "PRICE", "VALUE", "UNREALIZED")
+	var value, cost, income decimal.Decimal
+	for _, h := range holdings {
+		fmt.Printf("%-20s %14s %14s %14s %16s %16s\n",
+			h.CryptoID, h.Quantity, h.AverageCost.StringFixed(2), h.Price.StringFixed(2),
+			h.Value().StringFixed(2), signed(h.UnrealizedPnL()))
+		value = value.Add(h.Value())
+		cost = cost.Add(h.CostBasis)
+		income = income.Add(h.Income)
+	}
+	fmt.Printf("\nTotal value %s %s, cost basis %s %s, unrealized %s %s\n",
+		value.StringFixed(2), code, cost.StringFixed(2), code, signed(value.Sub(cost)), code)
+	if income.IsPositive() {
+		// Income is reported apart from the P&L; its value on receipt is part of the cost basis
+		fmt.Printf("Income received %s %s (airdrops, staking rewards and interest)\n", income.StringFixed(2), code)
+	}
+}
+
+// portfolioBackfillFlags are the flags of the portfolio backfill command
+type portfolioBackfillFlags struct {
+	global     *globalFlags
+	ledgerPath *string
+	output     *string
+}
+
+func newPortfolioBackfillFlags() (*commandFlags, portfolioBackfillFlags) {
+	fs, g := newFlagSet("portfolio backfill", "")
+	f := portfolioBackfillFlags{global: g}
+	f.ledgerPath = fs.String("ledger", "transactions.json", "JSON file containing the transaction ledger")
+	f.output = fs.String("out", "", "file the completed ledger is written to (default overwrite -ledger)")
+	return fs, f
+}
+
+// runPortfolioBackfill fills the missing prices of a ledger with historical
+// prices and writes the completed ledger, listing entries that need review
+func runPortfolioBackfill(args []string) {
+	fs, f := newPortfolioBackfillFlags()
+	parseArgs(fs, args)
+
+	e := load(f.global, nil)
+	transactions, err := openLedger(e.cfg, *f.ledgerPath).Transactions()
+	if err != nil {
+		fatal(e.logger, "failed to read ledger", err)
+	}
+
+	history := pricehistory.NewService(newClient(e.cfg, e.logger), memory.NewDailyPriceRepository())
+	filled, report := portfolio.Backfill(transactions, history)
+
+	if *f.output == "" {
+		*f.output = *f.ledgerPath
+	}
+	if err := openLedger(e.cfg, *f.output).SaveTransactions(filled); err != nil {
+		fatal(e.logger, "failed to write ledger", err)
+	}
+
+	fmt.Printf("Filled %d price(s), %d lookup(s) failed, wrote %s\n", len(report.Filled), len(report.Failed), *f.output)
+	for _, entry := range report.LowConfidence() {
+		fmt.Printf("  review %-12s %-12s %s  %.2f (%s)\n",
+			entry.TransactionID, entry.CryptoID, entry.Timestamp.Format(time.DateTime), entry.Price, entry.Note)
+	}
+	for _, entry := range report.Failed {
+		fmt.Printf("  failed %-12s %-12s %s  %s\n",
+			entry.TransactionID, entry.CryptoID, entry.Timestamp.Format(time.DateTime), entry.Note)
+	}
+}
+
+// signed formats an amount with two decimals and an explicit sign
+func signed(d decimal.Decimal) string {
+	if d.IsNegative() {
+		return d.StringFixed(2)
+	}
+	return "+" + d.StringFixed(2)
+}
+
+// rotateKeyFlags are the flags of the portfolio rotate-key command
+type rotateKeyFlags struct {
+	global     *globalFlags
+	ledgerPath *string
+	reencrypt  *bool
+}
+
+func newRotateKeyFlags() (*commandFlags, rotateKeyFlags) {
+	fs, g := newFlagSet("portfolio rotate-key", "")
+	f := rotateKeyFlags{global: g}
+	f.ledgerPath = fs.String("ledger", "transactions.json", "JSON file containing the transaction ledger")
+	f.reencrypt = fs.Bool("reencrypt", false, "encrypt every amount again under a new data key")
+	return fs, f
+}
+
+// runPortfolioRotateKey re-wraps the data key of an encrypted ledger with the
+// first configured encryption key, or encrypts a plain ledger
+func runPortfolioRotateKey(args []string) {
+	fs, f := newRotateKeyFlags()
+	parseArgs(fs, args)
+
+	e := load(f.global, nil)
+	if len(e.cfg.Database.EncryptionKeys) == 0 {
+		invalid(errors.New("rotate-key needs database.encryption_keys"))
+	}
+	if err := openLedger(e.cfg, *f.ledgerPath).Rotate(*f.reencrypt); err != nil {
+		fatal(e.logger, "failed to rotate the ledger key", err)
+	}
+	active, _, _ := strings.Cut(e.cfg.Database.EncryptionKeys[0], ":")
+	fmt.Printf("Encrypted %s with key %s\n", *f.ledgerPath, active)
+}
+
+// openLedger returns the ledger file, encrypted with the configured keys when there are any
+func openLedger(cfg *config.Config, path string) *jsonfile.Ledger {
+	if len(cfg.Database.EncryptionKeys) == 0 {
+		return jsonfile.NewLedger(path, nil)
+	}
+	// The configuration was validated, so the keys parse
+	keys, _ := envelope.ParseKeyring(cfg.Database.EncryptionKeys)
+	return jsonfile.NewLedger(path, keys)
+}
+
+// valueHoldings returns the open positions of the ledger valued at current prices
+func valueHoldings(client *api.CoinGeckoClient, currency models.Currency, store *jsonfile.Ledger) ([]export.Holding, error) {
+	holdings, err := openHoldings(client, currency, store)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(holdings))
+	for i, h := range holdings {
+		ids[i] = h.CryptoID
+	}
+
+	prices, err := client.FetchCryptoPrices(ids, currency)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]decimal.Decimal, len(prices))
+	for _, p := range prices {
+		byID[p.ID] = p.CurrentPrice
+	}
+	for i := range holdings {
+		holdings[i].Price = byID[holdings[i].CryptoID]
+	}
+	return holdings, nil
+}
+
+// errNoPositions is returned by openHoldings for a ledger whose positions are all closed
+var errNoPositions = errors.New("ledger has no open positions")
+
+// openHoldings replays the ledger in the given currency, converting foreign
+// transactions at the rate of their own date, and returns the open positions
+// sorted by coin without a price
+func openHoldings(client *api.CoinGeckoClient, currency models.Currency, store *jsonfile.Ledger) ([]export.Holding, error) {
+	transactions, err := store.Transactions()
+	if err != nil {
+		return nil, err
+	}
+	ledger, err := portfolio.NewLedger(transactions)
+	if err != nil {
+		return nil, err
+	}
+	currencies := []models.Currency{currency}
+	for _, tx := range transactions {
+		if !tx.IsMigration() {
+			currencies = append(currencies, tx.PriceCurrency(), tx.FeeCurrency())
+		}
+	}
+	slices.Sort(currencies)
+	currencies = slices.Compact(currencies)
+	if ledger, err = ledger.InCurrency(currency, fx.NewService(client, currencies...)); err != nil {
+		return nil, err
+	}
+	positions, err := ledger.Positions()
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for id, p := range positions {
+		if p.Quantity.IsPositive() {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, errNoPositions
+	}
+	sort.Strings(ids)
+
+	holdings := make([]export.Holding, len(ids))
+	for i, id := range ids {
+		p := positions[id]
+		holdings[i] = export.Holding{
+			CryptoID:    id,
+			Quantity:    p.Quantity,
+			AverageCost: p.AverageCost(),
+			CostBasis:   p.CostBasis,
+			RealizedPnL: p.RealizedPnL,
+			Income:      p.Income,
+			Currency:    currency,
+		}
+	}
+	return holdings, nil
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/api"
+)
+
+// pricesFlags are the flags of the prices command
+type pricesFlags struct {
+	global      *globalFlags
+	top         *int
+	watch       *time.Duration
+	failOnStale *time.Duration
+}
+
+func newPricesFlags() (*commandFlags, pricesFlags) {
+	fs, g := newFlagSet("prices", "[coin-id-or-ticker...]")
+	f := pricesFlags{global: g}
+	f.top = fs.Int("top", 20, "number of coins to list by market cap when no IDs are given")
+	f.watch = fs.Duration("watch", 0, "redraw the prices of the given coins at this interval, highlighting changes")
+	f.failOnStale = fs.Duration("fail-on-stale", 0, "exit with code 7 when a price is older than this")
+	return fs, f
+}
+
+// runPrices prints the top coins by market cap, or the coins given as
+// arguments, once or every -watch interval
+func runPrices(args []string) {
+	fs, f := newPricesFlags()
+	ids := parseArgs(fs, args)
+
+	e := load(f.global, nil)
+	if *f.watch != 0 {
+		if len(ids) == 0 {
+			fatal(e.logger, "invalid flag", invalid(errors.New("-watch needs coin IDs or tickers")))
+		}
+		if *f.watch < time.Second {
+			fatal(e.logger, "invalid flag", invalid(fmt.Errorf("-watch must be at least 1s, got %s", *f.watch)))
+		}
+		// Logs would garble the redrawn table; errors are shown below it instead
+		client := newClient(e.cfg, e.cfg.Logger(io.Discard), api.WithPartialResults())
+		watchPrices(client, ids, e.currency, *f.watch)
+		return
+	}
+	client := newClient(e.cfg, e.logger)
+
+	if len(ids) > 0 {
+		_, prices, err := resolveCoins(client, ids, e.currency)
+		if err != nil {
+			fatal(e.logger, "failed to fetch prices", err)
+		}
+		printPriceTable(os.Stdout, prices)
+		if err := checkStale(prices, *f.failOnStale); err != nil {
+			fatal(e.logger, "stale prices", err)
+		}
+		return
+	}
+
+	if *f.top <= 0 {
+		fatal(e.logger, "invalid flag", invalid(fmt.Errorf("-top must be positive, got %d", *f.top)))
+	}
+	prices, err := client.GetTopNCryptos(*f.top, e.currency)
+	if err != nil {
+		fatal(e.logger, "failed to fetch top cryptos", err)
+	}
+	for i, price := range prices {
+		fmt.Printf("%3d. %-20s (%s) %s %s  24h %+.2f%%  7d %+.2f%%  mcap %.0f\n",
+			i+1,
+			price.Name,
+			price.Symbol,
+			price.CurrentPrice,
+			strings.ToUpper(string(price.Currency)),
+			price.PriceChange24h,
+			price.PriceChange7d,
+			price.MarketCap)
+	}
+	if err := checkStale(prices, *f.failOnStale); err != nil {
+		fatal(e.logger, "stale prices", err)
+	}
+}
+
+// watchPrices redraws the prices of the coins every interval until interrupted
+func watchPrices(client *api.CoinGeckoClient, ids []string, currency models.Currency, interval time.Duration) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	table := &priceTable{color: colorOutput(os.Stdout)}
+	scr := &screen{w: os.Stdout}
+	ids, prices, err := resolveCoins(client, ids, currency)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		scr.draw(watchFrame(table, prices, len(ids), interval, err))
+
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return
+		case <-ticker.C:
+			var latest []models.CryptoPrice
+			// A failed refresh keeps the last prices on screen
+			if latest, err = client.FetchCryptoPrices(ids, currency); len(latest) > 0 {
+				prices = latest
+			}
+		}
+	}
+}
+
+// watchFrame renders the prices with the refresh interval, time and error
+func watchFrame(table *priceTable, prices []models.CryptoPrice, coins int, interval time.Duration, err error) string {
+	var frame strings.Builder
+	fmt.Fprintf(&frame, "Watching %d coin(s) every %s, Ctrl+C to quit\n\n", coins, interval)
+	table.print(&frame, prices)
+	fmt.Fprintf(&frame, "\nUpdated %s\n", time.Now().Format(time.TimeOnly))
+	if err != nil {
+		fmt.Fprintf(&frame, "Last refresh failed: %v\n", err)
+	}
+	return frame.String()
+}
+
+// resolveCoins fetches the prices of the coins. Arguments CoinGecko does not
+// know as IDs are looked up as tickers, so "btc" finds bitcoin; the resolved
+// IDs are returned with the prices.
+func resolveCoins(client *api.CoinGeckoClient, ids []string, currency models.Currency) ([]string, []models.CryptoPrice, error) {
+	prices, err := client.FetchCryptoPrices(ids, currency)
+	var fetchErr *api.FetchError
+	if !errors.As(err, &fetchErr) {
+		return ids, prices, err
+	}
+
+	resolved := slices.Clone(ids)
+	changed := false
+	for i, id := range ids {
+		if failure, failed := fetchErr.Failures[id]; !failed || !errors.Is(failure, api.ErrNotFound) {
+			continue
+		}
+		results, err := client.Search(id)
+		if err != nil {
+			continue
+		}
+		// Results are ranked by market cap, so the first match is the best known coin
+		for _, coin := range results {
+			if strings.EqualFold(coin.Symbol, id) {
+				resolved[i], changed = coin.ID, true
+				break
+			}
+		}
+	}
+	if !changed {
+		return ids, prices, err
+	}
+	prices, err = client.FetchCryptoPrices(resolved, currency)
+	return resolved, prices, err
+}
+
+// printPriceTable prints one line per coin with its price and 24h change
+func printPriceTable(w io.Writer, prices []models.CryptoPrice) {
+	(&priceTable{}).print(w, prices)
+}
+
+// ANSI sequences used to redraw the terminal and highlight changes
+const (
+	clearScreen = "\033[H\033[2J"
+	cursorHome  = "\033[H"
+	clearLine   = "\033[K"
+	clearBelow  = "\033[J"
+	colorUp     = "\033[1;32m"
+	colorDown   = "\033[1;31m"
+	colorReset  = "\033[0m"
+)
+
+// priceTable prints prices, highlighting the cells that changed since the
+// previous print: in green or red on a terminal, with an arrow otherwise
+type priceTable struct {
+	color    bool
+	previous map[string]models.CryptoPrice
+}
+
+func (t *priceTable) print(w io.Writer, prices []models.CryptoPrice) {
+	current := make(map[string]models.CryptoPrice, len(prices))
+	for _, price := range prices {
+		current[price.ID] = price
+		var priceMove, changeMove int
+		if before, ok := t.previous[price.ID]; ok {
+			priceMove = price.CurrentPrice.Cmp(before.CurrentPrice)
+			changeMove = compareFloats(price.PriceChange24h, before.PriceChange24h)
+		}
+		fmt.Fprintf(w, "  %-20s %s %s  24h %s\n",
+			price.ID,
+			t.highlight(fmt.Sprintf("%14s", price.CurrentPrice), priceMove),
+			strings.ToUpper(string(price.Currency)),
+			t.highlight(fmt.Sprintf("%+6.2f%%", price.PriceChange24h), changeMove))
+	}
+	t.previous = current
+}
+
+// highlight marks a cell that went up or down
+func (t *priceTable) highlight(cell string, move int) string {
+	switch {
+	case move == 0:
+		if t.previous != nil && !t.color {
+			return cell + "  "
+		}
+		return cell
+	case t.color && move > 0:
+		return colorUp + cell + colorReset
+	case t.color:
+		return colorDown + cell + colorReset
+	case move > 0:
+		return cell + " ▲"
+	default:
+		return cell + " ▼"
+	}
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a > b:
+		return 1
+	case a < b:
+		return -1
+	}
+	return 0
+}
+
+// colorOutput reports whether highlights can use colors: the file is a
+// terminal and NO_COLOR is not set
+func colorOutput(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// screen redraws a frame in place, overwriting the previous one instead of
+// clearing the terminal, so refreshes do not flicker
+type screen struct {
+	w     io.Writer
+	drawn bool
+}
+
+func (s *screen) draw(frame string) {
+	start := cursorHome
+	if !s.drawn {
+		start, s.drawn = clearScreen, true
+	}
+	fmt.Fprint(s.w, start+strings.ReplaceAll(frame, "\n", clearLine+"\n")+clearBelow)
+}
@@ -0,0 +1,710 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"slices"
+	"syscall"
+	"time"
+
+	"crypto-dashboard/internal/application/alerts"
+	"crypto-dashboard/internal/application/analytics"
+	"crypto-dashboard/internal/application/backtest"
+	"crypto-dashboard/internal/application/calendar"
+	"crypto-dashboard/internal/application/candles"
+	"crypto-dashboard/internal/application/chart"
+	"crypto-dashboard/internal/application/coins"
+	"crypto-dashboard/internal/application/compare"
+	"crypto-dashboard/internal/application/digest"
+	"crypto-dashboard/internal/application/etf"
+	"crypto-dashboard/internal/application/events"
+	"crypto-dashboard/internal/application/icons"
+	"crypto-dashboard/internal/application/incident"
+	"crypto-dashboard/internal/application/manifest"
+	"crypto-dashboard/internal/application/market"
+	"crypto-dashboard/internal/application/optin"
+	"crypto-dashboard/internal/application/poller"
+	"crypto-dashboard/internal/application/preferences"
+	"crypto-dashboard/internal/application/pricehistory"
+	"crypto-dashboard/internal/application/projection"
+	"crypto-dashboard/internal/application/replay"
+	"crypto-dashboard/internal/application/risk"
+	"crypto-dashboard/internal/application/search"
+	"crypto-dashboard/internal/application/shadow"
+	"crypto-dashboard/internal/application/status"
+	"crypto-dashboard/internal/application/telemetry"
+	"crypto-dashboard/internal/application/theme"
+	"crypto-dashboard/internal/application/universe"
+	"crypto-dashboard/internal/application/watchdog"
+	"crypto-dashboard/internal/application/watchlist"
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/config"
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/api"
+	"crypto-dashboard/internal/infrastructure/assets"
+	"crypto-dashboard/internal/infrastructure/beacon"
+	"crypto-dashboard/internal/infrastructure/coinseed"
+	"crypto-dashboard/internal/infrastructure/etfflows"
+	"crypto-dashboard/internal/infrastructure/exchanges"
+	"crypto-dashboard/internal/infrastructure/export"
+	"crypto-dashboard/internal/infrastructure/grpcserver"
+	"crypto-dashboard/internal/infrastructure/metrics"
+	"crypto-dashboard/internal/infrastructure/push"
+	"crypto-dashboard/internal/infrastructure/repository/memory"
+	"crypto-dashboard/internal/infrastructure/server"
+	"crypto-dashboard/internal/infrastructure/sheets"
+	"crypto-dashboard/internal/infrastructure/websocket"
+)
+
+// serveFlags are the flags of the serve command
+type serveFlags struct {
+	global     *globalFlags
+	port       *int
+	ledgerPath *string
+	replayPath *string
+	speedName  *string
+	recordPath *string
+}
+
+func newServeFlags() (*commandFlags, serveFlags) {
+	fs, g := newFlagSet("serve", "")
+	f := serveFlags{global: g}
+	f.port = fs.Int("port", 0, "HTTP port (overrides server.port)")
+	f.ledgerPath = fs.String("ledger", "", "JSON transaction ledger whose open positions /lite totals")
+	f.replayPath = fs.String("replay", "", "tick recording to replay instead of polling the provider")
+	f.speedName = fs.String("speed", "1x", "replay speed, e.g. 1x or 60x")
+	f.recordPath = fs.String("record", "", "append every polled price to a tick recording for -replay")
+	return fs, f
+}
+
+// runServe wires the application services and serves the HTTP API until interrupted
+func runServe(args []string) {
+	fs, f := newServeFlags()
+	parseArgs(fs, args)
+
+	e := load(f.global, func(cfg *config.Config) {
+		if *f.port != 0 {
+			cfg.Server.Port = *f.port
+		}
+	})
+	cfg, logger := e.cfg, e.logger
+
+	// A replay feeds recorded ticks to the poller on a simulated clock, which
+	// candles and alerts follow. Nothing is backfilled or pushed meanwhile.
+	var provider poller.PriceProvider
+	simulated := clock.Real
+	if *f.replayPath != "" {
+		recording, speed := openReplay(*f.replayPath, *f.speedName, logger)
+		simulated = replay.NewClock(recording.Start(), speed, clock.Real)
+		provider = replay.NewProvider(recording, simulated)
+		cfg.Poller.Coins, e.currency = recording.Coins(), recording.Currency()
+		logger.Info("replaying", "ticks", recording.Len(), "from", recording.Start(), "to", recording.End(), "speed", speed,
+			"duration", time.Duration(float64(recording.End().Sub(recording.Start()))/speed))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Upstream requests are instrumented for the /metrics endpoint and stop while
+	// the breaker is open, in which case the poller keeps serving cached prices.
+	// Every provider's calls and bytes are counted against its daily budget.
+	m := metrics.New()
+	usage := api.NewUsage()
+	usage.SetObserver(m)
+	if cfg.API.Budget.DailyCalls > 0 {
+		usage.SetBudget("coingecko", cfg.API.Budget.DailyCalls, cfg.API.Budget.Reserve)
+	}
+	throttled := func() bool { return usage.Throttled("coingecko") }
+	transport := providerTransport(cfg, logger)
+	breaker := api.NewBreaker(cfg.API.Breaker.Failures, cfg.API.Breaker.Cooldown)
+	client := newClient(cfg, logger,
+		api.WithMiddleware(usage.Middleware("coingecko"), m.InstrumentTransport),
+		api.WithBreaker(breaker),
+		api.WithPartialResults(),
+	)
+
+	if provider == nil {
+		provider = client
+	}
+	// A sample of the polled prices may be mirrored to a secondary source to
+	// compare data quality; recordings are never mirrored
+	directory := coinDirectory(client, logger)
+	var monitor *shadow.Monitor
+	if *f.replayPath == "" {
+		if monitor = shadowing(cfg, directory, transport, usage, logger); monitor != nil {
+			provider = monitor.Wrap(provider)
+		}
+	}
+
+	seriesRepo := memory.NewDailySeriesRepository()
+	seriesRepo.SetBudget(cfg.History.CacheBytes)
+	seriesRepo.SetObserver(m)
+
+	var holdings []export.Holding
+	if *f.ledgerPath != "" {
+		var err error
+		if holdings, err = openHoldings(client, e.currency, openLedger(cfg, *f.ledgerPath)); err != nil {
+			fatal(logger, "failed to read ledger", err)
+		}
+	}
+
+	// The poller publishes price changes on the bus; every consumer subscribes independently
+	bus := events.NewBus()
+
+	// Every long-running loop is started through supervise. With the watchdog
+	// enabled, the loops start together once all are defined and are restarted
+	// when they stop making progress; those following the replay clock and
+	// those on the system clock are checked by separate watchdogs.
+	var replayed, background *watchdog.Watchdog
+	if cfg.Poller.WatchdogCycles > 0 {
+		replayed = newWatchdog(cfg.Poller.WatchdogCycles, bus, simulated, m, logger)
+		background = newWatchdog(cfg.Poller.WatchdogCycles, bus, clock.Real, m, logger)
+	}
+	supervise := func(dog *watchdog.Watchdog, c watchdog.Component) {
+		if dog == nil {
+			go c.Run(ctx)
+			return
+		}
+		dog.Supervise(c)
+	}
+	supervise(background, watchdog.Component{
+		Name:     "coin-list",
+		Interval: coinListInterval,
+		Run:      func(ctx context.Context) { directory.RunCatalogSync(ctx, client, coinListInterval) },
+		Progress: directory.LastCatalogSync,
+	})
+
+	p := poller.New(provider, cfg.Poller.Interval, e.currency, cfg.Poller.Coins)
+	p.SetClock(simulated)
+	p.SetObserver(m)
+	p.SetLogger(logger)
+	p.SetPublisher(bus)
+	p.SetInactiveAfter(cfg.Poller.InactiveAfter)
+	for id, levels := range cfg.Poller.Thresholds {
+		p.SetThresholds(id, levels)
+	}
+
+	// Tracked coins follow the active watchlists; the configured coins seed the default one.
+	// Lists following a universe are re-synced whenever its members change.
+	watchlists := watchlist.NewService(memory.NewWatchlistRepository(), p)
+	universes := coinUniverses(cfg, client, e.currency, logger)
+	if universes != nil {
+		watchlists.SetUniverses(universes)
+		universes.SetThrottle(throttled)
+		universes.OnChange(func() {
+			if err := watchlists.Refresh(); err != nil {
+				logger.Error("failed to sync watchlists", "error", err)
+			}
+		})
+		supervise(background, watchdog.Component{Name: "universes", Interval: cfg.Universe.Interval, Run: universes.Run, Progress: universes.LastRun})
+	}
+	if err := watchlists.EnsureDefault(cfg.Poller.Coins); err != nil {
+		fatal(logger, "failed to initialize watchlists", err)
+	}
+
+	// Polled prices are aggregated into candles; alert rules run on every close
+	candleRepo := memory.NewCandleRepository()
+	builder := candles.NewBuilder(cfg.Candles.Interval, candleRepo)
+	builder.SetClock(simulated)
+	builder.SetLogger(logger)
+	p.SetTickSink(builder)
+
+	// History is backfilled for coins without candles, then candles are rolled
+	// up and pruned every interval
+	backfiller := candles.NewBackfiller(client, candleRepo, e.currency, append([]time.Duration{cfg.Candles.Interval}, cfg.Candles.Rollups...)...)
+	backfiller.SetLogger(logger)
+	maintainer := candles.NewMaintainer(candleRepo, cfg.Candles.Interval, cfg.Candles.Rollups, cfg.Candles.Retention, p.Coins)
+	maintainer.SetLogger(logger)
+	maintainer.SetClock(simulated)
+	supervise(replayed, watchdog.Component{
+		Name:     "candle-maintenance",
+		Interval: cfg.Candles.Interval,
+		Run: func(ctx context.Context) {
+			// A restart backfills again, skipping the coins that have candles
+			if *f.replayPath == "" {
+				backfiller.Run(ctx, p.Coins(), cfg.Candles.BackfillDays)
+			}
+			maintainer.Run(ctx, cfg.Candles.Interval)
+		},
+		// The backfill progresses coin by coin before the first maintenance run
+		Progress: func() time.Time {
+			if last := maintainer.LastRun(); !last.IsZero() {
+				return last
+			}
+			return backfiller.LastRun()
+		},
+	})
+	if *f.recordPath != "" {
+		recordTicks(ctx, bus, *f.recordPath, logger)
+	}
+
+	overview := market.NewService(client, e.currency, market.DefaultInterval)
+	overview.SetLogger(logger)
+	overview.SetThrottle(throttled)
+	supervise(background, watchdog.Component{Name: "market", Interval: market.DefaultInterval, Run: overview.Run, Progress: overview.LastRun})
+
+	flows := etfFlows(cfg, api.Chain(transport, usage.Middleware("etf")))
+	if flows != nil {
+		flows.SetLogger(logger)
+		supervise(background, watchdog.Component{Name: "etf-flows", Interval: cfg.ETF.Interval, Run: flows.Run, Progress: flows.LastRun})
+	}
+
+	notifiers := alerts.Notifiers{alerts.LogNotifier{Logger: logger}}
+	if cfg.Sheets.Enabled() && *f.replayPath == "" {
+		notifiers = append(notifiers, startSheetsSink(ctx, cfg.Sheets, p, logger)...)
+	}
+	optIns := optin.NewService(memory.NewOptInRepository())
+	if *f.replayPath == "" {
+		notifiers = append(notifiers, pushNotifiers(cfg.Notify, optIns)...)
+		if cfg.Notify.WhatsApp.Enabled() {
+			optIns.SetSender(whatsAppNotifier(cfg.Notify.WhatsApp, optIns))
+		}
+	}
+	engine := alerts.NewEngine(memory.NewAlertRuleRepository(), candleRepo, cfg.Candles.Interval, notifiers)
+	engine.SetLogger(logger)
+	engine.SetClock(simulated)
+	engine.SetOwners(coinOwners(watchlists, holdings))
+	engine.SetPublisher(bus)
+	builder.OnClose(engine.OnCandleClose)
+	alertEvents, _ := bus.Subscribe(events.KindThresholdCrossed, events.KindCoinInactive, events.KindProviderDegraded, events.KindProviderRecovered)
+	supervise(replayed, watchdog.Component{
+		Name:     "alerts",
+		Interval: cfg.Poller.Interval,
+		Run:      func(ctx context.Context) { engine.Consume(ctx, alertEvents) },
+		Progress: engine.Consumed,
+		Pending:  func() bool { return len(alertEvents) > 0 },
+	})
+	if at, ok := cfg.Notify.DigestTime(); ok && cfg.Notify.Matrix.Enabled() {
+		digests := digest.NewScheduler(p, engine, at, matrixNotifier(cfg.Notify.Matrix))
+		digests.SetLogger(logger)
+		if flows != nil {
+			digests.SetFlows(flows)
+		}
+		supervise(background, watchdog.Component{Name: "digest", Interval: 24 * time.Hour, Run: digests.Run, Progress: digests.LastRun})
+	}
+	// Provider degradations and recoveries are recorded as outages, shown on
+	// the status page and shaded on charts
+	incidents := incident.NewService(memory.NewIncidentRepository())
+	incidents.SetLogger(logger)
+	healthEvents, _ := bus.Subscribe(events.KindProviderDegraded, events.KindProviderRecovered)
+	supervise(background, watchdog.Component{
+		Name:     "incidents",
+		Interval: cfg.Poller.Interval,
+		Run:      func(ctx context.Context) { incidents.Consume(ctx, healthEvents) },
+		Progress: incidents.Consumed,
+		Pending:  func() bool { return len(healthEvents) > 0 },
+	})
+	tracker := analytics.NewTracker(candleRepo, cfg.Candles.Interval, analytics.DefaultIndicatorConfig())
+	builder.OnClose(tracker.OnCandleClose)
+
+	// Polling starts once every consumer is subscribed so none misses the first prices
+	supervise(replayed, watchdog.Component{
+		Name:     "candles",
+		Interval: cfg.Poller.Interval,
+		Run:      builder.Run,
+		Progress: builder.Consumed,
+		Pending:  builder.Pending,
+	})
+	supervise(replayed, watchdog.Component{Name: "poller", Interval: cfg.Poller.Interval, Run: p.Run, Progress: p.LastPoll})
+	if replayed != nil {
+		go background.Run(ctx, cfg.Poller.Interval)
+		go replayed.Run(ctx, cfg.Poller.Interval)
+	}
+
+	calendarEvents := calendar.NewService(memory.NewEventRepository())
+	themes := theme.NewService(memory.NewThemeRepository())
+	charts := chart.NewService(memory.NewChartRepository())
+	comparisons := comparison(cfg, client, transport, usage)
+	manifests := manifest.NewService(watchlists, engine, themes, charts)
+	manifests.SetProviders(runtimeProviders(comparisons, flows))
+	srv := server.New(cfg.Server.Port, server.Services{
+		Poller:         p,
+		Risk:           risk.NewService(memory.NewWatchOrderRepository(), client, e.currency),
+		Watchlists:     watchlists,
+		Alerts:         engine,
+		Projection:     projection.NewService(candleRepo, p, cfg.Candles.Interval, e.currency),
+		Backtest:       backtest.NewService(candleRepo, cfg.Candles.Interval),
+		Calendar:       calendarEvents,
+		Themes:         themes,
+		Preferences:    preferences.NewService(memory.NewCoinOrderRepository()),
+		Charts:         charts,
+		Manifest:       manifests,
+		Search:         fullTextSearch(calendarEvents, incidents, engine),
+		OptIns:         optIns,
+		Incidents:      incidents,
+		Coins:          directory,
+		Icons:          coinIcons(cfg, directory, api.Chain(transport, usage.Middleware("images")), logger),
+		Market:         overview,
+		PriceHistory:   pricehistory.NewService(client, memory.NewDailyPriceRepository()),
+		Inception:      pricehistory.NewInception(client, seriesRepo),
+		Candles:        candleRepo,
+		CandleInterval: cfg.Candles.Interval,
+		Events:         bus,
+		Socket:         socketOptions(cfg.Server.WebSocket),
+		Tiers:          tierPolicy(cfg.Server.Degradation),
+		Compare:        comparisons,
+		Shadow:         monitor,
+		ETF:            flows,
+		Universes:      universes,
+		Status:         status.NewTracker(incidents),
+		Indicators:     tracker,
+		Holdings:       holdings,
+		Targets:        cfg.Portfolio.Targets,
+		DriftBand:      cfg.Portfolio.DriftBand,
+		Usage:          usage,
+		Telemetry:      featureTelemetry(ctx, cfg, transport, logger),
+		Breaker:        breaker,
+		Metrics:        m,
+		Auth:           authentication(cfg.Server.Auth),
+		Logger:         logger,
+	})
+
+	if cfg.Server.GRPCPort != 0 {
+		rpc := grpcserver.New(grpcserver.Services{
+			Poller:         p,
+			Candles:        candleRepo,
+			CandleInterval: cfg.Candles.Interval,
+			Logger:         logger,
+		})
+		go func() {
+			logger.Info("serving grpc", "port", cfg.Server.GRPCPort)
+			if err := rpc.ListenAndServe(ctx, cfg.Server.GRPCPort); err != nil {
+				fatal(logger, "grpc server error", err)
+			}
+		}()
+	}
+
+	logger.Info("listening", "port", cfg.Server.Port)
+	if err := srv.ListenAndServe(ctx); err != nil {
+		fatal(logger, "server error", err)
+	}
+}
+
+// openReplay reads the tick recording and the speed to replay it at
+func openReplay(path, speedName string, logger *slog.Logger) (*replay.Recording, float64) {
+	speed, err := replay.ParseSpeed(speedName)
+	if err != nil {
+		fatal(logger, "invalid flag", invalid(err))
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		fatal(logger, "failed to open recording", err)
+	}
+	defer f.Close()
+	recording, err := replay.ReadRecording(f)
+	if err != nil {
+		fatal(logger, "failed to read recording", invalid(err))
+	}
+	return recording, speed
+}
+
+// recordTicks appends every price update to the tick recording at path
+func recordTicks(ctx context.Context, bus *events.Bus, path string, logger *slog.Logger) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		fatal(logger, "failed to open recording", err)
+	}
+	updates, _ := bus.Subscribe(events.KindPriceUpdated)
+	go func() {
+		defer f.Close()
+		if err := replay.NewRecorder(f).Consume(ctx, updates); err != nil {
+			logger.Error("tick recording stopped", "path", path, "error", err)
+		}
+	}()
+}
+
+// startSheetsSink starts appending snapshots to Google Sheets and returns the
+// alert notifier to register when an alerts sheet is configured
+func startSheetsSink(ctx context.Context, cfg config.SheetsConfig, p *poller.Poller, logger *slog.Logger) []alerts.Notifier {
+	account, err := sheets.LoadServiceAccount(cfg.CredentialsFile)
+	if err != nil {
+		fatal(logger, "failed to load Google credentials", err)
+	}
+	client, err := sheets.NewClient(account, cfg.SpreadsheetID)
+	if err != nil {
+		fatal(logger, "failed to create Google Sheets client", err)
+	}
+
+	sink := sheets.NewSink(client, cfg.Sheet, p, cfg.Interval)
+	sink.SetHoldings(cfg.Holdings)
+	sink.SetLogger(logger)
+	go sink.Run(ctx)
+
+	if cfg.AlertsSheet == "" {
+		return nil
+	}
+	return []alerts.Notifier{sheets.AlertNotifier{Appender: client, Sheet: cfg.AlertsSheet}}
+}
+
+// pushNotifiers returns a notifier for every enabled push service
+func pushNotifiers(cfg config.NotifyConfig, optIns *optin.Service) []alerts.Notifier {
+	var notifiers []alerts.Notifier
+	for _, c := range pushChannels(cfg, optIns) {
+		notifiers = append(notifiers, c.notifier)
+	}
+	return notifiers
+}
+
+// notifyChannel is an enabled notification service
+type notifyChannel struct {
+	name     string
+	notifier alerts.Notifier
+}
+
+// pushChannels returns every enabled push service; WhatsApp messages go to
+// the recipients
+func pushChannels(cfg config.NotifyConfig, recipients push.Recipients) []notifyChannel {
+	var channels []notifyChannel
+	if cfg.Ntfy.Enabled() {
+		channels = append(channels, notifyChannel{"ntfy", push.Ntfy{Server: cfg.Ntfy.Server, Topic: cfg.Ntfy.Topic, Token: cfg.Ntfy.Token}})
+	}
+	if cfg.Gotify.Enabled() {
+		channels = append(channels, notifyChannel{"gotify", push.Gotify{Server: cfg.Gotify.Server, Token: cfg.Gotify.Token}})
+	}
+	if cfg.Matrix.Enabled() {
+		channels = append(channels, notifyChannel{"matrix", matrixNotifier(cfg.Matrix)})
+	}
+	if cfg.WhatsApp.Enabled() {
+		channels = append(channels, notifyChannel{"whatsapp", whatsAppNotifier(cfg.WhatsApp, recipients)})
+	}
+	return channels
+}
+
+// whatsAppNotifier sends alerts and opt-in codes over WhatsApp
+func whatsAppNotifier(cfg config.WhatsAppConfig, recipients push.Recipients) push.WhatsApp {
+	return push.WhatsApp{
+		PhoneNumberID: cfg.PhoneNumberID,
+		Token:         cfg.Token,
+		Template:      cfg.Template,
+		CodeTemplate:  cfg.CodeTemplate,
+		Language:      cfg.Language,
+		Recipients:    recipients,
+	}
+}
+
+// fullTextSearch indexes the text of calendar events, incidents and fired alerts
+func fullTextSearch(calendarEvents *calendar.Service, incidents *incident.Service, engine *alerts.Engine) *search.Service {
+	return search.NewService(search.DefaultMaxAge,
+		search.SourceFunc(func() ([]search.Document, error) {
+			all, err := calendarEvents.All()
+			return search.EventDocuments(all), err
+		}),
+		search.SourceFunc(func() ([]search.Document, error) {
+			all, err := incidents.Incidents("", time.Time{}, time.Time{})
+			return search.IncidentDocuments(all), err
+		}),
+		search.SourceFunc(func() ([]search.Document, error) {
+			return search.AlertDocuments(engine.RecentAlerts()), nil
+		}),
+	)
+}
+
+// comparison returns the price comparison over the configured sources, or nil
+// when none are. Exchanges are skipped when replaying fixtures so the server
+// stays offline. Exchange requests are bounded by compare.timeout.
+func comparison(cfg *config.Config, client *api.CoinGeckoClient, transport http.RoundTripper, usage *api.Usage) *compare.Service {
+	offline := cfg.API.Fixtures.Mode == string(api.FixturesReplay)
+	exchange := func(name string) *http.Client {
+		return &http.Client{Transport: api.Chain(transport, usage.Middleware(name))}
+	}
+	var sources []compare.Source
+	for _, name := range cfg.Compare.Sources {
+		switch {
+		case name == "coingecko":
+			sources = append(sources, exchanges.NewCoinGecko(client))
+		case name == "binance" && !offline:
+			sources = append(sources, exchanges.Binance{Client: exchange(name)})
+		case name == "kraken" && !offline:
+			sources = append(sources, exchanges.Kraken{Client: exchange(name)})
+		}
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+	return compare.NewService(cfg.Compare.Timeout, sources...)
+}
+
+// shadowing returns the monitor mirroring polled prices to compare.shadow.source,
+// or nil when shadowing is off. It is off when replaying fixtures so the
+// server stays offline. Symbols are resolved with the coin directory.
+func shadowing(cfg *config.Config, directory *coins.Service, transport http.RoundTripper, usage *api.Usage, logger *slog.Logger) *shadow.Monitor {
+	shadowCfg := cfg.Compare.Shadow
+	if shadowCfg.Source == "" || cfg.API.Fixtures.Mode == string(api.FixturesReplay) {
+		return nil
+	}
+	client := &http.Client{Transport: api.Chain(transport, usage.Middleware(shadowCfg.Source))}
+	var secondary compare.Source = exchanges.Binance{Client: client}
+	if shadowCfg.Source == "kraken" {
+		secondary = exchanges.Kraken{Client: client}
+	}
+	monitor := shadow.NewMonitor(secondary, directory, shadowCfg.Sample, shadowCfg.TolerancePct, cfg.Compare.Timeout)
+	monitor.SetLogger(logger)
+	logger.Info("shadowing prices", "source", shadowCfg.Source, "sample", shadowCfg.Sample, "tolerance_pct", shadowCfg.TolerancePct)
+	return monitor
+}
+
+// runtimeProviders lists the providers the server runs with, for manifest drift detection
+func runtimeProviders(comparisons *compare.Service, flows *etf.Service) manifest.Providers {
+	var p manifest.Providers
+	if comparisons != nil {
+		p.Compare = comparisons.Sources()
+	}
+	if flows != nil {
+		p.ETF = flows.Assets()
+	}
+	return p
+}
+
+// coinOwners names the watchlists and the ledger portfolio holding a coin
+func coinOwners(watchlists *watchlist.Service, holdings []export.Holding) func(string) []string {
+	return func(cryptoID string) []string {
+		var owners []string
+		lists, _ := watchlists.Containing(cryptoID)
+		for _, w := range lists {
+			owners = append(owners, fmt.Sprintf("watchlist %q", w.Name))
+		}
+		if slices.ContainsFunc(holdings, func(h export.Holding) bool { return h.CryptoID == cryptoID }) {
+			owners = append(owners, "the portfolio")
+		}
+		return owners
+	}
+}
+
+// coinUniverses returns the configured universes, or nil when none are defined
+func coinUniverses(cfg *config.Config, client *api.CoinGeckoClient, currency models.Currency, logger *slog.Logger) *universe.Service {
+	if len(cfg.Universe.Lists) == 0 {
+		return nil
+	}
+	universes, err := universe.NewService(client, currency, cfg.Universe.Interval, cfg.Universe.Universes()...)
+	if err != nil {
+		fatal(logger, "invalid universes", invalid(err))
+	}
+	universes.SetLogger(logger)
+	return universes
+}
+
+// etfTimeout bounds the download of an ETF flow table
+const etfTimeout = 30 * time.Second
+
+// etfFlows returns the ETF flow tracker over the configured sources, or nil
+// when none are set or fixtures are replayed so the server stays offline
+func etfFlows(cfg *config.Config, transport http.RoundTripper) *etf.Service {
+	if !cfg.ETF.Enabled() || cfg.API.Fixtures.Mode == string(api.FixturesReplay) {
+		return nil
+	}
+	httpClient := &http.Client{Timeout: etfTimeout, Transport: transport}
+	sources := make(map[string]etf.Source)
+	for asset, url := range cfg.ETF.Sources {
+		if url != "" {
+			sources[asset] = etfflows.CSV{URL: url, Scale: cfg.ETF.Scale, Client: httpClient}
+		}
+	}
+	return etf.NewService(memory.NewETFFlowRepository(), cfg.ETF.Interval, sources)
+}
+
+// newWatchdog creates a watchdog restarting the loops following the clock
+func newWatchdog(missed int, bus *events.Bus, c clock.Clock, m *metrics.Metrics, logger *slog.Logger) *watchdog.Watchdog {
+	dog := watchdog.New(missed, bus)
+	dog.SetClock(c)
+	dog.SetLogger(logger)
+	dog.SetObserver(m)
+	return dog
+}
+
+// coinListInterval is how often the coin list behind symbol resolution is synced
+const coinListInterval = 24 * time.Hour
+
+// coinDirectory returns the coin lookup service. It answers from the coin
+// list bundled in the binary until the live list is synced by RunCatalogSync.
+func coinDirectory(client *api.CoinGeckoClient, logger *slog.Logger) *coins.Service {
+	directory := coins.NewService(client)
+	directory.SetLogger(logger)
+	if seed, err := coinseed.Load(); err != nil {
+		logger.Warn("failed to load the bundled coin list", "error", err)
+	} else {
+		directory.SetCatalog(seed)
+	}
+	return directory
+}
+
+// coinIcons returns the logo service, storing logos on disk when a cache directory is set
+func coinIcons(cfg *config.Config, directory *coins.Service, transport http.RoundTripper, logger *slog.Logger) *icons.Service {
+	var repo icons.Repository = memory.NewIconRepository()
+	if cfg.Icons.CacheDir != "" {
+		dir, err := assets.NewDir(cfg.Icons.CacheDir)
+		if err != nil {
+			fatal(logger, "failed to open the icon cache", err)
+		}
+		repo = dir
+	}
+	return icons.NewService(directory, assets.Downloader{Client: &http.Client{Timeout: cfg.API.Timeout, Transport: transport}}, repo)
+}
+
+// featureTelemetry returns the feature usage collector. It always serves its
+// preview, but only counts and reports when the operator opted in.
+func featureTelemetry(ctx context.Context, cfg *config.Config, transport http.RoundTripper, logger *slog.Logger) *telemetry.Collector {
+	version := "unknown"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		version = info.Main.Version
+	}
+	collector := telemetry.NewCollector(version)
+	collector.SetLogger(logger)
+	if cfg.Telemetry.Enabled {
+		collector.Enable(beacon.Beacon{URL: cfg.Telemetry.Endpoint, Client: &http.Client{Timeout: cfg.API.Timeout, Transport: transport}}, cfg.Telemetry.Interval)
+		logger.Info("anonymous usage reports enabled", "endpoint", cfg.Telemetry.Endpoint, "interval", cfg.Telemetry.Interval)
+		go collector.Run(ctx)
+	}
+	return collector
+}
+
+// authentication returns the API token check, or nil when no tokens are
+// configured and the API stays open
+func authentication(cfg config.AuthConfig) *server.Auth {
+	if !cfg.Enabled() {
+		return nil
+	}
+	auth := &server.Auth{
+		PublicRead: cfg.PublicRead,
+		SessionKey: []byte(cfg.SessionSecret),
+		SessionTTL: cfg.SessionTTL,
+	}
+	for _, t := range cfg.Tokens {
+		limit := t.RateLimit
+		if limit == 0 {
+			limit = cfg.RateLimit
+		}
+		auth.Tokens = append(auth.Tokens, server.Token{Name: t.Name, Secret: t.Token, ReadOnly: t.ReadOnly, RateLimit: limit})
+	}
+	return auth
+}
+
+// tierPolicy returns the worst data tier each endpoint may serve
+func tierPolicy(degradation map[string]string) server.TierPolicy {
+	policy := make(server.TierPolicy, len(degradation))
+	for endpoint, name := range degradation {
+		// The configuration was validated, so the tiers parse
+		policy[endpoint], _ = server.ParseTier(name)
+	}
+	return policy
+}
+
+// socketOptions returns the compression and message size of WebSocket connections
+func socketOptions(cfg config.WebSocketConfig) websocket.Options {
+	return websocket.Options{
+		Compression: websocket.Compression{
+			Enabled:   cfg.Compression,
+			Threshold: cfg.CompressionThreshold,
+			Level:     cfg.CompressionLevel,
+		},
+		MaxMessageSize: cfg.MaxMessageSize,
+	}
+}
+
+func matrixNotifier(cfg config.MatrixConfig) push.Matrix {
+	return push.Matrix{Homeserver: cfg.Homeserver, AccessToken: cfg.AccessToken, RoomID: cfg.RoomID}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/digest"
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/api"
+	"crypto-dashboard/internal/infrastructure/repository/jsonfile"
+)
+
+// summaryFlags are the flags of the summary command
+type summaryFlags struct {
+	global     *globalFlags
+	top        *int
+	movers     *int
+	ledgerPath *string
+}
+
+func newSummaryFlags() (*commandFlags, summaryFlags) {
+	fs, g := newFlagSet("summary", "")
+	f := summaryFlags{global: g}
+	f.top = fs.Int("top", 100, "number of coins by market cap the movers are picked from")
+	f.movers = fs.Int("movers", 5, "number of top movers to list")
+	f.ledgerPath = fs.String("ledger", "", "JSON transaction ledger whose weekly change is included (default none)")
+	return fs, f
+}
+
+// runSummary prints the weekly market summary as Markdown, ready to post to
+// Slack, Discord or Telegram, e.g. by piping it to a webhook from cron
+func runSummary(args []string) {
+	fs, f := newSummaryFlags()
+	parseArgs(fs, args)
+
+	e := load(f.global, nil)
+	if *f.top <= 0 || *f.top > models.MaxUniverseSize {
+		fatal(e.logger, "invalid flag", invalid(fmt.Errorf("-top must be between 1 and %d, got %d", models.MaxUniverseSize, *f.top)))
+	}
+	if *f.movers <= 0 {
+		fatal(e.logger, "invalid flag", invalid(fmt.Errorf("-movers must be positive, got %d", *f.movers)))
+	}
+	client := newClient(e.cfg, e.logger)
+
+	markets, err := client.GetTopNCryptos(*f.top, e.currency)
+	if err != nil {
+		fatal(e.logger, "failed to fetch top cryptos", err)
+	}
+	summary := digest.Weekly(time.Now(), e.currency, markets, *f.movers)
+
+	// The movers are the point of the summary; the overview is left out when it fails
+	if global, err := client.GetGlobalData(e.currency); err != nil {
+		e.logger.Warn("summary without market overview", "error", err)
+	} else {
+		summary.Global = &global
+	}
+
+	if *f.ledgerPath != "" {
+		delta, err := weekDelta(client, e.currency, openLedger(e.cfg, *f.ledgerPath))
+		if err != nil {
+			fatal(e.logger, "failed to value holdings", err)
+		}
+		summary.Portfolio = &delta
+	}
+
+	fmt.Print(summary.Markdown())
+}
+
+// weekDelta values the open positions of the ledger now and a week ago. A
+// ledger without open positions has a zero delta.
+func weekDelta(client *api.CoinGeckoClient, currency models.Currency, store *jsonfile.Ledger) (digest.PortfolioDelta, error) {
+	holdings, err := openHoldings(client, currency, store)
+	if errors.Is(err, errNoPositions) {
+		return digest.PortfolioDelta{}, nil
+	}
+	if err != nil {
+		return digest.PortfolioDelta{}, err
+	}
+	quantities := make(map[string]decimal.Decimal, len(holdings))
+	ids := make([]string, len(holdings))
+	for i, h := range holdings {
+		quantities[h.CryptoID] = h.Quantity
+		ids[i] = h.CryptoID
+	}
+	// Simple prices carry no 7d change, so the holdings are priced with market data
+	prices, err := client.GetUniverse(models.Universe{Name: "holdings", Kind: models.UniverseCustom, Coins: ids}, currency)
+	if err != nil {
+		return digest.PortfolioDelta{}, err
+	}
+	return digest.WeekDelta(quantities, prices), nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"crypto-dashboard/internal/application/poller"
+	"crypto-dashboard/internal/config"
+	"crypto-dashboard/internal/infrastructure/api"
+)
+
+// watchFlags are the flags of the watch command
+type watchFlags struct {
+	global   *globalFlags
+	interval *time.Duration
+}
+
+func newWatchFlags() (*commandFlags, watchFlags) {
+	fs, g := newFlagSet("watch", "[coin-id...]")
+	f := watchFlags{global: g}
+	f.interval = fs.Duration("interval", 0, "refresh interval (overrides poller.interval)")
+	return fs, f
+}
+
+// runWatch polls the given coins and redraws their prices after every refresh until interrupted
+func runWatch(args []string) {
+	fs, f := newWatchFlags()
+	ids := parseArgs(fs, args)
+
+	e := load(f.global, func(cfg *config.Config) {
+		if len(ids) > 0 {
+			cfg.Poller.Coins = ids
+		}
+		if *f.interval != 0 {
+			cfg.Poller.Interval = *f.interval
+			// Candles are not built here, but the configuration must stay valid
+			cfg.Candles.Interval = max(cfg.Candles.Interval, *f.interval)
+		}
+	})
+
+	// The screen is redrawn on every refresh, so background logs would garble
+	// it; poll errors are shown below the prices instead
+	quiet := e.cfg.Logger(io.Discard)
+	client := newClient(e.cfg, quiet, api.WithPartialResults())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	p := poller.New(client, e.cfg.Poller.Interval, e.currency, e.cfg.Poller.Coins)
+	p.SetLogger(quiet)
+	updates, cancel := p.Subscribe()
+	defer cancel()
+	go p.Run(ctx)
+
+	table := &priceTable{color: colorOutput(os.Stdout)}
+	scr := &screen{w: os.Stdout}
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return
+		case <-updates:
+			scr.draw(watchFrame(table, p.Snapshot(), len(e.cfg.Poller.Coins), e.cfg.Poller.Interval, p.LastError()))
+		}
+	}
+}
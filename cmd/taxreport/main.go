@@ -0,0 +1,143 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"crypto-dashboard/internal/application/fx"
+	"crypto-dashboard/internal/application/portfolio"
+	"crypto-dashboard/internal/application/pricehistory"
+	"crypto-dashboard/internal/application/tax"
+	"crypto-dashboard/internal/config"
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/api"
+	"crypto-dashboard/internal/infrastructure/envelope"
+	"crypto-dashboard/internal/infrastructure/repository/jsonfile"
+	"crypto-dashboard/internal/infrastructure/repository/memory"
+)
+
+func main() {
+	configPath := flag.String("config", "", "path to a YAML configuration file")
+	ledgerPath := flag.String("ledger", "transactions.json", "JSON file containing the transaction ledger")
+	year := flag.Int("year", time.Now().Year()-1, "tax year to report")
+	jurisdictionCode := flag.String("jurisdiction", "us", "tax jurisdiction template")
+	methodName := flag.String("method", "fifo", "cost basis method (fifo, lifo, average)")
+	output := flag.String("out", "capital-gains", "output file name without extension")
+	backfill := flag.Bool("backfill", false, "fill missing transaction prices with historical prices")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fatal(slog.Default(), "failed to load configuration", err)
+	}
+	logger := cfg.Logger(os.Stderr)
+
+	jurisdiction, err := tax.Lookup(*jurisdictionCode)
+	if err != nil {
+		logger.Error("unknown jurisdiction", "error", err, "available", tax.Jurisdictions())
+		os.Exit(1)
+	}
+	method, err := portfolio.ParseCostBasisMethod(*methodName)
+	if err != nil {
+		fatal(logger, "invalid cost basis method", err)
+	}
+
+	// Encrypted ledgers are decrypted with the configured keys, which were validated
+	var keys *envelope.Keyring
+	if len(cfg.Database.EncryptionKeys) > 0 {
+		keys, _ = envelope.ParseKeyring(cfg.Database.EncryptionKeys)
+	}
+	transactions, err := jsonfile.NewLedger(*ledgerPath, keys).Transactions()
+	if err != nil {
+		fatal(logger, "failed to read ledger", err)
+	}
+
+	// Historical FX rates convert foreign-currency transactions on their own date
+	// The configuration was validated, so the fixture mode is known
+	fixtureMode, _ := api.ParseFixtureMode(cfg.API.Fixtures.Mode)
+	client := api.NewCoinGeckoClient(
+		api.WithBaseURL(cfg.API.BaseURL),
+		api.WithAPIKey(cfg.API.APIKey),
+		api.WithTimeout(cfg.API.Timeout),
+		api.WithFixtures(api.Fixtures{Dir: cfg.API.Fixtures.Dir, Mode: fixtureMode}),
+		api.WithLogger(logger),
+	)
+
+	if *backfill {
+		history := pricehistory.NewService(client, memory.NewDailyPriceRepository())
+		var report portfolio.BackfillReport
+		transactions, report = portfolio.Backfill(transactions, history)
+		for _, entry := range report.LowConfidence() {
+			logger.Warn("backfilled price needs review", "transaction", entry.TransactionID, "price", entry.Price, "reason", entry.Note)
+		}
+		for _, entry := range report.Failed {
+			logger.Warn("price backfill failed", "transaction", entry.TransactionID, "error", entry.Err)
+		}
+	}
+
+	ledger, err := portfolio.NewLedger(transactions)
+	if err != nil {
+		fatal(logger, "failed to build ledger", err)
+	}
+	rates := fx.NewService(client, ledgerCurrencies(ledger, jurisdiction.Currency())...)
+
+	report, err := tax.Generate(ledger, tax.Options{
+		Year:         *year,
+		Method:       method,
+		Jurisdiction: jurisdiction,
+		Converter:    rates,
+	})
+	if err != nil {
+		fatal(logger, "failed to generate report", err)
+	}
+
+	if err := writeFile(*output+".csv", report, tax.WriteCSV); err != nil {
+		fatal(logger, "failed to write CSV", err)
+	}
+	if err := writeFile(*output+".pdf", report, tax.WritePDF); err != nil {
+		fatal(logger, "failed to write PDF", err)
+	}
+
+	fmt.Printf("Wrote %s.csv and %s.pdf (%d disposals, total gain %s %s)\n",
+		*output, *output, len(report.Entries), report.TotalGain().StringFixed(2), report.Currency)
+}
+
+// fatal logs the error and exits with a non-zero status
+func fatal(logger *slog.Logger, msg string, err error) {
+	logger.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+func writeFile(path string, report *tax.Report, write func(w io.Writer, r *tax.Report) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f, report); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ledgerCurrencies lists every fiat currency that appears in the ledger plus the target
+func ledgerCurrencies(ledger *portfolio.Ledger, target models.Currency) []models.Currency {
+	seen := map[models.Currency]bool{target: true}
+	currencies := []models.Currency{target}
+	for _, tx := range ledger.Transactions() {
+		if tx.IsMigration() {
+			continue
+		}
+		for _, c := range []models.Currency{tx.PriceCurrency(), tx.FeeCurrency()} {
+			if !seen[c] {
+				seen[c] = true
+				currencies = append(currencies, c)
+			}
+		}
+	}
+	return currencies
+}
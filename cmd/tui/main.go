@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/term"
+
+	"crypto-dashboard/internal/application/market"
+	"crypto-dashboard/internal/application/poller"
+	"crypto-dashboard/internal/config"
+	"crypto-dashboard/internal/infrastructure/api"
+	"crypto-dashboard/internal/infrastructure/tui"
+)
+
+func main() {
+	configPath := flag.String("config", "", "path to a YAML configuration file")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fatal(slog.Default(), "failed to load configuration", err)
+	}
+	logger := cfg.Logger(os.Stderr)
+
+	// The dashboard owns the terminal, so background logs would garble the
+	// screen; poll errors are shown in the status line instead
+	quiet := cfg.Logger(io.Discard)
+
+	// The configuration was validated, so the fixture mode is known
+	fixtureMode, _ := api.ParseFixtureMode(cfg.API.Fixtures.Mode)
+	client := api.NewCoinGeckoClient(
+		api.WithBaseURL(cfg.API.BaseURL),
+		api.WithAPIKey(cfg.API.APIKey),
+		api.WithTimeout(cfg.API.Timeout),
+		api.WithConcurrency(cfg.API.Concurrency),
+		api.WithBatchSize(cfg.API.BatchSize),
+		api.WithFixtures(api.Fixtures{Dir: cfg.API.Fixtures.Dir, Mode: fixtureMode}),
+		api.WithPartialResults(),
+		api.WithBreaker(api.NewBreaker(cfg.API.Breaker.Failures, cfg.API.Breaker.Cooldown)),
+		api.WithLogger(quiet),
+	)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	p := poller.New(client, cfg.Poller.Interval, cfg.Currency(), cfg.Poller.Coins)
+	p.SetLogger(quiet)
+	go p.Run(ctx)
+
+	overview := market.NewService(client, cfg.Currency(), market.DefaultInterval)
+	overview.SetLogger(quiet)
+	go overview.Run(ctx)
+
+	// Raw mode delivers key presses immediately instead of line by line
+	state, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		fatal(logger, "failed to switch terminal to raw mode", err)
+	}
+	defer term.Restore(int(os.Stdin.Fd()), state)
+
+	app := tui.NewApp(p, os.Stdin, os.Stdout)
+	app.SetMarket(overview)
+	if err := app.Run(ctx); err != nil {
+		term.Restore(int(os.Stdin.Fd()), state)
+		fatal(logger, "dashboard error", err)
+	}
+}
+
+// fatal logs the error and exits with a non-zero status
+func fatal(logger *slog.Logger, msg string, err error) {
+	logger.Error(msg, "error", err)
+	os.Exit(1)
+}
@@ -0,0 +1,239 @@
+// Package alerts evaluates user defined alert rules every time a candle closes.
+// Evaluating on candle close rather than on every tick keeps alerts from
+// flapping on intra-candle noise.
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/application/analytics"
+	"crypto-dashboard/internal/application/events"
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// ErrRuleNotFound is returned when an alert rule does not exist
+var ErrRuleNotFound = errors.New("alert rule not found")
+
+// maxRecentAlerts is the number of triggered alerts kept for the API
+const maxRecentAlerts = 100
+
+// RuleRepository persists alert rules
+type RuleRepository interface {
+	SaveRule(rule models.AlertRule) (models.AlertRule, error)
+	ListRules() ([]models.AlertRule, error)
+	DeleteRule(id string) error
+}
+
+// CandleReader loads stored candles
+type CandleReader interface {
+	Candles(cryptoID string, interval time.Duration, limit int) ([]models.Candle, error)
+}
+
+// Notifier delivers triggered alerts
+type Notifier interface {
+	Notify(ctx context.Context, alert models.Alert) error
+}
+
+// Engine manages alert rules and evaluates them against stored candles
+type Engine struct {
+	rules     RuleRepository
+	candles   CandleReader
+	interval  time.Duration
+	notifier  Notifier
+	owners    func(cryptoID string) []string
+	publisher events.Publisher
+	logger    *slog.Logger
+	clock     clock.Clock
+
+	mu       sync.Mutex
+	recent   []models.Alert
+	consumed time.Time
+}
+
+// NewEngine creates an alert engine working on candles of the given interval
+func NewEngine(rules RuleRepository, candles CandleReader, interval time.Duration, notifier Notifier) *Engine {
+	return &Engine{rules: rules, candles: candles, interval: interval, notifier: notifier, logger: slog.Default(), clock: clock.Real}
+}
+
+// SetOwners names what holds a coin, such as watchlists and portfolios, in
+// alerts about the coin itself like it becoming inactive
+func (e *Engine) SetOwners(owners func(cryptoID string) []string) {
+	e.owners = owners
+}
+
+// SetPublisher publishes every raised alert as an AlertTriggered event
+func (e *Engine) SetPublisher(publisher events.Publisher) {
+	e.publisher = publisher
+}
+
+// SetLogger replaces the default logger used to report evaluation failures
+func (e *Engine) SetLogger(logger *slog.Logger) {
+	e.logger = logger
+}
+
+// SetClock replaces the system clock rules and alerts are stamped with
+func (e *Engine) SetClock(c clock.Clock) {
+	e.clock = c
+}
+
+// CreateRule applies defaults, validates and stores a rule
+func (e *Engine) CreateRule(rule models.AlertRule) (models.AlertRule, error) {
+	rule = rule.WithDefaults()
+	if err := rule.Validate(); err != nil {
+		return models.AlertRule{}, err
+	}
+	if rule.CreatedAt.IsZero() {
+		rule.CreatedAt = e.clock.Now().UTC()
+	}
+	return e.rules.SaveRule(rule)
+}
+
+// Rules returns every stored rule
+func (e *Engine) Rules() ([]models.AlertRule, error) {
+	return e.rules.ListRules()
+}
+
+// DeleteRule removes a rule
+func (e *Engine) DeleteRule(id string) error {
+	return e.rules.DeleteRule(id)
+}
+
+// RecentAlerts returns the most recently triggered alerts, newest first
+func (e *Engine) RecentAlerts() []models.Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	alerts := make([]models.Alert, len(e.recent))
+	for i, a := range e.recent {
+		alerts[len(e.recent)-1-i] = a
+	}
+	return alerts
+}
+
+// OnCandleClose evaluates every rule of the candle's coin. It is meant to be
+// registered as a candle builder close handler.
+func (e *Engine) OnCandleClose(candle models.Candle) {
+	if _, err := e.Evaluate(context.Background(), candle.CryptoID); err != nil {
+		e.logger.Error("alert evaluation failed", "crypto", candle.CryptoID, "error", err)
+	}
+}
+
+// Evaluate checks the rules of a coin against its stored candles and notifies
+// every rule whose condition was met by the latest close
+func (e *Engine) Evaluate(ctx context.Context, cryptoID string) ([]models.Alert, error) {
+	rules, err := e.rules.ListRules()
+	if err != nil {
+		return nil, err
+	}
+
+	var coinRules []models.AlertRule
+	lookback := 2
+	for _, r := range rules {
+		if r.CryptoID != cryptoID {
+			continue
+		}
+		coinRules = append(coinRules, r)
+		lookback = max(lookback, r.SlowPeriod+1, r.Period*10+1)
+	}
+	if len(coinRules) == 0 {
+		return nil, nil
+	}
+
+	candles, err := e.candles.Candles(cryptoID, e.interval, lookback)
+	if err != nil {
+		return nil, err
+	}
+	closes := models.Closes(candles)
+
+	var triggered []models.Alert
+	var errs []error
+	for _, rule := range coinRules {
+		message, ok := evaluateRule(rule, closes)
+		if !ok {
+			continue
+		}
+		alert := models.Alert{
+			RuleID:      rule.ID,
+			CryptoID:    rule.CryptoID,
+			Kind:        rule.Kind,
+			Severity:    rule.Severity,
+			Message:     message,
+			Price:       closes[len(closes)-1],
+			TriggeredAt: e.clock.Now().UTC(),
+		}
+		triggered = append(triggered, alert)
+		e.record(alert)
+		if e.notifier != nil {
+			if err := e.notifier.Notify(ctx, alert); err != nil {
+				errs = append(errs, fmt.Errorf("failed to notify rule %s: %w", rule.ID, err))
+			}
+		}
+	}
+	return triggered, errors.Join(errs...)
+}
+
+func (e *Engine) record(alert models.Alert) {
+	e.mu.Lock()
+	e.recent = append(e.recent, alert)
+	if len(e.recent) > maxRecentAlerts {
+		e.recent = e.recent[len(e.recent)-maxRecentAlerts:]
+	}
+	e.mu.Unlock()
+	if e.publisher != nil {
+		e.publisher.Publish(events.AlertTriggered{Alert: alert})
+	}
+}
+
+// evaluateRule reports whether the latest close crossed the rule condition.
+// Every condition is edge triggered: it compares the previous and the latest
+// close so an alert fires once per crossing rather than on every candle.
+func evaluateRule(rule models.AlertRule, closes []float64) (string, bool) {
+	if len(closes) < 2 {
+		return "", false
+	}
+	prev, last := closes[:len(closes)-1], closes
+	prevClose, lastClose := prev[len(prev)-1], last[len(last)-1]
+
+	switch rule.Kind {
+	case models.AlertPriceAbove:
+		if prevClose <= rule.Threshold && lastClose > rule.Threshold {
+			return fmt.Sprintf("%s closed above %.2f at %.2f", rule.CryptoID, rule.Threshold, lastClose), true
+		}
+	case models.AlertPriceBelow:
+		if prevClose >= rule.Threshold && lastClose < rule.Threshold {
+			return fmt.Sprintf("%s closed below %.2f at %.2f", rule.CryptoID, rule.Threshold, lastClose), true
+		}
+	case models.AlertGoldenCross, models.AlertDeathCross:
+		prevFast, ok1 := analytics.LastSMA(prev, rule.FastPeriod)
+		prevSlow, ok2 := analytics.LastSMA(prev, rule.SlowPeriod)
+		fast, ok3 := analytics.LastSMA(last, rule.FastPeriod)
+		slow, ok4 := analytics.LastSMA(last, rule.SlowPeriod)
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			return "", false
+		}
+		if rule.Kind == models.AlertGoldenCross && prevFast <= prevSlow && fast > slow {
+			return fmt.Sprintf("%s golden cross: SMA%d crossed above SMA%d", rule.CryptoID, rule.FastPeriod, rule.SlowPeriod), true
+		}
+		if rule.Kind == models.AlertDeathCross && prevFast >= prevSlow && fast < slow {
+			return fmt.Sprintf("%s death cross: SMA%d crossed below SMA%d", rule.CryptoID, rule.FastPeriod, rule.SlowPeriod), true
+		}
+	case models.AlertRSIBelow, models.AlertRSIAbove:
+		prevRSI, ok1 := analytics.LastRSI(prev, rule.Period)
+		lastRSI, ok2 := analytics.LastRSI(last, rule.Period)
+		if !ok1 || !ok2 {
+			return "", false
+		}
+		if rule.Kind == models.AlertRSIBelow && prevRSI >= rule.Threshold && lastRSI < rule.Threshold {
+			return fmt.Sprintf("%s RSI%d dropped below %.0f (%.1f)", rule.CryptoID, rule.Period, rule.Threshold, lastRSI), true
+		}
+		if rule.Kind == models.AlertRSIAbove && prevRSI <= rule.Threshold && lastRSI > rule.Threshold {
+			return fmt.Sprintf("%s RSI%d rose above %.0f (%.1f)", rule.CryptoID, rule.Period, rule.Threshold, lastRSI), true
+		}
+	}
+	return "", false
+}
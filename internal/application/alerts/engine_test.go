@@ -0,0 +1,119 @@
+package alerts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+type memRules struct {
+	rules []models.AlertRule
+}
+
+func (m *memRules) SaveRule(r models.AlertRule) (models.AlertRule, error) {
+	r.ID = string(r.Kind)
+	m.rules = append(m.rules, r)
+	return r, nil
+}
+
+func (m *memRules) ListRules() ([]models.AlertRule, error) { return m.rules, nil }
+
+func (m *memRules) DeleteRule(id string) error { return nil }
+
+type memCandles struct {
+	closes []float64
+}
+
+func (m *memCandles) Candles(id string, interval time.Duration, limit int) ([]models.Candle, error) {
+	closes := m.closes
+	if limit > 0 && len(closes) > limit {
+		closes = closes[len(closes)-limit:]
+	}
+	candles := make([]models.Candle, len(closes))
+	for i, c := range closes {
+		candles[i] = models.Candle{CryptoID: id, Close: c}
+	}
+	return candles, nil
+}
+
+type recordingNotifier struct {
+	alerts []models.Alert
+	err    error
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, a models.Alert) error {
+	r.alerts = append(r.alerts, a)
+	return r.err
+}
+
+func TestEngine_PriceCrossing(t *testing.T) {
+	candles := &memCandles{closes: []float64{90, 95}}
+	notifier := &recordingNotifier{}
+	engine := NewEngine(&memRules{}, candles, time.Hour, notifier)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	engine.SetClock(clock.NewFake(now))
+	engine.CreateRule(models.AlertRule{CryptoID: "bitcoin", Kind: models.AlertPriceAbove, Threshold: 100})
+
+	if alerts, _ := engine.Evaluate(context.Background(), "bitcoin"); len(alerts) != 0 {
+		t.Fatalf("Expected no alert below threshold, got %d", len(alerts))
+	}
+
+	candles.closes = append(candles.closes, 105)
+	if alerts, _ := engine.Evaluate(context.Background(), "bitcoin"); len(alerts) != 1 || !alerts[0].TriggeredAt.Equal(now) {
+		t.Fatalf("Expected alert on crossing stamped with the clock, got %+v", alerts)
+	}
+
+	// Staying above the threshold must not trigger again
+	candles.closes = append(candles.closes, 110)
+	if alerts, _ := engine.Evaluate(context.Background(), "bitcoin"); len(alerts) != 0 {
+		t.Errorf("Expected no repeated alert, got %d", len(alerts))
+	}
+
+	if len(notifier.alerts) != 1 || len(engine.RecentAlerts()) != 1 {
+		t.Errorf("Expected exactly one notification, got %d", len(notifier.alerts))
+	}
+}
+
+func TestEngine_GoldenAndDeathCross(t *testing.T) {
+	// A long decline followed by a sharp rally pushes SMA3 above SMA6
+	candles := &memCandles{closes: []float64{20, 19, 18, 17, 16, 15, 14}}
+	engine := NewEngine(&memRules{}, candles, time.Hour, nil)
+	engine.CreateRule(models.AlertRule{CryptoID: "bitcoin", Kind: models.AlertGoldenCross, FastPeriod: 3, SlowPeriod: 6})
+	engine.CreateRule(models.AlertRule{CryptoID: "bitcoin", Kind: models.AlertDeathCross, FastPeriod: 3, SlowPeriod: 6})
+
+	var kinds []models.AlertKind
+	for _, price := range []float64{20, 26, 30, 12, 8, 5} {
+		candles.closes = append(candles.closes, price)
+		alerts, _ := engine.Evaluate(context.Background(), "bitcoin")
+		for _, a := range alerts {
+			kinds = append(kinds, a.Kind)
+		}
+	}
+
+	if len(kinds) != 2 || kinds[0] != models.AlertGoldenCross || kinds[1] != models.AlertDeathCross {
+		t.Errorf("Expected golden then death cross, got %v", kinds)
+	}
+}
+
+func TestEngine_RSIThreshold(t *testing.T) {
+	var closes []float64
+	for i := 0; i < 20; i++ {
+		closes = append(closes, 100+float64(i%2))
+	}
+	candles := &memCandles{closes: closes}
+	engine := NewEngine(&memRules{}, candles, time.Hour, nil)
+	engine.CreateRule(models.AlertRule{CryptoID: "bitcoin", Kind: models.AlertRSIBelow})
+
+	triggered := 0
+	for i := 0; i < 5; i++ {
+		candles.closes = append(candles.closes, candles.closes[len(candles.closes)-1]-5)
+		alerts, _ := engine.Evaluate(context.Background(), "bitcoin")
+		triggered += len(alerts)
+	}
+	if triggered != 1 {
+		t.Errorf("Expected a single oversold alert, got %d", triggered)
+	}
+}
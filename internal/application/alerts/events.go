@@ -0,0 +1,105 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"crypto-dashboard/internal/application/events"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Consume turns bus events into alerts until the channel is closed or the
+// context is cancelled. Threshold crossings and inactive coins are notified
+// like rule alerts; a degraded or recovered provider is logged since it
+// concerns no single coin.
+func (e *Engine) Consume(ctx context.Context, updates <-chan events.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-updates:
+			if !ok {
+				return
+			}
+			e.handleEvent(ctx, event)
+			now := e.clock.Now()
+			e.mu.Lock()
+			e.consumed = now
+			e.mu.Unlock()
+		}
+	}
+}
+
+// Consumed returns when Consume last finished handling an event, or the zero
+// time if it has not yet
+func (e *Engine) Consumed() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.consumed
+}
+
+func (e *Engine) handleEvent(ctx context.Context, event events.Event) {
+	switch ev := event.(type) {
+	case events.ThresholdCrossed:
+		alert := crossingAlert(ev)
+		e.record(alert)
+		if e.notifier != nil {
+			if err := e.notifier.Notify(ctx, alert); err != nil {
+				e.logger.Error("failed to notify threshold crossing", "crypto", ev.CryptoID, "error", err)
+			}
+		}
+	case events.CoinInactive:
+		var owners []string
+		if e.owners != nil {
+			owners = e.owners(ev.CryptoID)
+		}
+		alert := inactiveAlert(ev, owners)
+		e.record(alert)
+		if e.notifier != nil {
+			if err := e.notifier.Notify(ctx, alert); err != nil {
+				e.logger.Error("failed to notify inactive coin", "crypto", ev.CryptoID, "error", err)
+			}
+		}
+	case events.ProviderDegraded:
+		e.logger.Warn("price provider degraded", "failures", ev.Failures, "error", ev.Error)
+	case events.ProviderRecovered:
+		e.logger.Info("price provider recovered", "downtime", ev.Downtime)
+	}
+}
+
+// inactiveAlert describes a coin the provider stopped updating and names what holds it
+func inactiveAlert(ev events.CoinInactive, owners []string) models.Alert {
+	message := fmt.Sprintf("%s was never returned by the price provider", ev.CryptoID)
+	if !ev.LastUpdate.IsZero() {
+		message = fmt.Sprintf("%s has not been updated since %s", ev.CryptoID, ev.LastUpdate.Format("2006-01-02 15:04 UTC"))
+	}
+	message += "; it may have been delisted, renamed or migrated"
+	if len(owners) > 0 {
+		message += ". Held in " + strings.Join(owners, ", ")
+	}
+	return models.Alert{
+		CryptoID:    ev.CryptoID,
+		Kind:        models.AlertCoinInactive,
+		Severity:    models.DefaultSeverity(models.AlertCoinInactive),
+		Message:     message,
+		TriggeredAt: ev.At,
+	}
+}
+
+// crossingAlert describes a threshold crossing as an alert without a rule
+func crossingAlert(ev events.ThresholdCrossed) models.Alert {
+	kind, side := models.AlertPriceAbove, "above"
+	if ev.Direction == events.Down {
+		kind, side = models.AlertPriceBelow, "below"
+	}
+	return models.Alert{
+		CryptoID:    ev.CryptoID,
+		Kind:        kind,
+		Severity:    models.DefaultSeverity(kind),
+		Message:     fmt.Sprintf("%s crossed %s %s at %s", ev.CryptoID, side, ev.Threshold, ev.Price),
+		Price:       ev.Price.InexactFloat64(),
+		TriggeredAt: ev.At,
+	}
+}
@@ -0,0 +1,92 @@
+package alerts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/events"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestEngine_ConsumesThresholdCrossings(t *testing.T) {
+	notifier := &recordingNotifier{}
+	engine := NewEngine(&memRules{}, &memCandles{}, time.Hour, notifier)
+
+	bus := events.NewBus()
+	updates, cancel := bus.Subscribe()
+	done := make(chan struct{})
+	go func() {
+		engine.Consume(context.Background(), updates)
+		close(done)
+	}()
+
+	bus.Publish(events.ThresholdCrossed{
+		CryptoID:  "bitcoin",
+		Threshold: decimal.NewFromInt(60000),
+		Direction: events.Down,
+		Price:     decimal.RequireFromString("59990.5"),
+		At:        time.Now(),
+	})
+	bus.Publish(events.ProviderDegraded{Failures: 3, Error: "timeout"})
+	cancel()
+	<-done
+
+	if len(notifier.alerts) != 1 {
+		t.Fatalf("Expected one notified alert, got %d", len(notifier.alerts))
+	}
+	alert := notifier.alerts[0]
+	if alert.Kind != models.AlertPriceBelow || alert.Message != "bitcoin crossed below 60000 at 59990.5" {
+		t.Errorf("Unexpected alert: %+v", alert)
+	}
+	if recent := engine.RecentAlerts(); len(recent) != 1 {
+		t.Errorf("Expected the crossing among recent alerts, got %d", len(recent))
+	}
+	if engine.Consumed().IsZero() {
+		t.Error("Expected the consumption to be reported for the watchdog")
+	}
+}
+
+func TestEngine_NotifiesInactiveCoins(t *testing.T) {
+	notifier := &recordingNotifier{}
+	engine := NewEngine(&memRules{}, &memCandles{}, time.Hour, notifier)
+	engine.SetOwners(func(cryptoID string) []string {
+		return []string{"watchlist Alts", "the portfolio"}
+	})
+
+	engine.handleEvent(context.Background(), events.CoinInactive{
+		CryptoID:   "terra-luna",
+		LastUpdate: time.Date(2022, 5, 13, 0, 0, 0, 0, time.UTC),
+		At:         time.Now(),
+	})
+
+	if len(notifier.alerts) != 1 {
+		t.Fatalf("Expected one notified alert, got %d", len(notifier.alerts))
+	}
+	alert := notifier.alerts[0]
+	want := "terra-luna has not been updated since 2022-05-13 00:00 UTC; it may have been delisted, renamed or migrated. Held in watchlist Alts, the portfolio"
+	if alert.Kind != models.AlertCoinInactive || alert.Severity != models.SeverityWarning || alert.Message != want {
+		t.Errorf("Unexpected alert: %+v", alert)
+	}
+}
+
+func TestEngine_PublishesRaisedAlerts(t *testing.T) {
+	bus := events.NewBus()
+	raised, cancel := bus.Subscribe(events.KindAlertTriggered)
+	defer cancel()
+	engine := NewEngine(&memRules{}, &memCandles{}, time.Hour, nil)
+	engine.SetPublisher(bus)
+
+	engine.handleEvent(context.Background(), events.CoinInactive{CryptoID: "terra-luna", At: time.Now()})
+
+	select {
+	case event := <-raised:
+		if alert := event.(events.AlertTriggered).Alert; alert.CryptoID != "terra-luna" || alert.Kind != models.AlertCoinInactive {
+			t.Errorf("Unexpected published alert: %+v", alert)
+		}
+	default:
+		t.Fatal("Expected the alert to be published")
+	}
+}
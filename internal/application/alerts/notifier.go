@@ -0,0 +1,44 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// LogNotifier writes triggered alerts to a structured logger.
+// The zero value logs to the default logger.
+type LogNotifier struct {
+	Logger *slog.Logger
+}
+
+// Notify implements Notifier
+func (n LogNotifier) Notify(ctx context.Context, alert models.Alert) error {
+	logger := n.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.InfoContext(ctx, "alert triggered",
+		"rule", alert.RuleID,
+		"crypto", alert.CryptoID,
+		"kind", alert.Kind,
+		"price", alert.Price,
+		"message", alert.Message)
+	return nil
+}
+
+// Notifiers delivers every alert to all of its notifiers, collecting their errors
+type Notifiers []Notifier
+
+// Notify implements Notifier
+func (ns Notifiers) Notify(ctx context.Context, alert models.Alert) error {
+	var errs []error
+	for _, n := range ns {
+		if err := n.Notify(ctx, alert); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
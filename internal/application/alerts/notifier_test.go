@@ -0,0 +1,22 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestNotifiers(t *testing.T) {
+	ok := &recordingNotifier{}
+	failing := &recordingNotifier{err: errors.New("unreachable")}
+
+	err := Notifiers{failing, ok}.Notify(context.Background(), models.Alert{RuleID: "1"})
+	if !errors.Is(err, failing.err) {
+		t.Errorf("Expected the failing notifier's error, got %v", err)
+	}
+	if len(ok.alerts) != 1 || len(failing.alerts) != 1 {
+		t.Error("Expected every notifier to receive the alert despite the failure")
+	}
+}
@@ -0,0 +1,182 @@
+package analytics
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// IndicatorConfig holds the periods of every indicator
+type IndicatorConfig struct {
+	SMAPeriod       int     `json:"sma_period"`
+	EMAPeriod       int     `json:"ema_period"`
+	RSIPeriod       int     `json:"rsi_period"`
+	MACDFast        int     `json:"macd_fast"`
+	MACDSlow        int     `json:"macd_slow"`
+	MACDSignal      int     `json:"macd_signal"`
+	BollingerPeriod int     `json:"bollinger_period"`
+	BollingerK      float64 `json:"bollinger_k"`
+}
+
+// DefaultIndicatorConfig returns the periods most charting tools use by default
+func DefaultIndicatorConfig() IndicatorConfig {
+	return IndicatorConfig{
+		SMAPeriod:       20,
+		EMAPeriod:       20,
+		RSIPeriod:       14,
+		MACDFast:        12,
+		MACDSlow:        26,
+		MACDSignal:      9,
+		BollingerPeriod: 20,
+		BollingerK:      2,
+	}
+}
+
+// Validate checks that every period is usable
+func (c IndicatorConfig) Validate() error {
+	if c.SMAPeriod <= 0 || c.EMAPeriod <= 0 || c.RSIPeriod <= 0 || c.BollingerPeriod <= 0 {
+		return errors.New("indicator periods must be positive")
+	}
+	if c.MACDFast <= 0 || c.MACDSignal <= 0 || c.MACDFast >= c.MACDSlow {
+		return errors.New("MACD fast period must be positive and shorter than the slow period")
+	}
+	if c.BollingerK <= 0 {
+		return errors.New("bollinger k must be positive")
+	}
+	return nil
+}
+
+// IndicatorPoint holds the indicator values at a candle close. Indicators that
+// are still warming up are nil.
+type IndicatorPoint struct {
+	Time      time.Time       `json:"time"`
+	Close     float64         `json:"close"`
+	SMA       *float64        `json:"sma,omitempty"`
+	EMA       *float64        `json:"ema,omitempty"`
+	RSI       *float64        `json:"rsi,omitempty"`
+	MACD      *MACDValue      `json:"macd,omitempty"`
+	Bollinger *BollingerBands `json:"bollinger,omitempty"`
+}
+
+// Indicators updates every indicator of a single series incrementally
+type Indicators struct {
+	sma       *SMA
+	ema       *EMA
+	rsi       *RSI
+	macd      *MACD
+	bollinger *Bollinger
+	last      IndicatorPoint
+}
+
+// NewIndicators creates the indicators described by the config
+func NewIndicators(cfg IndicatorConfig) *Indicators {
+	return &Indicators{
+		sma:       NewSMA(cfg.SMAPeriod),
+		ema:       NewEMA(cfg.EMAPeriod),
+		rsi:       NewRSI(cfg.RSIPeriod),
+		macd:      NewMACD(cfg.MACDFast, cfg.MACDSlow, cfg.MACDSignal),
+		bollinger: NewBollinger(cfg.BollingerPeriod, cfg.BollingerK),
+	}
+}
+
+// Update feeds a closed candle to every indicator and returns their new values
+func (ind *Indicators) Update(c models.Candle) IndicatorPoint {
+	p := IndicatorPoint{Time: c.CloseTime, Close: c.Close}
+	if v, ok := ind.sma.Update(c.Close); ok {
+		p.SMA = &v
+	}
+	if v, ok := ind.ema.Update(c.Close); ok {
+		p.EMA = &v
+	}
+	if v, ok := ind.rsi.Update(c.Close); ok {
+		p.RSI = &v
+	}
+	if v, ok := ind.macd.Update(c.Close); ok {
+		p.MACD = &v
+	}
+	if v, ok := ind.bollinger.Update(c.Close); ok {
+		p.Bollinger = &v
+	}
+	ind.last = p
+	return p
+}
+
+// Last returns the values after the latest update
+func (ind *Indicators) Last() IndicatorPoint {
+	return ind.last
+}
+
+// ComputeIndicators runs the indicators over a candle series, one point per candle
+func ComputeIndicators(candles []models.Candle, cfg IndicatorConfig) ([]IndicatorPoint, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	ind := NewIndicators(cfg)
+	points := make([]IndicatorPoint, len(candles))
+	for i, c := range candles {
+		points[i] = ind.Update(c)
+	}
+	return points, nil
+}
+
+// CandleReader loads stored candles
+type CandleReader interface {
+	Candles(cryptoID string, interval time.Duration, limit int) ([]models.Candle, error)
+}
+
+// Tracker maintains the default indicators of every coin as candles close, so
+// the latest values are available without replaying the whole history
+type Tracker struct {
+	candles  CandleReader
+	interval time.Duration
+	cfg      IndicatorConfig
+
+	mu     sync.RWMutex
+	series map[string]*Indicators
+}
+
+// NewTracker creates a tracker for candles of the given interval. Coins seen for
+// the first time are warmed up from the stored history.
+func NewTracker(candles CandleReader, interval time.Duration, cfg IndicatorConfig) *Tracker {
+	return &Tracker{candles: candles, interval: interval, cfg: cfg, series: make(map[string]*Indicators)}
+}
+
+// Config returns the indicator periods maintained by the tracker
+func (t *Tracker) Config() IndicatorConfig {
+	return t.cfg
+}
+
+// OnCandleClose updates the coin's indicators. It is meant to be registered as
+// a candle builder close handler, which runs after the candle was stored.
+func (t *Tracker) OnCandleClose(c models.Candle) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if ind, ok := t.series[c.CryptoID]; ok {
+		ind.Update(c)
+		return
+	}
+
+	ind := NewIndicators(t.cfg)
+	history, err := t.candles.Candles(c.CryptoID, t.interval, 0)
+	if err != nil || len(history) == 0 || !history[len(history)-1].OpenTime.Equal(c.OpenTime) {
+		history = append(history, c)
+	}
+	for _, h := range history {
+		ind.Update(h)
+	}
+	t.series[c.CryptoID] = ind
+}
+
+// Latest returns the most recent indicator values of a coin
+func (t *Tracker) Latest(cryptoID string) (IndicatorPoint, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	ind, ok := t.series[cryptoID]
+	if !ok {
+		return IndicatorPoint{}, false
+	}
+	return ind.Last(), true
+}
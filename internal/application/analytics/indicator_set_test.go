@@ -0,0 +1,79 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func hourlyCandles(id string, closes ...float64) []models.Candle {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := make([]models.Candle, len(closes))
+	for i, c := range closes {
+		candles[i] = models.NewCandle(id, c, start.Add(time.Duration(i)*time.Hour), time.Hour)
+	}
+	return candles
+}
+
+func smallConfig() IndicatorConfig {
+	return IndicatorConfig{SMAPeriod: 2, EMAPeriod: 2, RSIPeriod: 2, MACDFast: 2, MACDSlow: 3, MACDSignal: 2, BollingerPeriod: 2, BollingerK: 2}
+}
+
+func TestComputeIndicators(t *testing.T) {
+	points, err := ComputeIndicators(hourlyCandles("bitcoin", 1, 2, 3, 4, 5), smallConfig())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(points) != 5 {
+		t.Fatalf("Expected 5 points, got %d", len(points))
+	}
+	if points[0].SMA != nil || points[0].RSI != nil {
+		t.Error("Expected indicators to be warming up on the first point")
+	}
+	last := points[4]
+	if last.SMA == nil || *last.SMA != 4.5 {
+		t.Errorf("Expected SMA of 4.5, got %v", last.SMA)
+	}
+	if last.RSI == nil || *last.RSI != 100 {
+		t.Errorf("Expected RSI of 100, got %v", last.RSI)
+	}
+	if last.MACD == nil || last.Bollinger == nil {
+		t.Error("Expected MACD and Bollinger bands to be ready")
+	}
+
+	if _, err := ComputeIndicators(nil, IndicatorConfig{}); err == nil {
+		t.Error("Expected invalid config error")
+	}
+}
+
+type stubReader []models.Candle
+
+func (s stubReader) Candles(id string, interval time.Duration, limit int) ([]models.Candle, error) {
+	return s, nil
+}
+
+func TestTracker(t *testing.T) {
+	candles := hourlyCandles("bitcoin", 1, 2, 3, 4, 5, 6)
+	stored := stubReader(candles[:5])
+	tracker := NewTracker(stored, time.Hour, smallConfig())
+
+	if _, ok := tracker.Latest("bitcoin"); ok {
+		t.Error("Expected no values before the first close")
+	}
+
+	// The first close warms up from the stored history
+	tracker.OnCandleClose(candles[4])
+	latest, ok := tracker.Latest("bitcoin")
+	if !ok || latest.SMA == nil || *latest.SMA != 4.5 {
+		t.Fatalf("Expected warmed up SMA of 4.5, got %+v", latest)
+	}
+
+	// Later closes update incrementally and match a full recomputation
+	tracker.OnCandleClose(candles[5])
+	latest, _ = tracker.Latest("bitcoin")
+	full, _ := ComputeIndicators(candles, smallConfig())
+	if *latest.SMA != *full[5].SMA || *latest.EMA != *full[5].EMA || latest.MACD.MACD != full[5].MACD.MACD {
+		t.Errorf("Expected streaming values %+v to match batch values %+v", latest, full[5])
+	}
+}
@@ -0,0 +1,215 @@
+package analytics
+
+import "math"
+
+// SMA is a streaming simple moving average. Each update is O(1).
+type SMA struct {
+	period int
+	window []float64
+	next   int
+	count  int
+	sum    float64
+}
+
+// NewSMA creates a simple moving average over period values. Periods below 1
+// are raised to 1.
+func NewSMA(period int) *SMA {
+	period = max(period, 1)
+	return &SMA{period: period, window: make([]float64, period)}
+}
+
+// Update adds a value and returns the average once the window is full
+func (s *SMA) Update(v float64) (float64, bool) {
+	if s.count == s.period {
+		s.sum -= s.window[s.next]
+	} else {
+		s.count++
+	}
+	s.window[s.next] = v
+	s.sum += v
+	s.next = (s.next + 1) % s.period
+	return s.Value()
+}
+
+// Value returns the current average
+func (s *SMA) Value() (float64, bool) {
+	if s.count < s.period {
+		return 0, false
+	}
+	return s.sum / float64(s.period), true
+}
+
+// EMA is a streaming exponential moving average seeded with the SMA of the first period values
+type EMA struct {
+	alpha float64
+	seed  *SMA
+	value float64
+	ready bool
+}
+
+// NewEMA creates an exponential moving average with smoothing 2/(period+1).
+// Periods below 1 are raised to 1.
+func NewEMA(period int) *EMA {
+	period = max(period, 1)
+	return &EMA{alpha: 2 / float64(period+1), seed: NewSMA(period)}
+}
+
+// Update adds a value and returns the average once it is seeded
+func (e *EMA) Update(v float64) (float64, bool) {
+	if !e.ready {
+		avg, ok := e.seed.Update(v)
+		if !ok {
+			return 0, false
+		}
+		e.value, e.ready = avg, true
+		return e.value, true
+	}
+	e.value += e.alpha * (v - e.value)
+	return e.value, true
+}
+
+// Value returns the current average
+func (e *EMA) Value() (float64, bool) {
+	return e.value, e.ready
+}
+
+// RSI is a streaming relative strength index using Wilder's smoothing
+type RSI struct {
+	period   int
+	prev     float64
+	count    int
+	avgGain  float64
+	avgLoss  float64
+	hasPrev  bool
+	hasValue bool
+}
+
+// NewRSI creates a relative strength index over period changes
+func NewRSI(period int) *RSI {
+	return &RSI{period: period}
+}
+
+// Update adds a close and returns the index once period changes were seen
+func (r *RSI) Update(v float64) (float64, bool) {
+	if r.period <= 0 {
+		return 0, false
+	}
+	if !r.hasPrev {
+		r.prev, r.hasPrev = v, true
+		return 0, false
+	}
+	change := v - r.prev
+	r.prev = v
+	up, down := max(change, 0), max(-change, 0)
+
+	if r.count < r.period {
+		// The first averages are plain means of the initial changes
+		r.avgGain += up / float64(r.period)
+		r.avgLoss += down / float64(r.period)
+		r.count++
+		if r.count < r.period {
+			return 0, false
+		}
+		r.hasValue = true
+		return r.Value()
+	}
+	r.avgGain = (r.avgGain*float64(r.period-1) + up) / float64(r.period)
+	r.avgLoss = (r.avgLoss*float64(r.period-1) + down) / float64(r.period)
+	return r.Value()
+}
+
+// Value returns the current index
+func (r *RSI) Value() (float64, bool) {
+	if !r.hasValue {
+		return 0, false
+	}
+	if r.avgLoss == 0 {
+		return 100, true
+	}
+	return 100 - 100/(1+r.avgGain/r.avgLoss), true
+}
+
+// MACDValue is the MACD line, its signal line and their difference
+type MACDValue struct {
+	MACD      float64 `json:"macd"`
+	Signal    float64 `json:"signal"`
+	Histogram float64 `json:"histogram"`
+}
+
+// MACD is a streaming moving average convergence/divergence indicator
+type MACD struct {
+	fast, slow, signal *EMA
+}
+
+// NewMACD creates a MACD from fast and slow EMAs and a signal EMA of their difference
+func NewMACD(fast, slow, signal int) *MACD {
+	return &MACD{fast: NewEMA(fast), slow: NewEMA(slow), signal: NewEMA(signal)}
+}
+
+// Update adds a close and returns the indicator once the signal line is seeded
+func (m *MACD) Update(v float64) (MACDValue, bool) {
+	fast, ok1 := m.fast.Update(v)
+	slow, ok2 := m.slow.Update(v)
+	if !ok1 || !ok2 {
+		return MACDValue{}, false
+	}
+	line := fast - slow
+	signal, ok := m.signal.Update(line)
+	if !ok {
+		return MACDValue{}, false
+	}
+	return MACDValue{MACD: line, Signal: signal, Histogram: line - signal}, true
+}
+
+// BollingerBands is a moving average enveloped by k standard deviations
+type BollingerBands struct {
+	Middle float64 `json:"middle"`
+	Upper  float64 `json:"upper"`
+	Lower  float64 `json:"lower"`
+}
+
+// Bollinger computes streaming Bollinger bands from running sums
+type Bollinger struct {
+	k     float64
+	mean  *SMA
+	sqSum *SMA
+}
+
+// NewBollinger creates bands over period values, k population standard
+// deviations wide. Periods below 1 are raised to 1.
+func NewBollinger(period int, k float64) *Bollinger {
+	return &Bollinger{k: k, mean: NewSMA(period), sqSum: NewSMA(period)}
+}
+
+// Update adds a close and returns the bands once the window is full
+func (b *Bollinger) Update(v float64) (BollingerBands, bool) {
+	mean, ok := b.mean.Update(v)
+	meanSq, _ := b.sqSum.Update(v * v)
+	if !ok {
+		return BollingerBands{}, false
+	}
+	// Rounding can make the variance of a flat window slightly negative
+	std := math.Sqrt(max(meanSq-mean*mean, 0))
+	return BollingerBands{Middle: mean, Upper: mean + b.k*std, Lower: mean - b.k*std}, true
+}
+
+// LastSMA returns the simple moving average of the last period values
+func LastSMA(values []float64, period int) (float64, bool) {
+	if period <= 0 || len(values) < period {
+		return 0, false
+	}
+	sma := NewSMA(period)
+	for _, v := range values[len(values)-period:] {
+		sma.Update(v)
+	}
+	return sma.Value()
+}
+
+// LastRSI returns the relative strength index at the end of the series
+func LastRSI(values []float64, period int) (float64, bool) {
+	rsi := NewRSI(period)
+	for _, v := range values {
+		rsi.Update(v)
+	}
+	return rsi.Value()
+}
@@ -0,0 +1,109 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSMA(t *testing.T) {
+	sma := NewSMA(3)
+	expected := []struct {
+		value float64
+		ok    bool
+	}{{0, false}, {0, false}, {2, true}, {3, true}, {4, true}}
+
+	for i, v := range []float64{1, 2, 3, 4, 5} {
+		got, ok := sma.Update(v)
+		if ok != expected[i].ok || got != expected[i].value {
+			t.Errorf("Update %d: expected (%f, %t), got (%f, %t)", i, expected[i].value, expected[i].ok, got, ok)
+		}
+	}
+}
+
+func TestEMA(t *testing.T) {
+	ema := NewEMA(3)
+	ema.Update(1)
+	ema.Update(2)
+	if v, ok := ema.Update(3); !ok || v != 2 {
+		t.Errorf("Expected EMA to be seeded with the SMA of 2, got %f", v)
+	}
+	// alpha = 0.5: 2 + 0.5*(6-2)
+	if v, _ := ema.Update(6); v != 4 {
+		t.Errorf("Expected EMA of 4, got %f", v)
+	}
+}
+
+func TestNonPositivePeriods(t *testing.T) {
+	for _, period := range []int{0, -3} {
+		// A period raised to 1 follows every value
+		if v, ok := NewSMA(period).Update(5); !ok || v != 5 {
+			t.Errorf("SMA(%d): expected (5, true), got (%f, %t)", period, v, ok)
+		}
+		if v, ok := NewEMA(period).Update(5); !ok || v != 5 {
+			t.Errorf("EMA(%d): expected (5, true), got (%f, %t)", period, v, ok)
+		}
+		if _, ok := NewBollinger(period, 2).Update(5); !ok {
+			t.Errorf("Bollinger(%d): expected bands after one value", period)
+		}
+	}
+}
+
+func TestRSI(t *testing.T) {
+	up := []float64{1, 2, 3, 4, 5}
+	if got, _ := LastRSI(up, 4); got != 100 {
+		t.Errorf("Expected RSI of 100 for a rising series, got %f", got)
+	}
+
+	flat := []float64{1, 2, 1, 2, 1}
+	if got, _ := LastRSI(flat, 4); math.Abs(got-50) > 1e-9 {
+		t.Errorf("Expected RSI of 50 for a balanced series, got %f", got)
+	}
+
+	if _, ok := LastRSI([]float64{1, 2}, 4); ok {
+		t.Error("Expected not enough data")
+	}
+}
+
+func TestMACD(t *testing.T) {
+	macd := NewMACD(2, 4, 2)
+	var last MACDValue
+	var ready int
+	for i := 1; i <= 10; i++ {
+		if v, ok := macd.Update(float64(i)); ok {
+			last = v
+			ready++
+		}
+	}
+	// Slow EMA is ready after 4 closes and the signal needs 2 more MACD values
+	if ready != 6 {
+		t.Errorf("Expected 6 MACD values, got %d", ready)
+	}
+	if last.MACD <= 0 || math.Abs(last.Histogram-(last.MACD-last.Signal)) > 1e-12 {
+		t.Errorf("Expected a positive MACD on a rising series, got %+v", last)
+	}
+}
+
+func TestBollinger(t *testing.T) {
+	b := NewBollinger(4, 2)
+	var bands BollingerBands
+	var ok bool
+	for _, v := range []float64{2, 4, 4, 6} {
+		bands, ok = b.Update(v)
+	}
+	if !ok {
+		t.Fatal("Expected bands once the window is full")
+	}
+	// mean 4, population std sqrt(2)
+	if bands.Middle != 4 || math.Abs(bands.Upper-(4+2*math.Sqrt2)) > 1e-9 || math.Abs(bands.Lower-(4-2*math.Sqrt2)) > 1e-9 {
+		t.Errorf("Unexpected bands: %+v", bands)
+	}
+}
+
+func TestLastSMA(t *testing.T) {
+	if v, ok := LastSMA([]float64{1, 2, 3, 4}, 2); !ok || v != 3.5 {
+		t.Errorf("Expected 3.5, got %f", v)
+	}
+	if _, ok := LastSMA([]float64{1}, 2); ok {
+		t.Error("Expected not enough data")
+	}
+}
@@ -0,0 +1,100 @@
+package analytics
+
+import (
+	"errors"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// SeasonalBucket aggregates the returns of every period sharing a label, e.g. all Octobers
+type SeasonalBucket struct {
+	Label string `json:"label"`
+	// Samples is the number of periods that contributed to the bucket
+	Samples int `json:"samples"`
+	// AverageReturn is the mean period return in percent
+	AverageReturn float64 `json:"average_return"`
+	// PositiveRate is the share of periods that closed higher than they opened
+	PositiveRate float64 `json:"positive_rate"`
+}
+
+// Seasonality is the return table of a coin by calendar month and day of week
+type Seasonality struct {
+	ByMonth   []SeasonalBucket `json:"by_month"`
+	ByWeekday []SeasonalBucket `json:"by_weekday"`
+}
+
+// period is the open and close price of a calendar day or month
+type period struct {
+	start time.Time
+	open  float64
+	close float64
+}
+
+// ComputeSeasonality groups candles into UTC days and months and averages the
+// open-to-close return of each period by month of year and day of week
+func ComputeSeasonality(candles []models.Candle) (Seasonality, error) {
+	if len(candles) == 0 {
+		return Seasonality{}, errors.New("no candles to analyse")
+	}
+
+	days := periods(candles, func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	})
+	months := periods(candles, func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	})
+
+	byMonth := make([]SeasonalBucket, 12)
+	for i := range byMonth {
+		byMonth[i].Label = time.Month(i + 1).String()
+	}
+	for _, p := range months {
+		addReturn(&byMonth[p.start.Month()-1], p)
+	}
+
+	byWeekday := make([]SeasonalBucket, 7)
+	for i := range byWeekday {
+		byWeekday[i].Label = time.Weekday(i).String()
+	}
+	for _, p := range days {
+		addReturn(&byWeekday[p.start.Weekday()], p)
+	}
+
+	for _, buckets := range [][]SeasonalBucket{byMonth, byWeekday} {
+		for i := range buckets {
+			if n := float64(buckets[i].Samples); n > 0 {
+				buckets[i].AverageReturn /= n
+				buckets[i].PositiveRate /= n
+			}
+		}
+	}
+	return Seasonality{ByMonth: byMonth, ByWeekday: byWeekday}, nil
+}
+
+// periods merges consecutive candles sharing the same truncated open time
+func periods(candles []models.Candle, truncate func(time.Time) time.Time) []period {
+	var out []period
+	for _, c := range candles {
+		start := truncate(c.OpenTime.UTC())
+		if n := len(out); n > 0 && out[n-1].start.Equal(start) {
+			out[n-1].close = c.Close
+			continue
+		}
+		out = append(out, period{start: start, open: c.Open, close: c.Close})
+	}
+	return out
+}
+
+// addReturn accumulates the period return into the bucket; averages are taken afterwards
+func addReturn(b *SeasonalBucket, p period) {
+	if p.open <= 0 {
+		return
+	}
+	ret := (p.close/p.open - 1) * 100
+	b.Samples++
+	b.AverageReturn += ret
+	if ret > 0 {
+		b.PositiveRate++
+	}
+}
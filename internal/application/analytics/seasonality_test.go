@@ -0,0 +1,64 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func dailyCandle(day time.Time, open, close float64) models.Candle {
+	return models.Candle{OpenTime: day, CloseTime: day.Add(24 * time.Hour), Open: open, Close: close}
+}
+
+func TestComputeSeasonality(t *testing.T) {
+	// 2024-10-07 is a Monday
+	monday := time.Date(2024, 10, 7, 0, 0, 0, 0, time.UTC)
+	candles := []models.Candle{
+		dailyCandle(monday, 100, 110),
+		{OpenTime: monday.Add(24 * time.Hour), Open: 110, Close: 105},
+		{OpenTime: monday.Add(30 * time.Hour), Open: 105, Close: 99},
+		dailyCandle(monday.Add(7*24*time.Hour), 99, 120),
+		dailyCandle(time.Date(2024, 11, 4, 0, 0, 0, 0, time.UTC), 120, 108),
+	}
+
+	s, err := ComputeSeasonality(candles)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		bucket  SeasonalBucket
+		label   string
+		samples int
+		avg     float64
+		pos     float64
+	}{
+		{"October", s.ByMonth[time.October-1], "October", 1, 20, 1},
+		{"November", s.ByMonth[time.November-1], "November", 1, -10, 0},
+		{"January", s.ByMonth[time.January-1], "January", 0, 0, 0},
+		{"Monday", s.ByWeekday[time.Monday], "Monday", 3, (10 + 21.212121 - 10) / 3, 2.0 / 3},
+		{"Tuesday", s.ByWeekday[time.Tuesday], "Tuesday", 1, -10, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.bucket.Label != tt.label {
+				t.Errorf("Expected label %s, got %s", tt.label, tt.bucket.Label)
+			}
+			if tt.bucket.Samples != tt.samples {
+				t.Errorf("Expected %d samples, got %d", tt.samples, tt.bucket.Samples)
+			}
+			if diff := tt.bucket.AverageReturn - tt.avg; diff > 1e-4 || diff < -1e-4 {
+				t.Errorf("Expected average return %f, got %f", tt.avg, tt.bucket.AverageReturn)
+			}
+			if diff := tt.bucket.PositiveRate - tt.pos; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Expected positive rate %f, got %f", tt.pos, tt.bucket.PositiveRate)
+			}
+		})
+	}
+
+	if _, err := ComputeSeasonality(nil); err == nil {
+		t.Error("Expected error for empty series")
+	}
+}
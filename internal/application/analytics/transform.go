@@ -0,0 +1,69 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Transform converts a price series into a comparable one
+type Transform string
+
+// Supported transforms
+const (
+	// TransformRaw leaves prices unchanged
+	TransformRaw Transform = "raw"
+	// TransformRebased scales the series so it starts at 100
+	TransformRebased Transform = "rebased"
+	// TransformLogReturns is the log of each price over the previous one
+	TransformLogReturns Transform = "log-returns"
+	// TransformCumulativeReturns is the change since the first price, as a fraction
+	TransformCumulativeReturns Transform = "cumulative-returns"
+)
+
+// ParseTransform validates a transform name; an empty name is TransformRaw
+func ParseTransform(name string) (Transform, error) {
+	switch t := Transform(name); t {
+	case "":
+		return TransformRaw, nil
+	case TransformRaw, TransformRebased, TransformLogReturns, TransformCumulativeReturns:
+		return t, nil
+	}
+	return "", fmt.Errorf("unknown transform: %q", name)
+}
+
+// Apply returns the transformed series, aligned with the input. The first
+// log return has no previous price and is zero. Every transform but raw needs
+// positive prices.
+func (t Transform) Apply(prices []float64) ([]float64, error) {
+	out := make([]float64, len(prices))
+	if t == TransformRaw || t == "" {
+		copy(out, prices)
+		return out, nil
+	}
+	for _, p := range prices {
+		if p <= 0 {
+			return nil, errors.New("transforms need positive prices")
+		}
+	}
+	if len(prices) == 0 {
+		return out, nil
+	}
+
+	base := prices[0]
+	for i, p := range prices {
+		switch t {
+		case TransformRebased:
+			out[i] = 100 * p / base
+		case TransformCumulativeReturns:
+			out[i] = p/base - 1
+		case TransformLogReturns:
+			if i > 0 {
+				out[i] = math.Log(p / prices[i-1])
+			}
+		default:
+			return nil, fmt.Errorf("unknown transform: %q", t)
+		}
+	}
+	return out, nil
+}
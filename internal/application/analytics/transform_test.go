@@ -0,0 +1,58 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTransform_Apply(t *testing.T) {
+	prices := []float64{50, 100, 75}
+	tests := []struct {
+		transform Transform
+		want      []float64
+	}{
+		{TransformRaw, []float64{50, 100, 75}},
+		{TransformRebased, []float64{100, 200, 150}},
+		{TransformCumulativeReturns, []float64{0, 1, 0.5}},
+		{TransformLogReturns, []float64{0, math.Log(2), math.Log(0.75)}},
+	}
+	for _, tt := range tests {
+		got, err := tt.transform.Apply(prices)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.transform, err)
+		}
+		for i := range tt.want {
+			if math.Abs(got[i]-tt.want[i]) > 1e-12 {
+				t.Errorf("%s: expected %v, got %v", tt.transform, tt.want, got)
+				break
+			}
+		}
+	}
+
+	// Log returns sum to the log of the total change
+	returns, _ := TransformLogReturns.Apply(prices)
+	if sum := returns[1] + returns[2]; math.Abs(sum-math.Log(1.5)) > 1e-12 {
+		t.Errorf("Expected log returns to add up, got %f", sum)
+	}
+}
+
+func TestTransform_ApplyRejectsNonPositivePrices(t *testing.T) {
+	if _, err := TransformRebased.Apply([]float64{0, 1}); err == nil {
+		t.Error("Expected an error rebasing from zero")
+	}
+	if got, err := TransformRaw.Apply([]float64{0, -1}); err != nil || got[1] != -1 {
+		t.Errorf("Expected raw prices to pass through, got %v (%v)", got, err)
+	}
+}
+
+func TestParseTransform(t *testing.T) {
+	if tr, err := ParseTransform(""); err != nil || tr != TransformRaw {
+		t.Errorf("Expected raw by default, got %q (%v)", tr, err)
+	}
+	if tr, err := ParseTransform("log-returns"); err != nil || tr != TransformLogReturns {
+		t.Errorf("Expected log-returns, got %q (%v)", tr, err)
+	}
+	if _, err := ParseTransform("rebased=50"); err == nil {
+		t.Error("Expected an unknown transform to be rejected")
+	}
+}
@@ -0,0 +1,150 @@
+// Package analytics computes statistics over stored candle series
+package analytics
+
+import (
+	"errors"
+	"sort"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// valueAreaShare is the share of total volume contained in the value area
+const valueAreaShare = 0.7
+
+// PriceBin is a price range and the volume traded inside it
+type PriceBin struct {
+	Low    float64 `json:"low"`
+	High   float64 `json:"high"`
+	Volume float64 `json:"volume"`
+}
+
+// Mid returns the middle price of the bin
+func (b PriceBin) Mid() float64 {
+	return (b.Low + b.High) / 2
+}
+
+// VolumeProfile is the distribution of traded volume across price levels
+type VolumeProfile struct {
+	Bins []PriceBin `json:"bins"`
+	// POC (point of control) is the bin with the most volume
+	POC PriceBin `json:"poc"`
+	// ValueAreaLow and ValueAreaHigh bound the range holding 70% of the volume around the POC
+	ValueAreaLow  float64 `json:"value_area_low"`
+	ValueAreaHigh float64 `json:"value_area_high"`
+	// HighVolumeNodes are local volume peaks above the average, typical support/resistance zones
+	HighVolumeNodes []PriceBin `json:"high_volume_nodes"`
+	// TimeWeighted is true when candles carried no volume and each candle counted as one unit
+	TimeWeighted bool `json:"time_weighted"`
+}
+
+// ComputeVolumeProfile distributes every candle's volume evenly over the bins
+// its low-high range spans. Candles without volume are weighted by time instead.
+func ComputeVolumeProfile(candles []models.Candle, bins int) (VolumeProfile, error) {
+	if len(candles) == 0 {
+		return VolumeProfile{}, errors.New("no candles to profile")
+	}
+	if bins <= 0 {
+		return VolumeProfile{}, errors.New("bin count must be positive")
+	}
+
+	low, high := candles[0].Low, candles[0].High
+	timeWeighted := true
+	for _, c := range candles {
+		low = min(low, c.Low)
+		high = max(high, c.High)
+		if c.Volume > 0 {
+			timeWeighted = false
+		}
+	}
+	if high == low {
+		high = low + 1e-9
+	}
+
+	width := (high - low) / float64(bins)
+	profile := make([]PriceBin, bins)
+	for i := range profile {
+		profile[i] = PriceBin{Low: low + float64(i)*width, High: low + float64(i+1)*width}
+	}
+
+	for _, c := range candles {
+		volume := c.Volume
+		if timeWeighted {
+			volume = 1
+		}
+		first := binIndex(c.Low, low, width, bins)
+		last := binIndex(c.High, low, width, bins)
+		share := volume / float64(last-first+1)
+		for i := first; i <= last; i++ {
+			profile[i].Volume += share
+		}
+	}
+
+	poc := 0
+	total := 0.0
+	for i, b := range profile {
+		total += b.Volume
+		if b.Volume > profile[poc].Volume {
+			poc = i
+		}
+	}
+
+	vaLow, vaHigh := valueArea(profile, poc, total)
+	return VolumeProfile{
+		Bins:            profile,
+		POC:             profile[poc],
+		ValueAreaLow:    profile[vaLow].Low,
+		ValueAreaHigh:   profile[vaHigh].High,
+		HighVolumeNodes: highVolumeNodes(profile, total),
+		TimeWeighted:    timeWeighted,
+	}, nil
+}
+
+func binIndex(price, low, width float64, bins int) int {
+	i := int((price - low) / width)
+	return max(0, min(i, bins-1))
+}
+
+// valueArea expands from the POC towards the side with more volume until
+// valueAreaShare of the total volume is covered
+func valueArea(profile []PriceBin, poc int, total float64) (int, int) {
+	lo, hi := poc, poc
+	covered := profile[poc].Volume
+	for covered < total*valueAreaShare && (lo > 0 || hi < len(profile)-1) {
+		below, above := -1.0, -1.0
+		if lo > 0 {
+			below = profile[lo-1].Volume
+		}
+		if hi < len(profile)-1 {
+			above = profile[hi+1].Volume
+		}
+		if above >= below {
+			hi++
+			covered += above
+		} else {
+			lo--
+			covered += below
+		}
+	}
+	return lo, hi
+}
+
+// highVolumeNodes returns local maxima whose volume is above the bin average,
+// sorted by volume descending
+func highVolumeNodes(profile []PriceBin, total float64) []PriceBin {
+	average := total / float64(len(profile))
+	var nodes []PriceBin
+	for i, b := range profile {
+		if b.Volume <= average {
+			continue
+		}
+		if i > 0 && profile[i-1].Volume > b.Volume {
+			continue
+		}
+		if i < len(profile)-1 && profile[i+1].Volume > b.Volume {
+			continue
+		}
+		nodes = append(nodes, b)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Volume > nodes[j].Volume })
+	return nodes
+}
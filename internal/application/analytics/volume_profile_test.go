@@ -0,0 +1,69 @@
+package analytics
+
+import (
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func candle(low, high, volume float64) models.Candle {
+	return models.Candle{Open: low, High: high, Low: low, Close: high, Volume: volume}
+}
+
+func TestComputeVolumeProfile(t *testing.T) {
+	candles := []models.Candle{
+		candle(100, 101, 10),
+		candle(100, 101, 10),
+		candle(104, 105, 50),
+		candle(108, 110, 5),
+		candle(100, 110, 10),
+	}
+
+	profile, err := ComputeVolumeProfile(candles, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(profile.Bins) != 10 {
+		t.Fatalf("Expected 10 bins, got %d", len(profile.Bins))
+	}
+	if profile.POC.Low != 104 {
+		t.Errorf("Expected POC at 104-105, got %f-%f", profile.POC.Low, profile.POC.High)
+	}
+
+	total := 0.0
+	for _, b := range profile.Bins {
+		total += b.Volume
+	}
+	if total < 84.999 || total > 85.001 {
+		t.Errorf("Expected total volume of 85 to be preserved, got %f", total)
+	}
+
+	if profile.ValueAreaLow > profile.POC.Low || profile.ValueAreaHigh < profile.POC.High {
+		t.Errorf("Expected value area to contain the POC, got %f-%f", profile.ValueAreaLow, profile.ValueAreaHigh)
+	}
+	if len(profile.HighVolumeNodes) < 2 || profile.HighVolumeNodes[0].Low != 104 {
+		t.Errorf("Expected POC and the 100 cluster as high volume nodes, got %+v", profile.HighVolumeNodes)
+	}
+	if profile.TimeWeighted {
+		t.Error("Expected a volume weighted profile")
+	}
+}
+
+func TestComputeVolumeProfile_TimeWeightedFallback(t *testing.T) {
+	profile, err := ComputeVolumeProfile([]models.Candle{candle(1, 2, 0), candle(1, 2, 0)}, 4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !profile.TimeWeighted {
+		t.Error("Expected time weighted profile when candles have no volume")
+	}
+}
+
+func TestComputeVolumeProfile_Errors(t *testing.T) {
+	if _, err := ComputeVolumeProfile(nil, 10); err == nil {
+		t.Error("Expected error for empty series, got nil")
+	}
+	if _, err := ComputeVolumeProfile([]models.Candle{candle(1, 2, 1)}, 0); err == nil {
+		t.Error("Expected error for zero bins, got nil")
+	}
+}
@@ -0,0 +1,63 @@
+// Package backtest replays allocation strategies over stored candle history
+package backtest
+
+import (
+	"math"
+	"time"
+)
+
+// EquityPoint is the value of a strategy at a candle close
+type EquityPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// Metrics summarizes the performance of a strategy
+type Metrics struct {
+	FinalValue float64 `json:"final_value"`
+	// TotalReturn, CAGR, Volatility and MaxDrawdown are percentages
+	TotalReturn float64 `json:"total_return"`
+	CAGR        float64 `json:"cagr"`
+	Volatility  float64 `json:"volatility"`
+	MaxDrawdown float64 `json:"max_drawdown"`
+	// Turnover is the traded value, counted one way, divided by the average portfolio value
+	Turnover   float64 `json:"turnover"`
+	Rebalances int     `json:"rebalances"`
+}
+
+// computeMetrics derives the performance metrics of an equity curve.
+// Volatility is annualized from the period returns.
+func computeMetrics(curve []EquityPoint, periodsPerYear, traded float64) Metrics {
+	first, last := curve[0], curve[len(curve)-1]
+	m := Metrics{
+		FinalValue:  last.Value,
+		TotalReturn: (last.Value/first.Value - 1) * 100,
+	}
+
+	if years := last.Time.Sub(first.Time).Hours() / (365 * 24); years > 0 {
+		m.CAGR = (math.Pow(last.Value/first.Value, 1/years) - 1) * 100
+	}
+
+	var sum, sumSq, total float64
+	peak := first.Value
+	for i, p := range curve {
+		total += p.Value
+		peak = max(peak, p.Value)
+		m.MaxDrawdown = max(m.MaxDrawdown, (1-p.Value/peak)*100)
+		if i == 0 {
+			continue
+		}
+		r := p.Value/curve[i-1].Value - 1
+		sum += r
+		sumSq += r * r
+	}
+	if n := float64(len(curve) - 1); n > 1 {
+		mean := sum / n
+		variance := (sumSq - n*mean*mean) / (n - 1)
+		m.Volatility = math.Sqrt(max(variance, 0)*periodsPerYear) * 100
+	}
+	if avg := total / float64(len(curve)); avg > 0 {
+		m.Turnover = traded / 2 / avg
+	}
+	return m
+}
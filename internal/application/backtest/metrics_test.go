@@ -0,0 +1,35 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestComputeMetrics(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	curve := []EquityPoint{
+		{Time: start, Value: 100},
+		{Time: start.AddDate(1, 0, 0), Value: 150},
+		{Time: start.AddDate(2, 0, 1), Value: 121},
+	}
+
+	m := computeMetrics(curve, 1, 74)
+	if math.Abs(m.TotalReturn-21) > 1e-9 {
+		t.Errorf("Expected total return of 21%%, got %f", m.TotalReturn)
+	}
+	if math.Abs(m.CAGR-10) > 0.05 {
+		t.Errorf("Expected CAGR close to 10%%, got %f", m.CAGR)
+	}
+	if math.Abs(m.MaxDrawdown-(1-121.0/150)*100) > 1e-9 {
+		t.Errorf("Expected drawdown of 19.33%%, got %f", m.MaxDrawdown)
+	}
+	// Returns of +50% and -19.33% have a sample standard deviation of about 49%
+	if math.Abs(m.Volatility-49.03) > 0.05 {
+		t.Errorf("Expected volatility of about 49%%, got %f", m.Volatility)
+	}
+	// 74 traded one way over an average value of 123.67
+	if math.Abs(m.Turnover-37/(371.0/3)) > 1e-9 {
+		t.Errorf("Unexpected turnover: %f", m.Turnover)
+	}
+}
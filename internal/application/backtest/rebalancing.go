@@ -0,0 +1,238 @@
+package backtest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Frequency is how often the rebalancing strategy restores the target weights
+type Frequency string
+
+// Supported rebalancing frequencies
+const (
+	Daily     Frequency = "daily"
+	Weekly    Frequency = "weekly"
+	Monthly   Frequency = "monthly"
+	Quarterly Frequency = "quarterly"
+)
+
+// period returns a key that changes whenever a new rebalancing period starts
+func (f Frequency) period(t time.Time) (int, error) {
+	t = t.UTC()
+	switch f {
+	case Daily:
+		return t.Year()*1000 + t.YearDay(), nil
+	case Weekly:
+		year, week := t.ISOWeek()
+		return year*100 + week, nil
+	case Monthly:
+		return t.Year()*100 + int(t.Month()), nil
+	case Quarterly:
+		return t.Year()*10 + (int(t.Month())-1)/3, nil
+	}
+	return 0, fmt.Errorf("unknown rebalancing frequency: %q", f)
+}
+
+// RebalancingInput configures a rebalancing backtest
+type RebalancingInput struct {
+	// Allocation maps coin IDs to target weights; weights are normalized
+	Allocation   map[string]float64 `json:"allocation"`
+	InitialValue float64            `json:"initial_value"`
+	Frequency    Frequency          `json:"frequency"`
+}
+
+// StrategyResult is the outcome of one strategy
+type StrategyResult struct {
+	Strategy string        `json:"strategy"`
+	Metrics  Metrics       `json:"metrics"`
+	Equity   []EquityPoint `json:"equity"`
+}
+
+// RebalancingResult compares buy-and-hold with periodic rebalancing over the same history
+type RebalancingResult struct {
+	From        time.Time      `json:"from"`
+	To          time.Time      `json:"to"`
+	BuyAndHold  StrategyResult `json:"buy_and_hold"`
+	Rebalancing StrategyResult `json:"rebalancing"`
+}
+
+// RunRebalancing replays both strategies over candle series keyed by coin ID.
+// Only the candles present in every series are used, so all coins trade at the same closes.
+func RunRebalancing(in RebalancingInput, series map[string][]models.Candle, periodsPerYear float64) (RebalancingResult, error) {
+	if in.InitialValue <= 0 {
+		return RebalancingResult{}, errors.New("initial value must be positive")
+	}
+	if in.Frequency == "" {
+		in.Frequency = Monthly
+	}
+	if _, err := in.Frequency.period(time.Time{}); err != nil {
+		return RebalancingResult{}, err
+	}
+	weights, err := normalize(in.Allocation)
+	if err != nil {
+		return RebalancingResult{}, err
+	}
+
+	times, closes := align(series, weights)
+	if len(times) < 2 {
+		return RebalancingResult{}, errors.New("not enough overlapping history to backtest")
+	}
+
+	hold := simulate(weights, times, closes, in.InitialValue, "")
+	rebalance := simulate(weights, times, closes, in.InitialValue, in.Frequency)
+	hold.Metrics = computeMetrics(hold.Equity, periodsPerYear, hold.traded)
+	rebalance.Metrics = computeMetrics(rebalance.Equity, periodsPerYear, rebalance.traded)
+	hold.Metrics.Rebalances = hold.rebalances
+	rebalance.Metrics.Rebalances = rebalance.rebalances
+
+	return RebalancingResult{
+		From:        times[0],
+		To:          times[len(times)-1],
+		BuyAndHold:  StrategyResult{Strategy: "buy_and_hold", Metrics: hold.Metrics, Equity: hold.Equity},
+		Rebalancing: StrategyResult{Strategy: "rebalance_" + string(in.Frequency), Metrics: rebalance.Metrics, Equity: rebalance.Equity},
+	}, nil
+}
+
+// Allocation is a coin's share of a portfolio and how far it drifted from its target
+type Allocation struct {
+	// Weight is the coin's share of the portfolio value, from 0 to 1
+	Weight float64 `json:"weight"`
+	// Target is the normalized target weight; zero for coins without one
+	Target float64 `json:"target"`
+	// Drift is Weight minus Target, in percentage points
+	Drift float64 `json:"drift"`
+	// Drifted is set when the drift is larger than the band in either direction
+	Drifted bool `json:"drifted"`
+}
+
+// Allocations weighs coins by value and compares them with the target
+// allocation, normalized like a rebalancing allocation. Coins held without a
+// target are never flagged; targeted coins that are not held weigh zero.
+func Allocations(values, targets map[string]float64, bandPct float64) (map[string]Allocation, error) {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	if total <= 0 {
+		return nil, errors.New("portfolio has no value")
+	}
+	weights := map[string]float64{}
+	if len(targets) > 0 {
+		var err error
+		if weights, err = normalize(targets); err != nil {
+			return nil, err
+		}
+	}
+
+	allocations := make(map[string]Allocation, len(values)+len(weights))
+	for id, v := range values {
+		allocations[id] = Allocation{Weight: v / total}
+	}
+	for id, target := range weights {
+		a := allocations[id]
+		a.Target = target
+		a.Drift = (a.Weight - target) * 100
+		a.Drifted = math.Abs(a.Drift) > bandPct
+		allocations[id] = a
+	}
+	return allocations, nil
+}
+
+// run is a strategy being simulated
+type run struct {
+	StrategyResult
+	traded     float64
+	rebalances int
+}
+
+// simulate buys the target weights at the first close and, when a frequency is
+// given, trades back to them at the first close of every new period
+func simulate(weights map[string]float64, times []time.Time, closes map[string][]float64, initial float64, freq Frequency) run {
+	units := make(map[string]float64, len(weights))
+	for id, w := range weights {
+		units[id] = initial * w / closes[id][0]
+	}
+
+	r := run{StrategyResult: StrategyResult{Equity: make([]EquityPoint, len(times))}}
+	r.Equity[0] = EquityPoint{Time: times[0], Value: initial}
+	lastPeriod, _ := freq.period(times[0])
+
+	for i := 1; i < len(times); i++ {
+		value := 0.0
+		for id, u := range units {
+			value += u * closes[id][i]
+		}
+
+		if freq != "" {
+			if p, _ := freq.period(times[i]); p != lastPeriod {
+				lastPeriod = p
+				for id, w := range weights {
+					target := value * w / closes[id][i]
+					r.traded += math.Abs(target-units[id]) * closes[id][i]
+					units[id] = target
+				}
+				r.rebalances++
+			}
+		}
+		r.Equity[i] = EquityPoint{Time: times[i], Value: value}
+	}
+	return r
+}
+
+// align returns the close times shared by every allocated coin and the closes at those times
+func align(series map[string][]models.Candle, weights map[string]float64) ([]time.Time, map[string][]float64) {
+	counts := make(map[time.Time]int)
+	for id := range weights {
+		for _, c := range series[id] {
+			if c.Close > 0 {
+				counts[c.CloseTime]++
+			}
+		}
+	}
+	var times []time.Time
+	for t, n := range counts {
+		if n == len(weights) {
+			times = append(times, t)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	index := make(map[time.Time]int, len(times))
+	for i, t := range times {
+		index[t] = i
+	}
+	closes := make(map[string][]float64, len(weights))
+	for id := range weights {
+		closes[id] = make([]float64, len(times))
+		for _, c := range series[id] {
+			if i, ok := index[c.CloseTime]; ok {
+				closes[id][i] = c.Close
+			}
+		}
+	}
+	return times, closes
+}
+
+// normalize scales the allocation so the weights sum to one
+func normalize(allocation map[string]float64) (map[string]float64, error) {
+	if len(allocation) == 0 {
+		return nil, errors.New("allocation cannot be empty")
+	}
+	total := 0.0
+	for id, w := range allocation {
+		if w <= 0 {
+			return nil, fmt.Errorf("weight of %s must be positive", id)
+		}
+		total += w
+	}
+	weights := make(map[string]float64, len(allocation))
+	for id, w := range allocation {
+		weights[id] = w / total
+	}
+	return weights, nil
+}
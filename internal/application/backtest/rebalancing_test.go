@@ -0,0 +1,113 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func daily(id string, start time.Time, closes ...float64) []models.Candle {
+	candles := make([]models.Candle, len(closes))
+	for i, c := range closes {
+		candles[i] = models.NewCandle(id, c, start.Add(time.Duration(i)*24*time.Hour), 24*time.Hour)
+	}
+	return candles
+}
+
+func TestRunRebalancing(t *testing.T) {
+	start := time.Date(2024, 1, 30, 0, 0, 0, 0, time.UTC)
+	// A doubles then halves around the month boundary, B stays flat
+	series := map[string][]models.Candle{
+		"a": daily("a", start, 100, 200, 200, 100),
+		"b": daily("b", start, 10, 10, 10, 10),
+	}
+
+	result, err := RunRebalancing(RebalancingInput{
+		Allocation:   map[string]float64{"a": 1, "b": 1},
+		InitialValue: 1000,
+		Frequency:    Monthly,
+	}, series, 365)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Buy and hold ends where it started
+	hold := result.BuyAndHold.Metrics
+	if math.Abs(hold.FinalValue-1000) > 1e-9 || hold.Turnover != 0 || hold.Rebalances != 0 {
+		t.Errorf("Unexpected buy-and-hold metrics: %+v", hold)
+	}
+	if math.Abs(hold.MaxDrawdown-100.0/3) > 1e-9 {
+		t.Errorf("Expected a 33.3%% drawdown from 1500 to 1000, got %f", hold.MaxDrawdown)
+	}
+
+	// Rebalancing at 1500 on Feb 1st sells the winner before it halves
+	reb := result.Rebalancing.Metrics
+	if reb.Rebalances != 1 {
+		t.Errorf("Expected 1 rebalance, got %d", reb.Rebalances)
+	}
+	if math.Abs(reb.FinalValue-1125) > 1e-9 {
+		t.Errorf("Expected rebalanced final value of 1125, got %f", reb.FinalValue)
+	}
+	if reb.Turnover <= 0 || reb.CAGR <= hold.CAGR {
+		t.Errorf("Expected positive turnover and a better CAGR, got %+v", reb)
+	}
+	if len(result.Rebalancing.Equity) != 4 || !result.From.Equal(start.Add(24*time.Hour)) {
+		t.Errorf("Unexpected equity curve from %v: %+v", result.From, result.Rebalancing.Equity)
+	}
+}
+
+func TestRunRebalancing_Validation(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	series := map[string][]models.Candle{"a": daily("a", start, 1, 2, 3)}
+
+	tests := []struct {
+		name string
+		in   RebalancingInput
+	}{
+		{"no value", RebalancingInput{Allocation: map[string]float64{"a": 1}}},
+		{"empty allocation", RebalancingInput{InitialValue: 1}},
+		{"negative weight", RebalancingInput{InitialValue: 1, Allocation: map[string]float64{"a": -1}}},
+		{"unknown frequency", RebalancingInput{InitialValue: 1, Allocation: map[string]float64{"a": 1}, Frequency: "hourly"}},
+		{"no overlap", RebalancingInput{InitialValue: 1, Allocation: map[string]float64{"a": 1, "b": 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := RunRebalancing(tt.in, series, 365); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestAllocations(t *testing.T) {
+	got, err := Allocations(
+		map[string]float64{"a": 700, "b": 250, "c": 50},
+		map[string]float64{"a": 3, "b": 1, "d": 1}, // 60%, 20% and 20%
+		5,
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	if a := got["a"]; !near(a.Weight, 0.7) || !near(a.Target, 0.6) || !near(a.Drift, 10) || !a.Drifted {
+		t.Errorf("Expected a to drift 10 points over its target, got %+v", a)
+	}
+	if b := got["b"]; !near(b.Drift, 5) || b.Drifted {
+		t.Errorf("Expected b to stay within the band, got %+v", b)
+	}
+	if c := got["c"]; !near(c.Weight, 0.05) || c.Target != 0 || c.Drifted {
+		t.Errorf("Expected c without a target to be weighed only, got %+v", c)
+	}
+	if d := got["d"]; d.Weight != 0 || !near(d.Drift, -20) || !d.Drifted {
+		t.Errorf("Expected d to be missing its whole target, got %+v", d)
+	}
+
+	if _, err := Allocations(map[string]float64{"a": 0}, nil, 5); err == nil {
+		t.Error("Expected an error for a portfolio without value")
+	}
+	if _, err := Allocations(map[string]float64{"a": 1}, map[string]float64{"a": -1}, 5); err == nil {
+		t.Error("Expected an error for a negative target")
+	}
+}
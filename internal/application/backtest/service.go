@@ -0,0 +1,38 @@
+package backtest
+
+import (
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// CandleReader loads stored candles
+type CandleReader interface {
+	Candles(cryptoID string, interval time.Duration, limit int) ([]models.Candle, error)
+}
+
+// Service runs backtests against the stored candle history
+type Service struct {
+	candles  CandleReader
+	interval time.Duration
+}
+
+// NewService creates a backtest service working on candles of the given interval
+func NewService(candles CandleReader, interval time.Duration) *Service {
+	return &Service{candles: candles, interval: interval}
+}
+
+// Rebalancing compares buy-and-hold with periodic rebalancing of the allocation
+// over every stored candle
+func (s *Service) Rebalancing(in RebalancingInput) (RebalancingResult, error) {
+	series := make(map[string][]models.Candle, len(in.Allocation))
+	for id := range in.Allocation {
+		candles, err := s.candles.Candles(id, s.interval, 0)
+		if err != nil {
+			return RebalancingResult{}, err
+		}
+		series[id] = candles
+	}
+	periodsPerYear := float64(365*24*time.Hour) / float64(s.interval)
+	return RunRebalancing(in, series, periodsPerYear)
+}
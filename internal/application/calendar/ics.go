@@ -0,0 +1,89 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+const (
+	icsDateTime = "20060102T150405Z"
+	icsDate     = "20060102"
+	// icsLineLimit is the maximum line length in octets before folding (RFC 5545 section 3.1)
+	icsLineLimit = 75
+)
+
+// WriteICS writes the events as an iCalendar (RFC 5545) feed that calendar
+// applications can subscribe to
+func WriteICS(w io.Writer, name string, events []models.Event) error {
+	bw := bufio.NewWriter(w)
+	line := func(format string, args ...any) {
+		writeFolded(bw, fmt.Sprintf(format, args...))
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//crypto-dashboard//calendar//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:%s", escapeText(name))
+
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:%s@crypto-dashboard", e.ID)
+		line("DTSTAMP:%s", e.CreatedAt.UTC().Format(icsDateTime))
+		if e.AllDay {
+			line("DTSTART;VALUE=DATE:%s", e.Start.Format(icsDate))
+			line("DTEND;VALUE=DATE:%s", e.Finish().Format(icsDate))
+		} else {
+			line("DTSTART:%s", e.Start.UTC().Format(icsDateTime))
+			if e.End != nil {
+				line("DTEND:%s", e.End.UTC().Format(icsDateTime))
+			}
+		}
+		line("SUMMARY:%s", escapeText(e.Title))
+		if e.Description != "" {
+			line("DESCRIPTION:%s", escapeText(e.Description))
+		}
+		line("CATEGORIES:%s", strings.ToUpper(string(e.Kind)))
+		if e.RemindMinutes > 0 {
+			line("BEGIN:VALARM")
+			line("ACTION:DISPLAY")
+			line("DESCRIPTION:%s", escapeText(e.Title))
+			line("TRIGGER:-PT%dM", e.RemindMinutes)
+			line("END:VALARM")
+		}
+		line("END:VEVENT")
+	}
+
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
+
+// escapeText escapes a TEXT property value
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeFolded writes a content line terminated by CRLF, folding it into
+// continuation lines that start with a space without splitting UTF-8 sequences
+func writeFolded(w *bufio.Writer, line string) {
+	limit := icsLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space of continuation lines counts towards the limit
+		limit = icsLineLimit - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,68 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestWriteICS(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC)
+	events := []models.Event{
+		{
+			ID: "1", Kind: models.EventUnlock, Title: "ARB unlock, 1.1B tokens", Description: "Team; investors\nCliff ends",
+			Start: start, RemindMinutes: 60, CreatedAt: start,
+		},
+		{ID: "2", Kind: models.EventReminder, Title: "Rebalance", Start: time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC), AllDay: true, CreatedAt: start},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteICS(&buf, "Crypto dashboard", events); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:1@crypto-dashboard\r\n",
+		"DTSTART:20240601T123000Z\r\n",
+		`SUMMARY:ARB unlock\, 1.1B tokens` + "\r\n",
+		`DESCRIPTION:Team\; investors\nCliff ends` + "\r\n",
+		"CATEGORIES:UNLOCK\r\n",
+		"TRIGGER:-PT60M\r\n",
+		"DTSTART;VALUE=DATE:20240603\r\n",
+		"DTEND;VALUE=DATE:20240604\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected feed to contain %q, got\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "BEGIN:VEVENT") != 2 {
+		t.Errorf("Expected two events, got\n%s", out)
+	}
+}
+
+func TestWriteICS_FoldsLongLines(t *testing.T) {
+	title := strings.Repeat("é", 100)
+	var buf bytes.Buffer
+	WriteICS(&buf, "x", []models.Event{{ID: "1", Title: title, Start: time.Now()}})
+
+	var unfolded strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > icsLineLimit {
+			t.Errorf("Line exceeds %d octets: %q", icsLineLimit, line)
+		}
+		if strings.HasPrefix(line, " ") {
+			unfolded.WriteString(line[1:])
+		} else {
+			unfolded.WriteString("\n" + line)
+		}
+	}
+	if !strings.Contains(unfolded.String(), "SUMMARY:"+title) {
+		t.Error("Expected folded summary to unfold to the original title")
+	}
+}
@@ -0,0 +1,82 @@
+// Package calendar manages scheduled market events and user reminders
+package calendar
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// ErrNotFound is returned when an event does not exist
+var ErrNotFound = errors.New("event not found")
+
+// Repository persists events
+type Repository interface {
+	Save(e models.Event) (models.Event, error)
+	Get(id string) (models.Event, error)
+	List() ([]models.Event, error)
+	Delete(id string) error
+}
+
+// Service manages calendar events
+type Service struct {
+	repo Repository
+}
+
+// NewService creates a calendar service
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Create validates and stores a new event
+func (s *Service) Create(e models.Event) (models.Event, error) {
+	e.ID = ""
+	e.Normalize()
+	if err := e.Validate(); err != nil {
+		return models.Event{}, err
+	}
+	e.CreatedAt = time.Now().UTC()
+	return s.repo.Save(e)
+}
+
+// Get returns an event by ID
+func (s *Service) Get(id string) (models.Event, error) {
+	return s.repo.Get(id)
+}
+
+// Delete removes an event
+func (s *Service) Delete(id string) error {
+	return s.repo.Delete(id)
+}
+
+// All returns the events of every owner
+func (s *Service) All() ([]models.Event, error) {
+	return s.repo.List()
+}
+
+// Events returns the events visible to the owner, i.e. their own and the shared
+// ones, ordered by start. A zero from or to leaves that side of the range open.
+func (s *Service) Events(owner string, from, to time.Time) ([]models.Event, error) {
+	all, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	if to.IsZero() {
+		to = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	var events []models.Event
+	for _, e := range all {
+		if e.Owner != owner && e.Owner != models.DefaultOwner {
+			continue
+		}
+		if !e.Overlaps(from, to) {
+			continue
+		}
+		events = append(events, e)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+	return events, nil
+}
@@ -0,0 +1,77 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubRepo map[string]models.Event
+
+func (r stubRepo) Save(e models.Event) (models.Event, error) {
+	if e.ID == "" {
+		e.ID = string(rune('a' + len(r)))
+	}
+	r[e.ID] = e
+	return e, nil
+}
+
+func (r stubRepo) Get(id string) (models.Event, error) {
+	e, ok := r[id]
+	if !ok {
+		return models.Event{}, ErrNotFound
+	}
+	return e, nil
+}
+
+func (r stubRepo) List() ([]models.Event, error) {
+	var events []models.Event
+	for _, e := range r {
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+func (r stubRepo) Delete(id string) error {
+	delete(r, id)
+	return nil
+}
+
+func TestService_Create(t *testing.T) {
+	s := NewService(stubRepo{})
+
+	created, err := s.Create(models.Event{ID: "ignored", Title: " Unlock ", Start: time.Now()})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if created.ID == "ignored" || created.Title != "Unlock" || created.Kind != models.EventOther || created.CreatedAt.IsZero() {
+		t.Errorf("Unexpected event: %+v", created)
+	}
+
+	if _, err := s.Create(models.Event{Start: time.Now()}); err == nil {
+		t.Error("Expected an error for an event without title")
+	}
+}
+
+func TestService_Events(t *testing.T) {
+	s := NewService(stubRepo{})
+	day := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	s.Create(models.Event{Title: "Shared unlock", Start: day.Add(10 * time.Hour)})
+	s.Create(models.Event{Title: "My reminder", Owner: "alice", Kind: models.EventReminder, Start: day.Add(8 * time.Hour)})
+	s.Create(models.Event{Title: "Bob's reminder", Owner: "bob", Start: day.Add(9 * time.Hour)})
+	s.Create(models.Event{Title: "Next week", Start: day.AddDate(0, 0, 7)})
+
+	events, err := s.Events("alice", day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[0].Title != "My reminder" || events[1].Title != "Shared unlock" {
+		t.Errorf("Expected own and shared events of the day ordered by start, got %+v", events)
+	}
+
+	if all, _ := s.Events("alice", time.Time{}, time.Time{}); len(all) != 3 {
+		t.Errorf("Expected an open range to include every visible event, got %d", len(all))
+	}
+}
@@ -0,0 +1,133 @@
+package candles
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// backfillChunk is the longest range fetched at once; CoinGecko answers with
+// hourly prices up to 90 days and only daily ones beyond
+const backfillChunk = 90 * 24 * time.Hour
+
+// HistorySource fetches the recorded prices of a coin over a time range
+type HistorySource interface {
+	GetPriceRange(cryptoID string, currency models.Currency, from, to time.Time) ([]models.PricePoint, error)
+}
+
+// Backfiller seeds the repository with candles built from the provider's
+// price history so charts have data before the first candles close
+type Backfiller struct {
+	source    HistorySource
+	repo      Repository
+	currency  models.Currency
+	intervals []time.Duration
+	logger    *slog.Logger
+	clock     clock.Clock
+
+	mu       sync.Mutex
+	lastCoin time.Time
+}
+
+// NewBackfiller creates a backfiller storing candles of every interval.
+// Intervals shorter than an hour are ignored since the fetched history is hourly.
+func NewBackfiller(source HistorySource, repo Repository, currency models.Currency, intervals ...time.Duration) *Backfiller {
+	intervals = slices.DeleteFunc(slices.Clone(intervals), func(d time.Duration) bool { return d < time.Hour })
+	slices.Sort(intervals)
+	return &Backfiller{
+		source:    source,
+		repo:      repo,
+		currency:  currency,
+		intervals: slices.Compact(intervals),
+		logger:    slog.Default(),
+		clock:     clock.Real,
+	}
+}
+
+// SetLogger replaces the default logger used to report failed backfills
+func (b *Backfiller) SetLogger(logger *slog.Logger) {
+	b.logger = logger
+}
+
+// SetClock replaces the system clock, for tests
+func (b *Backfiller) SetClock(c clock.Clock) {
+	b.clock = c
+}
+
+// Backfill fetches the last days of history of a coin and stores its closed
+// candles, replacing stored ones with the same open time. It returns the
+// number of candles stored.
+func (b *Backfiller) Backfill(cryptoID string, days int) (int, error) {
+	if days <= 0 {
+		return 0, fmt.Errorf("days must be positive, got %d", days)
+	}
+	now := b.clock.Now().UTC()
+	var points []models.PricePoint
+	for from := now.AddDate(0, 0, -days); from.Before(now); from = from.Add(backfillChunk) {
+		to := from.Add(backfillChunk)
+		if to.After(now) {
+			to = now
+		}
+		chunk, err := b.source.GetPriceRange(cryptoID, b.currency, from, to)
+		if err != nil {
+			return 0, err
+		}
+		points = append(points, chunk...)
+	}
+
+	stored := 0
+	for _, interval := range b.intervals {
+		for _, c := range FromPoints(cryptoID, points, interval) {
+			if c.CloseTime.After(now) {
+				// The open candle is left to the builder
+				continue
+			}
+			if err := b.repo.SaveCandle(interval, c); err != nil {
+				return stored, err
+			}
+			stored++
+		}
+	}
+	return stored, nil
+}
+
+// Run backfills every coin that has no candles yet, so restarts do not
+// fetch the history again. Failures are logged and do not stop the others.
+func (b *Backfiller) Run(ctx context.Context, coins []string, days int) {
+	if len(b.intervals) == 0 || days <= 0 {
+		return
+	}
+	for _, id := range coins {
+		if ctx.Err() != nil {
+			return
+		}
+		now := b.clock.Now()
+		b.mu.Lock()
+		b.lastCoin = now
+		b.mu.Unlock()
+		existing, err := b.repo.Candles(id, b.intervals[0], 1)
+		if err == nil && len(existing) > 0 {
+			continue
+		}
+		stored, err := b.Backfill(id, days)
+		if err != nil {
+			b.logger.Warn("candle backfill failed", "crypto", id, "error", err)
+			continue
+		}
+		b.logger.Info("backfilled candles", "crypto", id, "days", days, "candles", stored)
+	}
+}
+
+// LastRun returns when the backfill last moved on to a coin, for the
+// watchdog; a coin is fetched in chunks and takes a while on its own
+func (b *Backfiller) LastRun() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastCoin
+}
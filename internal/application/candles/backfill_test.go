@@ -0,0 +1,93 @@
+package candles
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// hourlySource returns one point per hour of the requested range
+type hourlySource struct {
+	calls int
+	err   error
+}
+
+func (s *hourlySource) GetPriceRange(id string, currency models.Currency, from, to time.Time) ([]models.PricePoint, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	var points []models.PricePoint
+	for at := from.Truncate(time.Hour); at.Before(to); at = at.Add(time.Hour) {
+		points = append(points, models.PricePoint{Price: float64(at.Hour()), Time: at})
+	}
+	return points, nil
+}
+
+// intervalRepo stores candles per interval
+type intervalRepo map[time.Duration][]models.Candle
+
+func (r intervalRepo) SaveCandle(interval time.Duration, c models.Candle) error {
+	r[interval] = append(r[interval], c)
+	return nil
+}
+
+func (r intervalRepo) Candles(id string, interval time.Duration, limit int) ([]models.Candle, error) {
+	return r[interval], nil
+}
+
+func TestBackfiller_Backfill(t *testing.T) {
+	source := &hourlySource{}
+	repo := intervalRepo{}
+	b := NewBackfiller(source, repo, models.DefaultCurrency, 5*time.Minute, time.Hour, 24*time.Hour)
+	now := time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC)
+	b.SetClock(clock.NewFake(now))
+
+	stored, err := b.Backfill("bitcoin", 100)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if source.calls != 2 {
+		t.Errorf("Expected 100 days to be fetched in 2 chunks, got %d calls", source.calls)
+	}
+	if len(repo[5*time.Minute]) != 0 {
+		t.Errorf("Expected intervals under an hour to be skipped")
+	}
+	hourly, daily := repo[time.Hour], repo[24*time.Hour]
+	if len(hourly) != 100*24 {
+		t.Errorf("Expected %d closed hourly candles, got %d", 100*24, len(hourly))
+	}
+	if last := hourly[len(hourly)-1]; last.CloseTime.After(now) {
+		t.Errorf("Expected the open candle to be left out, got %+v", last)
+	}
+	// The first day is partial and the current one is still open
+	if len(daily) != 100 {
+		t.Errorf("Expected 100 daily candles, got %d", len(daily))
+	}
+	if stored != len(hourly)+len(daily) {
+		t.Errorf("Expected %d stored candles, got %d", len(hourly)+len(daily), stored)
+	}
+}
+
+func TestBackfiller_RunSkipsBackfilledCoins(t *testing.T) {
+	source := &hourlySource{}
+	repo := intervalRepo{time.Hour: {models.NewCandle("bitcoin", 1, time.Now(), time.Hour)}}
+	b := NewBackfiller(source, repo, models.DefaultCurrency, time.Hour)
+
+	b.Run(context.Background(), []string{"bitcoin"}, 7)
+	if source.calls != 0 {
+		t.Errorf("Expected no fetch for a coin with candles, got %d", source.calls)
+	}
+
+	source.err = errors.New("unavailable")
+	if _, err := b.Backfill("ethereum", 7); err == nil {
+		t.Error("Expected the source error to be returned")
+	}
+	if _, err := b.Backfill("ethereum", 0); err == nil {
+		t.Error("Expected an error for zero days")
+	}
+}
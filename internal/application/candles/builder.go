@@ -0,0 +1,192 @@
+// Package candles aggregates price ticks into OHLC candles and stores them
+package candles
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// maxQueuedPolls bounds the polls waiting for Run; the oldest are dropped
+// beyond it, which only happens while Run is wedged
+const maxQueuedPolls = 1000
+
+// Repository persists closed candles per coin and interval
+type Repository interface {
+	SaveCandle(interval time.Duration, candle models.Candle) error
+	Candles(cryptoID string, interval time.Duration, limit int) ([]models.Candle, error)
+}
+
+// CloseHandler is called every time a candle closes
+type CloseHandler func(candle models.Candle)
+
+// Builder turns a stream of price ticks into candles of a fixed interval.
+// A candle closes when the first tick of the next interval arrives.
+type Builder struct {
+	interval time.Duration
+	repo     Repository
+	clock    clock.Clock
+	logger   *slog.Logger
+
+	mu       sync.Mutex
+	open     map[string]*models.Candle
+	handlers []CloseHandler
+	consumed time.Time
+	queue    []queuedPoll
+	wake     chan struct{}
+}
+
+// queuedPoll is the prices of one poll waiting to be added
+type queuedPoll struct {
+	prices []models.CryptoPrice
+	at     time.Time
+}
+
+// NewBuilder creates a candle builder storing closed candles in repo
+func NewBuilder(interval time.Duration, repo Repository) *Builder {
+	return &Builder{
+		interval: interval,
+		repo:     repo,
+		clock:    clock.Real,
+		logger:   slog.Default(),
+		open:     make(map[string]*models.Candle),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// SetClock replaces the system clock Consumed follows. It must be called before Run.
+func (b *Builder) SetClock(c clock.Clock) {
+	b.clock = c
+}
+
+// SetLogger replaces the default logger
+func (b *Builder) SetLogger(logger *slog.Logger) {
+	b.logger = logger
+}
+
+// Interval returns the candle interval
+func (b *Builder) Interval() time.Duration {
+	return b.interval
+}
+
+// OnClose registers a handler invoked after a candle is stored
+func (b *Builder) OnClose(handler CloseHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// AddTick records a price observation
+func (b *Builder) AddTick(cryptoID string, price float64, at time.Time) error {
+	b.mu.Lock()
+	current, ok := b.open[cryptoID]
+	if ok && current.Contains(at) {
+		current.Update(price)
+		b.mu.Unlock()
+		return nil
+	}
+	if ok && at.Before(current.OpenTime) {
+		// Late tick for an already closed interval
+		b.mu.Unlock()
+		return nil
+	}
+
+	next := models.NewCandle(cryptoID, price, at, b.interval)
+	b.open[cryptoID] = &next
+	handlers := append([]CloseHandler(nil), b.handlers...)
+	b.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	closed := *current
+	if err := b.repo.SaveCandle(b.interval, closed); err != nil {
+		return err
+	}
+	for _, handler := range handlers {
+		handler(closed)
+	}
+	return nil
+}
+
+// Current returns the candle still being built for a coin
+func (b *Builder) Current(cryptoID string) (models.Candle, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.open[cryptoID]
+	if !ok {
+		return models.Candle{}, false
+	}
+	return *c, true
+}
+
+// Enqueue queues the prices of a poll for Run. Every coin gets a tick, so
+// periods without price changes still produce flat candles. It implements
+// poller.TickSink and never blocks the poller.
+func (b *Builder) Enqueue(prices []models.CryptoPrice, at time.Time) {
+	b.mu.Lock()
+	b.queue = append(b.queue, queuedPoll{prices: prices, at: at})
+	if dropped := len(b.queue) - maxQueuedPolls; dropped > 0 {
+		b.queue = b.queue[dropped:]
+		b.logger.Warn("candle queue full, dropping the oldest polls", "dropped", dropped)
+	}
+	b.mu.Unlock()
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Pending reports whether queued polls are waiting for Run
+func (b *Builder) Pending() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.queue) > 0
+}
+
+// Run adds the ticks of the queued polls until the context is cancelled
+func (b *Builder) Run(ctx context.Context) {
+	for {
+		b.mu.Lock()
+		var next queuedPoll
+		queued := len(b.queue) > 0
+		if queued {
+			next = b.queue[0]
+			b.queue = b.queue[1:]
+		}
+		b.mu.Unlock()
+
+		if !queued {
+			select {
+			case <-ctx.Done():
+				return
+			case <-b.wake:
+			}
+			continue
+		}
+		for _, price := range next.prices {
+			if err := b.AddTick(price.ID, price.PriceFloat(), next.at); err != nil {
+				b.logger.Error("failed to store candle", "crypto", price.ID, "error", err)
+			}
+		}
+		now := b.clock.Now()
+		b.mu.Lock()
+		b.consumed = now
+		b.mu.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// Consumed returns when Run last finished adding a poll, or the zero time if
+// it has not yet
+func (b *Builder) Consumed() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.consumed
+}
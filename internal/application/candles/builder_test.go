@@ -0,0 +1,102 @@
+package candles
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+type fakeRepo struct {
+	saved []models.Candle
+}
+
+func (f *fakeRepo) SaveCandle(interval time.Duration, c models.Candle) error {
+	f.saved = append(f.saved, c)
+	return nil
+}
+
+func (f *fakeRepo) Candles(id string, interval time.Duration, limit int) ([]models.Candle, error) {
+	return f.saved, nil
+}
+
+func TestBuilder_AddTick(t *testing.T) {
+	repo := &fakeRepo{}
+	b := NewBuilder(time.Hour, repo)
+
+	var closed []models.Candle
+	b.OnClose(func(c models.Candle) { closed = append(closed, c) })
+
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	b.AddTick("bitcoin", 100, start.Add(5*time.Minute))
+	b.AddTick("bitcoin", 110, start.Add(20*time.Minute))
+	b.AddTick("bitcoin", 95, start.Add(40*time.Minute))
+	if len(closed) != 0 {
+		t.Fatalf("Expected no closed candle yet, got %d", len(closed))
+	}
+
+	b.AddTick("bitcoin", 101, start.Add(65*time.Minute))
+	if len(closed) != 1 || len(repo.saved) != 1 {
+		t.Fatalf("Expected one closed and stored candle, got %d/%d", len(closed), len(repo.saved))
+	}
+	c := closed[0]
+	if c.Open != 100 || c.High != 110 || c.Low != 95 || c.Close != 95 {
+		t.Errorf("Unexpected OHLC: %+v", c)
+	}
+
+	current, ok := b.Current("bitcoin")
+	if !ok || current.Open != 101 {
+		t.Errorf("Expected a new candle opened at 101, got %+v", current)
+	}
+
+	// Ticks older than the open candle are ignored
+	b.AddTick("bitcoin", 1, start)
+	if current, _ := b.Current("bitcoin"); current.Low != 101 {
+		t.Errorf("Expected late tick to be ignored, got low %f", current.Low)
+	}
+}
+
+func TestBuilder_RunAddsQueuedPolls(t *testing.T) {
+	repo := &fakeRepo{}
+	b := NewBuilder(time.Hour, repo)
+	clk := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	b.SetClock(clk)
+	closed := make(chan models.Candle, 1)
+	b.OnClose(func(c models.Candle) { closed <- c })
+
+	// A flat price still opens and closes a candle every interval
+	prices := []models.CryptoPrice{{ID: "bitcoin", CurrentPrice: decimal.NewFromInt(100)}}
+	b.Enqueue(prices, time.Date(2024, 1, 1, 10, 5, 0, 0, time.UTC))
+	b.Enqueue(prices, time.Date(2024, 1, 1, 11, 5, 0, 0, time.UTC))
+	if !b.Pending() {
+		t.Fatal("Expected the polls to wait for Run")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		b.Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the queued polls to close a candle")
+	}
+	cancel()
+	<-done
+
+	if current, ok := b.Current("bitcoin"); !ok || current.OpenTime.Hour() != 11 || current.Open != 100 {
+		t.Errorf("Expected a flat candle opened at 11:00, got %+v", current)
+	}
+	if len(repo.saved) != 1 || repo.saved[0].Close != 100 {
+		t.Errorf("Expected the 10:00 candle closed at 100, got %+v", repo.saved)
+	}
+	if !b.Consumed().Equal(clk.Now()) {
+		t.Errorf("Expected the consumption to be stamped with the clock, got %s", b.Consumed())
+	}
+}
@@ -0,0 +1,140 @@
+package candles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+)
+
+// Store is a candle repository that can drop old candles
+type Store interface {
+	Repository
+	PruneCandles(cryptoID string, interval time.Duration, before time.Time) (int, error)
+}
+
+// Maintainer rolls the candles built from polled prices up into longer
+// intervals and prunes them once they are older than the retention window,
+// so storage stays bounded while long-range charts keep their data
+type Maintainer struct {
+	repo      Store
+	base      time.Duration
+	rollups   []time.Duration
+	retention time.Duration
+	coins     func() []string
+	logger    *slog.Logger
+	clock     clock.Clock
+
+	mu      sync.Mutex
+	lastRun time.Time
+}
+
+// NewMaintainer creates a maintainer for the candles of the base interval of
+// the coins returned by coins. Rollups not longer than base are ignored and a
+// zero retention keeps base candles forever.
+func NewMaintainer(repo Store, base time.Duration, rollups []time.Duration, retention time.Duration, coins func() []string) *Maintainer {
+	rollups = slices.DeleteFunc(slices.Clone(rollups), func(d time.Duration) bool { return d <= base })
+	slices.Sort(rollups)
+	return &Maintainer{
+		repo:      repo,
+		base:      base,
+		rollups:   slices.Compact(rollups),
+		retention: retention,
+		coins:     coins,
+		logger:    slog.Default(),
+		clock:     clock.Real,
+	}
+}
+
+// SetLogger replaces the default logger used to report failed runs
+func (m *Maintainer) SetLogger(logger *slog.Logger) {
+	m.logger = logger
+}
+
+// SetClock replaces the system clock, for tests
+func (m *Maintainer) SetClock(c clock.Clock) {
+	m.clock = c
+}
+
+// Run maintains the candles every interval until the context is cancelled
+func (m *Maintainer) Run(ctx context.Context, every time.Duration) {
+	ticker := m.clock.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		if err := m.RunOnce(); err != nil {
+			m.logger.Warn("candle maintenance failed", "error", err)
+		}
+		now := m.clock.Now()
+		m.mu.Lock()
+		m.lastRun = now
+		m.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+	}
+}
+
+// LastRun returns when the last maintenance run ended, for the watchdog
+func (m *Maintainer) LastRun() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastRun
+}
+
+// RunOnce rolls up and prunes the candles of every coin
+func (m *Maintainer) RunOnce() error {
+	now := m.clock.Now().UTC()
+	cutoff := m.cutoff(now)
+	var errs []error
+	for _, id := range m.coins() {
+		if err := m.maintain(id, now, cutoff); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// cutoff is the time before which base candles are pruned. It is aligned to
+// the longest rollup so no period is left partly pruned; zero keeps everything.
+func (m *Maintainer) cutoff(now time.Time) time.Time {
+	if m.retention <= 0 {
+		return time.Time{}
+	}
+	align := m.base
+	if len(m.rollups) > 0 {
+		align = m.rollups[len(m.rollups)-1]
+	}
+	return now.Add(-m.retention).Truncate(align)
+}
+
+func (m *Maintainer) maintain(cryptoID string, now, cutoff time.Time) error {
+	raw, err := m.repo.Candles(cryptoID, m.base, 0)
+	if err != nil || len(raw) == 0 {
+		return err
+	}
+	for _, interval := range m.rollups {
+		for _, c := range Rollup(raw, interval) {
+			// Only closed periods covered from their start are rolled, and
+			// periods before the cutoff were rolled before being pruned
+			if c.CloseTime.After(now) || c.OpenTime.Before(raw[0].OpenTime) || c.OpenTime.Before(cutoff) {
+				continue
+			}
+			if err := m.repo.SaveCandle(interval, c); err != nil {
+				return err
+			}
+		}
+	}
+	if cutoff.IsZero() {
+		return nil
+	}
+	_, err = m.repo.PruneCandles(cryptoID, m.base, cutoff)
+	return err
+}
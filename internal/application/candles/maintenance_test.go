@@ -0,0 +1,119 @@
+package candles
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// pruningRepo keeps candles per interval sorted by open time
+type pruningRepo map[time.Duration][]models.Candle
+
+func (r pruningRepo) SaveCandle(interval time.Duration, c models.Candle) error {
+	series := r[interval]
+	for i := range series {
+		if series[i].OpenTime.Equal(c.OpenTime) {
+			series[i] = c
+			return nil
+		}
+	}
+	r[interval] = append(series, c)
+	return nil
+}
+
+func (r pruningRepo) Candles(id string, interval time.Duration, limit int) ([]models.Candle, error) {
+	return r[interval], nil
+}
+
+func (r pruningRepo) PruneCandles(id string, interval time.Duration, before time.Time) (int, error) {
+	var kept []models.Candle
+	for _, c := range r[interval] {
+		if !c.OpenTime.Before(before) {
+			kept = append(kept, c)
+		}
+	}
+	removed := len(r[interval]) - len(kept)
+	r[interval] = kept
+	return removed, nil
+}
+
+func TestMaintainer_RollsUpAndPrunes(t *testing.T) {
+	repo := pruningRepo{}
+	start := time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)
+	// Hourly candles from 06:00 on day one to 12:00 on day four
+	for h := range 3*24 + 6 {
+		repo.SaveCandle(time.Hour, models.NewCandle("bitcoin", float64(h), start.Add(time.Duration(h)*time.Hour), time.Hour))
+	}
+
+	m := NewMaintainer(repo, time.Hour, []time.Duration{24 * time.Hour, time.Hour}, 48*time.Hour, func() []string { return []string{"bitcoin"} })
+	now := time.Date(2024, 1, 4, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(now)
+	m.SetClock(clk)
+	if err := m.RunOnce(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Day one is not covered from midnight and day four is still open
+	daily := repo[24*time.Hour]
+	if len(daily) != 2 {
+		t.Fatalf("Expected 2 daily candles, got %d", len(daily))
+	}
+	if d := daily[0]; !d.OpenTime.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) || d.Open != 18 || d.Close != 41 {
+		t.Errorf("Unexpected daily candle: %+v", d)
+	}
+
+	// Hourly candles before midnight two days ago are pruned
+	hourly := repo[time.Hour]
+	if first := hourly[0].OpenTime; !first.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected hourly candles to start on 2024-01-02, got %s", first)
+	}
+
+	// Pruned days are not rolled again from what is left
+	clk.Advance(48 * time.Hour)
+	m.RunOnce()
+	if d := repo[24*time.Hour][0]; d.Open != 18 {
+		t.Errorf("Expected the first daily candle to be kept, got %+v", d)
+	}
+}
+
+func TestMaintainer_ZeroRetentionKeepsCandles(t *testing.T) {
+	repo := pruningRepo{}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for h := range 48 {
+		repo.SaveCandle(time.Hour, models.NewCandle("bitcoin", 1, start.Add(time.Duration(h)*time.Hour), time.Hour))
+	}
+
+	m := NewMaintainer(repo, time.Hour, []time.Duration{24 * time.Hour}, 0, func() []string { return []string{"bitcoin"} })
+	m.SetClock(clock.NewFake(start.AddDate(1, 0, 0)))
+	m.RunOnce()
+	if len(repo[time.Hour]) != 48 || len(repo[24*time.Hour]) != 2 {
+		t.Errorf("Expected 48 hourly and 2 daily candles, got %d and %d", len(repo[time.Hour]), len(repo[24*time.Hour]))
+	}
+}
+
+func TestMaintainer_RunReportsProgress(t *testing.T) {
+	m := NewMaintainer(pruningRepo{}, time.Minute, nil, 0, func() []string { return nil })
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	m.SetClock(fake)
+	if !m.LastRun().IsZero() {
+		t.Fatalf("Expected no run yet, got %v", m.LastRun())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		m.Run(ctx, time.Hour)
+		close(done)
+	}()
+	for m.LastRun().IsZero() {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+	if !m.LastRun().Equal(fake.Now()) {
+		t.Errorf("Expected the run at %v, got %v", fake.Now(), m.LastRun())
+	}
+}
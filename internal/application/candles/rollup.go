@@ -0,0 +1,49 @@
+package candles
+
+import (
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// FromPoints builds candles of an interval from price points ordered by time.
+// Points carry no volume, so the candles have none either.
+func FromPoints(cryptoID string, points []models.PricePoint, interval time.Duration) []models.Candle {
+	var out []models.Candle
+	for _, p := range points {
+		if n := len(out); n > 0 && out[n-1].Contains(p.Time) {
+			out[n-1].Update(p.Price)
+			continue
+		}
+		out = append(out, models.NewCandle(cryptoID, p.Price, p.Time, interval))
+	}
+	return out
+}
+
+// Rollup combines candles ordered by open time into candles of a longer
+// interval, which should be a multiple of theirs
+func Rollup(candles []models.Candle, interval time.Duration) []models.Candle {
+	var out []models.Candle
+	for _, c := range candles {
+		if n := len(out); n > 0 && out[n-1].Contains(c.OpenTime) {
+			last := &out[n-1]
+			last.High = max(last.High, c.High)
+			last.Low = min(last.Low, c.Low)
+			last.Close = c.Close
+			last.Volume += c.Volume
+			continue
+		}
+		open := c.OpenTime.UTC().Truncate(interval)
+		out = append(out, models.Candle{
+			CryptoID:  c.CryptoID,
+			OpenTime:  open,
+			CloseTime: open.Add(interval),
+			Open:      c.Open,
+			High:      c.High,
+			Low:       c.Low,
+			Close:     c.Close,
+			Volume:    c.Volume,
+		})
+	}
+	return out
+}
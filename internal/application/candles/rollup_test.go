@@ -0,0 +1,54 @@
+package candles
+
+import (
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestFromPoints(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	points := []models.PricePoint{
+		{Price: 100, Time: start.Add(5 * time.Minute)},
+		{Price: 120, Time: start.Add(30 * time.Minute)},
+		{Price: 90, Time: start.Add(50 * time.Minute)},
+		{Price: 95, Time: start.Add(70 * time.Minute)},
+	}
+
+	got := FromPoints("bitcoin", points, time.Hour)
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 candles, got %d", len(got))
+	}
+	if c := got[0]; !c.OpenTime.Equal(start) || c.Open != 100 || c.High != 120 || c.Low != 90 || c.Close != 90 {
+		t.Errorf("Unexpected first candle: %+v", c)
+	}
+	if got[1].Open != 95 {
+		t.Errorf("Expected the second candle to open at 95, got %+v", got[1])
+	}
+}
+
+func TestRollup(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var hourly []models.Candle
+	for h := range 30 {
+		c := models.NewCandle("bitcoin", float64(100+h), start.Add(time.Duration(h)*time.Hour), time.Hour)
+		c.Volume = 1
+		hourly = append(hourly, c)
+	}
+
+	daily := Rollup(hourly, 24*time.Hour)
+	if len(daily) != 2 {
+		t.Fatalf("Expected 2 daily candles, got %d", len(daily))
+	}
+	day := daily[0]
+	if !day.OpenTime.Equal(start) || !day.CloseTime.Equal(start.Add(24*time.Hour)) {
+		t.Errorf("Unexpected period: %s - %s", day.OpenTime, day.CloseTime)
+	}
+	if day.Open != 100 || day.High != 123 || day.Low != 100 || day.Close != 123 || day.Volume != 24 {
+		t.Errorf("Unexpected daily candle: %+v", day)
+	}
+	if daily[1].Open != 124 || daily[1].Volume != 6 {
+		t.Errorf("Unexpected partial day: %+v", daily[1])
+	}
+}
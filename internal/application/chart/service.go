@@ -0,0 +1,89 @@
+// Package chart stores named chart configurations that are shared by their
+// stable ID and resolved by the dashboard and by chart renderers alike
+package chart
+
+import (
+	"errors"
+	"maps"
+	"sort"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// ErrNotFound is returned when a chart configuration does not exist
+var ErrNotFound = errors.New("chart not found")
+
+// Repository persists chart configurations
+type Repository interface {
+	Save(c models.ChartConfig) (models.ChartConfig, error)
+	Get(id string) (models.ChartConfig, error)
+	List() ([]models.ChartConfig, error)
+	Delete(id string) error
+}
+
+// Service manages chart configurations
+type Service struct {
+	repo Repository
+}
+
+// NewService creates a chart configuration service
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Create validates and stores a new configuration under a new ID
+func (s *Service) Create(c models.ChartConfig) (models.ChartConfig, error) {
+	c.ID = ""
+	c.Indicators = maps.Clone(c.Indicators)
+	c.Normalize()
+	if err := c.Validate(); err != nil {
+		return models.ChartConfig{}, err
+	}
+	c.CreatedAt = time.Now().UTC()
+	c.UpdatedAt = c.CreatedAt
+	return s.repo.Save(c)
+}
+
+// List returns the configurations of an owner, most recently changed first
+func (s *Service) List(owner string) ([]models.ChartConfig, error) {
+	all, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	charts := []models.ChartConfig{}
+	for _, c := range all {
+		if c.Owner == owner {
+			charts = append(charts, c)
+		}
+	}
+	sort.SliceStable(charts, func(i, j int) bool { return charts[i].UpdatedAt.After(charts[j].UpdatedAt) })
+	return charts, nil
+}
+
+// Get returns a configuration by ID, whoever owns it
+func (s *Service) Get(id string) (models.ChartConfig, error) {
+	return s.repo.Get(id)
+}
+
+// Update replaces the settings of a configuration. Its ID, and so its
+// permalink, owner and creation time are kept.
+func (s *Service) Update(id string, c models.ChartConfig) (models.ChartConfig, error) {
+	current, err := s.repo.Get(id)
+	if err != nil {
+		return models.ChartConfig{}, err
+	}
+	c.ID, c.Owner, c.CreatedAt = current.ID, current.Owner, current.CreatedAt
+	c.Indicators = maps.Clone(c.Indicators)
+	c.Normalize()
+	if err := c.Validate(); err != nil {
+		return models.ChartConfig{}, err
+	}
+	c.UpdatedAt = time.Now().UTC()
+	return s.repo.Save(c)
+}
+
+// Delete removes a configuration; its permalink stops resolving
+func (s *Service) Delete(id string) error {
+	return s.repo.Delete(id)
+}
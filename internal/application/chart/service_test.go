@@ -0,0 +1,75 @@
+package chart
+
+import (
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubRepo map[string]models.ChartConfig
+
+func (r stubRepo) Save(c models.ChartConfig) (models.ChartConfig, error) {
+	if c.ID == "" {
+		c.ID = c.Name
+	}
+	r[c.ID] = c
+	return c, nil
+}
+
+func (r stubRepo) Get(id string) (models.ChartConfig, error) {
+	c, ok := r[id]
+	if !ok {
+		return models.ChartConfig{}, ErrNotFound
+	}
+	return c, nil
+}
+
+func (r stubRepo) List() ([]models.ChartConfig, error) {
+	var charts []models.ChartConfig
+	for _, c := range r {
+		charts = append(charts, c)
+	}
+	return charts, nil
+}
+
+func (r stubRepo) Delete(id string) error {
+	delete(r, id)
+	return nil
+}
+
+func TestService_UpdateKeepsIdentity(t *testing.T) {
+	s := NewService(stubRepo{})
+	created, err := s.Create(models.ChartConfig{ID: "chosen", Owner: "alice", Name: "cycle", CryptoID: "bitcoin"})
+	if err != nil || created.ID != "cycle" {
+		t.Fatalf("Expected a repository ID, got %+v (%v)", created, err)
+	}
+
+	updated, err := s.Update(created.ID, models.ChartConfig{Owner: "mallory", Name: "cycle", CryptoID: "bitcoin", Range: models.ChartMax})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.ID != created.ID || updated.Owner != "alice" || !updated.CreatedAt.Equal(created.CreatedAt) || updated.Scale != models.ChartLog {
+		t.Errorf("Expected new settings under the same identity, got %+v", updated)
+	}
+
+	if _, err := s.Update(created.ID, models.ChartConfig{Name: "cycle", CryptoID: "bitcoin", Scale: "sqrt"}); err == nil {
+		t.Error("Expected an invalid update to be rejected")
+	}
+}
+
+func TestService_ListsOwnChartsRecentFirst(t *testing.T) {
+	repo := stubRepo{}
+	s := NewService(repo)
+	s.Create(models.ChartConfig{Owner: "alice", Name: "old", CryptoID: "bitcoin"})
+	s.Create(models.ChartConfig{Owner: "bob", Name: "other", CryptoID: "bitcoin"})
+	s.Create(models.ChartConfig{Owner: "alice", Name: "new", CryptoID: "ethereum"})
+	old := repo["old"]
+	old.UpdatedAt = old.UpdatedAt.Add(-time.Hour)
+	repo["old"] = old
+
+	charts, err := s.List("alice")
+	if err != nil || len(charts) != 2 || charts[0].Name != "new" || charts[1].Name != "old" {
+		t.Errorf("Expected alice's charts, most recent first, got %+v (%v)", charts, err)
+	}
+}
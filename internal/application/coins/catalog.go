@@ -0,0 +1,136 @@
+package coins
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// CatalogSize is how many of the largest coins the local catalog keeps
+const CatalogSize = 500
+
+// maxCatalogResults bounds the results of a search answered by the catalog
+const maxCatalogResults = 25
+
+// Markets lists the largest coins by market cap
+type Markets interface {
+	GetTopNCryptos(n int, currency models.Currency) ([]models.CryptoPrice, error)
+}
+
+// SetCatalog replaces the local list of coins, ordered by market cap rank,
+// e.g. with a bundled snapshot. Until the first sync, searches it can answer
+// are answered from it without calling the provider, and lookups fall back
+// to it whenever the provider fails.
+func (s *Service) SetCatalog(coins []models.SearchResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.catalog = slices.Clone(coins)
+}
+
+// SetLogger replaces the default logger
+func (s *Service) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
+// SyncCatalog replaces the catalog with the current largest coins
+func (s *Service) SyncCatalog(markets Markets) error {
+	prices, err := markets.GetTopNCryptos(CatalogSize, models.DefaultCurrency)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.catalog = CatalogOf(prices)
+	s.synced = true
+	return nil
+}
+
+// CatalogOf turns a market cap ranking into catalog entries
+func CatalogOf(ranking []models.CryptoPrice) []models.SearchResult {
+	coins := make([]models.SearchResult, len(ranking))
+	for i, p := range ranking {
+		coins[i] = models.SearchResult{ID: p.ID, Symbol: strings.ToUpper(p.Symbol), Name: p.Name, MarketCapRank: i + 1, Thumb: p.Image}
+	}
+	return coins
+}
+
+// RunCatalogSync syncs the catalog now and on every interval until the
+// context is cancelled
+func (s *Service) RunCatalogSync(ctx context.Context, markets Markets, interval time.Duration) {
+	ticker := s.clock.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.SyncCatalog(markets); err != nil {
+			s.logger.Warn("coin list sync failed", "error", err)
+		}
+		now := s.clock.Now()
+		s.mu.Lock()
+		s.lastSync = now
+		s.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+	}
+}
+
+// LastCatalogSync returns when RunCatalogSync last ended a sync, whatever its
+// outcome, or the zero time if it has not yet
+func (s *Service) LastCatalogSync() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastSync
+}
+
+// Symbol returns the ticker of a catalog coin, e.g. BTC for bitcoin
+func (s *Service) Symbol(id string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := slices.IndexFunc(s.catalog, func(c models.SearchResult) bool { return c.ID == id })
+	if i < 0 {
+		return "", false
+	}
+	return s.catalog[i].Symbol, true
+}
+
+// searchCatalog returns the catalog coins whose ticker, ID or name starts
+// with the query, exact ticker and ID matches first. The caller must hold the lock.
+func (s *Service) searchCatalog(query string) []models.SearchResult {
+	var exact, prefix []models.SearchResult
+	for _, c := range s.catalog {
+		switch {
+		case c.ExactMatch(query):
+			exact = append(exact, c)
+		case strings.HasPrefix(strings.ToLower(c.Symbol), query) ||
+			strings.HasPrefix(c.ID, query) ||
+			strings.HasPrefix(strings.ToLower(c.Name), query):
+			prefix = append(prefix, c)
+		}
+	}
+	results := append(exact, prefix...)
+	return results[:min(len(results), maxCatalogResults)]
+}
+
+// catalogInfo returns the metadata the catalog has of a coin. The caller must hold the lock.
+func (s *Service) catalogInfo(id string) (models.CoinInfo, bool) {
+	i := slices.IndexFunc(s.catalog, func(c models.SearchResult) bool { return c.ID == id })
+	if i < 0 {
+		return models.CoinInfo{}, false
+	}
+	c := s.catalog[i]
+	return models.CoinInfo{
+		ID:            c.ID,
+		Symbol:        c.Symbol,
+		Name:          c.Name,
+		Categories:    []string{},
+		Images:        models.Images{Thumb: c.Thumb},
+		MarketCapRank: c.MarketCapRank,
+		Explorers:     models.ExplorersFor(c.ID),
+		Stale:         true,
+	}, true
+}
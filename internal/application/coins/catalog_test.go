@@ -0,0 +1,112 @@
+package coins
+
+import (
+	"errors"
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type failingDirectory struct {
+	err      error
+	searches int
+}
+
+func (d *failingDirectory) Search(string) ([]models.SearchResult, error) {
+	d.searches++
+	return nil, d.err
+}
+
+func (d *failingDirectory) CoinInfo(string) (models.CoinInfo, error) {
+	return models.CoinInfo{}, d.err
+}
+
+type stubMarkets []models.CryptoPrice
+
+func (m stubMarkets) GetTopNCryptos(n int, currency models.Currency) ([]models.CryptoPrice, error) {
+	return m, nil
+}
+
+var seed = []models.SearchResult{
+	{ID: "bitcoin", Symbol: "BTC", Name: "Bitcoin", MarketCapRank: 1},
+	{ID: "solana", Symbol: "SOL", Name: "Solana", MarketCapRank: 6},
+	{ID: "wrapped-solana", Symbol: "WSOL", Name: "Wrapped SOL", MarketCapRank: 90},
+	{ID: "solv-protocol", Symbol: "SOLV", Name: "Solv Protocol", MarketCapRank: 300},
+}
+
+func TestService_CatalogBeforeSync(t *testing.T) {
+	dir := &countingDirectory{}
+	s := NewService(dir)
+	s.SetCatalog(seed)
+
+	results, err := s.Search("sol")
+	if err != nil || dir.searches != 0 {
+		t.Fatalf("Expected the catalog to answer without the provider, got %v after %d calls", err, dir.searches)
+	}
+	if len(results) != 2 || results[0].ID != "solana" || results[1].ID != "solv-protocol" {
+		t.Errorf("Expected the exact ticker first, then prefix matches, got %+v", results)
+	}
+
+	// Queries the catalog has nothing for go to the provider
+	s.Search("zzz")
+	if dir.searches != 1 {
+		t.Errorf("Expected one provider search, got %d", dir.searches)
+	}
+}
+
+func TestService_CatalogAfterSync(t *testing.T) {
+	dir := &countingDirectory{}
+	s := NewService(dir)
+	s.SetCatalog(seed)
+	if err := s.SyncCatalog(stubMarkets{{ID: "bitcoin", Symbol: "btc", Name: "Bitcoin"}, {ID: "solana", Symbol: "sol", Name: "Solana", Image: "https://example.com/sol.png"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Search("sol")
+	if dir.searches != 1 {
+		t.Errorf("Expected the provider to answer after the sync, got %d calls", dir.searches)
+	}
+	s.mu.Lock()
+	synced := s.catalog
+	s.mu.Unlock()
+	if len(synced) != 2 || synced[1].Symbol != "SOL" || synced[1].MarketCapRank != 2 || synced[1].Thumb != "https://example.com/sol.png" {
+		t.Errorf("Expected the synced list to replace the seed, got %+v", synced)
+	}
+}
+
+func TestService_CatalogFallback(t *testing.T) {
+	dir := &failingDirectory{err: errors.New("provider unavailable")}
+	s := NewService(dir)
+	s.SetCatalog(seed)
+	if err := s.SyncCatalog(stubMarkets{{ID: "bitcoin", Symbol: "btc", Name: "Bitcoin"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := s.Search("btc")
+	if err != nil || len(results) != 1 || results[0].ID != "bitcoin" || dir.searches != 1 {
+		t.Errorf("Expected the catalog to answer while the provider fails, got %+v, %v", results, err)
+	}
+	if _, err := s.Search("eth"); err == nil {
+		t.Error("Expected the provider error without a catalog match")
+	}
+
+	info, err := s.Info("bitcoin")
+	if err != nil || info.Name != "Bitcoin" || len(info.Explorers) == 0 {
+		t.Errorf("Expected catalog metadata, got %+v, %v", info, err)
+	}
+	dir.err = ErrNotFound
+	if _, err := s.Info("bitcoin"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected unknown coins to stay unknown, got %v", err)
+	}
+}
+
+func TestService_Symbol(t *testing.T) {
+	s := NewService(&failingDirectory{})
+	s.SetCatalog(seed)
+	if symbol, ok := s.Symbol("solana"); !ok || symbol != "SOL" {
+		t.Errorf("Expected SOL, got %q, %v", symbol, ok)
+	}
+	if _, ok := s.Symbol("unknown"); ok {
+		t.Error("Expected no symbol for a coin outside the catalog")
+	}
+}
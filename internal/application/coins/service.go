@@ -0,0 +1,144 @@
+// Package coins resolves search queries and tickers to coin IDs and serves coin metadata
+package coins
+
+import (
+	"errors"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// ErrNotFound is returned when a coin does not exist upstream
+var ErrNotFound = errors.New("coin not found")
+
+// Cache lifetimes; metadata rarely changes while search rankings follow market caps
+const (
+	infoTTL   = 24 * time.Hour
+	searchTTL = 10 * time.Minute
+	// maxSearches bounds the search cache since queries are arbitrary user input
+	maxSearches = 1000
+)
+
+// Directory looks coins up at the data provider
+type Directory interface {
+	Search(query string) ([]models.SearchResult, error)
+	CoinInfo(id string) (models.CoinInfo, error)
+}
+
+type cached[T any] struct {
+	value   T
+	expires time.Time
+}
+
+// Service caches directory lookups and keeps a local catalog of the largest
+// coins to fall back on
+type Service struct {
+	directory Directory
+	clock     clock.Clock
+	logger    *slog.Logger
+
+	mu       sync.Mutex
+	info     map[string]cached[models.CoinInfo]
+	searches map[string]cached[[]models.SearchResult]
+	catalog  []models.SearchResult
+	// synced is set once the catalog was replaced by a live coin list
+	synced bool
+	// lastSync is when RunCatalogSync last ended a sync, whatever its outcome
+	lastSync time.Time
+}
+
+// NewService creates a coin lookup service
+func NewService(directory Directory) *Service {
+	return &Service{
+		directory: directory,
+		clock:     clock.Real,
+		logger:    slog.Default(),
+		info:      make(map[string]cached[models.CoinInfo]),
+		searches:  make(map[string]cached[[]models.SearchResult]),
+	}
+}
+
+// SetClock replaces the system clock, for tests
+func (s *Service) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Search returns the coins matching the query. Coins whose ticker or ID equals
+// the query come first, so "sol" resolves to Solana before tokens merely named after it.
+// The catalog answers searches while the provider fails and, before the first
+// sync, every search it has a match for.
+func (s *Service) Search(query string) ([]models.SearchResult, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil, errors.New("search query cannot be empty")
+	}
+
+	s.mu.Lock()
+	hit, ok := s.searches[query]
+	s.mu.Unlock()
+	if ok && s.clock.Now().Before(hit.expires) {
+		return hit.value, nil
+	}
+
+	s.mu.Lock()
+	local, synced := s.searchCatalog(query), s.synced
+	s.mu.Unlock()
+	// Before the first sync the bundled catalog answers at once
+	if !synced && len(local) > 0 {
+		return local, nil
+	}
+
+	results, err := s.directory.Search(query)
+	if err != nil {
+		if len(local) > 0 {
+			return local, nil
+		}
+		return nil, err
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].ExactMatch(query) && !results[j].ExactMatch(query)
+	})
+
+	s.mu.Lock()
+	if len(s.searches) >= maxSearches {
+		clear(s.searches)
+	}
+	s.searches[query] = cached[[]models.SearchResult]{value: results, expires: s.clock.Now().Add(searchTTL)}
+	s.mu.Unlock()
+	return results, nil
+}
+
+// Info returns the metadata of a coin, or what the catalog has of it while
+// the provider fails
+func (s *Service) Info(id string) (models.CoinInfo, error) {
+	id = strings.ToLower(strings.TrimSpace(id))
+
+	s.mu.Lock()
+	hit, ok := s.info[id]
+	s.mu.Unlock()
+	if ok && s.clock.Now().Before(hit.expires) {
+		return hit.value, nil
+	}
+
+	info, err := s.directory.CoinInfo(id)
+	if err != nil {
+		s.mu.Lock()
+		local, ok := s.catalogInfo(id)
+		s.mu.Unlock()
+		if ok && !errors.Is(err, ErrNotFound) {
+			return local, nil
+		}
+		return models.CoinInfo{}, err
+	}
+	info.Explorers = models.ExplorersFor(info.ID)
+
+	s.mu.Lock()
+	s.info[id] = cached[models.CoinInfo]{value: info, expires: s.clock.Now().Add(infoTTL)}
+	s.mu.Unlock()
+	return info, nil
+}
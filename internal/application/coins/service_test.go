@@ -0,0 +1,71 @@
+package coins
+
+import (
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+type countingDirectory struct {
+	searches, infos int
+}
+
+func (d *countingDirectory) Search(query string) ([]models.SearchResult, error) {
+	d.searches++
+	return []models.SearchResult{
+		{ID: "wrapped-solana", Symbol: "WSOL"},
+		{ID: "solana", Symbol: "SOL"},
+	}, nil
+}
+
+func (d *countingDirectory) CoinInfo(id string) (models.CoinInfo, error) {
+	d.infos++
+	return models.CoinInfo{ID: id}, nil
+}
+
+func TestService_Search(t *testing.T) {
+	dir := &countingDirectory{}
+	s := NewService(dir)
+
+	results, err := s.Search(" Sol ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if results[0].ID != "solana" {
+		t.Errorf("Expected the exact ticker match first, got %+v", results)
+	}
+
+	s.Search("sol")
+	if dir.searches != 1 {
+		t.Errorf("Expected the second search to be cached, got %d upstream calls", dir.searches)
+	}
+
+	if _, err := s.Search("  "); err == nil {
+		t.Error("Expected an error for an empty query")
+	}
+}
+
+func TestService_InfoExpires(t *testing.T) {
+	dir := &countingDirectory{}
+	s := NewService(dir)
+	now := time.Now()
+	clk := clock.NewFake(now)
+	s.SetClock(clk)
+
+	info, _ := s.Info("bitcoin")
+	if len(info.Explorers) == 0 {
+		t.Error("Expected bitcoin metadata to carry explorer links")
+	}
+	s.Info("Bitcoin")
+	if dir.infos != 1 {
+		t.Errorf("Expected cached metadata, got %d upstream calls", dir.infos)
+	}
+
+	clk.Advance(infoTTL + time.Second)
+	s.Info("bitcoin")
+	if dir.infos != 2 {
+		t.Errorf("Expected expired metadata to be refetched, got %d upstream calls", dir.infos)
+	}
+}
@@ -0,0 +1,102 @@
+// Package compare fetches the price of an asset from several providers and
+// exchanges concurrently and measures how far apart they are
+package compare
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// DefaultOutlierPct is how far from the median, in percent, a quote may be
+// before it is flagged as an outlier
+const DefaultOutlierPct = 2.0
+
+// ErrNoQuotes is returned when no source could price the asset
+var ErrNoQuotes = errors.New("no source returned a price")
+
+// Source prices an asset by ticker symbol, e.g. BTC
+type Source interface {
+	Name() string
+	Quote(ctx context.Context, symbol string, currency models.Currency) (models.SourceQuote, error)
+}
+
+// Service compares the quotes of several sources
+type Service struct {
+	sources    []Source
+	timeout    time.Duration
+	outlierPct float64
+}
+
+// NewService creates a comparison service giving every source at most timeout to answer
+func NewService(timeout time.Duration, sources ...Source) *Service {
+	return &Service{sources: sources, timeout: timeout, outlierPct: DefaultOutlierPct}
+}
+
+// Sources returns the names of the compared sources
+func (s *Service) Sources() []string {
+	names := make([]string, len(s.sources))
+	for i, source := range s.sources {
+		names[i] = source.Name()
+	}
+	return names
+}
+
+// Compare queries every source concurrently. Sources that fail or do not
+// answer in time are listed with their error.
+func (s *Service) Compare(ctx context.Context, symbol string, currency models.Currency) (models.PriceComparison, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return models.PriceComparison{}, errors.New("symbol cannot be empty")
+	}
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	type result struct {
+		i     int
+		quote models.SourceQuote
+		err   error
+	}
+	// Buffered so sources that ignore the context do not block once we stop waiting
+	results := make(chan result, len(s.sources))
+	for i, source := range s.sources {
+		go func() {
+			quote, err := source.Quote(ctx, symbol, currency)
+			results <- result{i: i, quote: quote, err: err}
+		}()
+	}
+
+	quotes := make([]models.SourceQuote, len(s.sources))
+	for i, source := range s.sources {
+		quotes[i] = models.SourceQuote{Source: source.Name(), Error: "timed out"}
+	}
+	priced := 0
+collect:
+	for range s.sources {
+		select {
+		case r := <-results:
+			name := s.sources[r.i].Name()
+			if r.err != nil {
+				quotes[r.i] = models.SourceQuote{Source: name, Error: r.err.Error()}
+				continue
+			}
+			r.quote.Source = name
+			quotes[r.i] = r.quote
+			priced++
+		case <-ctx.Done():
+			break collect
+		}
+	}
+
+	comparison := models.NewPriceComparison(symbol, currency, quotes, s.outlierPct)
+	if priced == 0 {
+		return comparison, ErrNoQuotes
+	}
+	return comparison, nil
+}
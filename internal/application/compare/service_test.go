@@ -0,0 +1,71 @@
+package compare
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubSource struct {
+	name  string
+	price int64
+	err   error
+	delay time.Duration
+}
+
+func (s stubSource) Name() string { return s.name }
+
+func (s stubSource) Quote(ctx context.Context, symbol string, currency models.Currency) (models.SourceQuote, error) {
+	if s.delay > 0 {
+		select {
+		case <-time.After(s.delay):
+		case <-ctx.Done():
+			return models.SourceQuote{}, ctx.Err()
+		}
+	}
+	if s.err != nil {
+		return models.SourceQuote{}, s.err
+	}
+	return models.SourceQuote{Pair: symbol + string(currency), Price: decimal.NewFromInt(s.price)}, nil
+}
+
+func TestService_Compare(t *testing.T) {
+	s := NewService(50*time.Millisecond,
+		stubSource{name: "a", price: 100},
+		stubSource{name: "b", price: 102},
+		stubSource{name: "c", err: errors.New("pair not listed")},
+		stubSource{name: "d", price: 1, delay: time.Second},
+	)
+
+	c, err := s.Compare(context.Background(), " btc ", "")
+	if err != nil {
+		t.Fatalf("Compare: %v", err)
+	}
+	if c.Symbol != "BTC" || c.Currency != models.DefaultCurrency || len(c.Quotes) != 4 {
+		t.Fatalf("Unexpected comparison: %+v", c)
+	}
+	if c.Quotes[0].Source != "a" || c.Quotes[0].Pair != "BTCusd" {
+		t.Errorf("Expected quotes in source order, got %+v", c.Quotes[0])
+	}
+	if c.Quotes[2].Error != "pair not listed" || c.Quotes[3].Error == "" {
+		t.Errorf("Expected the failed and slow sources to carry errors, got %+v", c.Quotes[2:])
+	}
+	if !c.Spread.Equal(decimal.NewFromInt(2)) || c.Low != "a" || c.High != "b" {
+		t.Errorf("Unexpected spread: %+v", c)
+	}
+}
+
+func TestService_CompareWithoutQuotes(t *testing.T) {
+	s := NewService(time.Second, stubSource{name: "a", err: errors.New("down")})
+	if _, err := s.Compare(context.Background(), "btc", models.USD); !errors.Is(err, ErrNoQuotes) {
+		t.Errorf("Expected ErrNoQuotes, got %v", err)
+	}
+	if _, err := s.Compare(context.Background(), " ", models.USD); err == nil {
+		t.Error("Expected an error for an empty symbol")
+	}
+}
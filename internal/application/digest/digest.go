@@ -0,0 +1,145 @@
+// Package digest sends a daily summary of prices and triggered alerts to the
+// notifiers that support it, and renders weekly market summaries for chat
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Sender delivers a digest
+type Sender interface {
+	SendDigest(ctx context.Context, digest models.Digest) error
+}
+
+// PriceSource provides the current prices of the tracked coins
+type PriceSource interface {
+	Snapshot() []models.CryptoPrice
+	Currency() models.Currency
+}
+
+// AlertSource provides the recently triggered alerts
+type AlertSource interface {
+	RecentAlerts() []models.Alert
+}
+
+// FlowSource provides the summaries of the spot ETF flows
+type FlowSource interface {
+	Summaries() ([]models.ETFFlowSummary, error)
+}
+
+// Scheduler sends a digest to every sender once a day at a fixed UTC time
+type Scheduler struct {
+	prices  PriceSource
+	alerts  AlertSource
+	flows   FlowSource
+	senders []Sender
+	at      time.Duration
+	logger  *slog.Logger
+	clock   clock.Clock
+
+	mu      sync.Mutex
+	lastRun time.Time
+}
+
+// NewScheduler creates a scheduler sending at the given offset from midnight UTC
+func NewScheduler(prices PriceSource, alerts AlertSource, at time.Duration, senders ...Sender) *Scheduler {
+	return &Scheduler{prices: prices, alerts: alerts, senders: senders, at: at, logger: slog.Default(), clock: clock.Real}
+}
+
+// SetLogger replaces the default logger used to report failed deliveries
+func (s *Scheduler) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
+// SetClock replaces the system clock, for tests
+func (s *Scheduler) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetFlows adds a section on spot ETF flows to the digests
+func (s *Scheduler) SetFlows(flows FlowSource) {
+	s.flows = flows
+}
+
+// Build summarizes the current prices, the alerts of the last 24 hours and,
+// when set, the ETF flows
+func (s *Scheduler) Build() models.Digest {
+	now := s.clock.Now().UTC()
+	digest := models.Digest{
+		Date:     now,
+		Currency: s.prices.Currency(),
+		Prices:   s.prices.Snapshot(),
+	}
+	since := now.Add(-24 * time.Hour)
+	for _, alert := range s.alerts.RecentAlerts() {
+		if alert.TriggeredAt.After(since) {
+			digest.Alerts = append(digest.Alerts, alert)
+		}
+	}
+	if s.flows != nil {
+		flows, err := s.flows.Summaries()
+		if err != nil {
+			s.logger.Warn("digest without ETF flows", "error", err)
+		}
+		digest.ETFFlows = flows
+	}
+	return digest
+}
+
+// Send builds a digest and delivers it to every sender
+func (s *Scheduler) Send(ctx context.Context) error {
+	digest := s.Build()
+	var errs []error
+	for _, sender := range s.senders {
+		if err := sender.SendDigest(ctx, digest); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send digest: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Next returns the first send time after now
+func (s *Scheduler) Next(now time.Time) time.Time {
+	now = now.UTC()
+	next := now.Truncate(24 * time.Hour).Add(s.at)
+	if !next.After(now) {
+		next = next.Add(24 * time.Hour)
+	}
+	return next
+}
+
+// Run sends a digest every day until the context is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		now := s.clock.Now()
+		s.mu.Lock()
+		s.lastRun = now
+		s.mu.Unlock()
+		timer := s.clock.NewTimer(s.Next(now).Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+		if err := s.Send(ctx); err != nil {
+			s.logger.Error("daily digest failed", "error", err)
+		}
+	}
+}
+
+// LastRun returns when Run last scheduled a digest, at least once a day
+// unless a delivery hangs, or the zero time if it has not yet
+func (s *Scheduler) LastRun() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastRun
+}
@@ -0,0 +1,129 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubPrices []models.CryptoPrice
+
+func (s stubPrices) Snapshot() []models.CryptoPrice { return s }
+func (s stubPrices) Currency() models.Currency      { return models.DefaultCurrency }
+
+type stubAlerts []models.Alert
+
+func (s stubAlerts) RecentAlerts() []models.Alert { return s }
+
+type stubFlows []models.ETFFlowSummary
+
+func (s stubFlows) Summaries() ([]models.ETFFlowSummary, error) { return s, nil }
+
+type recordingSender struct {
+	digests []models.Digest
+	err     error
+}
+
+func (r *recordingSender) SendDigest(ctx context.Context, d models.Digest) error {
+	r.digests = append(r.digests, d)
+	return r.err
+}
+
+func TestScheduler_Send(t *testing.T) {
+	now := time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC)
+	prices := stubPrices{{ID: "bitcoin", CurrentPrice: decimal.NewFromInt(60000)}}
+	alerts := stubAlerts{
+		{CryptoID: "bitcoin", Kind: models.AlertPriceAbove, TriggeredAt: now.Add(-time.Hour)},
+		{CryptoID: "ethereum", Kind: models.AlertPriceBelow, TriggeredAt: now.Add(-30 * time.Hour)},
+	}
+	ok, failing := &recordingSender{}, &recordingSender{err: errors.New("down")}
+	s := NewScheduler(prices, alerts, 8*time.Hour, ok, failing)
+	s.SetClock(clock.NewFake(now))
+
+	if err := s.Send(context.Background()); err == nil {
+		t.Error("Expected the failing sender to be reported")
+	}
+	if len(ok.digests) != 1 {
+		t.Fatalf("Expected every sender to receive the digest, got %d", len(ok.digests))
+	}
+	d := ok.digests[0]
+	if len(d.Prices) != 1 || d.Currency != models.DefaultCurrency {
+		t.Errorf("Unexpected prices: %+v", d)
+	}
+	if len(d.Alerts) != 1 || d.Alerts[0].CryptoID != "bitcoin" {
+		t.Errorf("Expected only the alerts of the last day, got %+v", d.Alerts)
+	}
+}
+
+func TestScheduler_BuildWithFlows(t *testing.T) {
+	s := NewScheduler(stubPrices{}, stubAlerts{}, 8*time.Hour)
+	if d := s.Build(); d.ETFFlows != nil {
+		t.Errorf("Expected no ETF section by default, got %+v", d.ETFFlows)
+	}
+
+	s.SetFlows(stubFlows{{Asset: "BTC", Latest: 250e6}})
+	if d := s.Build(); len(d.ETFFlows) != 1 || d.ETFFlows[0].Asset != "BTC" {
+		t.Errorf("Expected the BTC flow summary, got %+v", d.ETFFlows)
+	}
+}
+
+func TestScheduler_Next(t *testing.T) {
+	s := NewScheduler(stubPrices{}, stubAlerts{}, 8*time.Hour)
+	tests := []struct {
+		now, want time.Time
+	}{
+		{time.Date(2024, 3, 2, 6, 0, 0, 0, time.UTC), time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC), time.Date(2024, 3, 3, 8, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 3, 2, 23, 0, 0, 0, time.UTC), time.Date(2024, 3, 3, 8, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := s.Next(tt.now); !got.Equal(tt.want) {
+			t.Errorf("Next(%s) = %s, want %s", tt.now, got, tt.want)
+		}
+	}
+}
+
+// channelSender hands every digest to the test goroutine
+type channelSender chan models.Digest
+
+func (c channelSender) SendDigest(ctx context.Context, d models.Digest) error {
+	c <- d
+	return nil
+}
+
+func TestScheduler_Run(t *testing.T) {
+	sent := make(channelSender)
+	s := NewScheduler(stubPrices{}, stubAlerts{}, 8*time.Hour, sent)
+	clk := clock.NewFake(time.Date(2024, 3, 2, 7, 0, 0, 0, time.UTC))
+	s.SetClock(clk)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	clk.BlockUntil(1)
+	if !s.LastRun().Equal(clk.Now()) {
+		t.Errorf("Expected the scheduling to be reported for the watchdog, got %s", s.LastRun())
+	}
+	clk.Advance(59 * time.Minute)
+	select {
+	case d := <-sent:
+		t.Fatalf("Expected no digest before 08:00, got one at %s", d.Date)
+	default:
+	}
+	clk.Advance(time.Minute)
+	if d := <-sent; !d.Date.Equal(time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the digest at 08:00, got %s", d.Date)
+	}
+
+	clk.BlockUntil(1)
+	clk.Advance(24 * time.Hour)
+	if d := <-sent; !d.Date.Equal(time.Date(2024, 3, 3, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the next digest a day later, got %s", d.Date)
+	}
+}
@@ -0,0 +1,110 @@
+package digest
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/format"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Summary is the weekly market summary posted to chat rooms
+type Summary struct {
+	Date     time.Time
+	Currency models.Currency
+	// Movers are the coins that moved most over 7 days, either way
+	Movers []models.CryptoPrice
+	// Global and Portfolio are left out of the summary when nil
+	Global    *models.GlobalMarket
+	Portfolio *PortfolioDelta
+}
+
+// PortfolioDelta is the value of the holdings now and 7 days ago
+type PortfolioDelta struct {
+	Value    decimal.Decimal
+	Previous decimal.Decimal
+}
+
+// Change returns the change of the value over the week
+func (d PortfolioDelta) Change() decimal.Decimal {
+	return d.Value.Sub(d.Previous)
+}
+
+// ChangePct returns the change of the value over the week in percent, zero
+// when there was no value a week ago
+func (d PortfolioDelta) ChangePct() float64 {
+	if d.Previous.IsZero() {
+		return 0
+	}
+	return d.Change().Div(d.Previous).InexactFloat64() * 100
+}
+
+// Weekly summarizes the market from coins with market data, which must carry
+// their 7d change, keeping the given number of movers
+func Weekly(date time.Time, currency models.Currency, markets []models.CryptoPrice, movers int) Summary {
+	sorted := slices.Clone(markets)
+	slices.SortStableFunc(sorted, func(a, b models.CryptoPrice) int {
+		return cmp.Compare(math.Abs(b.PriceChange7d), math.Abs(a.PriceChange7d))
+	})
+	return Summary{Date: date.UTC(), Currency: currency, Movers: sorted[:min(len(sorted), movers)]}
+}
+
+// WeekDelta values the holdings, quantities by coin ID, at the prices and at
+// the prices of a week ago derived from their 7d change. Holdings without a
+// price are left out.
+func WeekDelta(holdings map[string]decimal.Decimal, prices []models.CryptoPrice) PortfolioDelta {
+	var d PortfolioDelta
+	for _, p := range prices {
+		quantity, ok := holdings[p.ID]
+		if !ok || p.PriceChange7d <= -100 {
+			continue
+		}
+		value := quantity.Mul(p.CurrentPrice)
+		d.Value = d.Value.Add(value)
+		d.Previous = d.Previous.Add(value.Div(decimal.NewFromFloat(1 + p.PriceChange7d/100)))
+	}
+	return d
+}
+
+// Markdown renders the summary for Slack, Discord or Telegram. Tables are
+// fixed-width text in a code block, since none of them renders Markdown
+// tables, and the title uses the single-asterisk bold they all understand.
+func (s Summary) Markdown() string {
+	code := strings.ToUpper(string(s.Currency))
+	var b strings.Builder
+	fmt.Fprintf(&b, "*Weekly market summary, %s (%s)*\n", s.Date.Format(time.DateOnly), code)
+
+	if g := s.Global; g != nil {
+		fmt.Fprintf(&b, "\nMarket cap %s %s (%+.2f%% 24h), volume %s %s\nBTC dominance %.1f%%, ETH dominance %.1f%%\n",
+			format.Compact(g.TotalMarketCap), code, g.MarketCapChange24h,
+			format.Compact(g.TotalVolume), code, g.BTCDominance, g.ETHDominance)
+	}
+
+	if len(s.Movers) > 0 {
+		b.WriteString("\nTop movers (7d)\n```\n")
+		fmt.Fprintf(&b, "%-8s %14s %9s %9s\n", "COIN", "PRICE", "7D", "24H")
+		for _, p := range s.Movers {
+			decimals := format.PriceDecimals(p.CurrentPrice, 2)
+			fmt.Fprintf(&b, "%-8s %14s %+8.2f%% %+8.2f%%\n",
+				strings.ToUpper(cmp.Or(p.Symbol, p.ID)), p.CurrentPrice.StringFixed(int32(decimals)),
+				p.PriceChange7d, p.PriceChange24h)
+		}
+		b.WriteString("```\n")
+	}
+
+	if d := s.Portfolio; d != nil {
+		change := d.Change().StringFixed(2)
+		if !d.Change().IsNegative() {
+			change = "+" + change
+		}
+		fmt.Fprintf(&b, "\nPortfolio %s %s, %s %s (%+.2f%%) over 7 days\n",
+			d.Value.StringFixed(2), code, change, code, d.ChangePct())
+	}
+	return b.String()
+}
@@ -0,0 +1,92 @@
+package digest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestWeekly(t *testing.T) {
+	markets := []models.CryptoPrice{
+		{ID: "bitcoin", Symbol: "btc", CurrentPrice: decimal.NewFromInt(60000), PriceChange7d: 2},
+		{ID: "solana", Symbol: "sol", CurrentPrice: decimal.NewFromInt(150), PriceChange7d: 18.5},
+		{ID: "dogecoin", Symbol: "doge", CurrentPrice: decimal.RequireFromString("0.12345"), PriceChange7d: -12},
+	}
+	s := Weekly(time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC), models.USD, markets, 2)
+	if len(s.Movers) != 2 || s.Movers[0].ID != "solana" || s.Movers[1].ID != "dogecoin" {
+		t.Fatalf("Expected the biggest moves either way, got %+v", s.Movers)
+	}
+	if markets[0].ID != "bitcoin" {
+		t.Error("Expected the markets to be left in order")
+	}
+
+	md := s.Markdown()
+	for _, want := range []string{
+		"*Weekly market summary, 2024-03-02 (USD)*",
+		"SOL              150.00   +18.50%",
+		"DOGE             0.1235   -12.00%",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Expected %q in:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "Market cap") || strings.Contains(md, "Portfolio") {
+		t.Errorf("Expected no global or portfolio section without data:\n%s", md)
+	}
+}
+
+func TestSummary_MarkdownSections(t *testing.T) {
+	s := Summary{
+		Date:     time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+		Currency: models.EUR,
+		Global:   &models.GlobalMarket{TotalMarketCap: 2.41e12, TotalVolume: 98.1e9, MarketCapChange24h: -3.2, BTCDominance: 54.12, ETHDominance: 17.2},
+		Portfolio: &PortfolioDelta{
+			Value:    decimal.NewFromInt(900),
+			Previous: decimal.NewFromInt(1000),
+		},
+	}
+	md := s.Markdown()
+	for _, want := range []string{
+		"Market cap 2.41T EUR (-3.20% 24h), volume 98.10B EUR",
+		"BTC dominance 54.1%, ETH dominance 17.2%",
+		"Portfolio 900.00 EUR, -100.00 EUR (-10.00%) over 7 days",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Expected %q in:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "```") {
+		t.Errorf("Expected no movers table without movers:\n%s", md)
+	}
+}
+
+func TestWeekDelta(t *testing.T) {
+	holdings := map[string]decimal.Decimal{
+		"bitcoin":  decimal.NewFromFloat(0.5),
+		"ethereum": decimal.NewFromInt(2),
+		"unlisted": decimal.NewFromInt(100),
+	}
+	prices := []models.CryptoPrice{
+		{ID: "bitcoin", CurrentPrice: decimal.NewFromInt(66000), PriceChange7d: 10},
+		{ID: "ethereum", CurrentPrice: decimal.NewFromInt(2700), PriceChange7d: -10},
+		{ID: "solana", CurrentPrice: decimal.NewFromInt(150), PriceChange7d: 5},
+	}
+	d := WeekDelta(holdings, prices)
+	if !d.Value.Equal(decimal.NewFromInt(38400)) {
+		t.Errorf("Expected a value of 38400, got %s", d.Value)
+	}
+	// 0.5 * 60000 + 2 * 3000
+	if !d.Previous.Round(2).Equal(decimal.NewFromInt(36000)) {
+		t.Errorf("Expected 36000 a week ago, got %s", d.Previous)
+	}
+	if got := d.ChangePct(); got < 6.66 || got > 6.67 {
+		t.Errorf("Expected a change of 6.67%%, got %.4f", got)
+	}
+	if got := (PortfolioDelta{}).ChangePct(); got != 0 {
+		t.Errorf("Expected no change without a previous value, got %f", got)
+	}
+}
@@ -0,0 +1,163 @@
+// Package etf keeps a series of the daily net flows of the spot BTC and ETH
+// ETFs, fetched from pluggable public data sources
+package etf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// ErrUnknownAsset is returned for assets without a configured source
+var ErrUnknownAsset = errors.New("no ETF flow source for asset")
+
+// DefaultInterval is how often flows are refreshed; sources publish once a day
+const DefaultInterval = 6 * time.Hour
+
+// SummaryDays is the number of days of flows summarized
+const SummaryDays = 30
+
+// Source fetches the recent daily net flows of the spot ETFs of an asset
+type Source interface {
+	Flows(ctx context.Context, asset string) ([]models.ETFFlow, error)
+}
+
+// Repository stores the flow series. Saving a flow replaces the one of the same asset and day.
+type Repository interface {
+	SaveFlows(flows []models.ETFFlow) error
+	// Flows returns the flows of an asset between from and to inclusive, sorted by date
+	Flows(asset string, from, to time.Time) ([]models.ETFFlow, error)
+}
+
+// Service refreshes and serves the ETF flow series
+type Service struct {
+	sources  map[string]Source
+	repo     Repository
+	interval time.Duration
+	logger   *slog.Logger
+	clock    clock.Clock
+
+	mu      sync.Mutex
+	lastRun time.Time
+}
+
+// NewService creates a service with one source per asset, refreshing every interval
+func NewService(repo Repository, interval time.Duration, sources map[string]Source) *Service {
+	normalized := make(map[string]Source, len(sources))
+	for asset, source := range sources {
+		normalized[strings.ToUpper(asset)] = source
+	}
+	return &Service{sources: normalized, repo: repo, interval: interval, logger: slog.Default(), clock: clock.Real}
+}
+
+// SetLogger replaces the default logger used to report refresh failures
+func (s *Service) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
+// SetClock replaces the system clock, for tests
+func (s *Service) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Assets returns the assets with a source, sorted
+func (s *Service) Assets() []string {
+	assets := make([]string, 0, len(s.sources))
+	for asset := range s.sources {
+		assets = append(assets, asset)
+	}
+	slices.Sort(assets)
+	return assets
+}
+
+// Refresh fetches and stores the flows of every asset. Flows the source
+// reports for another asset or without a date are dropped.
+func (s *Service) Refresh(ctx context.Context) error {
+	var errs []error
+	for _, asset := range s.Assets() {
+		flows, err := s.sources[asset].Flows(ctx, asset)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to fetch %s ETF flows: %w", asset, err))
+			continue
+		}
+		valid := flows[:0]
+		for _, f := range flows {
+			f.Asset = asset
+			f.Normalize()
+			if err := f.Validate(); err != nil {
+				s.logger.Warn("dropping invalid ETF flow", "asset", asset, "error", err)
+				continue
+			}
+			valid = append(valid, f)
+		}
+		if err := s.repo.SaveFlows(valid); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run refreshes the flows every interval until the context is cancelled
+func (s *Service) Run(ctx context.Context) {
+	ticker := s.clock.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Refresh(ctx); err != nil {
+			s.logger.Warn("ETF flow refresh failed", "error", err)
+		}
+		now := s.clock.Now()
+		s.mu.Lock()
+		s.lastRun = now
+		s.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+	}
+}
+
+// LastRun returns when Run last ended a refresh, whatever its outcome, or the
+// zero time if it has not yet
+func (s *Service) LastRun() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastRun
+}
+
+// Flows returns the flows of an asset over the last days
+func (s *Service) Flows(asset string, days int) ([]models.ETFFlow, error) {
+	asset = strings.ToUpper(strings.TrimSpace(asset))
+	if _, ok := s.sources[asset]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAsset, asset)
+	}
+	if days <= 0 {
+		return nil, errors.New("days must be positive")
+	}
+	to := s.clock.Now().UTC().Truncate(24 * time.Hour)
+	return s.repo.Flows(asset, to.AddDate(0, 0, 1-days), to)
+}
+
+// Summaries summarizes the recent flows of every asset that has any
+func (s *Service) Summaries() ([]models.ETFFlowSummary, error) {
+	var summaries []models.ETFFlowSummary
+	for _, asset := range s.Assets() {
+		flows, err := s.Flows(asset, SummaryDays)
+		if err != nil {
+			return nil, err
+		}
+		if summary, ok := models.SummarizeETFFlows(asset, flows); ok {
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries, nil
+}
@@ -0,0 +1,83 @@
+package etf
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubSource struct {
+	flows []models.ETFFlow
+	err   error
+}
+
+func (s stubSource) Flows(ctx context.Context, asset string) ([]models.ETFFlow, error) {
+	return s.flows, s.err
+}
+
+type memRepo struct {
+	flows []models.ETFFlow
+}
+
+func (r *memRepo) SaveFlows(flows []models.ETFFlow) error {
+	r.flows = append(r.flows, flows...)
+	return nil
+}
+
+func (r *memRepo) Flows(asset string, from, to time.Time) ([]models.ETFFlow, error) {
+	var flows []models.ETFFlow
+	for _, f := range r.flows {
+		if f.Asset == asset && !f.Date.Before(from) && !f.Date.After(to) {
+			flows = append(flows, f)
+		}
+	}
+	return flows, nil
+}
+
+func day(d int) time.Time {
+	return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestService_Refresh(t *testing.T) {
+	repo := &memRepo{}
+	s := NewService(repo, time.Hour, map[string]Source{
+		"btc": stubSource{flows: []models.ETFFlow{
+			{Date: day(4).Add(20 * time.Hour), NetFlow: 100},
+			{NetFlow: 5},
+			{Date: day(5), NetFlow: -40},
+		}},
+		"eth": stubSource{err: errors.New("unavailable")},
+	})
+	s.SetClock(clock.NewFake(day(5).Add(12 * time.Hour)))
+
+	err := s.Refresh(context.Background())
+	if err == nil {
+		t.Error("Expected the failing ETH source to be reported")
+	}
+	if len(repo.flows) != 2 || repo.flows[0].Asset != "BTC" || !repo.flows[0].Date.Equal(day(4)) {
+		t.Fatalf("Expected the two dated BTC flows to be stored, got %+v", repo.flows)
+	}
+
+	flows, err := s.Flows("btc", 1)
+	if err != nil || len(flows) != 1 || flows[0].NetFlow != -40 {
+		t.Errorf("Expected the flow of the last day, got %+v (%v)", flows, err)
+	}
+	if _, err := s.Flows("sol", 30); !errors.Is(err, ErrUnknownAsset) {
+		t.Errorf("Expected ErrUnknownAsset, got %v", err)
+	}
+	if _, err := s.Flows("btc", 0); err == nil {
+		t.Error("Expected an error for zero days")
+	}
+
+	summaries, err := s.Summaries()
+	if err != nil {
+		t.Fatalf("Summaries: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].Asset != "BTC" || summaries[0].Week != 60 || summaries[0].Streak != -1 {
+		t.Errorf("Unexpected summaries: %+v", summaries)
+	}
+}
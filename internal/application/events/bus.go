@@ -0,0 +1,182 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// SubscriberBuffer is the number of events queued per subscriber before new ones are dropped
+const SubscriberBuffer = 256
+
+// HistorySize is the number of recent events kept for reconnecting clients
+const HistorySize = 1024
+
+// Sequenced is an event numbered in publication order. Sequence numbers start
+// at 1 and only compare between events of the same bus epoch.
+type Sequenced struct {
+	Seq   uint64
+	Event Event
+}
+
+// Publisher publishes events. The poller depends on this rather than on the bus itself.
+type Publisher interface {
+	Publish(event Event)
+}
+
+// subscription delivers either plain events on ch or sequenced ones on seq
+type subscription struct {
+	ch    chan Event
+	seq   chan Sequenced
+	kinds []Kind
+}
+
+func (s *subscription) wants(kind Kind) bool {
+	return len(s.kinds) == 0 || slices.Contains(s.kinds, kind)
+}
+
+// Bus fans events out to independent subscribers. Publish never blocks:
+// a subscriber whose buffer is full misses the event, which is counted in Dropped.
+//
+// Every event is numbered and the last HistorySize are kept, so clients that
+// reconnect can ask for the ones they missed.
+type Bus struct {
+	epoch   string
+	mu      sync.RWMutex
+	subs    map[*subscription]struct{}
+	seq     uint64
+	history []Sequenced
+	dropped atomic.Uint64
+}
+
+// NewBus creates an event bus without subscribers
+func NewBus() *Bus {
+	epoch := make([]byte, 4)
+	rand.Read(epoch)
+	return &Bus{epoch: hex.EncodeToString(epoch), subs: make(map[*subscription]struct{})}
+}
+
+// Epoch identifies this bus, so sequence numbers of a previous process are
+// not mistaken for its own
+func (b *Bus) Epoch() string {
+	return b.epoch
+}
+
+// Publish numbers the event and delivers it to every subscriber interested in its kind
+func (b *Bus) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.seq++
+	sequenced := Sequenced{Seq: b.seq, Event: event}
+	if len(b.history) == HistorySize {
+		b.history = append(b.history[:0], b.history[1:]...)
+	}
+	b.history = append(b.history, sequenced)
+
+	for sub := range b.subs {
+		if !sub.wants(event.Kind()) {
+			continue
+		}
+		var delivered bool
+		if sub.seq != nil {
+			select {
+			case sub.seq <- sequenced:
+				delivered = true
+			default:
+			}
+		} else {
+			select {
+			case sub.ch <- event:
+				delivered = true
+			default:
+			}
+		}
+		if !delivered {
+			b.dropped.Add(1)
+		}
+	}
+}
+
+// Sequence returns the number of the last published event, zero before the first
+func (b *Bus) Sequence() uint64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.seq
+}
+
+// Since returns the events of the given kinds published after seq and up to
+// until, oldest first. It reports false when some of them are no longer in
+// the history or seq is ahead of the bus.
+func (b *Bus) Since(seq, until uint64, kinds ...Kind) ([]Sequenced, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if seq > b.seq {
+		return nil, false
+	}
+	if len(b.history) > 0 && b.history[0].Seq > seq+1 {
+		return nil, false
+	}
+	filter := subscription{kinds: kinds}
+	var missed []Sequenced
+	for _, e := range b.history {
+		if e.Seq > seq && e.Seq <= until && filter.wants(e.Event.Kind()) {
+			missed = append(missed, e)
+		}
+	}
+	return missed, true
+}
+
+// Subscribe returns a channel receiving the events of the given kinds, or every
+// event when no kind is given, and a function that cancels the subscription
+// and closes the channel
+func (b *Bus) Subscribe(kinds ...Kind) (<-chan Event, func()) {
+	sub := &subscription{ch: make(chan Event, SubscriberBuffer), kinds: slices.Clone(kinds)}
+	cancel, _ := b.add(sub)
+	return sub.ch, cancel
+}
+
+// SubscribeSequenced is Subscribe with the sequence number of every event. It
+// also returns the number of the last event published before the subscription,
+// so missed events can be fetched with Since without gaps or duplicates.
+func (b *Bus) SubscribeSequenced(kinds ...Kind) (<-chan Sequenced, uint64, func()) {
+	sub := &subscription{seq: make(chan Sequenced, SubscriberBuffer), kinds: slices.Clone(kinds)}
+	cancel, last := b.add(sub)
+	return sub.seq, last, cancel
+}
+
+// add registers a subscription and returns the function cancelling it with the
+// number of the last event published before it
+func (b *Bus) add(sub *subscription) (func(), uint64) {
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	last := b.seq
+	b.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, sub)
+			b.mu.Unlock()
+			if sub.seq != nil {
+				close(sub.seq)
+			} else {
+				close(sub.ch)
+			}
+		})
+	}, last
+}
+
+// Subscribers returns the number of active subscriptions
+func (b *Bus) Subscribers() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
+
+// Dropped returns how many events were discarded because a subscriber fell behind
+func (b *Bus) Dropped() uint64 {
+	return b.dropped.Load()
+}
@@ -0,0 +1,115 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestBus_FiltersByKind(t *testing.T) {
+	bus := NewBus()
+	all, cancelAll := bus.Subscribe()
+	defer cancelAll()
+	crossings, cancelCrossings := bus.Subscribe(KindThresholdCrossed)
+	defer cancelCrossings()
+
+	bus.Publish(PriceUpdated{At: time.Now()})
+	bus.Publish(ThresholdCrossed{CryptoID: "bitcoin", Threshold: decimal.NewFromInt(70000), Direction: Up})
+
+	if got := (<-all).Kind(); got != KindPriceUpdated {
+		t.Errorf("Expected the price update first, got %s", got)
+	}
+	if got := (<-all).Kind(); got != KindThresholdCrossed {
+		t.Errorf("Expected the crossing second, got %s", got)
+	}
+
+	event := <-crossings
+	crossed, ok := event.(ThresholdCrossed)
+	if !ok || crossed.CryptoID != "bitcoin" || crossed.Direction != Up {
+		t.Errorf("Expected the bitcoin crossing, got %+v", event)
+	}
+	select {
+	case e := <-crossings:
+		t.Errorf("Expected only crossings, got %+v", e)
+	default:
+	}
+}
+
+func TestBus_SlowSubscriberDoesNotBlock(t *testing.T) {
+	bus := NewBus()
+	_, cancel := bus.Subscribe()
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < SubscriberBuffer+10; i++ {
+			bus.Publish(PriceUpdated{})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Publish not to block on a full subscriber")
+	}
+	if bus.Dropped() != 10 {
+		t.Errorf("Expected 10 dropped events, got %d", bus.Dropped())
+	}
+}
+
+func TestBus_CancelClosesChannel(t *testing.T) {
+	bus := NewBus()
+	ch, cancel := bus.Subscribe()
+	cancel()
+	cancel()
+
+	if _, ok := <-ch; ok {
+		t.Error("Expected the channel to be closed")
+	}
+	if bus.Subscribers() != 0 {
+		t.Errorf("Expected no subscribers, got %d", bus.Subscribers())
+	}
+	bus.Publish(PriceUpdated{})
+}
+
+func TestBus_SequencesEvents(t *testing.T) {
+	bus := NewBus()
+	bus.Publish(PriceUpdated{})
+	updates, last, cancel := bus.SubscribeSequenced(KindCoinInactive)
+	defer cancel()
+	if last != 1 {
+		t.Errorf("Expected the subscription to start after event 1, got %d", last)
+	}
+
+	bus.Publish(CoinInactive{CryptoID: "terra-luna"})
+	if got := <-updates; got.Seq != 2 || got.Event.(CoinInactive).CryptoID != "terra-luna" {
+		t.Errorf("Expected the second event, got %+v", got)
+	}
+	if bus.Sequence() != 2 || bus.Epoch() == "" || bus.Epoch() == NewBus().Epoch() {
+		t.Errorf("Unexpected sequence %d or epoch %q", bus.Sequence(), bus.Epoch())
+	}
+}
+
+func TestBus_Since(t *testing.T) {
+	bus := NewBus()
+	for range HistorySize + 2 {
+		bus.Publish(PriceUpdated{})
+	}
+	bus.Publish(CoinReactivated{CryptoID: "bitcoin"})
+
+	missed, ok := bus.Since(HistorySize, bus.Sequence(), KindCoinReactivated)
+	if !ok || len(missed) != 1 || missed[0].Seq != HistorySize+3 {
+		t.Errorf("Expected the reactivation, got %v %+v", ok, missed)
+	}
+	if missed, ok := bus.Since(HistorySize+1, HistorySize+2); !ok || len(missed) != 1 {
+		t.Errorf("Expected events up to until only, got %v %+v", ok, missed)
+	}
+	if _, ok := bus.Since(1, bus.Sequence()); ok {
+		t.Error("Expected events dropped from the history to be reported")
+	}
+	if _, ok := bus.Since(bus.Sequence()+1, bus.Sequence()+1); ok {
+		t.Error("Expected a sequence ahead of the bus to be reported")
+	}
+}
@@ -0,0 +1,141 @@
+// Package events is an in-process event bus that decouples the poller from the
+// subsystems reacting to price changes. Publishers never block on consumers.
+package events
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Kind identifies the type of an event
+type Kind string
+
+const (
+	// KindPriceUpdated is published when a polled price differs from the previous one
+	KindPriceUpdated Kind = "price_updated"
+	// KindThresholdCrossed is published when a price moves through a watched level
+	KindThresholdCrossed Kind = "threshold_crossed"
+	// KindProviderDegraded is published when the price provider keeps failing
+	KindProviderDegraded Kind = "provider_degraded"
+	// KindProviderRecovered is published on the first successful poll after a degradation
+	KindProviderRecovered Kind = "provider_recovered"
+	// KindCoinInactive is published when the provider stops updating a tracked coin
+	KindCoinInactive Kind = "coin_inactive"
+	// KindCoinReactivated is published when an inactive coin is updated again
+	KindCoinReactivated Kind = "coin_reactivated"
+	// KindAlertTriggered is published when the alert engine raises an alert
+	KindAlertTriggered Kind = "alert_triggered"
+	// KindComponentWedged is published when the watchdog restarts a component that stopped making progress
+	KindComponentWedged Kind = "component_wedged"
+)
+
+// ParseKind validates an event kind name
+func ParseKind(name string) (Kind, error) {
+	switch kind := Kind(name); kind {
+	case KindPriceUpdated, KindThresholdCrossed, KindProviderDegraded, KindProviderRecovered,
+		KindCoinInactive, KindCoinReactivated, KindAlertTriggered, KindComponentWedged:
+		return kind, nil
+	}
+	return "", fmt.Errorf("unknown event kind: %q", name)
+}
+
+// Event is implemented by every event type. Consumers use a type switch to read the payload.
+type Event interface {
+	Kind() Kind
+}
+
+// PriceUpdated carries a new price of a coin and the one it replaces.
+// Previous is zero for the first price of a coin.
+type PriceUpdated struct {
+	Price    models.CryptoPrice `json:"price"`
+	Previous decimal.Decimal    `json:"previous"`
+	At       time.Time          `json:"at"`
+}
+
+// Kind implements Event
+func (PriceUpdated) Kind() Kind { return KindPriceUpdated }
+
+// Direction is the side a threshold was crossed towards
+type Direction string
+
+// Crossing directions
+const (
+	Up   Direction = "up"
+	Down Direction = "down"
+)
+
+// ThresholdCrossed reports a price moving through a watched level between two polls
+type ThresholdCrossed struct {
+	CryptoID  string          `json:"crypto_id"`
+	Currency  models.Currency `json:"currency"`
+	Threshold decimal.Decimal `json:"threshold"`
+	Direction Direction       `json:"direction"`
+	Price     decimal.Decimal `json:"price"`
+	At        time.Time       `json:"at"`
+}
+
+// Kind implements Event
+func (ThresholdCrossed) Kind() Kind { return KindThresholdCrossed }
+
+// ProviderDegraded reports that the price provider failed several polls in a row
+type ProviderDegraded struct {
+	Failures int       `json:"failures"`
+	Error    string    `json:"error"`
+	At       time.Time `json:"at"`
+}
+
+// Kind implements Event
+func (ProviderDegraded) Kind() Kind { return KindProviderDegraded }
+
+// ProviderRecovered reports that the price provider answers again after a degradation
+type ProviderRecovered struct {
+	Downtime time.Duration `json:"downtime"`
+	At       time.Time     `json:"at"`
+}
+
+// Kind implements Event
+func (ProviderRecovered) Kind() Kind { return KindProviderRecovered }
+
+// CoinInactive reports a tracked coin the provider has not updated for a while,
+// typically because it was delisted, renamed or migrated. LastUpdate is zero
+// when the provider never returned the coin.
+type CoinInactive struct {
+	CryptoID   string    `json:"crypto_id"`
+	LastUpdate time.Time `json:"last_update"`
+	At         time.Time `json:"at"`
+}
+
+// Kind implements Event
+func (CoinInactive) Kind() Kind { return KindCoinInactive }
+
+// CoinReactivated reports that an inactive coin is updated again
+type CoinReactivated struct {
+	CryptoID string    `json:"crypto_id"`
+	At       time.Time `json:"at"`
+}
+
+// Kind implements Event
+func (CoinReactivated) Kind() Kind { return KindCoinReactivated }
+
+// AlertTriggered carries an alert raised by a rule, a threshold crossing or an inactive coin
+type AlertTriggered struct {
+	Alert models.Alert `json:"alert"`
+}
+
+// Kind implements Event
+func (AlertTriggered) Kind() Kind { return KindAlertTriggered }
+
+// ComponentWedged reports a component that made no progress for Stalled and
+// was restarted
+type ComponentWedged struct {
+	Component string        `json:"component"`
+	Stalled   time.Duration `json:"stalled"`
+	At        time.Time     `json:"at"`
+}
+
+// Kind implements Event
+func (ComponentWedged) Kind() Kind { return KindComponentWedged }
@@ -0,0 +1,135 @@
+// Package format decides how amounts are rendered, so the API, the lite page
+// and the terminal UI show the same number of decimals and the same compact
+// notation, and API clients can follow the hints instead of guessing
+package format
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// MaxPriceDecimals caps the decimals of micro-cap prices
+const MaxPriceDecimals = 8
+
+// significantDigits is the number of significant digits kept for prices below one
+const significantDigits = 4
+
+// style is how a currency is written
+type style struct {
+	symbol   string
+	decimals int
+	// suffix places the symbol after the amount
+	suffix bool
+}
+
+// styles covers the common vs_currencies; others use their uppercase code
+var styles = map[models.Currency]style{
+	models.USD: {symbol: "$", decimals: 2},
+	models.EUR: {symbol: "€", decimals: 2},
+	models.BRL: {symbol: "R$", decimals: 2},
+	models.GBP: {symbol: "£", decimals: 2},
+	models.JPY: {symbol: "¥", decimals: 0},
+	"cad":      {symbol: "CA$", decimals: 2},
+	"aud":      {symbol: "A$", decimals: 2},
+	"chf":      {symbol: "CHF", decimals: 2, suffix: true},
+	"inr":      {symbol: "₹", decimals: 2},
+	"krw":      {symbol: "₩", decimals: 0},
+	"btc":      {symbol: "₿", decimals: 8},
+	"eth":      {symbol: "Ξ", decimals: 6},
+	"sats":     {symbol: "sats", decimals: 0, suffix: true},
+}
+
+func styleOf(currency models.Currency) style {
+	if s, ok := styles[currency]; ok {
+		return s
+	}
+	return style{symbol: strings.ToUpper(string(currency)), decimals: 2, suffix: true}
+}
+
+// Hints tells clients how to render the amounts of one response
+type Hints struct {
+	Currency string `json:"currency"`
+	Symbol   string `json:"symbol"`
+	// SymbolPosition is prefix or suffix
+	SymbolPosition string `json:"symbol_position"`
+	// Decimals is the number of decimals of amounts in the currency
+	Decimals int `json:"decimals"`
+	// PriceDecimals lists the coins whose price needs more decimals than the currency
+	PriceDecimals map[string]int `json:"price_decimals,omitempty"`
+	// Compact holds the compact notation of large amounts, keyed by field name
+	Compact map[string]string `json:"compact,omitempty"`
+}
+
+// NewHints returns the hints of a currency
+func NewHints(currency models.Currency) Hints {
+	s := styleOf(currency)
+	position := "prefix"
+	if s.suffix {
+		position = "suffix"
+	}
+	return Hints{
+		Currency:       strings.ToUpper(string(currency)),
+		Symbol:         s.symbol,
+		SymbolPosition: position,
+		Decimals:       s.decimals,
+	}
+}
+
+// ForPrices returns the hints of a currency with the decimals of each price
+func ForPrices(currency models.Currency, prices []models.CryptoPrice) Hints {
+	h := NewHints(currency)
+	for _, p := range prices {
+		h.AddPrice(p.ID, p.CurrentPrice)
+	}
+	return h
+}
+
+// AddPrice records the decimals of a coin's price when they differ from the currency's
+func (h *Hints) AddPrice(id string, price decimal.Decimal) {
+	decimals := PriceDecimals(price, h.Decimals)
+	if decimals == h.Decimals {
+		return
+	}
+	if h.PriceDecimals == nil {
+		h.PriceDecimals = make(map[string]int)
+	}
+	h.PriceDecimals[id] = decimals
+}
+
+// AddCompact records the compact notation of an amount
+func (h *Hints) AddCompact(field string, v float64) {
+	if h.Compact == nil {
+		h.Compact = make(map[string]string)
+	}
+	h.Compact[field] = Compact(v)
+}
+
+// PriceDecimals returns the decimals to show a price with: the currency's
+// decimals, or enough to keep four significant digits below one
+func PriceDecimals(price decimal.Decimal, currencyDecimals int) int {
+	abs := price.Abs()
+	if abs.IsZero() || abs.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return currencyDecimals
+	}
+	f, _ := abs.Float64()
+	leadingZeros := int(math.Floor(-math.Log10(f)))
+	return max(currencyDecimals, min(leadingZeros+significantDigits, MaxPriceDecimals))
+}
+
+// Compact formats large amounts with a K, M, B or T suffix
+func Compact(v float64) string {
+	for _, unit := range []struct {
+		size   float64
+		suffix string
+	}{{1e12, "T"}, {1e9, "B"}, {1e6, "M"}, {1e3, "K"}} {
+		if math.Abs(v) >= unit.size {
+			return fmt.Sprintf("%.2f%s", v/unit.size, unit.suffix)
+		}
+	}
+	return fmt.Sprintf("%.2f", v)
+}
@@ -0,0 +1,71 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestNewHints(t *testing.T) {
+	tests := []struct {
+		currency models.Currency
+		want     Hints
+	}{
+		{models.USD, Hints{Currency: "USD", Symbol: "$", SymbolPosition: "prefix", Decimals: 2}},
+		{models.JPY, Hints{Currency: "JPY", Symbol: "¥", SymbolPosition: "prefix", Decimals: 0}},
+		{"sats", Hints{Currency: "SATS", Symbol: "sats", SymbolPosition: "suffix", Decimals: 0}},
+		{"xdr", Hints{Currency: "XDR", Symbol: "XDR", SymbolPosition: "suffix", Decimals: 2}},
+	}
+	for _, tt := range tests {
+		got := NewHints(tt.currency)
+		if got.Currency != tt.want.Currency || got.Symbol != tt.want.Symbol ||
+			got.SymbolPosition != tt.want.SymbolPosition || got.Decimals != tt.want.Decimals {
+			t.Errorf("NewHints(%s) = %+v, want %+v", tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestPriceDecimals(t *testing.T) {
+	for price, want := range map[string]int{
+		"65000.12":        2,
+		"1":               2,
+		"0.5":             4,
+		"0.0123":          5,
+		"0.0000123456789": 8,
+		"0.00000000012":   8,
+		"0":               2,
+	} {
+		if got := PriceDecimals(decimal.RequireFromString(price), 2); got != want {
+			t.Errorf("PriceDecimals(%s) = %d, want %d", price, got, want)
+		}
+	}
+	// Currencies with more decimals than needed keep their own
+	if got := PriceDecimals(decimal.RequireFromString("0.5"), 8); got != 8 {
+		t.Errorf("Expected BTC prices to keep 8 decimals, got %d", got)
+	}
+}
+
+func TestForPrices(t *testing.T) {
+	h := ForPrices(models.USD, []models.CryptoPrice{
+		{ID: "bitcoin", CurrentPrice: decimal.NewFromInt(65000)},
+		{ID: "shiba-inu", CurrentPrice: decimal.RequireFromString("0.00001812")},
+	})
+	if len(h.PriceDecimals) != 1 || h.PriceDecimals["shiba-inu"] != 8 {
+		t.Errorf("Expected only the micro-cap to need more decimals, got %v", h.PriceDecimals)
+	}
+
+	h.AddCompact("total_market_cap", 2.4e12)
+	if h.Compact["total_market_cap"] != "2.40T" {
+		t.Errorf("Unexpected compact amounts: %v", h.Compact)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	for v, want := range map[float64]string{999: "999.00", 1500: "1.50K", 2.5e6: "2.50M", -3e9: "-3.00B", 1.2e12: "1.20T"} {
+		if got := Compact(v); got != want {
+			t.Errorf("Compact(%v) = %s, want %s", v, got, want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Package fx provides historical fiat exchange rates for converting ledger amounts
+package fx
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// RateSource returns the exchange rates in effect on a given day
+type RateSource interface {
+	GetHistoricalExchangeRates(date time.Time, base models.Currency, currencies []models.Currency) (models.ExchangeRates, error)
+}
+
+// Service converts amounts between fiat currencies using the rate of the day,
+// caching each day's rates since historical values never change
+type Service struct {
+	source     RateSource
+	base       models.Currency
+	currencies []models.Currency
+
+	mu    sync.Mutex
+	cache map[string]models.ExchangeRates
+}
+
+// NewService creates an exchange-rate service able to convert between the given currencies
+func NewService(source RateSource, currencies ...models.Currency) *Service {
+	return &Service{
+		source:     source,
+		base:       models.USD,
+		currencies: currencies,
+		cache:      make(map[string]models.ExchangeRates),
+	}
+}
+
+// RatesOn returns the exchange rates for the UTC day containing date
+func (s *Service) RatesOn(date time.Time) (models.ExchangeRates, error) {
+	day := date.UTC().Truncate(24 * time.Hour)
+	key := day.Format("2006-01-02")
+
+	s.mu.Lock()
+	rates, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok {
+		return rates, nil
+	}
+
+	rates, err := s.source.GetHistoricalExchangeRates(day, s.base, s.currencies)
+	if err != nil {
+		return models.ExchangeRates{}, fmt.Errorf("failed to fetch exchange rates for %s: %w", key, err)
+	}
+
+	s.mu.Lock()
+	s.cache[key] = rates
+	s.mu.Unlock()
+	return rates, nil
+}
+
+// ConvertAt converts an amount using the exchange rates in effect at the given time
+func (s *Service) ConvertAt(amount decimal.Decimal, from, to models.Currency, at time.Time) (decimal.Decimal, error) {
+	if from == to {
+		return amount, nil
+	}
+	rates, err := s.RatesOn(at)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return rates.ConvertDecimal(amount, from, to)
+}
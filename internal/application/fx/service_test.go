@@ -0,0 +1,61 @@
+package fx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type fakeSource struct {
+	calls int
+	rates map[string]map[models.Currency]float64
+}
+
+func (f *fakeSource) GetHistoricalExchangeRates(date time.Time, base models.Currency, currencies []models.Currency) (models.ExchangeRates, error) {
+	f.calls++
+	return models.NewExchangeRates(base, f.rates[date.Format("2006-01-02")])
+}
+
+func TestService_ConvertAt(t *testing.T) {
+	source := &fakeSource{rates: map[string]map[models.Currency]float64{
+		"2024-01-10": {models.USD: 1, models.BRL: 5},
+		"2024-06-10": {models.USD: 1, models.BRL: 5.5},
+	}}
+	service := NewService(source, models.BRL)
+
+	jan := time.Date(2024, 1, 10, 15, 30, 0, 0, time.UTC)
+	got, err := service.ConvertAt(decimal.NewFromInt(100), models.USD, models.BRL, jan)
+	if err != nil || !got.Equal(decimal.NewFromInt(500)) {
+		t.Errorf("Expected 500 BRL in January, got %s (err %v)", got, err)
+	}
+
+	jun := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+	got, err = service.ConvertAt(decimal.NewFromInt(550), models.BRL, models.USD, jun)
+	if err != nil || !got.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("Expected 100 USD in June, got %s (err %v)", got, err)
+	}
+
+	// A second conversion on the same day must hit the cache
+	if _, err := service.ConvertAt(decimal.NewFromInt(1), models.USD, models.BRL, jan.Add(time.Hour)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if source.calls != 2 {
+		t.Errorf("Expected 2 source calls, got %d", source.calls)
+	}
+}
+
+func TestService_SameCurrencyShortCircuits(t *testing.T) {
+	source := &fakeSource{}
+	service := NewService(source)
+
+	got, err := service.ConvertAt(decimal.NewFromInt(42), models.EUR, models.EUR, time.Now())
+	if err != nil || !got.Equal(decimal.NewFromInt(42)) {
+		t.Errorf("Expected 42, got %s (err %v)", got, err)
+	}
+	if source.calls != 0 {
+		t.Errorf("Expected no source calls, got %d", source.calls)
+	}
+}
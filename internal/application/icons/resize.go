@@ -0,0 +1,57 @@
+package icons
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// Fit scales src to fit a size×size square, preserving its aspect ratio and
+// centering it on a transparent background. Each destination pixel averages
+// the source pixels it covers, which keeps downscaled logos smooth; upscaling
+// repeats the nearest pixel.
+func Fit(src image.Image, size int) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	b := src.Bounds()
+	if b.Empty() {
+		return dst
+	}
+	w, h := size, size
+	if b.Dx() > b.Dy() {
+		h = max(1, size*b.Dy()/b.Dx())
+	} else {
+		w = max(1, size*b.Dx()/b.Dy())
+	}
+	offset := image.Pt((size-w)/2, (size-h)/2)
+
+	rgba := image.NewRGBA(b)
+	draw.Draw(rgba, b, src, b.Min, draw.Src)
+	for y := range h {
+		y0, y1 := span(y, h, b.Dy())
+		for x := range w {
+			x0, x1 := span(x, w, b.Dx())
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := rgba.RGBAAt(b.Min.X+sx, b.Min.Y+sy)
+					r, g, bl, a, n = r+uint64(c.R), g+uint64(c.G), bl+uint64(c.B), a+uint64(c.A), n+1
+				}
+			}
+			// Premultiplied averages are converted back to straight alpha
+			avg := color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(bl / n), A: uint8(a / n)}
+			dst.Set(offset.X+x, offset.Y+y, avg)
+		}
+	}
+	return dst
+}
+
+// span returns the source pixels [lo, hi) covered by destination pixel i of n
+// when scaling a length of from pixels. It covers at least one pixel.
+func span(i, n, from int) (int, int) {
+	lo := i * from / n
+	hi := (i + 1) * from / n
+	if hi <= lo {
+		hi = lo + 1
+	}
+	return lo, hi
+}
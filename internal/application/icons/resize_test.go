@@ -0,0 +1,43 @@
+package icons
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestFit(t *testing.T) {
+	// A 40×20 logo, red on the left half and blue on the right
+	src := image.NewNRGBA(image.Rect(0, 0, 40, 20))
+	for y := range 20 {
+		for x := range 40 {
+			c := color.NRGBA{R: 255, A: 255}
+			if x >= 20 {
+				c = color.NRGBA{B: 255, A: 255}
+			}
+			src.Set(x, y, c)
+		}
+	}
+
+	dst := Fit(src, 16)
+	if dst.Bounds().Dx() != 16 || dst.Bounds().Dy() != 16 {
+		t.Fatalf("Expected a 16×16 icon, got %s", dst.Bounds())
+	}
+	if got := dst.NRGBAAt(2, 8); got != (color.NRGBA{R: 255, A: 255}) {
+		t.Errorf("Expected the left half to stay red, got %v", got)
+	}
+	if got := dst.NRGBAAt(13, 8); got != (color.NRGBA{B: 255, A: 255}) {
+		t.Errorf("Expected the right half to stay blue, got %v", got)
+	}
+	// The wide logo is letterboxed between transparent bands
+	if got := dst.NRGBAAt(8, 1); got.A != 0 {
+		t.Errorf("Expected a transparent band above the logo, got %v", got)
+	}
+	if got := dst.NRGBAAt(8, 4); got.A != 255 {
+		t.Errorf("Expected the logo to be centered, got %v", got)
+	}
+
+	if up := Fit(image.NewNRGBA(image.Rect(0, 0, 4, 4)), 32); up.Bounds().Dx() != 32 {
+		t.Errorf("Expected small logos to be scaled up, got %s", up.Bounds())
+	}
+}
@@ -0,0 +1,157 @@
+// Package icons serves coin logos from a local cache, resized to the
+// requested size, so the interfaces never hotlink to the provider's CDN and
+// keep their icons while offline
+package icons
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
+	"sync"
+
+	// Logos are PNG, JPEG or GIF
+	_ "image/gif"
+	_ "image/jpeg"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Icon sizes in pixels
+const (
+	MinSize     = 16
+	MaxSize     = 256
+	DefaultSize = 64
+)
+
+// maxResized bounds the resized icons kept in memory
+const maxResized = 1000
+
+// maxSourcePixels bounds the dimensions of a logo, checked before decoding
+// since a few kilobytes of compressed image can claim gigabytes of pixels
+const maxSourcePixels = 2048 * 2048
+
+// ErrNoIcon is returned for coins the provider has no logo for
+var ErrNoIcon = errors.New("coin has no icon")
+
+// Directory returns the metadata, and so the logo URLs, of a coin
+type Directory interface {
+	Info(id string) (models.CoinInfo, error)
+}
+
+// Downloader fetches the image at a URL
+type Downloader interface {
+	Download(url string) ([]byte, error)
+}
+
+// Repository stores the original logos, keyed by coin ID
+type Repository interface {
+	Icon(cryptoID string) ([]byte, bool, error)
+	SaveIcon(cryptoID string, data []byte) error
+}
+
+type resizedKey struct {
+	cryptoID string
+	size     int
+}
+
+// Service downloads each logo once and serves it as PNG at any size
+type Service struct {
+	directory  Directory
+	downloader Downloader
+	repo       Repository
+
+	mu      sync.Mutex
+	resized map[resizedKey][]byte
+}
+
+// NewService creates an icon service
+func NewService(directory Directory, downloader Downloader, repo Repository) *Service {
+	return &Service{directory: directory, downloader: downloader, repo: repo, resized: make(map[resizedKey][]byte)}
+}
+
+// Icon returns the logo of a coin as a size×size PNG
+func (s *Service) Icon(cryptoID string, size int) ([]byte, error) {
+	cryptoID = strings.ToLower(strings.TrimSpace(cryptoID))
+	if cryptoID == "" {
+		return nil, errors.New("crypto ID cannot be empty")
+	}
+	if size < MinSize || size > MaxSize {
+		return nil, fmt.Errorf("size must be between %d and %d, got %d", MinSize, MaxSize, size)
+	}
+	key := resizedKey{cryptoID, size}
+	s.mu.Lock()
+	icon, ok := s.resized[key]
+	s.mu.Unlock()
+	if ok {
+		return icon, nil
+	}
+
+	original, err := s.original(cryptoID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDimensions(cryptoID, original); err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(original))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the %s icon: %w", cryptoID, err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, Fit(img, size)); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if len(s.resized) >= maxResized {
+		clear(s.resized)
+	}
+	s.resized[key] = buf.Bytes()
+	s.mu.Unlock()
+	return buf.Bytes(), nil
+}
+
+// original returns the stored logo of a coin, downloading the largest one
+// the provider has on first use
+func (s *Service) original(cryptoID string) ([]byte, error) {
+	data, ok, err := s.repo.Icon(cryptoID)
+	if err != nil || ok {
+		return data, err
+	}
+	info, err := s.directory.Info(cryptoID)
+	if err != nil {
+		return nil, err
+	}
+	url := cmp.Or(info.Images.Large, info.Images.Small, info.Images.Thumb)
+	if url == "" {
+		return nil, ErrNoIcon
+	}
+	data, err = s.downloader.Download(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download the %s icon: %w", cryptoID, err)
+	}
+	// Only images that can be decoded are stored, so a bad download is retried
+	if err := checkDimensions(cryptoID, data); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveIcon(cryptoID, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// checkDimensions reads the image header and rejects images too large to decode
+func checkDimensions(cryptoID string, data []byte) error {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode the %s icon: %w", cryptoID, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxSourcePixels {
+		return fmt.Errorf("the %s icon is %d×%d, more than %d pixels", cryptoID, cfg.Width, cfg.Height, maxSourcePixels)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package icons
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubDirectory map[string]models.Images
+
+func (d stubDirectory) Info(id string) (models.CoinInfo, error) {
+	images, ok := d[id]
+	if !ok {
+		return models.CoinInfo{}, errors.New("coin not found")
+	}
+	return models.CoinInfo{ID: id, Images: images}, nil
+}
+
+type stubDownloader struct {
+	calls []string
+	data  []byte
+}
+
+func (d *stubDownloader) Download(url string) ([]byte, error) {
+	d.calls = append(d.calls, url)
+	return d.data, nil
+}
+
+type mapRepo map[string][]byte
+
+func (r mapRepo) Icon(id string) ([]byte, bool, error) {
+	data, ok := r[id]
+	return data, ok, nil
+}
+
+func (r mapRepo) SaveIcon(id string, data []byte) error {
+	r[id] = data
+	return nil
+}
+
+func encodePNG(size int) []byte {
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, size, size)))
+	return buf.Bytes()
+}
+
+func TestService_Icon(t *testing.T) {
+	directory := stubDirectory{
+		"bitcoin": {Thumb: "https://img/thumb.png", Large: "https://img/large.png"},
+		"tether":  {Thumb: "https://img/usdt.png"},
+		"unknown": {},
+	}
+	downloader := &stubDownloader{data: encodePNG(200)}
+	repo := mapRepo{}
+	s := NewService(directory, downloader, repo)
+
+	icon, err := s.Icon("Bitcoin", 32)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg, err := png.DecodeConfig(bytes.NewReader(icon)); err != nil || cfg.Width != 32 || cfg.Height != 32 {
+		t.Errorf("Expected a 32×32 PNG, got %+v (%v)", cfg, err)
+	}
+	if len(downloader.calls) != 1 || downloader.calls[0] != "https://img/large.png" {
+		t.Errorf("Expected the largest logo to be downloaded, got %v", downloader.calls)
+	}
+
+	// Other sizes are resized from the stored original
+	s.Icon("bitcoin", 64)
+	s.Icon("bitcoin", 64)
+	if len(downloader.calls) != 1 || repo["bitcoin"] == nil {
+		t.Errorf("Expected the logo to be downloaded once, got %v", downloader.calls)
+	}
+
+	s.Icon("tether", DefaultSize)
+	if downloader.calls[1] != "https://img/usdt.png" {
+		t.Errorf("Expected the smaller logos as fallback, got %v", downloader.calls)
+	}
+	if _, err := s.Icon("unknown", DefaultSize); !errors.Is(err, ErrNoIcon) {
+		t.Errorf("Expected ErrNoIcon, got %v", err)
+	}
+	if _, err := s.Icon("bitcoin", MaxSize+1); err == nil {
+		t.Error("Expected an oversized icon to be rejected")
+	}
+
+	// Downloads that are not images are not stored
+	downloader.data = []byte("<html>")
+	directory["broken"] = models.Images{Large: "https://img/broken.png"}
+	if _, err := s.Icon("broken", DefaultSize); err == nil || repo["broken"] != nil {
+		t.Errorf("Expected the broken download to be rejected and not stored, got %v", err)
+	}
+
+	// Nor are images too large to decode safely
+	var huge bytes.Buffer
+	png.Encode(&huge, image.NewGray(image.Rect(0, 0, maxSourcePixels/1000, 1001)))
+	downloader.data = huge.Bytes()
+	directory["huge"] = models.Images{Large: "https://img/huge.png"}
+	if _, err := s.Icon("huge", DefaultSize); err == nil || repo["huge"] != nil {
+		t.Errorf("Expected the oversized image to be rejected and not stored, got %v", err)
+	}
+	repo["stored"] = huge.Bytes()
+	if _, err := s.Icon("stored", DefaultSize); err == nil {
+		t.Error("Expected an oversized stored image to be rejected before decoding")
+	}
+}
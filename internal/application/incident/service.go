@@ -0,0 +1,182 @@
+// Package incident records provider outages and data-quality incidents so
+// charts and the status page can show when prices were unreliable
+package incident
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/application/events"
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// ErrNotFound is returned when an incident does not exist
+var ErrNotFound = errors.New("incident not found")
+
+// Repository persists incidents
+type Repository interface {
+	Save(i models.Incident) (models.Incident, error)
+	Get(id string) (models.Incident, error)
+	List() ([]models.Incident, error)
+}
+
+// Service records and queries incidents
+type Service struct {
+	repo   Repository
+	logger *slog.Logger
+	clock  clock.Clock
+
+	mu       sync.Mutex
+	consumed time.Time
+}
+
+// NewService creates an incident service
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo, logger: slog.Default(), clock: clock.Real}
+}
+
+// SetLogger replaces the default logger used by Consume
+func (s *Service) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
+// SetClock replaces the system clock, for tests
+func (s *Service) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Record validates and stores a new incident, e.g. a data-quality annotation
+func (s *Service) Record(i models.Incident) (models.Incident, error) {
+	i.ID = ""
+	i.Normalize()
+	if i.Started.IsZero() {
+		i.Started = s.clock.Now().UTC()
+	}
+	if err := i.Validate(); err != nil {
+		return models.Incident{}, err
+	}
+	return s.repo.Save(i)
+}
+
+// Resolve ends an ongoing incident at the given time, or now when it is zero
+func (s *Service) Resolve(id string, at time.Time) (models.Incident, error) {
+	i, err := s.repo.Get(id)
+	if err != nil {
+		return models.Incident{}, err
+	}
+	if !i.Ongoing() {
+		return i, nil
+	}
+	if at.IsZero() {
+		at = s.clock.Now().UTC()
+	}
+	i.Resolved = &at
+	if err := i.Validate(); err != nil {
+		return models.Incident{}, err
+	}
+	return s.repo.Save(i)
+}
+
+// Incidents returns the incidents affecting the coin during [from, to),
+// ordered by start. An empty coin matches every incident and a zero to leaves
+// the range open.
+func (s *Service) Incidents(cryptoID string, from, to time.Time) ([]models.Incident, error) {
+	all, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	var incidents []models.Incident
+	for _, i := range all {
+		if i.Affects(cryptoID) && i.Overlaps(from, to) {
+			incidents = append(incidents, i)
+		}
+	}
+	sort.SliceStable(incidents, func(a, b int) bool { return incidents[a].Started.Before(incidents[b].Started) })
+	return incidents, nil
+}
+
+// Recent returns the latest n incidents, most recent first
+func (s *Service) Recent(n int) ([]models.Incident, error) {
+	incidents, err := s.Incidents("", time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	recent := make([]models.Incident, 0, min(n, len(incidents)))
+	for i := len(incidents) - 1; i >= 0 && len(recent) < n; i-- {
+		recent = append(recent, incidents[i])
+	}
+	return recent, nil
+}
+
+// Consume opens an outage on every provider degradation and resolves it on
+// recovery, until the channel is closed or the context is cancelled
+func (s *Service) Consume(ctx context.Context, updates <-chan events.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-updates:
+			if !ok {
+				return
+			}
+			if err := s.handleEvent(event); err != nil {
+				s.logger.Error("failed to record incident", "error", err)
+			}
+			now := s.clock.Now()
+			s.mu.Lock()
+			s.consumed = now
+			s.mu.Unlock()
+		}
+	}
+}
+
+// Consumed returns when Consume last finished handling an event, or the zero
+// time if it has not yet
+func (s *Service) Consumed() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.consumed
+}
+
+func (s *Service) handleEvent(event events.Event) error {
+	switch ev := event.(type) {
+	case events.ProviderDegraded:
+		// A repeated degradation only updates the open outage
+		outage, ok, err := s.ongoingOutage()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			outage = models.Incident{Kind: models.IncidentOutage, Started: ev.At}
+		}
+		outage.Description, outage.Failures = ev.Error, ev.Failures
+		_, err = s.repo.Save(outage)
+		return err
+	case events.ProviderRecovered:
+		outage, ok, err := s.ongoingOutage()
+		if err != nil || !ok {
+			return err
+		}
+		_, err = s.Resolve(outage.ID, ev.At)
+		return err
+	}
+	return nil
+}
+
+func (s *Service) ongoingOutage() (models.Incident, bool, error) {
+	all, err := s.repo.List()
+	if err != nil {
+		return models.Incident{}, false, err
+	}
+	for _, i := range all {
+		if i.Kind == models.IncidentOutage && i.Ongoing() {
+			return i, true, nil
+		}
+	}
+	return models.Incident{}, false, nil
+}
@@ -0,0 +1,133 @@
+package incident
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/events"
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// memRepo is a minimal in-memory Repository
+type memRepo struct {
+	mu        sync.Mutex
+	incidents []models.Incident
+}
+
+func (r *memRepo) Save(i models.Incident) (models.Incident, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i.ID == "" {
+		i.ID = string(rune('a' + len(r.incidents)))
+		r.incidents = append(r.incidents, i)
+		return i, nil
+	}
+	for n := range r.incidents {
+		if r.incidents[n].ID == i.ID {
+			r.incidents[n] = i
+		}
+	}
+	return i, nil
+}
+
+func (r *memRepo) Get(id string) (models.Incident, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, i := range r.incidents {
+		if i.ID == id {
+			return i, nil
+		}
+	}
+	return models.Incident{}, ErrNotFound
+}
+
+func (r *memRepo) List() ([]models.Incident, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.Incident(nil), r.incidents...), nil
+}
+
+func TestService_RecordAndResolve(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s := NewService(&memRepo{})
+	s.SetClock(clock.NewFake(now))
+
+	recorded, err := s.Record(models.Incident{CryptoID: "Bitcoin", Description: "Exchange printed 0"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if recorded.Kind != models.IncidentDataQuality || !recorded.Started.Equal(now) || recorded.CryptoID != "bitcoin" {
+		t.Errorf("Expected a data-quality incident starting now, got %+v", recorded)
+	}
+	if _, err := s.Record(models.Incident{}); err == nil {
+		t.Error("Expected an incident without description to be rejected")
+	}
+
+	resolved, err := s.Resolve(recorded.ID, now.Add(time.Hour))
+	if err != nil || resolved.Ongoing() || resolved.Duration(now) != time.Hour {
+		t.Errorf("Expected a resolved incident of 1h, got %+v (%v)", resolved, err)
+	}
+	if _, err := s.Resolve("missing", time.Time{}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if _, err := s.Resolve(recorded.ID, now.Add(2*time.Hour)); err != nil {
+		t.Errorf("Expected resolving twice to keep the first end, got %v", err)
+	}
+}
+
+func TestService_Incidents(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	s := NewService(&memRepo{})
+	end := start.Add(time.Hour)
+	s.Record(models.Incident{Kind: models.IncidentOutage, Description: "timeout", Started: start, Resolved: &end})
+	s.Record(models.Incident{CryptoID: "ethereum", Description: "bad data", Started: start.Add(30 * time.Minute)})
+	s.Record(models.Incident{CryptoID: "bitcoin", Description: "bad data", Started: start.Add(5 * time.Hour)})
+
+	got, _ := s.Incidents("bitcoin", start, start.Add(2*time.Hour))
+	if len(got) != 1 || got[0].Kind != models.IncidentOutage {
+		t.Errorf("Expected only the coin-wide outage in range, got %+v", got)
+	}
+	got, _ = s.Incidents("bitcoin", start, time.Time{})
+	if len(got) != 2 {
+		t.Errorf("Expected an open range to include the later incident, got %+v", got)
+	}
+
+	recent, _ := s.Recent(2)
+	if len(recent) != 2 || recent[0].CryptoID != "bitcoin" || recent[1].CryptoID != "ethereum" {
+		t.Errorf("Expected the latest incidents first, got %+v", recent)
+	}
+}
+
+func TestService_ConsumesProviderHealth(t *testing.T) {
+	s := NewService(&memRepo{})
+	bus := events.NewBus()
+	updates, cancel := bus.Subscribe(events.KindProviderDegraded, events.KindProviderRecovered)
+	done := make(chan struct{})
+	go func() {
+		s.Consume(context.Background(), updates)
+		close(done)
+	}()
+
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	bus.Publish(events.ProviderDegraded{Failures: 3, Error: "timeout", At: at})
+	bus.Publish(events.ProviderDegraded{Failures: 3, Error: "rate limited", At: at.Add(time.Minute)})
+	bus.Publish(events.ProviderRecovered{Downtime: 10 * time.Minute, At: at.Add(10 * time.Minute)})
+	cancel()
+	<-done
+
+	incidents, _ := s.Incidents("", time.Time{}, time.Time{})
+	if len(incidents) != 1 {
+		t.Fatalf("Expected a single outage, got %+v", incidents)
+	}
+	outage := incidents[0]
+	if outage.Kind != models.IncidentOutage || outage.Description != "rate limited" || outage.Ongoing() || outage.Duration(at) != 10*time.Minute {
+		t.Errorf("Unexpected outage: %+v", outage)
+	}
+	if s.Consumed().IsZero() {
+		t.Error("Expected the consumption to be reported for the watchdog")
+	}
+}
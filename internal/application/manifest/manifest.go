@@ -0,0 +1,137 @@
+// Package manifest exports the watchlists, alert rules and dashboard settings
+// of a session as a declarative YAML manifest and reconciles a session with
+// one, so setups can live in version control
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Version identifies the manifest format
+const Version = "dashboard/v1"
+
+// Manifest declares the state of a session. Sections left out are not managed,
+// so a manifest listing only watchlists never touches alert rules; an empty
+// section is managed and holds nothing.
+type Manifest struct {
+	Version    string      `yaml:"version"`
+	Providers  *Providers  `yaml:"providers,omitempty"`
+	Theme      *Theme      `yaml:"theme,omitempty"`
+	Watchlists []Watchlist `yaml:"watchlists"`
+	AlertRules []AlertRule `yaml:"alert_rules"`
+	Charts     []Chart     `yaml:"charts"`
+}
+
+// Providers declares the data providers the server is expected to run with.
+// They come from the server configuration, so differences are reported as
+// drift and never applied.
+type Providers struct {
+	// Compare lists the exchanges prices are compared across
+	Compare []string `yaml:"compare"`
+	// ETF lists the assets whose spot ETF flows are tracked
+	ETF []string `yaml:"etf"`
+}
+
+// Theme declares the appearance and privacy mode
+type Theme struct {
+	Mode    models.ThemeMode `yaml:"mode"`
+	Accent  string           `yaml:"accent,omitempty"`
+	Up      string           `yaml:"up,omitempty"`
+	Down    string           `yaml:"down,omitempty"`
+	Privacy bool             `yaml:"privacy,omitempty"`
+}
+
+// Watchlist declares a watchlist, identified by its name
+type Watchlist struct {
+	Name     string   `yaml:"name"`
+	Coins    []string `yaml:"coins,omitempty"`
+	Universe string   `yaml:"universe,omitempty"`
+	Archived bool     `yaml:"archived,omitempty"`
+}
+
+// AlertRule declares an alert rule, identified by all of its settings
+type AlertRule struct {
+	CryptoID   string               `yaml:"crypto_id"`
+	Kind       models.AlertKind     `yaml:"kind"`
+	Threshold  float64              `yaml:"threshold,omitempty"`
+	FastPeriod int                  `yaml:"fast_period,omitempty"`
+	SlowPeriod int                  `yaml:"slow_period,omitempty"`
+	Period     int                  `yaml:"period,omitempty"`
+	Severity   models.AlertSeverity `yaml:"severity,omitempty"`
+}
+
+// Chart declares a saved chart, identified by its name
+type Chart struct {
+	Name       string            `yaml:"name"`
+	CryptoID   string            `yaml:"crypto_id"`
+	Range      models.ChartRange `yaml:"range,omitempty"`
+	Scale      models.ChartScale `yaml:"scale,omitempty"`
+	Indicators map[string]int    `yaml:"indicators,omitempty"`
+}
+
+// Parse reads a manifest, rejecting unknown fields so typos do not go unnoticed
+func Parse(r io.Reader) (Manifest, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	var m Manifest
+	if err := dec.Decode(&m); err != nil {
+		if errors.Is(err, io.EOF) {
+			return Manifest{}, errors.New("manifest is empty")
+		}
+		return Manifest{}, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if m.Version != Version {
+		return Manifest{}, fmt.Errorf("unsupported manifest version %q, expected %q", m.Version, Version)
+	}
+	return m, nil
+}
+
+// Marshal writes a manifest as YAML
+func Marshal(m Manifest) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(m); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), enc.Close()
+}
+
+func (t Theme) model(owner string) models.Theme {
+	theme := models.Theme{Owner: owner, Mode: t.Mode, Accent: t.Accent, Up: t.Up, Down: t.Down, Privacy: t.Privacy}
+	theme.Normalize()
+	return theme
+}
+
+func (w Watchlist) model(owner string) models.Watchlist {
+	list := models.Watchlist{Owner: owner, Name: w.Name, Coins: slices.Clone(w.Coins), Universe: w.Universe, Archived: w.Archived}
+	list.Normalize()
+	return list
+}
+
+func (r AlertRule) model() models.AlertRule {
+	return models.AlertRule{
+		CryptoID:   r.CryptoID,
+		Kind:       r.Kind,
+		Threshold:  r.Threshold,
+		FastPeriod: r.FastPeriod,
+		SlowPeriod: r.SlowPeriod,
+		Period:     r.Period,
+		Severity:   r.Severity,
+	}.WithDefaults()
+}
+
+func (c Chart) model(owner string) models.ChartConfig {
+	chart := models.ChartConfig{Owner: owner, Name: c.Name, CryptoID: c.CryptoID, Range: c.Range, Scale: c.Scale, Indicators: maps.Clone(c.Indicators)}
+	chart.Normalize()
+	return chart
+}
@@ -0,0 +1,63 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	m, err := Parse(strings.NewReader(`
+version: dashboard/v1
+watchlists:
+  - name: Majors
+    coins: [bitcoin, ethereum]
+alert_rules:
+  - crypto_id: bitcoin
+    kind: price_above
+    threshold: 70000
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Watchlists) != 1 || m.Watchlists[0].Coins[1] != "ethereum" || m.AlertRules[0].Threshold != 70000 {
+		t.Errorf("Unexpected manifest: %+v", m)
+	}
+	if m.Charts != nil || m.Theme != nil {
+		t.Errorf("Expected sections left out to stay unmanaged, got %+v", m)
+	}
+
+	for name, doc := range map[string]string{
+		"empty":   "",
+		"version": "version: dashboard/v2\n",
+		"typo":    "version: dashboard/v1\nwatchlist: []\n",
+	} {
+		if _, err := Parse(strings.NewReader(doc)); err == nil {
+			t.Errorf("Expected the %s manifest to be rejected", name)
+		}
+	}
+}
+
+func TestMarshal_RoundTrip(t *testing.T) {
+	in := Manifest{
+		Version:    Version,
+		Theme:      &Theme{Mode: "light", Privacy: true},
+		Watchlists: []Watchlist{{Name: "Top", Universe: "top-100"}},
+		AlertRules: []AlertRule{},
+		Charts:     []Chart{{Name: "cycle", CryptoID: "bitcoin", Range: "max", Indicators: map[string]int{"sma": 200}}},
+	}
+	data, err := Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "alert_rules: []") {
+		t.Errorf("Expected empty sections to be written so they stay managed:\n%s", data)
+	}
+
+	out, err := Parse(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !out.Theme.Privacy || out.Watchlists[0].Universe != "top-100" || out.AlertRules == nil || out.Charts[0].Indicators["sma"] != 200 {
+		t.Errorf("Expected the manifest to survive a round trip, got %+v", out)
+	}
+}
@@ -0,0 +1,665 @@
+package manifest
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"crypto-dashboard/internal/application/watchlist"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Watchlists manages the watchlists of a session
+type Watchlists interface {
+	List(owner string) ([]models.Watchlist, error)
+	Create(w models.Watchlist) (models.Watchlist, error)
+	Update(id string, u watchlist.Update) (models.Watchlist, error)
+	Delete(id string) error
+}
+
+// AlertRules manages the alert rules, which every session shares
+type AlertRules interface {
+	Rules() ([]models.AlertRule, error)
+	CreateRule(rule models.AlertRule) (models.AlertRule, error)
+	DeleteRule(id string) error
+}
+
+// Themes manages the appearance of a session
+type Themes interface {
+	Get(owner string) (models.Theme, error)
+	Set(owner string, t models.Theme) (models.Theme, error)
+	SetPrivacy(owner string, enabled bool) (models.Theme, error)
+}
+
+// Charts manages the saved charts of a session
+type Charts interface {
+	List(owner string) ([]models.ChartConfig, error)
+	Create(c models.ChartConfig) (models.ChartConfig, error)
+	Update(id string, c models.ChartConfig) (models.ChartConfig, error)
+	Delete(id string) error
+}
+
+// Action is what reconciling does to one resource
+type Action string
+
+// Reconcile actions
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// FieldDiff is one setting an update changes
+type FieldDiff struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// Change is one step of a plan
+type Change struct {
+	Action Action `json:"action"`
+	// Kind is theme, watchlist, alert_rule, chart or providers
+	Kind string      `json:"kind"`
+	Name string      `json:"name"`
+	Diff []FieldDiff `json:"diff,omitempty"`
+	// Drifted is set when the resource was changed, created or deleted outside
+	// the manifest since the session last applied one
+	Drifted bool `json:"drifted,omitempty"`
+
+	apply func() error
+}
+
+// Plan lists the changes that bring a session to its manifest, in the order
+// they are applied. Drift lists differences applying cannot fix since they
+// come from the server configuration.
+type Plan struct {
+	Changes   []Change `json:"changes"`
+	Drift     []Change `json:"drift,omitempty"`
+	Unchanged int      `json:"unchanged"`
+
+	applied func()
+	// adopted are the IDs of alert rules no session owned that the manifest
+	// declares; they become the session's once the plan is applied
+	adopted []string
+}
+
+// Service exports and reconciles manifests. It remembers the last manifest
+// each session applied to tell drift apart from edits of the manifest.
+//
+// Alert rules are shared by every session, so the service also remembers
+// which session's manifests created or adopted each rule. A session only
+// exports, matches and prunes its own rules.
+type Service struct {
+	watchlists Watchlists
+	rules      AlertRules
+	themes     Themes
+	charts     Charts
+	providers  Providers
+
+	mu      sync.Mutex
+	applied map[string]Manifest
+	// ruleOwners maps the ID of every alert rule a manifest manages to its session
+	ruleOwners map[string]string
+}
+
+// NewService creates a manifest service
+func NewService(watchlists Watchlists, rules AlertRules, themes Themes, charts Charts) *Service {
+	return &Service{
+		watchlists: watchlists,
+		rules:      rules,
+		themes:     themes,
+		charts:     charts,
+		applied:    make(map[string]Manifest),
+		ruleOwners: make(map[string]string),
+	}
+}
+
+// SetProviders records the data providers the server runs with, so they are
+// exported and manifests declaring others report drift
+func (s *Service) SetProviders(p Providers) {
+	s.providers = Providers{Compare: slices.Clone(p.Compare), ETF: slices.Clone(p.ETF)}
+}
+
+// Export describes the current state of a session as a manifest. Watchlists
+// following a universe are exported without their resolved coins. Alert rules
+// of other sessions' manifests are left out.
+func (s *Service) Export(owner string) (Manifest, error) {
+	m := Manifest{Version: Version, Watchlists: []Watchlist{}, AlertRules: []AlertRule{}, Charts: []Chart{}}
+	providers := s.runtimeProviders()
+	m.Providers = &providers
+
+	t, err := s.themes.Get(owner)
+	if err != nil {
+		return Manifest{}, err
+	}
+	m.Theme = &Theme{Mode: t.Mode, Accent: t.Accent, Up: t.Up, Down: t.Down, Privacy: t.Privacy}
+
+	lists, err := s.watchlists.List(owner)
+	if err != nil {
+		return Manifest{}, err
+	}
+	for _, w := range lists {
+		spec := Watchlist{Name: w.Name, Universe: w.Universe, Archived: w.Archived}
+		if w.Universe == "" {
+			spec.Coins = w.Coins
+		}
+		m.Watchlists = append(m.Watchlists, spec)
+	}
+
+	own, unowned, err := s.sessionRules(owner)
+	if err != nil {
+		return Manifest{}, err
+	}
+	for _, r := range slices.Concat(own, unowned) {
+		m.AlertRules = append(m.AlertRules, AlertRule{
+			CryptoID:   r.CryptoID,
+			Kind:       r.Kind,
+			Threshold:  r.Threshold,
+			FastPeriod: r.FastPeriod,
+			SlowPeriod: r.SlowPeriod,
+			Period:     r.Period,
+			Severity:   r.Severity,
+		})
+	}
+
+	charts, err := s.charts.List(owner)
+	if err != nil {
+		return Manifest{}, err
+	}
+	sort.Slice(charts, func(i, j int) bool { return charts[i].Name < charts[j].Name })
+	for _, c := range charts {
+		m.Charts = append(m.Charts, Chart{Name: c.Name, CryptoID: c.CryptoID, Range: c.Range, Scale: c.Scale, Indicators: c.Indicators})
+	}
+	return m, nil
+}
+
+// Plan compares a session with a manifest. Resources missing from a section the
+// manifest declares, even as an empty list, are only deleted with prune; the
+// theme is never deleted. Every declared resource is validated first, so an
+// invalid manifest changes nothing.
+func (s *Service) Plan(owner string, m Manifest, prune bool) (Plan, error) {
+	if err := validate(owner, m); err != nil {
+		return Plan{}, err
+	}
+
+	// Without a previous apply the sections of last are nil and nothing counts as drift
+	s.mu.Lock()
+	last := s.applied[owner]
+	s.mu.Unlock()
+
+	plan := Plan{Changes: []Change{}}
+	s.planProviders(m, &plan)
+	steps := []func(string, Manifest, Manifest, bool, *Plan) error{s.planTheme, s.planWatchlists, s.planCharts, s.planAlertRules}
+	for _, step := range steps {
+		if err := step(owner, m, last, prune, &plan); err != nil {
+			return Plan{}, err
+		}
+	}
+	// The caller keeps the manifest, so the reference is a copy of its sections
+	applied := Manifest{Watchlists: slices.Clone(m.Watchlists), AlertRules: slices.Clone(m.AlertRules), Charts: slices.Clone(m.Charts)}
+	if m.Theme != nil {
+		theme := *m.Theme
+		applied.Theme = &theme
+	}
+	adopted := plan.adopted
+	plan.applied = func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.applied[owner] = applied
+		for _, id := range adopted {
+			if s.ruleOwners[id] == "" {
+				s.ruleOwners[id] = owner
+			}
+		}
+	}
+	return plan, nil
+}
+
+// Apply makes the changes of the plan in order and stops at the first failure.
+// Once every change is made the manifest becomes the session's reference for drift.
+func (p Plan) Apply() error {
+	for _, c := range p.Changes {
+		if err := c.apply(); err != nil {
+			return fmt.Errorf("failed to %s %s %q: %w", c.Action, strings.ReplaceAll(c.Kind, "_", " "), c.Name, err)
+		}
+	}
+	if p.applied != nil {
+		p.applied()
+	}
+	return nil
+}
+
+// validate checks every declared resource the way the services would, and
+// that watchlists and charts are named only once
+func validate(owner string, m Manifest) error {
+	if m.Theme != nil {
+		t := m.Theme.model(owner)
+		if err := t.Validate(); err != nil {
+			return err
+		}
+	}
+	seen := map[string]bool{}
+	for _, w := range m.Watchlists {
+		list := w.model(owner)
+		if err := list.Validate(); err != nil {
+			return err
+		}
+		if seen[list.Name] {
+			return fmt.Errorf("watchlist %q is declared twice", list.Name)
+		}
+		seen[list.Name] = true
+	}
+	for _, r := range m.AlertRules {
+		rule := r.model()
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	clear(seen)
+	for _, c := range m.Charts {
+		chart := c.model(owner)
+		if err := chart.Validate(); err != nil {
+			return err
+		}
+		if seen[chart.Name] {
+			return fmt.Errorf("chart %q is declared twice", chart.Name)
+		}
+		seen[chart.Name] = true
+	}
+	return nil
+}
+
+func (s *Service) runtimeProviders() Providers {
+	p := Providers{Compare: slices.Clone(s.providers.Compare), ETF: slices.Clone(s.providers.ETF)}
+	if p.Compare == nil {
+		p.Compare = []string{}
+	}
+	if p.ETF == nil {
+		p.ETF = []string{}
+	}
+	return p
+}
+
+// planProviders reports providers the server does not run as declared
+func (s *Service) planProviders(m Manifest, plan *Plan) {
+	if m.Providers == nil {
+		return
+	}
+	runtime := s.runtimeProviders()
+	for _, p := range []struct {
+		name       string
+		have, want []string
+	}{
+		{"compare", runtime.Compare, m.Providers.Compare},
+		{"etf", runtime.ETF, m.Providers.ETF},
+	} {
+		have, want := sortedLower(p.have), sortedLower(p.want)
+		if !slices.Equal(have, want) {
+			plan.Drift = append(plan.Drift, Change{
+				Action: ActionUpdate, Kind: "providers", Name: p.name, Drifted: true,
+				Diff: []FieldDiff{{Field: p.name, From: listValue(have), To: listValue(want)}},
+			})
+		}
+	}
+}
+
+func (s *Service) planTheme(owner string, m, last Manifest, prune bool, plan *Plan) error {
+	if m.Theme == nil {
+		return nil
+	}
+	current, err := s.themes.Get(owner)
+	if err != nil {
+		return err
+	}
+	want := m.Theme.model(owner)
+	diff := themeDiff(current, want)
+	if len(diff) == 0 {
+		plan.Unchanged++
+		return nil
+	}
+	colorsChanged := slices.ContainsFunc(diff, func(d FieldDiff) bool { return d.Field != "privacy" })
+	plan.Changes = append(plan.Changes, Change{
+		Action: ActionUpdate, Kind: "theme", Name: string(want.Mode), Diff: diff,
+		Drifted: last.Theme != nil && len(themeDiff(current, last.Theme.model(owner))) > 0,
+		apply: func() error {
+			if colorsChanged {
+				if _, err := s.themes.Set(owner, want); err != nil {
+					return err
+				}
+			}
+			_, err := s.themes.SetPrivacy(owner, want.Privacy)
+			return err
+		},
+	})
+	return nil
+}
+
+func themeDiff(have, want models.Theme) []FieldDiff {
+	var diff []FieldDiff
+	diff = appendDiff(diff, "mode", string(have.Mode), string(want.Mode))
+	diff = appendDiff(diff, "accent", have.Accent, want.Accent)
+	diff = appendDiff(diff, "up", have.Up, want.Up)
+	diff = appendDiff(diff, "down", have.Down, want.Down)
+	return appendDiff(diff, "privacy", strconv.FormatBool(have.Privacy), strconv.FormatBool(want.Privacy))
+}
+
+func (s *Service) planWatchlists(owner string, m, last Manifest, prune bool, plan *Plan) error {
+	if m.Watchlists == nil {
+		return nil
+	}
+	lists, err := s.watchlists.List(owner)
+	if err != nil {
+		return err
+	}
+	current := make(map[string]models.Watchlist, len(lists))
+	for _, w := range lists {
+		current[w.Name] = w
+	}
+	applied := make(map[string]models.Watchlist, len(last.Watchlists))
+	for _, spec := range last.Watchlists {
+		w := spec.model(owner)
+		applied[w.Name] = w
+	}
+	// drifted reports whether a watchlist differs from the last applied manifest
+	drifted := func(name string, have *models.Watchlist) bool {
+		if last.Watchlists == nil {
+			return false
+		}
+		was, ok := applied[name]
+		if have == nil || !ok {
+			return ok != (have != nil)
+		}
+		return len(watchlistDiff(*have, was)) > 0
+	}
+
+	for _, spec := range m.Watchlists {
+		want := spec.model(owner)
+		have, ok := current[want.Name]
+		delete(current, want.Name)
+		if !ok {
+			plan.Changes = append(plan.Changes, Change{
+				Action: ActionCreate, Kind: "watchlist", Name: want.Name, Drifted: drifted(want.Name, nil),
+				apply: func() error {
+					_, err := s.watchlists.Create(want)
+					return err
+				},
+			})
+			continue
+		}
+		diff := watchlistDiff(have, want)
+		if len(diff) == 0 {
+			plan.Unchanged++
+			continue
+		}
+		update := watchlist.Update{Archived: &want.Archived, Universe: &want.Universe}
+		if want.Universe == "" {
+			// An empty list of coins is sent as such so it clears the watchlist
+			update.Coins = append([]string{}, want.Coins...)
+		}
+		id := have.ID
+		plan.Changes = append(plan.Changes, Change{
+			Action: ActionUpdate, Kind: "watchlist", Name: want.Name, Diff: diff, Drifted: drifted(want.Name, &have),
+			apply: func() error {
+				_, err := s.watchlists.Update(id, update)
+				return err
+			},
+		})
+	}
+
+	if prune {
+		for _, w := range lists {
+			if _, stale := current[w.Name]; stale {
+				id := w.ID
+				plan.Changes = append(plan.Changes, Change{
+					Action: ActionDelete, Kind: "watchlist", Name: w.Name, Drifted: drifted(w.Name, &w),
+					apply: func() error { return s.watchlists.Delete(id) },
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// watchlistDiff compares the declared settings; the coins of a watchlist
+// following a universe are resolved, so they are only compared without one
+func watchlistDiff(have, want models.Watchlist) []FieldDiff {
+	var diff []FieldDiff
+	diff = appendDiff(diff, "universe", have.Universe, want.Universe)
+	if want.Universe == "" {
+		diff = appendDiff(diff, "coins", listValue(have.Coins), listValue(want.Coins))
+	}
+	return appendDiff(diff, "archived", strconv.FormatBool(have.Archived), strconv.FormatBool(want.Archived))
+}
+
+func (s *Service) planCharts(owner string, m, last Manifest, prune bool, plan *Plan) error {
+	if m.Charts == nil {
+		return nil
+	}
+	charts, err := s.charts.List(owner)
+	if err != nil {
+		return err
+	}
+	current := make(map[string]models.ChartConfig, len(charts))
+	for _, c := range charts {
+		current[c.Name] = c
+	}
+	applied := make(map[string]models.ChartConfig, len(last.Charts))
+	for _, spec := range last.Charts {
+		c := spec.model(owner)
+		applied[c.Name] = c
+	}
+	drifted := func(name string, have *models.ChartConfig) bool {
+		if last.Charts == nil {
+			return false
+		}
+		was, ok := applied[name]
+		if have == nil || !ok {
+			return ok != (have != nil)
+		}
+		return len(chartDiff(*have, was)) > 0
+	}
+
+	for _, spec := range m.Charts {
+		want := spec.model(owner)
+		have, ok := current[want.Name]
+		delete(current, want.Name)
+		if !ok {
+			plan.Changes = append(plan.Changes, Change{
+				Action: ActionCreate, Kind: "chart", Name: want.Name, Drifted: drifted(want.Name, nil),
+				apply: func() error {
+					_, err := s.charts.Create(want)
+					return err
+				},
+			})
+			continue
+		}
+		diff := chartDiff(have, want)
+		if len(diff) == 0 {
+			plan.Unchanged++
+			continue
+		}
+		id := have.ID
+		plan.Changes = append(plan.Changes, Change{
+			Action: ActionUpdate, Kind: "chart", Name: want.Name, Diff: diff, Drifted: drifted(want.Name, &have),
+			apply: func() error {
+				_, err := s.charts.Update(id, want)
+				return err
+			},
+		})
+	}
+
+	if prune {
+		for _, c := range charts {
+			if _, stale := current[c.Name]; stale {
+				id := c.ID
+				plan.Changes = append(plan.Changes, Change{
+					Action: ActionDelete, Kind: "chart", Name: c.Name, Drifted: drifted(c.Name, &c),
+					apply: func() error { return s.charts.Delete(id) },
+				})
+			}
+		}
+	}
+	return nil
+}
+
+func chartDiff(have, want models.ChartConfig) []FieldDiff {
+	var diff []FieldDiff
+	diff = appendDiff(diff, "crypto_id", have.CryptoID, want.CryptoID)
+	diff = appendDiff(diff, "range", string(have.Range), string(want.Range))
+	diff = appendDiff(diff, "scale", string(have.Scale), string(want.Scale))
+	return appendDiff(diff, "indicators", indicatorsValue(have.Indicators), indicatorsValue(want.Indicators))
+}
+
+// planAlertRules matches rules by all of their settings, preferring the
+// session's own rules over ones no session owns, which are adopted. Rules
+// cannot be edited, so a changed rule is a new one and the old one is pruned.
+// Only the session's own rules are pruned; those of other sessions and the
+// ones created outside a manifest are left alone.
+func (s *Service) planAlertRules(owner string, m, last Manifest, prune bool, plan *Plan) error {
+	if m.AlertRules == nil {
+		return nil
+	}
+	own, unowned, err := s.sessionRules(owner)
+	if err != nil {
+		return err
+	}
+	current := make(map[string][]models.AlertRule)
+	for _, r := range own {
+		current[ruleKey(r)] = append(current[ruleKey(r)], r)
+	}
+	adoptable := make(map[string][]models.AlertRule)
+	for _, r := range unowned {
+		adoptable[ruleKey(r)] = append(adoptable[ruleKey(r)], r)
+	}
+	applied := make(map[string]bool, len(last.AlertRules))
+	for _, spec := range last.AlertRules {
+		applied[ruleKey(spec.model())] = true
+	}
+
+	for _, spec := range m.AlertRules {
+		want := spec.model()
+		key := ruleKey(want)
+		if matches := current[key]; len(matches) > 0 {
+			current[key] = matches[1:]
+			plan.Unchanged++
+			continue
+		}
+		if matches := adoptable[key]; len(matches) > 0 {
+			adoptable[key] = matches[1:]
+			plan.adopted = append(plan.adopted, matches[0].ID)
+			plan.Unchanged++
+			continue
+		}
+		plan.Changes = append(plan.Changes, Change{
+			Action: ActionCreate, Kind: "alert_rule", Name: ruleName(want), Drifted: applied[key],
+			apply: func() error {
+				created, err := s.rules.CreateRule(want)
+				if err != nil {
+					return err
+				}
+				s.mu.Lock()
+				defer s.mu.Unlock()
+				s.ruleOwners[created.ID] = owner
+				return nil
+			},
+		})
+	}
+
+	if prune {
+		for _, r := range own {
+			key := ruleKey(r)
+			if !slices.ContainsFunc(current[key], func(c models.AlertRule) bool { return c.ID == r.ID }) {
+				continue
+			}
+			id := r.ID
+			plan.Changes = append(plan.Changes, Change{
+				Action: ActionDelete, Kind: "alert_rule", Name: ruleName(r), Drifted: last.AlertRules != nil && !applied[key],
+				apply: func() error {
+					if err := s.rules.DeleteRule(id); err != nil {
+						return err
+					}
+					s.mu.Lock()
+					defer s.mu.Unlock()
+					delete(s.ruleOwners, id)
+					return nil
+				},
+			})
+		}
+	}
+	return nil
+}
+
+// sessionRules splits the alert rules into those the session's manifests
+// manage and those no session does. Rules of other sessions are left out.
+func (s *Service) sessionRules(owner string) (own, unowned []models.AlertRule, err error) {
+	rules, err := s.rules.Rules()
+	if err != nil {
+		return nil, nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range rules {
+		switch s.ruleOwners[r.ID] {
+		case owner:
+			own = append(own, r)
+		case "":
+			unowned = append(unowned, r)
+		}
+	}
+	return own, unowned, nil
+}
+
+// ruleKey identifies a rule by its settings once defaults are applied
+func ruleKey(r models.AlertRule) string {
+	r = r.WithDefaults()
+	return strings.Join([]string{
+		r.CryptoID, string(r.Kind), strconv.FormatFloat(r.Threshold, 'g', -1, 64),
+		strconv.Itoa(r.FastPeriod), strconv.Itoa(r.SlowPeriod), strconv.Itoa(r.Period), string(r.Severity),
+	}, "|")
+}
+
+// ruleName describes a rule in a plan, e.g. "bitcoin price_above 70000"
+func ruleName(r models.AlertRule) string {
+	switch r.Kind {
+	case models.AlertGoldenCross, models.AlertDeathCross:
+		return fmt.Sprintf("%s %s %d/%d", r.CryptoID, r.Kind, r.FastPeriod, r.SlowPeriod)
+	case models.AlertRSIBelow, models.AlertRSIAbove:
+		return fmt.Sprintf("%s %s %g (%d)", r.CryptoID, r.Kind, r.Threshold, r.Period)
+	}
+	return fmt.Sprintf("%s %s %g", r.CryptoID, r.Kind, r.Threshold)
+}
+
+func appendDiff(diff []FieldDiff, field, from, to string) []FieldDiff {
+	if from == to {
+		return diff
+	}
+	return append(diff, FieldDiff{Field: field, From: from, To: to})
+}
+
+// listValue formats a list for a diff, e.g. [bitcoin ethereum]
+func listValue(items []string) string {
+	return "[" + strings.Join(items, " ") + "]"
+}
+
+// indicatorsValue formats indicator periods for a diff, e.g. rsi=14 sma=50
+func indicatorsValue(indicators map[string]int) string {
+	parts := make([]string, 0, len(indicators))
+	for _, name := range slices.Sorted(maps.Keys(indicators)) {
+		parts = append(parts, name+"="+strconv.Itoa(indicators[name]))
+	}
+	return strings.Join(parts, " ")
+}
+
+func sortedLower(items []string) []string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = strings.ToLower(strings.TrimSpace(item))
+	}
+	slices.Sort(out)
+	return out
+}
@@ -0,0 +1,360 @@
+package manifest
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	"crypto-dashboard/internal/application/watchlist"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// fakeStore keeps every resource in memory, with IDs in creation order
+type fakeStore struct {
+	lists  []models.Watchlist
+	rules  []models.AlertRule
+	charts []models.ChartConfig
+	theme  models.Theme
+	nextID int
+}
+
+func (f *fakeStore) id() string {
+	f.nextID++
+	return strconv.Itoa(f.nextID)
+}
+
+func (f *fakeStore) List(owner string) ([]models.Watchlist, error) {
+	return slices.Clone(f.lists), nil
+}
+
+func (f *fakeStore) Create(w models.Watchlist) (models.Watchlist, error) {
+	w.ID = f.id()
+	f.lists = append(f.lists, w)
+	return w, nil
+}
+
+func (f *fakeStore) Update(id string, u watchlist.Update) (models.Watchlist, error) {
+	i := slices.IndexFunc(f.lists, func(w models.Watchlist) bool { return w.ID == id })
+	if u.Coins != nil {
+		f.lists[i].Coins = u.Coins
+	}
+	if u.Universe != nil {
+		f.lists[i].Universe = *u.Universe
+	}
+	if u.Archived != nil {
+		f.lists[i].Archived = *u.Archived
+	}
+	return f.lists[i], nil
+}
+
+func (f *fakeStore) Delete(id string) error {
+	f.lists = slices.DeleteFunc(f.lists, func(w models.Watchlist) bool { return w.ID == id })
+	return nil
+}
+
+func (f *fakeStore) Rules() ([]models.AlertRule, error) { return slices.Clone(f.rules), nil }
+
+func (f *fakeStore) CreateRule(r models.AlertRule) (models.AlertRule, error) {
+	if r.CryptoID == "broken" {
+		return models.AlertRule{}, errors.New("storage is down")
+	}
+	r.ID = f.id()
+	f.rules = append(f.rules, r.WithDefaults())
+	return r, nil
+}
+
+func (f *fakeStore) DeleteRule(id string) error {
+	f.rules = slices.DeleteFunc(f.rules, func(r models.AlertRule) bool { return r.ID == id })
+	return nil
+}
+
+func (f *fakeStore) Get(owner string) (models.Theme, error) {
+	if f.theme.Mode == "" {
+		return models.DefaultTheme(owner), nil
+	}
+	return f.theme, nil
+}
+
+func (f *fakeStore) Set(owner string, t models.Theme) (models.Theme, error) {
+	t.Privacy = f.theme.Privacy
+	f.theme = t
+	return t, nil
+}
+
+func (f *fakeStore) SetPrivacy(owner string, enabled bool) (models.Theme, error) {
+	f.theme, _ = f.Get(owner)
+	f.theme.Privacy = enabled
+	return f.theme, nil
+}
+
+// fakeCharts is separate since charts and watchlists share method names
+type fakeCharts struct{ store *fakeStore }
+
+func (c fakeCharts) List(owner string) ([]models.ChartConfig, error) {
+	return slices.Clone(c.store.charts), nil
+}
+
+func (c fakeCharts) Create(chart models.ChartConfig) (models.ChartConfig, error) {
+	chart.ID = c.store.id()
+	c.store.charts = append(c.store.charts, chart)
+	return chart, nil
+}
+
+func (c fakeCharts) Update(id string, chart models.ChartConfig) (models.ChartConfig, error) {
+	i := slices.IndexFunc(c.store.charts, func(have models.ChartConfig) bool { return have.ID == id })
+	chart.ID = id
+	c.store.charts[i] = chart
+	return chart, nil
+}
+
+func (c fakeCharts) Delete(id string) error {
+	c.store.charts = slices.DeleteFunc(c.store.charts, func(have models.ChartConfig) bool { return have.ID == id })
+	return nil
+}
+
+func newTestService() (*Service, *fakeStore) {
+	store := &fakeStore{}
+	return NewService(store, store, store, fakeCharts{store}), store
+}
+
+func summary(plan Plan) []string {
+	var out []string
+	for _, c := range plan.Changes {
+		out = append(out, string(c.Action)+" "+c.Kind+" "+c.Name)
+	}
+	return out
+}
+
+func TestService_PlanAndApply(t *testing.T) {
+	s, store := newTestService()
+	store.lists = []models.Watchlist{
+		{ID: "a", Name: "Majors", Coins: []string{"bitcoin"}},
+		{ID: "b", Name: "Old", Coins: []string{"dogecoin"}},
+	}
+	store.rules = []models.AlertRule{
+		models.AlertRule{ID: "r1", CryptoID: "bitcoin", Kind: models.AlertPriceAbove, Threshold: 70000}.WithDefaults(),
+		models.AlertRule{ID: "r2", CryptoID: "ethereum", Kind: models.AlertPriceBelow, Threshold: 2000}.WithDefaults(),
+	}
+
+	m := Manifest{
+		Version: Version,
+		Theme:   &Theme{Mode: "dark", Privacy: true},
+		Watchlists: []Watchlist{
+			{Name: "Majors", Coins: []string{"bitcoin", "ethereum"}},
+			{Name: "Top", Universe: "top-100"},
+		},
+		AlertRules: []AlertRule{
+			{CryptoID: "bitcoin", Kind: models.AlertPriceAbove, Threshold: 70000},
+			{CryptoID: "solana", Kind: models.AlertRSIBelow},
+		},
+	}
+
+	plan, err := s.Plan(models.DefaultOwner, m, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"update theme dark",
+		"update watchlist Majors",
+		"create watchlist Top",
+		"create alert_rule solana rsi_below 30 (14)",
+	}
+	if got := summary(plan); !slices.Equal(got, want) || plan.Unchanged != 1 {
+		t.Fatalf("Expected %v with one unchanged rule, got %v (%d unchanged)", want, got, plan.Unchanged)
+	}
+	if len(store.lists) != 2 || len(store.rules) != 2 {
+		t.Fatal("Expected planning to change nothing")
+	}
+
+	pruned, err := s.Plan(models.DefaultOwner, m, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pruned.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	if !store.theme.Privacy || len(store.lists) != 2 || store.lists[1].Name != "Top" || len(store.lists[0].Coins) != 2 {
+		t.Errorf("Unexpected state after apply: %+v %+v", store.theme, store.lists)
+	}
+	// r2 was created outside a manifest, so other sessions may rely on it
+	if len(store.rules) != 3 || store.rules[0].ID != "r1" || store.rules[1].ID != "r2" || store.rules[2].CryptoID != "solana" {
+		t.Errorf("Expected only the manifest's rules, with r1 adopted, got %+v", store.rules)
+	}
+
+	again, _ := s.Plan(models.DefaultOwner, m, true)
+	if len(again.Changes) != 0 || again.Unchanged != 5 {
+		t.Errorf("Expected a converged session, got %v (%d unchanged)", summary(again), again.Unchanged)
+	}
+}
+
+func TestService_PrunesOnlyOwnRules(t *testing.T) {
+	s, store := newTestService()
+	mine := Manifest{AlertRules: []AlertRule{{CryptoID: "bitcoin", Kind: models.AlertPriceAbove, Threshold: 70000}}}
+	plan, _ := s.Plan("alice", mine, false)
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+
+	theirs, err := s.Plan("bob", Manifest{AlertRules: []AlertRule{}}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(theirs.Changes) != 0 {
+		t.Errorf("Expected another session's rules left alone, got %v", summary(theirs))
+	}
+	if m, _ := s.Export("bob"); len(m.AlertRules) != 0 {
+		t.Errorf("Expected another session's rules left out of the export, got %+v", m.AlertRules)
+	}
+	if m, _ := s.Export("alice"); len(m.AlertRules) != 1 {
+		t.Errorf("Expected the session's own rule exported, got %+v", m.AlertRules)
+	}
+
+	pruned, _ := s.Plan("alice", Manifest{AlertRules: []AlertRule{}}, true)
+	if got := summary(pruned); !slices.Equal(got, []string{"delete alert_rule bitcoin price_above 70000"}) {
+		t.Errorf("Expected the session's own rule pruned, got %v", got)
+	}
+	if err := pruned.Apply(); err != nil || len(store.rules) != 0 {
+		t.Errorf("Expected the rule deleted, got %+v, %v", store.rules, err)
+	}
+}
+
+func TestService_PlanRejectsInvalidManifest(t *testing.T) {
+	s, _ := newTestService()
+	for name, m := range map[string]Manifest{
+		"duplicate": {Watchlists: []Watchlist{{Name: "A"}, {Name: "A"}}},
+		"rule":      {AlertRules: []AlertRule{{CryptoID: "bitcoin", Kind: models.AlertPriceAbove}}},
+		"chart":     {Charts: []Chart{{Name: "c", CryptoID: "bitcoin", Scale: "sqrt"}}},
+		"theme":     {Theme: &Theme{Mode: "sepia"}},
+	} {
+		if _, err := s.Plan(models.DefaultOwner, m, false); err == nil {
+			t.Errorf("Expected the %s manifest to be rejected", name)
+		}
+	}
+}
+
+func TestPlan_ApplyStopsAtFirstFailure(t *testing.T) {
+	s, store := newTestService()
+	plan, err := s.Plan(models.DefaultOwner, Manifest{AlertRules: []AlertRule{
+		{CryptoID: "broken", Kind: models.AlertPriceAbove, Threshold: 1},
+		{CryptoID: "bitcoin", Kind: models.AlertPriceAbove, Threshold: 1},
+	}}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plan.Apply(); err == nil || err.Error() != `failed to create alert rule "broken price_above 1": storage is down` {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if len(store.rules) != 0 {
+		t.Errorf("Expected nothing after the failure, got %+v", store.rules)
+	}
+}
+
+func TestService_Export(t *testing.T) {
+	s, store := newTestService()
+	store.lists = []models.Watchlist{{ID: "a", Name: "Top", Universe: "top-100", Coins: []string{"bitcoin"}}}
+	fakeCharts{store}.Create(models.ChartConfig{Name: "cycle", CryptoID: "bitcoin", Range: models.ChartMax, Scale: models.ChartLog})
+
+	m, err := s.Export(models.DefaultOwner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Theme.Mode != models.ThemeDark || m.Watchlists[0].Coins != nil || m.AlertRules == nil || m.Charts[0].Name != "cycle" {
+		t.Errorf("Unexpected export: %+v", m)
+	}
+}
+
+func TestService_PlanShowsFieldDiffs(t *testing.T) {
+	s, store := newTestService()
+	store.lists = []models.Watchlist{{ID: "a", Name: "Majors", Coins: []string{"bitcoin"}}}
+	fakeCharts{store}.Create(models.ChartConfig{Name: "cycle", CryptoID: "bitcoin", Range: models.ChartRecent, Scale: models.ChartLinear})
+
+	plan, err := s.Plan(models.DefaultOwner, Manifest{
+		Watchlists: []Watchlist{{Name: "Majors", Coins: []string{"bitcoin", "ethereum"}, Archived: true}},
+		Charts:     []Chart{{Name: "cycle", CryptoID: "bitcoin", Range: "max", Indicators: map[string]int{"sma": 200}}},
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Changes) != 2 {
+		t.Fatalf("Expected two updates, got %v", summary(plan))
+	}
+	wantList := []FieldDiff{{"coins", "[bitcoin]", "[bitcoin ethereum]"}, {"archived", "false", "true"}}
+	wantChart := []FieldDiff{{"range", "recent", "max"}, {"scale", "linear", "log"}, {"indicators", "", "sma=200"}}
+	if !slices.Equal(plan.Changes[0].Diff, wantList) || !slices.Equal(plan.Changes[1].Diff, wantChart) {
+		t.Errorf("Unexpected diffs: %+v / %+v", plan.Changes[0].Diff, plan.Changes[1].Diff)
+	}
+}
+
+func TestService_PlanDetectsDrift(t *testing.T) {
+	s, store := newTestService()
+	m := Manifest{
+		Watchlists: []Watchlist{{Name: "Majors", Coins: []string{"bitcoin"}}, {Name: "Alts", Coins: []string{"solana"}}},
+		AlertRules: []AlertRule{{CryptoID: "bitcoin", Kind: models.AlertPriceAbove, Threshold: 70000}},
+	}
+	first, _ := s.Plan(models.DefaultOwner, m, true)
+	for _, c := range first.Changes {
+		if c.Drifted {
+			t.Errorf("Expected no drift before the first apply, got %+v", c)
+		}
+	}
+	if err := first.Apply(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Outside the manifest: Majors edited, Alts deleted, the rule deleted and a watchlist added
+	store.lists[0].Coins = []string{"bitcoin", "dogecoin"}
+	store.Delete(store.lists[1].ID)
+	store.rules = nil
+	store.Create(models.Watchlist{Name: "Scratch"})
+
+	plan, err := s.Plan(models.DefaultOwner, m, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"update watchlist Majors",
+		"create watchlist Alts",
+		"delete watchlist Scratch",
+		"create alert_rule bitcoin price_above 70000",
+	}
+	if got := summary(plan); !slices.Equal(got, want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for _, c := range plan.Changes {
+		if !c.Drifted {
+			t.Errorf("Expected %s %s to be reported as drift", c.Kind, c.Name)
+		}
+	}
+
+	// Once reconciled, editing the manifest itself is not drift
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	m.Watchlists[0].Coins = []string{"bitcoin", "ethereum"}
+	plan, _ = s.Plan(models.DefaultOwner, m, true)
+	if len(plan.Changes) != 1 || plan.Changes[0].Drifted {
+		t.Errorf("Expected a manifest edit without drift, got %+v", plan.Changes)
+	}
+}
+
+func TestService_PlanReportsProviderDrift(t *testing.T) {
+	s, _ := newTestService()
+	s.SetProviders(Providers{Compare: []string{"coingecko", "binance"}, ETF: []string{"btc"}})
+
+	plan, err := s.Plan(models.DefaultOwner, Manifest{Providers: &Providers{Compare: []string{"Binance", "coingecko"}, ETF: []string{"btc", "eth"}}}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Changes) != 0 || len(plan.Drift) != 1 {
+		t.Fatalf("Expected only the ETF providers as drift, got %+v", plan)
+	}
+	if d := plan.Drift[0]; d.Name != "etf" || d.Diff[0] != (FieldDiff{"etf", "[btc]", "[btc eth]"}) {
+		t.Errorf("Unexpected drift: %+v", d)
+	}
+
+	m, _ := s.Export(models.DefaultOwner)
+	if !slices.Equal(m.Providers.Compare, []string{"coingecko", "binance"}) {
+		t.Errorf("Expected the runtime providers in the export, got %+v", m.Providers)
+	}
+}
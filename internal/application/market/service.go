@@ -0,0 +1,134 @@
+// Package market keeps the global crypto market overview fresh
+package market
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// DefaultInterval is how long an overview is reused; upstream only refreshes it every few minutes
+const DefaultInterval = 5 * time.Minute
+
+// Source fetches the global market overview
+type Source interface {
+	GetGlobalData(currency models.Currency) (models.GlobalMarket, error)
+}
+
+// Service caches the global market overview
+type Service struct {
+	source   Source
+	currency models.Currency
+	interval time.Duration
+	logger   *slog.Logger
+	clock    clock.Clock
+	throttle func() bool
+
+	mu      sync.Mutex
+	latest  models.GlobalMarket
+	fetched time.Time
+	lastRun time.Time
+}
+
+// NewService creates a market overview service refreshing at most once per interval
+func NewService(source Source, currency models.Currency, interval time.Duration) *Service {
+	return &Service{source: source, currency: currency, interval: interval, logger: slog.Default(), clock: clock.Real}
+}
+
+// SetLogger replaces the default logger used to report refresh failures
+func (s *Service) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
+// SetThrottle registers a check run before every scheduled refresh. The
+// refresh is skipped while it returns true, e.g. when the provider's daily
+// call budget is nearly spent. It must be called before Run.
+func (s *Service) SetThrottle(throttled func() bool) {
+	s.throttle = throttled
+}
+
+// SetClock replaces the system clock, for tests
+func (s *Service) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Global returns the cached overview, fetching a new one when it is older than the interval.
+// When the fetch fails the last known overview is returned flagged as stale;
+// the error is only returned when there is none.
+func (s *Service) Global() (models.GlobalMarket, error) {
+	if global, ok := s.fresh(); ok {
+		return global, nil
+	}
+	global, err := s.refresh()
+	if err != nil {
+		if stale, ok := s.Latest(); ok {
+			return stale, nil
+		}
+	}
+	return global, err
+}
+
+// Latest returns the last fetched overview without blocking on the network
+func (s *Service) Latest() (models.GlobalMarket, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latest, !s.fetched.IsZero()
+}
+
+// Run refreshes the overview every interval until the context is cancelled
+func (s *Service) Run(ctx context.Context) {
+	ticker := s.clock.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if s.throttle != nil && s.throttle() {
+			s.logger.Debug("global market refresh skipped, call budget nearly spent")
+		} else if _, err := s.refresh(); err != nil {
+			s.logger.Warn("global market refresh failed", "error", err)
+		}
+		now := s.clock.Now()
+		s.mu.Lock()
+		s.lastRun = now
+		s.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+	}
+}
+
+// LastRun returns when Run last ended a refresh cycle, refreshed or skipped,
+// or the zero time if it has not yet
+func (s *Service) LastRun() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastRun
+}
+
+func (s *Service) fresh() (models.GlobalMarket, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fetched.IsZero() || s.clock.Now().Sub(s.fetched) >= s.interval {
+		return models.GlobalMarket{}, false
+	}
+	return s.latest, true
+}
+
+func (s *Service) refresh() (models.GlobalMarket, error) {
+	global, err := s.source.GetGlobalData(s.currency)
+	if err != nil {
+		s.mu.Lock()
+		s.latest.Stale = !s.fetched.IsZero()
+		s.mu.Unlock()
+		return models.GlobalMarket{}, err
+	}
+	s.mu.Lock()
+	s.latest, s.fetched = global, s.clock.Now()
+	s.mu.Unlock()
+	return global, nil
+}
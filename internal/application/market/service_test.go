@@ -0,0 +1,94 @@
+package market
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubSource struct {
+	calls int
+	err   error
+}
+
+func (s *stubSource) GetGlobalData(currency models.Currency) (models.GlobalMarket, error) {
+	s.calls++
+	if s.err != nil {
+		return models.GlobalMarket{}, s.err
+	}
+	return models.GlobalMarket{Currency: currency, TotalMarketCap: float64(s.calls)}, nil
+}
+
+func TestService_Global(t *testing.T) {
+	source := &stubSource{}
+	s := NewService(source, models.EUR, time.Minute)
+	now := time.Now()
+	clk := clock.NewFake(now)
+	s.SetClock(clk)
+
+	if _, ok := s.Latest(); ok {
+		t.Error("Expected no overview before the first fetch")
+	}
+
+	global, err := s.Global()
+	if err != nil || global.Currency != models.EUR {
+		t.Fatalf("Unexpected overview %+v (%v)", global, err)
+	}
+	s.Global()
+	if source.calls != 1 {
+		t.Errorf("Expected the overview to be cached, got %d calls", source.calls)
+	}
+
+	clk.Advance(time.Minute)
+	if global, _ := s.Global(); global.TotalMarketCap != 2 {
+		t.Errorf("Expected a stale overview to be refetched, got %+v", global)
+	}
+
+	source.err = errors.New("rate limited")
+	clk.Advance(time.Minute)
+	if global, err := s.Global(); err != nil || !global.Stale || global.TotalMarketCap != 2 {
+		t.Errorf("Expected the last good overview flagged stale, got %+v (%v)", global, err)
+	}
+	if latest, ok := s.Latest(); !ok || latest.TotalMarketCap != 2 {
+		t.Errorf("Expected the last good overview to be kept, got %+v", latest)
+	}
+
+	source.err = nil
+	clk.Advance(time.Minute)
+	if global, _ := s.Global(); global.Stale {
+		t.Errorf("Expected a fresh overview after recovery, got %+v", global)
+	}
+}
+
+func TestService_RunThrottled(t *testing.T) {
+	source := &stubSource{}
+	s := NewService(source, models.USD, time.Minute)
+	clk := clock.NewFake(time.Now())
+	s.SetClock(clk)
+	throttled := make(chan bool)
+	s.SetThrottle(func() bool { return <-throttled })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	throttled <- true
+	clk.Advance(time.Minute)
+	throttled <- false
+	cancel()
+	<-done
+
+	if source.calls != 1 {
+		t.Errorf("Expected only the unthrottled refresh to fetch, got %d calls", source.calls)
+	}
+	// Skipped refreshes count as progress too
+	if !s.LastRun().Equal(clk.Now()) {
+		t.Errorf("Expected the last cycle at %s, got %s", clk.Now(), s.LastRun())
+	}
+}
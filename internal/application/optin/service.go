@@ -0,0 +1,223 @@
+// Package optin keeps track of the recipients who agreed to receive alerts on
+// channels that require consent, such as WhatsApp. A recipient only becomes
+// active after confirming the opt-in with a code sent to their address, so
+// nobody can sign up a number they do not control.
+package optin
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+const (
+	// CodeTTL is how long a confirmation code stays valid
+	CodeTTL = 10 * time.Minute
+	// ResendInterval is the shortest time between two codes to one recipient
+	ResendInterval = time.Minute
+	// MaxAttempts is how many wrong codes discard a pending opt-in
+	MaxAttempts = 5
+	// codeDigits is the length of a confirmation code
+	codeDigits = 6
+)
+
+var (
+	// ErrNotFound is returned when a recipient has not opted in
+	ErrNotFound = errors.New("recipient has not opted in")
+	// ErrInvalidCode is returned for a wrong, expired or unknown confirmation code
+	ErrInvalidCode = errors.New("confirmation code is invalid or expired")
+	// ErrTooSoon is returned when a code was sent to the recipient less than ResendInterval ago
+	ErrTooSoon = errors.New("a confirmation code was sent recently, try again later")
+	// ErrNoSender is returned when codes cannot be delivered on the channel
+	ErrNoSender = errors.New("channel cannot deliver confirmation codes")
+	// ErrSendFailed wraps the failure to deliver a confirmation code
+	ErrSendFailed = errors.New("failed to send confirmation code")
+)
+
+// Repository persists confirmed opt-ins per channel and recipient
+type Repository interface {
+	Save(o models.OptIn) (models.OptIn, error)
+	Get(channel models.OptInChannel, recipient string) (models.OptIn, error)
+	Delete(channel models.OptInChannel, recipient string) error
+	List(channel models.OptInChannel) ([]models.OptIn, error)
+}
+
+// CodeSender delivers confirmation codes to recipients
+type CodeSender interface {
+	SendCode(ctx context.Context, o models.OptIn, code string) error
+}
+
+// Pending is an opt-in waiting for its confirmation code
+type Pending struct {
+	Channel   models.OptInChannel `json:"channel"`
+	Recipient string              `json:"recipient"`
+	ExpiresAt time.Time           `json:"expires_at"`
+}
+
+type pendingKey struct {
+	channel   models.OptInChannel
+	recipient string
+}
+
+type pending struct {
+	optIn    models.OptIn
+	code     string
+	sentAt   time.Time
+	attempts int
+}
+
+// Service manages opt-ins
+type Service struct {
+	repo   Repository
+	sender CodeSender
+	clock  clock.Clock
+
+	mu      sync.Mutex
+	pending map[pendingKey]*pending
+}
+
+// NewService creates an opt-in service
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo, clock: clock.Real, pending: make(map[pendingKey]*pending)}
+}
+
+// SetSender sets how confirmation codes are delivered; opt-ins are refused
+// without it
+func (s *Service) SetSender(sender CodeSender) {
+	s.sender = sender
+}
+
+// SetClock replaces the system clock, for tests
+func (s *Service) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Request starts an opt-in for the owner, the session asking for it, and
+// sends a confirmation code to the recipient. Requesting again for a
+// confirmed recipient updates the language once the new code is confirmed.
+func (s *Service) Request(ctx context.Context, owner string, o models.OptIn) (Pending, error) {
+	o.Normalize()
+	if err := o.Validate(); err != nil {
+		return Pending{}, err
+	}
+	if s.sender == nil {
+		return Pending{}, ErrNoSender
+	}
+	o.Owner = owner
+
+	key := pendingKey{channel: o.Channel, recipient: o.Recipient}
+	now := s.clock.Now().UTC()
+	s.mu.Lock()
+	s.expire(now)
+	if p, ok := s.pending[key]; ok && now.Sub(p.sentAt) < ResendInterval {
+		s.mu.Unlock()
+		return Pending{}, ErrTooSoon
+	}
+	code, err := newCode()
+	if err != nil {
+		s.mu.Unlock()
+		return Pending{}, err
+	}
+	s.pending[key] = &pending{optIn: o, code: code, sentAt: now}
+	s.mu.Unlock()
+
+	if err := s.sender.SendCode(ctx, o, code); err != nil {
+		s.mu.Lock()
+		delete(s.pending, key)
+		s.mu.Unlock()
+		return Pending{}, fmt.Errorf("%w: %w", ErrSendFailed, err)
+	}
+	return Pending{Channel: o.Channel, Recipient: o.Recipient, ExpiresAt: now.Add(CodeTTL)}, nil
+}
+
+// Confirm activates the owner's pending opt-in when the code matches. Too
+// many wrong codes discard it.
+func (s *Service) Confirm(owner string, channel models.OptInChannel, recipient, code string) (models.OptIn, error) {
+	key := pendingKey{channel: channel, recipient: normalizedRecipient(recipient)}
+	now := s.clock.Now().UTC()
+	s.mu.Lock()
+	s.expire(now)
+	p, ok := s.pending[key]
+	if !ok || p.optIn.Owner != owner {
+		s.mu.Unlock()
+		return models.OptIn{}, ErrInvalidCode
+	}
+	if subtle.ConstantTimeCompare([]byte(p.code), []byte(code)) != 1 {
+		if p.attempts++; p.attempts >= MaxAttempts {
+			delete(s.pending, key)
+		}
+		s.mu.Unlock()
+		return models.OptIn{}, ErrInvalidCode
+	}
+	delete(s.pending, key)
+	s.mu.Unlock()
+
+	o := p.optIn
+	o.OptedInAt = now
+	return s.repo.Save(o)
+}
+
+// expire drops the pending opt-ins whose code expired. The caller must hold the lock.
+func (s *Service) expire(now time.Time) {
+	for key, p := range s.pending {
+		if now.Sub(p.sentAt) >= CodeTTL {
+			delete(s.pending, key)
+		}
+	}
+}
+
+// List returns the confirmed opt-ins of a channel the owner requested
+func (s *Service) List(owner string, channel models.OptInChannel) ([]models.OptIn, error) {
+	all, err := s.repo.List(channel)
+	if err != nil {
+		return nil, err
+	}
+	var owned []models.OptIn
+	for _, o := range all {
+		if o.Owner == owner {
+			owned = append(owned, o)
+		}
+	}
+	return owned, nil
+}
+
+// OptOut withdraws the consent of a recipient the owner opted in
+func (s *Service) OptOut(owner string, channel models.OptInChannel, recipient string) error {
+	recipient = normalizedRecipient(recipient)
+	o, err := s.repo.Get(channel, recipient)
+	if err != nil {
+		return err
+	}
+	if o.Owner != owner {
+		return ErrNotFound
+	}
+	return s.repo.Delete(channel, recipient)
+}
+
+// Recipients returns everyone who confirmed an opt-in to a channel
+func (s *Service) Recipients(channel models.OptInChannel) ([]models.OptIn, error) {
+	return s.repo.List(channel)
+}
+
+func normalizedRecipient(recipient string) string {
+	o := models.OptIn{Recipient: recipient}
+	o.Normalize()
+	return o.Recipient
+}
+
+// newCode returns a random numeric confirmation code
+func newCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", codeDigits, n.Int64()), nil
+}
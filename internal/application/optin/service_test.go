@@ -0,0 +1,146 @@
+package optin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubRepo map[string]models.OptIn
+
+func (r stubRepo) Save(o models.OptIn) (models.OptIn, error) {
+	r[string(o.Channel)+":"+o.Recipient] = o
+	return o, nil
+}
+
+func (r stubRepo) Get(channel models.OptInChannel, recipient string) (models.OptIn, error) {
+	o, ok := r[string(channel)+":"+recipient]
+	if !ok {
+		return models.OptIn{}, ErrNotFound
+	}
+	return o, nil
+}
+
+func (r stubRepo) Delete(channel models.OptInChannel, recipient string) error {
+	key := string(channel) + ":" + recipient
+	if _, ok := r[key]; !ok {
+		return ErrNotFound
+	}
+	delete(r, key)
+	return nil
+}
+
+func (r stubRepo) List(channel models.OptInChannel) ([]models.OptIn, error) {
+	var out []models.OptIn
+	for _, o := range r {
+		if o.Channel == channel {
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
+
+// codeSender keeps the last code sent to each recipient
+type codeSender map[string]string
+
+func (c codeSender) SendCode(ctx context.Context, o models.OptIn, code string) error {
+	c[o.Recipient] = code
+	return nil
+}
+
+func newTestService() (*Service, codeSender, *clock.Fake) {
+	s := NewService(stubRepo{})
+	sent := codeSender{}
+	s.SetSender(sent)
+	clk := clock.NewFake(time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC))
+	s.SetClock(clk)
+	return s, sent, clk
+}
+
+func TestService_OptInAndOut(t *testing.T) {
+	s, sent, _ := newTestService()
+	ctx := context.Background()
+
+	p, err := s.Request(ctx, "alice", models.OptIn{Channel: models.ChannelWhatsApp, Recipient: "+55 11 98765-4321", Language: "pt_BR"})
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	if p.Recipient != "5511987654321" || p.ExpiresAt.IsZero() {
+		t.Errorf("Expected a normalized pending opt-in, got %+v", p)
+	}
+	if _, err := s.Request(ctx, "alice", models.OptIn{Channel: models.ChannelWhatsApp, Recipient: "call me"}); err == nil {
+		t.Error("Expected an invalid recipient to be rejected")
+	}
+	if recipients, _ := s.Recipients(models.ChannelWhatsApp); len(recipients) != 0 {
+		t.Fatalf("Expected no recipient before the confirmation, got %+v", recipients)
+	}
+
+	code := sent["5511987654321"]
+	if _, err := s.Confirm("mallory", models.ChannelWhatsApp, "5511987654321", code); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("Expected another session's confirmation to fail, got %v", err)
+	}
+	saved, err := s.Confirm("alice", models.ChannelWhatsApp, "+55 11 98765-4321", code)
+	if err != nil {
+		t.Fatalf("Confirm: %v", err)
+	}
+	if saved.Owner != "alice" || saved.OptedInAt.IsZero() {
+		t.Errorf("Expected an owned, timestamped opt-in, got %+v", saved)
+	}
+	if _, err := s.Confirm("alice", models.ChannelWhatsApp, "5511987654321", code); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("Expected a code to work once, got %v", err)
+	}
+
+	if recipients, _ := s.Recipients(models.ChannelWhatsApp); len(recipients) != 1 {
+		t.Fatalf("Expected one recipient, got %d", len(recipients))
+	}
+	if owned, _ := s.List("mallory", models.ChannelWhatsApp); len(owned) != 0 {
+		t.Errorf("Expected other sessions to see no opt-ins, got %+v", owned)
+	}
+	if err := s.OptOut("mallory", models.ChannelWhatsApp, "5511987654321"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected other sessions not to opt the recipient out, got %v", err)
+	}
+	if err := s.OptOut("alice", models.ChannelWhatsApp, "+55 11 98765-4321"); err != nil {
+		t.Fatalf("OptOut: %v", err)
+	}
+	if err := s.OptOut("alice", models.ChannelWhatsApp, "5511987654321"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after opting out, got %v", err)
+	}
+}
+
+func TestService_ConfirmLimits(t *testing.T) {
+	s, sent, clk := newTestService()
+	ctx := context.Background()
+	o := models.OptIn{Channel: models.ChannelWhatsApp, Recipient: "5511987654321"}
+
+	if _, err := s.Request(ctx, "alice", o); err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	if _, err := s.Request(ctx, "alice", o); !errors.Is(err, ErrTooSoon) {
+		t.Errorf("Expected a resend within a minute to be refused, got %v", err)
+	}
+
+	for range MaxAttempts {
+		s.Confirm("alice", o.Channel, o.Recipient, "wrong")
+	}
+	if _, err := s.Confirm("alice", o.Channel, o.Recipient, sent[o.Recipient]); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("Expected the opt-in discarded after %d wrong codes, got %v", MaxAttempts, err)
+	}
+
+	clk.Advance(ResendInterval)
+	if _, err := s.Request(ctx, "alice", o); err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	clk.Advance(CodeTTL)
+	if _, err := s.Confirm("alice", o.Channel, o.Recipient, sent[o.Recipient]); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("Expected an expired code to be refused, got %v", err)
+	}
+
+	unsent := NewService(stubRepo{})
+	if _, err := unsent.Request(ctx, "alice", o); !errors.Is(err, ErrNoSender) {
+		t.Errorf("Expected ErrNoSender without a sender, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+// Package palette ranks coins, dashboard pages and actions against one query
+// for the keyboard-driven command palette
+package palette
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Kind is the type of a palette entry
+type Kind string
+
+// Entry kinds
+const (
+	KindCoin   Kind = "coin"
+	KindPage   Kind = "page"
+	KindAction Kind = "action"
+)
+
+// Match scores; a coin whose ticker or ID is the query ranks just below a
+// command titled exactly like it
+const (
+	scoreExactTitle  = 100
+	scoreExactCoin   = 95
+	scoreTitlePrefix = 90
+	scoreWordPrefix  = 70
+	scoreCoin        = 60
+	scoreSubstring   = 50
+	scoreMinCoin     = 20
+)
+
+// Entry is a palette result. Pages are opened at Path; actions are requests to
+// Path with Method; coins are selected by ID.
+type Entry struct {
+	Kind   Kind   `json:"kind"`
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Hint   string `json:"hint,omitempty"`
+	Path   string `json:"path,omitempty"`
+	Method string `json:"method,omitempty"`
+	// Keywords are extra lowercase words the entry is found by, e.g. "csv" for an export
+	Keywords []string `json:"-"`
+	Score    int      `json:"score"`
+}
+
+// Searcher looks coins up by name or ticker
+type Searcher interface {
+	Search(query string) ([]models.SearchResult, error)
+}
+
+// Search returns up to limit entries matching the query, best first. An empty
+// query lists the commands without looking coins up. When the coin lookup
+// fails the matching commands are still returned together with the error.
+func Search(query string, commands []Entry, coins Searcher, limit int) ([]Entry, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	var results []Entry
+	for _, c := range commands {
+		if score := commandScore(c, query); score > 0 {
+			c.Score = score
+			results = append(results, c)
+		}
+	}
+
+	var err error
+	if query != "" && coins != nil {
+		var found []models.SearchResult
+		found, err = coins.Search(query)
+		if err != nil {
+			err = fmt.Errorf("coin search failed: %w", err)
+		}
+		for i, coin := range found {
+			score := max(scoreCoin-i, scoreMinCoin)
+			if coin.ExactMatch(query) {
+				score = scoreExactCoin
+			}
+			results = append(results, Entry{
+				Kind:  KindCoin,
+				ID:    coin.ID,
+				Title: coin.Name,
+				Hint:  strings.ToUpper(coin.Symbol),
+				Score: score,
+			})
+		}
+	}
+
+	slices.SortStableFunc(results, func(a, b Entry) int { return cmp.Compare(b.Score, a.Score) })
+	return results[:min(limit, len(results))], err
+}
+
+// commandScore rates how well a page or action matches the query, zero when it
+// does not. A query matches by words when each of its terms starts a word of the
+// title or a keyword, so "exp csv" finds "Export prices as CSV".
+func commandScore(c Entry, query string) int {
+	if query == "" {
+		return scoreSubstring
+	}
+	title := strings.ToLower(c.Title)
+	switch {
+	case title == query:
+		return scoreExactTitle
+	case strings.HasPrefix(title, query):
+		return scoreTitlePrefix
+	}
+	words := append(strings.Fields(title), c.Keywords...)
+	matched := true
+	for _, term := range strings.Fields(query) {
+		matched = matched && slices.ContainsFunc(words, func(w string) bool { return strings.HasPrefix(w, term) })
+	}
+	if matched {
+		return scoreWordPrefix
+	}
+	if strings.Contains(title, query) {
+		return scoreSubstring
+	}
+	return 0
+}
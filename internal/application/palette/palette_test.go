@@ -0,0 +1,68 @@
+package palette
+
+import (
+	"errors"
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubCoins struct {
+	results []models.SearchResult
+	err     error
+	calls   int
+}
+
+func (s *stubCoins) Search(query string) ([]models.SearchResult, error) {
+	s.calls++
+	return s.results, s.err
+}
+
+var commands = []Entry{
+	{Kind: KindPage, ID: "status", Title: "Status", Path: "/status"},
+	{Kind: KindAction, ID: "export-prices", Title: "Export prices as CSV", Path: "/api/v1/export/prices?format=csv", Method: "GET", Keywords: []string{"download"}},
+	{Kind: KindAction, ID: "add-alert", Title: "Add alert", Path: "/api/v1/alerts/rules", Method: "POST"},
+}
+
+func TestSearch_RanksCommandsAndCoins(t *testing.T) {
+	coins := &stubCoins{results: []models.SearchResult{
+		{ID: "solana-name-service", Symbol: "sns", Name: "Solana Name Service"},
+		{ID: "status", Symbol: "snt", Name: "Status"},
+	}}
+
+	got, err := Search("Status", commands, coins, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0].ID != "status" || got[0].Kind != KindPage || got[1].Kind != KindCoin || got[1].Score != scoreExactCoin {
+		t.Fatalf("Expected the page, then the exact coin, got %+v", got)
+	}
+	if got[2].ID != "solana-name-service" || got[2].Hint != "SNS" {
+		t.Errorf("Expected the other coin last, got %+v", got[2])
+	}
+}
+
+func TestSearch_MatchesWordPrefixesAndKeywords(t *testing.T) {
+	for _, query := range []string{"exp csv", "download", "prices as"} {
+		got, _ := Search(query, commands, nil, 10)
+		if len(got) != 1 || got[0].ID != "export-prices" {
+			t.Errorf("Expected %q to find the export, got %+v", query, got)
+		}
+	}
+}
+
+func TestSearch_EmptyQueryListsCommands(t *testing.T) {
+	coins := &stubCoins{}
+	got, err := Search(" ", commands, coins, 2)
+	if err != nil || len(got) != 2 || coins.calls != 0 {
+		t.Errorf("Expected two commands without a coin lookup, got %+v (%v, %d calls)", got, err, coins.calls)
+	}
+}
+
+func TestSearch_KeepsCommandsWhenCoinsFail(t *testing.T) {
+	coins := &stubCoins{err: errors.New("rate limited")}
+	got, err := Search("alert", commands, coins, 10)
+	if err == nil || len(got) != 1 || got[0].ID != "add-alert" {
+		t.Errorf("Expected the action together with the lookup error, got %+v (%v)", got, err)
+	}
+}
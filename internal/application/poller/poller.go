@@ -0,0 +1,581 @@
+// Package poller periodically refreshes the prices of the tracked coins and keeps
+// the latest snapshot and a short price history in memory for the interfaces
+package poller
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/events"
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// DefaultHistorySize is the number of price points kept per coin
+const DefaultHistorySize = 120
+
+// DegradedAfter is the number of consecutive failed polls after which the provider is reported degraded
+const DegradedAfter = 3
+
+// DefaultInactiveAfter is how long the provider may go without updating a
+// coin before it is marked inactive
+const DefaultInactiveAfter = 24 * time.Hour
+
+// ErrBackingOff is returned by PollOnce while the provider asked for a pause
+var ErrBackingOff = errors.New("backing off the price provider")
+
+// retryAfter is implemented by provider errors that ask for a pause before the
+// next request, such as rate limits
+type retryAfter interface {
+	RetryAfter() time.Duration
+}
+
+// missingCoins is implemented by provider errors that tell coins the provider
+// does not know apart from failed requests
+type missingCoins interface {
+	Missing() []string
+}
+
+// PriceProvider is the source of current prices used by the poller
+type PriceProvider interface {
+	FetchCryptoPrices(cryptoIDs []string, currency models.Currency) ([]models.CryptoPrice, error)
+}
+
+// Observer is notified about poll cycles and cache lookups, e.g. to export metrics
+type Observer interface {
+	PollCompleted(tracked int, duration time.Duration, err error)
+	CacheLookup(hits, misses int)
+}
+
+// TickSink receives every price of every poll, changed or not, e.g. to build
+// candles that must not miss flat periods or depend on the lossy event bus
+type TickSink interface {
+	Enqueue(prices []models.CryptoPrice, at time.Time)
+}
+
+// Poller refreshes the tracked coins on a fixed interval
+type Poller struct {
+	provider      PriceProvider
+	interval      time.Duration
+	currency      models.Currency
+	historySize   int
+	inactiveAfter time.Duration
+	observer      Observer
+	publisher     events.Publisher
+	sink          TickSink
+	logger        *slog.Logger
+	clock         clock.Clock
+
+	mu            sync.RWMutex
+	coins         []string
+	latest        map[string]models.CryptoPrice
+	history       map[string][]models.PricePoint
+	thresholds    map[string][]decimal.Decimal
+	updatedAt     map[string]time.Time
+	inactive      map[string]bool
+	lastErr       error
+	lastSuccess   time.Time
+	lastPoll      time.Time
+	failures      int
+	degradedSince time.Time
+	resumeAt      time.Time
+	subscribers   map[chan struct{}]struct{}
+	trigger       chan struct{}
+}
+
+// New creates a poller for the given coins
+func New(provider PriceProvider, interval time.Duration, currency models.Currency, coins []string) *Poller {
+	return &Poller{
+		provider:      provider,
+		interval:      interval,
+		currency:      currency,
+		historySize:   DefaultHistorySize,
+		inactiveAfter: DefaultInactiveAfter,
+		logger:        slog.Default(),
+		clock:         clock.Real,
+		coins:         slices.Clone(coins),
+		latest:        make(map[string]models.CryptoPrice),
+		history:       make(map[string][]models.PricePoint),
+		thresholds:    make(map[string][]decimal.Decimal),
+		updatedAt:     make(map[string]time.Time),
+		inactive:      make(map[string]bool),
+		subscribers:   make(map[chan struct{}]struct{}),
+		trigger:       make(chan struct{}, 1),
+	}
+}
+
+// SetObserver registers an observer. It must be called before Run.
+func (p *Poller) SetObserver(observer Observer) {
+	p.observer = observer
+}
+
+// SetPublisher registers where price changes, threshold crossings and provider
+// health events are published. It must be called before Run.
+func (p *Poller) SetPublisher(publisher events.Publisher) {
+	p.publisher = publisher
+}
+
+// SetTickSink registers where the prices of every poll are handed over. It
+// must be called before Run.
+func (p *Poller) SetTickSink(sink TickSink) {
+	p.sink = sink
+}
+
+// SetThresholds replaces the price levels watched for a coin. A ThresholdCrossed
+// event is published whenever the price moves through one of them between two polls.
+func (p *Poller) SetThresholds(cryptoID string, levels []decimal.Decimal) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(levels) == 0 {
+		delete(p.thresholds, cryptoID)
+		return
+	}
+	p.thresholds[cryptoID] = slices.Clone(levels)
+}
+
+// SetInactiveAfter sets how long the provider may go without updating a coin
+// before it is marked inactive. It must be called before Run.
+func (p *Poller) SetInactiveAfter(d time.Duration) {
+	p.inactiveAfter = d
+}
+
+// SetLogger replaces the default logger. It must be called before Run.
+func (p *Poller) SetLogger(logger *slog.Logger) {
+	p.logger = logger
+}
+
+// SetClock replaces the system clock the poll interval, back-offs and price
+// timestamps follow. It must be called before Run.
+func (p *Poller) SetClock(c clock.Clock) {
+	p.clock = c
+}
+
+// Run polls immediately and then on every interval until the context is cancelled.
+// Adding a coin triggers an early poll so it shows up without waiting a full interval.
+func (p *Poller) Run(ctx context.Context) {
+	ticker := p.clock.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	p.PollOnce()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			p.PollOnce()
+		case <-p.trigger:
+			p.PollOnce()
+		}
+	}
+}
+
+// PollOnce fetches the tracked coins once. Prices returned alongside an error
+// (partial results) are still recorded; the cached prices of the other coins
+// are kept and flagged as stale. When the error asks for a pause, e.g. a rate
+// limit with Retry-After, polls return ErrBackingOff until it has passed.
+// Coins the provider does not know do not count as a provider failure; like
+// coins it stopped updating, they are marked inactive after a while.
+func (p *Poller) PollOnce() error {
+	defer p.polled()
+	coins := p.Coins()
+	if len(coins) == 0 {
+		return nil
+	}
+	p.mu.RLock()
+	resumeAt := p.resumeAt
+	p.mu.RUnlock()
+	if p.clock.Now().Before(resumeAt) {
+		p.logger.Debug("poll skipped", "resume_at", resumeAt)
+		return ErrBackingOff
+	}
+
+	start := p.clock.Now()
+	prices, err := p.provider.FetchCryptoPrices(coins, p.currency)
+	now := p.clock.Now().UTC()
+	duration := now.Sub(start)
+	if p.observer != nil {
+		p.observer.PollCompleted(len(coins), duration, err)
+	}
+	// The provider answered when the only failures are coins it does not know
+	var missing []string
+	var unknown missingCoins
+	if errors.As(err, &unknown) {
+		missing = unknown.Missing()
+	}
+	answered := err == nil || len(prices)+len(missing) == len(coins)
+	var pause retryAfter
+	if errors.As(err, &pause) && pause.RetryAfter() > 0 {
+		p.logger.Warn("provider asked to back off", "retry_after", pause.RetryAfter())
+		p.mu.Lock()
+		p.resumeAt = now.Add(pause.RetryAfter())
+		p.mu.Unlock()
+	}
+	if !answered {
+		p.logger.Warn("poll failed", "coins", len(coins), "fetched", len(prices), "duration", duration, "error", err)
+	} else {
+		p.logger.Debug("poll completed", "coins", len(coins), "missing", len(missing), "duration", duration)
+	}
+
+	p.mu.Lock()
+	healthErr := err
+	if answered {
+		healthErr = nil
+		p.lastSuccess = now
+	}
+	p.lastErr = healthErr
+	published := p.providerHealth(healthErr, now)
+	for _, price := range prices {
+		previous, seen := p.latest[price.ID]
+		if !seen || !previous.CurrentPrice.Equal(price.CurrentPrice) {
+			published = append(published, events.PriceUpdated{Price: price, Previous: previous.CurrentPrice, At: now})
+		}
+		if seen {
+			published = append(published, p.crossings(previous, price, now)...)
+		}
+
+		p.latest[price.ID] = price
+		points := append(p.history[price.ID], models.PricePoint{Price: price.PriceFloat(), Time: now})
+		if len(points) > p.historySize {
+			points = points[len(points)-p.historySize:]
+		}
+		p.history[price.ID] = points
+	}
+	if err != nil {
+		p.markStale(coins, prices)
+	}
+	if answered {
+		published = append(published, p.activity(coins, prices, now)...)
+	}
+	p.mu.Unlock()
+
+	if p.sink != nil && len(prices) > 0 {
+		p.sink.Enqueue(prices, now)
+	}
+	if p.publisher != nil {
+		for _, event := range published {
+			p.publisher.Publish(event)
+		}
+	}
+	p.notify()
+	return err
+}
+
+// markStale flags the cached prices of the coins a failed poll did not refresh,
+// so interfaces keep showing them as last known values. The caller must hold the lock.
+func (p *Poller) markStale(coins []string, fetched []models.CryptoPrice) {
+	for _, id := range coins {
+		if slices.ContainsFunc(fetched, func(price models.CryptoPrice) bool { return price.ID == id }) {
+			continue
+		}
+		price, ok := p.latest[id]
+		if !ok || price.Stale {
+			continue
+		}
+		price.Stale = true
+		if points := p.history[id]; len(points) > 0 {
+			since := points[len(points)-1].Time
+			price.StaleSince = &since
+		}
+		p.latest[id] = price
+	}
+}
+
+// activity records when the provider last updated each coin and marks the
+// coins it has not updated within the inactivity period, returning the
+// inactivity and reactivation events to publish. It is only called for polls
+// the provider answered, so outages do not make coins inactive. The caller must hold the lock.
+func (p *Poller) activity(coins []string, fetched []models.CryptoPrice, now time.Time) []events.Event {
+	for _, price := range fetched {
+		updated, ok := price.LastUpdatedTime()
+		if !ok {
+			updated = now
+		}
+		p.updatedAt[price.ID] = updated
+	}
+
+	var published []events.Event
+	for _, id := range coins {
+		updated, seen := p.updatedAt[id]
+		if !seen {
+			// Coins the provider never returned count from their first miss
+			p.updatedAt[id] = now
+			updated = now
+		}
+		inactive := now.Sub(updated) >= p.inactiveAfter
+		if price, ok := p.latest[id]; ok {
+			price.Inactive = inactive
+			p.latest[id] = price
+		}
+		if inactive == p.inactive[id] {
+			continue
+		}
+		if inactive {
+			p.inactive[id] = true
+			var last time.Time
+			if _, ok := p.latest[id]; ok {
+				last = updated
+			}
+			p.logger.Warn("coin inactive", "crypto", id, "last_update", last)
+			published = append(published, events.CoinInactive{CryptoID: id, LastUpdate: last, At: now})
+		} else {
+			delete(p.inactive, id)
+			p.logger.Info("coin reactivated", "crypto", id)
+			published = append(published, events.CoinReactivated{CryptoID: id, At: now})
+		}
+	}
+	return published
+}
+
+// providerHealth tracks consecutive failures and returns the degradation or
+// recovery event to publish, if any. The caller must hold the lock.
+func (p *Poller) providerHealth(err error, now time.Time) []events.Event {
+	if err != nil {
+		p.failures++
+		if p.failures != DegradedAfter {
+			return nil
+		}
+		p.degradedSince = now
+		return []events.Event{events.ProviderDegraded{Failures: p.failures, Error: err.Error(), At: now}}
+	}
+
+	p.failures = 0
+	if p.degradedSince.IsZero() {
+		return nil
+	}
+	downtime := now.Sub(p.degradedSince)
+	p.degradedSince = time.Time{}
+	return []events.Event{events.ProviderRecovered{Downtime: downtime, At: now}}
+}
+
+// crossings returns the watched levels the price moved through since the
+// previous poll. The caller must hold the lock.
+func (p *Poller) crossings(previous, current models.CryptoPrice, now time.Time) []events.Event {
+	var crossed []events.Event
+	from, to := previous.CurrentPrice, current.CurrentPrice
+	for _, level := range p.thresholds[current.ID] {
+		var direction events.Direction
+		switch {
+		case from.LessThanOrEqual(level) && to.GreaterThan(level):
+			direction = events.Up
+		case from.GreaterThanOrEqual(level) && to.LessThan(level):
+			direction = events.Down
+		default:
+			continue
+		}
+		crossed = append(crossed, events.ThresholdCrossed{
+			CryptoID:  current.ID,
+			Currency:  current.Currency,
+			Threshold: level,
+			Direction: direction,
+			Price:     to,
+			At:        now,
+		})
+	}
+	return crossed
+}
+
+// Snapshot returns the latest price of every tracked coin in tracking order.
+// Coins that have not been fetched yet are omitted.
+func (p *Poller) Snapshot() []models.CryptoPrice {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	prices := make([]models.CryptoPrice, 0, len(p.coins))
+	for _, id := range p.coins {
+		if price, ok := p.latest[id]; ok {
+			prices = append(prices, price)
+		}
+	}
+	return prices
+}
+
+// Latest returns the latest price of a single coin
+func (p *Poller) Latest(id string) (models.CryptoPrice, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	price, ok := p.latest[id]
+	return price, ok
+}
+
+// Prices returns the prices of the requested coins, serving tracked coins from
+// the latest snapshot and fetching the others from the provider
+func (p *Poller) Prices(ids []string) ([]models.CryptoPrice, error) {
+	prices := make([]models.CryptoPrice, 0, len(ids))
+	var missing []string
+
+	p.mu.RLock()
+	for _, id := range ids {
+		if price, ok := p.latest[id]; ok {
+			prices = append(prices, price)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	p.mu.RUnlock()
+
+	if p.observer != nil {
+		p.observer.CacheLookup(len(ids)-len(missing), len(missing))
+	}
+	if len(missing) == 0 {
+		return prices, nil
+	}
+
+	fetched, err := p.provider.FetchCryptoPrices(missing, p.currency)
+	return append(prices, fetched...), err
+}
+
+// History returns the recent price points of a coin, oldest first
+func (p *Poller) History(id string) []models.PricePoint {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return slices.Clone(p.history[id])
+}
+
+// Inactive returns the tracked coins the provider stopped updating, in tracking order
+func (p *Poller) Inactive() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	inactive := []string{}
+	for _, id := range p.coins {
+		if p.inactive[id] {
+			inactive = append(inactive, id)
+		}
+	}
+	return inactive
+}
+
+// LastError returns the error of the most recent poll, if any. Coins the
+// provider does not know are not counted as errors.
+func (p *Poller) LastError() error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lastErr
+}
+
+// LastSuccess returns when a poll last refreshed every tracked coin, or the
+// zero time if none has yet
+func (p *Poller) LastSuccess() time.Time {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lastSuccess
+}
+
+// LastPoll returns when a poll cycle last ended, whatever its outcome, or the
+// zero time if none has yet. A poller stuck in a provider call stops advancing it.
+func (p *Poller) LastPoll() time.Time {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lastPoll
+}
+
+func (p *Poller) polled() {
+	now := p.clock.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastPoll = now
+}
+
+// Currency returns the currency prices are fetched in
+func (p *Poller) Currency() models.Currency {
+	return p.currency
+}
+
+// Coins returns the tracked coin IDs
+func (p *Poller) Coins() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return slices.Clone(p.coins)
+}
+
+// AddCoin starts tracking a coin and requests an early poll
+func (p *Poller) AddCoin(id string) {
+	p.mu.Lock()
+	if slices.Contains(p.coins, id) {
+		p.mu.Unlock()
+		return
+	}
+	p.coins = append(p.coins, id)
+	p.mu.Unlock()
+
+	select {
+	case p.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// RemoveCoin stops tracking a coin and forgets its data
+func (p *Poller) RemoveCoin(id string) {
+	p.mu.Lock()
+	p.coins = slices.DeleteFunc(p.coins, func(c string) bool { return c == id })
+	p.forget(id)
+	p.mu.Unlock()
+
+	p.notify()
+}
+
+// SetCoins replaces the tracked coins. Data of coins that are no longer tracked
+// is forgotten, and newly added coins trigger an early poll.
+func (p *Poller) SetCoins(ids []string) {
+	p.mu.Lock()
+	added := false
+	for _, id := range ids {
+		if !slices.Contains(p.coins, id) {
+			added = true
+		}
+	}
+	for _, id := range p.coins {
+		if !slices.Contains(ids, id) {
+			p.forget(id)
+		}
+	}
+	p.coins = slices.Clone(ids)
+	p.mu.Unlock()
+
+	if added {
+		select {
+		case p.trigger <- struct{}{}:
+		default:
+		}
+	}
+	p.notify()
+}
+
+// forget drops the data of a coin that is no longer tracked. The caller must hold the lock.
+func (p *Poller) forget(id string) {
+	delete(p.latest, id)
+	delete(p.history, id)
+	delete(p.updatedAt, id)
+	delete(p.inactive, id)
+}
+
+// Subscribe returns a channel that receives a signal after every update.
+// Signals are coalesced, so slow consumers only see the latest state.
+func (p *Poller) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	p.mu.Lock()
+	p.subscribers[ch] = struct{}{}
+	p.mu.Unlock()
+
+	cancel := func() {
+		p.mu.Lock()
+		delete(p.subscribers, ch)
+		p.mu.Unlock()
+	}
+	return ch, cancel
+}
+
+func (p *Poller) notify() {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for ch := range p.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
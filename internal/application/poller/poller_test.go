@@ -0,0 +1,438 @@
+package poller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/events"
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+type fakeProvider struct {
+	prices map[string]float64
+	err    error
+	calls  int
+}
+
+func (f *fakeProvider) FetchCryptoPrices(ids []string, currency models.Currency) ([]models.CryptoPrice, error) {
+	f.calls++
+	var prices []models.CryptoPrice
+	for _, id := range ids {
+		if price, ok := f.prices[id]; ok {
+			prices = append(prices, models.CryptoPrice{ID: id, CurrentPrice: decimal.NewFromFloat(price), Currency: currency})
+		}
+	}
+	return prices, f.err
+}
+
+func TestPoller_PollOnce(t *testing.T) {
+	provider := &fakeProvider{prices: map[string]float64{"bitcoin": 50000, "ethereum": 3000}}
+	p := New(provider, time.Minute, models.USD, []string{"ethereum", "bitcoin"})
+
+	if err := p.PollOnce(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	provider.prices["bitcoin"] = 51000
+	p.PollOnce()
+
+	snapshot := p.Snapshot()
+	if len(snapshot) != 2 || snapshot[0].ID != "ethereum" {
+		t.Errorf("Expected snapshot in tracking order, got %+v", snapshot)
+	}
+
+	history := p.History("bitcoin")
+	if len(history) != 2 || history[1].Price != 51000 {
+		t.Errorf("Expected 2 history points ending at 51000, got %+v", history)
+	}
+}
+
+type recorder struct {
+	events []events.Event
+}
+
+func (r *recorder) Publish(event events.Event) { r.events = append(r.events, event) }
+
+func (r *recorder) kinds() []events.Kind {
+	var kinds []events.Kind
+	for _, e := range r.events {
+		kinds = append(kinds, e.Kind())
+	}
+	return kinds
+}
+
+func TestPoller_PublishesPriceChangesAndCrossings(t *testing.T) {
+	provider := &fakeProvider{prices: map[string]float64{"bitcoin": 69000}}
+	p := New(provider, time.Minute, models.USD, []string{"bitcoin"})
+	p.SetThresholds("bitcoin", []decimal.Decimal{decimal.NewFromInt(70000), decimal.NewFromInt(75000)})
+	rec := &recorder{}
+	p.SetPublisher(rec)
+
+	p.PollOnce()
+	p.PollOnce() // unchanged price publishes nothing
+	if len(rec.events) != 1 || rec.events[0].Kind() != events.KindPriceUpdated {
+		t.Fatalf("Expected a single price update, got %v", rec.kinds())
+	}
+
+	provider.prices["bitcoin"] = 70500
+	p.PollOnce()
+	if len(rec.events) != 3 {
+		t.Fatalf("Expected an update and a crossing, got %v", rec.kinds())
+	}
+	update := rec.events[1].(events.PriceUpdated)
+	if !update.Previous.Equal(decimal.NewFromInt(69000)) || !update.Price.CurrentPrice.Equal(decimal.NewFromInt(70500)) {
+		t.Errorf("Unexpected price update: %+v", update)
+	}
+	crossed := rec.events[2].(events.ThresholdCrossed)
+	if !crossed.Threshold.Equal(decimal.NewFromInt(70000)) || crossed.Direction != events.Up {
+		t.Errorf("Expected an upward crossing of 70000, got %+v", crossed)
+	}
+
+	provider.prices["bitcoin"] = 68000
+	p.PollOnce()
+	if crossed := rec.events[len(rec.events)-1].(events.ThresholdCrossed); crossed.Direction != events.Down {
+		t.Errorf("Expected a downward crossing, got %+v", crossed)
+	}
+}
+
+func TestPoller_PublishesProviderHealth(t *testing.T) {
+	provider := &fakeProvider{err: errors.New("rate limited")}
+	p := New(provider, time.Minute, models.USD, []string{"bitcoin"})
+	rec := &recorder{}
+	p.SetPublisher(rec)
+
+	for i := 0; i < DegradedAfter+2; i++ {
+		p.PollOnce()
+	}
+	if len(rec.events) != 1 {
+		t.Fatalf("Expected a single degradation event, got %v", rec.kinds())
+	}
+	if degraded := rec.events[0].(events.ProviderDegraded); degraded.Failures != DegradedAfter || degraded.Error != "rate limited" {
+		t.Errorf("Unexpected degradation event: %+v", degraded)
+	}
+
+	provider.err = nil
+	provider.prices = map[string]float64{"bitcoin": 50000}
+	p.PollOnce()
+	p.PollOnce()
+	if got := rec.kinds(); len(got) != 3 || got[1] != events.KindProviderRecovered || got[2] != events.KindPriceUpdated {
+		t.Errorf("Expected a recovery followed by the first price, got %v", got)
+	}
+}
+
+func TestPoller_KeepsPartialResults(t *testing.T) {
+	provider := &fakeProvider{prices: map[string]float64{"bitcoin": 50000}, err: errors.New("ethereum failed")}
+	p := New(provider, time.Minute, models.USD, []string{"bitcoin", "ethereum"})
+
+	if err := p.PollOnce(); err == nil {
+		t.Error("Expected error from provider, got nil")
+	}
+	if _, ok := p.Latest("bitcoin"); !ok {
+		t.Error("Expected bitcoin to be recorded despite the error")
+	}
+	if p.LastError() == nil {
+		t.Error("Expected last error to be recorded")
+	}
+}
+
+func TestPoller_FlagsStalePrices(t *testing.T) {
+	provider := &fakeProvider{prices: map[string]float64{"bitcoin": 50000, "ethereum": 3000}}
+	p := New(provider, time.Minute, models.USD, []string{"bitcoin", "ethereum"})
+	p.PollOnce()
+	refreshed := p.History("ethereum")[0].Time
+
+	provider.prices = map[string]float64{"bitcoin": 51000}
+	provider.err = errors.New("ethereum failed")
+	p.PollOnce()
+
+	btc, _ := p.Latest("bitcoin")
+	eth, _ := p.Latest("ethereum")
+	if btc.Stale {
+		t.Error("Expected the refreshed bitcoin price not to be stale")
+	}
+	if !eth.Stale || eth.StaleSince == nil || !eth.StaleSince.Equal(refreshed) {
+		t.Fatalf("Expected the cached ethereum price flagged stale since %v, got %+v", refreshed, eth)
+	}
+	if !eth.CurrentPrice.Equal(decimal.NewFromInt(3000)) {
+		t.Errorf("Expected the last known ethereum price, got %s", eth.CurrentPrice)
+	}
+
+	if !p.LastSuccess().Equal(refreshed) {
+		t.Errorf("Expected the last success to stay at the first poll, got %v", p.LastSuccess())
+	}
+
+	provider.prices["ethereum"] = 3100
+	provider.err = nil
+	p.PollOnce()
+	if eth, _ := p.Latest("ethereum"); eth.Stale || eth.StaleSince != nil {
+		t.Errorf("Expected a fresh price after recovery, got %+v", eth)
+	}
+	if !p.LastSuccess().After(refreshed) {
+		t.Errorf("Expected the recovery to be the last success, got %v", p.LastSuccess())
+	}
+}
+
+// rateLimited asks the poller to wait before the next request
+type rateLimited time.Duration
+
+func (r rateLimited) Error() string             { return "rate limited" }
+func (r rateLimited) RetryAfter() time.Duration { return time.Duration(r) }
+
+func TestPoller_BacksOffWhenAsked(t *testing.T) {
+	provider := &fakeProvider{prices: map[string]float64{}, err: fmt.Errorf("bitcoin: %w", rateLimited(time.Minute))}
+	p := New(provider, time.Minute, models.USD, []string{"bitcoin"})
+	clk := clock.NewFake(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	p.SetClock(clk)
+	p.PollOnce()
+
+	clk.Advance(59 * time.Second)
+	if err := p.PollOnce(); !errors.Is(err, ErrBackingOff) || provider.calls != 1 {
+		t.Fatalf("Expected the second poll to back off without a request, got %v after %d calls", err, provider.calls)
+	}
+	if !p.LastPoll().Equal(clk.Now()) {
+		t.Errorf("Expected skipped polls to count as progress, got %s", p.LastPoll())
+	}
+
+	clk.Advance(time.Second)
+	provider.err = nil
+	if err := p.PollOnce(); err != nil || provider.calls != 2 {
+		t.Errorf("Expected polling to resume, got %v after %d calls", err, provider.calls)
+	}
+	if !p.LastSuccess().Equal(clk.Now()) {
+		t.Errorf("Expected the poll to be stamped with the clock, got %s", p.LastSuccess())
+	}
+}
+
+// signalProvider reports every fetch on calls
+type signalProvider struct {
+	calls chan struct{}
+}
+
+func (s signalProvider) FetchCryptoPrices(ids []string, currency models.Currency) ([]models.CryptoPrice, error) {
+	s.calls <- struct{}{}
+	return nil, nil
+}
+
+func TestPoller_Run(t *testing.T) {
+	provider := signalProvider{calls: make(chan struct{})}
+	p := New(provider, time.Minute, models.USD, []string{"bitcoin"})
+	clk := clock.NewFake(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	p.SetClock(clk)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Run(ctx)
+
+	<-provider.calls
+	clk.BlockUntil(1)
+	clk.Advance(30 * time.Second)
+	select {
+	case <-provider.calls:
+		t.Fatal("Expected no poll before the interval")
+	default:
+	}
+	clk.Advance(30 * time.Second)
+	<-provider.calls
+}
+
+func TestPoller_HistoryIsBounded(t *testing.T) {
+	provider := &fakeProvider{prices: map[string]float64{"bitcoin": 1}}
+	p := New(provider, time.Minute, models.USD, []string{"bitcoin"})
+	p.historySize = 3
+
+	for i := 0; i < 5; i++ {
+		p.PollOnce()
+	}
+	if got := len(p.History("bitcoin")); got != 3 {
+		t.Errorf("Expected history capped at 3, got %d", got)
+	}
+}
+
+func TestPoller_AddRemoveAndSubscribe(t *testing.T) {
+	provider := &fakeProvider{prices: map[string]float64{"bitcoin": 1, "solana": 2}}
+	p := New(provider, time.Hour, models.USD, []string{"bitcoin"})
+
+	updates, cancel := p.Subscribe()
+	defer cancel()
+
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go p.Run(ctx)
+
+	<-updates // initial poll
+	p.AddCoin("solana")
+	deadline := time.After(time.Second)
+	for {
+		if _, ok := p.Latest("solana"); ok {
+			break
+		}
+		select {
+		case <-updates:
+		case <-deadline:
+			t.Fatal("Expected solana to be polled after being added")
+		}
+	}
+
+	p.RemoveCoin("bitcoin")
+	if coins := p.Coins(); len(coins) != 1 || coins[0] != "solana" {
+		t.Errorf("Expected only solana to be tracked, got %v", coins)
+	}
+	if _, ok := p.Latest("bitcoin"); ok {
+		t.Error("Expected bitcoin data to be removed")
+	}
+}
+
+func TestPoller_SetCoins(t *testing.T) {
+	provider := &fakeProvider{prices: map[string]float64{"bitcoin": 1, "ethereum": 2, "solana": 3}}
+	p := New(provider, time.Hour, models.USD, []string{"bitcoin", "ethereum"})
+	p.PollOnce()
+
+	p.SetCoins([]string{"solana", "bitcoin"})
+	if coins := p.Coins(); len(coins) != 2 || coins[0] != "solana" {
+		t.Errorf("Expected the new coin order, got %v", coins)
+	}
+	if _, ok := p.Latest("ethereum"); ok {
+		t.Error("Expected ethereum data to be forgotten")
+	}
+	if _, ok := p.Latest("bitcoin"); !ok {
+		t.Error("Expected bitcoin data to be kept")
+	}
+
+	select {
+	case <-p.trigger:
+	default:
+		t.Error("Expected adding solana to trigger an early poll")
+	}
+}
+
+type countingObserver struct {
+	polls, hits, misses int
+}
+
+func (c *countingObserver) PollCompleted(tracked int, d time.Duration, err error) { c.polls++ }
+
+func (c *countingObserver) CacheLookup(hits, misses int) {
+	c.hits += hits
+	c.misses += misses
+}
+
+func TestPoller_PricesUsesCache(t *testing.T) {
+	provider := &fakeProvider{prices: map[string]float64{"bitcoin": 50000, "dogecoin": 0.1}}
+	p := New(provider, time.Minute, models.USD, []string{"bitcoin"})
+	observer := &countingObserver{}
+	p.SetObserver(observer)
+	p.PollOnce()
+
+	prices, err := p.Prices([]string{"bitcoin", "dogecoin"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(prices) != 2 {
+		t.Fatalf("Expected 2 prices, got %d", len(prices))
+	}
+	if observer.polls != 1 || observer.hits != 1 || observer.misses != 1 {
+		t.Errorf("Expected 1 poll, 1 hit and 1 miss, got %+v", observer)
+	}
+}
+
+type missingError []string
+
+func (e missingError) Error() string     { return fmt.Sprintf("unknown coins: %v", []string(e)) }
+func (e missingError) Missing() []string { return e }
+
+type listProvider struct {
+	prices []models.CryptoPrice
+	err    error
+}
+
+func (l *listProvider) FetchCryptoPrices(ids []string, currency models.Currency) ([]models.CryptoPrice, error) {
+	return l.prices, l.err
+}
+
+func TestPoller_MarksInactiveCoins(t *testing.T) {
+	frozen := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+	provider := &listProvider{
+		prices: []models.CryptoPrice{
+			{ID: "bitcoin", CurrentPrice: decimal.NewFromInt(60000)},
+			{ID: "terra-luna", CurrentPrice: decimal.RequireFromString("0.0001"), LastUpdated: frozen},
+		},
+		err: missingError{"old-token"},
+	}
+	p := New(provider, time.Minute, models.USD, []string{"bitcoin", "terra-luna", "old-token"})
+	p.SetInactiveAfter(24 * time.Hour)
+	rec := &recorder{}
+	p.SetPublisher(rec)
+
+	for i := 0; i < DegradedAfter; i++ {
+		if err := p.PollOnce(); err == nil {
+			t.Fatal("Expected the provider error to be returned")
+		}
+	}
+	if p.LastError() != nil || p.LastSuccess().IsZero() {
+		t.Errorf("Expected unknown coins not to count as provider failures, got %v", p.LastError())
+	}
+	if got := p.Inactive(); len(got) != 1 || got[0] != "terra-luna" {
+		t.Errorf("Expected the frozen coin to be inactive, got %v", got)
+	}
+	if price, _ := p.Latest("terra-luna"); !price.Inactive {
+		t.Error("Expected the frozen price to be flagged inactive")
+	}
+
+	// An unknown coin becomes inactive once it has been missing for the period
+	p.mu.Lock()
+	p.updatedAt["old-token"] = time.Now().Add(-25 * time.Hour)
+	p.mu.Unlock()
+	provider.prices[1].LastUpdated = time.Now().UTC().Format(time.RFC3339)
+	p.PollOnce()
+	if got := p.Inactive(); len(got) != 1 || got[0] != "old-token" {
+		t.Errorf("Expected only the unknown coin to be inactive, got %v", got)
+	}
+
+	var kinds []events.Kind
+	for _, e := range rec.events {
+		switch ev := e.(type) {
+		case events.CoinInactive:
+			kinds = append(kinds, e.Kind())
+			if ev.CryptoID == "old-token" && !ev.LastUpdate.IsZero() {
+				t.Errorf("Expected no last update for a coin never returned, got %s", ev.LastUpdate)
+			}
+		case events.CoinReactivated:
+			kinds = append(kinds, e.Kind())
+		case events.ProviderDegraded:
+			t.Error("Expected no degradation for unknown coins")
+		}
+	}
+	if fmt.Sprint(kinds) != "[coin_inactive coin_reactivated coin_inactive]" {
+		t.Errorf("Unexpected activity events: %v", kinds)
+	}
+
+	p.SetCoins([]string{"bitcoin"})
+	if got := p.Inactive(); len(got) != 0 {
+		t.Errorf("Expected untracked coins to be forgotten, got %v", got)
+	}
+}
+
+type tickSink struct {
+	polls [][]models.CryptoPrice
+}
+
+func (s *tickSink) Enqueue(prices []models.CryptoPrice, at time.Time) {
+	s.polls = append(s.polls, prices)
+}
+
+func TestPoller_HandsEveryPollToTheTickSink(t *testing.T) {
+	provider := &fakeProvider{prices: map[string]float64{"bitcoin": 69000}}
+	p := New(provider, time.Minute, models.USD, []string{"bitcoin"})
+	sink := &tickSink{}
+	p.SetTickSink(sink)
+
+	p.PollOnce()
+	p.PollOnce() // unchanged, but candles still need the tick
+	if len(sink.polls) != 2 || len(sink.polls[1]) != 1 || sink.polls[1][0].ID != "bitcoin" {
+		t.Errorf("Expected both polls handed over, got %+v", sink.polls)
+	}
+}
@@ -0,0 +1,109 @@
+package portfolio
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// lowConfidenceSwing is the move between two daily prices above which an
+// interpolated price is flagged, since the intraday path is unknown
+const lowConfidenceSwing = 0.05
+
+// PriceLookup returns the price of a coin at 00:00 UTC of the day containing at
+type PriceLookup interface {
+	PriceAt(cryptoID string, currency models.Currency, at time.Time) (float64, error)
+}
+
+// Confidence rates how closely a backfilled price should match the real fill
+type Confidence string
+
+// Confidence levels
+const (
+	ConfidenceHigh Confidence = "high"
+	ConfidenceLow  Confidence = "low"
+)
+
+// BackfillEntry records the price filled into a transaction, or why none was
+type BackfillEntry struct {
+	TransactionID string     `json:"transaction_id"`
+	CryptoID      string     `json:"crypto_id"`
+	Timestamp     time.Time  `json:"timestamp"`
+	Price         float64    `json:"price,omitempty"`
+	Confidence    Confidence `json:"confidence,omitempty"`
+	Note          string     `json:"note,omitempty"`
+	Err           error      `json:"-"`
+}
+
+// BackfillReport lists every transaction the backfill touched
+type BackfillReport struct {
+	Filled []BackfillEntry `json:"filled"`
+	Failed []BackfillEntry `json:"failed"`
+}
+
+// LowConfidence returns the filled entries that should be reviewed
+func (r *BackfillReport) LowConfidence() []BackfillEntry {
+	var flagged []BackfillEntry
+	for _, e := range r.Filled {
+		if e.Confidence == ConfidenceLow {
+			flagged = append(flagged, e)
+		}
+	}
+	return flagged
+}
+
+// Backfill fills the price of every trade or income that has none with the
+// historical price at its timestamp. The price is interpolated between the
+// daily prices around the timestamp; entries are flagged as low confidence when
+// only one daily price is known or the coin moved more than 5% that day.
+// Transactions whose lookup fails are left unchanged and listed as failed.
+func Backfill(transactions []models.Transaction, prices PriceLookup) ([]models.Transaction, BackfillReport) {
+	filled := make([]models.Transaction, len(transactions))
+	copy(filled, transactions)
+
+	var report BackfillReport
+	for i, tx := range filled {
+		if tx.Price.IsPositive() || tx.IsMigration() {
+			continue
+		}
+		entry := BackfillEntry{TransactionID: tx.ID, CryptoID: tx.CryptoID, Timestamp: tx.Timestamp}
+
+		price, confidence, note, err := estimatePrice(prices, tx.CryptoID, tx.PriceCurrency(), tx.Timestamp)
+		if err != nil {
+			entry.Err = err
+			entry.Note = err.Error()
+			report.Failed = append(report.Failed, entry)
+			continue
+		}
+
+		filled[i].Price = decimal.NewFromFloat(price)
+		filled[i].Currency = tx.PriceCurrency()
+		entry.Price, entry.Confidence, entry.Note = price, confidence, note
+		report.Filled = append(report.Filled, entry)
+	}
+	return filled, report
+}
+
+func estimatePrice(prices PriceLookup, cryptoID string, currency models.Currency, at time.Time) (float64, Confidence, string, error) {
+	day := at.UTC().Truncate(24 * time.Hour)
+	open, err := prices.PriceAt(cryptoID, currency, day)
+	if err != nil {
+		return 0, "", "", fmt.Errorf("no price on %s: %w", day.Format(time.DateOnly), err)
+	}
+
+	next, err := prices.PriceAt(cryptoID, currency, day.AddDate(0, 0, 1))
+	if err != nil || open <= 0 {
+		return open, ConfidenceLow, "only the daily opening price is known", nil
+	}
+
+	elapsed := at.Sub(day).Hours() / 24
+	price := open + (next-open)*elapsed
+	if swing := math.Abs(next/open - 1); swing > lowConfidenceSwing {
+		return price, ConfidenceLow, fmt.Sprintf("price moved %.1f%% that day", swing*100), nil
+	}
+	return price, ConfidenceHigh, "", nil
+}
@@ -0,0 +1,66 @@
+package portfolio
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// dailyPrices maps "id/2006-01-02" to the price at 00:00 UTC of that day
+type dailyPrices map[string]float64
+
+var errNoPrice = errors.New("no price")
+
+func (d dailyPrices) PriceAt(cryptoID string, currency models.Currency, at time.Time) (float64, error) {
+	price, ok := d[cryptoID+"/"+at.UTC().Format(time.DateOnly)]
+	if !ok {
+		return 0, errNoPrice
+	}
+	return price, nil
+}
+
+func TestBackfill(t *testing.T) {
+	prices := dailyPrices{
+		"bitcoin/2024-01-01":  40000,
+		"bitcoin/2024-01-02":  41000,
+		"solana/2024-01-01":   100,
+		"solana/2024-01-02":   120,
+		"ethereum/2024-01-01": 2300,
+	}
+	noon := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	transactions := []models.Transaction{
+		{ID: "priced", CryptoID: "bitcoin", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(39000), Timestamp: noon},
+		{ID: "btc", CryptoID: "bitcoin", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1), Timestamp: noon},
+		{ID: "sol", CryptoID: "solana", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1), Timestamp: noon},
+		{ID: "eth", CryptoID: "ethereum", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1), Timestamp: noon},
+		{ID: "new", CryptoID: "newcoin", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1), Timestamp: noon},
+	}
+
+	filled, report := Backfill(transactions, prices)
+
+	if !filled[0].Price.Equal(decimal.NewFromInt(39000)) {
+		t.Errorf("Expected priced transaction to be untouched, got %s", filled[0].Price)
+	}
+	if !almostEqual(filled[1].Price, 40500) || filled[1].Currency != models.USD {
+		t.Errorf("Expected price interpolated to 40500 USD, got %s %s", filled[1].Price, filled[1].Currency)
+	}
+	if !transactions[1].Price.IsZero() {
+		t.Error("Expected the input transactions to be unchanged")
+	}
+
+	if len(report.Filled) != 3 || len(report.Failed) != 1 || report.Failed[0].TransactionID != "new" {
+		t.Fatalf("Unexpected report: %+v", report)
+	}
+	if !errors.Is(report.Failed[0].Err, errNoPrice) {
+		t.Errorf("Expected the lookup error to be kept, got %v", report.Failed[0].Err)
+	}
+
+	flagged := report.LowConfidence()
+	if len(flagged) != 2 || flagged[0].TransactionID != "sol" || flagged[1].TransactionID != "eth" {
+		t.Errorf("Expected the volatile and the single-price entries to be flagged, got %+v", flagged)
+	}
+}
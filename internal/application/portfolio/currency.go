@@ -0,0 +1,46 @@
+package portfolio
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// RateConverter converts fiat amounts using the exchange rate in effect at a given time
+type RateConverter interface {
+	ConvertAt(amount decimal.Decimal, from, to models.Currency, at time.Time) (decimal.Decimal, error)
+}
+
+// InCurrency returns a copy of the ledger with every price and fiat fee converted
+// to the reporting currency using the exchange rate of each transaction date.
+// Cost basis and P&L computed from the result are therefore historically accurate.
+func (l *Ledger) InCurrency(target models.Currency, converter RateConverter) (*Ledger, error) {
+	converted := &Ledger{transactions: make([]models.Transaction, len(l.transactions))}
+	for i, tx := range l.transactions {
+		if tx.IsMigration() {
+			// Migrations carry no fiat amounts
+			converted.transactions[i] = tx
+			continue
+		}
+		price, err := converter.ConvertAt(tx.Price, tx.PriceCurrency(), target, tx.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert price of transaction %q: %w", tx.ID, err)
+		}
+
+		if tx.Fee.Amount.IsPositive() && !tx.FeeInCrypto() {
+			fee, err := converter.ConvertAt(tx.Fee.Amount, tx.FeeCurrency(), target, tx.Timestamp)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert fee of transaction %q: %w", tx.ID, err)
+			}
+			tx.Fee = models.Fee{Amount: fee, Currency: string(target)}
+		}
+
+		tx.Price = price
+		tx.Currency = target
+		converted.transactions[i] = tx
+	}
+	return converted, nil
+}
@@ -0,0 +1,64 @@
+package portfolio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// staticConverter uses a fixed USD->BRL rate per year
+type staticConverter map[int]float64
+
+func (s staticConverter) ConvertAt(amount decimal.Decimal, from, to models.Currency, at time.Time) (decimal.Decimal, error) {
+	rates, _ := models.NewExchangeRates(models.USD, map[models.Currency]float64{
+		models.USD: 1,
+		models.BRL: s[at.Year()],
+	})
+	return rates.ConvertDecimal(amount, from, to)
+}
+
+func TestLedger_InCurrency(t *testing.T) {
+	ledger, err := NewLedger([]models.Transaction{
+		{
+			ID: "1", CryptoID: "bitcoin", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(20000),
+			Currency: models.USD, Fee: models.Fee{Amount: decimal.NewFromInt(10), Currency: "usd"}, Timestamp: date(2023, 1, 1),
+		},
+		{
+			ID: "2", CryptoID: "bitcoin", Type: models.TransactionSell, Quantity: decimal.RequireFromString("0.9999"), Price: decimal.NewFromInt(150000),
+			Currency: models.BRL, Fee: models.Fee{Amount: decimal.NewFromFloat(0.0001), Currency: "bitcoin"}, Timestamp: date(2024, 1, 1),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	converted, err := ledger.InCurrency(models.BRL, staticConverter{2023: 5, 2024: 6})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	txs := converted.Transactions()
+	if !almostEqual(txs[0].Price, 100000) || !almostEqual(txs[0].Fee.Amount, 50) {
+		t.Errorf("Expected buy converted at 2023 rate, got price %s fee %s", txs[0].Price, txs[0].Fee.Amount)
+	}
+	if !txs[1].Price.Equal(decimal.NewFromInt(150000)) || txs[1].Fee.Currency != "bitcoin" {
+		t.Errorf("Expected BRL sell to be unchanged, got %+v", txs[1])
+	}
+
+	positions, err := converted.Positions()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Proceeds 149985 - 100050 cost of the whole position, the fee included
+	if !almostEqual(positions["bitcoin"].RealizedPnL, 49935) {
+		t.Errorf("Expected realized P&L of 49935 BRL, got %s", positions["bitcoin"].RealizedPnL)
+	}
+
+	// The original ledger must not be modified
+	if !ledger.Transactions()[0].Price.Equal(decimal.NewFromInt(20000)) {
+		t.Error("Expected original ledger to be unchanged")
+	}
+}
@@ -0,0 +1,217 @@
+// Package portfolio turns a transaction ledger into positions and P&L figures
+package portfolio
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Position is the aggregated state of a single coin after replaying the ledger.
+// CostBasis and RealizedPnL are net of fees. Income is the value of the coins
+// received as income on receipt; it is part of the cost basis but never of the P&L.
+type Position struct {
+	CryptoID    string          `json:"crypto_id"`
+	Quantity    decimal.Decimal `json:"quantity"`
+	CostBasis   decimal.Decimal `json:"cost_basis"`
+	RealizedPnL decimal.Decimal `json:"realized_pnl"`
+	FeesPaid    decimal.Decimal `json:"fees_paid"`
+	Income      decimal.Decimal `json:"income"`
+}
+
+// IncomeEntry is a receipt of coins as income valued at the price on receipt
+type IncomeEntry struct {
+	TransactionID string                 `json:"transaction_id"`
+	CryptoID      string                 `json:"crypto_id"`
+	Type          models.TransactionType `json:"type"`
+	Exchange      string                 `json:"exchange"`
+	Quantity      decimal.Decimal        `json:"quantity"`
+	Value         decimal.Decimal        `json:"value"`
+	ReceivedAt    time.Time              `json:"received_at"`
+}
+
+// AverageCost returns the cost basis per unit currently held
+func (p *Position) AverageCost() decimal.Decimal {
+	if p.Quantity.IsZero() {
+		return decimal.Zero
+	}
+	return p.CostBasis.Div(p.Quantity)
+}
+
+// Ledger holds transactions in chronological order
+type Ledger struct {
+	transactions []models.Transaction
+}
+
+// NewLedger validates the transactions and orders them by timestamp
+func NewLedger(transactions []models.Transaction) (*Ledger, error) {
+	l := &Ledger{}
+	for _, tx := range transactions {
+		if err := l.Add(tx); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// Add validates a transaction and inserts it keeping chronological order
+func (l *Ledger) Add(tx models.Transaction) error {
+	if err := tx.Validate(); err != nil {
+		return fmt.Errorf("invalid transaction %q: %w", tx.ID, err)
+	}
+	i := sort.Search(len(l.transactions), func(i int) bool {
+		return l.transactions[i].Timestamp.After(tx.Timestamp)
+	})
+	l.transactions = append(l.transactions, models.Transaction{})
+	copy(l.transactions[i+1:], l.transactions[i:])
+	l.transactions[i] = tx
+	return nil
+}
+
+// Transactions returns a copy of the ordered transactions
+func (l *Ledger) Transactions() []models.Transaction {
+	return append([]models.Transaction(nil), l.transactions...)
+}
+
+// feeValue returns the fee of a trade in the currency of its price. A fee paid
+// in another fiat currency cannot be added to the price without a rate, so the
+// ledger has to be converted with InCurrency first.
+func feeValue(tx models.Transaction) (decimal.Decimal, error) {
+	if tx.Fee.Amount.IsPositive() && tx.FeeCurrency() != tx.PriceCurrency() {
+		return decimal.Zero, fmt.Errorf("transaction %q pays its fee in %s but is priced in %s; convert the ledger first",
+			tx.ID, tx.FeeCurrency(), tx.PriceCurrency())
+	}
+	return tx.FeeValue(), nil
+}
+
+// Positions replays the ledger using the average cost method. Buy fees are
+// added to the cost basis, or reduce the quantity received when paid in the
+// coin bought, and sell fees are deducted from the proceeds, or take coins on
+// top of the ones sold when paid in the coin, so the realized P&L is always
+// net of fees. Income is added at its value on receipt. A migration moves the
+// quantity and cost basis to the target coin; the realized P&L and fees stay
+// with the coin they were made in.
+func (l *Ledger) Positions() (map[string]*Position, error) {
+	positions := make(map[string]*Position)
+	position := func(id string) *Position {
+		pos, ok := positions[id]
+		if !ok {
+			pos = &Position{CryptoID: id}
+			positions[id] = pos
+		}
+		return pos
+	}
+	for _, tx := range l.transactions {
+		pos := position(tx.CryptoID)
+		if tx.IsMigration() {
+			quantity, cost := pos.Quantity.Mul(*tx.Ratio), pos.CostBasis
+			pos.Quantity, pos.CostBasis = decimal.Zero, decimal.Zero
+			target := position(tx.Target())
+			target.Quantity = target.Quantity.Add(quantity)
+			target.CostBasis = target.CostBasis.Add(cost)
+			continue
+		}
+
+		fee, err := feeValue(tx)
+		if err != nil {
+			return nil, err
+		}
+		pos.FeesPaid = pos.FeesPaid.Add(fee)
+
+		switch {
+		case tx.IsIncome():
+			pos.Quantity = pos.Quantity.Add(tx.Quantity)
+			pos.CostBasis = pos.CostBasis.Add(tx.Value())
+			pos.Income = pos.Income.Add(tx.Value())
+		case tx.Type == models.TransactionBuy:
+			received, cost := tx.Quantity, tx.Value().Add(fee)
+			if tx.FeeInCrypto() {
+				// The exchange kept part of the coins: the fee is paid by receiving
+				// fewer of them, so its value is not added to the cost again
+				received, cost = received.Sub(tx.Fee.Amount), tx.Value()
+			}
+			pos.Quantity = pos.Quantity.Add(received)
+			pos.CostBasis = pos.CostBasis.Add(cost)
+		case tx.Type == models.TransactionSell:
+			disposed := tx.Disposed()
+			if disposed.GreaterThan(pos.Quantity) {
+				return nil, fmt.Errorf("transaction %q sells %s %s but only %s is held",
+					tx.ID, disposed, tx.CryptoID, pos.Quantity)
+			}
+			// Selling the whole position releases the whole cost basis, so no division remainder is left behind
+			soldCost := pos.CostBasis
+			if disposed.LessThan(pos.Quantity) {
+				soldCost = pos.CostBasis.Mul(disposed).Div(pos.Quantity)
+			}
+			proceeds := tx.Value().Sub(fee)
+			if tx.FeeInCrypto() {
+				// The fee is paid with coins on top of the ones sold, so it is
+				// charged by releasing their cost basis, not from the proceeds
+				proceeds = tx.Value()
+			}
+			pos.RealizedPnL = pos.RealizedPnL.Add(proceeds.Sub(soldCost))
+			pos.CostBasis = pos.CostBasis.Sub(soldCost)
+			pos.Quantity = pos.Quantity.Sub(disposed)
+		}
+	}
+	return positions, nil
+}
+
+// Income returns every receipt of coins as income in chronological order
+func (l *Ledger) Income() []IncomeEntry {
+	var income []IncomeEntry
+	for _, tx := range l.transactions {
+		if !tx.IsIncome() {
+			continue
+		}
+		income = append(income, IncomeEntry{
+			TransactionID: tx.ID,
+			CryptoID:      tx.CryptoID,
+			Type:          tx.Type,
+			Exchange:      tx.Exchange,
+			Quantity:      tx.Quantity,
+			Value:         tx.Value(),
+			ReceivedAt:    tx.Timestamp,
+		})
+	}
+	return income
+}
+
+// TotalFees returns the fiat value of every fee in the ledger
+func (l *Ledger) TotalFees() decimal.Decimal {
+	total := decimal.Zero
+	for _, tx := range l.transactions {
+		if !tx.IsMigration() {
+			total = total.Add(tx.FeeValue())
+		}
+	}
+	return total
+}
+
+// FeesByExchange returns the total fees paid on each exchange
+func (l *Ledger) FeesByExchange() map[string]decimal.Decimal {
+	fees := make(map[string]decimal.Decimal)
+	for _, tx := range l.transactions {
+		if !tx.IsMigration() {
+			fees[tx.Exchange] = fees[tx.Exchange].Add(tx.FeeValue())
+		}
+	}
+	return fees
+}
+
+// FeesByYear returns the total fees paid in each calendar year (UTC)
+func (l *Ledger) FeesByYear() map[int]decimal.Decimal {
+	fees := make(map[int]decimal.Decimal)
+	for _, tx := range l.transactions {
+		if tx.IsMigration() {
+			continue
+		}
+		year := tx.Timestamp.UTC().Year()
+		fees[year] = fees[year].Add(tx.FeeValue())
+	}
+	return fees
+}
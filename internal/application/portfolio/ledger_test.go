@@ -0,0 +1,180 @@
+package portfolio
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+}
+
+func almostEqual(a decimal.Decimal, b float64) bool {
+	return math.Abs(a.InexactFloat64()-b) < 1e-9
+}
+
+func sampleLedger(t *testing.T) *Ledger {
+	t.Helper()
+	ledger, err := NewLedger([]models.Transaction{
+		{
+			ID: "3", CryptoID: "bitcoin", Type: models.TransactionSell, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(30000),
+			Fee: models.Fee{Amount: decimal.NewFromInt(30), Currency: "usd"}, Exchange: "kraken", Timestamp: date(2024, 3, 1),
+		},
+		{
+			ID: "1", CryptoID: "bitcoin", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(20000),
+			Fee: models.Fee{Amount: decimal.NewFromInt(20), Currency: "usd"}, Exchange: "binance", Timestamp: date(2023, 1, 1),
+		},
+		{
+			ID: "2", CryptoID: "bitcoin", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(24000),
+			Fee: models.Fee{Amount: decimal.NewFromFloat(0.001), Currency: "bitcoin"}, Exchange: "binance", Timestamp: date(2023, 6, 1),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error building ledger: %v", err)
+	}
+	return ledger
+}
+
+func TestLedger_OrdersTransactions(t *testing.T) {
+	ledger := sampleLedger(t)
+	txs := ledger.Transactions()
+	if txs[0].ID != "1" || txs[1].ID != "2" || txs[2].ID != "3" {
+		t.Errorf("Expected chronological order 1,2,3, got %s,%s,%s", txs[0].ID, txs[1].ID, txs[2].ID)
+	}
+}
+
+func TestLedger_PositionsIncludeFees(t *testing.T) {
+	positions, err := sampleLedger(t).Positions()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	btc := positions["bitcoin"]
+	// Cost: 20000 + 20 + 24000 = 44020 for 1.999 BTC; the 0.001 BTC fee is paid in coins
+	avgCost := 44020.0 / 1.999
+	expectedPnL := 30000 - 30 - avgCost
+	if !almostEqual(btc.RealizedPnL, expectedPnL) {
+		t.Errorf("Expected realized P&L %f, got %s", expectedPnL, btc.RealizedPnL)
+	}
+	if !almostEqual(btc.Quantity, 0.999) {
+		t.Errorf("Expected remaining quantity 0.999, got %s", btc.Quantity)
+	}
+	if !almostEqual(btc.FeesPaid, 74) {
+		t.Errorf("Expected fees paid 74, got %s", btc.FeesPaid)
+	}
+}
+
+func TestLedger_MicroCapPositionsAreExact(t *testing.T) {
+	price := decimal.RequireFromString("0.00001234567891234")
+	var transactions []models.Transaction
+	for i := 1; i <= 10; i++ {
+		transactions = append(transactions, models.Transaction{
+			ID: fmt.Sprint(i), CryptoID: "pepe", Type: models.TransactionBuy,
+			Quantity: decimal.RequireFromString("0.1"), Price: price, Timestamp: date(2024, 1, i),
+		})
+	}
+	transactions = append(transactions, models.Transaction{
+		ID: "sell", CryptoID: "pepe", Type: models.TransactionSell,
+		Quantity: decimal.NewFromInt(1), Price: price.Mul(decimal.NewFromInt(2)), Timestamp: date(2024, 2, 1),
+	})
+	ledger, err := NewLedger(transactions)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Ten float64 buys of 0.1 add up to 0.9999999999999999 and the sell would be rejected
+	positions, err := ledger.Positions()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pepe := positions["pepe"]
+	if !pepe.Quantity.IsZero() || !pepe.CostBasis.IsZero() {
+		t.Errorf("Expected a closed position, got quantity %s and cost %s", pepe.Quantity, pepe.CostBasis)
+	}
+	if !pepe.RealizedPnL.Equal(price) {
+		t.Errorf("Expected realized P&L of exactly %s, got %s", price, pepe.RealizedPnL)
+	}
+}
+
+func TestLedger_OversellIsRejected(t *testing.T) {
+	ledger, _ := NewLedger([]models.Transaction{
+		{ID: "1", CryptoID: "ethereum", Type: models.TransactionSell, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(3000), Timestamp: date(2024, 1, 1)},
+	})
+	if _, err := ledger.Positions(); err == nil {
+		t.Error("Expected error selling more than held, got nil")
+	}
+}
+
+func TestLedger_RejectsFeesInAnotherCurrency(t *testing.T) {
+	ledger, _ := NewLedger([]models.Transaction{
+		{
+			ID: "1", CryptoID: "bitcoin", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(50000),
+			Currency: models.BRL, Fee: models.Fee{Amount: decimal.NewFromInt(10), Currency: "usd"}, Timestamp: date(2024, 1, 1),
+		},
+	})
+	if _, err := ledger.Positions(); err == nil {
+		t.Error("Expected a USD fee on a BRL trade to be rejected without conversion")
+	}
+	if _, err := ledger.Disposals(FIFO); err == nil {
+		t.Error("Expected a USD fee on a BRL trade to be rejected by the lots without conversion")
+	}
+	converted, err := ledger.InCurrency(models.BRL, staticConverter{2024: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if positions, err := converted.Positions(); err != nil || !positions["bitcoin"].CostBasis.Equal(decimal.NewFromInt(50050)) {
+		t.Errorf("Expected the converted fee in the cost basis, got %+v, %v", positions["bitcoin"], err)
+	}
+}
+
+func TestLedger_FeeReports(t *testing.T) {
+	ledger := sampleLedger(t)
+
+	byExchange := ledger.FeesByExchange()
+	if !almostEqual(byExchange["binance"], 44) || !almostEqual(byExchange["kraken"], 30) {
+		t.Errorf("Unexpected fees by exchange: %v", byExchange)
+	}
+
+	byYear := ledger.FeesByYear()
+	if !almostEqual(byYear[2023], 44) || !almostEqual(byYear[2024], 30) {
+		t.Errorf("Unexpected fees by year: %v", byYear)
+	}
+
+	if !almostEqual(ledger.TotalFees(), 74) {
+		t.Errorf("Expected total fees 74, got %s", ledger.TotalFees())
+	}
+}
+
+func TestLedger_Income(t *testing.T) {
+	ledger, err := NewLedger([]models.Transaction{
+		{ID: "b1", CryptoID: "ethereum", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(2000), Timestamp: date(2024, 1, 1)},
+		{ID: "r1", CryptoID: "ethereum", Type: models.TransactionStakingReward, Quantity: decimal.NewFromFloat(0.05), Price: decimal.NewFromInt(3000), Timestamp: date(2024, 2, 1)},
+		{ID: "a1", CryptoID: "arbitrum", Type: models.TransactionAirdrop, Quantity: decimal.NewFromInt(100), Timestamp: date(2024, 3, 1)},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	positions, err := ledger.Positions()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	eth := positions["ethereum"]
+	if !eth.Quantity.Equal(decimal.NewFromFloat(1.05)) || !eth.CostBasis.Equal(decimal.NewFromInt(2150)) || !eth.Income.Equal(decimal.NewFromInt(150)) {
+		t.Errorf("Expected the reward added at its value on receipt, got %+v", eth)
+	}
+	if !eth.RealizedPnL.IsZero() {
+		t.Errorf("Expected income to stay out of the P&L, got %s", eth.RealizedPnL)
+	}
+
+	income := ledger.Income()
+	if len(income) != 2 || income[0].Type != models.TransactionStakingReward || !income[1].Value.IsZero() {
+		t.Errorf("Unexpected income entries: %+v", income)
+	}
+}
@@ -0,0 +1,196 @@
+package portfolio
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// CostBasisMethod selects which acquisition lots a sale is matched against
+type CostBasisMethod string
+
+const (
+	// FIFO sells the oldest lots first
+	FIFO CostBasisMethod = "fifo"
+	// LIFO sells the newest lots first
+	LIFO CostBasisMethod = "lifo"
+	// AverageCost values every unit at the average cost of the position
+	AverageCost CostBasisMethod = "average"
+)
+
+// ParseCostBasisMethod validates a method name, defaulting to FIFO when empty
+func ParseCostBasisMethod(name string) (CostBasisMethod, error) {
+	switch CostBasisMethod(name) {
+	case "":
+		return FIFO, nil
+	case FIFO, LIFO, AverageCost:
+		return CostBasisMethod(name), nil
+	default:
+		return "", fmt.Errorf("unknown cost basis method: %q", name)
+	}
+}
+
+// lot is a quantity of coins acquired at a given total cost (fees included).
+// Keeping the total instead of a unit cost means a lot sold in full releases
+// exactly what was paid for it.
+type lot struct {
+	quantity   decimal.Decimal
+	cost       decimal.Decimal
+	acquiredAt time.Time
+}
+
+// costOf returns the cost of part of the lot
+func (l lot) costOf(quantity decimal.Decimal) decimal.Decimal {
+	if quantity.Equal(l.quantity) {
+		return l.cost
+	}
+	return l.cost.Mul(quantity).Div(l.quantity)
+}
+
+// Disposal is the portion of a sale matched against a single acquisition lot.
+// Proceeds are net of the sale fee and CostBasis includes the purchase fee.
+type Disposal struct {
+	TransactionID string          `json:"transaction_id"`
+	CryptoID      string          `json:"crypto_id"`
+	Exchange      string          `json:"exchange"`
+	Quantity      decimal.Decimal `json:"quantity"`
+	Proceeds      decimal.Decimal `json:"proceeds"`
+	CostBasis     decimal.Decimal `json:"cost_basis"`
+	AcquiredAt    time.Time       `json:"acquired_at"`
+	DisposedAt    time.Time       `json:"disposed_at"`
+}
+
+// Gain returns the realized gain (or loss when negative) of the disposal
+func (d Disposal) Gain() decimal.Decimal {
+	return d.Proceeds.Sub(d.CostBasis)
+}
+
+// HoldingPeriod returns how long the disposed coins were held
+func (d Disposal) HoldingPeriod() time.Duration {
+	return d.DisposedAt.Sub(d.AcquiredAt)
+}
+
+// Disposals replays the ledger and matches every sale against acquisition lots
+// using the given cost-basis method. With AverageCost, lots are still consumed
+// oldest first so holding periods remain meaningful. Migrated lots keep their
+// cost and acquisition date, so gains after a swap or redenomination are
+// measured against what was originally paid.
+func (l *Ledger) Disposals(method CostBasisMethod) ([]Disposal, error) {
+	lots := make(map[string][]lot)
+	var disposals []Disposal
+
+	for _, tx := range l.transactions {
+		if _, err := feeValue(tx); err != nil {
+			return nil, err
+		}
+		switch tx.Type {
+		case models.TransactionMigrate:
+			migrated := lots[tx.CryptoID]
+			delete(lots, tx.CryptoID)
+			for i := range migrated {
+				migrated[i].quantity = migrated[i].quantity.Mul(*tx.Ratio)
+			}
+			// Lots stay in acquisition order so FIFO and LIFO keep matching them chronologically
+			merged := append(lots[tx.Target()], migrated...)
+			sort.SliceStable(merged, func(i, j int) bool { return merged[i].acquiredAt.Before(merged[j].acquiredAt) })
+			lots[tx.Target()] = merged
+		case models.TransactionBuy:
+			received, cost := tx.Quantity, tx.Value().Add(tx.FeeValue())
+			if tx.FeeInCrypto() {
+				// A fee kept in coins is paid by receiving fewer of them, not on top of the cost
+				received, cost = received.Sub(tx.Fee.Amount), tx.Value()
+			}
+			lots[tx.CryptoID] = append(lots[tx.CryptoID], lot{
+				quantity:   received,
+				cost:       cost,
+				acquiredAt: tx.Timestamp,
+			})
+		case models.TransactionAirdrop, models.TransactionStakingReward, models.TransactionInterest:
+			// Income lots cost their value on receipt, so only later price moves are capital gains
+			lots[tx.CryptoID] = append(lots[tx.CryptoID], lot{
+				quantity:   tx.Quantity,
+				cost:       tx.Value(),
+				acquiredAt: tx.Timestamp,
+			})
+		case models.TransactionSell:
+			matched, remaining, err := matchLots(lots[tx.CryptoID], tx, method)
+			if err != nil {
+				return nil, err
+			}
+			lots[tx.CryptoID] = remaining
+			disposals = append(disposals, matched...)
+		}
+	}
+	return disposals, nil
+}
+
+// matchLots consumes lots to cover a sale and returns the resulting disposals and leftover lots
+func matchLots(open []lot, tx models.Transaction, method CostBasisMethod) ([]Disposal, []lot, error) {
+	held, totalCost := decimal.Zero, decimal.Zero
+	for _, l := range open {
+		held = held.Add(l.quantity)
+		totalCost = totalCost.Add(l.cost)
+	}
+	toSell := tx.Disposed()
+	if toSell.GreaterThan(held) {
+		return nil, nil, fmt.Errorf("transaction %q sells %s %s but only %s is held",
+			tx.ID, toSell, tx.CryptoID, held)
+	}
+
+	// The whole position valued at its average cost, sold off proportionally
+	average := lot{quantity: held, cost: totalCost}
+	// Coins paid as the fee are disposed of with the ones sold, their cost
+	// basis standing for the fee, so the proceeds are spread over both
+	sale := lot{quantity: toSell, cost: tx.Value().Sub(tx.FeeValue())}
+	if tx.FeeInCrypto() {
+		sale.cost = tx.Value()
+	}
+
+	remaining := append([]lot(nil), open...)
+	var disposals []Disposal
+	for toSell.IsPositive() && len(remaining) > 0 {
+		i := 0
+		if method == LIFO {
+			i = len(remaining) - 1
+		}
+
+		qty := decimal.Min(remaining[i].quantity, toSell)
+		cost := remaining[i].costOf(qty)
+		if method == AverageCost {
+			cost = average.costOf(qty)
+			average = lot{quantity: average.quantity.Sub(qty), cost: average.cost.Sub(cost)}
+		}
+		proceeds := sale.costOf(qty)
+		sale = lot{quantity: sale.quantity.Sub(qty), cost: sale.cost.Sub(proceeds)}
+
+		disposals = append(disposals, Disposal{
+			TransactionID: tx.ID,
+			CryptoID:      tx.CryptoID,
+			Exchange:      tx.Exchange,
+			Quantity:      qty,
+			Proceeds:      proceeds,
+			CostBasis:     cost,
+			AcquiredAt:    remaining[i].acquiredAt,
+			DisposedAt:    tx.Timestamp,
+		})
+
+		remaining[i].cost = remaining[i].cost.Sub(remaining[i].costOf(qty))
+		remaining[i].quantity = remaining[i].quantity.Sub(qty)
+		toSell = toSell.Sub(qty)
+		if !remaining[i].quantity.IsPositive() {
+			remaining = append(remaining[:i], remaining[i+1:]...)
+		}
+	}
+
+	if method == AverageCost {
+		// Every remaining unit now carries the same average cost
+		for i := range remaining {
+			remaining[i].cost = average.costOf(remaining[i].quantity)
+		}
+	}
+	return disposals, remaining, nil
+}
@@ -0,0 +1,200 @@
+package portfolio
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func lotsLedger(t *testing.T) *Ledger {
+	t.Helper()
+	ledger, err := NewLedger([]models.Transaction{
+		{ID: "b1", CryptoID: "bitcoin", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(10000), Timestamp: date(2022, 1, 1)},
+		{ID: "b2", CryptoID: "bitcoin", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(30000), Timestamp: date(2023, 6, 1)},
+		{
+			ID: "s1", CryptoID: "bitcoin", Type: models.TransactionSell, Quantity: decimal.NewFromFloat(1.5), Price: decimal.NewFromInt(40000),
+			Fee: models.Fee{Amount: decimal.NewFromInt(60), Currency: "usd"}, Timestamp: date(2024, 1, 1),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return ledger
+}
+
+func TestLedger_Disposals(t *testing.T) {
+	tests := []struct {
+		method    CostBasisMethod
+		wantLots  int
+		wantCost  int64
+		firstDate int
+	}{
+		{method: FIFO, wantLots: 2, wantCost: 10000 + 15000, firstDate: 2022},
+		{method: LIFO, wantLots: 2, wantCost: 30000 + 5000, firstDate: 2023},
+		{method: AverageCost, wantLots: 2, wantCost: 1.5 * 20000, firstDate: 2022},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.method), func(t *testing.T) {
+			disposals, err := lotsLedger(t).Disposals(tt.method)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(disposals) != tt.wantLots {
+				t.Fatalf("Expected %d disposals, got %d", tt.wantLots, len(disposals))
+			}
+
+			cost, proceeds := decimal.Zero, decimal.Zero
+			for _, d := range disposals {
+				cost = cost.Add(d.CostBasis)
+				proceeds = proceeds.Add(d.Proceeds)
+			}
+			if !cost.Equal(decimal.NewFromInt(tt.wantCost)) {
+				t.Errorf("Expected cost basis %d, got %s", tt.wantCost, cost)
+			}
+			if !proceeds.Equal(decimal.NewFromInt(60000 - 60)) {
+				t.Errorf("Expected net proceeds 59940, got %s", proceeds)
+			}
+			if disposals[0].AcquiredAt.Year() != tt.firstDate {
+				t.Errorf("Expected first lot from %d, got %d", tt.firstDate, disposals[0].AcquiredAt.Year())
+			}
+		})
+	}
+}
+
+func TestLedger_DisposalsCryptoFee(t *testing.T) {
+	ledger, err := NewLedger([]models.Transaction{
+		{
+			ID: "b1", CryptoID: "bitcoin", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(20000),
+			Fee: models.Fee{Amount: decimal.RequireFromString("0.2"), Currency: "bitcoin"}, Timestamp: date(2023, 1, 1),
+		},
+		{ID: "s1", CryptoID: "bitcoin", Type: models.TransactionSell, Quantity: decimal.RequireFromString("0.4"), Price: decimal.NewFromInt(30000), Timestamp: date(2024, 1, 1)},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	disposals, err := ledger.Disposals(FIFO)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(disposals) != 1 {
+		t.Fatalf("Expected 1 disposal, got %+v", disposals)
+	}
+	// 20000 paid for the 0.8 BTC received is 25000 per coin
+	d := disposals[0]
+	if unit := d.CostBasis.Div(d.Quantity); !unit.Equal(decimal.NewFromInt(25000)) {
+		t.Errorf("Expected a unit cost of 25000, got %s", unit)
+	}
+	if !d.CostBasis.Equal(decimal.NewFromInt(10000)) || !d.Gain().Equal(decimal.NewFromInt(2000)) {
+		t.Errorf("Unexpected disposal: %+v", d)
+	}
+}
+
+func TestLedger_SellCryptoFee(t *testing.T) {
+	ledger, err := NewLedger([]models.Transaction{
+		{ID: "b1", CryptoID: "bitcoin", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(2), Price: decimal.NewFromInt(100), Timestamp: date(2023, 1, 1)},
+		{
+			ID: "s1", CryptoID: "bitcoin", Type: models.TransactionSell, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(150),
+			Fee: models.Fee{Amount: decimal.RequireFromString("0.1"), Currency: "bitcoin"}, Timestamp: date(2024, 1, 1),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The 0.1 BTC fee leaves the position with the coin sold: 1.1 BTC costing
+	// 110 go for 150
+	positions, err := ledger.Positions()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	btc := positions["bitcoin"]
+	if !btc.Quantity.Equal(decimal.RequireFromString("0.9")) || !btc.CostBasis.Equal(decimal.NewFromInt(90)) || !btc.RealizedPnL.Equal(decimal.NewFromInt(40)) {
+		t.Errorf("Expected 0.9 BTC costing 90 and a gain of 40, got %+v", btc)
+	}
+	disposals, err := ledger.Disposals(FIFO)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(disposals) != 1 || !disposals[0].Quantity.Equal(decimal.RequireFromString("1.1")) || !disposals[0].Gain().Equal(decimal.NewFromInt(40)) {
+		t.Errorf("Expected the fee disposed of with the sale, got %+v", disposals)
+	}
+
+	// Nothing is left after selling the only coin, so the fee cannot be paid
+	oversold, err := NewLedger([]models.Transaction{
+		{ID: "b1", CryptoID: "bitcoin", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(100), Timestamp: date(2023, 1, 1)},
+		{
+			ID: "s1", CryptoID: "bitcoin", Type: models.TransactionSell, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(150),
+			Fee: models.Fee{Amount: decimal.RequireFromString("0.1"), Currency: "bitcoin"}, Timestamp: date(2024, 1, 1),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := oversold.Positions(); err == nil {
+		t.Error("Expected a fee beyond the coins held to be rejected")
+	}
+	if _, err := oversold.Disposals(FIFO); err == nil {
+		t.Error("Expected a fee beyond the coins held to be rejected by the lots")
+	}
+}
+
+func TestParseCostBasisMethod(t *testing.T) {
+	if m, err := ParseCostBasisMethod(""); err != nil || m != FIFO {
+		t.Errorf("Expected FIFO default, got %s (err %v)", m, err)
+	}
+	if _, err := ParseCostBasisMethod("hifo"); err == nil {
+		t.Error("Expected error for unknown method, got nil")
+	}
+}
+
+func TestLedger_Migrations(t *testing.T) {
+	ratio := decimal.NewFromInt(1000)
+	one := decimal.NewFromInt(1)
+	ledger, err := NewLedger([]models.Transaction{
+		{ID: "b1", CryptoID: "old", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(2), Price: decimal.NewFromInt(100), Timestamp: date(2021, 1, 1)},
+		{ID: "b2", CryptoID: "new", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1000), Price: decimal.NewFromFloat(0.3), Timestamp: date(2022, 1, 1)},
+		{ID: "s1", CryptoID: "old", Type: models.TransactionSell, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(150), Timestamp: date(2022, 6, 1)},
+		// 1 old becomes 1000 new; the remaining unit was bought before b2
+		{ID: "m1", CryptoID: "old", Type: models.TransactionMigrate, ToCryptoID: "new", Ratio: &ratio, Timestamp: date(2023, 1, 1)},
+		{ID: "m2", CryptoID: "new", Type: models.TransactionMigrate, ToCryptoID: "renamed", Ratio: &one, Timestamp: date(2023, 6, 1)},
+		{ID: "s2", CryptoID: "renamed", Type: models.TransactionSell, Quantity: decimal.NewFromInt(1500), Price: decimal.NewFromFloat(0.2), Timestamp: date(2024, 1, 1)},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	positions, err := ledger.Positions()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	old, renamed := positions["old"], positions["renamed"]
+	if !old.Quantity.IsZero() || !old.RealizedPnL.Equal(decimal.NewFromInt(50)) {
+		t.Errorf("Expected the old coin emptied with its realized P&L kept, got %+v", old)
+	}
+	// 2000 units cost 100 + 300; selling 1500 of them at 0.2 realizes 300 - 300
+	if !renamed.Quantity.Equal(decimal.NewFromInt(500)) || !renamed.CostBasis.Equal(decimal.NewFromInt(100)) || !renamed.RealizedPnL.IsZero() {
+		t.Errorf("Unexpected migrated position: %+v", renamed)
+	}
+
+	disposals, err := ledger.Disposals(FIFO)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(disposals) != 3 {
+		t.Fatalf("Expected 3 disposals, got %+v", disposals)
+	}
+	// The migrated lot keeps its 2021 acquisition date and is sold first
+	first := disposals[1]
+	if first.CryptoID != "renamed" || !first.AcquiredAt.Equal(date(2021, 1, 1)) || !first.Quantity.Equal(decimal.NewFromInt(1000)) || !first.CostBasis.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("Unexpected first disposal after the migration: %+v", first)
+	}
+	if last := disposals[2]; !last.Quantity.Equal(decimal.NewFromInt(500)) || !last.CostBasis.Equal(decimal.NewFromInt(150)) {
+		t.Errorf("Unexpected second disposal after the migration: %+v", last)
+	}
+	if fees := ledger.FeesByExchange(); len(fees) != 1 || !ledger.TotalFees().IsZero() {
+		t.Errorf("Expected migrations not to show up in fee reports, got %v", fees)
+	}
+}
@@ -0,0 +1,89 @@
+// Package preferences stores how each owner arranges the price table: the
+// coins pinned to the top and a manual order, applied by the API's default sort
+package preferences
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// ErrNotFound is returned when an owner has no stored coin order
+var ErrNotFound = errors.New("coin order not found")
+
+// Repository persists one coin order per owner
+type Repository interface {
+	Get(owner string) (models.CoinOrder, error)
+	Save(o models.CoinOrder) (models.CoinOrder, error)
+	Delete(owner string) error
+}
+
+// Service manages owner coin orders
+type Service struct {
+	repo Repository
+}
+
+// NewService creates a preferences service
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Order returns the coin order of an owner, or an empty one when they never set one
+func (s *Service) Order(owner string) (models.CoinOrder, error) {
+	o, err := s.repo.Get(owner)
+	if errors.Is(err, ErrNotFound) {
+		return models.DefaultCoinOrder(owner), nil
+	}
+	return o, err
+}
+
+// SetOrder validates and stores the pins and the manual order of an owner
+func (s *Service) SetOrder(owner string, o models.CoinOrder) (models.CoinOrder, error) {
+	o.Owner = owner
+	return s.save(o)
+}
+
+// Pin moves a coin to the top, below the coins pinned before it. Pinning a
+// pinned coin keeps its place.
+func (s *Service) Pin(owner, id string) (models.CoinOrder, error) {
+	o, err := s.Order(owner)
+	if err != nil {
+		return models.CoinOrder{}, err
+	}
+	if id = strings.ToLower(strings.TrimSpace(id)); !slices.Contains(o.Pinned, id) {
+		o.Pinned = append(o.Pinned, id)
+	}
+	return s.save(o)
+}
+
+// Unpin returns a coin to its place in the manual or default order
+func (s *Service) Unpin(owner, id string) (models.CoinOrder, error) {
+	o, err := s.Order(owner)
+	if err != nil {
+		return models.CoinOrder{}, err
+	}
+	id = strings.ToLower(strings.TrimSpace(id))
+	o.Pinned = slices.DeleteFunc(o.Pinned, func(pinned string) bool { return pinned == id })
+	return s.save(o)
+}
+
+// Reset drops the coin order of an owner so the default order applies again
+func (s *Service) Reset(owner string) error {
+	err := s.repo.Delete(owner)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (s *Service) save(o models.CoinOrder) (models.CoinOrder, error) {
+	o.Normalize()
+	if err := o.Validate(); err != nil {
+		return models.CoinOrder{}, err
+	}
+	o.UpdatedAt = time.Now().UTC()
+	return s.repo.Save(o)
+}
@@ -0,0 +1,71 @@
+package preferences
+
+import (
+	"slices"
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubRepo map[string]models.CoinOrder
+
+func (r stubRepo) Get(owner string) (models.CoinOrder, error) {
+	o, ok := r[owner]
+	if !ok {
+		return models.CoinOrder{}, ErrNotFound
+	}
+	return o, nil
+}
+
+func (r stubRepo) Save(o models.CoinOrder) (models.CoinOrder, error) {
+	r[o.Owner] = o
+	return o, nil
+}
+
+func (r stubRepo) Delete(owner string) error {
+	if _, ok := r[owner]; !ok {
+		return ErrNotFound
+	}
+	delete(r, owner)
+	return nil
+}
+
+func TestService_PinAndUnpin(t *testing.T) {
+	s := NewService(stubRepo{})
+	if o, err := s.Order("alice"); err != nil || len(o.Pinned) != 0 || o.Owner != "alice" {
+		t.Fatalf("Expected an empty order, got %+v (%v)", o, err)
+	}
+
+	s.Pin("alice", "Solana")
+	s.Pin("alice", "bitcoin")
+	o, err := s.Pin("alice", "solana")
+	if err != nil || !slices.Equal(o.Pinned, []string{"solana", "bitcoin"}) || o.UpdatedAt.IsZero() {
+		t.Fatalf("Expected pins in pinning order, got %+v (%v)", o, err)
+	}
+
+	o, err = s.Unpin("alice", "solana")
+	if err != nil || !slices.Equal(o.Pinned, []string{"bitcoin"}) {
+		t.Errorf("Expected solana to be unpinned, got %+v (%v)", o, err)
+	}
+	if other, _ := s.Order("bob"); len(other.Pinned) != 0 {
+		t.Errorf("Expected pins to be per owner, got %+v", other)
+	}
+}
+
+func TestService_SetOrder(t *testing.T) {
+	s := NewService(stubRepo{})
+	o, err := s.SetOrder("alice", models.CoinOrder{Owner: "mallory", Order: []string{"ETHEREUM", "bitcoin"}})
+	if err != nil || o.Owner != "alice" || !slices.Equal(o.Order, []string{"ethereum", "bitcoin"}) || o.Pinned == nil {
+		t.Fatalf("Expected a normalized order of alice, got %+v (%v)", o, err)
+	}
+	if _, err := s.SetOrder("alice", models.CoinOrder{Order: []string{"bitcoin", "bitcoin"}}); err == nil {
+		t.Error("Expected a duplicate coin to be rejected")
+	}
+
+	if err := s.Reset("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if o, _ := s.Order("alice"); len(o.Order) != 0 {
+		t.Errorf("Expected the default order after a reset, got %+v", o)
+	}
+}
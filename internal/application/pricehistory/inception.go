@@ -0,0 +1,168 @@
+package pricehistory
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// EarliestMarketData is the first day CoinGecko has prices for; series of
+// coins without a genesis date, or with an older one, start there
+var EarliestMarketData = time.Date(2013, 4, 28, 0, 0, 0, 0, time.UTC)
+
+// stitchWindow is the span of each range request. Ranges longer than 90 days
+// come back with daily granularity.
+const stitchWindow = 365 * 24 * time.Hour
+
+// RangeSource fetches the recorded prices of a coin and its genesis date
+type RangeSource interface {
+	GetPriceRange(cryptoID string, currency models.Currency, from, to time.Time) ([]models.PricePoint, error)
+	CoinInfo(id string) (models.CoinInfo, error)
+}
+
+// SeriesRepository caches daily series. Through is the last day fetched,
+// which may be after the last point when a coin had no prices yet.
+type SeriesRepository interface {
+	DailySeries(cryptoID string, currency models.Currency) (points []models.PricePoint, through time.Time, err error)
+	SaveDailySeries(cryptoID string, currency models.Currency, points []models.PricePoint, through time.Time) error
+}
+
+// Inception serves daily series from a coin's genesis to yesterday. Closed
+// days never change, so they are fetched once and later requests only fetch
+// the days since the last one cached.
+type Inception struct {
+	source RangeSource
+	repo   SeriesRepository
+	clock  clock.Clock
+
+	// flights lets concurrent requests for a series share one fetch, while
+	// series of other coins are fetched independently
+	mu      sync.Mutex
+	flights map[seriesKey]*flight
+}
+
+type seriesKey struct {
+	cryptoID string
+	currency models.Currency
+}
+
+// flight is a series being fetched; done is closed once points and err are set
+type flight struct {
+	done   chan struct{}
+	points []models.PricePoint
+	err    error
+}
+
+// NewInception creates a since-inception series service
+func NewInception(source RangeSource, repo SeriesRepository) *Inception {
+	return &Inception{source: source, repo: repo, clock: clock.Real, flights: make(map[seriesKey]*flight)}
+}
+
+// SetClock replaces the system clock, for tests
+func (s *Inception) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Series returns one price per closed UTC day since the coin's genesis.
+// Days without a positive price are left out so the series can be drawn on a log scale.
+func (s *Inception) Series(cryptoID string, currency models.Currency) ([]models.PricePoint, error) {
+	cryptoID = strings.ToLower(strings.TrimSpace(cryptoID))
+	if cryptoID == "" {
+		return nil, errors.New("crypto ID cannot be empty")
+	}
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+
+	key := seriesKey{cryptoID: cryptoID, currency: currency}
+	s.mu.Lock()
+	if f, ok := s.flights[key]; ok {
+		s.mu.Unlock()
+		<-f.done
+		return f.points, f.err
+	}
+	f := &flight{done: make(chan struct{})}
+	s.flights[key] = f
+	s.mu.Unlock()
+
+	f.points, f.err = s.series(cryptoID, currency)
+	s.mu.Lock()
+	delete(s.flights, key)
+	s.mu.Unlock()
+	close(f.done)
+	return f.points, f.err
+}
+
+// series reads the cached series and fetches the days it lacks
+func (s *Inception) series(cryptoID string, currency models.Currency) ([]models.PricePoint, error) {
+	points, through, err := s.repo.DailySeries(cryptoID, currency)
+	if err != nil {
+		return nil, err
+	}
+	yesterday := Day(s.clock.Now()).AddDate(0, 0, -1)
+	if !through.IsZero() && !through.Before(yesterday) {
+		return points, nil
+	}
+
+	from := through.AddDate(0, 0, 1)
+	if through.IsZero() {
+		if from, err = s.genesis(cryptoID); err != nil {
+			return nil, err
+		}
+	}
+	fetched, err := s.fetch(cryptoID, currency, from, yesterday)
+	if err != nil {
+		return nil, err
+	}
+	points = append(points, fetched...)
+	if err := s.repo.SaveDailySeries(cryptoID, currency, points, yesterday); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// genesis returns the first day to fetch for a coin
+func (s *Inception) genesis(cryptoID string) (time.Time, error) {
+	info, err := s.source.CoinInfo(cryptoID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	genesis, err := time.Parse(time.DateOnly, info.GenesisDate)
+	if err != nil || genesis.Before(EarliestMarketData) {
+		return EarliestMarketData, nil
+	}
+	return genesis, nil
+}
+
+// fetch stitches range requests covering the days from first to last into
+// one point per day, keeping the earliest price of each day
+func (s *Inception) fetch(cryptoID string, currency models.Currency, first, last time.Time) ([]models.PricePoint, error) {
+	var points []models.PricePoint
+	end := last.Add(24*time.Hour - time.Second)
+	for from := first; !from.After(last); from = from.Add(stitchWindow) {
+		to := from.Add(stitchWindow - time.Second)
+		if to.After(end) {
+			to = end
+		}
+		window, err := s.source.GetPriceRange(cryptoID, currency, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s history from %s: %w", cryptoID, from.Format(time.DateOnly), err)
+		}
+		for _, p := range window {
+			day := Day(p.Time)
+			if p.Price <= 0 || day.Before(first) || day.After(last) {
+				continue
+			}
+			if n := len(points); n > 0 && !day.After(points[n-1].Time) {
+				continue
+			}
+			points = append(points, models.PricePoint{Price: p.Price, Time: day})
+		}
+	}
+	return points, nil
+}
@@ -0,0 +1,176 @@
+package pricehistory
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// stubRange returns two points a day, at 00:00 and 12:00, priced by day index
+type stubRange struct {
+	genesis string
+	ranges  [][2]time.Time
+	err     error
+}
+
+func (s *stubRange) GetPriceRange(cryptoID string, currency models.Currency, from, to time.Time) ([]models.PricePoint, error) {
+	s.ranges = append(s.ranges, [2]time.Time{from, to})
+	if s.err != nil {
+		return nil, s.err
+	}
+	var points []models.PricePoint
+	for t := from; !t.After(to); t = t.Add(12 * time.Hour) {
+		price := float64(t.Sub(EarliestMarketData) / (24 * time.Hour))
+		points = append(points, models.PricePoint{Price: price, Time: t})
+	}
+	return points, nil
+}
+
+func (s *stubRange) CoinInfo(id string) (models.CoinInfo, error) {
+	return models.CoinInfo{ID: id, GenesisDate: s.genesis}, nil
+}
+
+type seriesRepo struct {
+	points  []models.PricePoint
+	through time.Time
+}
+
+func (r *seriesRepo) DailySeries(string, models.Currency) ([]models.PricePoint, time.Time, error) {
+	return r.points, r.through, nil
+}
+
+func (r *seriesRepo) SaveDailySeries(_ string, _ models.Currency, points []models.PricePoint, through time.Time) error {
+	r.points, r.through = points, through
+	return nil
+}
+
+func TestInception_Series(t *testing.T) {
+	source := &stubRange{genesis: "2009-01-03"}
+	repo := &seriesRepo{}
+	s := NewInception(source, repo)
+	now := EarliestMarketData.AddDate(0, 0, 500).Add(15 * time.Hour)
+	clk := clock.NewFake(now)
+	s.SetClock(clk)
+
+	points, err := s.Series("Bitcoin", "")
+	if err != nil {
+		t.Fatalf("Series: %v", err)
+	}
+	// The first day is priced 0 and left out; today is not closed yet
+	if len(points) != 499 || points[0].Price != 1 || !points[0].Time.Equal(EarliestMarketData.AddDate(0, 0, 1)) {
+		t.Fatalf("Unexpected series of %d points starting %+v", len(points), points[0])
+	}
+	if last := points[len(points)-1]; last.Price != 499 {
+		t.Errorf("Expected the series to end yesterday, got %+v", last)
+	}
+	if len(source.ranges) != 2 || !source.ranges[0][0].Equal(EarliestMarketData) {
+		t.Errorf("Expected a genesis before 2013 to start at the earliest market data in two windows, got %v", source.ranges)
+	}
+
+	// The same day is served from the cache; the next one only fetches one day
+	s.Series("bitcoin", models.USD)
+	if len(source.ranges) != 2 {
+		t.Errorf("Expected no fetch on the same day, got %d", len(source.ranges))
+	}
+	clk.Advance(24 * time.Hour)
+	points, _ = s.Series("bitcoin", models.USD)
+	if len(source.ranges) != 3 || len(points) != 500 {
+		t.Errorf("Expected one incremental fetch adding a day, got %d fetches and %d points", len(source.ranges), len(points))
+	}
+	if from := source.ranges[2][0]; !from.Equal(Day(clk.Now()).AddDate(0, 0, -1)) {
+		t.Errorf("Expected the incremental fetch to start yesterday, got %s", from)
+	}
+}
+
+func TestInception_SeriesStartsAtGenesis(t *testing.T) {
+	source := &stubRange{genesis: "2015-07-30"}
+	s := NewInception(source, &seriesRepo{})
+	s.SetClock(clock.NewFake(time.Date(2015, 8, 10, 0, 0, 0, 0, time.UTC)))
+
+	points, err := s.Series("ethereum", models.USD)
+	if err != nil || len(points) != 11 || !points[0].Time.Equal(time.Date(2015, 7, 30, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected 11 days from genesis, got %d (%v)", len(points), err)
+	}
+}
+
+func TestInception_SeriesFetchError(t *testing.T) {
+	repo := &seriesRepo{}
+	s := NewInception(&stubRange{err: errors.New("rate limited")}, repo)
+
+	if _, err := s.Series("bitcoin", models.USD); err == nil {
+		t.Fatal("Expected the fetch error")
+	}
+	if !repo.through.IsZero() {
+		t.Error("Expected nothing to be cached after a failed fetch")
+	}
+}
+
+// blockingRange holds the fetches of bitcoin until released
+type blockingRange struct {
+	release chan struct{}
+	infos   atomic.Int32
+}
+
+func (b *blockingRange) GetPriceRange(cryptoID string, _ models.Currency, from, _ time.Time) ([]models.PricePoint, error) {
+	if cryptoID == "bitcoin" {
+		<-b.release
+	}
+	return []models.PricePoint{{Price: 1, Time: from}}, nil
+}
+
+func (b *blockingRange) CoinInfo(id string) (models.CoinInfo, error) {
+	b.infos.Add(1)
+	return models.CoinInfo{ID: id, GenesisDate: "2024-01-01"}, nil
+}
+
+type mapSeriesRepo struct {
+	mu     sync.Mutex
+	series map[string][]models.PricePoint
+}
+
+func (r *mapSeriesRepo) DailySeries(id string, _ models.Currency) ([]models.PricePoint, time.Time, error) {
+	return nil, time.Time{}, nil
+}
+
+func (r *mapSeriesRepo) SaveDailySeries(id string, _ models.Currency, points []models.PricePoint, _ time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.series[id] = points
+	return nil
+}
+
+func TestInception_SeriesSharesFetches(t *testing.T) {
+	source := &blockingRange{release: make(chan struct{})}
+	s := NewInception(source, &mapSeriesRepo{series: make(map[string][]models.PricePoint)})
+	s.SetClock(clock.NewFake(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)))
+
+	var wg sync.WaitGroup
+	results := make([][]models.PricePoint, 2)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = s.Series("bitcoin", models.USD)
+		}()
+	}
+	// Another coin is not held up by the pending bitcoin fetch
+	if points, err := s.Series("ethereum", models.USD); err != nil || len(points) != 1 {
+		t.Fatalf("Expected ethereum while bitcoin is fetched, got %v (%v)", points, err)
+	}
+	close(source.release)
+	wg.Wait()
+
+	if len(results[0]) != 1 || len(results[1]) != 1 {
+		t.Errorf("Expected both requests to get the series, got %v", results)
+	}
+	// One genesis lookup per coin is shared by every concurrent request; a
+	// request arriving after the first finished may look it up again
+	if n := source.infos.Load(); n < 2 || n > 3 {
+		t.Errorf("Expected bitcoin looked up once or twice, got %d lookups", n)
+	}
+}
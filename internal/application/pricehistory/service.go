@@ -0,0 +1,82 @@
+// Package pricehistory answers "price at date" queries, reading through a
+// repository so every day is fetched from the provider at most once
+package pricehistory
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// ErrNoPrice is returned when the provider has no price for a coin on a date,
+// typically because the coin was not listed yet
+var ErrNoPrice = errors.New("no price recorded on that date")
+
+// Source fetches the price of a coin at 00:00 UTC of a day
+type Source interface {
+	GetPriceAt(cryptoID string, date time.Time, currency models.Currency) (float64, error)
+}
+
+// Repository caches daily prices
+type Repository interface {
+	DailyPrice(cryptoID string, currency models.Currency, day time.Time) (float64, bool, error)
+	SaveDailyPrice(cryptoID string, currency models.Currency, day time.Time, price float64) error
+}
+
+// Service looks daily prices up in the repository and falls back to the source
+type Service struct {
+	source Source
+	repo   Repository
+	clock  clock.Clock
+}
+
+// NewService creates a read-through price history service
+func NewService(source Source, repo Repository) *Service {
+	return &Service{source: source, repo: repo, clock: clock.Real}
+}
+
+// SetClock replaces the system clock, for tests
+func (s *Service) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// PriceAt returns the daily price of a coin for the UTC day containing at
+func (s *Service) PriceAt(cryptoID string, currency models.Currency, at time.Time) (float64, error) {
+	cryptoID = strings.ToLower(strings.TrimSpace(cryptoID))
+	if cryptoID == "" {
+		return 0, errors.New("crypto ID cannot be empty")
+	}
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+	day := Day(at)
+	if day.After(s.clock.Now()) {
+		return 0, fmt.Errorf("no price for future date %s", day.Format(time.DateOnly))
+	}
+
+	price, ok, err := s.repo.DailyPrice(cryptoID, currency, day)
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		return price, nil
+	}
+
+	price, err = s.source.GetPriceAt(cryptoID, day, currency)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.repo.SaveDailyPrice(cryptoID, currency, day, price); err != nil {
+		return 0, err
+	}
+	return price, nil
+}
+
+// Day returns the start of the UTC day containing t
+func Day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,78 @@
+package pricehistory
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubSource struct {
+	calls int
+	days  []time.Time
+}
+
+func (s *stubSource) GetPriceAt(cryptoID string, date time.Time, currency models.Currency) (float64, error) {
+	s.calls++
+	s.days = append(s.days, date)
+	if cryptoID == "unlisted" {
+		return 0, ErrNoPrice
+	}
+	return 42000, nil
+}
+
+type mapRepo map[string]float64
+
+func key(id string, currency models.Currency, day time.Time) string {
+	return id + "/" + string(currency) + "/" + day.Format(time.DateOnly)
+}
+
+func (r mapRepo) DailyPrice(id string, currency models.Currency, day time.Time) (float64, bool, error) {
+	price, ok := r[key(id, currency, day)]
+	return price, ok, nil
+}
+
+func (r mapRepo) SaveDailyPrice(id string, currency models.Currency, day time.Time, price float64) error {
+	r[key(id, currency, day)] = price
+	return nil
+}
+
+func TestService_PriceAt(t *testing.T) {
+	source := &stubSource{}
+	s := NewService(source, mapRepo{})
+	at := time.Date(2024, 3, 5, 18, 30, 0, 0, time.UTC)
+
+	price, err := s.PriceAt("Bitcoin", "", at)
+	if err != nil || price != 42000 {
+		t.Fatalf("Expected 42000, got %f (%v)", price, err)
+	}
+	if !source.days[0].Equal(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the lookup to use the start of the day, got %s", source.days[0])
+	}
+
+	// Any time of the same day is served from the repository
+	s.PriceAt("bitcoin", models.USD, at.Add(-10*time.Hour))
+	if source.calls != 1 {
+		t.Errorf("Expected a single upstream call, got %d", source.calls)
+	}
+
+	s.PriceAt("bitcoin", models.EUR, at)
+	if source.calls != 2 {
+		t.Errorf("Expected another currency to be fetched separately, got %d calls", source.calls)
+	}
+}
+
+func TestService_PriceAtErrors(t *testing.T) {
+	s := NewService(&stubSource{}, mapRepo{})
+
+	if _, err := s.PriceAt("unlisted", models.USD, time.Now()); !errors.Is(err, ErrNoPrice) {
+		t.Errorf("Expected ErrNoPrice, got %v", err)
+	}
+	if _, err := s.PriceAt("bitcoin", models.USD, time.Now().AddDate(0, 0, 2)); err == nil {
+		t.Error("Expected an error for a future date")
+	}
+	if _, err := s.PriceAt(" ", models.USD, time.Now()); err == nil {
+		t.Error("Expected an error for an empty ID")
+	}
+}
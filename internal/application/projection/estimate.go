@@ -0,0 +1,79 @@
+// Package projection simulates how a portfolio could evolve from the return
+// and volatility observed in its stored price history
+package projection
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Estimate is the annualized drift and volatility of log returns
+type Estimate struct {
+	Mean       float64 `json:"mean"`
+	Volatility float64 `json:"volatility"`
+	// Periods is the number of returns the estimate is based on
+	Periods int `json:"periods"`
+}
+
+// PeriodsPerYear converts a candle interval into the number of periods in a year
+func PeriodsPerYear(interval time.Duration) float64 {
+	return float64(365*24*time.Hour) / float64(interval)
+}
+
+// EstimatePortfolio estimates the returns of a portfolio that keeps the given
+// weights every period. Series hold the close prices of each coin and are aligned
+// on their most recent values, so only the overlapping tail is used.
+func EstimatePortfolio(series map[string][]float64, weights map[string]float64, periodsPerYear float64) (Estimate, error) {
+	if len(weights) == 0 {
+		return Estimate{}, errors.New("portfolio has no holdings")
+	}
+
+	length := -1
+	total := 0.0
+	for id, w := range weights {
+		closes, ok := series[id]
+		if !ok || len(closes) < 2 {
+			return Estimate{}, fmt.Errorf("not enough history for %s", id)
+		}
+		if length < 0 || len(closes) < length {
+			length = len(closes)
+		}
+		total += w
+	}
+	if total <= 0 {
+		return Estimate{}, errors.New("portfolio weights must be positive")
+	}
+
+	returns := make([]float64, length-1)
+	for id, w := range weights {
+		closes := series[id][len(series[id])-length:]
+		for i := 1; i < length; i++ {
+			if closes[i-1] <= 0 {
+				return Estimate{}, fmt.Errorf("non-positive price in %s history", id)
+			}
+			returns[i-1] += w / total * (closes[i]/closes[i-1] - 1)
+		}
+	}
+
+	var sum, sumSq float64
+	for i, r := range returns {
+		returns[i] = math.Log1p(r)
+		sum += returns[i]
+	}
+	mean := sum / float64(len(returns))
+	for _, r := range returns {
+		sumSq += (r - mean) * (r - mean)
+	}
+	variance := 0.0
+	if len(returns) > 1 {
+		variance = sumSq / float64(len(returns)-1)
+	}
+
+	return Estimate{
+		Mean:       mean * periodsPerYear,
+		Volatility: math.Sqrt(variance * periodsPerYear),
+		Periods:    len(returns),
+	}, nil
+}
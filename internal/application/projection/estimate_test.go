@@ -0,0 +1,45 @@
+package projection
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPeriodsPerYear(t *testing.T) {
+	if got := PeriodsPerYear(24 * time.Hour); got != 365 {
+		t.Errorf("Expected 365 daily periods, got %f", got)
+	}
+}
+
+func TestEstimatePortfolio(t *testing.T) {
+	// Bitcoin doubles every period, the stable coin never moves
+	series := map[string][]float64{
+		"bitcoin": {1, 2, 4, 8},
+		"usdc":    {1, 1, 1, 1, 1, 1},
+	}
+
+	est, err := EstimatePortfolio(series, map[string]float64{"bitcoin": 1}, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if math.Abs(est.Mean-math.Log(2)) > 1e-9 || est.Volatility != 0 || est.Periods != 3 {
+		t.Errorf("Expected ln(2) drift without volatility over 3 periods, got %+v", est)
+	}
+
+	// A 50/50 mix gains 50% per period on the overlapping tail
+	est, err = EstimatePortfolio(series, map[string]float64{"bitcoin": 1, "usdc": 1}, 12)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if math.Abs(est.Mean-12*math.Log(1.5)) > 1e-9 || est.Periods != 3 {
+		t.Errorf("Expected annualized ln(1.5) drift, got %+v", est)
+	}
+
+	if _, err := EstimatePortfolio(series, map[string]float64{"solana": 1}, 1); err == nil {
+		t.Error("Expected error for coin without history")
+	}
+	if _, err := EstimatePortfolio(series, nil, 1); err == nil {
+		t.Error("Expected error for empty portfolio")
+	}
+}
@@ -0,0 +1,125 @@
+package projection
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"sort"
+)
+
+// Default simulation settings
+const (
+	DefaultSimulations  = 1000
+	DefaultStepsPerYear = 12
+	MaxSimulations      = 20000
+	MaxYears            = 50
+)
+
+// Percentiles reported for every step of the projection
+var Percentiles = []float64{5, 25, 50, 75, 95}
+
+// Input configures a Monte Carlo run
+type Input struct {
+	InitialValue float64
+	Estimate     Estimate
+	Years        int
+	StepsPerYear int
+	Simulations  int
+	// Seed makes the run reproducible; 0 picks a random seed
+	Seed uint64
+}
+
+// Band is the distribution of simulated portfolio values at one point in time
+type Band struct {
+	// Year is the time since the start of the projection in years
+	Year float64 `json:"year"`
+	// Values holds one value per entry of Percentiles
+	Values []float64 `json:"values"`
+}
+
+// Result is the outcome of a Monte Carlo run
+type Result struct {
+	Percentiles []float64 `json:"percentiles"`
+	Bands       []Band    `json:"bands"`
+	// MeanFinal is the average simulated value at the end of the horizon
+	MeanFinal float64 `json:"mean_final"`
+	// LossProbability is the share of paths ending below the initial value
+	LossProbability float64 `json:"loss_probability"`
+}
+
+// Simulate runs geometric Brownian motion paths driven by the estimate and
+// summarizes them into percentile bands, one per step
+func Simulate(in Input) (Result, error) {
+	if in.InitialValue <= 0 {
+		return Result{}, errors.New("initial value must be positive")
+	}
+	if in.Years <= 0 || in.Years > MaxYears {
+		return Result{}, errors.New("years must be between 1 and 50")
+	}
+	if in.Simulations <= 0 {
+		in.Simulations = DefaultSimulations
+	}
+	if in.Simulations > MaxSimulations {
+		return Result{}, errors.New("too many simulations")
+	}
+	if in.StepsPerYear <= 0 {
+		in.StepsPerYear = DefaultStepsPerYear
+	}
+	seed := in.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	rng := rand.New(rand.NewPCG(seed, seed>>1|1))
+
+	steps := in.Years * in.StepsPerYear
+	dt := 1 / float64(in.StepsPerYear)
+	drift := in.Estimate.Mean * dt
+	shock := in.Estimate.Volatility * math.Sqrt(dt)
+
+	// values[step][path]
+	values := make([][]float64, steps+1)
+	for i := range values {
+		values[i] = make([]float64, in.Simulations)
+	}
+	for p := 0; p < in.Simulations; p++ {
+		v := in.InitialValue
+		values[0][p] = v
+		for step := 1; step <= steps; step++ {
+			v *= math.Exp(drift + shock*rng.NormFloat64())
+			values[step][p] = v
+		}
+	}
+
+	result := Result{Percentiles: Percentiles, Bands: make([]Band, steps+1)}
+	for step, paths := range values {
+		sort.Float64s(paths)
+		band := Band{Year: float64(step) * dt, Values: make([]float64, len(Percentiles))}
+		for i, pct := range Percentiles {
+			band.Values[i] = percentile(paths, pct)
+		}
+		result.Bands[step] = band
+	}
+
+	final := values[steps]
+	losses := 0
+	for _, v := range final {
+		result.MeanFinal += v
+		if v < in.InitialValue {
+			losses++
+		}
+	}
+	result.MeanFinal /= float64(len(final))
+	result.LossProbability = float64(losses) / float64(len(final))
+	return result, nil
+}
+
+// percentile interpolates linearly between the closest ranks of sorted values
+func percentile(sorted []float64, pct float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	rank := pct / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
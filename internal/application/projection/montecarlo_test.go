@@ -0,0 +1,87 @@
+package projection
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimulate_Deterministic(t *testing.T) {
+	// Without volatility every path follows the drift exactly
+	result, err := Simulate(Input{
+		InitialValue: 100,
+		Estimate:     Estimate{Mean: math.Log(2)},
+		Years:        2,
+		StepsPerYear: 4,
+		Simulations:  10,
+		Seed:         1,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(result.Bands) != 9 {
+		t.Fatalf("Expected 9 bands, got %d", len(result.Bands))
+	}
+	last := result.Bands[8]
+	if last.Year != 2 {
+		t.Errorf("Expected last band at year 2, got %f", last.Year)
+	}
+	for i, v := range last.Values {
+		if math.Abs(v-400) > 1e-6 {
+			t.Errorf("Expected percentile %v to be 400, got %f", result.Percentiles[i], v)
+		}
+	}
+	if result.LossProbability != 0 {
+		t.Errorf("Expected no losses, got %f", result.LossProbability)
+	}
+}
+
+func TestSimulate_Bands(t *testing.T) {
+	in := Input{
+		InitialValue: 1000,
+		Estimate:     Estimate{Mean: 0.1, Volatility: 0.6},
+		Years:        5,
+		Simulations:  2000,
+		Seed:         42,
+	}
+	result, err := Simulate(in)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, band := range result.Bands {
+		for i := 1; i < len(band.Values); i++ {
+			if band.Values[i] < band.Values[i-1] {
+				t.Fatalf("Expected percentiles to be ordered at year %f, got %v", band.Year, band.Values)
+			}
+		}
+	}
+	final := result.Bands[len(result.Bands)-1].Values
+	if final[0] >= 1000 || final[4] <= 1000 {
+		t.Errorf("Expected volatile paths to spread around the start, got %v", final)
+	}
+
+	again, _ := Simulate(in)
+	if again.MeanFinal != result.MeanFinal {
+		t.Error("Expected the same seed to reproduce the run")
+	}
+}
+
+func TestSimulate_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Input
+	}{
+		{"no value", Input{Years: 1}},
+		{"no years", Input{InitialValue: 1}},
+		{"too many years", Input{InitialValue: 1, Years: 100}},
+		{"too many simulations", Input{InitialValue: 1, Years: 1, Simulations: MaxSimulations + 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Simulate(tt.in); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
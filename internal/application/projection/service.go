@@ -0,0 +1,109 @@
+package projection
+
+import (
+	"fmt"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// CandleReader loads stored candles
+type CandleReader interface {
+	Candles(cryptoID string, interval time.Duration, limit int) ([]models.Candle, error)
+}
+
+// PriceReader returns current prices for a set of coins
+type PriceReader interface {
+	Prices(ids []string) ([]models.CryptoPrice, error)
+}
+
+// Request describes the portfolio to project
+type Request struct {
+	// Holdings maps each coin ID to the quantity held
+	Holdings    map[string]float64 `json:"holdings"`
+	Years       int                `json:"years"`
+	Simulations int                `json:"simulations,omitempty"`
+	Seed        uint64             `json:"seed,omitempty"`
+}
+
+// Projection is a Monte Carlo projection of a portfolio
+type Projection struct {
+	Currency     models.Currency `json:"currency"`
+	InitialValue float64         `json:"initial_value"`
+	Estimate     Estimate        `json:"estimate"`
+	Result
+}
+
+// Service projects portfolios from stored candle history and current prices
+type Service struct {
+	candles  CandleReader
+	prices   PriceReader
+	interval time.Duration
+	currency models.Currency
+}
+
+// NewService creates a projection service working on candles of the given interval
+func NewService(candles CandleReader, prices PriceReader, interval time.Duration, currency models.Currency) *Service {
+	return &Service{candles: candles, prices: prices, interval: interval, currency: currency}
+}
+
+// Project values the holdings at current prices, estimates their historical
+// return and volatility and simulates the portfolio over the requested years
+func (s *Service) Project(req Request) (Projection, error) {
+	if len(req.Holdings) == 0 {
+		return Projection{}, fmt.Errorf("holdings cannot be empty")
+	}
+
+	ids := make([]string, 0, len(req.Holdings))
+	for id, qty := range req.Holdings {
+		if qty <= 0 {
+			return Projection{}, fmt.Errorf("quantity of %s must be positive", id)
+		}
+		ids = append(ids, id)
+	}
+
+	prices, err := s.prices.Prices(ids)
+	if err != nil && len(prices) < len(ids) {
+		return Projection{}, fmt.Errorf("failed to fetch prices: %w", err)
+	}
+
+	// Current market values double as the weights of the portfolio
+	weights := make(map[string]float64, len(ids))
+	initial := 0.0
+	for _, p := range prices {
+		if qty, ok := req.Holdings[p.ID]; ok {
+			weights[p.ID] = qty * p.PriceFloat()
+			initial += weights[p.ID]
+		}
+	}
+	for _, id := range ids {
+		if _, ok := weights[id]; !ok {
+			return Projection{}, fmt.Errorf("no current price for %s", id)
+		}
+	}
+
+	series := make(map[string][]float64, len(ids))
+	for _, id := range ids {
+		candles, err := s.candles.Candles(id, s.interval, 0)
+		if err != nil {
+			return Projection{}, err
+		}
+		series[id] = models.Closes(candles)
+	}
+	estimate, err := EstimatePortfolio(series, weights, PeriodsPerYear(s.interval))
+	if err != nil {
+		return Projection{}, err
+	}
+
+	result, err := Simulate(Input{
+		InitialValue: initial,
+		Estimate:     estimate,
+		Years:        req.Years,
+		Simulations:  req.Simulations,
+		Seed:         req.Seed,
+	})
+	if err != nil {
+		return Projection{}, err
+	}
+	return Projection{Currency: s.currency, InitialValue: initial, Estimate: estimate, Result: result}, nil
+}
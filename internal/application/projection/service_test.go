@@ -0,0 +1,62 @@
+package projection
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubCandles map[string][]float64
+
+func (s stubCandles) Candles(id string, interval time.Duration, limit int) ([]models.Candle, error) {
+	var candles []models.Candle
+	for _, c := range s[id] {
+		candles = append(candles, models.Candle{CryptoID: id, Close: c})
+	}
+	return candles, nil
+}
+
+type stubPrices map[string]float64
+
+func (s stubPrices) Prices(ids []string) ([]models.CryptoPrice, error) {
+	var prices []models.CryptoPrice
+	for _, id := range ids {
+		if p, ok := s[id]; ok {
+			prices = append(prices, models.CryptoPrice{ID: id, CurrentPrice: decimal.NewFromFloat(p)})
+		}
+	}
+	return prices, nil
+}
+
+func TestService_Project(t *testing.T) {
+	svc := NewService(
+		stubCandles{"bitcoin": {100, 102, 101, 105, 107}, "ethereum": {10, 9, 11, 12, 12}},
+		stubPrices{"bitcoin": 50000, "ethereum": 3000},
+		24*time.Hour,
+		models.USD,
+	)
+
+	p, err := svc.Project(Request{Holdings: map[string]float64{"bitcoin": 0.5, "ethereum": 2}, Years: 3, Seed: 7})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p.InitialValue != 31000 {
+		t.Errorf("Expected initial value 31000, got %f", p.InitialValue)
+	}
+	if p.Estimate.Periods != 4 || p.Estimate.Volatility <= 0 {
+		t.Errorf("Unexpected estimate: %+v", p.Estimate)
+	}
+	if len(p.Bands) != 3*DefaultStepsPerYear+1 {
+		t.Errorf("Expected %d bands, got %d", 3*DefaultStepsPerYear+1, len(p.Bands))
+	}
+
+	if _, err := svc.Project(Request{Holdings: map[string]float64{"solana": 1}, Years: 1}); err == nil {
+		t.Error("Expected error for coin without a price")
+	}
+	if _, err := svc.Project(Request{Holdings: map[string]float64{"bitcoin": -1}, Years: 1}); err == nil {
+		t.Error("Expected error for negative quantity")
+	}
+}
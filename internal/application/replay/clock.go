@@ -0,0 +1,64 @@
+package replay
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+)
+
+// MaxSpeed is the fastest replay; faster ones would poll in a busy loop
+const MaxSpeed = 3600
+
+// ParseSpeed parses a replay speed like 1, 60 or 60x, from 1x up to MaxSpeed
+func ParseSpeed(s string) (float64, error) {
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(strings.ToLower(s), "x"), 64)
+	// Written so NaN fails too
+	if err != nil || !(speed >= 1 && speed <= MaxSpeed) {
+		return 0, fmt.Errorf("invalid replay speed %q, expected a factor between 1x and %dx", s, MaxSpeed)
+	}
+	return speed, nil
+}
+
+// Clock runs simulated time from a start at a multiple of the base clock's
+// speed. Tickers and timers are scaled to the simulated time but deliver the
+// base clock's time on their channels.
+type Clock struct {
+	start  time.Time
+	speed  float64
+	base   clock.Clock
+	origin time.Time
+}
+
+// NewClock returns a clock at start that runs speed times faster than base
+func NewClock(start time.Time, speed float64, base clock.Clock) *Clock {
+	return &Clock{start: start, speed: speed, base: base, origin: base.Now()}
+}
+
+// Now returns the simulated time
+func (c *Clock) Now() time.Time {
+	return c.start.Add(c.simulated(c.base.Now().Sub(c.origin)))
+}
+
+// NewTicker returns a ticker firing every d of simulated time
+func (c *Clock) NewTicker(d time.Duration) clock.Ticker {
+	return c.base.NewTicker(c.real(d))
+}
+
+// NewTimer returns a timer firing after d of simulated time
+func (c *Clock) NewTimer(d time.Duration) clock.Timer {
+	return c.base.NewTimer(c.real(d))
+}
+
+// simulated converts a duration of the base clock to simulated time
+func (c *Clock) simulated(d time.Duration) time.Duration {
+	return time.Duration(float64(d) * c.speed)
+}
+
+// real converts a duration of simulated time to the base clock, keeping it
+// positive for tickers
+func (c *Clock) real(d time.Duration) time.Duration {
+	return max(time.Duration(float64(d)/c.speed), time.Nanosecond)
+}
@@ -0,0 +1,36 @@
+package replay
+
+import (
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+)
+
+func TestClock(t *testing.T) {
+	base := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := NewClock(day, 60, base)
+	if !c.Now().Equal(day) {
+		t.Errorf("Expected the replay to start at the recording, got %s", c.Now())
+	}
+
+	ticker := c.NewTicker(time.Minute)
+	base.Advance(time.Second)
+	<-ticker.C()
+	if !c.Now().Equal(day.Add(time.Minute)) {
+		t.Errorf("Expected a minute to pass every second at 60x, got %s", c.Now())
+	}
+}
+
+func TestParseSpeed(t *testing.T) {
+	for input, want := range map[string]float64{"1": 1, "60X": 60, "1.5x": 1.5} {
+		if got, err := ParseSpeed(input); err != nil || got != want {
+			t.Errorf("ParseSpeed(%q) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "0", "0.5x", "-2x", "fast", "nan", "10000x"} {
+		if _, err := ParseSpeed(input); err == nil {
+			t.Errorf("Expected %q to be rejected", input)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package replay
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// Provider serves the recorded prices at the time of its clock, standing in
+// for the price provider of the poller. Its answers depend only on the
+// clock, so a replay driven by a fake clock is deterministic.
+type Provider struct {
+	recording *Recording
+	clock     clock.Clock
+}
+
+// NewProvider creates a provider replaying the recording on the clock
+func NewProvider(recording *Recording, c clock.Clock) *Provider {
+	return &Provider{recording: recording, clock: c}
+}
+
+// Done reports whether the clock went past the last tick
+func (p *Provider) Done() bool {
+	return p.clock.Now().After(p.recording.End())
+}
+
+// FetchCryptoPrices returns the last recorded price of each coin. Coins
+// without a tick yet are reported as missing, like coins the provider does
+// not know.
+func (p *Provider) FetchCryptoPrices(cryptoIDs []string, currency models.Currency) ([]models.CryptoPrice, error) {
+	if currency != p.recording.Currency() {
+		return nil, fmt.Errorf("recording is in %s, not %s", p.recording.Currency(), currency)
+	}
+	now := p.clock.Now()
+	var prices []models.CryptoPrice
+	var missing missingError
+	for _, id := range cryptoIDs {
+		tick, ok := p.recording.PriceAt(id, now)
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		price := models.CryptoPrice{
+			ID:           id,
+			Symbol:       cmp.Or(tick.Symbol, id),
+			Name:         cmp.Or(tick.Name, id),
+			CurrentPrice: tick.Price,
+			Currency:     currency,
+			LastUpdated:  tick.At.Format(time.RFC3339),
+		}
+		if before, ok := p.recording.PriceAt(id, tick.At.Add(-24*time.Hour)); ok && before.Price.IsPositive() {
+			price.PriceChange24h = tick.Price.Sub(before.Price).Div(before.Price).Mul(hundred).InexactFloat64()
+		}
+		prices = append(prices, price)
+	}
+	if len(missing) > 0 {
+		return prices, missing
+	}
+	return prices, nil
+}
+
+// missingError lists the coins without a recorded tick yet
+type missingError []string
+
+func (e missingError) Error() string {
+	return "no recorded price for " + strings.Join(e, ", ")
+}
+
+// Missing implements the poller's check for unknown coins
+func (e missingError) Missing() []string {
+	return e
+}
@@ -0,0 +1,65 @@
+package replay
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/events"
+	"crypto-dashboard/internal/application/poller"
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestProvider(t *testing.T) {
+	r, _ := NewRecording([]Tick{tick("bitcoin", 0, 60000), tick("bitcoin", 24*60, 66000), tick("ethereum", 24*60+5, 3900)})
+	c := clock.NewFake(day.Add(24 * time.Hour))
+	p := NewProvider(r, c)
+
+	prices, err := p.FetchCryptoPrices([]string{"bitcoin", "ethereum"}, models.USD)
+	var missing interface{ Missing() []string }
+	if !errors.As(err, &missing) || len(missing.Missing()) != 1 || missing.Missing()[0] != "ethereum" {
+		t.Errorf("Expected ethereum to be missing before its first tick, got %v", err)
+	}
+	if len(prices) != 1 || prices[0].CurrentPrice.IntPart() != 66000 || prices[0].PriceChange24h != 10 {
+		t.Errorf("Expected bitcoin up 10%% over the recorded day, got %+v", prices)
+	}
+	if _, err := p.FetchCryptoPrices([]string{"bitcoin"}, models.EUR); err == nil {
+		t.Error("Expected another currency to be rejected")
+	}
+
+	c.Advance(10 * time.Minute)
+	if !p.Done() {
+		t.Error("Expected the replay to be done after the last tick")
+	}
+}
+
+// recorder keeps every published event
+type recorder []events.Event
+
+func (r *recorder) Publish(e events.Event) { *r = append(*r, e) }
+
+func TestProvider_DrivesThePoller(t *testing.T) {
+	r, _ := NewRecording([]Tick{tick("bitcoin", 0, 60000), tick("bitcoin", 3, 61000), tick("bitcoin", 7, 59000)})
+	base := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := NewClock(r.Start(), 60, base)
+	p := poller.New(NewProvider(r, c), time.Minute, models.USD, []string{"bitcoin"})
+	p.SetClock(c)
+	var published recorder
+	p.SetPublisher(&published)
+
+	// Ten polls a second apart at 60x cover the recorded ten minutes
+	for range 10 {
+		p.PollOnce()
+		base.Advance(time.Second)
+	}
+	var changes []string
+	for _, e := range published {
+		if update, ok := e.(events.PriceUpdated); ok {
+			changes = append(changes, update.At.Format("15:04")+"="+update.Price.CurrentPrice.String())
+		}
+	}
+	if got := len(changes); got != 3 || changes[1] != "00:03=61000" || changes[2] != "00:07=59000" {
+		t.Errorf("Expected the recorded price changes at their simulated times, got %v", changes)
+	}
+}
@@ -0,0 +1,49 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"crypto-dashboard/internal/application/events"
+)
+
+// Recorder writes every polled price as a tick, building recordings to replay
+type Recorder struct {
+	enc *json.Encoder
+}
+
+// NewRecorder creates a recorder writing JSON lines to w
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{enc: json.NewEncoder(w)}
+}
+
+// Consume records a tick for every PriceUpdated event received until the
+// channel is closed or the context is cancelled, returning the first write error
+func (r *Recorder) Consume(ctx context.Context, updates <-chan events.Event) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			update, ok := event.(events.PriceUpdated)
+			if !ok {
+				continue
+			}
+			tick := Tick{
+				At:       update.At.UTC(),
+				CryptoID: update.Price.ID,
+				Symbol:   update.Price.Symbol,
+				Name:     update.Price.Name,
+				Price:    update.Price.CurrentPrice,
+				Currency: update.Price.Currency,
+			}
+			if err := r.enc.Encode(tick); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Package replay feeds recorded price ticks through the dashboard at a
+// configurable speed, so alert rules, the interfaces and backtests can be
+// demonstrated and debugged against past market days
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Tick is a recorded price observation. Recordings are stored as one JSON
+// tick per line.
+type Tick struct {
+	At       time.Time       `json:"at"`
+	CryptoID string          `json:"crypto_id"`
+	Symbol   string          `json:"symbol,omitempty"`
+	Name     string          `json:"name,omitempty"`
+	Price    decimal.Decimal `json:"price"`
+	Currency models.Currency `json:"currency"`
+}
+
+// ErrEmptyRecording is returned for recordings without any tick
+var ErrEmptyRecording = errors.New("recording has no ticks")
+
+// Recording is a span of recorded ticks in time order
+type Recording struct {
+	ticks    []Tick
+	byCoin   map[string][]Tick
+	currency models.Currency
+}
+
+// NewRecording sorts the ticks into a recording. Every tick must be in the
+// same currency.
+func NewRecording(ticks []Tick) (*Recording, error) {
+	if len(ticks) == 0 {
+		return nil, ErrEmptyRecording
+	}
+	r := &Recording{ticks: slices.Clone(ticks), byCoin: make(map[string][]Tick), currency: ticks[0].Currency}
+	sort.SliceStable(r.ticks, func(i, j int) bool { return r.ticks[i].At.Before(r.ticks[j].At) })
+	for _, t := range r.ticks {
+		if t.Currency != r.currency {
+			return nil, fmt.Errorf("recording mixes %s and %s ticks", r.currency, t.Currency)
+		}
+		if t.CryptoID == "" || t.At.IsZero() {
+			return nil, fmt.Errorf("tick without a coin or time: %+v", t)
+		}
+		r.byCoin[t.CryptoID] = append(r.byCoin[t.CryptoID], t)
+	}
+	return r, nil
+}
+
+// ReadRecording reads a recording of JSON lines
+func ReadRecording(r io.Reader) (*Recording, error) {
+	var ticks []Tick
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var t Tick
+		if err := json.Unmarshal(scanner.Bytes(), &t); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		ticks = append(ticks, t)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewRecording(ticks)
+}
+
+// WriteTicks writes ticks as JSON lines
+func WriteTicks(w io.Writer, ticks []Tick) error {
+	enc := json.NewEncoder(w)
+	for _, t := range ticks {
+		if err := enc.Encode(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TicksFromHistory turns a provider price history into ticks
+func TicksFromHistory(cryptoID string, currency models.Currency, points []models.PricePoint) []Tick {
+	ticks := make([]Tick, len(points))
+	for i, p := range points {
+		ticks[i] = Tick{At: p.Time.UTC(), CryptoID: cryptoID, Price: decimal.NewFromFloat(p.Price), Currency: currency}
+	}
+	return ticks
+}
+
+// Start returns the time of the first tick
+func (r *Recording) Start() time.Time {
+	return r.ticks[0].At
+}
+
+// End returns the time of the last tick
+func (r *Recording) End() time.Time {
+	return r.ticks[len(r.ticks)-1].At
+}
+
+// Currency returns the currency of every tick
+func (r *Recording) Currency() models.Currency {
+	return r.currency
+}
+
+// Coins returns the recorded coin IDs, sorted
+func (r *Recording) Coins() []string {
+	coins := make([]string, 0, len(r.byCoin))
+	for id := range r.byCoin {
+		coins = append(coins, id)
+	}
+	sort.Strings(coins)
+	return coins
+}
+
+// Len returns the number of ticks
+func (r *Recording) Len() int {
+	return len(r.ticks)
+}
+
+// PriceAt returns the last tick of the coin at or before at
+func (r *Recording) PriceAt(cryptoID string, at time.Time) (Tick, bool) {
+	ticks := r.byCoin[cryptoID]
+	i := sort.Search(len(ticks), func(i int) bool { return ticks[i].At.After(at) })
+	if i == 0 {
+		return Tick{}, false
+	}
+	return ticks[i-1], true
+}
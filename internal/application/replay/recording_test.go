@@ -0,0 +1,87 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/events"
+	"crypto-dashboard/internal/domain/models"
+)
+
+var day = time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+
+// tick returns a USD tick of the coin minutes into the day
+func tick(id string, minutes int, price int64) Tick {
+	return Tick{At: day.Add(time.Duration(minutes) * time.Minute), CryptoID: id, Price: decimal.NewFromInt(price), Currency: models.USD}
+}
+
+func TestRecording(t *testing.T) {
+	r, err := NewRecording([]Tick{tick("ethereum", 5, 3900), tick("bitcoin", 10, 68000), tick("bitcoin", 0, 67000)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !r.Start().Equal(day) || !r.End().Equal(day.Add(10*time.Minute)) || r.Len() != 3 {
+		t.Errorf("Expected the ticks in time order, got %s to %s", r.Start(), r.End())
+	}
+	if got := strings.Join(r.Coins(), ","); got != "bitcoin,ethereum" {
+		t.Errorf("Expected the recorded coins, got %s", got)
+	}
+	if got, ok := r.PriceAt("bitcoin", day.Add(9*time.Minute)); !ok || got.Price.IntPart() != 67000 {
+		t.Errorf("Expected the last tick before the time, got %+v", got)
+	}
+	if _, ok := r.PriceAt("ethereum", day.Add(4*time.Minute)); ok {
+		t.Error("Expected no price before the first tick")
+	}
+
+	if _, err := NewRecording(nil); err != ErrEmptyRecording {
+		t.Errorf("Expected an empty recording to be rejected, got %v", err)
+	}
+	eur := tick("bitcoin", 1, 62000)
+	eur.Currency = models.EUR
+	if _, err := NewRecording([]Tick{tick("bitcoin", 0, 67000), eur}); err == nil {
+		t.Error("Expected mixed currencies to be rejected")
+	}
+}
+
+func TestRecorder_RoundTrip(t *testing.T) {
+	updates := make(chan events.Event, 3)
+	updates <- events.PriceUpdated{Price: models.CryptoPrice{ID: "bitcoin", Symbol: "btc", CurrentPrice: decimal.RequireFromString("67000.5"), Currency: models.USD}, At: day}
+	updates <- events.AlertTriggered{}
+	updates <- events.PriceUpdated{Price: models.CryptoPrice{ID: "bitcoin", CurrentPrice: decimal.NewFromInt(67100), Currency: models.USD}, At: day.Add(time.Minute)}
+	close(updates)
+
+	var buf bytes.Buffer
+	if err := NewRecorder(&buf).Consume(context.Background(), updates); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	r, err := ReadRecording(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	first, _ := r.PriceAt("bitcoin", day)
+	if r.Len() != 2 || first.Symbol != "btc" || first.Price.String() != "67000.5" {
+		t.Errorf("Expected the price updates to round trip, got %d ticks starting with %+v", r.Len(), first)
+	}
+
+	if _, err := ReadRecording(strings.NewReader("{\"at\":\"2024-03-05T00:00:00Z\"}\nnot json\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected the bad line to be reported, got %v", err)
+	}
+}
+
+func TestTicksFromHistory(t *testing.T) {
+	points := []models.PricePoint{{Price: 67000, Time: day}, {Price: 67250.5, Time: day.Add(5 * time.Minute)}}
+	var buf bytes.Buffer
+	WriteTicks(&buf, TicksFromHistory("bitcoin", models.USD, points))
+	r, err := ReadRecording(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if last, _ := r.PriceAt("bitcoin", r.End()); last.Price.String() != "67250.5" || r.Currency() != models.USD {
+		t.Errorf("Expected the history as ticks, got %+v", last)
+	}
+}
@@ -0,0 +1,75 @@
+// Package risk provides position sizing and R-multiple tracking for trade planning
+package risk
+
+import (
+	"errors"
+	"math"
+)
+
+// PositionSizeInput describes a planned trade and how much of the account may be risked
+type PositionSizeInput struct {
+	AccountSize float64 `json:"account_size"`
+	RiskPercent float64 `json:"risk_percent"`
+	EntryPrice  float64 `json:"entry_price"`
+	StopLoss    float64 `json:"stop_loss"`
+	TakeProfit  float64 `json:"take_profit,omitempty"`
+}
+
+// PositionSize is the result of the position sizing calculation
+type PositionSize struct {
+	Direction       string  `json:"direction"`
+	Quantity        float64 `json:"quantity"`
+	PositionValue   float64 `json:"position_value"`
+	RiskAmount      float64 `json:"risk_amount"`
+	RiskPerUnit     float64 `json:"risk_per_unit"`
+	AccountFraction float64 `json:"account_fraction"`
+	RewardToRisk    float64 `json:"reward_to_risk,omitempty"`
+}
+
+// Validate ensures the input can be sized
+func (in PositionSizeInput) Validate() error {
+	if in.AccountSize <= 0 {
+		return errors.New("account size must be positive")
+	}
+	if in.RiskPercent <= 0 || in.RiskPercent > 100 {
+		return errors.New("risk percent must be between 0 and 100")
+	}
+	if in.EntryPrice <= 0 || in.StopLoss <= 0 {
+		return errors.New("entry and stop prices must be positive")
+	}
+	if in.EntryPrice == in.StopLoss {
+		return errors.New("stop loss must differ from entry price")
+	}
+	return nil
+}
+
+// CalculatePositionSize returns how many units to buy (or sell short) so that
+// hitting the stop loses exactly RiskPercent of the account
+func CalculatePositionSize(in PositionSizeInput) (PositionSize, error) {
+	if err := in.Validate(); err != nil {
+		return PositionSize{}, err
+	}
+
+	direction := "long"
+	if in.StopLoss > in.EntryPrice {
+		direction = "short"
+	}
+
+	riskAmount := in.AccountSize * in.RiskPercent / 100
+	riskPerUnit := math.Abs(in.EntryPrice - in.StopLoss)
+	quantity := riskAmount / riskPerUnit
+	positionValue := quantity * in.EntryPrice
+
+	result := PositionSize{
+		Direction:       direction,
+		Quantity:        quantity,
+		PositionValue:   positionValue,
+		RiskAmount:      riskAmount,
+		RiskPerUnit:     riskPerUnit,
+		AccountFraction: positionValue / in.AccountSize,
+	}
+	if in.TakeProfit > 0 {
+		result.RewardToRisk = math.Abs(in.TakeProfit-in.EntryPrice) / riskPerUnit
+	}
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package risk
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculatePositionSize(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      PositionSizeInput
+		wantQty    float64
+		wantDir    string
+		wantReward float64
+		wantErr    bool
+	}{
+		{
+			name:       "long with target",
+			input:      PositionSizeInput{AccountSize: 10000, RiskPercent: 1, EntryPrice: 50000, StopLoss: 48000, TakeProfit: 56000},
+			wantQty:    0.05,
+			wantDir:    "long",
+			wantReward: 3,
+		},
+		{
+			name:    "short",
+			input:   PositionSizeInput{AccountSize: 5000, RiskPercent: 2, EntryPrice: 100, StopLoss: 110},
+			wantQty: 10,
+			wantDir: "short",
+		},
+		{
+			name:    "invalid risk percent",
+			input:   PositionSizeInput{AccountSize: 5000, RiskPercent: 0, EntryPrice: 100, StopLoss: 90},
+			wantErr: true,
+		},
+		{
+			name:    "stop equals entry",
+			input:   PositionSizeInput{AccountSize: 5000, RiskPercent: 1, EntryPrice: 100, StopLoss: 100},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CalculatePositionSize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CalculatePositionSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if math.Abs(got.Quantity-tt.wantQty) > 1e-9 {
+				t.Errorf("Expected quantity %f, got %f", tt.wantQty, got.Quantity)
+			}
+			if got.Direction != tt.wantDir {
+				t.Errorf("Expected direction %s, got %s", tt.wantDir, got.Direction)
+			}
+			if math.Abs(got.RewardToRisk-tt.wantReward) > 1e-9 {
+				t.Errorf("Expected reward to risk %f, got %f", tt.wantReward, got.RewardToRisk)
+			}
+		})
+	}
+}
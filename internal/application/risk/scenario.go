@@ -0,0 +1,136 @@
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Shock groups that apply to several coins at once. A shock keyed by a coin ID
+// always takes precedence over its group.
+const (
+	GroupStablecoins = "stablecoins"
+	GroupAlts        = "alts"
+)
+
+// stablecoins lists the CoinGecko IDs treated as stable by the stablecoins group
+var stablecoins = map[string]bool{
+	"tether":            true,
+	"usd-coin":          true,
+	"dai":               true,
+	"true-usd":          true,
+	"first-digital-usd": true,
+	"paypal-usd":        true,
+	"ethena-usde":       true,
+	"frax":              true,
+}
+
+// IsStablecoin reports whether the coin belongs to the stablecoins group
+func IsStablecoin(cryptoID string) bool {
+	return stablecoins[cryptoID]
+}
+
+// Scenario is a set of hypothetical price shocks in percent, e.g. {"bitcoin": -40, "alts": -60}
+type Scenario struct {
+	Name   string             `json:"name"`
+	Shocks map[string]float64 `json:"shocks"`
+}
+
+// DefaultScenarios are used when a stress test does not define its own
+var DefaultScenarios = []Scenario{
+	{Name: "Crypto winter", Shocks: map[string]float64{"bitcoin": -40, GroupAlts: -60, GroupStablecoins: 0}},
+	{Name: "Alt season", Shocks: map[string]float64{"bitcoin": 20, GroupAlts: 80, GroupStablecoins: 0}},
+	{Name: "Stablecoin depeg", Shocks: map[string]float64{"bitcoin": -15, GroupAlts: -25, GroupStablecoins: -10}},
+}
+
+// Validate checks that no shock wipes out more than the whole position
+func (s Scenario) Validate() error {
+	if s.Name == "" {
+		return errors.New("scenario name cannot be empty")
+	}
+	if len(s.Shocks) == 0 {
+		return fmt.Errorf("scenario %q has no shocks", s.Name)
+	}
+	for key, pct := range s.Shocks {
+		if pct < -100 {
+			return fmt.Errorf("scenario %q: shock for %s cannot be below -100%%", s.Name, key)
+		}
+	}
+	return nil
+}
+
+// ShockFor returns the percentage change applied to a coin: its own shock,
+// otherwise its group's shock, otherwise no change
+func (s Scenario) ShockFor(cryptoID string) float64 {
+	if pct, ok := s.Shocks[cryptoID]; ok {
+		return pct
+	}
+	switch {
+	case cryptoID == "bitcoin":
+		return 0
+	case IsStablecoin(cryptoID):
+		return s.Shocks[GroupStablecoins]
+	default:
+		return s.Shocks[GroupAlts]
+	}
+}
+
+// PositionImpact is a single holding revalued under a scenario
+type PositionImpact struct {
+	CryptoID     string  `json:"crypto_id"`
+	Quantity     float64 `json:"quantity"`
+	Price        float64 `json:"price"`
+	Value        float64 `json:"value"`
+	ShockPercent float64 `json:"shock_percent"`
+	ShockedValue float64 `json:"shocked_value"`
+	Impact       float64 `json:"impact"`
+}
+
+// ScenarioResult is the whole portfolio revalued under a scenario
+type ScenarioResult struct {
+	Scenario      string           `json:"scenario"`
+	Positions     []PositionImpact `json:"positions"`
+	TotalValue    float64          `json:"total_value"`
+	ShockedValue  float64          `json:"shocked_value"`
+	Impact        float64          `json:"impact"`
+	ImpactPercent float64          `json:"impact_percent"`
+}
+
+// Revalue applies a scenario to holdings valued at the given prices. Positions
+// are ordered by impact, the biggest loss first.
+func Revalue(holdings, prices map[string]float64, scenario Scenario) (ScenarioResult, error) {
+	result := ScenarioResult{Scenario: scenario.Name, Positions: make([]PositionImpact, 0, len(holdings))}
+	for id, qty := range holdings {
+		price, ok := prices[id]
+		if !ok {
+			return ScenarioResult{}, fmt.Errorf("no price for %s", id)
+		}
+		shock := scenario.ShockFor(id)
+		value := qty * price
+		shocked := value * (1 + shock/100)
+		result.Positions = append(result.Positions, PositionImpact{
+			CryptoID:     id,
+			Quantity:     qty,
+			Price:        price,
+			Value:        value,
+			ShockPercent: shock,
+			ShockedValue: shocked,
+			Impact:       shocked - value,
+		})
+		result.TotalValue += value
+		result.ShockedValue += shocked
+	}
+
+	sort.Slice(result.Positions, func(i, j int) bool {
+		a, b := result.Positions[i], result.Positions[j]
+		if a.Impact != b.Impact {
+			return a.Impact < b.Impact
+		}
+		return a.CryptoID < b.CryptoID
+	})
+	result.Impact = result.ShockedValue - result.TotalValue
+	if result.TotalValue > 0 {
+		result.ImpactPercent = result.Impact / result.TotalValue * 100
+	}
+	return result, nil
+}
@@ -0,0 +1,90 @@
+package risk
+
+import (
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestScenario_ShockFor(t *testing.T) {
+	sc := Scenario{Name: "test", Shocks: map[string]float64{
+		"bitcoin": -40, "solana": -80, GroupAlts: -60, GroupStablecoins: -1,
+	}}
+
+	tests := []struct {
+		id       string
+		expected float64
+	}{
+		{"bitcoin", -40},
+		{"solana", -80},
+		{"ethereum", -60},
+		{"tether", -1},
+	}
+	for _, tt := range tests {
+		if got := sc.ShockFor(tt.id); got != tt.expected {
+			t.Errorf("Expected %s shock %f, got %f", tt.id, tt.expected, got)
+		}
+	}
+
+	// Bitcoin is never part of a group
+	if got := (Scenario{Shocks: map[string]float64{GroupAlts: -50}}).ShockFor("bitcoin"); got != 0 {
+		t.Errorf("Expected bitcoin to be unaffected by the alts group, got %f", got)
+	}
+}
+
+func TestRevalue(t *testing.T) {
+	holdings := map[string]float64{"bitcoin": 1, "ethereum": 10, "usd-coin": 5000}
+	prices := map[string]float64{"bitcoin": 50000, "ethereum": 3000, "usd-coin": 1}
+
+	result, err := Revalue(holdings, prices, DefaultScenarios[0])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.TotalValue != 85000 {
+		t.Errorf("Expected total value 85000, got %f", result.TotalValue)
+	}
+	if result.ShockedValue != 30000+12000+5000 {
+		t.Errorf("Expected shocked value 47000, got %f", result.ShockedValue)
+	}
+	if result.Impact != -38000 {
+		t.Errorf("Expected impact -38000, got %f", result.Impact)
+	}
+	if result.Positions[0].CryptoID != "bitcoin" || result.Positions[2].CryptoID != "usd-coin" {
+		t.Errorf("Expected positions ordered by impact, got %+v", result.Positions)
+	}
+
+	if _, err := Revalue(map[string]float64{"solana": 1}, prices, DefaultScenarios[0]); err == nil {
+		t.Error("Expected error for holding without price")
+	}
+}
+
+func TestService_StressTest(t *testing.T) {
+	service := NewService(&stubRepo{}, stubPrices{"bitcoin": 50000, "ethereum": 2000}, models.USD)
+
+	results, err := service.StressTest(map[string]float64{"bitcoin": 0.5, "ethereum": 5}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(results) != len(DefaultScenarios) {
+		t.Errorf("Expected %d default scenarios, got %d", len(DefaultScenarios), len(results))
+	}
+
+	tests := []struct {
+		name      string
+		holdings  map[string]float64
+		scenarios []Scenario
+	}{
+		{"empty holdings", nil, nil},
+		{"negative quantity", map[string]float64{"bitcoin": -1}, nil},
+		{"unnamed scenario", map[string]float64{"bitcoin": 1}, []Scenario{{Shocks: map[string]float64{"bitcoin": -10}}}},
+		{"shock below -100%", map[string]float64{"bitcoin": 1}, []Scenario{{Name: "x", Shocks: map[string]float64{"bitcoin": -150}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.StressTest(tt.holdings, tt.scenarios); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// ErrNotFound is returned when a watch order does not exist
+var ErrNotFound = errors.New("watch order not found")
+
+// WatchOrderRepository persists watch orders
+type WatchOrderRepository interface {
+	Save(order models.WatchOrder) (models.WatchOrder, error)
+	List() ([]models.WatchOrder, error)
+	Delete(id string) error
+}
+
+// PriceFetcher returns current prices for a set of coins
+type PriceFetcher interface {
+	FetchCryptoPrices(cryptoIDs []string, currency models.Currency) ([]models.CryptoPrice, error)
+}
+
+// WatchOrderStatus is a watch order evaluated against the current market price
+type WatchOrderStatus struct {
+	Order        models.WatchOrder `json:"order"`
+	CurrentPrice float64           `json:"current_price"`
+	RMultiple    float64           `json:"r_multiple"`
+	TargetR      float64           `json:"target_r,omitempty"`
+	OpenPnL      float64           `json:"open_pnl"`
+}
+
+// Service manages watch orders and tracks their R-multiples
+type Service struct {
+	repo     WatchOrderRepository
+	prices   PriceFetcher
+	currency models.Currency
+}
+
+// NewService creates a risk service
+func NewService(repo WatchOrderRepository, prices PriceFetcher, currency models.Currency) *Service {
+	return &Service{repo: repo, prices: prices, currency: currency}
+}
+
+// CreateWatchOrder validates and stores a new watch order
+func (s *Service) CreateWatchOrder(order models.WatchOrder) (models.WatchOrder, error) {
+	if err := order.Validate(); err != nil {
+		return models.WatchOrder{}, err
+	}
+	if order.CreatedAt.IsZero() {
+		order.CreatedAt = time.Now().UTC()
+	}
+	return s.repo.Save(order)
+}
+
+// DeleteWatchOrder removes a watch order
+func (s *Service) DeleteWatchOrder(id string) error {
+	return s.repo.Delete(id)
+}
+
+// WatchOrderStatuses returns every watch order with its current R-multiple
+func (s *Service) WatchOrderStatuses() ([]WatchOrderStatus, error) {
+	orders, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return []WatchOrderStatus{}, nil
+	}
+
+	seen := make(map[string]bool)
+	var ids []string
+	for _, o := range orders {
+		if !seen[o.CryptoID] {
+			seen[o.CryptoID] = true
+			ids = append(ids, o.CryptoID)
+		}
+	}
+
+	prices, err := s.prices.FetchCryptoPrices(ids, s.currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch prices for watch orders: %w", err)
+	}
+	current := make(map[string]float64, len(prices))
+	for _, p := range prices {
+		current[p.ID] = p.PriceFloat()
+	}
+
+	statuses := make([]WatchOrderStatus, len(orders))
+	for i, o := range orders {
+		price := current[o.CryptoID]
+		pnl := (price - o.EntryPrice) * o.Quantity
+		if !o.IsLong() {
+			pnl = -pnl
+		}
+		statuses[i] = WatchOrderStatus{
+			Order:        o,
+			CurrentPrice: price,
+			RMultiple:    o.RMultiple(price),
+			TargetR:      o.TargetRMultiple(),
+			OpenPnL:      pnl,
+		}
+	}
+	return statuses, nil
+}
+
+// StressTest revalues the holdings at current prices under each scenario.
+// DefaultScenarios are used when none are given.
+func (s *Service) StressTest(holdings map[string]float64, scenarios []Scenario) ([]ScenarioResult, error) {
+	if len(holdings) == 0 {
+		return nil, errors.New("holdings cannot be empty")
+	}
+	if len(scenarios) == 0 {
+		scenarios = DefaultScenarios
+	}
+
+	ids := make([]string, 0, len(holdings))
+	for id, qty := range holdings {
+		if qty <= 0 {
+			return nil, fmt.Errorf("quantity of %s must be positive", id)
+		}
+		ids = append(ids, id)
+	}
+	for _, sc := range scenarios {
+		if err := sc.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	fetched, err := s.prices.FetchCryptoPrices(ids, s.currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch prices for stress test: %w", err)
+	}
+	prices := make(map[string]float64, len(fetched))
+	for _, p := range fetched {
+		prices[p.ID] = p.PriceFloat()
+	}
+
+	results := make([]ScenarioResult, len(scenarios))
+	for i, sc := range scenarios {
+		if results[i], err = Revalue(holdings, prices, sc); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
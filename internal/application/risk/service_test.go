@@ -0,0 +1,62 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubRepo struct {
+	orders []models.WatchOrder
+}
+
+func (r *stubRepo) Save(order models.WatchOrder) (models.WatchOrder, error) {
+	order.ID = "1"
+	r.orders = append(r.orders, order)
+	return order, nil
+}
+
+func (r *stubRepo) List() ([]models.WatchOrder, error) { return r.orders, nil }
+
+func (r *stubRepo) Delete(id string) error { return nil }
+
+type stubPrices map[string]float64
+
+func (s stubPrices) FetchCryptoPrices(ids []string, currency models.Currency) ([]models.CryptoPrice, error) {
+	var prices []models.CryptoPrice
+	for _, id := range ids {
+		prices = append(prices, models.CryptoPrice{ID: id, CurrentPrice: decimal.NewFromFloat(s[id]), Currency: currency})
+	}
+	return prices, nil
+}
+
+func TestService_WatchOrderStatuses(t *testing.T) {
+	service := NewService(&stubRepo{}, stubPrices{"bitcoin": 52000}, models.USD)
+
+	_, err := service.CreateWatchOrder(models.WatchOrder{
+		CryptoID: "bitcoin", EntryPrice: 50000, StopLoss: 49000, TakeProfit: 54000, Quantity: 0.5,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	statuses, err := service.WatchOrderStatuses()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(statuses) != 1 {
+		t.Fatalf("Expected 1 status, got %d", len(statuses))
+	}
+	if statuses[0].RMultiple != 2 || statuses[0].TargetR != 4 || statuses[0].OpenPnL != 1000 {
+		t.Errorf("Unexpected status: %+v", statuses[0])
+	}
+}
+
+func TestService_CreateWatchOrder_Invalid(t *testing.T) {
+	service := NewService(&stubRepo{}, stubPrices{}, models.USD)
+	if _, err := service.CreateWatchOrder(models.WatchOrder{CryptoID: "bitcoin"}); err == nil {
+		t.Error("Expected validation error, got nil")
+	}
+}
@@ -0,0 +1,199 @@
+// Package search is an in-memory full-text index over the free text the
+// dashboard stores: calendar events, incidents and the alert log. Results are
+// grouped by kind.
+//
+// It does not cover user notes, news headlines or an audit log, which the
+// dashboard does not keep, and it is not backed by SQLite FTS5 or Postgres
+// tsvector. Those sources would be added as further Sources.
+package search
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Kind is the type of an indexed document
+type Kind string
+
+// Document kinds
+const (
+	KindEvent    Kind = "event"
+	KindIncident Kind = "incident"
+	KindAlert    Kind = "alert"
+)
+
+// titleWeight is how much more a term counts in a title than in the text
+const titleWeight = 2
+
+// snippetWords is the number of words of text returned around the first match
+const snippetWords = 12
+
+// Document is a searchable record. Documents of the DefaultOwner, or without
+// an owner, are visible to every session.
+type Document struct {
+	Kind     Kind      `json:"kind"`
+	ID       string    `json:"id"`
+	Owner    string    `json:"-"`
+	Title    string    `json:"title"`
+	Text     string    `json:"-"`
+	CryptoID string    `json:"crypto_id,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// Hit is a matching document with its relevance and an excerpt of its text
+type Hit struct {
+	Document
+	Snippet string  `json:"snippet,omitempty"`
+	Score   float64 `json:"score"`
+}
+
+// visibleTo reports whether the owner may see the document
+func (d Document) visibleTo(owner string) bool {
+	return d.Owner == "" || d.Owner == models.DefaultOwner || d.Owner == owner
+}
+
+type posting struct {
+	doc    int
+	weight int
+}
+
+// Index is an inverted index of documents. It is immutable once built, so it
+// can be queried concurrently.
+type Index struct {
+	docs  []Document
+	terms map[string][]posting
+	// sorted holds the terms in order so prefixes are found by binary search
+	sorted []string
+}
+
+// NewIndex tokenizes and indexes the documents
+func NewIndex(docs []Document) *Index {
+	idx := &Index{docs: docs, terms: make(map[string][]posting)}
+	for i, d := range docs {
+		weights := make(map[string]int)
+		for _, t := range tokenize(d.Title) {
+			weights[t] += titleWeight
+		}
+		for _, t := range tokenize(d.Text + " " + d.CryptoID) {
+			weights[t]++
+		}
+		for t, w := range weights {
+			idx.terms[t] = append(idx.terms[t], posting{doc: i, weight: w})
+		}
+	}
+	idx.sorted = make([]string, 0, len(idx.terms))
+	for t := range idx.terms {
+		idx.sorted = append(idx.sorted, t)
+	}
+	slices.Sort(idx.sorted)
+	return idx
+}
+
+// Len returns the number of indexed documents
+func (idx *Index) Len() int {
+	return len(idx.docs)
+}
+
+// Search returns the documents visible to the owner that match every term of
+// the query, best first. Terms match words they start, so "unl" finds
+// "unlock"; an exact word scores higher than a longer one it starts. Rare
+// terms weigh more than common ones.
+func (idx *Index) Search(owner, query string) []Hit {
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	scores := make(map[int]float64)
+	for n, term := range terms {
+		matched := idx.match(term)
+		for doc := range scores {
+			if _, ok := matched[doc]; !ok {
+				delete(scores, doc)
+			}
+		}
+		for doc, score := range matched {
+			if n == 0 {
+				scores[doc] = score
+			} else if _, ok := scores[doc]; ok {
+				scores[doc] += score
+			}
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for doc, score := range scores {
+		d := idx.docs[doc]
+		if !d.visibleTo(owner) {
+			continue
+		}
+		hits = append(hits, Hit{Document: d, Snippet: snippet(d.Text, terms), Score: math.Round(score*1000) / 1000})
+	}
+	slices.SortFunc(hits, func(a, b Hit) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return b.At.Compare(a.At)
+	})
+	return hits
+}
+
+// match scores the documents containing a word starting with term, weighting
+// each word by its inverse document frequency
+func (idx *Index) match(term string) map[int]float64 {
+	scores := make(map[int]float64)
+	for i, _ := slices.BinarySearch(idx.sorted, term); i < len(idx.sorted) && strings.HasPrefix(idx.sorted[i], term); i++ {
+		word := idx.sorted[i]
+		postings := idx.terms[word]
+		idf := math.Log(1 + float64(len(idx.docs))/float64(len(postings)))
+		if word != term {
+			// A prefix is a weaker match than the whole word
+			idf /= 2
+		}
+		for _, p := range postings {
+			scores[p.doc] = max(scores[p.doc], float64(p.weight)*idf)
+		}
+	}
+	return scores
+}
+
+// tokenize lowercases text and splits it into words of letters and digits
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// snippet returns the words of text around the first one matching a term,
+// with an ellipsis where text was cut
+func snippet(text string, terms []string) string {
+	words := strings.Fields(text)
+	if len(words) <= snippetWords {
+		return strings.Join(words, " ")
+	}
+	first := 0
+	for i, w := range words {
+		if slices.ContainsFunc(tokenize(w), func(t string) bool {
+			return slices.ContainsFunc(terms, func(term string) bool { return strings.HasPrefix(t, term) })
+		}) {
+			first = i
+			break
+		}
+	}
+	start := max(0, min(first-snippetWords/3, len(words)-snippetWords))
+	end := start + snippetWords
+	s := strings.Join(words[start:end], " ")
+	if start > 0 {
+		s = "…" + s
+	}
+	if end < len(words) {
+		s += "…"
+	}
+	return s
+}
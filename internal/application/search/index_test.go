@@ -0,0 +1,69 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func testDocs() []Document {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	return []Document{
+		{Kind: KindEvent, ID: "e1", Title: "Arbitrum token unlock", Text: "92 million ARB released to the team and investors", CryptoID: "arbitrum", At: day},
+		{Kind: KindEvent, ID: "e2", Owner: "alice", Title: "Rebalance portfolio", Text: "Move profits from the arbitrum unlock into bitcoin", At: day.AddDate(0, 0, 1)},
+		{Kind: KindIncident, ID: "i1", Title: "provider outage", Text: "CoinGecko timeouts after 3 failed polls", At: day},
+		{Kind: KindAlert, ID: "1", Title: "bitcoin price below", Text: "bitcoin crossed below 60000 at 59990.5", CryptoID: "bitcoin", At: day},
+	}
+}
+
+func TestIndex_Search(t *testing.T) {
+	idx := NewIndex(testDocs())
+
+	hits := idx.Search("bob", "unlock")
+	if len(hits) != 1 || hits[0].ID != "e1" {
+		t.Fatalf("Expected only the shared unlock for bob, got %+v", hits)
+	}
+
+	hits = idx.Search("alice", "arbitrum unlock")
+	if len(hits) != 2 || hits[0].ID != "e1" {
+		t.Fatalf("Expected the titled unlock first, got %+v", hits)
+	}
+
+	if hits := idx.Search("alice", "unl"); len(hits) != 2 {
+		t.Errorf("Expected a prefix to match, got %+v", hits)
+	}
+	if hits := idx.Search("alice", "bitcoin outage"); len(hits) != 0 {
+		t.Errorf("Expected every term to be required, got %+v", hits)
+	}
+	if hits := idx.Search("alice", "COINGECKO"); len(hits) != 1 || hits[0].Kind != KindIncident {
+		t.Errorf("Expected a case-insensitive match, got %+v", hits)
+	}
+	if hits := idx.Search("alice", "  ,"); hits != nil {
+		t.Errorf("Expected no hits without terms, got %+v", hits)
+	}
+}
+
+func TestIndex_ExactWordRanksAbovePrefix(t *testing.T) {
+	idx := NewIndex([]Document{
+		{Kind: KindEvent, ID: "long", Title: "Listings roundup"},
+		{Kind: KindEvent, ID: "exact", Title: "Exchange listing"},
+	})
+	hits := idx.Search(models.DefaultOwner, "listing")
+	if len(hits) != 2 || hits[0].ID != "exact" {
+		t.Errorf("Expected the exact word first, got %+v", hits)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	text := "one two three four five six seven eight nine ten eleven twelve thirteen fourteen fifteen sixteen"
+	if got := snippet(text, []string{"fifteen"}); got != "…five six seven eight nine ten eleven twelve thirteen fourteen fifteen sixteen" {
+		t.Errorf("Unexpected snippet: %q", got)
+	}
+	if got := snippet(text, []string{"six"}); got != "…two three four five six seven eight nine ten eleven twelve thirteen…" {
+		t.Errorf("Unexpected snippet: %q", got)
+	}
+	if got := snippet("short text", []string{"missing"}); got != "short text" {
+		t.Errorf("Unexpected snippet: %q", got)
+	}
+}
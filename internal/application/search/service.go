@@ -0,0 +1,185 @@
+package search
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// DefaultMaxAge is how long an index is reused before it is rebuilt from the sources
+const DefaultMaxAge = 15 * time.Second
+
+// Source lists the documents of one kind
+type Source interface {
+	Documents() ([]Document, error)
+}
+
+// SourceFunc adapts a function to a Source
+type SourceFunc func() ([]Document, error)
+
+// Documents implements Source
+func (f SourceFunc) Documents() ([]Document, error) { return f() }
+
+// Group holds the best hits of one kind and how many matched in total
+type Group struct {
+	Kind  Kind  `json:"kind"`
+	Total int   `json:"total"`
+	Hits  []Hit `json:"hits"`
+}
+
+// Results are the hits of a query grouped by kind, the group with the best hit first
+type Results struct {
+	Query  string  `json:"query"`
+	Total  int     `json:"total"`
+	Groups []Group `json:"groups"`
+}
+
+// Service searches the documents of its sources. The index is rebuilt when it
+// is older than the max age, so results may lag behind writes by that long.
+type Service struct {
+	sources []Source
+	maxAge  time.Duration
+	clock   clock.Clock
+
+	mu      sync.Mutex
+	index   *Index
+	built   time.Time
+	partial error
+}
+
+// NewService creates a search service over the sources
+func NewService(maxAge time.Duration, sources ...Source) *Service {
+	return &Service{sources: sources, maxAge: maxAge, clock: clock.Real}
+}
+
+// SetClock replaces the system clock, for tests
+func (s *Service) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Invalidate makes the next search rebuild the index
+func (s *Service) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.index = nil
+}
+
+// Search returns up to perGroup hits of each kind matching the query for the
+// owner. When a source fails the others are still searched and the results
+// are returned together with the error.
+func (s *Service) Search(owner, query string, perGroup int) (Results, error) {
+	results := Results{Query: strings.TrimSpace(query), Groups: []Group{}}
+	idx, err := s.current()
+
+	groups := make(map[Kind]*Group)
+	for _, hit := range idx.Search(owner, query) {
+		g, ok := groups[hit.Kind]
+		if !ok {
+			g = &Group{Kind: hit.Kind}
+			groups[hit.Kind] = g
+		}
+		g.Total++
+		results.Total++
+		if len(g.Hits) < perGroup {
+			g.Hits = append(g.Hits, hit)
+		}
+	}
+	for _, g := range groups {
+		results.Groups = append(results.Groups, *g)
+	}
+	slices.SortFunc(results.Groups, func(a, b Group) int {
+		if c := cmp.Compare(b.Hits[0].Score, a.Hits[0].Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Kind, b.Kind)
+	})
+	return results, err
+}
+
+// current returns the index, rebuilding it when it is missing or too old
+func (s *Service) current() (*Index, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.index != nil && s.clock.Now().Sub(s.built) < s.maxAge {
+		return s.index, s.partial
+	}
+
+	var docs []Document
+	var errs []error
+	for _, source := range s.sources {
+		found, err := source.Documents()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		docs = append(docs, found...)
+	}
+	s.index, s.built = NewIndex(docs), s.clock.Now()
+	s.partial = nil
+	if err := errors.Join(errs...); err != nil {
+		s.partial = fmt.Errorf("search index is incomplete: %w", err)
+	}
+	return s.index, s.partial
+}
+
+// EventDocuments turns calendar events into documents visible to their owner
+func EventDocuments(events []models.Event) []Document {
+	docs := make([]Document, len(events))
+	for i, e := range events {
+		docs[i] = Document{
+			Kind:     KindEvent,
+			ID:       e.ID,
+			Owner:    e.Owner,
+			Title:    e.Title,
+			Text:     e.Description,
+			CryptoID: e.CryptoID,
+			At:       e.Start,
+		}
+	}
+	return docs
+}
+
+// IncidentDocuments turns incidents into documents visible to every session
+func IncidentDocuments(incidents []models.Incident) []Document {
+	docs := make([]Document, len(incidents))
+	for i, inc := range incidents {
+		title := strings.ReplaceAll(string(inc.Kind), "_", " ")
+		if inc.CryptoID != "" {
+			title += " of " + inc.CryptoID
+		}
+		docs[i] = Document{
+			Kind:     KindIncident,
+			ID:       inc.ID,
+			Title:    title,
+			Text:     inc.Description,
+			CryptoID: inc.CryptoID,
+			At:       inc.Started,
+		}
+	}
+	return docs
+}
+
+// AlertDocuments turns fired alerts into documents visible to every session.
+// Alerts have no ID of their own, so they are numbered from the most recent.
+func AlertDocuments(alerts []models.Alert) []Document {
+	docs := make([]Document, len(alerts))
+	for i, a := range alerts {
+		docs[i] = Document{
+			Kind:     KindAlert,
+			ID:       strconv.Itoa(i + 1),
+			Title:    fmt.Sprintf("%s %s", a.CryptoID, strings.ReplaceAll(string(a.Kind), "_", " ")),
+			Text:     a.Message,
+			CryptoID: a.CryptoID,
+			At:       a.TriggeredAt,
+		}
+	}
+	return docs
+}
@@ -0,0 +1,83 @@
+package search
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestService_GroupsResultsByKind(t *testing.T) {
+	docs := testDocs()
+	s := NewService(time.Minute, SourceFunc(func() ([]Document, error) { return docs, nil }))
+
+	results, err := s.Search("alice", "bitcoin", 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if results.Total != 2 || len(results.Groups) != 2 {
+		t.Fatalf("Expected two groups of bitcoin hits, got %+v", results)
+	}
+	if g := results.Groups[0]; g.Kind != KindAlert || g.Total != 1 {
+		t.Errorf("Expected the alert titled bitcoin first, got %+v", g)
+	}
+
+	results, _ = s.Search("alice", "arbitrum", 1)
+	if g := results.Groups[0]; g.Kind != KindEvent || g.Total != 2 || len(g.Hits) != 1 {
+		t.Errorf("Expected two events capped to one hit, got %+v", g)
+	}
+}
+
+func TestService_RebuildsStaleIndex(t *testing.T) {
+	docs := []Document{{Kind: KindEvent, ID: "a", Title: "Merge"}}
+	s := NewService(time.Minute, SourceFunc(func() ([]Document, error) { return docs, nil }))
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(now)
+	s.SetClock(clk)
+
+	s.Search("alice", "merge", 5)
+	docs = append(docs, Document{Kind: KindEvent, ID: "b", Title: "Merge anniversary"})
+	if results, _ := s.Search("alice", "merge", 5); results.Total != 1 {
+		t.Errorf("Expected the index to be reused, got %d hits", results.Total)
+	}
+	clk.Advance(time.Minute)
+	if results, _ := s.Search("alice", "merge", 5); results.Total != 2 {
+		t.Errorf("Expected a stale index to be rebuilt, got %d hits", results.Total)
+	}
+
+	docs = docs[:1]
+	s.Invalidate()
+	if results, _ := s.Search("alice", "merge", 5); results.Total != 1 {
+		t.Errorf("Expected an invalidated index to be rebuilt, got %d hits", results.Total)
+	}
+}
+
+func TestService_PartialResults(t *testing.T) {
+	s := NewService(time.Minute,
+		SourceFunc(func() ([]Document, error) { return nil, errors.New("disk failure") }),
+		SourceFunc(func() ([]Document, error) { return testDocs(), nil }),
+	)
+	results, err := s.Search("alice", "outage", 5)
+	if err == nil || results.Total != 1 {
+		t.Errorf("Expected partial results with an error, got %d hits and %v", results.Total, err)
+	}
+}
+
+func TestDocuments(t *testing.T) {
+	events := EventDocuments([]models.Event{{ID: "e1", Owner: "alice", Title: "Unlock", Description: "ARB", CryptoID: "arbitrum"}})
+	if d := events[0]; d.Kind != KindEvent || d.Owner != "alice" || d.Text != "ARB" {
+		t.Errorf("Unexpected event document: %+v", d)
+	}
+
+	incidents := IncidentDocuments([]models.Incident{{ID: "i1", Kind: models.IncidentDataQuality, CryptoID: "bitcoin", Description: "Wick"}})
+	if d := incidents[0]; d.Title != "data quality of bitcoin" || d.Text != "Wick" {
+		t.Errorf("Unexpected incident document: %+v", d)
+	}
+
+	alerts := AlertDocuments([]models.Alert{{CryptoID: "bitcoin", Kind: models.AlertPriceBelow, Message: "crossed"}, {CryptoID: "ethereum"}})
+	if d := alerts[1]; d.ID != "2" || alerts[0].Title != "bitcoin price below" {
+		t.Errorf("Unexpected alert documents: %+v", alerts)
+	}
+}
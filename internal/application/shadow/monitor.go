@@ -0,0 +1,242 @@
+// Package shadow mirrors a sample of the prices read from the primary provider
+// to a secondary source and reports where the two disagree, so operators can
+// judge which provider to rely on. Mirrored reads never delay or change what
+// the primary returns.
+package shadow
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/compare"
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// maxRecent bounds the discrepancies kept for the report
+const maxRecent = 100
+
+// PriceProvider is the primary source of current prices
+type PriceProvider interface {
+	FetchCryptoPrices(cryptoIDs []string, currency models.Currency) ([]models.CryptoPrice, error)
+}
+
+// Symbols resolves coin IDs to the ticker symbols the secondary quotes by
+type Symbols interface {
+	Symbol(id string) (string, bool)
+}
+
+// Discrepancy is a mirrored price the secondary disagreed with beyond the tolerance
+type Discrepancy struct {
+	CryptoID  string          `json:"crypto_id"`
+	Symbol    string          `json:"symbol"`
+	Currency  models.Currency `json:"currency"`
+	Primary   decimal.Decimal `json:"primary"`
+	Secondary decimal.Decimal `json:"secondary"`
+	// DeviationPct is how far the secondary is from the primary, in percent
+	DeviationPct float64   `json:"deviation_pct"`
+	At           time.Time `json:"at"`
+}
+
+// CoinQuality sums up the comparisons of one coin
+type CoinQuality struct {
+	CryptoID        string  `json:"crypto_id"`
+	Compared        int     `json:"compared"`
+	Failed          int     `json:"failed"`
+	Discrepancies   int     `json:"discrepancies"`
+	MaxDeviationPct float64 `json:"max_deviation_pct"`
+}
+
+// Report is the data-quality report since the monitor started
+type Report struct {
+	Secondary    string    `json:"secondary"`
+	SampleRate   float64   `json:"sample_rate"`
+	TolerancePct float64   `json:"tolerance_pct"`
+	Since        time.Time `json:"since"`
+	// Compared counts mirrored prices the secondary answered; Failed those it
+	// could not, e.g. because it does not list the coin
+	Compared        int     `json:"compared"`
+	Failed          int     `json:"failed"`
+	Discrepancies   int     `json:"discrepancies"`
+	MaxDeviationPct float64 `json:"max_deviation_pct"`
+	// Coins are ordered by discrepancies, then by worst deviation
+	Coins []CoinQuality `json:"coins"`
+	// Recent are the latest discrepancies, newest first
+	Recent []Discrepancy `json:"recent"`
+}
+
+// Monitor compares sampled primary prices with a secondary source
+type Monitor struct {
+	secondary    compare.Source
+	symbols      Symbols
+	sample       float64
+	tolerancePct float64
+	timeout      time.Duration
+	random       func() float64
+	clock        clock.Clock
+	logger       *slog.Logger
+
+	// running keeps one mirror at a time, so a slow secondary cannot pile up requests
+	running sync.Mutex
+	wg      sync.WaitGroup
+
+	mu     sync.Mutex
+	since  time.Time
+	coins  map[string]*CoinQuality
+	recent []Discrepancy
+}
+
+// NewMonitor creates a monitor mirroring each price with probability sample
+// and recording deviations above tolerancePct. Every secondary quote has at
+// most timeout to answer.
+func NewMonitor(secondary compare.Source, symbols Symbols, sample, tolerancePct float64, timeout time.Duration) *Monitor {
+	return &Monitor{
+		secondary:    secondary,
+		symbols:      symbols,
+		sample:       sample,
+		tolerancePct: tolerancePct,
+		timeout:      timeout,
+		random:       rand.Float64,
+		clock:        clock.Real,
+		logger:       slog.Default(),
+		since:        clock.Real.Now().UTC(),
+		coins:        make(map[string]*CoinQuality),
+	}
+}
+
+// SetClock replaces the system clock, for tests
+func (m *Monitor) SetClock(clk clock.Clock) {
+	m.clock = clk
+	m.since = clk.Now().UTC()
+}
+
+// SetLogger replaces the default logger
+func (m *Monitor) SetLogger(logger *slog.Logger) {
+	m.logger = logger
+}
+
+// Wrap returns a provider answering like provider that mirrors a sample of
+// the prices it returns in the background
+func (m *Monitor) Wrap(provider PriceProvider) PriceProvider {
+	return shadowed{primary: provider, monitor: m}
+}
+
+type shadowed struct {
+	primary PriceProvider
+	monitor *Monitor
+}
+
+func (s shadowed) FetchCryptoPrices(cryptoIDs []string, currency models.Currency) ([]models.CryptoPrice, error) {
+	prices, err := s.primary.FetchCryptoPrices(cryptoIDs, currency)
+	s.monitor.observe(prices)
+	return prices, err
+}
+
+// observe mirrors the sampled prices unless a mirror is still running
+func (m *Monitor) observe(prices []models.CryptoPrice) {
+	var sampled []models.CryptoPrice
+	for _, p := range prices {
+		if !p.Stale && m.random() < m.sample {
+			sampled = append(sampled, p)
+		}
+	}
+	if len(sampled) == 0 || !m.running.TryLock() {
+		return
+	}
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer m.running.Unlock()
+		m.Mirror(context.Background(), sampled)
+	}()
+}
+
+// Mirror quotes every price on the secondary and records the outcome
+func (m *Monitor) Mirror(ctx context.Context, prices []models.CryptoPrice) {
+	for _, p := range prices {
+		symbol, ok := m.symbols.Symbol(p.ID)
+		if !ok || p.CurrentPrice.IsZero() {
+			continue
+		}
+		quoteCtx, cancel := context.WithTimeout(ctx, m.timeout)
+		quote, err := m.secondary.Quote(quoteCtx, symbol, p.Currency)
+		cancel()
+		if err != nil {
+			m.logger.Debug("shadow quote failed", "source", m.secondary.Name(), "coin", p.ID, "error", err)
+			m.record(p.ID, nil)
+			continue
+		}
+		deviation := quote.Price.Sub(p.CurrentPrice).Abs().Div(p.CurrentPrice).InexactFloat64() * 100
+		m.record(p.ID, &Discrepancy{
+			CryptoID:     p.ID,
+			Symbol:       strings.ToUpper(symbol),
+			Currency:     p.Currency,
+			Primary:      p.CurrentPrice,
+			Secondary:    quote.Price,
+			DeviationPct: deviation,
+			At:           m.clock.Now().UTC(),
+		})
+	}
+}
+
+// record counts a comparison; d is nil when the secondary failed
+func (m *Monitor) record(id string, d *Discrepancy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.coins[id]
+	if !ok {
+		c = &CoinQuality{CryptoID: id}
+		m.coins[id] = c
+	}
+	if d == nil {
+		c.Failed++
+		return
+	}
+	c.Compared++
+	c.MaxDeviationPct = max(c.MaxDeviationPct, d.DeviationPct)
+	if d.DeviationPct <= m.tolerancePct {
+		return
+	}
+	c.Discrepancies++
+	m.recent = append(m.recent, *d)
+	if len(m.recent) > maxRecent {
+		m.recent = slices.Delete(m.recent, 0, len(m.recent)-maxRecent)
+	}
+}
+
+// Report returns the comparisons so far
+func (m *Monitor) Report() Report {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r := Report{
+		Secondary:    m.secondary.Name(),
+		SampleRate:   m.sample,
+		TolerancePct: m.tolerancePct,
+		Since:        m.since,
+		Coins:        make([]CoinQuality, 0, len(m.coins)),
+		Recent:       slices.Clone(m.recent),
+	}
+	slices.Reverse(r.Recent)
+	if r.Recent == nil {
+		r.Recent = []Discrepancy{}
+	}
+	for _, c := range m.coins {
+		r.Compared += c.Compared
+		r.Failed += c.Failed
+		r.Discrepancies += c.Discrepancies
+		r.MaxDeviationPct = max(r.MaxDeviationPct, c.MaxDeviationPct)
+		r.Coins = append(r.Coins, *c)
+	}
+	slices.SortFunc(r.Coins, func(a, b CoinQuality) int {
+		return cmp.Or(b.Discrepancies-a.Discrepancies, cmp.Compare(b.MaxDeviationPct, a.MaxDeviationPct), strings.Compare(a.CryptoID, b.CryptoID))
+	})
+	return r
+}
@@ -0,0 +1,87 @@
+package shadow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubSource map[string]int64
+
+func (s stubSource) Name() string { return "kraken" }
+
+func (s stubSource) Quote(_ context.Context, symbol string, currency models.Currency) (models.SourceQuote, error) {
+	price, ok := s[symbol]
+	if !ok {
+		return models.SourceQuote{}, errors.New("pair not listed")
+	}
+	return models.SourceQuote{Price: decimal.NewFromInt(price)}, nil
+}
+
+type stubSymbols map[string]string
+
+func (s stubSymbols) Symbol(id string) (string, bool) {
+	symbol, ok := s[id]
+	return symbol, ok
+}
+
+type stubPrimary []models.CryptoPrice
+
+func (p stubPrimary) FetchCryptoPrices([]string, models.Currency) ([]models.CryptoPrice, error) {
+	return p, nil
+}
+
+func price(id string, value int64) models.CryptoPrice {
+	return models.CryptoPrice{ID: id, CurrentPrice: decimal.NewFromInt(value), Currency: models.USD}
+}
+
+var symbols = stubSymbols{"bitcoin": "BTC", "ethereum": "ETH", "tiny": "TINY"}
+
+func TestMonitor_Mirror(t *testing.T) {
+	m := NewMonitor(stubSource{"BTC": 50500, "ETH": 3000}, symbols, 1, 0.5, time.Second)
+	m.Mirror(context.Background(), []models.CryptoPrice{
+		price("bitcoin", 50000),  // 1% off
+		price("ethereum", 3001),  // within tolerance
+		price("tiny", 1),         // not listed on the secondary
+		price("unresolved", 100), // no symbol, skipped
+	})
+
+	r := m.Report()
+	if r.Secondary != "kraken" || r.Compared != 2 || r.Failed != 1 || r.Discrepancies != 1 {
+		t.Fatalf("Unexpected totals: %+v", r)
+	}
+	if len(r.Recent) != 1 || r.Recent[0].CryptoID != "bitcoin" || r.Recent[0].Symbol != "BTC" || r.Recent[0].DeviationPct != 1 {
+		t.Errorf("Expected the bitcoin discrepancy, got %+v", r.Recent)
+	}
+	if len(r.Coins) != 3 || r.Coins[0].CryptoID != "bitcoin" || r.Coins[0].MaxDeviationPct != 1 || r.MaxDeviationPct != 1 {
+		t.Errorf("Expected coins ordered by discrepancies, got %+v", r.Coins)
+	}
+}
+
+func TestMonitor_Wrap(t *testing.T) {
+	primary := stubPrimary{price("bitcoin", 50000), price("ethereum", 3000)}
+	m := NewMonitor(stubSource{"BTC": 52000, "ETH": 3000}, symbols, 0.5, 1, time.Second)
+	// Only the first price is sampled
+	draws := []float64{0.1, 0.9}
+	m.random = func() float64 {
+		draw := draws[0]
+		draws = draws[1:]
+		return draw
+	}
+
+	prices, err := m.Wrap(primary).FetchCryptoPrices([]string{"bitcoin", "ethereum"}, models.USD)
+	if err != nil || len(prices) != 2 {
+		t.Fatalf("Expected the primary prices unchanged, got %+v, %v", prices, err)
+	}
+	m.wg.Wait()
+
+	r := m.Report()
+	if r.Compared != 1 || r.Discrepancies != 1 || r.Coins[0].CryptoID != "bitcoin" {
+		t.Errorf("Expected only bitcoin to be mirrored, got %+v", r)
+	}
+}
@@ -0,0 +1,62 @@
+// Package status reports the provider incidents and process uptime shown on
+// the public status page
+package status
+
+import (
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// MaxIncidents is the number of recent incidents reported
+const MaxIncidents = 20
+
+// IncidentSource provides the recorded incidents
+type IncidentSource interface {
+	Recent(n int) ([]models.Incident, error)
+}
+
+// Tracker reports uptime and the recent incidents
+type Tracker struct {
+	incidents IncidentSource
+	started   time.Time
+	clock     clock.Clock
+}
+
+// NewTracker creates a tracker counting uptime from now
+func NewTracker(incidents IncidentSource) *Tracker {
+	return &Tracker{incidents: incidents, started: clock.Real.Now().UTC(), clock: clock.Real}
+}
+
+// SetClock replaces the system clock and counts uptime from its current time.
+// It must be called before the tracker is used.
+func (t *Tracker) SetClock(c clock.Clock) {
+	t.clock = c
+	t.started = c.Now().UTC()
+}
+
+// Started returns when the tracker, and so the process, started
+func (t *Tracker) Started() time.Time {
+	return t.started
+}
+
+// Uptime returns how long the process has been running
+func (t *Tracker) Uptime() time.Duration {
+	return t.clock.Now().Sub(t.started)
+}
+
+// Incidents returns the recent incidents, most recent first, and whether any
+// of them is still ongoing
+func (t *Tracker) Incidents() ([]models.Incident, bool, error) {
+	incidents, err := t.incidents.Recent(MaxIncidents)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, i := range incidents {
+		if i.Ongoing() {
+			return incidents, true, nil
+		}
+	}
+	return incidents, false, nil
+}
@@ -0,0 +1,53 @@
+package status
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+type fakeIncidents struct {
+	incidents []models.Incident
+	err       error
+}
+
+func (f fakeIncidents) Recent(n int) ([]models.Incident, error) {
+	return f.incidents[:min(n, len(f.incidents))], f.err
+}
+
+func TestTracker_Uptime(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := clock.NewFake(start)
+	tracker := NewTracker(fakeIncidents{})
+	tracker.SetClock(c)
+	if !tracker.Started().Equal(start) {
+		t.Errorf("Expected uptime to count from the clock, started %v", tracker.Started())
+	}
+	c.Advance(time.Hour)
+	if tracker.Uptime() != time.Hour {
+		t.Errorf("Expected 1h of uptime, got %v", tracker.Uptime())
+	}
+}
+
+func TestTracker_Incidents(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(time.Minute)
+
+	tracker := NewTracker(fakeIncidents{incidents: []models.Incident{{Started: start, Resolved: &end}}})
+	if incidents, ongoing, err := tracker.Incidents(); err != nil || ongoing || len(incidents) != 1 {
+		t.Errorf("Expected one resolved incident, got %+v %v %v", incidents, ongoing, err)
+	}
+
+	tracker = NewTracker(fakeIncidents{incidents: []models.Incident{{Started: start.Add(time.Hour)}, {Started: start, Resolved: &end}}})
+	if _, ongoing, _ := tracker.Incidents(); !ongoing {
+		t.Error("Expected the open incident to be reported as ongoing")
+	}
+
+	tracker = NewTracker(fakeIncidents{err: errors.New("down")})
+	if _, _, err := tracker.Incidents(); err == nil {
+		t.Error("Expected the source error")
+	}
+}
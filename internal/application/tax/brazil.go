@@ -0,0 +1,90 @@
+package tax
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/portfolio"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Brazil categories
+const (
+	CategoryExempt  = "exempt"
+	CategoryTaxable = "taxable"
+)
+
+const (
+	// brazilMonthlyExemption is the monthly sales total under which gains are exempt
+	brazilMonthlyExemption = 35000
+)
+
+// brazilTaxRate is the rate applied to taxable gains
+var brazilTaxRate = decimal.RequireFromString("0.15")
+
+// Brazil applies the monthly exemption: gains are exempt in months whose total
+// sales do not exceed R$35,000, otherwise they are taxed at 15%
+type Brazil struct{}
+
+// Code implements Jurisdiction
+func (Brazil) Code() string { return "br" }
+
+// Name implements Jurisdiction
+func (Brazil) Name() string { return "Brazil" }
+
+// Currency implements Jurisdiction
+func (Brazil) Currency() models.Currency { return models.BRL }
+
+// Classify implements Jurisdiction
+func (Brazil) Classify(disposals []portfolio.Disposal) ([]Entry, []SummaryLine) {
+	salesByMonth := make(map[string]decimal.Decimal)
+	for _, d := range disposals {
+		month := monthKey(d)
+		salesByMonth[month] = salesByMonth[month].Add(d.Proceeds)
+	}
+
+	entries := make([]Entry, len(disposals))
+	gainByMonth := make(map[string]decimal.Decimal)
+	exempt, taxable := decimal.Zero, decimal.Zero
+	for i, d := range disposals {
+		month := monthKey(d)
+		category := CategoryExempt
+		if salesByMonth[month].GreaterThan(decimal.NewFromInt(brazilMonthlyExemption)) {
+			category = CategoryTaxable
+			taxable = taxable.Add(d.Gain())
+			gainByMonth[month] = gainByMonth[month].Add(d.Gain())
+		} else {
+			exempt = exempt.Add(d.Gain())
+		}
+		entries[i] = Entry{Disposal: d, Category: category}
+	}
+
+	months := make([]string, 0, len(gainByMonth))
+	for month := range gainByMonth {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+
+	var summary []SummaryLine
+	taxDue := decimal.Zero
+	for _, month := range months {
+		due := decimal.Zero
+		if gainByMonth[month].IsPositive() {
+			due = gainByMonth[month].Mul(brazilTaxRate)
+		}
+		taxDue = taxDue.Add(due)
+		summary = append(summary, SummaryLine{Label: fmt.Sprintf("Tax due %s", month), Value: due})
+	}
+
+	return entries, append(summary,
+		SummaryLine{Label: "Exempt gain", Value: exempt},
+		SummaryLine{Label: "Taxable gain", Value: taxable},
+		SummaryLine{Label: "Total tax due", Value: taxDue},
+	)
+}
+
+func monthKey(d portfolio.Disposal) string {
+	return d.DisposedAt.UTC().Format("2006-01")
+}
@@ -0,0 +1,75 @@
+package tax
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+)
+
+// WriteCSV writes one row per report entry followed by the summary lines, then
+// the income received in the year and its totals
+func WriteCSV(w io.Writer, r *Report) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{
+		"transaction_id", "crypto_id", "exchange", "quantity", "acquired_at", "disposed_at",
+		"proceeds", "cost_basis", "gain", "category",
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, e := range r.Entries {
+		row := []string{
+			e.TransactionID,
+			e.CryptoID,
+			e.Exchange,
+			e.Quantity.StringFixed(8),
+			e.AcquiredAt.UTC().Format(time.DateOnly),
+			e.DisposedAt.UTC().Format(time.DateOnly),
+			e.Proceeds.StringFixed(2),
+			e.CostBasis.StringFixed(2),
+			e.Gain().StringFixed(2),
+			e.Category,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	// A blank row separates the entries from the summary
+	if err := cw.Write(nil); err != nil {
+		return err
+	}
+	for _, line := range r.Summary {
+		if err := cw.Write([]string{line.Label, line.Value.StringFixed(2)}); err != nil {
+			return err
+		}
+	}
+
+	// Income follows in its own section since it is not a capital gain
+	if len(r.Income) > 0 {
+		rows := [][]string{nil, {"transaction_id", "crypto_id", "exchange", "quantity", "received_at", "type", "value"}}
+		for _, e := range r.Income {
+			rows = append(rows, []string{
+				e.TransactionID,
+				e.CryptoID,
+				e.Exchange,
+				e.Quantity.StringFixed(8),
+				e.ReceivedAt.UTC().Format(time.DateOnly),
+				string(e.Type),
+				e.Value.StringFixed(2),
+			})
+		}
+		rows = append(rows, nil)
+		for _, line := range r.IncomeSummary {
+			rows = append(rows, []string{line.Label, line.Value.StringFixed(2)})
+		}
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
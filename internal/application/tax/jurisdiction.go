@@ -0,0 +1,62 @@
+package tax
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"crypto-dashboard/internal/application/portfolio"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Jurisdiction is a pluggable template that applies local tax rules to disposals
+type Jurisdiction interface {
+	// Code is the short identifier used to select the jurisdiction (e.g. "us")
+	Code() string
+	// Name is the human readable name printed on reports
+	Name() string
+	// Currency is the currency reports must be expressed in
+	Currency() models.Currency
+	// Classify categorizes each disposal and computes the summary totals
+	Classify(disposals []portfolio.Disposal) ([]Entry, []SummaryLine)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Jurisdiction)
+)
+
+func init() {
+	Register(UnitedStates{})
+	Register(Brazil{})
+}
+
+// Register makes a jurisdiction available through Lookup, replacing any with the same code
+func Register(j Jurisdiction) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[j.Code()] = j
+}
+
+// Lookup returns the jurisdiction registered under code
+func Lookup(code string) (Jurisdiction, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	j, ok := registry[code]
+	if !ok {
+		return nil, fmt.Errorf("unknown tax jurisdiction: %q", code)
+	}
+	return j, nil
+}
+
+// Jurisdictions lists the registered jurisdiction codes in alphabetical order
+func Jurisdictions() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	codes := make([]string, 0, len(registry))
+	for code := range registry {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
@@ -0,0 +1,136 @@
+package tax
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	pdfLinesPerPage = 60
+	pdfFontSize     = 9
+	pdfLineHeight   = 12
+)
+
+// WritePDF renders the report as a plain, text-only PDF document.
+// The writer is intentionally minimal (a single built-in Courier font, no images)
+// so reports can be produced without external dependencies.
+func WritePDF(w io.Writer, r *Report) error {
+	lines := reportLines(r)
+
+	var pages [][]string
+	for start := 0; start < len(lines); start += pdfLinesPerPage {
+		end := start + pdfLinesPerPage
+		if end > len(lines) {
+			end = len(lines)
+		}
+		pages = append(pages, lines[start:end])
+	}
+
+	// Object layout: 1 catalog, 2 pages, 3 font, then a page and a content stream per page
+	var objects []string
+	objects = append(objects, "<< /Type /Catalog /Pages 2 0 R >>")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+i*2)
+	}
+	objects = append(objects, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>")
+
+	for i, page := range pages {
+		content := pageContent(page)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", 5+i*2),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// reportLines lays the report out as fixed-width text lines
+func reportLines(r *Report) []string {
+	currency := strings.ToUpper(string(r.Currency))
+	lines := []string{
+		fmt.Sprintf("Capital gains report %d - %s", r.Year, r.Jurisdiction),
+		fmt.Sprintf("Currency: %s   Cost basis: %s   Generated: %s", currency, r.Method, r.GeneratedAt.Format(time.RFC3339)),
+		"",
+		fmt.Sprintf("%-12s %-10s %-10s %14s %14s %14s %-10s", "Coin", "Acquired", "Disposed", "Proceeds", "Cost", "Gain", "Category"),
+		strings.Repeat("-", 92),
+	}
+	for _, e := range r.Entries {
+		lines = append(lines, fmt.Sprintf("%-12.12s %-10s %-10s %14s %14s %14s %-10s",
+			e.CryptoID,
+			e.AcquiredAt.UTC().Format(time.DateOnly),
+			e.DisposedAt.UTC().Format(time.DateOnly),
+			e.Proceeds.StringFixed(2), e.CostBasis.StringFixed(2), e.Gain().StringFixed(2), e.Category))
+	}
+	lines = append(lines, "", "Summary", strings.Repeat("-", 92))
+	for _, s := range r.Summary {
+		lines = append(lines, fmt.Sprintf("%-40s %14s %s", s.Label, s.Value.StringFixed(2), currency))
+	}
+	if len(r.Income) == 0 {
+		return lines
+	}
+
+	lines = append(lines, "", "Income (not included in capital gains)",
+		fmt.Sprintf("%-12s %-10s %-16s %18s %14s", "Coin", "Received", "Type", "Quantity", "Value"),
+		strings.Repeat("-", 92))
+	for _, e := range r.Income {
+		lines = append(lines, fmt.Sprintf("%-12.12s %-10s %-16s %18s %14s",
+			e.CryptoID, e.ReceivedAt.UTC().Format(time.DateOnly), e.Type, e.Quantity.StringFixed(8), e.Value.StringFixed(2)))
+	}
+	lines = append(lines, "")
+	for _, s := range r.IncomeSummary {
+		lines = append(lines, fmt.Sprintf("%-40s %14s %s", s.Label, s.Value.StringFixed(2), currency))
+	}
+	return lines
+}
+
+// pageContent builds the content stream drawing the lines top to bottom
+func pageContent(lines []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "BT\n/F1 %d Tf\n%d TL\n40 760 Td\n", pdfFontSize, pdfLineHeight)
+	for _, line := range lines {
+		fmt.Fprintf(&b, "(%s) Tj T*\n", escapePDF(line))
+	}
+	b.WriteString("ET")
+	return b.String()
+}
+
+// escapePDF escapes characters with special meaning in PDF literal strings
+// and drops anything outside printable ASCII
+func escapePDF(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r >= 32 && r < 127:
+			b.WriteRune(r)
+		default:
+			b.WriteRune('?')
+		}
+	}
+	return b.String()
+}
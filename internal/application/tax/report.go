@@ -0,0 +1,155 @@
+// Package tax generates yearly capital-gains reports from the portfolio ledger
+package tax
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/portfolio"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Entry is a single disposal classified by the jurisdiction rules
+type Entry struct {
+	portfolio.Disposal
+	Category string `json:"category"`
+}
+
+// SummaryLine is a labelled total shown at the end of a report
+type SummaryLine struct {
+	Label string          `json:"label"`
+	Value decimal.Decimal `json:"value"`
+}
+
+// Report is a yearly capital-gains report expressed in the jurisdiction currency.
+// Coins received as income are listed separately from the capital gains.
+type Report struct {
+	Year         int                       `json:"year"`
+	Jurisdiction string                    `json:"jurisdiction"`
+	Currency     models.Currency           `json:"currency"`
+	Method       portfolio.CostBasisMethod `json:"method"`
+	Entries      []Entry                   `json:"entries"`
+	Summary      []SummaryLine             `json:"summary"`
+	Income       []portfolio.IncomeEntry   `json:"income"`
+	// IncomeSummary totals the income per type and overall
+	IncomeSummary []SummaryLine `json:"income_summary"`
+	GeneratedAt   time.Time     `json:"generated_at"`
+}
+
+// Options controls how a report is generated
+type Options struct {
+	Year         int
+	Method       portfolio.CostBasisMethod
+	Jurisdiction Jurisdiction
+	// Converter translates transactions to the jurisdiction currency using the rate
+	// of each transaction date. It may be nil when the ledger is already in that currency.
+	Converter portfolio.RateConverter
+}
+
+// Generate builds the capital-gains report for a single year
+func Generate(ledger *portfolio.Ledger, opts Options) (*Report, error) {
+	if opts.Jurisdiction == nil {
+		return nil, errors.New("a jurisdiction is required")
+	}
+	if opts.Method == "" {
+		opts.Method = portfolio.FIFO
+	}
+	currency := opts.Jurisdiction.Currency()
+
+	if opts.Converter != nil {
+		converted, err := ledger.InCurrency(currency, opts.Converter)
+		if err != nil {
+			return nil, err
+		}
+		ledger = converted
+	} else {
+		for _, tx := range ledger.Transactions() {
+			if tx.IsMigration() {
+				continue
+			}
+			if tx.PriceCurrency() != currency {
+				return nil, fmt.Errorf("transaction %q is in %s but the report requires %s; provide a converter",
+					tx.ID, tx.PriceCurrency(), currency)
+			}
+			if tx.Fee.Amount.IsPositive() && tx.FeeCurrency() != currency {
+				return nil, fmt.Errorf("transaction %q pays its fee in %s but the report requires %s; provide a converter",
+					tx.ID, tx.FeeCurrency(), currency)
+			}
+		}
+	}
+
+	disposals, err := ledger.Disposals(opts.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	var yearly []portfolio.Disposal
+	for _, d := range disposals {
+		if d.DisposedAt.UTC().Year() == opts.Year {
+			yearly = append(yearly, d)
+		}
+	}
+	sort.SliceStable(yearly, func(i, j int) bool {
+		return yearly[i].DisposedAt.Before(yearly[j].DisposedAt)
+	})
+
+	var income []portfolio.IncomeEntry
+	for _, e := range ledger.Income() {
+		if e.ReceivedAt.UTC().Year() == opts.Year {
+			income = append(income, e)
+		}
+	}
+
+	entries, summary := opts.Jurisdiction.Classify(yearly)
+	return &Report{
+		Year:          opts.Year,
+		Jurisdiction:  opts.Jurisdiction.Name(),
+		Currency:      currency,
+		Method:        opts.Method,
+		Entries:       entries,
+		Summary:       summary,
+		Income:        income,
+		IncomeSummary: summarizeIncome(income),
+		GeneratedAt:   time.Now().UTC(),
+	}, nil
+}
+
+// incomeLabels name the income types in summaries
+var incomeLabels = map[models.TransactionType]string{
+	models.TransactionAirdrop:       "Airdrops",
+	models.TransactionStakingReward: "Staking rewards",
+	models.TransactionInterest:      "Interest",
+}
+
+// summarizeIncome totals the income of each type that occurred, then overall
+func summarizeIncome(income []portfolio.IncomeEntry) []SummaryLine {
+	if len(income) == 0 {
+		return nil
+	}
+	byType := make(map[models.TransactionType]decimal.Decimal)
+	total := decimal.Zero
+	for _, e := range income {
+		byType[e.Type] = byType[e.Type].Add(e.Value)
+		total = total.Add(e.Value)
+	}
+	var lines []SummaryLine
+	for _, kind := range models.IncomeTypes {
+		if value, ok := byType[kind]; ok {
+			lines = append(lines, SummaryLine{Label: incomeLabels[kind], Value: value})
+		}
+	}
+	return append(lines, SummaryLine{Label: "Total income", Value: total})
+}
+
+// TotalGain returns the sum of every entry gain
+func (r *Report) TotalGain() decimal.Decimal {
+	total := decimal.Zero
+	for _, e := range r.Entries {
+		total = total.Add(e.Gain())
+	}
+	return total
+}
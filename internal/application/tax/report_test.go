@@ -0,0 +1,199 @@
+package tax
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/portfolio"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+}
+
+func summaryValue(r *Report, label string) float64 {
+	for _, line := range r.Summary {
+		if line.Label == label {
+			return line.Value.InexactFloat64()
+		}
+	}
+	return math.NaN()
+}
+
+func newLedger(t *testing.T, currency models.Currency, txs ...models.Transaction) *portfolio.Ledger {
+	t.Helper()
+	for i := range txs {
+		txs[i].Currency = currency
+	}
+	ledger, err := portfolio.NewLedger(txs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return ledger
+}
+
+func TestGenerate_UnitedStates(t *testing.T) {
+	ledger := newLedger(t, models.USD,
+		models.Transaction{ID: "b1", CryptoID: "bitcoin", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(10000), Timestamp: date(2022, 1, 1)},
+		models.Transaction{ID: "b2", CryptoID: "bitcoin", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(30000), Timestamp: date(2024, 2, 1)},
+		models.Transaction{ID: "s1", CryptoID: "bitcoin", Type: models.TransactionSell, Quantity: decimal.NewFromInt(2), Price: decimal.NewFromInt(40000), Timestamp: date(2024, 6, 1)},
+	)
+
+	us, _ := Lookup("us")
+	report, err := Generate(ledger, Options{Year: 2024, Method: portfolio.FIFO, Jurisdiction: us})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(report.Entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(report.Entries))
+	}
+	if report.Entries[0].Category != CategoryLongTerm || report.Entries[1].Category != CategoryShortTerm {
+		t.Errorf("Unexpected categories: %s, %s", report.Entries[0].Category, report.Entries[1].Category)
+	}
+	if summaryValue(report, "Long-term gain") != 30000 || summaryValue(report, "Short-term gain") != 10000 {
+		t.Errorf("Unexpected summary: %+v", report.Summary)
+	}
+}
+
+func TestGenerate_BrazilMonthlyExemption(t *testing.T) {
+	ledger := newLedger(t, models.BRL,
+		models.Transaction{ID: "b1", CryptoID: "bitcoin", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(100000), Timestamp: date(2023, 1, 1)},
+		// January sales stay under the exemption threshold
+		models.Transaction{ID: "s1", CryptoID: "bitcoin", Type: models.TransactionSell, Quantity: decimal.NewFromFloat(0.1), Price: decimal.NewFromInt(200000), Timestamp: date(2024, 1, 10)},
+		// March sales exceed it
+		models.Transaction{ID: "s2", CryptoID: "bitcoin", Type: models.TransactionSell, Quantity: decimal.NewFromFloat(0.5), Price: decimal.NewFromInt(300000), Timestamp: date(2024, 3, 10)},
+	)
+
+	br, _ := Lookup("br")
+	report, err := Generate(ledger, Options{Year: 2024, Jurisdiction: br})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if report.Entries[0].Category != CategoryExempt || report.Entries[1].Category != CategoryTaxable {
+		t.Errorf("Unexpected categories: %s, %s", report.Entries[0].Category, report.Entries[1].Category)
+	}
+	if got := summaryValue(report, "Total tax due"); math.Abs(got-15000) > 1e-6 {
+		t.Errorf("Expected tax due of 15000, got %f", got)
+	}
+}
+
+func TestGenerate_SeparatesIncome(t *testing.T) {
+	ledger := newLedger(t, models.USD,
+		models.Transaction{ID: "a1", CryptoID: "uniswap", Type: models.TransactionAirdrop, Quantity: decimal.NewFromInt(400), Price: decimal.NewFromInt(5), Timestamp: date(2023, 9, 17)},
+		models.Transaction{ID: "i1", CryptoID: "usd-coin", Type: models.TransactionInterest, Quantity: decimal.NewFromInt(10), Price: decimal.NewFromInt(1), Timestamp: date(2024, 1, 31)},
+		models.Transaction{ID: "r1", CryptoID: "uniswap", Type: models.TransactionStakingReward, Quantity: decimal.NewFromInt(10), Price: decimal.NewFromInt(6), Timestamp: date(2024, 3, 1)},
+		models.Transaction{ID: "s1", CryptoID: "uniswap", Type: models.TransactionSell, Quantity: decimal.NewFromInt(410), Price: decimal.NewFromInt(10), Timestamp: date(2024, 6, 1)},
+	)
+
+	us, _ := Lookup("us")
+	report, err := Generate(ledger, Options{Year: 2024, Method: portfolio.FIFO, Jurisdiction: us})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The airdrop is sold against its value on receipt, not against zero
+	if got := report.TotalGain(); !got.Equal(decimal.NewFromInt(4100 - 2000 - 60)) {
+		t.Errorf("Expected gains over the receipt values, got %s", got)
+	}
+	if len(report.Income) != 2 || report.Income[0].TransactionID != "i1" {
+		t.Fatalf("Expected the 2024 income only, got %+v", report.Income)
+	}
+	want := []string{"Staking rewards 60", "Interest 10", "Total income 70"}
+	var got []string
+	for _, line := range report.IncomeSummary {
+		got = append(got, line.Label+" "+line.Value.String())
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Unexpected income summary: %v", got)
+	}
+}
+
+func TestGenerate_RequiresConverterForForeignCurrency(t *testing.T) {
+	ledger := newLedger(t, models.USD,
+		models.Transaction{ID: "b1", CryptoID: "bitcoin", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(10000), Timestamp: date(2024, 1, 1)},
+	)
+	br, _ := Lookup("br")
+	if _, err := Generate(ledger, Options{Year: 2024, Jurisdiction: br}); err == nil {
+		t.Error("Expected error for USD ledger in a BRL report, got nil")
+	}
+
+	ledger = newLedger(t, models.BRL,
+		models.Transaction{
+			ID: "b1", CryptoID: "bitcoin", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(50000),
+			Fee: models.Fee{Amount: decimal.NewFromInt(10), Currency: "usd"}, Timestamp: date(2024, 1, 1),
+		},
+	)
+	if _, err := Generate(ledger, Options{Year: 2024, Jurisdiction: br}); err == nil || !strings.Contains(err.Error(), "fee in usd") {
+		t.Errorf("Expected error for a USD fee in a BRL report, got %v", err)
+	}
+}
+
+func TestWriteCSVAndPDF(t *testing.T) {
+	ledger := newLedger(t, models.USD,
+		models.Transaction{ID: "b1", CryptoID: "ethereum", Type: models.TransactionBuy, Quantity: decimal.NewFromInt(2), Price: decimal.NewFromInt(1000), Timestamp: date(2024, 1, 1)},
+		models.Transaction{ID: "s1", CryptoID: "ethereum", Type: models.TransactionSell, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(3000), Timestamp: date(2024, 5, 1)},
+		models.Transaction{ID: "r1", CryptoID: "ethereum", Type: models.TransactionStakingReward, Quantity: decimal.NewFromFloat(0.01), Price: decimal.NewFromInt(3500), Timestamp: date(2024, 6, 1)},
+	)
+	us, _ := Lookup("us")
+	report, err := Generate(ledger, Options{Year: 2024, Jurisdiction: us})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var csvBuf bytes.Buffer
+	if err := WriteCSV(&csvBuf, report); err != nil {
+		t.Fatalf("Unexpected CSV error: %v", err)
+	}
+	reader := csv.NewReader(&csvBuf)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV output: %v", err)
+	}
+	if records[1][0] != "s1" || records[1][8] != "2000.00" {
+		t.Errorf("Unexpected CSV row: %v", records[1])
+	}
+	if last := records[len(records)-1]; last[0] != "Total income" || last[1] != "35.00" {
+		t.Errorf("Expected the income total to end the CSV, got %v", last)
+	}
+
+	var pdfBuf bytes.Buffer
+	if err := WritePDF(&pdfBuf, report); err != nil {
+		t.Fatalf("Unexpected PDF error: %v", err)
+	}
+	pdf := pdfBuf.String()
+	if !strings.HasPrefix(pdf, "%PDF-1.4") || !strings.HasSuffix(pdf, "%%EOF\n") {
+		t.Error("Expected a well-formed PDF envelope")
+	}
+	if !strings.Contains(pdf, "Capital gains report 2024 - United States") {
+		t.Error("Expected report title in PDF content")
+	}
+	if !strings.Contains(pdf, "Income \\(not included in capital gains\\)") {
+		t.Error("Expected the income section in PDF content")
+	}
+}
+
+func TestEscapePDF(t *testing.T) {
+	if got := escapePDF(`a(b)\c`); got != `a\(b\)\\c` {
+		t.Errorf("Unexpected escaping: %s", got)
+	}
+}
+
+func TestJurisdictions(t *testing.T) {
+	codes := Jurisdictions()
+	if len(codes) < 2 || codes[0] != "br" || codes[1] != "us" {
+		t.Errorf("Expected br and us to be registered, got %v", codes)
+	}
+	if _, err := Lookup("xx"); err == nil {
+		t.Error("Expected error for unknown jurisdiction, got nil")
+	}
+}
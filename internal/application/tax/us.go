@@ -0,0 +1,49 @@
+package tax
+
+import (
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/portfolio"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// US categories
+const (
+	CategoryShortTerm = "short-term"
+	CategoryLongTerm  = "long-term"
+)
+
+// UnitedStates splits gains into short-term (held one year or less) and long-term
+type UnitedStates struct{}
+
+// Code implements Jurisdiction
+func (UnitedStates) Code() string { return "us" }
+
+// Name implements Jurisdiction
+func (UnitedStates) Name() string { return "United States" }
+
+// Currency implements Jurisdiction
+func (UnitedStates) Currency() models.Currency { return models.USD }
+
+// Classify implements Jurisdiction
+func (UnitedStates) Classify(disposals []portfolio.Disposal) ([]Entry, []SummaryLine) {
+	entries := make([]Entry, len(disposals))
+	shortTerm, longTerm := decimal.Zero, decimal.Zero
+	for i, d := range disposals {
+		category := CategoryShortTerm
+		// Long-term treatment requires holding for more than one year
+		if d.DisposedAt.After(d.AcquiredAt.AddDate(1, 0, 0)) {
+			category = CategoryLongTerm
+			longTerm = longTerm.Add(d.Gain())
+		} else {
+			shortTerm = shortTerm.Add(d.Gain())
+		}
+		entries[i] = Entry{Disposal: d, Category: category}
+	}
+
+	return entries, []SummaryLine{
+		{Label: "Short-term gain", Value: shortTerm},
+		{Label: "Long-term gain", Value: longTerm},
+		{Label: "Net capital gain", Value: shortTerm.Add(longTerm)},
+	}
+}
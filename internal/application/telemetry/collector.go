@@ -0,0 +1,168 @@
+// Package telemetry counts how often each dashboard feature is used and, only
+// when the operator opted in, periodically sends the counts. Reports are
+// anonymous: they hold the version, the period and a count per feature, and
+// nothing about the installation, its users or the coins they follow.
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+)
+
+// maxFeatures bounds the distinct features counted per report
+const maxFeatures = 500
+
+// Report is exactly what is sent at the end of a period
+type Report struct {
+	Version  string           `json:"version"`
+	From     time.Time        `json:"from"`
+	To       time.Time        `json:"to"`
+	Features map[string]int64 `json:"features"`
+}
+
+// Sender delivers a report
+type Sender interface {
+	Send(ctx context.Context, r Report) error
+}
+
+// Collector aggregates feature usage in memory. It only counts and sends
+// once enabled, and Disable is final: the counts are dropped and nothing is
+// counted or sent again until restart.
+type Collector struct {
+	version string
+	clock   clock.Clock
+	logger  *slog.Logger
+
+	mu       sync.Mutex
+	sender   Sender
+	interval time.Duration
+	disabled bool
+	counts   map[string]int64
+	since    time.Time
+}
+
+// NewCollector creates a collector that is off until enabled
+func NewCollector(version string) *Collector {
+	return &Collector{version: version, clock: clock.Real, logger: slog.Default(), counts: make(map[string]int64)}
+}
+
+// SetClock replaces the system clock, for tests
+func (c *Collector) SetClock(clk clock.Clock) {
+	c.clock = clk
+}
+
+// SetLogger replaces the default logger
+func (c *Collector) SetLogger(logger *slog.Logger) {
+	c.logger = logger
+}
+
+// Enable starts counting and sends a report to sender on every interval.
+// It has no effect after Disable.
+func (c *Collector) Enable(sender Sender, interval time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.disabled {
+		return
+	}
+	c.sender, c.interval = sender, interval
+	c.since = c.clock.Now().UTC()
+}
+
+// Disable turns telemetry off for the rest of the process and drops the
+// counts not sent yet
+func (c *Collector) Disable() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.disabled = true
+	c.sender = nil
+	clear(c.counts)
+}
+
+// Enabled reports whether usage is counted and sent
+func (c *Collector) Enabled() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sender != nil
+}
+
+// Count records one use of a feature. It does nothing while telemetry is off.
+func (c *Collector) Count(feature string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sender == nil {
+		return
+	}
+	if _, ok := c.counts[feature]; !ok && len(c.counts) >= maxFeatures {
+		return
+	}
+	c.counts[feature]++
+}
+
+// Preview returns the report that would be sent now
+func (c *Collector) Preview() Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.report()
+}
+
+// Run sends a report on every interval until the context is cancelled. It
+// returns at once when telemetry is off.
+func (c *Collector) Run(ctx context.Context) {
+	c.mu.Lock()
+	enabled, interval := c.sender != nil, c.interval
+	c.mu.Unlock()
+	if !enabled {
+		return
+	}
+	ticker := c.clock.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			if !c.Enabled() {
+				return
+			}
+			if err := c.Flush(ctx); err != nil {
+				c.logger.Debug("telemetry report failed", "error", err)
+			}
+		}
+	}
+}
+
+// Flush sends the counts and starts a new period. Periods without any usage
+// are not sent; the counts are kept when sending fails.
+func (c *Collector) Flush(ctx context.Context) error {
+	c.mu.Lock()
+	sender := c.sender
+	r := c.report()
+	c.mu.Unlock()
+	if sender == nil || len(r.Features) == 0 {
+		return nil
+	}
+	if err := sender.Send(ctx, r); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Uses counted while sending stay for the next report
+	for feature, n := range r.Features {
+		if c.counts[feature] -= n; c.counts[feature] <= 0 {
+			delete(c.counts, feature)
+		}
+	}
+	c.since = r.To
+	return nil
+}
+
+// report builds the report of the current period. The caller must hold the lock.
+func (c *Collector) report() Report {
+	return Report{Version: c.version, From: c.since, To: c.clock.Now().UTC(), Features: maps.Clone(c.counts)}
+}
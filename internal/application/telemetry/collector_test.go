@@ -0,0 +1,96 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+)
+
+type stubSender struct {
+	reports []Report
+	err     error
+}
+
+func (s *stubSender) Send(_ context.Context, r Report) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.reports = append(s.reports, r)
+	return nil
+}
+
+var start = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+func TestCollector_OffByDefault(t *testing.T) {
+	c := NewCollector("1.0")
+	c.Count("GET /api/v1/prices")
+	if c.Enabled() || len(c.Preview().Features) != 0 {
+		t.Errorf("Expected nothing to be counted before opting in, got %+v", c.Preview())
+	}
+}
+
+func TestCollector_Flush(t *testing.T) {
+	clk := clock.NewFake(start)
+	c := NewCollector("1.0")
+	c.SetClock(clk)
+	sender := &stubSender{}
+	c.Enable(sender, 24*time.Hour)
+
+	c.Count("GET /api/v1/prices")
+	c.Count("GET /api/v1/prices")
+	c.Count("GET /lite")
+	clk.Advance(time.Hour)
+
+	preview := c.Preview()
+	if err := c.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(sender.reports) != 1 {
+		t.Fatalf("Expected one report, got %d", len(sender.reports))
+	}
+	sent := sender.reports[0]
+	if sent.Version != "1.0" || !sent.From.Equal(start) || !sent.To.Equal(preview.To) || sent.Features["GET /api/v1/prices"] != 2 || sent.Features["GET /lite"] != 1 {
+		t.Errorf("Expected the previewed report to be sent, got %+v", sent)
+	}
+	if len(c.Preview().Features) != 0 || !c.Preview().From.Equal(sent.To) {
+		t.Errorf("Expected a new period after sending, got %+v", c.Preview())
+	}
+
+	// Empty periods are not sent
+	if err := c.Flush(context.Background()); err != nil || len(sender.reports) != 1 {
+		t.Errorf("Expected no report without usage, got %d, %v", len(sender.reports), err)
+	}
+}
+
+func TestCollector_FailedSendKeepsCounts(t *testing.T) {
+	c := NewCollector("1.0")
+	sender := &stubSender{err: errors.New("offline")}
+	c.Enable(sender, time.Hour)
+	c.Count("GET /lite")
+	if err := c.Flush(context.Background()); err == nil {
+		t.Fatal("Expected the send error")
+	}
+	if c.Preview().Features["GET /lite"] != 1 {
+		t.Errorf("Expected the counts to be kept, got %+v", c.Preview())
+	}
+}
+
+func TestCollector_Disable(t *testing.T) {
+	c := NewCollector("1.0")
+	sender := &stubSender{}
+	c.Enable(sender, time.Hour)
+	c.Count("GET /lite")
+	c.Disable()
+	c.Enable(sender, time.Hour)
+	c.Count("GET /lite")
+
+	if c.Enabled() || len(c.Preview().Features) != 0 {
+		t.Errorf("Expected telemetry to stay off, got %+v", c.Preview())
+	}
+	if err := c.Flush(context.Background()); err != nil || len(sender.reports) != 0 {
+		t.Errorf("Expected nothing to be sent, got %d, %v", len(sender.reports), err)
+	}
+}
@@ -0,0 +1,34 @@
+package theme
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// WriteCSS writes the palette as custom properties on :root in name order,
+// e.g. "--accent: #4f8cff;", plus the matching color-scheme
+func WriteCSS(w io.Writer, t models.Theme) error {
+	palette := t.Palette()
+	names := make([]string, 0, len(palette))
+	for name := range palette {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	scheme := "dark"
+	if t.Mode == models.ThemeLight {
+		scheme = "light"
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "/* %s theme */\n:root {\n  color-scheme: %s;\n", t.Mode, scheme)
+	for _, name := range names {
+		fmt.Fprintf(bw, "  --%s: %s;\n", name, palette[name])
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
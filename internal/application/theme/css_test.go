@@ -0,0 +1,25 @@
+package theme
+
+import (
+	"strings"
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestWriteCSS(t *testing.T) {
+	var b strings.Builder
+	if err := WriteCSS(&b, models.Theme{Mode: models.ThemeLight, Accent: "#ff8800"}); err != nil {
+		t.Fatalf("WriteCSS: %v", err)
+	}
+	css := b.String()
+
+	for _, want := range []string{":root {", "color-scheme: light;", "--accent: #ff8800;", "--bg: #f5f6f8;"} {
+		if !strings.Contains(css, want) {
+			t.Errorf("Expected %q in:\n%s", want, css)
+		}
+	}
+	if strings.Index(css, "--accent") > strings.Index(css, "--bg") {
+		t.Error("Expected variables in name order")
+	}
+}
@@ -0,0 +1,82 @@
+// Package theme stores the dashboard appearance of each owner and renders it
+// as CSS custom properties shared by the main UI and embedded widgets
+package theme
+
+import (
+	"errors"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// ErrNotFound is returned when an owner has no stored theme
+var ErrNotFound = errors.New("theme not found")
+
+// Repository persists one theme per owner
+type Repository interface {
+	Get(owner string) (models.Theme, error)
+	Save(t models.Theme) (models.Theme, error)
+	Delete(owner string) error
+}
+
+// Service manages owner themes
+type Service struct {
+	repo Repository
+}
+
+// NewService creates a theme service
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Get returns the theme of an owner, or the default theme when they never chose one
+func (s *Service) Get(owner string) (models.Theme, error) {
+	t, err := s.repo.Get(owner)
+	if errors.Is(err, ErrNotFound) {
+		return models.DefaultTheme(owner), nil
+	}
+	return t, err
+}
+
+// Set validates and stores the theme of an owner
+func (s *Service) Set(owner string, t models.Theme) (models.Theme, error) {
+	t.Owner = owner
+	t.Normalize()
+	if err := t.Validate(); err != nil {
+		return models.Theme{}, err
+	}
+	current, err := s.Get(owner)
+	if err != nil {
+		return models.Theme{}, err
+	}
+	t.Privacy = current.Privacy
+	t.UpdatedAt = time.Now().UTC()
+	return s.repo.Save(t)
+}
+
+// SetPrivacy turns the privacy mode of an owner on or off, keeping their colors
+func (s *Service) SetPrivacy(owner string, enabled bool) (models.Theme, error) {
+	t, err := s.Get(owner)
+	if err != nil {
+		return models.Theme{}, err
+	}
+	t.Privacy = enabled
+	t.UpdatedAt = time.Now().UTC()
+	return s.repo.Save(t)
+}
+
+// Private reports whether an owner has the privacy mode on. Failures to load
+// the theme count as on so values are never shown by accident.
+func (s *Service) Private(owner string) bool {
+	t, err := s.Get(owner)
+	return err != nil || t.Privacy
+}
+
+// Reset drops the stored theme of an owner so the default applies again
+func (s *Service) Reset(owner string) error {
+	err := s.repo.Delete(owner)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+package theme
+
+import (
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubRepo map[string]models.Theme
+
+func (r stubRepo) Get(owner string) (models.Theme, error) {
+	t, ok := r[owner]
+	if !ok {
+		return models.Theme{}, ErrNotFound
+	}
+	return t, nil
+}
+
+func (r stubRepo) Save(t models.Theme) (models.Theme, error) {
+	r[t.Owner] = t
+	return t, nil
+}
+
+func (r stubRepo) Delete(owner string) error {
+	if _, ok := r[owner]; !ok {
+		return ErrNotFound
+	}
+	delete(r, owner)
+	return nil
+}
+
+func TestService_DefaultsUntilSet(t *testing.T) {
+	s := NewService(stubRepo{})
+
+	got, err := s.Get("alice")
+	if err != nil || got.Mode != models.ThemeDark || got.Owner != "alice" {
+		t.Fatalf("Expected the default dark theme, got %+v (%v)", got, err)
+	}
+
+	set, err := s.Set("alice", models.Theme{Owner: "mallory", Mode: "LIGHT", Accent: "#FF8800"})
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if set.Owner != "alice" || set.Mode != models.ThemeLight || set.UpdatedAt.IsZero() {
+		t.Errorf("Expected the theme stored for alice, got %+v", set)
+	}
+	if got, _ := s.Get("alice"); got.Accent != "#ff8800" {
+		t.Errorf("Expected the stored accent, got %+v", got)
+	}
+
+	if err := s.Reset("alice"); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	if err := s.Reset("alice"); err != nil {
+		t.Errorf("Expected resetting twice to succeed, got %v", err)
+	}
+	if got, _ := s.Get("alice"); got.Mode != models.ThemeDark {
+		t.Errorf("Expected the default after a reset, got %+v", got)
+	}
+}
+
+func TestService_RejectsInvalidTheme(t *testing.T) {
+	s := NewService(stubRepo{})
+	if _, err := s.Set("alice", models.Theme{Mode: "sepia"}); err == nil {
+		t.Error("Expected an unknown mode to be rejected")
+	}
+}
+
+func TestService_PrivacyOutlivesColorChanges(t *testing.T) {
+	s := NewService(stubRepo{})
+	if s.Private("alice") {
+		t.Fatal("Expected privacy mode to be off by default")
+	}
+
+	if _, err := s.SetPrivacy("alice", true); err != nil {
+		t.Fatal(err)
+	}
+	set, err := s.Set("alice", models.Theme{Mode: "light"})
+	if err != nil || !set.Privacy || set.Mode != models.ThemeLight {
+		t.Fatalf("Expected a new mode with privacy kept, got %+v (%v)", set, err)
+	}
+	if !s.Private("alice") || s.Private("bob") {
+		t.Error("Expected privacy mode for alice only")
+	}
+}
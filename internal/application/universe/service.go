@@ -0,0 +1,221 @@
+// Package universe resolves named universes, such as the top coins by market
+// cap or volume, a category or a custom list, into the coins they contain
+package universe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// ErrNotFound is returned for universes that are not defined
+var ErrNotFound = errors.New("universe not found")
+
+// Source fetches the coins of a universe with their market data
+type Source interface {
+	GetUniverse(u models.Universe, currency models.Currency) ([]models.CryptoPrice, error)
+}
+
+type resolved struct {
+	members []models.CryptoPrice
+	at      time.Time
+}
+
+// Service holds the defined universes and caches their members
+type Service struct {
+	source    Source
+	currency  models.Currency
+	interval  time.Duration
+	universes map[string]models.Universe
+	logger    *slog.Logger
+	clock     clock.Clock
+	throttle  func() bool
+
+	mu       sync.Mutex
+	members  map[string]resolved
+	onChange []func()
+	lastRun  time.Time
+}
+
+// NewService validates the universes and creates a service refreshing their members every interval
+func NewService(source Source, currency models.Currency, interval time.Duration, universes ...models.Universe) (*Service, error) {
+	s := &Service{
+		source:    source,
+		currency:  currency,
+		interval:  interval,
+		universes: make(map[string]models.Universe, len(universes)),
+		logger:    slog.Default(),
+		clock:     clock.Real,
+		members:   make(map[string]resolved),
+	}
+	for _, u := range universes {
+		u.Coins = slices.Clone(u.Coins)
+		u.Normalize()
+		if err := u.Validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := s.universes[u.Name]; ok {
+			return nil, fmt.Errorf("universe %s is defined twice", u.Name)
+		}
+		s.universes[u.Name] = u
+	}
+	return s, nil
+}
+
+// SetLogger replaces the default logger used to report refresh failures
+func (s *Service) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
+// SetThrottle registers a check run before every scheduled refresh. The
+// refresh is skipped while it returns true, e.g. when the provider's daily
+// call budget is nearly spent. It must be called before Run.
+func (s *Service) SetThrottle(throttled func() bool) {
+	s.throttle = throttled
+}
+
+// SetClock replaces the system clock, for tests
+func (s *Service) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// OnChange registers a function called after a refresh changed the members of any universe
+func (s *Service) OnChange(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = append(s.onChange, fn)
+}
+
+// List returns the defined universes sorted by name
+func (s *Service) List() []models.Universe {
+	universes := make([]models.Universe, 0, len(s.universes))
+	for _, u := range s.universes {
+		universes = append(universes, u)
+	}
+	sort.Slice(universes, func(i, j int) bool { return universes[i].Name < universes[j].Name })
+	return universes
+}
+
+// Get returns a universe by name
+func (s *Service) Get(name string) (models.Universe, error) {
+	u, ok := s.universes[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return models.Universe{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return u, nil
+}
+
+// Members returns the coins of a universe with their market data, resolving
+// them when the cached members are older than the interval. When resolving
+// fails the last known members are returned; the error is only returned when there are none.
+func (s *Service) Members(name string) ([]models.CryptoPrice, error) {
+	u, err := s.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	cached, ok := s.members[u.Name]
+	s.mu.Unlock()
+	if ok && s.clock.Now().Sub(cached.at) < s.interval {
+		return cached.members, nil
+	}
+
+	members, _, err := s.resolve(u)
+	if err != nil {
+		if ok {
+			return cached.members, nil
+		}
+		return nil, err
+	}
+	return members, nil
+}
+
+// Coins returns the IDs of the members of a universe in rank order
+func (s *Service) Coins(name string) ([]string, error) {
+	members, err := s.Members(name)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(members))
+	for i, p := range members {
+		ids[i] = p.ID
+	}
+	return ids, nil
+}
+
+// Refresh resolves every universe and notifies the OnChange functions when any membership changed
+func (s *Service) Refresh() error {
+	var errs []error
+	changed := false
+	for _, u := range s.List() {
+		_, c, err := s.resolve(u)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to resolve universe %s: %w", u.Name, err))
+		}
+		changed = changed || c
+	}
+	if changed {
+		s.mu.Lock()
+		callbacks := slices.Clone(s.onChange)
+		s.mu.Unlock()
+		for _, fn := range callbacks {
+			fn()
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run refreshes the universes every interval until the context is cancelled
+func (s *Service) Run(ctx context.Context) {
+	ticker := s.clock.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if s.throttle != nil && s.throttle() {
+			s.logger.Debug("universe refresh skipped, call budget nearly spent")
+		} else if err := s.Refresh(); err != nil {
+			s.logger.Warn("universe refresh failed", "error", err)
+		}
+		now := s.clock.Now()
+		s.mu.Lock()
+		s.lastRun = now
+		s.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+	}
+}
+
+// LastRun returns when Run last ended a refresh cycle, refreshed or skipped,
+// or the zero time if it has not yet
+func (s *Service) LastRun() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastRun
+}
+
+// resolve fetches the members of a universe and reports whether their IDs changed
+func (s *Service) resolve(u models.Universe) ([]models.CryptoPrice, bool, error) {
+	members, err := s.source.GetUniverse(u, s.currency)
+	if err != nil {
+		return nil, false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, ok := s.members[u.Name]
+	s.members[u.Name] = resolved{members: members, at: s.clock.Now()}
+	changed := !ok || !slices.EqualFunc(previous.members, members, func(a, b models.CryptoPrice) bool { return a.ID == b.ID })
+	return members, changed, nil
+}
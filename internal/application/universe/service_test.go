@@ -0,0 +1,97 @@
+package universe
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+var testUniverses = []models.Universe{
+	{Name: "top-100", Kind: models.UniverseMarketCap, Size: 100},
+	{Name: "top-volume", Kind: models.UniverseVolume, Size: 50},
+}
+
+type stubSource struct {
+	members map[string][]string
+	calls   int
+	err     error
+}
+
+func (s *stubSource) GetUniverse(u models.Universe, currency models.Currency) ([]models.CryptoPrice, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	var prices []models.CryptoPrice
+	for _, id := range s.members[u.Name] {
+		prices = append(prices, models.CryptoPrice{ID: id, Currency: currency})
+	}
+	return prices, nil
+}
+
+func TestNewService_Validates(t *testing.T) {
+	if _, err := NewService(&stubSource{}, models.USD, time.Minute, models.Universe{Name: "x", Kind: "gainers"}); err == nil {
+		t.Error("Expected an invalid universe to be rejected")
+	}
+	dup := models.Universe{Name: "Majors", Kind: models.UniverseCustom, Coins: []string{"bitcoin"}}
+	if _, err := NewService(&stubSource{}, models.USD, time.Minute, dup, dup); err == nil {
+		t.Error("Expected duplicate names to be rejected")
+	}
+}
+
+func TestService_Members(t *testing.T) {
+	source := &stubSource{members: map[string][]string{"top-100": {"bitcoin", "ethereum"}}}
+	s, err := NewService(source, models.USD, time.Minute, testUniverses...)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(now)
+	s.SetClock(clk)
+
+	if got := s.List(); len(got) != 2 || got[0].Name != "top-100" {
+		t.Errorf("Unexpected universes: %+v", got)
+	}
+	coins, err := s.Coins("TOP-100")
+	if err != nil || len(coins) != 2 || coins[0] != "bitcoin" {
+		t.Fatalf("Unexpected coins: %v (%v)", coins, err)
+	}
+	s.Coins("top-100")
+	if source.calls != 1 {
+		t.Errorf("Expected cached members within the interval, got %d calls", source.calls)
+	}
+
+	// Stale members are served when resolving fails
+	clk.Advance(time.Hour)
+	source.err = errors.New("rate limited")
+	if coins, err := s.Coins("top-100"); err != nil || len(coins) != 2 {
+		t.Errorf("Expected the last known members, got %v (%v)", coins, err)
+	}
+	if _, err := s.Coins("top-volume"); err == nil {
+		t.Error("Expected the error without known members")
+	}
+	if _, err := s.Coins("defi"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestService_RefreshNotifiesChanges(t *testing.T) {
+	source := &stubSource{members: map[string][]string{"majors": {"bitcoin"}}}
+	s, _ := NewService(source, models.USD, time.Minute, models.Universe{Name: "majors", Kind: models.UniverseCustom, Coins: []string{"bitcoin"}})
+	notified := 0
+	s.OnChange(func() { notified++ })
+
+	s.Refresh()
+	s.Refresh()
+	if notified != 1 {
+		t.Errorf("Expected one notification for the first resolution only, got %d", notified)
+	}
+	source.members["majors"] = []string{"bitcoin", "ethereum"}
+	s.Refresh()
+	if notified != 2 {
+		t.Errorf("Expected a notification when members change, got %d", notified)
+	}
+}
@@ -0,0 +1,155 @@
+// Package watchdog supervises long-running loops such as the poller and the
+// tick consumers, restarting any that stops making progress. Go cannot stop a
+// goroutine, so a wedged run is cancelled and left behind; the restart only
+// helps when it is stuck on something that eventually returns or fails, like
+// a provider call, and not on a deadlock the new run would hit too.
+package watchdog
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/application/events"
+	"crypto-dashboard/internal/clock"
+)
+
+// Component is a loop the watchdog runs and supervises
+type Component struct {
+	Name string
+	// Interval is how often the component is expected to make progress
+	Interval time.Duration
+	// Run runs the component until the context is cancelled
+	Run func(ctx context.Context)
+	// Progress returns when the component last made progress
+	Progress func() time.Time
+	// Pending reports whether work is waiting, for components that are idle
+	// without it, such as stream consumers. Without it the component is
+	// expected to progress every interval.
+	Pending func() bool
+}
+
+// Observer is notified about restarts, e.g. to export metrics
+type Observer interface {
+	ComponentRestarted(name string)
+}
+
+// Watchdog restarts components that made no progress for several intervals
+type Watchdog struct {
+	missed    int
+	publisher events.Publisher
+	observer  Observer
+	clock     clock.Clock
+	logger    *slog.Logger
+
+	mu         sync.Mutex
+	ctx        context.Context
+	supervised []*supervised
+}
+
+type supervised struct {
+	Component
+	cancel context.CancelFunc
+	// since is when the component was last started or work became pending
+	since time.Time
+}
+
+// New creates a watchdog restarting components after missed intervals
+// without progress and publishing a ComponentWedged event for each restart
+func New(missed int, publisher events.Publisher) *Watchdog {
+	return &Watchdog{missed: missed, publisher: publisher, clock: clock.Real, logger: slog.Default()}
+}
+
+// SetClock replaces the system clock, which must be the one the components
+// report progress with. It must be called before Start.
+func (w *Watchdog) SetClock(c clock.Clock) {
+	w.clock = c
+}
+
+// SetLogger replaces the default logger
+func (w *Watchdog) SetLogger(logger *slog.Logger) {
+	w.logger = logger
+}
+
+// SetObserver registers an observer. It must be called before Start.
+func (w *Watchdog) SetObserver(observer Observer) {
+	w.observer = observer
+}
+
+// Supervise adds a component. It must be called before Start.
+func (w *Watchdog) Supervise(c Component) {
+	w.supervised = append(w.supervised, &supervised{Component: c})
+}
+
+// Start runs every component until the context is cancelled
+func (w *Watchdog) Start(ctx context.Context) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.ctx = ctx
+	for _, s := range w.supervised {
+		w.start(s)
+	}
+}
+
+// Run starts the components and checks them every period until the context
+// is cancelled
+func (w *Watchdog) Run(ctx context.Context, every time.Duration) {
+	w.Start(ctx)
+	ticker := w.clock.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			w.Check()
+		}
+	}
+}
+
+// Check restarts the components without progress for missed intervals and
+// returns their names
+func (w *Watchdog) Check() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := w.clock.Now()
+	var restarted []string
+	for _, s := range w.supervised {
+		if s.Pending != nil && !s.Pending() {
+			s.since = time.Time{}
+			continue
+		}
+		if s.since.IsZero() {
+			s.since = now
+		}
+		last := s.Progress()
+		if last.Before(s.since) {
+			last = s.since
+		}
+		stalled := now.Sub(last)
+		if stalled <= time.Duration(w.missed)*s.Interval {
+			continue
+		}
+
+		w.logger.Error("component wedged, restarting", "component", s.Name, "stalled", stalled)
+		s.cancel()
+		w.start(s)
+		restarted = append(restarted, s.Name)
+		if w.observer != nil {
+			w.observer.ComponentRestarted(s.Name)
+		}
+		if w.publisher != nil {
+			w.publisher.Publish(events.ComponentWedged{Component: s.Name, Stalled: stalled, At: now.UTC()})
+		}
+	}
+	return restarted
+}
+
+// start runs a component in its own context. The caller must hold the lock.
+func (w *Watchdog) start(s *supervised) {
+	ctx, cancel := context.WithCancel(w.ctx)
+	s.cancel = cancel
+	s.since = w.clock.Now()
+	go s.Run(ctx)
+}
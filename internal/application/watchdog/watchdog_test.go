@@ -0,0 +1,102 @@
+package watchdog
+
+import (
+	"context"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/events"
+	"crypto-dashboard/internal/clock"
+)
+
+type recorder struct{ events []events.Event }
+
+func (r *recorder) Publish(e events.Event) { r.events = append(r.events, e) }
+
+type restarts map[string]int
+
+func (r restarts) ComponentRestarted(name string) { r[name]++ }
+
+var start = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+func TestWatchdog_RestartsWedgedComponent(t *testing.T) {
+	clk := clock.NewFake(start)
+	published := &recorder{}
+	observed := restarts{}
+	w := New(3, published)
+	w.SetClock(clk)
+	w.SetObserver(observed)
+
+	var runs atomic.Int32
+	progress := start
+	w.Supervise(Component{
+		Name:     "poller",
+		Interval: time.Minute,
+		Run:      func(ctx context.Context) { runs.Add(1); <-ctx.Done() },
+		Progress: func() time.Time { return progress },
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w.Start(ctx)
+
+	clk.Set(start.Add(2 * time.Minute))
+	progress = clk.Now()
+	clk.Set(start.Add(5 * time.Minute))
+	if restarted := w.Check(); len(restarted) != 0 {
+		t.Fatalf("Expected no restart within 3 intervals of progress, got %v", restarted)
+	}
+
+	clk.Set(start.Add(6 * time.Minute))
+	if restarted := w.Check(); !slices.Equal(restarted, []string{"poller"}) {
+		t.Fatalf("Expected the poller to be restarted, got %v", restarted)
+	}
+	if observed["poller"] != 1 || len(published.events) != 1 {
+		t.Fatalf("Expected a metric and an event, got %v and %v", observed, published.events)
+	}
+	if e, ok := published.events[0].(events.ComponentWedged); !ok || e.Component != "poller" || e.Stalled != 4*time.Minute {
+		t.Errorf("Unexpected event: %+v", published.events[0])
+	}
+
+	// The restarted run gets a fresh grace period
+	clk.Set(start.Add(8 * time.Minute))
+	if restarted := w.Check(); len(restarted) != 0 {
+		t.Errorf("Expected no restart right after restarting, got %v", restarted)
+	}
+	for runs.Load() != 2 {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWatchdog_IdleStreamIsNotWedged(t *testing.T) {
+	clk := clock.NewFake(start)
+	w := New(3, nil)
+	w.SetClock(clk)
+
+	pending := false
+	w.Supervise(Component{
+		Name:     "candles",
+		Interval: time.Minute,
+		Run:      func(ctx context.Context) { <-ctx.Done() },
+		Progress: func() time.Time { return time.Time{} },
+		Pending:  func() bool { return pending },
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w.Start(ctx)
+
+	clk.Set(start.Add(time.Hour))
+	if restarted := w.Check(); len(restarted) != 0 {
+		t.Fatalf("Expected an idle stream to be left alone, got %v", restarted)
+	}
+	// Work waiting since the last check counts from when it was first seen
+	pending = true
+	if restarted := w.Check(); len(restarted) != 0 {
+		t.Fatalf("Expected new work to get a grace period, got %v", restarted)
+	}
+	clk.Set(start.Add(time.Hour + 4*time.Minute))
+	if restarted := w.Check(); !slices.Equal(restarted, []string{"candles"}) {
+		t.Errorf("Expected the stuck consumer to be restarted, got %v", restarted)
+	}
+}
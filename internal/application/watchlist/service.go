@@ -0,0 +1,307 @@
+// Package watchlist manages named coin lists and derives the set of coins the
+// poller tracks from the watchlists that are not archived
+package watchlist
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// ErrNotFound is returned when a watchlist does not exist
+var ErrNotFound = errors.New("watchlist not found")
+
+// Repository persists watchlists
+type Repository interface {
+	Save(w models.Watchlist) (models.Watchlist, error)
+	Get(id string) (models.Watchlist, error)
+	List() ([]models.Watchlist, error)
+	Delete(id string) error
+}
+
+// CoinTracker receives the union of watched coins every time it changes
+type CoinTracker interface {
+	SetCoins(ids []string)
+}
+
+// UniverseResolver resolves the coins of the universes watchlists follow
+type UniverseResolver interface {
+	Get(name string) (models.Universe, error)
+	Coins(name string) ([]string, error)
+}
+
+// Update is a partial change to a watchlist; nil fields are left untouched
+type Update struct {
+	Name *string `json:"name,omitempty"`
+	// Coins replaces the coins, so it also reorders them
+	Coins    []string `json:"coins,omitempty"`
+	Archived *bool    `json:"archived,omitempty"`
+	// Universe switches the watchlist to a universe, or back to its own coins when empty
+	Universe *string `json:"universe,omitempty"`
+}
+
+// Service manages watchlists and keeps the tracker in sync with them
+type Service struct {
+	repo      Repository
+	tracker   CoinTracker
+	universes UniverseResolver
+}
+
+// NewService creates a watchlist service. The tracker may be nil.
+func NewService(repo Repository, tracker CoinTracker) *Service {
+	return &Service{repo: repo, tracker: tracker}
+}
+
+// SetUniverses lets watchlists follow the universes of the resolver
+func (s *Service) SetUniverses(universes UniverseResolver) {
+	s.universes = universes
+}
+
+// EnsureDefault creates a default watchlist with the given coins when none exist
+// yet and pushes the tracked coins to the tracker
+func (s *Service) EnsureDefault(coins []string) error {
+	lists, err := s.repo.List()
+	if err != nil {
+		return err
+	}
+	if len(lists) == 0 {
+		_, err := s.Create(models.Watchlist{Name: "Default", Coins: slices.Clone(coins)})
+		return err
+	}
+	return s.sync()
+}
+
+// Create validates and stores a new watchlist at the end of its owner's lists
+func (s *Service) Create(w models.Watchlist) (models.Watchlist, error) {
+	w.ID = ""
+	w.Normalize()
+	if err := s.validate(w); err != nil {
+		return models.Watchlist{}, err
+	}
+
+	owned, err := s.List(w.Owner)
+	if err != nil {
+		return models.Watchlist{}, err
+	}
+	w.Position = len(owned)
+	w.CreatedAt = time.Now().UTC()
+	w.UpdatedAt = w.CreatedAt
+
+	created, err := s.repo.Save(w)
+	if err != nil {
+		return models.Watchlist{}, err
+	}
+	return s.withUniverse(created), s.sync()
+}
+
+// List returns the watchlists of an owner in display order
+func (s *Service) List(owner string) ([]models.Watchlist, error) {
+	all, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	lists := []models.Watchlist{}
+	for _, w := range all {
+		if w.Owner == owner {
+			lists = append(lists, s.withUniverse(w))
+		}
+	}
+	sortLists(lists)
+	return lists, nil
+}
+
+// Get returns a single watchlist
+func (s *Service) Get(id string) (models.Watchlist, error) {
+	w, err := s.repo.Get(id)
+	if err != nil {
+		return models.Watchlist{}, err
+	}
+	return s.withUniverse(w), nil
+}
+
+// Update renames, re-archives or replaces the coins of a watchlist
+func (s *Service) Update(id string, u Update) (models.Watchlist, error) {
+	w, err := s.repo.Get(id)
+	if err != nil {
+		return models.Watchlist{}, err
+	}
+	if u.Name != nil {
+		w.Name = *u.Name
+	}
+	if u.Coins != nil {
+		w.Coins = slices.Clone(u.Coins)
+	}
+	if u.Archived != nil {
+		w.Archived = *u.Archived
+	}
+	if u.Universe != nil {
+		w.Universe = *u.Universe
+		// Following a universe drops the listed coins unless new ones are given
+		if *u.Universe != "" && u.Coins == nil {
+			w.Coins = nil
+		}
+	}
+	w.Normalize()
+	if err := s.validate(w); err != nil {
+		return models.Watchlist{}, err
+	}
+	w.UpdatedAt = time.Now().UTC()
+
+	saved, err := s.repo.Save(w)
+	if err != nil {
+		return models.Watchlist{}, err
+	}
+	return s.withUniverse(saved), s.sync()
+}
+
+// Reorder sets the display order of an owner's watchlists. ids must list every
+// watchlist of the owner exactly once.
+func (s *Service) Reorder(owner string, ids []string) ([]models.Watchlist, error) {
+	lists, err := s.List(owner)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) != len(lists) {
+		return nil, fmt.Errorf("expected %d watchlist IDs, got %d", len(lists), len(ids))
+	}
+
+	byID := make(map[string]models.Watchlist, len(lists))
+	for _, w := range lists {
+		byID[w.ID] = w
+	}
+	reordered := make([]models.Watchlist, len(ids))
+	for i, id := range ids {
+		w, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		delete(byID, id)
+		w.Position = i
+		reordered[i] = w
+	}
+
+	for i, w := range reordered {
+		if w, err = s.repo.Save(w); err != nil {
+			return nil, err
+		}
+		reordered[i] = s.withUniverse(w)
+	}
+	return reordered, s.sync()
+}
+
+// Delete removes a watchlist
+func (s *Service) Delete(id string) error {
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	return s.sync()
+}
+
+// Containing returns the watchlists that are not archived and hold a coin,
+// directly or through the universe they follow
+func (s *Service) Containing(cryptoID string) ([]models.Watchlist, error) {
+	all, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	sortLists(all)
+	var lists []models.Watchlist
+	for _, w := range all {
+		if w = s.withUniverse(w); !w.Archived && slices.Contains(w.Coins, cryptoID) {
+			lists = append(lists, w)
+		}
+	}
+	return lists, nil
+}
+
+// Refresh pushes the tracked coins to the tracker again, e.g. after the
+// members of a followed universe changed
+func (s *Service) Refresh() error {
+	return s.sync()
+}
+
+// TrackedCoins returns the union of the coins of every watchlist that is not
+// archived, following the display order of the lists and their coins. Lists
+// following a universe contribute its current members.
+func (s *Service) TrackedCoins() ([]string, error) {
+	lists, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	sortLists(lists)
+
+	seen := make(map[string]bool)
+	coins := []string{}
+	for _, w := range lists {
+		if w.Archived {
+			continue
+		}
+		for _, id := range s.withUniverse(w).Coins {
+			if !seen[id] {
+				seen[id] = true
+				coins = append(coins, id)
+			}
+		}
+	}
+	return coins, nil
+}
+
+// validate checks a watchlist and that the universe it follows exists
+func (s *Service) validate(w models.Watchlist) error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+	if w.Universe == "" {
+		return nil
+	}
+	if s.universes == nil {
+		return errors.New("universes are not configured")
+	}
+	_, err := s.universes.Get(w.Universe)
+	return err
+}
+
+// withUniverse fills the coins of a watchlist following a universe with its
+// members. They stay empty while the universe cannot be resolved.
+func (s *Service) withUniverse(w models.Watchlist) models.Watchlist {
+	if w.Universe == "" || s.universes == nil {
+		return w
+	}
+	coins, err := s.universes.Coins(w.Universe)
+	if err != nil {
+		coins = []string{}
+	}
+	w.Coins = coins
+	return w
+}
+
+// sync pushes the tracked coins to the tracker
+func (s *Service) sync() error {
+	if s.tracker == nil {
+		return nil
+	}
+	coins, err := s.TrackedCoins()
+	if err != nil {
+		return err
+	}
+	s.tracker.SetCoins(coins)
+	return nil
+}
+
+// sortLists orders watchlists by owner, position and creation time
+func sortLists(lists []models.Watchlist) {
+	sort.SliceStable(lists, func(i, j int) bool {
+		a, b := lists[i], lists[j]
+		if a.Owner != b.Owner {
+			return a.Owner < b.Owner
+		}
+		if a.Position != b.Position {
+			return a.Position < b.Position
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+}
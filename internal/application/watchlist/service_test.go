@@ -0,0 +1,193 @@
+package watchlist
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubRepo struct {
+	lists map[string]models.Watchlist
+	next  int
+}
+
+func newStubRepo() *stubRepo {
+	return &stubRepo{lists: make(map[string]models.Watchlist)}
+}
+
+func (r *stubRepo) Save(w models.Watchlist) (models.Watchlist, error) {
+	if w.ID == "" {
+		r.next++
+		w.ID = string(rune('a' + r.next - 1))
+	}
+	r.lists[w.ID] = w
+	return w, nil
+}
+
+func (r *stubRepo) Get(id string) (models.Watchlist, error) {
+	w, ok := r.lists[id]
+	if !ok {
+		return models.Watchlist{}, ErrNotFound
+	}
+	return w, nil
+}
+
+func (r *stubRepo) List() ([]models.Watchlist, error) {
+	var lists []models.Watchlist
+	for _, w := range r.lists {
+		lists = append(lists, w)
+	}
+	return lists, nil
+}
+
+func (r *stubRepo) Delete(id string) error {
+	if _, ok := r.lists[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.lists, id)
+	return nil
+}
+
+type stubTracker struct {
+	coins []string
+}
+
+func (s *stubTracker) SetCoins(ids []string) { s.coins = ids }
+
+func TestService_TracksUnionOfActiveWatchlists(t *testing.T) {
+	tracker := &stubTracker{}
+	svc := NewService(newStubRepo(), tracker)
+
+	if err := svc.EnsureDefault([]string{"bitcoin", "ethereum"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(tracker.coins, ",") != "bitcoin,ethereum" {
+		t.Errorf("Expected default coins to be tracked, got %v", tracker.coins)
+	}
+
+	alts, err := svc.Create(models.Watchlist{Name: "Alts", Coins: []string{"Solana", "ethereum"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if alts.Position != 1 || alts.Owner != models.DefaultOwner {
+		t.Errorf("Expected second default list, got %+v", alts)
+	}
+	if strings.Join(tracker.coins, ",") != "bitcoin,ethereum,solana" {
+		t.Errorf("Expected union of watchlists, got %v", tracker.coins)
+	}
+
+	archived := true
+	if _, err := svc.Update(alts.ID, Update{Archived: &archived}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(tracker.coins, ",") != "bitcoin,ethereum" {
+		t.Errorf("Expected archived list to be ignored, got %v", tracker.coins)
+	}
+
+	if lists, _ := svc.Containing("solana"); len(lists) != 0 {
+		t.Errorf("Expected archived lists not to contain coins, got %+v", lists)
+	}
+	if lists, _ := svc.Containing("ethereum"); len(lists) != 1 || lists[0].Name != "Default" {
+		t.Errorf("Expected the default list to contain ethereum, got %+v", lists)
+	}
+
+	// EnsureDefault does not recreate the default list once watchlists exist
+	svc.EnsureDefault([]string{"dogecoin"})
+	if lists, _ := svc.List(models.DefaultOwner); len(lists) != 2 {
+		t.Errorf("Expected 2 watchlists, got %d", len(lists))
+	}
+}
+
+func TestService_UpdateAndReorder(t *testing.T) {
+	svc := NewService(newStubRepo(), nil)
+	first, _ := svc.Create(models.Watchlist{Name: "First", Coins: []string{"bitcoin"}})
+	second, _ := svc.Create(models.Watchlist{Name: "Second"})
+	svc.Create(models.Watchlist{Name: "Other", Owner: "alice"})
+
+	name := " Renamed "
+	updated, err := svc.Update(first.ID, Update{Name: &name, Coins: []string{"ethereum", "bitcoin"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if updated.Name != "Renamed" || updated.Coins[0] != "ethereum" {
+		t.Errorf("Expected renamed and reordered coins, got %+v", updated)
+	}
+
+	empty := ""
+	if _, err := svc.Update(first.ID, Update{Name: &empty}); err == nil {
+		t.Error("Expected validation error for empty name")
+	}
+	if _, err := svc.Update("missing", Update{}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	if _, err := svc.Reorder(models.DefaultOwner, []string{second.ID, first.ID}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lists, _ := svc.List(models.DefaultOwner)
+	if len(lists) != 2 || lists[0].ID != second.ID || lists[1].Position != 1 {
+		t.Errorf("Expected Second before First, got %+v", lists)
+	}
+
+	if _, err := svc.Reorder(models.DefaultOwner, []string{first.ID}); err == nil {
+		t.Error("Expected error when not every watchlist is listed")
+	}
+	if _, err := svc.Reorder(models.DefaultOwner, []string{first.ID, first.ID}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a repeated ID, got %v", err)
+	}
+}
+
+type stubUniverses struct {
+	members map[string][]string
+}
+
+func (s *stubUniverses) Get(name string) (models.Universe, error) {
+	if _, ok := s.members[name]; !ok {
+		return models.Universe{}, errors.New("universe not found")
+	}
+	return models.Universe{Name: name, Kind: models.UniverseMarketCap, Size: len(s.members[name])}, nil
+}
+
+func (s *stubUniverses) Coins(name string) ([]string, error) {
+	return append([]string(nil), s.members[name]...), nil
+}
+
+func TestService_FollowsUniverses(t *testing.T) {
+	tracker := &stubTracker{}
+	svc := NewService(newStubRepo(), tracker)
+	if _, err := svc.Create(models.Watchlist{Name: "Top", Universe: "top-2"}); err == nil {
+		t.Error("Expected an error while universes are not configured")
+	}
+
+	universes := &stubUniverses{members: map[string][]string{"top-2": {"bitcoin", "ethereum"}}}
+	svc.SetUniverses(universes)
+	svc.Create(models.Watchlist{Name: "Mine", Coins: []string{"solana", "bitcoin"}})
+	top, err := svc.Create(models.Watchlist{Name: "Top", Universe: "TOP-2"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(top.Coins, ",") != "bitcoin,ethereum" {
+		t.Errorf("Expected the universe members, got %v", top.Coins)
+	}
+	if strings.Join(tracker.coins, ",") != "solana,bitcoin,ethereum" {
+		t.Errorf("Expected universe members to be tracked, got %v", tracker.coins)
+	}
+
+	universes.members["top-2"] = []string{"bitcoin", "tether"}
+	svc.Refresh()
+	if strings.Join(tracker.coins, ",") != "solana,bitcoin,tether" {
+		t.Errorf("Expected changed members after a refresh, got %v", tracker.coins)
+	}
+
+	missing := "top-10"
+	if _, err := svc.Update(top.ID, Update{Universe: &missing}); err == nil {
+		t.Error("Expected an error for an unknown universe")
+	}
+	none := ""
+	updated, err := svc.Update(top.ID, Update{Universe: &none, Coins: []string{"dogecoin"}})
+	if err != nil || updated.Universe != "" || strings.Join(updated.Coins, ",") != "dogecoin" {
+		t.Errorf("Expected the list to own its coins again, got %+v (%v)", updated, err)
+	}
+}
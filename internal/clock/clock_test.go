@@ -0,0 +1,66 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFake_Advance(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	f := NewFake(start)
+	ticker := f.NewTicker(time.Minute)
+	timer := f.NewTimer(90 * time.Second)
+
+	f.Advance(30 * time.Second)
+	select {
+	case <-ticker.C():
+		t.Fatal("Expected no tick before the period")
+	default:
+	}
+
+	f.Advance(30 * time.Second)
+	if tick := <-ticker.C(); !tick.Equal(start.Add(time.Minute)) {
+		t.Errorf("Expected a tick at the minute, got %v", tick)
+	}
+	f.Advance(time.Minute)
+	if fired := <-timer.C(); !fired.Equal(start.Add(90 * time.Second)) {
+		t.Errorf("Expected the timer to fire at its deadline, got %v", fired)
+	}
+	if timer.Stop() {
+		t.Error("Expected a fired timer not to be active")
+	}
+	if !f.Now().Equal(start.Add(2 * time.Minute)) {
+		t.Errorf("Expected the clock to end at the advanced time, got %v", f.Now())
+	}
+
+	// Unreceived ticks are dropped like with time.Ticker
+	f.Advance(5 * time.Minute)
+	<-ticker.C()
+	select {
+	case <-ticker.C():
+		t.Error("Expected a single buffered tick")
+	default:
+	}
+	ticker.Stop()
+	f.Advance(time.Hour)
+	select {
+	case <-ticker.C():
+		t.Error("Expected a stopped ticker not to fire")
+	default:
+	}
+}
+
+func TestFake_BlockUntil(t *testing.T) {
+	f := NewFake(time.Time{})
+	done := make(chan time.Time)
+	go func() {
+		timer := f.NewTimer(time.Hour)
+		done <- <-timer.C()
+	}()
+
+	f.BlockUntil(1)
+	f.Advance(time.Hour)
+	if fired := <-done; !fired.Equal(time.Time{}.Add(time.Hour)) {
+		t.Errorf("Expected the timer to fire after an hour, got %v", fired)
+	}
+}
@@ -0,0 +1,998 @@
+// Package config loads the dashboard configuration from a YAML file and environment variables
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// EnvPrefix is prepended to every environment variable override
+const EnvPrefix = "DASHBOARD_"
+
+// Config is the root configuration injected into every subsystem
+type Config struct {
+	API       APIConfig       `yaml:"api"`
+	Poller    PollerConfig    `yaml:"poller"`
+	Candles   CandlesConfig   `yaml:"candles"`
+	History   HistoryConfig   `yaml:"history"`
+	Icons     IconsConfig     `yaml:"icons"`
+	Compare   CompareConfig   `yaml:"compare"`
+	ETF       ETFConfig       `yaml:"etf"`
+	Universe  UniverseConfig  `yaml:"universe"`
+	Server    ServerConfig    `yaml:"server"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Log       LogConfig       `yaml:"log"`
+	Sheets    SheetsConfig    `yaml:"sheets"`
+	Notify    NotifyConfig    `yaml:"notify"`
+	Telemetry TelemetryConfig `yaml:"telemetry"`
+	Portfolio PortfolioConfig `yaml:"portfolio"`
+}
+
+// APIConfig configures the CoinGecko client
+type APIConfig struct {
+	BaseURL     string        `yaml:"base_url"`
+	APIKey      string        `yaml:"api_key"`
+	Timeout     time.Duration `yaml:"timeout"`
+	Concurrency int           `yaml:"concurrency"`
+	// BatchSize is the number of coin IDs sent per price request
+	BatchSize int            `yaml:"batch_size"`
+	Breaker   BreakerConfig  `yaml:"breaker"`
+	Fixtures  FixturesConfig `yaml:"fixtures"`
+	// Proxy and TLS apply to every outbound provider request: CoinGecko,
+	// the comparison exchanges and the ETF flow tables
+	Proxy ProxyConfig `yaml:"proxy"`
+	TLS   TLSConfig   `yaml:"tls"`
+	DNS   DNSConfig   `yaml:"dns"`
+	// Budget limits the CoinGecko calls made per UTC day by background jobs
+	Budget BudgetConfig `yaml:"budget"`
+}
+
+// BudgetConfig is a daily call budget. Calls are only counted, never refused:
+// once the calls left fall to the reserve, the universe and market overview
+// refreshes are skipped so the poller and user requests can use the rest.
+type BudgetConfig struct {
+	// DailyCalls is the budget per UTC day; zero disables throttling
+	DailyCalls int64 `yaml:"daily_calls"`
+	// Reserve is the fraction of the budget kept for the poller and user requests
+	Reserve float64 `yaml:"reserve"`
+}
+
+// DNSConfig configures the resolution of provider hosts
+type DNSConfig struct {
+	// CacheTTL is how long resolved addresses are reused; zero disables the cache
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+	// MaxStale is how long expired addresses are still used while every resolver fails
+	MaxStale time.Duration `yaml:"max_stale"`
+	// Fallback DNS servers (ip or ip:port) are asked in order when the system resolver fails
+	Fallback []string `yaml:"fallback"`
+}
+
+// Enabled reports whether provider hosts are resolved by the dashboard
+// rather than on every connection by the system resolver
+func (c DNSConfig) Enabled() bool {
+	return c.CacheTTL > 0 || len(c.Fallback) > 0
+}
+
+// ProxyConfig routes provider requests through a proxy. The HTTP_PROXY,
+// HTTPS_PROXY and NO_PROXY environment variables apply when no URL is set.
+type ProxyConfig struct {
+	// URL is an http, https, socks5 or socks5h proxy, optionally with credentials
+	URL string `yaml:"url"`
+	// NoProxy lists the hosts, domains and CIDR ranges reached directly
+	NoProxy []string `yaml:"no_proxy"`
+}
+
+// TLSConfig customizes certificate verification of provider requests
+type TLSConfig struct {
+	// CAFile is a PEM bundle of certificate authorities trusted in addition to the system ones
+	CAFile string `yaml:"ca_file"`
+	// CertFile and KeyFile are a client certificate for mutual TLS
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+	// MinVersion is 1.2 or 1.3; empty keeps Go's default
+	MinVersion string `yaml:"min_version"`
+	// InsecureSkipVerify disables certificate verification; only for debugging
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify"`
+}
+
+// FixturesConfig records CoinGecko responses to a directory or replays them
+// for offline development
+type FixturesConfig struct {
+	// Mode is off, record or replay
+	Mode string `yaml:"mode"`
+	Dir  string `yaml:"dir"`
+}
+
+// BreakerConfig configures the circuit breaker around CoinGecko requests
+type BreakerConfig struct {
+	// Failures is the number of consecutive failed requests that opens the breaker
+	Failures int `yaml:"failures"`
+	// Cooldown is how long the breaker stays open before a probe request is let through
+	Cooldown time.Duration `yaml:"cooldown"`
+}
+
+// PollerConfig configures which coins are tracked and how often they are refreshed
+type PollerConfig struct {
+	Interval time.Duration `yaml:"interval"`
+	Coins    []string      `yaml:"coins"`
+	Currency string        `yaml:"currency"`
+	// Thresholds are price levels per coin; crossing one publishes a threshold_crossed event
+	Thresholds map[string][]decimal.Decimal `yaml:"thresholds"`
+	// InactiveAfter is how long the provider may go without updating a coin
+	// before it is marked inactive and its watchlists are notified
+	InactiveAfter time.Duration `yaml:"inactive_after"`
+	// WatchdogCycles is how many of their intervals the poller, the event
+	// consumers and the background refreshes may go without progress before
+	// they are restarted; zero disables it
+	WatchdogCycles int `yaml:"watchdog_cycles"`
+}
+
+// CandlesConfig configures how polled prices are aggregated into OHLC candles
+type CandlesConfig struct {
+	Interval time.Duration `yaml:"interval"`
+	// Rollups are the longer intervals candles are aggregated into, kept forever
+	Rollups []time.Duration `yaml:"rollups"`
+	// Retention is how long candles of the base interval are kept; zero keeps them forever
+	Retention time.Duration `yaml:"retention"`
+	// BackfillDays of provider history are loaded for coins without candles; zero disables it
+	BackfillDays int `yaml:"backfill_days"`
+}
+
+// HistoryConfig configures the since-inception series served to charts
+type HistoryConfig struct {
+	// CacheBytes bounds the memory of the cached daily series, evicting the
+	// coins viewed least recently first; zero keeps every series
+	CacheBytes int64 `yaml:"cache_bytes"`
+}
+
+// IconsConfig configures the coin logos served from /assets/icons
+type IconsConfig struct {
+	// CacheDir keeps downloaded logos across restarts; they are kept in memory when empty
+	CacheDir string `yaml:"cache_dir"`
+}
+
+// CompareSources are the price sources /api/v1/compare can query
+var CompareSources = []string{"coingecko", "binance", "kraken"}
+
+// CompareConfig configures the cross-source price comparison. It is disabled when no sources are set.
+type CompareConfig struct {
+	Sources []string `yaml:"sources"`
+	// Timeout is how long each source has to answer
+	Timeout time.Duration `yaml:"timeout"`
+	Shadow  ShadowConfig  `yaml:"shadow"`
+}
+
+// ShadowSources are the exchanges polled prices can be mirrored to
+var ShadowSources = []string{"binance", "kraken"}
+
+// ShadowConfig mirrors a sample of the polled prices to a secondary source and
+// reports the discrepancies. It is disabled when no source is set.
+type ShadowConfig struct {
+	Source string `yaml:"source"`
+	// Sample is the fraction of polled prices mirrored, from 0 to 1
+	Sample float64 `yaml:"sample"`
+	// TolerancePct is the deviation, in percent, above which prices are reported
+	TolerancePct float64 `yaml:"tolerance_pct"`
+}
+
+// ETFConfig configures the spot ETF flow tracker. It is enabled when a source is set.
+type ETFConfig struct {
+	// Sources maps BTC and ETH to the URL of a CSV table of daily flows
+	Sources map[string]string `yaml:"sources"`
+	// Scale multiplies the table values to get USD
+	Scale    float64       `yaml:"scale"`
+	Interval time.Duration `yaml:"interval"`
+}
+
+// Enabled reports whether any ETF flow source is configured
+func (c ETFConfig) Enabled() bool {
+	for _, source := range c.Sources {
+		if source != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// UniverseConfig configures the named universes watchlists can follow
+type UniverseConfig struct {
+	// Interval is how often ranked universes are re-resolved
+	Interval time.Duration        `yaml:"interval"`
+	Lists    []UniverseListConfig `yaml:"lists"`
+}
+
+// UniverseListConfig defines a universe by market cap, volume, category or a custom list
+type UniverseListConfig struct {
+	Name     string   `yaml:"name"`
+	Kind     string   `yaml:"kind"`
+	Size     int      `yaml:"size"`
+	Category string   `yaml:"category"`
+	Coins    []string `yaml:"coins"`
+}
+
+// Universes returns the configured universe definitions
+func (c UniverseConfig) Universes() []models.Universe {
+	universes := make([]models.Universe, len(c.Lists))
+	for i, l := range c.Lists {
+		universes[i] = models.Universe{
+			Name:     l.Name,
+			Kind:     models.UniverseKind(strings.ToLower(l.Kind)),
+			Size:     l.Size,
+			Category: l.Category,
+			Coins:    slices.Clone(l.Coins),
+		}
+	}
+	return universes
+}
+
+// ServerConfig configures the HTTP server
+type ServerConfig struct {
+	Port int `yaml:"port"`
+	// GRPCPort serves the gRPC API when set; zero disables it
+	GRPCPort int `yaml:"grpc_port"`
+	// Auth protects the API when tokens are configured
+	Auth AuthConfig `yaml:"auth"`
+	// WebSocket configures /api/v1/socket
+	WebSocket WebSocketConfig `yaml:"websocket"`
+	// Degradation is the worst data tier (live, cached or stale) each
+	// endpoint may serve, by endpoint; worse responses fail with 503.
+	// Endpoints without an entry serve whatever data they have.
+	Degradation map[string]string `yaml:"degradation"`
+}
+
+// DegradationEndpoints are the endpoints server.degradation can set a tier for
+var DegradationEndpoints = []string{"prices", "global", "history", "coin_info"}
+
+// degradationTiers are the tiers server.degradation accepts, best first
+var degradationTiers = []string{"live", "cached", "stale"}
+
+// cachedEndpoints always serve from a cache refreshed in the background, so
+// they are never live
+var cachedEndpoints = []string{"global", "coin_info"}
+
+// WebSocketConfig configures WebSocket connections. Compression uses
+// permessage-deflate with clients that offer it.
+type WebSocketConfig struct {
+	Compression bool `yaml:"compression"`
+	// CompressionThreshold is the smallest message, in bytes, that is compressed
+	CompressionThreshold int `yaml:"compression_threshold"`
+	// CompressionLevel is the flate level, from 1 (fastest) to 9 (smallest)
+	CompressionLevel int `yaml:"compression_level"`
+	// MaxMessageSize limits client messages after decompression, in bytes
+	MaxMessageSize int64 `yaml:"max_message_size"`
+}
+
+// AuthConfig configures API tokens and web UI sessions. The API is open when
+// no tokens are configured.
+type AuthConfig struct {
+	Tokens []TokenConfig `yaml:"tokens"`
+	// PublicRead leaves GET requests open to unauthenticated clients
+	PublicRead bool `yaml:"public_read"`
+	// SessionSecret signs web UI session cookies; a random secret is used when
+	// it is empty, so sessions end on restart
+	SessionSecret string        `yaml:"session_secret"`
+	SessionTTL    time.Duration `yaml:"session_ttl"`
+	// RateLimit is the default number of requests per minute for each token
+	RateLimit int `yaml:"rate_limit"`
+}
+
+// TokenConfig is a static API token
+type TokenConfig struct {
+	Name     string `yaml:"name"`
+	Token    string `yaml:"token"`
+	ReadOnly bool   `yaml:"read_only"`
+	// RateLimit overrides auth.rate_limit for this token
+	RateLimit int `yaml:"rate_limit"`
+}
+
+// Enabled reports whether the API requires a token
+func (c AuthConfig) Enabled() bool {
+	return len(c.Tokens) > 0
+}
+
+// minTokenLength is the shortest API token accepted
+const minTokenLength = 16
+
+// DatabaseConfig configures the storage backend
+type DatabaseConfig struct {
+	DSN string `yaml:"dsn"`
+	// EncryptionKeys are the master keys that encrypt the amounts of the
+	// transaction ledger, as "id:base64-key" with 32-byte keys. The first one
+	// encrypts; the others still decrypt ledgers written before a rotation.
+	// The ledger is stored in plain text when empty.
+	EncryptionKeys []string `yaml:"encryption_keys"`
+}
+
+// LogConfig configures structured logging
+type LogConfig struct {
+	// Level is one of debug, info, warn or error
+	Level string `yaml:"level"`
+	// Format is either text or json
+	Format string `yaml:"format"`
+}
+
+// SheetsConfig configures the optional Google Sheets sink. It is enabled when a spreadsheet ID is set.
+type SheetsConfig struct {
+	SpreadsheetID string `yaml:"spreadsheet_id"`
+	// CredentialsFile is the path of a service account JSON key with access to the spreadsheet
+	CredentialsFile string        `yaml:"credentials_file"`
+	Sheet           string        `yaml:"sheet"`
+	Interval        time.Duration `yaml:"interval"`
+	// Holdings switches the sink from price snapshots to portfolio valuations
+	Holdings map[string]float64 `yaml:"holdings"`
+	// AlertsSheet additionally receives every triggered alert when set
+	AlertsSheet string `yaml:"alerts_sheet"`
+}
+
+// Enabled reports whether the sink should run
+func (c SheetsConfig) Enabled() bool {
+	return c.SpreadsheetID != ""
+}
+
+// NotifyConfig configures the optional push notification channels alerts are delivered to
+type NotifyConfig struct {
+	Ntfy     NtfyConfig     `yaml:"ntfy"`
+	Gotify   GotifyConfig   `yaml:"gotify"`
+	Matrix   MatrixConfig   `yaml:"matrix"`
+	WhatsApp WhatsAppConfig `yaml:"whatsapp"`
+	// DigestAt is the UTC time of day (HH:MM) of the daily digest; empty disables it
+	DigestAt string `yaml:"digest_at"`
+}
+
+// DigestTime returns the offset from midnight UTC the daily digest is sent at
+func (c NotifyConfig) DigestTime() (time.Duration, bool) {
+	at, err := time.Parse("15:04", c.DigestAt)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute, true
+}
+
+// NtfyConfig configures ntfy notifications. They are enabled when a topic is set.
+type NtfyConfig struct {
+	Server string `yaml:"server"`
+	Topic  string `yaml:"topic"`
+	// Token is an access token for protected topics
+	Token string `yaml:"token"`
+}
+
+// Enabled reports whether alerts are published to ntfy
+func (c NtfyConfig) Enabled() bool {
+	return c.Topic != ""
+}
+
+// GotifyConfig configures Gotify notifications. They are enabled when a server is set.
+type GotifyConfig struct {
+	Server string `yaml:"server"`
+	// Token is the token of the Gotify application messages are sent as
+	Token string `yaml:"token"`
+}
+
+// Enabled reports whether alerts are published to Gotify
+func (c GotifyConfig) Enabled() bool {
+	return c.Server != ""
+}
+
+// MatrixConfig configures Matrix notifications. They are enabled when a homeserver is set.
+type MatrixConfig struct {
+	Homeserver string `yaml:"homeserver"`
+	// AccessToken belongs to the account messages are sent as
+	AccessToken string `yaml:"access_token"`
+	RoomID      string `yaml:"room_id"`
+}
+
+// Enabled reports whether alerts are posted to Matrix
+func (c MatrixConfig) Enabled() bool {
+	return c.Homeserver != ""
+}
+
+// WhatsAppConfig configures WhatsApp Cloud API notifications, sent to the
+// recipients who opted in. They are enabled when a phone number ID is set.
+type WhatsAppConfig struct {
+	PhoneNumberID string `yaml:"phone_number_id"`
+	Token         string `yaml:"token"`
+	// Template is the approved message template alerts are sent with
+	Template string `yaml:"template"`
+	// CodeTemplate is the approved authentication template that sends
+	// recipients the code confirming their opt-in
+	CodeTemplate string `yaml:"code_template"`
+	// Language is the template language for recipients without their own
+	Language string `yaml:"language"`
+}
+
+// Enabled reports whether alerts are sent over WhatsApp
+func (c WhatsAppConfig) Enabled() bool {
+	return c.PhoneNumberID != ""
+}
+
+// TelemetryConfig configures the anonymous feature usage reports, which are
+// only sent when enabled. DO_NOT_TRACK=1 in the environment turns them off
+// whatever the file says.
+type TelemetryConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Endpoint receives each report as a JSON POST
+	Endpoint string        `yaml:"endpoint"`
+	Interval time.Duration `yaml:"interval"`
+}
+
+// PortfolioConfig configures how the ledger holdings are weighed in the price table
+type PortfolioConfig struct {
+	// Targets maps coin IDs to target allocation weights, normalized to sum to one
+	Targets map[string]float64 `yaml:"targets"`
+	// DriftBand is how many percentage points a coin's share may move away
+	// from its target before its row is flagged
+	DriftBand float64 `yaml:"drift_band"`
+}
+
+// Default returns the configuration used when nothing else is specified
+func Default() Config {
+	return Config{
+		API: APIConfig{
+			BaseURL:     "https://api.coingecko.com/api/v3",
+			Timeout:     10 * time.Second,
+			Concurrency: 5,
+			BatchSize:   100,
+			Breaker: BreakerConfig{
+				Failures: 5,
+				Cooldown: 30 * time.Second,
+			},
+			Budget: BudgetConfig{
+				Reserve: 0.2,
+			},
+			Fixtures: FixturesConfig{
+				Mode: "off",
+				Dir:  "testdata/coingecko",
+			},
+			DNS: DNSConfig{
+				CacheTTL: time.Minute,
+				MaxStale: time.Hour,
+			},
+		},
+		Poller: PollerConfig{
+			Interval:       time.Minute,
+			Coins:          []string{"bitcoin", "ethereum"},
+			Currency:       string(models.DefaultCurrency),
+			InactiveAfter:  24 * time.Hour,
+			WatchdogCycles: 3,
+		},
+		Candles: CandlesConfig{
+			Interval:     time.Hour,
+			Rollups:      []time.Duration{24 * time.Hour},
+			Retention:    30 * 24 * time.Hour,
+			BackfillDays: 30,
+		},
+		History: HistoryConfig{
+			CacheBytes: 32 << 20,
+		},
+		Compare: CompareConfig{
+			Sources: slices.Clone(CompareSources),
+			Timeout: 5 * time.Second,
+			Shadow: ShadowConfig{
+				Sample:       0.1,
+				TolerancePct: 1,
+			},
+		},
+		ETF: ETFConfig{
+			Scale:    1e6,
+			Interval: 6 * time.Hour,
+		},
+		// No universe by default: each ranked one polls /coins/markets every interval
+		Universe: UniverseConfig{
+			Interval: 15 * time.Minute,
+		},
+		Server: ServerConfig{
+			Port: 8080,
+			Auth: AuthConfig{
+				SessionTTL: 24 * time.Hour,
+				RateLimit:  120,
+			},
+			WebSocket: WebSocketConfig{
+				Compression:          true,
+				CompressionThreshold: 256,
+				CompressionLevel:     1,
+				MaxMessageSize:       64 << 10,
+			},
+		},
+		Database: DatabaseConfig{
+			DSN: "memory://",
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "text",
+		},
+		Sheets: SheetsConfig{
+			Sheet:    "Prices",
+			Interval: time.Hour,
+		},
+		Notify: NotifyConfig{
+			Ntfy:     NtfyConfig{Server: "https://ntfy.sh"},
+			WhatsApp: WhatsAppConfig{Language: "pt_BR"},
+		},
+		Telemetry: TelemetryConfig{
+			Interval: 24 * time.Hour,
+		},
+		Portfolio: PortfolioConfig{
+			DriftBand: 5,
+		},
+	}
+}
+
+// Load builds the configuration from defaults, the optional YAML file at path and
+// DASHBOARD_* environment variables, in that order of precedence, and validates the result
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return &cfg, nil
+}
+
+// applyEnv overrides fields with the environment variables that are set
+func (c *Config) applyEnv() error {
+	if v, ok := lookupEnv("API_BASE_URL"); ok {
+		c.API.BaseURL = v
+	}
+	if v, ok := lookupEnv("API_KEY"); ok {
+		c.API.APIKey = v
+	}
+	if v, ok := lookupEnv("FIXTURES"); ok {
+		c.API.Fixtures.Mode = v
+	}
+	if v, ok := lookupEnv("API_PROXY"); ok {
+		c.API.Proxy.URL = v
+	}
+	if v, ok := lookupEnv("API_CA_FILE"); ok {
+		c.API.TLS.CAFile = v
+	}
+	if v, ok := lookupEnv("API_DNS_FALLBACK"); ok {
+		c.API.DNS.Fallback = splitList(v)
+	}
+	if v, ok := lookupEnv("API_TIMEOUT"); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid %sAPI_TIMEOUT: %w", EnvPrefix, err)
+		}
+		c.API.Timeout = d
+	}
+	if v, ok := lookupEnv("POLL_INTERVAL"); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid %sPOLL_INTERVAL: %w", EnvPrefix, err)
+		}
+		c.Poller.Interval = d
+	}
+	if v, ok := lookupEnv("COINS"); ok {
+		c.Poller.Coins = splitList(v)
+	}
+	if v, ok := lookupEnv("CURRENCY"); ok {
+		c.Poller.Currency = v
+	}
+	if v, ok := lookupEnv("CANDLE_INTERVAL"); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid %sCANDLE_INTERVAL: %w", EnvPrefix, err)
+		}
+		c.Candles.Interval = d
+	}
+	if v, ok := lookupEnv("COMPARE_SOURCES"); ok {
+		c.Compare.Sources = splitList(v)
+	}
+	if v, ok := lookupEnv("HTTP_PORT"); ok {
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid %sHTTP_PORT: %w", EnvPrefix, err)
+		}
+		c.Server.Port = port
+	}
+	if v, ok := lookupEnv("GRPC_PORT"); ok {
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid %sGRPC_PORT: %w", EnvPrefix, err)
+		}
+		c.Server.GRPCPort = port
+	}
+	if v, ok := lookupEnv("SESSION_SECRET"); ok {
+		c.Server.Auth.SessionSecret = v
+	}
+	if v, ok := lookupEnv("DB_DSN"); ok {
+		c.Database.DSN = v
+	}
+	if v, ok := lookupEnv("DB_ENCRYPTION_KEYS"); ok {
+		c.Database.EncryptionKeys = splitList(v)
+	}
+	if v, ok := lookupEnv("LOG_LEVEL"); ok {
+		c.Log.Level = v
+	}
+	if v, ok := lookupEnv("LOG_FORMAT"); ok {
+		c.Log.Format = v
+	}
+	if v, ok := lookupEnv("SHEETS_SPREADSHEET_ID"); ok {
+		c.Sheets.SpreadsheetID = v
+	}
+	if v, ok := lookupEnv("SHEETS_CREDENTIALS_FILE"); ok {
+		c.Sheets.CredentialsFile = v
+	}
+	if v, ok := lookupEnv("NTFY_TOKEN"); ok {
+		c.Notify.Ntfy.Token = v
+	}
+	if v, ok := lookupEnv("GOTIFY_TOKEN"); ok {
+		c.Notify.Gotify.Token = v
+	}
+	if v, ok := lookupEnv("MATRIX_ACCESS_TOKEN"); ok {
+		c.Notify.Matrix.AccessToken = v
+	}
+	if v, ok := lookupEnv("WHATSAPP_TOKEN"); ok {
+		c.Notify.WhatsApp.Token = v
+	}
+	// DO_NOT_TRACK is the cross-tool convention to opt out of telemetry
+	if v := os.Getenv("DO_NOT_TRACK"); v != "" && v != "0" {
+		c.Telemetry.Enabled = false
+	}
+	return nil
+}
+
+// Validate checks that the configuration can be used to start the dashboard
+func (c *Config) Validate() error {
+	var errs []error
+
+	if !absoluteURL(c.API.BaseURL) {
+		errs = append(errs, fmt.Errorf("api.base_url must be an absolute URL, got %q", c.API.BaseURL))
+	}
+	if c.API.Timeout <= 0 {
+		errs = append(errs, errors.New("api.timeout must be positive"))
+	}
+	if c.API.Concurrency <= 0 {
+		errs = append(errs, errors.New("api.concurrency must be positive"))
+	}
+	if c.API.BatchSize <= 0 {
+		errs = append(errs, errors.New("api.batch_size must be positive"))
+	}
+	if c.API.Breaker.Failures <= 0 || c.API.Breaker.Cooldown <= 0 {
+		errs = append(errs, errors.New("api.breaker.failures and api.breaker.cooldown must be positive"))
+	}
+	switch strings.ToLower(c.API.Fixtures.Mode) {
+	case "", "off":
+	case "record", "replay":
+		if c.API.Fixtures.Dir == "" {
+			errs = append(errs, errors.New("api.fixtures.dir cannot be empty when fixtures are enabled"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("api.fixtures.mode must be off, record or replay, got %q", c.API.Fixtures.Mode))
+	}
+	if c.API.Proxy.URL != "" {
+		if u, err := url.Parse(c.API.Proxy.URL); err != nil || !slices.Contains(proxySchemes, u.Scheme) || u.Host == "" {
+			errs = append(errs, fmt.Errorf("api.proxy.url must be an http, https, socks5 or socks5h URL, got %q", redactURL(c.API.Proxy.URL)))
+		}
+	}
+	if (c.API.TLS.CertFile == "") != (c.API.TLS.KeyFile == "") {
+		errs = append(errs, errors.New("api.tls.cert_file and api.tls.key_file must be set together"))
+	}
+	if v := c.API.TLS.MinVersion; v != "" && v != "1.2" && v != "1.3" {
+		errs = append(errs, fmt.Errorf("api.tls.min_version must be 1.2 or 1.3, got %q", v))
+	}
+	if c.API.DNS.CacheTTL < 0 || c.API.DNS.MaxStale < 0 {
+		errs = append(errs, errors.New("api.dns.cache_ttl and api.dns.max_stale cannot be negative"))
+	}
+	for _, server := range c.API.DNS.Fallback {
+		if !dnsServer(server) {
+			errs = append(errs, fmt.Errorf("api.dns.fallback must be IP addresses with an optional port, got %q", server))
+		}
+	}
+	if c.API.Budget.DailyCalls < 0 {
+		errs = append(errs, errors.New("api.budget.daily_calls cannot be negative"))
+	}
+	if c.API.Budget.Reserve < 0 || c.API.Budget.Reserve >= 1 {
+		errs = append(errs, fmt.Errorf("api.budget.reserve must be at least 0 and below 1, got %v", c.API.Budget.Reserve))
+	}
+	if c.Poller.Interval < time.Second {
+		errs = append(errs, errors.New("poller.interval must be at least 1s"))
+	}
+	if len(c.Poller.Coins) == 0 {
+		errs = append(errs, errors.New("poller.coins cannot be empty"))
+	}
+	if c.Poller.InactiveAfter < c.Poller.Interval {
+		errs = append(errs, errors.New("poller.inactive_after cannot be shorter than poller.interval"))
+	}
+	if c.Poller.WatchdogCycles < 0 {
+		errs = append(errs, errors.New("poller.watchdog_cycles cannot be negative"))
+	}
+	if _, err := models.ParseCurrency(c.Poller.Currency); err != nil {
+		errs = append(errs, fmt.Errorf("poller.currency: %w", err))
+	}
+	for id, levels := range c.Poller.Thresholds {
+		for _, level := range levels {
+			if !level.IsPositive() {
+				errs = append(errs, fmt.Errorf("poller.thresholds.%s must be positive, got %s", id, level))
+			}
+		}
+	}
+	if c.Candles.Interval < c.Poller.Interval {
+		errs = append(errs, errors.New("candles.interval cannot be shorter than poller.interval"))
+	}
+	var longest time.Duration
+	for _, rollup := range c.Candles.Rollups {
+		if c.Candles.Interval <= 0 || rollup <= c.Candles.Interval || rollup%c.Candles.Interval != 0 {
+			errs = append(errs, fmt.Errorf("candles.rollups must be multiples of candles.interval, got %s", rollup))
+		}
+		longest = max(longest, rollup)
+	}
+	for _, rollup := range c.Candles.Rollups {
+		if rollup > 0 && longest%rollup != 0 {
+			errs = append(errs, fmt.Errorf("candles.rollups must divide the longest rollup %s, got %s", longest, rollup))
+		}
+	}
+	if c.Candles.Retention < 0 || (c.Candles.Retention > 0 && c.Candles.Retention < longest) {
+		errs = append(errs, fmt.Errorf("candles.retention must be zero or at least the longest rollup, got %s", c.Candles.Retention))
+	}
+	if c.Candles.BackfillDays < 0 || c.Candles.BackfillDays > 365 {
+		errs = append(errs, fmt.Errorf("candles.backfill_days must be between 0 and 365, got %d", c.Candles.BackfillDays))
+	}
+	if c.History.CacheBytes < 0 {
+		errs = append(errs, errors.New("history.cache_bytes cannot be negative"))
+	}
+	for _, source := range c.Compare.Sources {
+		if !slices.Contains(CompareSources, source) {
+			errs = append(errs, fmt.Errorf("compare.sources must be among %s, got %q", strings.Join(CompareSources, ", "), source))
+		}
+	}
+	if (len(c.Compare.Sources) > 0 || c.Compare.Shadow.Source != "") && c.Compare.Timeout <= 0 {
+		errs = append(errs, errors.New("compare.timeout must be positive"))
+	}
+	if shadow := c.Compare.Shadow; shadow.Source != "" {
+		if !slices.Contains(ShadowSources, shadow.Source) {
+			errs = append(errs, fmt.Errorf("compare.shadow.source must be among %s, got %q", strings.Join(ShadowSources, ", "), shadow.Source))
+		}
+		if shadow.Sample <= 0 || shadow.Sample > 1 {
+			errs = append(errs, fmt.Errorf("compare.shadow.sample must be above 0 and at most 1, got %v", shadow.Sample))
+		}
+		if shadow.TolerancePct < 0 {
+			errs = append(errs, errors.New("compare.shadow.tolerance_pct cannot be negative"))
+		}
+	}
+	for asset, source := range c.ETF.Sources {
+		if !slices.Contains(models.ETFAssets, strings.ToUpper(asset)) {
+			errs = append(errs, fmt.Errorf("etf.sources must be keyed by %s, got %q", strings.Join(models.ETFAssets, " or "), asset))
+		}
+		if source != "" && !absoluteURL(source) {
+			errs = append(errs, fmt.Errorf("etf.sources.%s must be an absolute URL, got %q", asset, source))
+		}
+	}
+	if c.ETF.Scale <= 0 {
+		errs = append(errs, errors.New("etf.scale must be positive"))
+	}
+	if c.ETF.Interval < time.Minute {
+		errs = append(errs, errors.New("etf.interval must be at least 1m"))
+	}
+	if c.Universe.Interval < time.Minute {
+		errs = append(errs, errors.New("universe.interval must be at least 1m"))
+	}
+	names := make(map[string]bool, len(c.Universe.Lists))
+	for _, u := range c.Universe.Universes() {
+		u.Normalize()
+		if err := u.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("universe.lists: %w", err))
+		}
+		if names[u.Name] {
+			errs = append(errs, fmt.Errorf("universe.lists defines %s twice", u.Name))
+		}
+		names[u.Name] = true
+	}
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server.port must be between 1 and 65535, got %d", c.Server.Port))
+	}
+	if c.Server.GRPCPort < 0 || c.Server.GRPCPort > 65535 || c.Server.GRPCPort == c.Server.Port {
+		errs = append(errs, fmt.Errorf("server.grpc_port must be between 1 and 65535 and differ from server.port, got %d", c.Server.GRPCPort))
+	}
+	errs = append(errs, c.Server.Auth.validate()...)
+	if ws := c.Server.WebSocket; ws.Compression && (ws.CompressionLevel < 1 || ws.CompressionLevel > 9) {
+		errs = append(errs, fmt.Errorf("server.websocket.compression_level must be between 1 and 9, got %d", ws.CompressionLevel))
+	}
+	if c.Server.WebSocket.CompressionThreshold < 0 {
+		errs = append(errs, errors.New("server.websocket.compression_threshold cannot be negative"))
+	}
+	if c.Server.WebSocket.MaxMessageSize < 1024 {
+		errs = append(errs, errors.New("server.websocket.max_message_size must be at least 1024"))
+	}
+	for endpoint, tier := range c.Server.Degradation {
+		if !slices.Contains(DegradationEndpoints, endpoint) {
+			errs = append(errs, fmt.Errorf("server.degradation endpoints must be among %s, got %q", strings.Join(DegradationEndpoints, ", "), endpoint))
+		}
+		if !slices.Contains(degradationTiers, tier) {
+			errs = append(errs, fmt.Errorf("server.degradation.%s must be live, cached or stale, got %q", endpoint, tier))
+		}
+		if tier == "live" && slices.Contains(cachedEndpoints, endpoint) {
+			errs = append(errs, fmt.Errorf("server.degradation.%s must be cached or stale since the endpoint is served from a cache", endpoint))
+		}
+	}
+	if c.Database.DSN == "" {
+		errs = append(errs, errors.New("database.dsn cannot be empty"))
+	}
+	keyIDs := make(map[string]bool)
+	for _, key := range c.Database.EncryptionKeys {
+		id, encoded, _ := strings.Cut(key, ":")
+		secret, err := base64.StdEncoding.DecodeString(encoded)
+		switch {
+		case id == "" || err != nil || len(secret) != 32:
+			errs = append(errs, errors.New("database.encryption_keys must be written as id:base64-key with 32-byte keys"))
+		case keyIDs[id]:
+			errs = append(errs, fmt.Errorf("database.encryption_keys has a duplicate key %q", id))
+		}
+		keyIDs[id] = true
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		errs = append(errs, fmt.Errorf("log.level must be debug, info, warn or error, got %q", c.Log.Level))
+	}
+	if c.Log.Format != "text" && c.Log.Format != "json" {
+		errs = append(errs, fmt.Errorf("log.format must be text or json, got %q", c.Log.Format))
+	}
+	if c.Sheets.Enabled() {
+		if c.Sheets.CredentialsFile == "" {
+			errs = append(errs, errors.New("sheets.credentials_file is required when sheets.spreadsheet_id is set"))
+		}
+		if c.Sheets.Sheet == "" {
+			errs = append(errs, errors.New("sheets.sheet cannot be empty"))
+		}
+		if c.Sheets.Interval < time.Minute {
+			errs = append(errs, errors.New("sheets.interval must be at least 1m"))
+		}
+	}
+	if c.Telemetry.Enabled {
+		if !absoluteURL(c.Telemetry.Endpoint) {
+			errs = append(errs, fmt.Errorf("telemetry.endpoint must be an absolute URL when telemetry is enabled, got %q", c.Telemetry.Endpoint))
+		}
+		if c.Telemetry.Interval < time.Hour {
+			errs = append(errs, errors.New("telemetry.interval must be at least 1h"))
+		}
+	}
+	for id, weight := range c.Portfolio.Targets {
+		if weight <= 0 {
+			errs = append(errs, fmt.Errorf("portfolio.targets.%s must be positive", id))
+		}
+	}
+	if c.Portfolio.DriftBand <= 0 {
+		errs = append(errs, errors.New("portfolio.drift_band must be positive"))
+	}
+	if c.Notify.Ntfy.Enabled() && !absoluteURL(c.Notify.Ntfy.Server) {
+		errs = append(errs, fmt.Errorf("notify.ntfy.server must be an absolute URL, got %q", c.Notify.Ntfy.Server))
+	}
+	if c.Notify.Gotify.Enabled() {
+		if !absoluteURL(c.Notify.Gotify.Server) {
+			errs = append(errs, fmt.Errorf("notify.gotify.server must be an absolute URL, got %q", c.Notify.Gotify.Server))
+		}
+		if c.Notify.Gotify.Token == "" {
+			errs = append(errs, errors.New("notify.gotify.token is required when notify.gotify.server is set"))
+		}
+	}
+	if c.Notify.Matrix.Enabled() {
+		if !absoluteURL(c.Notify.Matrix.Homeserver) {
+			errs = append(errs, fmt.Errorf("notify.matrix.homeserver must be an absolute URL, got %q", c.Notify.Matrix.Homeserver))
+		}
+		if c.Notify.Matrix.AccessToken == "" || c.Notify.Matrix.RoomID == "" {
+			errs = append(errs, errors.New("notify.matrix.access_token and room_id are required when notify.matrix.homeserver is set"))
+		}
+	}
+	if w := c.Notify.WhatsApp; w.Enabled() && (w.Token == "" || w.Template == "" || w.CodeTemplate == "" || w.Language == "") {
+		errs = append(errs, errors.New("notify.whatsapp.token, template, code_template and language are required when notify.whatsapp.phone_number_id is set"))
+	}
+	if _, ok := c.Notify.DigestTime(); c.Notify.DigestAt != "" && !ok {
+		errs = append(errs, fmt.Errorf("notify.digest_at must be a time of day like 08:00, got %q", c.Notify.DigestAt))
+	}
+
+	return errors.Join(errs...)
+}
+
+// proxySchemes are the supported api.proxy.url schemes
+var proxySchemes = []string{"http", "https", "socks5", "socks5h"}
+
+// dnsServer reports whether s is an IP address with an optional port
+func dnsServer(s string) bool {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	return net.ParseIP(s) != nil
+}
+
+// redactURL hides the password of a URL so it can be shown in errors
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return s
+	}
+	return u.Redacted()
+}
+
+// absoluteURL reports whether s is a URL with a scheme and a host
+func absoluteURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+// Currency returns the parsed poller currency
+func (c *Config) Currency() models.Currency {
+	currency, err := models.ParseCurrency(c.Poller.Currency)
+	if err != nil {
+		return models.DefaultCurrency
+	}
+	return currency
+}
+
+// Logger builds the structured logger described by the log section, writing to w
+func (c *Config) Logger(w io.Writer) *slog.Logger {
+	var level slog.Level
+	level.UnmarshalText([]byte(c.Log.Level))
+
+	opts := &slog.HandlerOptions{Level: level}
+	if c.Log.Format == "json" {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+func lookupEnv(name string) (string, bool) {
+	return os.LookupEnv(EnvPrefix + name)
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (a AuthConfig) validate() []error {
+	var errs []error
+	names := make(map[string]bool, len(a.Tokens))
+	for i, t := range a.Tokens {
+		if t.Name == "" || names[t.Name] {
+			errs = append(errs, fmt.Errorf("server.auth.tokens[%d].name must be set and unique, got %q", i, t.Name))
+		}
+		names[t.Name] = true
+		if len(t.Token) < minTokenLength {
+			errs = append(errs, fmt.Errorf("server.auth.tokens[%d].token must be at least %d characters", i, minTokenLength))
+		}
+		if t.RateLimit < 0 {
+			errs = append(errs, fmt.Errorf("server.auth.tokens[%d].rate_limit cannot be negative", i))
+		}
+	}
+	if a.SessionTTL <= 0 {
+		errs = append(errs, errors.New("server.auth.session_ttl must be positive"))
+	}
+	if a.RateLimit <= 0 {
+		errs = append(errs, errors.New("server.auth.rate_limit must be positive"))
+	}
+	return errs
+}
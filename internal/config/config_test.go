@@ -0,0 +1,201 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Expected defaults to be valid, got %v", err)
+	}
+	if cfg.Server.Port != 8080 {
+		t.Errorf("Expected default port 8080, got %d", cfg.Server.Port)
+	}
+	if cfg.API.Timeout != 10*time.Second {
+		t.Errorf("Expected default timeout of 10s, got %v", cfg.API.Timeout)
+	}
+	if cfg.API.Breaker.Failures != 5 || cfg.API.Breaker.Cooldown != 30*time.Second {
+		t.Errorf("Expected the default breaker, got %+v", cfg.API.Breaker)
+	}
+	if len(cfg.Universe.Lists) != 0 {
+		t.Errorf("Expected no universe polling CoinGecko by default, got %+v", cfg.Universe.Lists)
+	}
+}
+
+func TestLoad_FileAndEnvOverrides(t *testing.T) {
+	path := writeConfig(t, `
+api:
+  api_key: from-file
+  timeout: 5s
+poller:
+  interval: 30s
+  coins: [bitcoin, solana]
+  currency: eur
+  thresholds:
+    bitcoin: [70000, 0.000012345678901234]
+server:
+  port: 9000
+`)
+	t.Setenv("DASHBOARD_HTTP_PORT", "9100")
+	t.Setenv("DASHBOARD_COINS", "cardano, polkadot")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.API.APIKey != "from-file" {
+		t.Errorf("Expected API key from file, got %q", cfg.API.APIKey)
+	}
+	if cfg.Poller.Interval != 30*time.Second {
+		t.Errorf("Expected interval of 30s, got %v", cfg.Poller.Interval)
+	}
+	if cfg.Server.Port != 9100 {
+		t.Errorf("Expected env port 9100 to win, got %d", cfg.Server.Port)
+	}
+	if len(cfg.Poller.Coins) != 2 || cfg.Poller.Coins[1] != "polkadot" {
+		t.Errorf("Expected coins from env, got %v", cfg.Poller.Coins)
+	}
+	if cfg.Currency() != "eur" {
+		t.Errorf("Expected currency eur, got %s", cfg.Currency())
+	}
+	if levels := cfg.Poller.Thresholds["bitcoin"]; len(levels) != 2 || levels[1].String() != "0.000012345678901234" {
+		t.Errorf("Expected exact bitcoin thresholds, got %v", levels)
+	}
+}
+
+func TestLoad_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "relative base URL", content: "api:\n  base_url: /v3\n"},
+		{name: "unknown fixture mode", content: "api:\n  fixtures:\n    mode: mock\n"},
+		{name: "zero batch size", content: "api:\n  batch_size: 0\n"},
+		{name: "unsupported proxy scheme", content: "api:\n  proxy:\n    url: ftp://proxy.example.com:21\n"},
+		{name: "client key without certificate", content: "api:\n  tls:\n    key_file: client.key\n"},
+		{name: "old tls version", content: "api:\n  tls:\n    min_version: \"1.0\"\n"},
+		{name: "fallback resolver by name", content: "api:\n  dns:\n    fallback: [dns.google]\n"},
+		{name: "negative dns ttl", content: "api:\n  dns:\n    cache_ttl: -1m\n"},
+		{name: "breaker without cooldown", content: "api:\n  breaker:\n    cooldown: 0s\n"},
+		{name: "grpc port same as http", content: "server:\n  port: 9000\n  grpc_port: 9000\n"},
+		{name: "gotify without token", content: "notify:\n  gotify:\n    server: https://push.example.com\n"},
+		{name: "matrix without room", content: "notify:\n  matrix:\n    homeserver: https://matrix.org\n    access_token: abc\n"},
+		{name: "whatsapp without template", content: "notify:\n  whatsapp:\n    phone_number_id: \"1098765\"\n    token: abc\n"},
+		{name: "whatsapp without code template", content: "notify:\n  whatsapp:\n    phone_number_id: \"1098765\"\n    token: abc\n    template: price_alert\n"},
+		{name: "invalid digest time", content: "notify:\n  digest_at: 8am\n"},
+		{name: "ntfy relative server", content: "notify:\n  ntfy:\n    server: ntfy.sh\n    topic: crypto\n"},
+		{name: "port out of range", content: "server:\n  port: 70000\n"},
+		{name: "short auth token", content: "server:\n  auth:\n    tokens:\n      - {name: phone, token: abc}\n"},
+		{name: "duplicate token names", content: "server:\n  auth:\n    tokens:\n      - {name: a, token: 0123456789abcdef}\n      - {name: a, token: fedcba9876543210}\n"},
+		{name: "websocket compression level", content: "server:\n  websocket:\n    compression_level: 12\n"},
+		{name: "budget reserve", content: "api:\n  budget:\n    reserve: 1\n"},
+		{name: "negative history cache", content: "history:\n  cache_bytes: -1\n"},
+		{name: "websocket message size", content: "server:\n  websocket:\n    max_message_size: 10\n"},
+		{name: "zero rate limit", content: "server:\n  auth:\n    rate_limit: 0\n"},
+		{name: "interval too short", content: "poller:\n  interval: 10ms\n"},
+		{name: "invalid currency", content: "poller:\n  currency: $$$\n"},
+		{name: "inactive before a poll", content: "poller:\n  inactive_after: 30s\n"},
+		{name: "negative watchdog cycles", content: "poller:\n  watchdog_cycles: -1\n"},
+		{name: "zero target weight", content: "portfolio:\n  targets:\n    bitcoin: 0\n"},
+		{name: "zero drift band", content: "portfolio:\n  drift_band: 0\n"},
+		{name: "negative threshold", content: "poller:\n  thresholds:\n    bitcoin: [-1]\n"},
+		{name: "candles shorter than poll", content: "candles:\n  interval: 30s\n"},
+		{name: "rollup not a multiple", content: "candles:\n  interval: 1h\n  rollups: [90m]\n"},
+		{name: "retention shorter than rollup", content: "candles:\n  retention: 12h\n"},
+		{name: "backfill too long", content: "candles:\n  backfill_days: 1000\n"},
+		{name: "unknown compare source", content: "compare:\n  sources: [coinbase]\n"},
+		{name: "compare without timeout", content: "compare:\n  timeout: 0s\n"},
+		{name: "unknown shadow source", content: "compare:\n  shadow:\n    source: coingecko\n"},
+		{name: "shadow sample above one", content: "compare:\n  shadow:\n    source: kraken\n    sample: 1.5\n"},
+		{name: "unknown etf asset", content: "etf:\n  sources:\n    sol: https://example.com/sol.csv\n"},
+		{name: "relative etf source", content: "etf:\n  sources:\n    btc: flows.csv\n"},
+		{name: "etf interval too short", content: "etf:\n  interval: 1s\n"},
+		{name: "universe interval too short", content: "universe:\n  interval: 1s\n"},
+		{name: "unknown universe kind", content: "universe:\n  lists:\n    - {name: gainers, kind: gainers, size: 10}\n"},
+		{name: "category universe without category", content: "universe:\n  lists:\n    - {name: defi, kind: category, size: 10}\n"},
+		{name: "duplicate universe", content: "universe:\n  lists:\n    - {name: majors, kind: custom, coins: [bitcoin]}\n    - {name: Majors, kind: custom, coins: [ethereum]}\n"},
+		{name: "unknown degradation endpoint", content: "server:\n  degradation:\n    candles: live\n"},
+		{name: "unknown degradation tier", content: "server:\n  degradation:\n    prices: fresh\n"},
+		{name: "live cached endpoint", content: "server:\n  degradation:\n    global: live\n"},
+		{name: "short encryption key", content: "database:\n  encryption_keys: [\"k1:c2hvcnQ=\"]\n"},
+		{name: "telemetry without endpoint", content: "telemetry:\n  enabled: true\n"},
+		{name: "unknown log level", content: "log:\n  level: loud\n"},
+		{name: "unknown log format", content: "log:\n  format: xml\n"},
+		{name: "sheets without credentials", content: "sheets:\n  spreadsheet_id: abc\n"},
+		{name: "sheets interval too short", content: "sheets:\n  spreadsheet_id: abc\n  credentials_file: key.json\n  interval: 1s\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(writeConfig(t, tt.content)); err == nil {
+				t.Error("Expected validation error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoad_InvalidEnv(t *testing.T) {
+	t.Setenv("DASHBOARD_POLL_INTERVAL", "soon")
+	if _, err := Load(""); err == nil {
+		t.Error("Expected error for invalid duration, got nil")
+	}
+}
+
+func TestLoad_DoNotTrack(t *testing.T) {
+	path := writeConfig(t, "telemetry:\n  enabled: true\n  endpoint: https://telemetry.example.com/v1\n")
+	t.Setenv("DO_NOT_TRACK", "1")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.Telemetry.Enabled {
+		t.Error("Expected DO_NOT_TRACK to turn telemetry off")
+	}
+}
+
+func TestConfig_Logger(t *testing.T) {
+	t.Setenv("DASHBOARD_LOG_LEVEL", "warn")
+	t.Setenv("DASHBOARD_LOG_FORMAT", "json")
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	logger := cfg.Logger(&buf)
+	logger.Info("hidden")
+	logger.Warn("shown", "coin", "bitcoin")
+
+	out := buf.String()
+	if strings.Contains(out, "hidden") {
+		t.Errorf("Expected info to be filtered at warn level, got %s", out)
+	}
+	if !strings.Contains(out, `"msg":"shown"`) || !strings.Contains(out, `"coin":"bitcoin"`) {
+		t.Errorf("Expected JSON log line, got %s", out)
+	}
+}
+
+func TestNotifyConfig_DigestTime(t *testing.T) {
+	if at, ok := (NotifyConfig{DigestAt: "08:30"}).DigestTime(); !ok || at != 8*time.Hour+30*time.Minute {
+		t.Errorf("Expected 8h30m, got %s (%v)", at, ok)
+	}
+	if _, ok := (NotifyConfig{}).DigestTime(); ok {
+		t.Error("Expected no digest time when unset")
+	}
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AlertKind identifies the condition an alert rule watches for
+type AlertKind string
+
+const (
+	// AlertPriceAbove fires when a candle closes above the threshold
+	AlertPriceAbove AlertKind = "price_above"
+	// AlertPriceBelow fires when a candle closes below the threshold
+	AlertPriceBelow AlertKind = "price_below"
+	// AlertGoldenCross fires when the fast moving average crosses above the slow one
+	AlertGoldenCross AlertKind = "golden_cross"
+	// AlertDeathCross fires when the fast moving average crosses below the slow one
+	AlertDeathCross AlertKind = "death_cross"
+	// AlertRSIBelow fires when the RSI drops below the threshold (oversold)
+	AlertRSIBelow AlertKind = "rsi_below"
+	// AlertRSIAbove fires when the RSI rises above the threshold (overbought)
+	AlertRSIAbove AlertKind = "rsi_above"
+	// AlertCoinInactive is raised without a rule when the provider stops updating a tracked coin
+	AlertCoinInactive AlertKind = "coin_inactive"
+)
+
+// AlertSeverity is how urgently an alert should reach the user. Push
+// notifiers map it to the priority levels of their service.
+type AlertSeverity string
+
+// Alert severities, least urgent first
+const (
+	SeverityInfo     AlertSeverity = "info"
+	SeverityWarning  AlertSeverity = "warning"
+	SeverityCritical AlertSeverity = "critical"
+)
+
+// DefaultSeverity returns the severity of alerts of a kind whose rule sets none:
+// price levels and inactive coins are warnings, indicator signals are informational
+func DefaultSeverity(kind AlertKind) AlertSeverity {
+	if kind == AlertPriceAbove || kind == AlertPriceBelow || kind == AlertCoinInactive {
+		return SeverityWarning
+	}
+	return SeverityInfo
+}
+
+// AlertRule is a user defined condition evaluated on every candle close
+type AlertRule struct {
+	ID         string    `json:"id"`
+	CryptoID   string    `json:"crypto_id"`
+	Kind       AlertKind `json:"kind"`
+	Threshold  float64   `json:"threshold,omitempty"`
+	FastPeriod int       `json:"fast_period,omitempty"`
+	SlowPeriod int       `json:"slow_period,omitempty"`
+	Period     int       `json:"period,omitempty"`
+	// Severity defaults to the severity of the kind
+	Severity  AlertSeverity `json:"severity,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// WithDefaults fills the indicator parameters that were left empty:
+// 50/200 periods for crossovers, and a 14 period RSI with 30/70 thresholds.
+// An empty severity becomes the default severity of the kind.
+func (r AlertRule) WithDefaults() AlertRule {
+	if r.Severity == "" {
+		r.Severity = DefaultSeverity(r.Kind)
+	}
+	switch r.Kind {
+	case AlertGoldenCross, AlertDeathCross:
+		if r.FastPeriod == 0 {
+			r.FastPeriod = 50
+		}
+		if r.SlowPeriod == 0 {
+			r.SlowPeriod = 200
+		}
+	case AlertRSIBelow, AlertRSIAbove:
+		if r.Period == 0 {
+			r.Period = 14
+		}
+		if r.Threshold == 0 && r.Kind == AlertRSIBelow {
+			r.Threshold = 30
+		}
+		if r.Threshold == 0 && r.Kind == AlertRSIAbove {
+			r.Threshold = 70
+		}
+	}
+	return r
+}
+
+// Validate ensures that the AlertRule entity is valid
+func (r *AlertRule) Validate() error {
+	if r.CryptoID == "" {
+		return errors.New("alert rule crypto ID cannot be empty")
+	}
+	switch r.Kind {
+	case AlertPriceAbove, AlertPriceBelow:
+		if r.Threshold <= 0 {
+			return errors.New("price alert threshold must be positive")
+		}
+	case AlertGoldenCross, AlertDeathCross:
+		if r.FastPeriod <= 0 || r.SlowPeriod <= r.FastPeriod {
+			return errors.New("crossover periods must satisfy 0 < fast < slow")
+		}
+	case AlertRSIBelow, AlertRSIAbove:
+		if r.Period <= 1 {
+			return errors.New("RSI period must be greater than 1")
+		}
+		if r.Threshold <= 0 || r.Threshold >= 100 {
+			return errors.New("RSI threshold must be between 0 and 100")
+		}
+	default:
+		return fmt.Errorf("unknown alert kind: %q", r.Kind)
+	}
+	switch r.Severity {
+	case "", SeverityInfo, SeverityWarning, SeverityCritical:
+	default:
+		return fmt.Errorf("unknown alert severity: %q", r.Severity)
+	}
+	return nil
+}
+
+// Alert is a triggered alert rule
+type Alert struct {
+	RuleID      string        `json:"rule_id"`
+	CryptoID    string        `json:"crypto_id"`
+	Kind        AlertKind     `json:"kind"`
+	Severity    AlertSeverity `json:"severity"`
+	Message     string        `json:"message"`
+	Price       float64       `json:"price"`
+	TriggeredAt time.Time     `json:"triggered_at"`
+}
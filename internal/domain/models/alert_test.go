@@ -0,0 +1,49 @@
+package models
+
+import "testing"
+
+func TestAlertRule_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    AlertRule
+		wantErr bool
+	}{
+		{name: "price above", rule: AlertRule{CryptoID: "bitcoin", Kind: AlertPriceAbove, Threshold: 60000}},
+		{name: "golden cross defaults", rule: AlertRule{CryptoID: "bitcoin", Kind: AlertGoldenCross}.WithDefaults()},
+		{name: "rsi defaults", rule: AlertRule{CryptoID: "bitcoin", Kind: AlertRSIBelow}.WithDefaults()},
+		{name: "invalid - unknown kind", rule: AlertRule{CryptoID: "bitcoin", Kind: "moon"}, wantErr: true},
+		{name: "invalid - fast not below slow", rule: AlertRule{CryptoID: "bitcoin", Kind: AlertDeathCross, FastPeriod: 50, SlowPeriod: 20}, wantErr: true},
+		{name: "invalid - rsi threshold", rule: AlertRule{CryptoID: "bitcoin", Kind: AlertRSIAbove, Period: 14, Threshold: 120}, wantErr: true},
+		{name: "invalid - unknown severity", rule: AlertRule{CryptoID: "bitcoin", Kind: AlertPriceAbove, Threshold: 1, Severity: "panic"}, wantErr: true},
+		{name: "invalid - missing price threshold", rule: AlertRule{CryptoID: "bitcoin", Kind: AlertPriceBelow}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AlertRule.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAlertRule_WithDefaults(t *testing.T) {
+	rule := AlertRule{Kind: AlertRSIAbove}.WithDefaults()
+	if rule.Period != 14 || rule.Threshold != 70 {
+		t.Errorf("Expected RSI(14) > 70, got period %d threshold %f", rule.Period, rule.Threshold)
+	}
+
+	rule = AlertRule{Kind: AlertGoldenCross, FastPeriod: 10}.WithDefaults()
+	if rule.FastPeriod != 10 || rule.SlowPeriod != 200 {
+		t.Errorf("Expected explicit fast period to be kept, got %d/%d", rule.FastPeriod, rule.SlowPeriod)
+	}
+	if rule.Severity != SeverityInfo {
+		t.Errorf("Expected crossovers to default to info, got %s", rule.Severity)
+	}
+
+	rule = AlertRule{Kind: AlertPriceBelow, Severity: SeverityCritical}.WithDefaults()
+	if rule.Severity != SeverityCritical {
+		t.Errorf("Expected an explicit severity to be kept, got %s", rule.Severity)
+	}
+}
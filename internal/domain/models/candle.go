@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Candle is an OHLCV bar covering [OpenTime, CloseTime)
+type Candle struct {
+	CryptoID  string    `json:"crypto_id"`
+	OpenTime  time.Time `json:"open_time"`
+	CloseTime time.Time `json:"close_time"`
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Close     float64   `json:"close"`
+	Volume    float64   `json:"volume"`
+}
+
+// NewCandle starts a candle for the interval containing at, opened at price
+func NewCandle(cryptoID string, price float64, at time.Time, interval time.Duration) Candle {
+	open := at.UTC().Truncate(interval)
+	return Candle{
+		CryptoID:  cryptoID,
+		OpenTime:  open,
+		CloseTime: open.Add(interval),
+		Open:      price,
+		High:      price,
+		Low:       price,
+		Close:     price,
+	}
+}
+
+// Update applies a new price to the candle
+func (c *Candle) Update(price float64) {
+	c.High = max(c.High, price)
+	c.Low = min(c.Low, price)
+	c.Close = price
+}
+
+// Contains reports whether the time falls inside the candle interval
+func (c *Candle) Contains(at time.Time) bool {
+	return !at.Before(c.OpenTime) && at.Before(c.CloseTime)
+}
+
+// Closes returns the close prices of a candle series
+func Closes(candles []Candle) []float64 {
+	closes := make([]float64, len(candles))
+	for i, c := range candles {
+		closes[i] = c.Close
+	}
+	return closes
+}
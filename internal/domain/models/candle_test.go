@@ -0,0 +1,26 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCandle_Update(t *testing.T) {
+	at := time.Date(2024, 1, 1, 10, 17, 0, 0, time.UTC)
+	c := NewCandle("bitcoin", 100, at, time.Hour)
+
+	if !c.OpenTime.Equal(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected candle to open at 10:00, got %v", c.OpenTime)
+	}
+
+	c.Update(120)
+	c.Update(90)
+	c.Update(105)
+	if c.Open != 100 || c.High != 120 || c.Low != 90 || c.Close != 105 {
+		t.Errorf("Unexpected OHLC: %+v", c)
+	}
+
+	if !c.Contains(at.Add(30*time.Minute)) || c.Contains(at.Add(time.Hour)) {
+		t.Error("Unexpected Contains result")
+	}
+}
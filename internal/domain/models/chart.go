@@ -0,0 +1,137 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChartRange is the span of history a chart shows
+type ChartRange string
+
+// Supported chart ranges
+const (
+	// ChartRecent shows the stored candles and recent polled prices
+	ChartRecent ChartRange = "recent"
+	// ChartMax shows the daily series since the coin's genesis
+	ChartMax ChartRange = "max"
+)
+
+// ChartScale is the scale of a chart's price axis
+type ChartScale string
+
+// Supported chart scales
+const (
+	ChartLinear ChartScale = "linear"
+	ChartLog    ChartScale = "log"
+)
+
+// ChartIndicators lists the indicators a chart can overlay, as named by the indicators endpoint
+var ChartIndicators = []string{"sma", "ema", "rsi", "bollinger"}
+
+// MaxIndicatorPeriod bounds the period of an indicator, in candles
+const MaxIndicatorPeriod = 1000
+
+// ChartConfig is a named, shareable set of chart settings. Anyone with its ID
+// can open it; only its owner can change or delete it.
+type ChartConfig struct {
+	ID string `json:"id"`
+	// Owner is never serialized since charts are shared and it is a session ID
+	Owner    string     `json:"-"`
+	Name     string     `json:"name"`
+	CryptoID string     `json:"crypto_id"`
+	Range    ChartRange `json:"range"`
+	Scale    ChartScale `json:"scale"`
+	// Indicators maps overlaid indicators to their period; zero uses the default period
+	Indicators map[string]int `json:"indicators,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Normalize trims the name, lowercases the coin and indicators and fills the
+// default range and scale. Charts since inception default to a log scale since
+// they span orders of magnitude.
+func (c *ChartConfig) Normalize() {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Owner = strings.TrimSpace(c.Owner)
+	if c.Owner == "" {
+		c.Owner = DefaultOwner
+	}
+	c.CryptoID = strings.ToLower(strings.TrimSpace(c.CryptoID))
+	c.Range = ChartRange(strings.ToLower(strings.TrimSpace(string(c.Range))))
+	if c.Range == "" {
+		c.Range = ChartRecent
+	}
+	c.Scale = ChartScale(strings.ToLower(strings.TrimSpace(string(c.Scale))))
+	if c.Scale == "" {
+		c.Scale = ChartLinear
+		if c.Range == ChartMax {
+			c.Scale = ChartLog
+		}
+	}
+	if len(c.Indicators) > 0 {
+		indicators := make(map[string]int, len(c.Indicators))
+		for name, period := range c.Indicators {
+			indicators[strings.ToLower(strings.TrimSpace(name))] = period
+		}
+		c.Indicators = indicators
+	}
+}
+
+// Validate ensures that the ChartConfig entity is valid
+func (c *ChartConfig) Validate() error {
+	if c.Name == "" {
+		return errors.New("chart name cannot be empty")
+	}
+	if c.CryptoID == "" {
+		return errors.New("chart crypto_id cannot be empty")
+	}
+	if c.Range != ChartRecent && c.Range != ChartMax {
+		return fmt.Errorf("unknown chart range: %q", c.Range)
+	}
+	if c.Scale != ChartLinear && c.Scale != ChartLog {
+		return fmt.Errorf("unknown chart scale: %q", c.Scale)
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Indicators)) {
+		if !slices.Contains(ChartIndicators, name) {
+			return fmt.Errorf("unknown chart indicator: %q", name)
+		}
+		if period := c.Indicators[name]; period < 0 || period > MaxIndicatorPeriod {
+			return fmt.Errorf("period of %s must be between 0 and %d, got %d", name, MaxIndicatorPeriod, period)
+		}
+	}
+	return nil
+}
+
+// HistoryPath is the API path of the prices the chart draws
+func (c ChartConfig) HistoryPath() string {
+	path := "/api/v1/coins/" + url.PathEscape(c.CryptoID) + "/history"
+	if c.Range == ChartMax {
+		path += "?range=max"
+	}
+	return path
+}
+
+// IndicatorsPath is the API path of the chart's indicators, empty when it overlays none
+func (c ChartConfig) IndicatorsPath() string {
+	if len(c.Indicators) == 0 {
+		return ""
+	}
+	query := url.Values{}
+	for name, period := range c.Indicators {
+		if period > 0 {
+			query.Set(name, strconv.Itoa(period))
+		}
+	}
+	path := "/api/v1/coins/" + url.PathEscape(c.CryptoID) + "/indicators"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return path
+}
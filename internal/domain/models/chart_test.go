@@ -0,0 +1,47 @@
+package models
+
+import "testing"
+
+func TestChartConfig_Normalize(t *testing.T) {
+	c := ChartConfig{Name: " BTC cycle ", CryptoID: " Bitcoin", Range: "MAX", Indicators: map[string]int{" SMA": 200}}
+	c.Normalize()
+	if c.Name != "BTC cycle" || c.CryptoID != "bitcoin" || c.Owner != DefaultOwner || c.Scale != ChartLog || c.Indicators["sma"] != 200 {
+		t.Errorf("Unexpected normalized chart: %+v", c)
+	}
+
+	recent := ChartConfig{Name: "ETH", CryptoID: "ethereum"}
+	recent.Normalize()
+	if recent.Range != ChartRecent || recent.Scale != ChartLinear {
+		t.Errorf("Expected a recent linear chart by default, got %+v", recent)
+	}
+}
+
+func TestChartConfig_Validate(t *testing.T) {
+	invalid := []ChartConfig{
+		{Name: "", CryptoID: "bitcoin", Range: ChartRecent, Scale: ChartLinear},
+		{Name: "no coin", Range: ChartRecent, Scale: ChartLinear},
+		{Name: "range", CryptoID: "bitcoin", Range: "1y", Scale: ChartLinear},
+		{Name: "scale", CryptoID: "bitcoin", Range: ChartRecent, Scale: "sqrt"},
+		{Name: "macd", CryptoID: "bitcoin", Range: ChartRecent, Scale: ChartLinear, Indicators: map[string]int{"macd": 9}},
+		{Name: "period", CryptoID: "bitcoin", Range: ChartRecent, Scale: ChartLinear, Indicators: map[string]int{"rsi": -1}},
+		{Name: "huge period", CryptoID: "bitcoin", Range: ChartRecent, Scale: ChartLinear, Indicators: map[string]int{"sma": MaxIndicatorPeriod + 1}},
+	}
+	for _, c := range invalid {
+		if err := c.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", c)
+		}
+	}
+}
+
+func TestChartConfig_Paths(t *testing.T) {
+	c := ChartConfig{CryptoID: "bitcoin", Range: ChartMax, Indicators: map[string]int{"sma": 50, "rsi": 0}}
+	if got := c.HistoryPath(); got != "/api/v1/coins/bitcoin/history?range=max" {
+		t.Errorf("Unexpected history path %s", got)
+	}
+	if got := c.IndicatorsPath(); got != "/api/v1/coins/bitcoin/indicators?sma=50" {
+		t.Errorf("Unexpected indicators path %s", got)
+	}
+	if got := (ChartConfig{CryptoID: "bitcoin"}).IndicatorsPath(); got != "" {
+		t.Errorf("Expected no indicators path without indicators, got %s", got)
+	}
+}
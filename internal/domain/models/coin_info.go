@@ -0,0 +1,43 @@
+package models
+
+import "strings"
+
+// CoinInfo is the descriptive metadata of a coin
+type CoinInfo struct {
+	ID            string   `json:"id"`
+	Symbol        string   `json:"symbol"`
+	Name          string   `json:"name"`
+	Description   string   `json:"description,omitempty"`
+	Homepage      string   `json:"homepage,omitempty"`
+	Categories    []string `json:"categories"`
+	Images        Images   `json:"images"`
+	GenesisDate   string   `json:"genesis_date,omitempty"`
+	MarketCapRank int      `json:"market_cap_rank,omitempty"`
+	// Explorers are URL templates for addresses and transactions on the coin's chain
+	Explorers []Explorer `json:"explorers,omitempty"`
+	// Stale marks metadata taken from the local coin list because the
+	// provider could not be reached; it only has the names and rank
+	Stale bool `json:"stale,omitempty"`
+}
+
+// Images holds the URLs of a coin logo in increasing sizes
+type Images struct {
+	Thumb string `json:"thumb,omitempty"`
+	Small string `json:"small,omitempty"`
+	Large string `json:"large,omitempty"`
+}
+
+// SearchResult is a coin matching a search query
+type SearchResult struct {
+	ID            string `json:"id"`
+	Symbol        string `json:"symbol"`
+	Name          string `json:"name"`
+	MarketCapRank int    `json:"market_cap_rank,omitempty"`
+	Thumb         string `json:"thumb,omitempty"`
+}
+
+// ExactMatch reports whether the query is the coin's ticker or ID, ignoring case
+func (r *SearchResult) ExactMatch(query string) bool {
+	query = strings.TrimSpace(query)
+	return strings.EqualFold(r.Symbol, query) || strings.EqualFold(r.ID, query)
+}
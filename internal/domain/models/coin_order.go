@@ -0,0 +1,86 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxOrderedCoins bounds the coins an owner may pin or order
+const MaxOrderedCoins = 500
+
+// CoinOrder is an owner's arrangement of the price table. Pinned coins come
+// first in the order they were pinned, then the coins of the manual order, then
+// every other coin in the default order.
+type CoinOrder struct {
+	Owner     string    `json:"owner"`
+	Pinned    []string  `json:"pinned"`
+	Order     []string  `json:"order"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DefaultCoinOrder returns the arrangement of owners who never chose one
+func DefaultCoinOrder(owner string) CoinOrder {
+	return CoinOrder{Owner: owner, Pinned: []string{}, Order: []string{}}
+}
+
+// Normalize lowercases the coin IDs and replaces missing lists with empty ones
+func (o *CoinOrder) Normalize() {
+	for _, ids := range []*[]string{&o.Pinned, &o.Order} {
+		if *ids == nil {
+			*ids = []string{}
+		}
+		for i, id := range *ids {
+			(*ids)[i] = strings.ToLower(strings.TrimSpace(id))
+		}
+	}
+}
+
+// Validate ensures that the CoinOrder entity is valid
+func (o *CoinOrder) Validate() error {
+	for name, ids := range map[string][]string{"pinned": o.Pinned, "order": o.Order} {
+		if len(ids) > MaxOrderedCoins {
+			return fmt.Errorf("%s cannot list more than %d coins", name, MaxOrderedCoins)
+		}
+		seen := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			if id == "" {
+				return errors.New("coin ID cannot be empty")
+			}
+			if seen[id] {
+				return fmt.Errorf("%s contains %s twice", name, id)
+			}
+			seen[id] = true
+		}
+	}
+	return nil
+}
+
+// Sort returns the prices arranged by the order. Coins it does not mention
+// keep their relative position after the pinned and ordered ones.
+func (o CoinOrder) Sort(prices []CryptoPrice) []CryptoPrice {
+	rank := make(map[string]int, len(o.Pinned)+len(o.Order))
+	for i, id := range o.Order {
+		rank[id] = len(o.Pinned) + i
+	}
+	// Pins win over the manual order
+	for i, id := range o.Pinned {
+		rank[id] = i
+	}
+	unranked := len(rank)
+	sorted := slices.Clone(prices)
+	slices.SortStableFunc(sorted, func(a, b CryptoPrice) int {
+		ra, ok := rank[a.ID]
+		if !ok {
+			ra = unranked
+		}
+		rb, ok := rank[b.ID]
+		if !ok {
+			rb = unranked
+		}
+		return ra - rb
+	})
+	return sorted
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCoinOrder_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   CoinOrder
+		wantErr bool
+	}{
+		{"valid", CoinOrder{Pinned: []string{"solana"}, Order: []string{"ethereum", "solana"}}, false},
+		{"empty", DefaultCoinOrder("alice"), false},
+		{"duplicate pin", CoinOrder{Pinned: []string{"solana", "solana"}}, true},
+		{"duplicate order", CoinOrder{Order: []string{"bitcoin", "bitcoin"}}, true},
+		{"empty coin", CoinOrder{Order: []string{""}}, true},
+		{"too many pins", CoinOrder{Pinned: make([]string, MaxOrderedCoins+1)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.order.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCoinOrder_Sort(t *testing.T) {
+	var prices []CryptoPrice
+	for _, id := range []string{"bitcoin", "ethereum", "solana", "cardano", "dogecoin"} {
+		prices = append(prices, CryptoPrice{ID: id})
+	}
+	order := CoinOrder{Pinned: []string{"dogecoin"}, Order: []string{"solana", "dogecoin", "bitcoin", "unknown"}}
+
+	var got []string
+	for _, p := range order.Sort(prices) {
+		got = append(got, p.ID)
+	}
+	want := []string{"dogecoin", "solana", "bitcoin", "ethereum", "cardano"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if prices[0].ID != "bitcoin" {
+		t.Error("Expected the input to be left untouched")
+	}
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SourceQuote is the price of an asset reported by one provider or exchange.
+// Error is set instead of the price when the source could not be queried.
+type SourceQuote struct {
+	Source string          `json:"source"`
+	Pair   string          `json:"pair,omitempty"`
+	Price  decimal.Decimal `json:"price"`
+	// Deviation is the distance from the median of all quotes, in percent
+	Deviation float64   `json:"deviation"`
+	Outlier   bool      `json:"outlier,omitempty"`
+	At        time.Time `json:"at"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// PriceComparison puts the quotes of several sources for the same asset side by side
+type PriceComparison struct {
+	Symbol   string          `json:"symbol"`
+	Currency Currency        `json:"currency"`
+	Quotes   []SourceQuote   `json:"quotes"`
+	Median   decimal.Decimal `json:"median"`
+	// Low and High are the sources with the lowest and highest price
+	Low  string `json:"low,omitempty"`
+	High string `json:"high,omitempty"`
+	// Spread is the difference between the highest and the lowest price
+	Spread decimal.Decimal `json:"spread"`
+	// SpreadPct is the spread relative to the lowest price, in percent
+	SpreadPct float64 `json:"spread_pct"`
+}
+
+// NewPriceComparison computes the median, spread and deviations of the quotes.
+// Quotes deviating from the median by more than outlierPct are flagged, which
+// usually means the source serves stale data. Failed quotes are kept but ignored.
+func NewPriceComparison(symbol string, currency Currency, quotes []SourceQuote, outlierPct float64) PriceComparison {
+	c := PriceComparison{Symbol: symbol, Currency: currency, Quotes: quotes}
+	var prices []decimal.Decimal
+	for _, q := range quotes {
+		if q.Error == "" {
+			prices = append(prices, q.Price)
+		}
+	}
+	if len(prices) == 0 {
+		return c
+	}
+
+	sort.Slice(prices, func(i, j int) bool { return prices[i].LessThan(prices[j]) })
+	mid := len(prices) / 2
+	c.Median = prices[mid]
+	if len(prices)%2 == 0 {
+		c.Median = prices[mid-1].Add(prices[mid]).Div(decimal.NewFromInt(2))
+	}
+
+	low, high := prices[0], prices[len(prices)-1]
+	c.Spread = high.Sub(low)
+	if low.IsPositive() {
+		c.SpreadPct = c.Spread.Div(low).Mul(decimal.NewFromInt(100)).InexactFloat64()
+	}
+	for i := range c.Quotes {
+		q := &c.Quotes[i]
+		if q.Error != "" {
+			continue
+		}
+		if q.Price.Equal(low) && c.Low == "" {
+			c.Low = q.Source
+		}
+		if q.Price.Equal(high) && c.High == "" {
+			c.High = q.Source
+		}
+		if c.Median.IsPositive() {
+			q.Deviation = q.Price.Sub(c.Median).Div(c.Median).Mul(decimal.NewFromInt(100)).InexactFloat64()
+			q.Outlier = len(prices) > 2 && (q.Deviation > outlierPct || q.Deviation < -outlierPct)
+		}
+	}
+	return c
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestNewPriceComparison(t *testing.T) {
+	quotes := []SourceQuote{
+		{Source: "coingecko", Price: decimal.NewFromInt(100)},
+		{Source: "binance", Price: decimal.NewFromInt(101)},
+		{Source: "kraken", Price: decimal.NewFromInt(110)},
+		{Source: "offline", Error: "timeout"},
+	}
+
+	c := NewPriceComparison("BTC", USD, quotes, 5)
+	if !c.Median.Equal(decimal.NewFromInt(101)) {
+		t.Errorf("Expected median 101, got %s", c.Median)
+	}
+	if c.Low != "coingecko" || c.High != "kraken" || !c.Spread.Equal(decimal.NewFromInt(10)) || c.SpreadPct != 10 {
+		t.Errorf("Unexpected spread: %s-%s %s (%f%%)", c.Low, c.High, c.Spread, c.SpreadPct)
+	}
+	if c.Quotes[1].Deviation != 0 || c.Quotes[1].Outlier {
+		t.Errorf("Expected binance at the median, got %+v", c.Quotes[1])
+	}
+	if q := c.Quotes[2]; !q.Outlier || math.Abs(q.Deviation-8.9109) > 0.001 {
+		t.Errorf("Expected kraken flagged as an outlier, got %+v", q)
+	}
+	if c.Quotes[3].Outlier {
+		t.Error("Expected failed quotes to be ignored")
+	}
+
+	// Two sources cannot tell which one is off
+	two := NewPriceComparison("BTC", USD, quotes[1:3], 1)
+	if !two.Median.Equal(decimal.RequireFromString("105.5")) || two.Quotes[1].Outlier {
+		t.Errorf("Unexpected comparison of two quotes: %+v", two)
+	}
+}
@@ -5,16 +5,42 @@ import (
 	"errors"
 	"fmt"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 // CryptoPrice represents cryptocurrency price data
 // This is our main domain entity that follows DDD principles
 type CryptoPrice struct {
-	ID           string  `json:"id"`
-	Symbol       string  `json:"symbol"`
-	Name         string  `json:"name"`
-	CurrentPrice float64 `json:"current_price"`
-	LastUpdated  string  `json:"last_updated"`
+	ID             string          `json:"id"`
+	Symbol         string          `json:"symbol"`
+	Name           string          `json:"name"`
+	CurrentPrice   decimal.Decimal `json:"current_price"`
+	Currency       Currency        `json:"currency"`
+	PriceChange24h float64         `json:"price_change_percentage_24h"`
+	LastUpdated    string          `json:"last_updated"`
+	// Stale marks a cached price served because refreshing it failed;
+	// StaleSince is when it was last refreshed successfully
+	Stale      bool       `json:"stale,omitempty"`
+	StaleSince *time.Time `json:"stale_since,omitempty"`
+	// Inactive marks a coin the provider stopped updating, e.g. because it was
+	// delisted or migrated; its price is frozen at LastUpdated
+	Inactive bool `json:"inactive,omitempty"`
+
+	// Market fields are only filled by market listings, not by simple price lookups
+	MarketCap         float64 `json:"market_cap,omitempty"`
+	Volume24h         float64 `json:"total_volume,omitempty"`
+	PriceChange7d     float64 `json:"price_change_percentage_7d,omitempty"`
+	CirculatingSupply float64 `json:"circulating_supply,omitempty"`
+	ATH               float64 `json:"ath,omitempty"`
+	// Image is the URL of the coin logo
+	Image string `json:"image,omitempty"`
+}
+
+// LastUpdatedTime parses LastUpdated, reporting false when it is empty or malformed
+func (c *CryptoPrice) LastUpdatedTime() (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, c.LastUpdated)
+	return t, err == nil
 }
 
 // CryptoBatch represents a collection of CryptoPrice
@@ -34,19 +60,20 @@ func (c *CryptoPrice) Validate() error {
 	if c.Name == "" {
 		return errors.New("crypto name cannot be empty")
 	}
-	if c.CurrentPrice < 0 {
+	if c.CurrentPrice.IsNegative() {
 		return errors.New("crypto price cannot be negative")
 	}
 	return nil
 }
 
-// UpdatePrice updates the current price and last updated timestamp
-func (c *CryptoPrice) UpdatePrice(newPrice float64) error {
-	if newPrice < 0 {
+// UpdatePrice updates the current price, stamping it as updated at the given
+// time, which callers take from their clock
+func (c *CryptoPrice) UpdatePrice(newPrice decimal.Decimal, at time.Time) error {
+	if newPrice.IsNegative() {
 		return errors.New("price cannot be negative")
 	}
 	c.CurrentPrice = newPrice
-	c.LastUpdated = time.Now().UTC().Format(time.RFC3339)
+	c.LastUpdated = at.UTC().Format(time.RFC3339)
 	return nil
 }
 
@@ -66,22 +93,46 @@ func (b *CryptoBatch) GetBySymbol(symbol string) (CryptoPrice, bool) {
 }
 
 // TotalValue calculates the total value of all cryptocurrencies in the batch
-func (b *CryptoBatch) TotalValue() float64 {
-	total := 0.0
+func (b *CryptoBatch) TotalValue() decimal.Decimal {
+	total := decimal.Zero
 	for _, crypto := range b.Prices {
-		total += crypto.CurrentPrice
+		total = total.Add(crypto.CurrentPrice)
 	}
 	return total
 }
 
-// MustUpdatePrice updates the price and panics if the price is invalid
+// TotalValueIn calculates the total value of the batch converted to a single currency.
+// Prices without a currency are assumed to be in DefaultCurrency.
+func (b *CryptoBatch) TotalValueIn(currency Currency, rates ExchangeRates) (decimal.Decimal, error) {
+	total := decimal.Zero
+	for _, crypto := range b.Prices {
+		from := crypto.Currency
+		if from == "" {
+			from = DefaultCurrency
+		}
+		value, err := rates.ConvertDecimal(crypto.CurrentPrice, from, currency)
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("failed to convert %s: %w", crypto.ID, err)
+		}
+		total = total.Add(value)
+	}
+	return total, nil
+}
+
+// PriceFloat returns the current price as a float64 for statistics and charts,
+// where the rounding error of a float does not matter
+func (c *CryptoPrice) PriceFloat() float64 {
+	return c.CurrentPrice.InexactFloat64()
+}
+
+// MustUpdatePrice updates the price like UpdatePrice and panics if the price is invalid
 // This demonstrates how to test panic scenarios
-func (c *CryptoPrice) MustUpdatePrice(newPrice float64) {
-	if newPrice < 0 {
-		panic(fmt.Sprintf("price cannot be negative: %f", newPrice))
+func (c *CryptoPrice) MustUpdatePrice(newPrice decimal.Decimal, at time.Time) {
+	if newPrice.IsNegative() {
+		panic(fmt.Sprintf("price cannot be negative: %s", newPrice))
 	}
 	c.CurrentPrice = newPrice
-	c.LastUpdated = time.Now().UTC().Format(time.RFC3339)
+	c.LastUpdated = at.UTC().Format(time.RFC3339)
 }
 
 // GetPriceAt returns the price at a specific index in the batch
@@ -92,3 +143,9 @@ func (b *CryptoBatch) GetPriceAt(index int) CryptoPrice {
 	}
 	return b.Prices[index]
 }
+
+// PricePoint is a price observed at a point in time
+type PricePoint struct {
+	Price float64   `json:"price"`
+	Time  time.Time `json:"time"`
+}
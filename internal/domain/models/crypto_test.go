@@ -1,8 +1,12 @@
 package models
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 func TestCryptoPrice_Validation(t *testing.T) {
@@ -17,7 +21,7 @@ func TestCryptoPrice_Validation(t *testing.T) {
 				ID:           "bitcoin",
 				Symbol:       "btc",
 				Name:         "Bitcoin",
-				CurrentPrice: 50000.0,
+				CurrentPrice: decimal.NewFromFloat(50000.0),
 				LastUpdated:  time.Now().UTC().Format(time.RFC3339),
 			},
 			wantErr: false,
@@ -28,7 +32,7 @@ func TestCryptoPrice_Validation(t *testing.T) {
 				ID:           "",
 				Symbol:       "btc",
 				Name:         "Bitcoin",
-				CurrentPrice: 50000.0,
+				CurrentPrice: decimal.NewFromFloat(50000.0),
 				LastUpdated:  time.Now().UTC().Format(time.RFC3339),
 			},
 			wantErr: true,
@@ -39,7 +43,7 @@ func TestCryptoPrice_Validation(t *testing.T) {
 				ID:           "bitcoin",
 				Symbol:       "btc",
 				Name:         "Bitcoin",
-				CurrentPrice: -100.0,
+				CurrentPrice: decimal.NewFromFloat(-100.0),
 				LastUpdated:  time.Now().UTC().Format(time.RFC3339),
 			},
 			wantErr: true,
@@ -62,14 +66,14 @@ func TestCryptoBatch_Operations(t *testing.T) {
 		ID:           "bitcoin",
 		Symbol:       "btc",
 		Name:         "Bitcoin",
-		CurrentPrice: 50000.0,
+		CurrentPrice: decimal.NewFromFloat(50000.0),
 		LastUpdated:  time.Now().UTC().Format(time.RFC3339),
 	}
 	eth := CryptoPrice{
 		ID:           "ethereum",
 		Symbol:       "eth",
 		Name:         "Ethereum",
-		CurrentPrice: 3000.0,
+		CurrentPrice: decimal.NewFromFloat(3000.0),
 		LastUpdated:  time.Now().UTC().Format(time.RFC3339),
 	}
 
@@ -102,13 +106,53 @@ func TestCryptoBatch_Operations(t *testing.T) {
 
 	t.Run("calculate total value", func(t *testing.T) {
 		batch := CryptoBatch{Prices: []CryptoPrice{btc, eth}}
-		expected := 53000.0 // 50000 + 3000
+		expected := decimal.NewFromInt(53000) // 50000 + 3000
 
 		total := batch.TotalValue()
-		if total != expected {
-			t.Errorf("Expected total value of %f, got %f", expected, total)
+		if !total.Equal(expected) {
+			t.Errorf("Expected total value of %s, got %s", expected, total)
 		}
 	})
+
+	t.Run("total value keeps micro-cap precision", func(t *testing.T) {
+		batch := CryptoBatch{}
+		for i := 0; i < 10; i++ {
+			batch.AddCrypto(CryptoPrice{ID: "pepe", CurrentPrice: decimal.RequireFromString("0.0000000000123456789")})
+		}
+		batch.AddCrypto(CryptoPrice{ID: "bitcoin", CurrentPrice: decimal.NewFromInt(65000)})
+
+		expected := decimal.RequireFromString("65000.000000000123456789")
+		if total := batch.TotalValue(); !total.Equal(expected) {
+			t.Errorf("Expected total value of %s, got %s", expected, total)
+		}
+	})
+}
+
+func TestCryptoPrice_JSONPreservesPrecision(t *testing.T) {
+	price := CryptoPrice{ID: "shiba-inu", CurrentPrice: decimal.RequireFromString("0.000012345678901234")}
+	data, err := json.Marshal(price)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), `"current_price":"0.000012345678901234"`) {
+		t.Errorf("Expected the price to be encoded exactly, got %s", data)
+	}
+
+	var decoded CryptoPrice
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !decoded.CurrentPrice.Equal(price.CurrentPrice) {
+		t.Errorf("Expected %s after a round trip, got %s", price.CurrentPrice, decoded.CurrentPrice)
+	}
+
+	// Upstream APIs send plain JSON numbers, which must decode without a float round trip
+	if err := json.Unmarshal([]byte(`{"current_price":0.00000000001234567891}`), &decoded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decoded.CurrentPrice.String() != "0.00000000001234567891" {
+		t.Errorf("Expected the number to decode exactly, got %s", decoded.CurrentPrice)
+	}
 }
 
 func TestCryptoPrice_PriceUpdate(t *testing.T) {
@@ -116,28 +160,38 @@ func TestCryptoPrice_PriceUpdate(t *testing.T) {
 		ID:           "bitcoin",
 		Symbol:       "btc",
 		Name:         "Bitcoin",
-		CurrentPrice: 50000.0,
+		CurrentPrice: decimal.NewFromFloat(50000.0),
 		LastUpdated:  time.Now().UTC().Format(time.RFC3339),
 	}
 
 	t.Run("valid price update", func(t *testing.T) {
-		newPrice := 51000.0
-		err := crypto.UpdatePrice(newPrice)
+		newPrice := decimal.NewFromInt(51000)
+		err := crypto.UpdatePrice(newPrice, time.Now())
 		if err != nil {
 			t.Errorf("Unexpected error updating price: %v", err)
 		}
-		if crypto.CurrentPrice != newPrice {
-			t.Errorf("Expected price %f, got %f", newPrice, crypto.CurrentPrice)
+		if !crypto.CurrentPrice.Equal(newPrice) {
+			t.Errorf("Expected price %s, got %s", newPrice, crypto.CurrentPrice)
 		}
 	})
 
 	t.Run("invalid price update", func(t *testing.T) {
-		newPrice := -1000.0
-		err := crypto.UpdatePrice(newPrice)
+		newPrice := decimal.NewFromInt(-1000)
+		err := crypto.UpdatePrice(newPrice, time.Now())
 		if err == nil {
 			t.Error("Expected error updating to negative price, got nil")
 		}
 	})
+
+	t.Run("stamped update", func(t *testing.T) {
+		at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("BRT", -3*60*60))
+		if err := crypto.UpdatePrice(decimal.NewFromInt(52000), at); err != nil {
+			t.Fatalf("Unexpected error updating price: %v", err)
+		}
+		if crypto.LastUpdated != "2024-05-01T15:00:00Z" {
+			t.Errorf("Expected the given time in UTC, got %s", crypto.LastUpdated)
+		}
+	})
 }
 
 func TestCryptoPrice_MustUpdatePrice_Panic(t *testing.T) {
@@ -145,7 +199,7 @@ func TestCryptoPrice_MustUpdatePrice_Panic(t *testing.T) {
 		ID:           "bitcoin",
 		Symbol:       "btc",
 		Name:         "Bitcoin",
-		CurrentPrice: 50000.0,
+		CurrentPrice: decimal.NewFromFloat(50000.0),
 		LastUpdated:  time.Now().UTC().Format(time.RFC3339),
 	}
 
@@ -155,14 +209,14 @@ func TestCryptoPrice_MustUpdatePrice_Panic(t *testing.T) {
 				t.Error("Expected panic but got none")
 			} else {
 				// Check if panic message is as expected
-				expected := "price cannot be negative: -100.000000"
+				expected := "price cannot be negative: -100"
 				if r.(string) != expected {
 					t.Errorf("Expected panic message '%s', got '%v'", expected, r)
 				}
 			}
 		}()
 
-		crypto.MustUpdatePrice(-100.0)
+		crypto.MustUpdatePrice(decimal.NewFromInt(-100), time.Now())
 	})
 
 	t.Run("should not panic with valid price", func(t *testing.T) {
@@ -172,9 +226,13 @@ func TestCryptoPrice_MustUpdatePrice_Panic(t *testing.T) {
 			}
 		}()
 
-		crypto.MustUpdatePrice(55000.0)
-		if crypto.CurrentPrice != 55000.0 {
-			t.Errorf("Expected price 55000.0, got %f", crypto.CurrentPrice)
+		at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		crypto.MustUpdatePrice(decimal.NewFromInt(55000), at)
+		if !crypto.CurrentPrice.Equal(decimal.NewFromInt(55000)) {
+			t.Errorf("Expected price 55000, got %s", crypto.CurrentPrice)
+		}
+		if crypto.LastUpdated != "2024-05-01T12:00:00Z" {
+			t.Errorf("Expected the given time, got %s", crypto.LastUpdated)
 		}
 	})
 }
@@ -186,7 +244,7 @@ func TestCryptoBatch_GetPriceAt_Panic(t *testing.T) {
 				ID:           "bitcoin",
 				Symbol:       "btc",
 				Name:         "Bitcoin",
-				CurrentPrice: 50000.0,
+				CurrentPrice: decimal.NewFromFloat(50000.0),
 			},
 		},
 	}
@@ -195,13 +253,13 @@ func TestCryptoBatch_GetPriceAt_Panic(t *testing.T) {
 		name          string
 		index         int
 		shouldPanic   bool
-		expectedPrice float64
+		expectedPrice decimal.Decimal
 	}{
 		{
 			name:          "valid index",
 			index:         0,
 			shouldPanic:   false,
-			expectedPrice: 50000.0,
+			expectedPrice: decimal.NewFromInt(50000),
 		},
 		{
 			name:        "panic on negative index",
@@ -228,8 +286,8 @@ func TestCryptoBatch_GetPriceAt_Panic(t *testing.T) {
 			}()
 
 			result := batch.GetPriceAt(tt.index)
-			if !tt.shouldPanic && result.CurrentPrice != tt.expectedPrice {
-				t.Errorf("Expected price %f, got %f", tt.expectedPrice, result.CurrentPrice)
+			if !tt.shouldPanic && !result.CurrentPrice.Equal(tt.expectedPrice) {
+				t.Errorf("Expected price %s, got %s", tt.expectedPrice, result.CurrentPrice)
 			}
 		})
 	}
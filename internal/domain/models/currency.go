@@ -0,0 +1,111 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Currency is a lowercase vs_currency code as understood by CoinGecko (e.g. "usd", "eur", "brl")
+type Currency string
+
+// Commonly used fiat currencies
+const (
+	USD Currency = "usd"
+	EUR Currency = "eur"
+	BRL Currency = "brl"
+	GBP Currency = "gbp"
+	JPY Currency = "jpy"
+)
+
+// DefaultCurrency is used whenever no currency is specified
+const DefaultCurrency = USD
+
+// ParseCurrency normalizes a currency code, falling back to DefaultCurrency when empty
+func ParseCurrency(code string) (Currency, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultCurrency, nil
+	}
+	if len(code) < 3 || len(code) > 5 {
+		return "", fmt.Errorf("invalid currency code: %q", code)
+	}
+	for _, r := range code {
+		if r < 'a' || r > 'z' {
+			return "", fmt.Errorf("invalid currency code: %q", code)
+		}
+	}
+	return Currency(code), nil
+}
+
+// String returns the currency code
+func (c Currency) String() string {
+	return string(c)
+}
+
+// ExchangeRates holds the value of one unit of Base expressed in other currencies
+type ExchangeRates struct {
+	Base  Currency             `json:"base"`
+	Rates map[Currency]float64 `json:"rates"`
+}
+
+// NewExchangeRates builds rates from quotes of the same asset in several currencies.
+// For example the price of bitcoin in usd, eur and brl is enough to convert between all three.
+func NewExchangeRates(base Currency, quotes map[Currency]float64) (ExchangeRates, error) {
+	baseQuote, ok := quotes[base]
+	if !ok || baseQuote <= 0 {
+		return ExchangeRates{}, fmt.Errorf("missing quote for base currency %s", base)
+	}
+	rates := make(map[Currency]float64, len(quotes))
+	for currency, quote := range quotes {
+		if quote <= 0 {
+			continue
+		}
+		rates[currency] = quote / baseQuote
+	}
+	return ExchangeRates{Base: base, Rates: rates}, nil
+}
+
+// Convert converts an amount between two currencies known to the rates
+func (r ExchangeRates) Convert(amount float64, from, to Currency) (float64, error) {
+	if from == to {
+		return amount, nil
+	}
+	fromRate, err := r.rate(from)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := r.rate(to)
+	if err != nil {
+		return 0, err
+	}
+	return amount / fromRate * toRate, nil
+}
+
+// ConvertDecimal converts an exact amount between two currencies known to the rates
+func (r ExchangeRates) ConvertDecimal(amount decimal.Decimal, from, to Currency) (decimal.Decimal, error) {
+	if from == to {
+		return amount, nil
+	}
+	fromRate, err := r.rate(from)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	toRate, err := r.rate(to)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return amount.Mul(decimal.NewFromFloat(toRate)).Div(decimal.NewFromFloat(fromRate)), nil
+}
+
+func (r ExchangeRates) rate(c Currency) (float64, error) {
+	if c == r.Base {
+		return 1, nil
+	}
+	rate, ok := r.Rates[c]
+	if !ok || rate <= 0 {
+		return 0, fmt.Errorf("no exchange rate for %s", c)
+	}
+	return rate, nil
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestParseCurrency(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Currency
+		wantErr bool
+	}{
+		{input: "", want: USD},
+		{input: "EUR", want: EUR},
+		{input: " brl ", want: BRL},
+		{input: "e1", wantErr: true},
+		{input: "us-d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCurrency(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCurrency(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExchangeRates_Convert(t *testing.T) {
+	// Bitcoin quoted in three currencies
+	rates, err := NewExchangeRates(USD, map[Currency]float64{
+		USD: 50000,
+		EUR: 45000,
+		BRL: 250000,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	got, err := rates.Convert(100, USD, BRL)
+	if err != nil || math.Abs(got-500) > 1e-9 {
+		t.Errorf("Expected 100 USD = 500 BRL, got %f (err %v)", got, err)
+	}
+
+	got, err = rates.Convert(90, EUR, BRL)
+	if err != nil || math.Abs(got-500) > 1e-9 {
+		t.Errorf("Expected 90 EUR = 500 BRL, got %f (err %v)", got, err)
+	}
+
+	if _, err := rates.Convert(1, USD, JPY); err == nil {
+		t.Error("Expected error converting to unknown currency, got nil")
+	}
+}
+
+func TestCryptoBatch_TotalValueIn(t *testing.T) {
+	rates, _ := NewExchangeRates(USD, map[Currency]float64{USD: 1, EUR: 0.9})
+	batch := CryptoBatch{Prices: []CryptoPrice{
+		{ID: "bitcoin", CurrentPrice: decimal.NewFromInt(100), Currency: USD},
+		{ID: "ethereum", CurrentPrice: decimal.NewFromInt(90), Currency: EUR},
+	}}
+
+	total, err := batch.TotalValueIn(EUR, rates)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !total.Equal(decimal.NewFromInt(180)) {
+		t.Errorf("Expected total of 180 EUR, got %s", total)
+	}
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Digest is the daily summary of the tracked prices and the alerts triggered
+// during the previous day
+type Digest struct {
+	Date     time.Time     `json:"date"`
+	Currency Currency      `json:"currency"`
+	Prices   []CryptoPrice `json:"prices"`
+	Alerts   []Alert       `json:"alerts"`
+	// ETFFlows summarizes spot ETF flows when they are tracked
+	ETFFlows []ETFFlowSummary `json:"etf_flows,omitempty"`
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ETFAssets are the assets whose spot ETF flows are tracked
+var ETFAssets = []string{"BTC", "ETH"}
+
+// ETFFlow is the net creations minus redemptions of the spot ETFs of an asset on one day
+type ETFFlow struct {
+	Asset string    `json:"asset"`
+	Date  time.Time `json:"date"`
+	// NetFlow is the total net flow in USD; negative values are outflows
+	NetFlow float64 `json:"net_flow"`
+	// Funds breaks the net flow down by fund ticker when the source reports it
+	Funds map[string]float64 `json:"funds,omitempty"`
+}
+
+// Normalize uppercases the asset and truncates the date to its UTC day
+func (f *ETFFlow) Normalize() {
+	f.Asset = strings.ToUpper(strings.TrimSpace(f.Asset))
+	f.Date = f.Date.UTC().Truncate(24 * time.Hour)
+}
+
+// Validate ensures that the ETFFlow entity is valid
+func (f *ETFFlow) Validate() error {
+	if !slices.Contains(ETFAssets, f.Asset) {
+		return fmt.Errorf("unsupported ETF asset: %q", f.Asset)
+	}
+	if f.Date.IsZero() {
+		return errors.New("ETF flow date is required")
+	}
+	return nil
+}
+
+// ETFFlowSummary condenses the recent flows of an asset for widgets and digests
+type ETFFlowSummary struct {
+	Asset string `json:"asset"`
+	// Date is the day of the latest reported flow
+	Date   time.Time `json:"date"`
+	Latest float64   `json:"latest"`
+	// Week and Month sum the flows of the 7 and 30 days ending on Date
+	Week  float64 `json:"week"`
+	Month float64 `json:"month"`
+	// Streak counts the consecutive reported days with flows in the direction
+	// of the latest one; it is negative for outflows
+	Streak int `json:"streak"`
+}
+
+// SummarizeETFFlows summarizes flows sorted by date. It reports false when there are none.
+func SummarizeETFFlows(asset string, flows []ETFFlow) (ETFFlowSummary, bool) {
+	if len(flows) == 0 {
+		return ETFFlowSummary{}, false
+	}
+	latest := flows[len(flows)-1]
+	summary := ETFFlowSummary{Asset: asset, Date: latest.Date, Latest: latest.NetFlow}
+	week, month := latest.Date.AddDate(0, 0, -7), latest.Date.AddDate(0, 0, -30)
+	for _, f := range flows {
+		if f.Date.After(week) {
+			summary.Week += f.NetFlow
+		}
+		if f.Date.After(month) {
+			summary.Month += f.NetFlow
+		}
+	}
+	for i := len(flows) - 1; i >= 0; i-- {
+		switch {
+		case latest.NetFlow > 0 && flows[i].NetFlow > 0:
+			summary.Streak++
+		case latest.NetFlow < 0 && flows[i].NetFlow < 0:
+			summary.Streak--
+		default:
+			return summary, true
+		}
+	}
+	return summary, true
+}
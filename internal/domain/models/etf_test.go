@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestETFFlow_NormalizeAndValidate(t *testing.T) {
+	f := ETFFlow{Asset: " btc ", Date: time.Date(2024, 3, 1, 21, 0, 0, 0, time.FixedZone("EST", -5*3600))}
+	f.Normalize()
+	if f.Asset != "BTC" || !f.Date.Equal(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected normalized flow: %+v", f)
+	}
+	if err := f.Validate(); err != nil {
+		t.Errorf("Expected a valid flow, got %v", err)
+	}
+
+	for _, bad := range []ETFFlow{{Asset: "SOL", Date: f.Date}, {Asset: "ETH"}} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", bad)
+		}
+	}
+}
+
+func TestSummarizeETFFlows(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	flows := []ETFFlow{
+		{Date: day(1).AddDate(0, -1, 0), NetFlow: 1000},
+		{Date: day(1), NetFlow: 500},
+		{Date: day(20), NetFlow: 100},
+		{Date: day(26), NetFlow: -50},
+		{Date: day(27), NetFlow: -20},
+		{Date: day(28), NetFlow: -30},
+	}
+
+	summary, ok := SummarizeETFFlows("BTC", flows)
+	if !ok {
+		t.Fatal("Expected a summary")
+	}
+	want := ETFFlowSummary{Asset: "BTC", Date: day(28), Latest: -30, Week: -100, Month: 500, Streak: -3}
+	if summary != want {
+		t.Errorf("Expected %+v, got %+v", want, summary)
+	}
+
+	if _, ok := SummarizeETFFlows("ETH", nil); ok {
+		t.Error("Expected no summary without flows")
+	}
+}
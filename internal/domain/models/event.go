@@ -0,0 +1,98 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EventKind categorizes calendar events
+type EventKind string
+
+// Supported event kinds
+const (
+	EventUnlock   EventKind = "unlock"
+	EventListing  EventKind = "listing"
+	EventUpgrade  EventKind = "upgrade"
+	EventReminder EventKind = "reminder"
+	EventOther    EventKind = "other"
+)
+
+// Event is a scheduled market event, such as a token unlock, or a user reminder.
+// Events of the DefaultOwner are shared with every session.
+type Event struct {
+	ID          string    `json:"id"`
+	Owner       string    `json:"owner"`
+	Kind        EventKind `json:"kind"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	CryptoID    string    `json:"crypto_id,omitempty"`
+	Start       time.Time `json:"start"`
+	// End is optional; all-day events last until the end of their start day
+	End    *time.Time `json:"end,omitempty"`
+	AllDay bool       `json:"all_day"`
+	// RemindMinutes raises an alarm this many minutes before the start; zero disables it
+	RemindMinutes int `json:"remind_minutes,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Normalize trims text fields, lowercases the coin ID and applies the default kind and owner
+func (e *Event) Normalize() {
+	e.Title = strings.TrimSpace(e.Title)
+	e.Owner = strings.TrimSpace(e.Owner)
+	if e.Owner == "" {
+		e.Owner = DefaultOwner
+	}
+	e.CryptoID = strings.ToLower(strings.TrimSpace(e.CryptoID))
+	if e.Kind == "" {
+		e.Kind = EventOther
+	}
+	if e.AllDay {
+		e.Start = time.Date(e.Start.Year(), e.Start.Month(), e.Start.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// Validate ensures that the Event entity is valid
+func (e *Event) Validate() error {
+	switch e.Kind {
+	case EventUnlock, EventListing, EventUpgrade, EventReminder, EventOther:
+	default:
+		return fmt.Errorf("unknown event kind: %q", e.Kind)
+	}
+	if e.Title == "" {
+		return errors.New("event title cannot be empty")
+	}
+	if e.Start.IsZero() {
+		return errors.New("event start is required")
+	}
+	if e.End != nil && e.End.Before(e.Start) {
+		return errors.New("event end cannot be before its start")
+	}
+	if e.RemindMinutes < 0 {
+		return errors.New("event reminder cannot be negative")
+	}
+	return nil
+}
+
+// Overlaps reports whether the event happens during [from, to)
+func (e *Event) Overlaps(from, to time.Time) bool {
+	if !e.Start.Before(to) {
+		return false
+	}
+	// Instantaneous events count when they start inside the range
+	return !e.Start.Before(from) || e.Finish().After(from)
+}
+
+// Finish returns the end of the event: its End when set, the following
+// midnight for all-day events and the start otherwise
+func (e *Event) Finish() time.Time {
+	switch {
+	case e.End != nil:
+		return *e.End
+	case e.AllDay:
+		return e.Start.AddDate(0, 0, 1)
+	}
+	return e.Start
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvent_Validate(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	before := start.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		event   Event
+		wantErr bool
+	}{
+		{name: "valid", event: Event{Title: "ARB unlock", Kind: EventUnlock, Start: start}},
+		{name: "missing title", event: Event{Kind: EventUnlock, Start: start}, wantErr: true},
+		{name: "missing start", event: Event{Title: "x", Kind: EventOther}, wantErr: true},
+		{name: "unknown kind", event: Event{Title: "x", Kind: "party", Start: start}, wantErr: true},
+		{name: "end before start", event: Event{Title: "x", Kind: EventOther, Start: start, End: &before}, wantErr: true},
+		{name: "negative reminder", event: Event{Title: "x", Kind: EventOther, Start: start, RemindMinutes: -5}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.event.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvent_Normalize(t *testing.T) {
+	e := Event{Title: "  Unlock ", CryptoID: " ARB ", AllDay: true, Start: time.Date(2024, 6, 1, 15, 30, 0, 0, time.UTC)}
+	e.Normalize()
+	if e.Title != "Unlock" || e.CryptoID != "arb" || e.Owner != DefaultOwner || e.Kind != EventOther {
+		t.Errorf("Unexpected normalized event: %+v", e)
+	}
+	if e.Start.Hour() != 0 || e.Start.Minute() != 0 {
+		t.Errorf("Expected all-day event to start at midnight, got %s", e.Start)
+	}
+}
+
+func TestEvent_Overlaps(t *testing.T) {
+	day := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	allDay := Event{Start: day, AllDay: true}
+	instant := Event{Start: day.Add(12 * time.Hour)}
+
+	if !allDay.Overlaps(day.Add(6*time.Hour), day.Add(7*time.Hour)) {
+		t.Error("Expected all-day event to overlap a range inside its day")
+	}
+	if allDay.Overlaps(day.AddDate(0, 0, 1), day.AddDate(0, 0, 2)) {
+		t.Error("Expected all-day event not to overlap the following day")
+	}
+	if !instant.Overlaps(day, day.AddDate(0, 0, 1)) {
+		t.Error("Expected instantaneous event inside the range to overlap")
+	}
+	if instant.Overlaps(day.Add(13*time.Hour), day.AddDate(0, 0, 1)) {
+		t.Error("Expected past instantaneous event not to overlap")
+	}
+}
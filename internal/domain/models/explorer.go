@@ -0,0 +1,91 @@
+package models
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Placeholders substituted in explorer URL templates
+const (
+	AddressPlaceholder = "{address}"
+	TxPlaceholder      = "{tx}"
+)
+
+// Explorer is a block explorer with URL templates for addresses and transactions
+type Explorer struct {
+	Name       string `json:"name"`
+	AddressURL string `json:"address_url"`
+	TxURL      string `json:"tx_url"`
+}
+
+// AddressLink returns the explorer URL of an address
+func (e Explorer) AddressLink(address string) string {
+	return strings.ReplaceAll(e.AddressURL, AddressPlaceholder, url.PathEscape(address))
+}
+
+// TxLink returns the explorer URL of a transaction
+func (e Explorer) TxLink(hash string) string {
+	return strings.ReplaceAll(e.TxURL, TxPlaceholder, url.PathEscape(hash))
+}
+
+// explorers maps CoinGecko IDs of native chain coins to their explorers, preferred first
+var explorers = map[string][]Explorer{
+	"bitcoin": {
+		{Name: "mempool.space", AddressURL: "https://mempool.space/address/{address}", TxURL: "https://mempool.space/tx/{tx}"},
+		{Name: "Blockstream", AddressURL: "https://blockstream.info/address/{address}", TxURL: "https://blockstream.info/tx/{tx}"},
+	},
+	"ethereum": {
+		{Name: "Etherscan", AddressURL: "https://etherscan.io/address/{address}", TxURL: "https://etherscan.io/tx/{tx}"},
+	},
+	"litecoin": {
+		{Name: "litecoinspace", AddressURL: "https://litecoinspace.org/address/{address}", TxURL: "https://litecoinspace.org/tx/{tx}"},
+	},
+	"dogecoin": {
+		{Name: "Blockchair", AddressURL: "https://blockchair.com/dogecoin/address/{address}", TxURL: "https://blockchair.com/dogecoin/transaction/{tx}"},
+	},
+	"solana": {
+		{Name: "Solscan", AddressURL: "https://solscan.io/account/{address}", TxURL: "https://solscan.io/tx/{tx}"},
+	},
+	"cardano": {
+		{Name: "Cardanoscan", AddressURL: "https://cardanoscan.io/address/{address}", TxURL: "https://cardanoscan.io/transaction/{tx}"},
+	},
+	"ripple": {
+		{Name: "XRPSCAN", AddressURL: "https://xrpscan.com/account/{address}", TxURL: "https://xrpscan.com/tx/{tx}"},
+	},
+	"tron": {
+		{Name: "Tronscan", AddressURL: "https://tronscan.org/#/address/{address}", TxURL: "https://tronscan.org/#/transaction/{tx}"},
+	},
+	"binancecoin": {
+		{Name: "BscScan", AddressURL: "https://bscscan.com/address/{address}", TxURL: "https://bscscan.com/tx/{tx}"},
+	},
+	"matic-network": {
+		{Name: "PolygonScan", AddressURL: "https://polygonscan.com/address/{address}", TxURL: "https://polygonscan.com/tx/{tx}"},
+	},
+	"avalanche-2": {
+		{Name: "Snowtrace", AddressURL: "https://snowtrace.io/address/{address}", TxURL: "https://snowtrace.io/tx/{tx}"},
+	},
+}
+
+// ExplorersFor returns the known explorers of a coin's chain, or nil
+func ExplorersFor(cryptoID string) []Explorer {
+	return append([]Explorer(nil), explorers[strings.ToLower(cryptoID)]...)
+}
+
+// paymentSchemes maps CoinGecko IDs to the URI scheme wallets open for payments (BIP 21, EIP 681)
+var paymentSchemes = map[string]string{
+	"bitcoin":      "bitcoin",
+	"bitcoin-cash": "bitcoincash",
+	"litecoin":     "litecoin",
+	"dogecoin":     "dogecoin",
+	"ethereum":     "ethereum",
+}
+
+// PaymentURI returns the payment URI of an address that wallets can scan.
+// Coins without a known scheme get the bare address, which most wallets accept.
+func PaymentURI(cryptoID, address string) string {
+	scheme, ok := paymentSchemes[strings.ToLower(cryptoID)]
+	if !ok || strings.HasPrefix(address, scheme+":") {
+		return address
+	}
+	return scheme + ":" + address
+}
@@ -0,0 +1,40 @@
+package models
+
+import "testing"
+
+func TestExplorersFor(t *testing.T) {
+	btc := ExplorersFor("Bitcoin")
+	if len(btc) == 0 || btc[0].Name != "mempool.space" {
+		t.Fatalf("Expected mempool.space first for bitcoin, got %+v", btc)
+	}
+	if got := btc[0].AddressLink("bc1qexample"); got != "https://mempool.space/address/bc1qexample" {
+		t.Errorf("Unexpected address link %s", got)
+	}
+	if got := btc[0].TxLink("ab/cd"); got != "https://mempool.space/tx/ab%2Fcd" {
+		t.Errorf("Expected the hash to be escaped, got %s", got)
+	}
+
+	btc[0].Name = "changed"
+	if ExplorersFor("bitcoin")[0].Name != "mempool.space" {
+		t.Error("Expected callers not to modify the registry")
+	}
+	if ExplorersFor("unknown-token") != nil {
+		t.Error("Expected no explorers for an unknown coin")
+	}
+}
+
+func TestPaymentURI(t *testing.T) {
+	tests := []struct {
+		id, address, want string
+	}{
+		{"Bitcoin", "bc1qexample", "bitcoin:bc1qexample"},
+		{"ethereum", "0xabc", "ethereum:0xabc"},
+		{"bitcoin", "bitcoin:bc1qexample", "bitcoin:bc1qexample"},
+		{"solana", "So1anaAddr", "So1anaAddr"},
+	}
+	for _, tt := range tests {
+		if got := PaymentURI(tt.id, tt.address); got != tt.want {
+			t.Errorf("PaymentURI(%s, %s) = %s, want %s", tt.id, tt.address, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// GlobalMarket is the aggregate state of the whole crypto market
+type GlobalMarket struct {
+	Currency       Currency `json:"currency"`
+	TotalMarketCap float64  `json:"total_market_cap"`
+	TotalVolume    float64  `json:"total_volume"`
+	// MarketCapChange24h is the 24h change of the total market cap, in percent
+	MarketCapChange24h float64 `json:"market_cap_change_percentage_24h"`
+	// BTCDominance and ETHDominance are shares of the total market cap, in percent
+	BTCDominance float64   `json:"btc_dominance"`
+	ETHDominance float64   `json:"eth_dominance"`
+	ActiveCoins  int       `json:"active_coins"`
+	Markets      int       `json:"markets"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	// Stale marks a cached overview served because refreshing it failed
+	Stale bool `json:"stale,omitempty"`
+}
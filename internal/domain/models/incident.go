@@ -0,0 +1,89 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// IncidentKind categorizes incidents
+type IncidentKind string
+
+// Supported incident kinds
+const (
+	// IncidentOutage is recorded automatically while the price provider keeps failing
+	IncidentOutage IncidentKind = "provider_outage"
+	// IncidentDataQuality marks a range of prices known to be wrong or missing
+	IncidentDataQuality IncidentKind = "data_quality"
+)
+
+// Incident is a period during which prices were unavailable or unreliable.
+// Charts shade the time ranges of the incidents returned with history queries.
+type Incident struct {
+	ID   string       `json:"id"`
+	Kind IncidentKind `json:"kind"`
+	// CryptoID limits the incident to one coin; empty affects every coin
+	CryptoID    string `json:"crypto_id,omitempty"`
+	Description string `json:"description"`
+	// Failures is the number of failed polls of an outage
+	Failures int       `json:"failures,omitempty"`
+	Started  time.Time `json:"started"`
+	// Resolved is nil while the incident is ongoing
+	Resolved *time.Time `json:"resolved,omitempty"`
+}
+
+// Normalize trims text fields, lowercases the coin ID and applies the default kind
+func (i *Incident) Normalize() {
+	i.Description = strings.TrimSpace(i.Description)
+	i.CryptoID = strings.ToLower(strings.TrimSpace(i.CryptoID))
+	if i.Kind == "" {
+		i.Kind = IncidentDataQuality
+	}
+}
+
+// Validate ensures that the Incident entity is valid
+func (i *Incident) Validate() error {
+	switch i.Kind {
+	case IncidentOutage, IncidentDataQuality:
+	default:
+		return fmt.Errorf("unknown incident kind: %q", i.Kind)
+	}
+	if i.Description == "" {
+		return errors.New("incident description cannot be empty")
+	}
+	if i.Started.IsZero() {
+		return errors.New("incident start is required")
+	}
+	if i.Resolved != nil && i.Resolved.Before(i.Started) {
+		return errors.New("incident cannot be resolved before it started")
+	}
+	return nil
+}
+
+// Ongoing reports whether the incident has not been resolved yet
+func (i Incident) Ongoing() bool {
+	return i.Resolved == nil
+}
+
+// Duration returns how long the incident lasted, or has lasted so far at now
+func (i Incident) Duration(now time.Time) time.Duration {
+	if i.Resolved != nil {
+		return i.Resolved.Sub(i.Started)
+	}
+	return now.Sub(i.Started)
+}
+
+// Affects reports whether the incident concerns the coin
+func (i Incident) Affects(cryptoID string) bool {
+	return i.CryptoID == "" || cryptoID == "" || i.CryptoID == cryptoID
+}
+
+// Overlaps reports whether the incident was ongoing at some point of [from, to).
+// A zero to leaves the range open.
+func (i Incident) Overlaps(from, to time.Time) bool {
+	if !to.IsZero() && !i.Started.Before(to) {
+		return false
+	}
+	return i.Resolved == nil || i.Resolved.After(from)
+}
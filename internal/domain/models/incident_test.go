@@ -0,0 +1,61 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIncident_Duration(t *testing.T) {
+	started := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	incident := Incident{Started: started}
+	if !incident.Ongoing() || incident.Duration(started.Add(time.Hour)) != time.Hour {
+		t.Errorf("Expected an ongoing incident of 1h, got %v", incident.Duration(started.Add(time.Hour)))
+	}
+
+	resolved := started.Add(10 * time.Minute)
+	incident.Resolved = &resolved
+	if incident.Ongoing() || incident.Duration(started.Add(time.Hour)) != 10*time.Minute {
+		t.Errorf("Expected a resolved incident of 10m, got %v", incident.Duration(started.Add(time.Hour)))
+	}
+}
+
+func TestIncident_Validate(t *testing.T) {
+	started := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	before := started.Add(-time.Minute)
+
+	incident := Incident{CryptoID: " Bitcoin ", Description: " Wrong prints ", Started: started}
+	incident.Normalize()
+	if err := incident.Validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if incident.Kind != IncidentDataQuality || incident.CryptoID != "bitcoin" || incident.Description != "Wrong prints" {
+		t.Errorf("Unexpected normalized incident: %+v", incident)
+	}
+
+	for name, invalid := range map[string]Incident{
+		"unknown kind":     {Kind: "bug", Description: "x", Started: started},
+		"no description":   {Kind: IncidentOutage, Started: started},
+		"no start":         {Kind: IncidentOutage, Description: "x"},
+		"resolved earlier": {Kind: IncidentOutage, Description: "x", Started: started, Resolved: &before},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestIncident_OverlapsAndAffects(t *testing.T) {
+	started := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	resolved := started.Add(time.Hour)
+	incident := Incident{CryptoID: "bitcoin", Started: started, Resolved: &resolved}
+
+	if !incident.Overlaps(started.Add(30*time.Minute), started.Add(2*time.Hour)) {
+		t.Error("Expected an overlap with a range starting during the incident")
+	}
+	if incident.Overlaps(resolved, resolved.Add(time.Hour)) || incident.Overlaps(started.Add(-time.Hour), started) {
+		t.Error("Expected no overlap with adjacent ranges")
+	}
+	if !incident.Affects("bitcoin") || incident.Affects("ethereum") || !(Incident{}).Affects("ethereum") {
+		t.Error("Expected only coin-wide or matching incidents to affect a coin")
+	}
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MarketField names a numeric CryptoPrice field that batches can be sorted and filtered by
+type MarketField string
+
+// Supported market fields
+const (
+	FieldPrice             MarketField = "price"
+	FieldMarketCap         MarketField = "market_cap"
+	FieldVolume24h         MarketField = "volume_24h"
+	FieldChange24h         MarketField = "change_24h"
+	FieldChange7d          MarketField = "change_7d"
+	FieldCirculatingSupply MarketField = "circulating_supply"
+	FieldATH               MarketField = "ath"
+	FieldATHDistance       MarketField = "ath_distance"
+)
+
+// ParseMarketField validates a market field name
+func ParseMarketField(name string) (MarketField, error) {
+	field := MarketField(strings.ToLower(strings.TrimSpace(name)))
+	switch field {
+	case FieldPrice, FieldMarketCap, FieldVolume24h, FieldChange24h, FieldChange7d,
+		FieldCirculatingSupply, FieldATH, FieldATHDistance:
+		return field, nil
+	}
+	return "", fmt.Errorf("unknown market field: %q", name)
+}
+
+// Field returns the value of a market field
+func (c *CryptoPrice) Field(field MarketField) float64 {
+	switch field {
+	case FieldPrice:
+		return c.PriceFloat()
+	case FieldMarketCap:
+		return c.MarketCap
+	case FieldVolume24h:
+		return c.Volume24h
+	case FieldChange24h:
+		return c.PriceChange24h
+	case FieldChange7d:
+		return c.PriceChange7d
+	case FieldCirculatingSupply:
+		return c.CirculatingSupply
+	case FieldATH:
+		return c.ATH
+	case FieldATHDistance:
+		return c.ATHDistance()
+	}
+	return 0
+}
+
+// ATHDistance returns how far the current price is below the all-time high, in percent.
+// It is 0 when the all-time high is unknown.
+func (c *CryptoPrice) ATHDistance() float64 {
+	if c.ATH <= 0 {
+		return 0
+	}
+	return (c.PriceFloat()/c.ATH - 1) * 100
+}
+
+// SortBy orders the batch by a market field. The sort is stable so equal values keep their order.
+func (b *CryptoBatch) SortBy(field MarketField, descending bool) {
+	sort.SliceStable(b.Prices, func(i, j int) bool {
+		if descending {
+			return b.Prices[i].Field(field) > b.Prices[j].Field(field)
+		}
+		return b.Prices[i].Field(field) < b.Prices[j].Field(field)
+	})
+}
+
+// Filter returns a new batch with the prices that match the predicate
+func (b *CryptoBatch) Filter(match func(CryptoPrice) bool) CryptoBatch {
+	var filtered CryptoBatch
+	for _, crypto := range b.Prices {
+		if match(crypto) {
+			filtered.AddCrypto(crypto)
+		}
+	}
+	return filtered
+}
+
+// FilterRange returns a new batch with the prices whose field lies within [min, max]
+func (b *CryptoBatch) FilterRange(field MarketField, min, max float64) CryptoBatch {
+	return b.Filter(func(c CryptoPrice) bool {
+		v := c.Field(field)
+		return v >= min && v <= max
+	})
+}
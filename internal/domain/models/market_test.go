@@ -0,0 +1,85 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func marketBatch() CryptoBatch {
+	return CryptoBatch{Prices: []CryptoPrice{
+		{ID: "bitcoin", CurrentPrice: decimal.NewFromInt(60000), MarketCap: 1.2e12, Volume24h: 3e10, PriceChange24h: 1.5, PriceChange7d: -2, ATH: 73000},
+		{ID: "ethereum", CurrentPrice: decimal.NewFromInt(3000), MarketCap: 3.6e11, Volume24h: 1.5e10, PriceChange24h: -3, PriceChange7d: 4, ATH: 4800},
+		{ID: "solana", CurrentPrice: decimal.NewFromInt(150), MarketCap: 7e10, Volume24h: 3e9, PriceChange24h: 6, PriceChange7d: 12, ATH: 260},
+	}}
+}
+
+func ids(b CryptoBatch) []string {
+	out := make([]string, len(b.Prices))
+	for i, p := range b.Prices {
+		out[i] = p.ID
+	}
+	return out
+}
+
+func TestParseMarketField(t *testing.T) {
+	if f, err := ParseMarketField(" Market_Cap "); err != nil || f != FieldMarketCap {
+		t.Errorf("Expected market_cap, got %q (%v)", f, err)
+	}
+	if _, err := ParseMarketField("rank"); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+}
+
+func TestCryptoBatch_SortBy(t *testing.T) {
+	tests := []struct {
+		field      MarketField
+		descending bool
+		expected   string
+	}{
+		{FieldMarketCap, true, "bitcoin,ethereum,solana"},
+		{FieldChange24h, false, "ethereum,bitcoin,solana"},
+		{FieldChange7d, true, "solana,ethereum,bitcoin"},
+		{FieldATHDistance, false, "solana,ethereum,bitcoin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.field), func(t *testing.T) {
+			batch := marketBatch()
+			batch.SortBy(tt.field, tt.descending)
+			got := ids(batch)
+			if joined := got[0] + "," + got[1] + "," + got[2]; joined != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, joined)
+			}
+		})
+	}
+}
+
+func TestCryptoBatch_FilterRange(t *testing.T) {
+	batch := marketBatch()
+
+	gainers := batch.FilterRange(FieldChange24h, 0, math.Inf(1))
+	if len(gainers.Prices) != 2 {
+		t.Errorf("Expected 2 gainers, got %v", ids(gainers))
+	}
+
+	large := batch.FilterRange(FieldMarketCap, 1e11, math.Inf(1))
+	if len(large.Prices) != 2 || large.Prices[1].ID != "ethereum" {
+		t.Errorf("Expected bitcoin and ethereum, got %v", ids(large))
+	}
+	if len(batch.Prices) != 3 {
+		t.Error("Expected filtering to leave the original batch untouched")
+	}
+}
+
+func TestCryptoPrice_ATHDistance(t *testing.T) {
+	c := CryptoPrice{CurrentPrice: decimal.NewFromInt(50), ATH: 200}
+	if d := c.ATHDistance(); d != -75 {
+		t.Errorf("Expected -75%%, got %f", d)
+	}
+	c.ATH = 0
+	if d := c.ATHDistance(); d != 0 {
+		t.Errorf("Expected 0 without ATH, got %f", d)
+	}
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// OptInChannel is a messaging channel that may only reach recipients who
+// explicitly agreed to receive alerts on it
+type OptInChannel string
+
+// Supported opt-in channels
+const (
+	ChannelWhatsApp OptInChannel = "whatsapp"
+)
+
+// OptInChannels lists the supported channels
+var OptInChannels = []OptInChannel{ChannelWhatsApp}
+
+// ParseOptInChannel validates a channel name
+func ParseOptInChannel(name string) (OptInChannel, error) {
+	channel := OptInChannel(strings.ToLower(strings.TrimSpace(name)))
+	for _, c := range OptInChannels {
+		if c == channel {
+			return channel, nil
+		}
+	}
+	return "", fmt.Errorf("unknown opt-in channel: %q", name)
+}
+
+var (
+	// phoneNumber is an international number without the leading + (E.164)
+	phoneNumber = regexp.MustCompile(`^[1-9][0-9]{7,14}$`)
+	// languageCode is a WhatsApp template language such as pt_BR or en
+	languageCode = regexp.MustCompile(`^[a-z]{2,3}(_[A-Z]{2})?$`)
+)
+
+// OptIn records that a recipient agreed to receive alerts on a channel
+type OptIn struct {
+	// Owner is the session that requested the opt-in; only it may list or withdraw it
+	Owner   string       `json:"owner"`
+	Channel OptInChannel `json:"channel"`
+	// Recipient is the address on the channel; for WhatsApp the phone number
+	// with country code and without the leading +
+	Recipient string `json:"recipient"`
+	// Language overrides the language of the message templates, e.g. pt_BR
+	Language string `json:"language,omitempty"`
+	// OptedInAt is when the recipient confirmed the opt-in
+	OptedInAt time.Time `json:"opted_in_at"`
+}
+
+// Normalize strips the formatting people type into phone numbers, e.g. "+55 (11) 98765-4321"
+func (o *OptIn) Normalize() {
+	o.Recipient = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(" +-().", r) {
+			return -1
+		}
+		return r
+	}, o.Recipient)
+	o.Language = strings.TrimSpace(o.Language)
+}
+
+// Validate ensures that the OptIn entity is valid
+func (o *OptIn) Validate() error {
+	if _, err := ParseOptInChannel(string(o.Channel)); err != nil {
+		return err
+	}
+	if !phoneNumber.MatchString(o.Recipient) {
+		return fmt.Errorf("recipient must be a phone number with country code, got %q", o.Recipient)
+	}
+	if o.Language != "" && !languageCode.MatchString(o.Language) {
+		return fmt.Errorf("language must be a code like pt_BR or en, got %q", o.Language)
+	}
+	return nil
+}
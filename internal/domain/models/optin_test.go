@@ -0,0 +1,35 @@
+package models
+
+import "testing"
+
+func TestOptIn_NormalizeAndValidate(t *testing.T) {
+	o := OptIn{Channel: ChannelWhatsApp, Recipient: "+55 (11) 98765-4321", Language: " pt_BR "}
+	o.Normalize()
+	if o.Recipient != "5511987654321" || o.Language != "pt_BR" {
+		t.Fatalf("Unexpected normalized opt-in: %+v", o)
+	}
+	if err := o.Validate(); err != nil {
+		t.Errorf("Expected a valid opt-in, got %v", err)
+	}
+
+	invalid := []OptIn{
+		{Channel: "sms", Recipient: "5511987654321"},
+		{Channel: ChannelWhatsApp, Recipient: "12345"},
+		{Channel: ChannelWhatsApp, Recipient: "0511987654321"},
+		{Channel: ChannelWhatsApp, Recipient: "5511987654321", Language: "portuguese"},
+	}
+	for _, o := range invalid {
+		if err := o.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", o)
+		}
+	}
+}
+
+func TestParseOptInChannel(t *testing.T) {
+	if c, err := ParseOptInChannel(" WhatsApp "); err != nil || c != ChannelWhatsApp {
+		t.Errorf("Expected whatsapp, got %q (%v)", c, err)
+	}
+	if _, err := ParseOptInChannel("pager"); err == nil {
+		t.Error("Expected an error for an unknown channel")
+	}
+}
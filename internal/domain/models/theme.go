@@ -0,0 +1,108 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ThemeMode is a base color scheme of the dashboard
+type ThemeMode string
+
+// Supported theme modes
+const (
+	ThemeDark         ThemeMode = "dark"
+	ThemeLight        ThemeMode = "light"
+	ThemeHighContrast ThemeMode = "high-contrast"
+)
+
+// ThemeModes lists the supported modes in display order
+var ThemeModes = []ThemeMode{ThemeDark, ThemeLight, ThemeHighContrast}
+
+// Palette maps CSS custom property names, without the leading dashes, to colors
+type Palette map[string]string
+
+// basePalettes holds the colors of every mode. Each palette defines the same variables.
+var basePalettes = map[ThemeMode]Palette{
+	ThemeDark: {
+		"bg": "#0f1115", "panel": "#171a21", "border": "#262a33", "text": "#e6e6e6",
+		"muted": "#8a8f98", "accent": "#4f8cff", "up": "#2ecc71", "down": "#e74c3c",
+	},
+	ThemeLight: {
+		"bg": "#f5f6f8", "panel": "#ffffff", "border": "#d9dce1", "text": "#1b1e24",
+		"muted": "#5f6670", "accent": "#2f6fe4", "up": "#1e9e55", "down": "#c9372c",
+	},
+	ThemeHighContrast: {
+		"bg": "#000000", "panel": "#000000", "border": "#ffffff", "text": "#ffffff",
+		"muted": "#d0d0d0", "accent": "#ffd400", "up": "#00ff66", "down": "#ff4040",
+	},
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-f]{3}|[0-9a-f]{6})$`)
+
+// Theme is an owner's dashboard appearance: a base mode and optional color overrides
+type Theme struct {
+	Owner string    `json:"owner"`
+	Mode  ThemeMode `json:"mode"`
+	// Accent replaces the accent color of the mode when set
+	Accent string `json:"accent,omitempty"`
+	// Up and Down replace the colors of gains and losses when set
+	Up   string `json:"up,omitempty"`
+	Down string `json:"down,omitempty"`
+	// Privacy hides absolute portfolio values, leaving percentages, e.g. while
+	// screen-sharing. It is toggled on its own and kept when the colors change.
+	Privacy   bool      `json:"privacy"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DefaultTheme returns the theme used for owners who never chose one
+func DefaultTheme(owner string) Theme {
+	return Theme{Owner: owner, Mode: ThemeDark}
+}
+
+// Normalize lowercases colors and the mode and defaults the mode to dark
+func (t *Theme) Normalize() {
+	t.Mode = ThemeMode(strings.ToLower(strings.TrimSpace(string(t.Mode))))
+	if t.Mode == "" {
+		t.Mode = ThemeDark
+	}
+	for _, c := range []*string{&t.Accent, &t.Up, &t.Down} {
+		*c = strings.ToLower(strings.TrimSpace(*c))
+	}
+}
+
+// Validate ensures that the Theme entity is valid
+func (t *Theme) Validate() error {
+	if _, ok := basePalettes[t.Mode]; !ok {
+		return fmt.Errorf("unknown theme mode: %q", t.Mode)
+	}
+	for _, c := range []string{t.Accent, t.Up, t.Down} {
+		if c != "" && !hexColor.MatchString(c) {
+			return errors.New("theme colors must be hex colors like #4f8cff")
+		}
+	}
+	return nil
+}
+
+// Palette returns the colors of the theme's mode with its overrides applied
+func (t Theme) Palette() Palette {
+	palette := BasePalette(t.Mode)
+	for name, c := range map[string]string{"accent": t.Accent, "up": t.Up, "down": t.Down} {
+		if c != "" {
+			palette[name] = c
+		}
+	}
+	return palette
+}
+
+// BasePalette returns a copy of the colors of a mode, or of the dark mode when it is unknown
+func BasePalette(mode ThemeMode) Palette {
+	base, ok := basePalettes[mode]
+	if !ok {
+		base = basePalettes[ThemeDark]
+	}
+	return maps.Clone(base)
+}
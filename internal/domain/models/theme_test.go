@@ -0,0 +1,41 @@
+package models
+
+import "testing"
+
+func TestTheme_Validate(t *testing.T) {
+	theme := Theme{Mode: " Light ", Accent: "#FF8800"}
+	theme.Normalize()
+	if err := theme.Validate(); err != nil {
+		t.Fatalf("Expected a valid theme, got %v", err)
+	}
+	if theme.Mode != ThemeLight || theme.Accent != "#ff8800" {
+		t.Errorf("Expected normalized mode and accent, got %+v", theme)
+	}
+
+	for _, bad := range []Theme{
+		{Mode: "sepia"},
+		{Mode: ThemeDark, Accent: "orange"},
+		{Mode: ThemeDark, Up: "#12345"},
+		{Mode: ThemeDark, Down: "#fff;}body{"},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", bad)
+		}
+	}
+}
+
+func TestTheme_Palette(t *testing.T) {
+	theme := Theme{Mode: ThemeHighContrast, Accent: "#00ffff"}
+	palette := theme.Palette()
+	if palette["accent"] != "#00ffff" || palette["bg"] != "#000000" {
+		t.Errorf("Expected the accent override on the high-contrast palette, got %v", palette)
+	}
+
+	palette["bg"] = "#123456"
+	if BasePalette(ThemeHighContrast)["bg"] != "#000000" {
+		t.Error("Expected callers not to modify the base palettes")
+	}
+	if len(BasePalette(ThemeLight)) != len(BasePalette(ThemeDark)) {
+		t.Error("Expected every mode to define the same variables")
+	}
+}
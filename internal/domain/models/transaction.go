@@ -0,0 +1,177 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TransactionType identifies the direction of a trade, a migration or income
+type TransactionType string
+
+const (
+	// TransactionBuy adds coins to a position
+	TransactionBuy TransactionType = "buy"
+	// TransactionSell removes coins from a position
+	TransactionSell TransactionType = "sell"
+	// TransactionMigrate converts a whole position into another coin, or the
+	// same coin with new units, at a fixed ratio: a token swap, redenomination
+	// or ticker rename. Cost basis and acquisition dates carry over.
+	TransactionMigrate TransactionType = "migrate"
+	// TransactionAirdrop receives coins for free from a token distribution
+	TransactionAirdrop TransactionType = "airdrop"
+	// TransactionStakingReward receives coins for staking or validating
+	TransactionStakingReward TransactionType = "staking_reward"
+	// TransactionInterest receives coins as interest from lending or savings products
+	TransactionInterest TransactionType = "interest"
+)
+
+// IncomeTypes are the transaction types that receive coins as income. They are
+// valued at the price on receipt, which becomes their cost basis.
+var IncomeTypes = []TransactionType{TransactionAirdrop, TransactionStakingReward, TransactionInterest}
+
+// Fee is the cost charged by an exchange for a transaction.
+// Currency is either a fiat code (e.g. "usd") or the crypto ID of the traded coin
+// when the exchange deducts the fee from the coins themselves. An empty currency
+// means the fee was paid in the transaction currency.
+type Fee struct {
+	Amount   decimal.Decimal `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// Transaction is a single entry of the portfolio ledger: a buy, a sell, a
+// migration or coins received as income
+type Transaction struct {
+	ID        string          `json:"id"`
+	CryptoID  string          `json:"crypto_id"`
+	Type      TransactionType `json:"type"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	Price     decimal.Decimal `json:"price"`
+	Currency  Currency        `json:"currency"`
+	Fee       Fee             `json:"fee"`
+	Exchange  string          `json:"exchange"`
+	Timestamp time.Time       `json:"timestamp"`
+	// ToCryptoID and Ratio describe a migration: every unit of CryptoID becomes
+	// Ratio units of ToCryptoID. ToCryptoID defaults to CryptoID for redenominations.
+	ToCryptoID string           `json:"to_crypto_id,omitempty"`
+	Ratio      *decimal.Decimal `json:"ratio,omitempty"`
+}
+
+// Validate ensures that the Transaction entity is valid
+func (t *Transaction) Validate() error {
+	if t.CryptoID == "" {
+		return errors.New("transaction crypto ID cannot be empty")
+	}
+	if t.IsMigration() {
+		return t.validateMigration()
+	}
+	if t.Type != TransactionBuy && t.Type != TransactionSell && !t.IsIncome() {
+		return errors.New("transaction type must be buy, sell, migrate, airdrop, staking_reward or interest")
+	}
+	if t.IsIncome() && !t.Fee.Amount.IsZero() {
+		return errors.New("income cannot carry a fee; record the net amount received")
+	}
+	if t.ToCryptoID != "" || t.Ratio != nil {
+		return errors.New("only migrations can set to_crypto_id and ratio")
+	}
+	if !t.Quantity.IsPositive() {
+		return errors.New("transaction quantity must be positive")
+	}
+	if t.Price.IsNegative() {
+		return errors.New("transaction price cannot be negative")
+	}
+	if t.Fee.Amount.IsNegative() {
+		return errors.New("transaction fee cannot be negative")
+	}
+	if t.Type == TransactionBuy && t.FeeInCrypto() && t.Fee.Amount.GreaterThanOrEqual(t.Quantity) {
+		return fmt.Errorf("fee of %s %s leaves nothing of the %s bought", t.Fee.Amount, t.CryptoID, t.Quantity)
+	}
+	if t.Timestamp.IsZero() {
+		return errors.New("transaction timestamp cannot be empty")
+	}
+	return nil
+}
+
+// validateMigration checks the fields of a migration, which converts the whole
+// position and therefore has no quantity, price or fee of its own
+func (t *Transaction) validateMigration() error {
+	if t.Ratio == nil || !t.Ratio.IsPositive() {
+		return errors.New("migration ratio must be positive")
+	}
+	if t.Target() == t.CryptoID && t.Ratio.Equal(decimal.NewFromInt(1)) {
+		return fmt.Errorf("migration of %s to itself at a 1:1 ratio changes nothing", t.CryptoID)
+	}
+	if !t.Quantity.IsZero() || !t.Price.IsZero() || !t.Fee.Amount.IsZero() {
+		return errors.New("migration cannot set a quantity, price or fee")
+	}
+	if t.Timestamp.IsZero() {
+		return errors.New("transaction timestamp cannot be empty")
+	}
+	return nil
+}
+
+// IsMigration reports whether the transaction is a migration rather than a trade
+func (t *Transaction) IsMigration() bool {
+	return t.Type == TransactionMigrate
+}
+
+// IsIncome reports whether the transaction receives coins as income
+func (t *Transaction) IsIncome() bool {
+	return slices.Contains(IncomeTypes, t.Type)
+}
+
+// Target returns the coin a migration converts into
+func (t *Transaction) Target() string {
+	if t.ToCryptoID == "" {
+		return t.CryptoID
+	}
+	return t.ToCryptoID
+}
+
+// PriceCurrency returns the fiat currency of Price, defaulting to DefaultCurrency
+func (t *Transaction) PriceCurrency() Currency {
+	if t.Currency == "" {
+		return DefaultCurrency
+	}
+	return t.Currency
+}
+
+// FeeCurrency returns the fiat currency the fee was paid in.
+// Crypto fees are valued at the transaction price, so they share PriceCurrency.
+func (t *Transaction) FeeCurrency() Currency {
+	if t.Fee.Currency == "" || t.FeeInCrypto() {
+		return t.PriceCurrency()
+	}
+	return Currency(strings.ToLower(t.Fee.Currency))
+}
+
+// FeeInCrypto reports whether the fee was paid with the traded coin
+func (t *Transaction) FeeInCrypto() bool {
+	return t.Fee.Amount.IsPositive() && strings.EqualFold(t.Fee.Currency, t.CryptoID)
+}
+
+// FeeValue returns the fee expressed in fiat (FeeCurrency), valuing crypto fees at the transaction price
+func (t *Transaction) FeeValue() decimal.Decimal {
+	if t.FeeInCrypto() {
+		return t.Fee.Amount.Mul(t.Price)
+	}
+	return t.Fee.Amount
+}
+
+// Disposed returns the coins a sale takes from the position: the quantity
+// sold and, when the fee is paid in the coin, the fee on top of it
+func (t *Transaction) Disposed() decimal.Decimal {
+	if t.FeeInCrypto() {
+		return t.Quantity.Add(t.Fee.Amount)
+	}
+	return t.Quantity
+}
+
+// Value returns the gross fiat value of the trade, excluding fees
+func (t *Transaction) Value() decimal.Decimal {
+	return t.Quantity.Mul(t.Price)
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestTransaction_Validate(t *testing.T) {
+	now := time.Now().UTC()
+	ratio := decimal.NewFromInt(1000)
+	one := decimal.NewFromInt(1)
+	tests := []struct {
+		name    string
+		tx      Transaction
+		wantErr bool
+	}{
+		{
+			name:    "valid buy",
+			tx:      Transaction{CryptoID: "bitcoin", Type: TransactionBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(50000), Timestamp: now},
+			wantErr: false,
+		},
+		{
+			name:    "invalid - unknown type",
+			tx:      Transaction{CryptoID: "bitcoin", Type: "swap", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(50000), Timestamp: now},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - zero quantity",
+			tx:      Transaction{CryptoID: "bitcoin", Type: TransactionSell, Quantity: decimal.NewFromInt(0), Price: decimal.NewFromInt(50000), Timestamp: now},
+			wantErr: true,
+		},
+		{
+			name:    "valid airdrop without a market price",
+			tx:      Transaction{CryptoID: "uniswap", Type: TransactionAirdrop, Quantity: decimal.NewFromInt(400), Timestamp: now},
+			wantErr: false,
+		},
+		{
+			name:    "invalid - income with fee",
+			tx:      Transaction{CryptoID: "ethereum", Type: TransactionStakingReward, Quantity: decimal.NewFromFloat(0.01), Fee: Fee{Amount: decimal.NewFromInt(1)}, Timestamp: now},
+			wantErr: true,
+		},
+		{
+			name:    "valid migration",
+			tx:      Transaction{CryptoID: "matic-network", Type: TransactionMigrate, ToCryptoID: "polygon-ecosystem-token", Ratio: &one, Timestamp: now},
+			wantErr: false,
+		},
+		{
+			name:    "valid redenomination",
+			tx:      Transaction{CryptoID: "shiba", Type: TransactionMigrate, Ratio: &ratio, Timestamp: now},
+			wantErr: false,
+		},
+		{
+			name:    "invalid - migration without ratio",
+			tx:      Transaction{CryptoID: "matic-network", Type: TransactionMigrate, ToCryptoID: "polygon-ecosystem-token", Timestamp: now},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - migration to itself",
+			tx:      Transaction{CryptoID: "bitcoin", Type: TransactionMigrate, Ratio: &one, Timestamp: now},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - migration with quantity",
+			tx:      Transaction{CryptoID: "shiba", Type: TransactionMigrate, Ratio: &ratio, Quantity: decimal.NewFromInt(1), Timestamp: now},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - trade with ratio",
+			tx:      Transaction{CryptoID: "bitcoin", Type: TransactionBuy, Quantity: decimal.NewFromInt(1), Ratio: &ratio, Timestamp: now},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - crypto fee of the whole buy",
+			tx:      Transaction{CryptoID: "bitcoin", Type: TransactionBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(50000), Fee: Fee{Amount: decimal.NewFromInt(1), Currency: "bitcoin"}, Timestamp: now},
+			wantErr: true,
+		},
+		{
+			name:    "valid crypto fee larger than a sale",
+			tx:      Transaction{CryptoID: "bitcoin", Type: TransactionSell, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(50000), Fee: Fee{Amount: decimal.NewFromInt(2), Currency: "bitcoin"}, Timestamp: now},
+			wantErr: false,
+		},
+		{
+			name:    "invalid - negative fee",
+			tx:      Transaction{CryptoID: "bitcoin", Type: TransactionBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(50000), Fee: Fee{Amount: decimal.NewFromInt(-1)}, Timestamp: now},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tx.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Transaction.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTransaction_FeeValue(t *testing.T) {
+	fiatFee := Transaction{CryptoID: "bitcoin", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(50000), Fee: Fee{Amount: decimal.NewFromInt(25), Currency: "usd"}}
+	if !fiatFee.FeeValue().Equal(decimal.NewFromInt(25)) {
+		t.Errorf("Expected fiat fee of 25, got %s", fiatFee.FeeValue())
+	}
+
+	cryptoFee := Transaction{CryptoID: "bitcoin", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(50000), Fee: Fee{Amount: decimal.NewFromFloat(0.001), Currency: "bitcoin"}}
+	if !cryptoFee.FeeInCrypto() {
+		t.Error("Expected fee to be detected as crypto-denominated")
+	}
+	if !cryptoFee.FeeValue().Equal(decimal.NewFromInt(50)) {
+		t.Errorf("Expected crypto fee worth 50, got %s", cryptoFee.FeeValue())
+	}
+}
+
+func TestTransaction_Currencies(t *testing.T) {
+	tx := Transaction{CryptoID: "bitcoin", Price: decimal.NewFromInt(250000), Currency: BRL, Fee: Fee{Amount: decimal.NewFromInt(5), Currency: "USD"}}
+	if tx.PriceCurrency() != BRL {
+		t.Errorf("Expected price currency brl, got %s", tx.PriceCurrency())
+	}
+	if tx.FeeCurrency() != USD {
+		t.Errorf("Expected fee currency usd, got %s", tx.FeeCurrency())
+	}
+
+	tx.Fee = Fee{Amount: decimal.NewFromFloat(0.0001), Currency: "bitcoin"}
+	if tx.FeeCurrency() != BRL {
+		t.Errorf("Expected crypto fee to be valued in brl, got %s", tx.FeeCurrency())
+	}
+
+	tx.Currency = ""
+	if tx.PriceCurrency() != DefaultCurrency {
+		t.Errorf("Expected default currency, got %s", tx.PriceCurrency())
+	}
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// UniverseKind selects how the coins of a universe are chosen
+type UniverseKind string
+
+// Supported universe kinds
+const (
+	// UniverseMarketCap is the top coins by market cap
+	UniverseMarketCap UniverseKind = "market_cap"
+	// UniverseVolume is the top coins by 24h trading volume
+	UniverseVolume UniverseKind = "volume"
+	// UniverseCategory is the top coins by market cap of a CoinGecko category
+	UniverseCategory UniverseKind = "category"
+	// UniverseCustom is a fixed list of coins
+	UniverseCustom UniverseKind = "custom"
+)
+
+// MaxUniverseSize caps the number of coins of a ranked universe
+const MaxUniverseSize = 1000
+
+// Universe is a named set of coins watchlists can follow instead of listing coins.
+// Ranked universes are re-resolved periodically, so their members change with the market.
+type Universe struct {
+	Name string       `json:"name"`
+	Kind UniverseKind `json:"kind"`
+	// Size is the number of coins of a ranked universe
+	Size int `json:"size,omitempty"`
+	// Category is the CoinGecko category ID of a category universe, e.g. decentralized-finance-defi
+	Category string `json:"category,omitempty"`
+	// Coins lists the coin IDs of a custom universe
+	Coins []string `json:"coins,omitempty"`
+}
+
+// Normalize lowercases the name, category and coin IDs
+func (u *Universe) Normalize() {
+	u.Name = strings.ToLower(strings.TrimSpace(u.Name))
+	u.Category = strings.ToLower(strings.TrimSpace(u.Category))
+	for i, id := range u.Coins {
+		u.Coins[i] = strings.ToLower(strings.TrimSpace(id))
+	}
+}
+
+// Validate ensures that the Universe entity is valid
+func (u *Universe) Validate() error {
+	if u.Name == "" || strings.ContainsAny(u.Name, " /") {
+		return fmt.Errorf("universe name must be a non-empty slug, got %q", u.Name)
+	}
+	switch u.Kind {
+	case UniverseMarketCap, UniverseVolume, UniverseCategory:
+		if u.Size <= 0 || u.Size > MaxUniverseSize {
+			return fmt.Errorf("universe %s size must be between 1 and %d, got %d", u.Name, MaxUniverseSize, u.Size)
+		}
+		if (u.Kind == UniverseCategory) != (u.Category != "") {
+			return fmt.Errorf("universe %s needs a category only when its kind is category", u.Name)
+		}
+		if len(u.Coins) > 0 {
+			return fmt.Errorf("universe %s lists coins but is ranked by %s", u.Name, u.Kind)
+		}
+	case UniverseCustom:
+		if len(u.Coins) == 0 {
+			return fmt.Errorf("custom universe %s needs coins", u.Name)
+		}
+		if u.Size != 0 || u.Category != "" {
+			return fmt.Errorf("custom universe %s cannot set a size or category", u.Name)
+		}
+		for _, id := range u.Coins {
+			if id == "" {
+				return errors.New("universe coin ID cannot be empty")
+			}
+		}
+	default:
+		return fmt.Errorf("unknown universe kind: %q", u.Kind)
+	}
+	return nil
+}
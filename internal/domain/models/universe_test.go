@@ -0,0 +1,41 @@
+package models
+
+import "testing"
+
+func TestUniverse_Validate(t *testing.T) {
+	valid := []Universe{
+		{Name: "top-100", Kind: UniverseMarketCap, Size: 100},
+		{Name: "liquid", Kind: UniverseVolume, Size: 20},
+		{Name: "defi", Kind: UniverseCategory, Size: 25, Category: "decentralized-finance-defi"},
+		{Name: "majors", Kind: UniverseCustom, Coins: []string{"bitcoin", "ethereum"}},
+	}
+	for _, u := range valid {
+		if err := u.Validate(); err != nil {
+			t.Errorf("Expected %s to be valid, got %v", u.Name, err)
+		}
+	}
+
+	invalid := []Universe{
+		{Name: "", Kind: UniverseMarketCap, Size: 10},
+		{Name: "top 10", Kind: UniverseMarketCap, Size: 10},
+		{Name: "huge", Kind: UniverseVolume, Size: 5000},
+		{Name: "nocat", Kind: UniverseCategory, Size: 10},
+		{Name: "mixed", Kind: UniverseMarketCap, Size: 10, Category: "layer-1"},
+		{Name: "empty", Kind: UniverseCustom},
+		{Name: "sized", Kind: UniverseCustom, Size: 3, Coins: []string{"bitcoin"}},
+		{Name: "odd", Kind: "gainers", Size: 10},
+	}
+	for _, u := range invalid {
+		if err := u.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", u)
+		}
+	}
+}
+
+func TestUniverse_Normalize(t *testing.T) {
+	u := Universe{Name: " Majors ", Kind: UniverseCustom, Coins: []string{" Bitcoin"}}
+	u.Normalize()
+	if u.Name != "majors" || u.Coins[0] != "bitcoin" {
+		t.Errorf("Unexpected normalized universe: %+v", u)
+	}
+}
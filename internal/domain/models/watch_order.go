@@ -0,0 +1,71 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// WatchOrder is a planned trade the user is watching: an entry with a protective
+// stop and an optional target. Its R-multiple measures progress in units of initial risk.
+type WatchOrder struct {
+	ID         string    `json:"id"`
+	CryptoID   string    `json:"crypto_id"`
+	EntryPrice float64   `json:"entry_price"`
+	StopLoss   float64   `json:"stop_loss"`
+	TakeProfit float64   `json:"take_profit,omitempty"`
+	Quantity   float64   `json:"quantity"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Validate ensures that the WatchOrder entity is valid
+func (o *WatchOrder) Validate() error {
+	if o.CryptoID == "" {
+		return errors.New("watch order crypto ID cannot be empty")
+	}
+	if o.EntryPrice <= 0 || o.StopLoss <= 0 {
+		return errors.New("watch order entry and stop prices must be positive")
+	}
+	if o.EntryPrice == o.StopLoss {
+		return errors.New("watch order stop loss must differ from entry price")
+	}
+	if o.TakeProfit < 0 {
+		return errors.New("watch order take profit cannot be negative")
+	}
+	if o.TakeProfit > 0 && (o.TakeProfit > o.EntryPrice) != o.IsLong() {
+		return errors.New("watch order take profit must be on the opposite side of the stop loss")
+	}
+	if o.Quantity < 0 {
+		return errors.New("watch order quantity cannot be negative")
+	}
+	return nil
+}
+
+// IsLong reports whether the order profits from a rising price
+func (o *WatchOrder) IsLong() bool {
+	return o.StopLoss < o.EntryPrice
+}
+
+// RiskPerUnit returns the loss per unit if the stop is hit
+func (o *WatchOrder) RiskPerUnit() float64 {
+	if o.IsLong() {
+		return o.EntryPrice - o.StopLoss
+	}
+	return o.StopLoss - o.EntryPrice
+}
+
+// RMultiple returns the open profit at price expressed in multiples of the initial risk
+func (o *WatchOrder) RMultiple(price float64) float64 {
+	move := price - o.EntryPrice
+	if !o.IsLong() {
+		move = -move
+	}
+	return move / o.RiskPerUnit()
+}
+
+// TargetRMultiple returns the reward-to-risk ratio of the take profit, or 0 without a target
+func (o *WatchOrder) TargetRMultiple() float64 {
+	if o.TakeProfit == 0 {
+		return 0
+	}
+	return o.RMultiple(o.TakeProfit)
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWatchOrder_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   WatchOrder
+		wantErr bool
+	}{
+		{name: "valid long", order: WatchOrder{CryptoID: "bitcoin", EntryPrice: 100, StopLoss: 90, TakeProfit: 130}},
+		{name: "valid short", order: WatchOrder{CryptoID: "bitcoin", EntryPrice: 100, StopLoss: 110, TakeProfit: 70}},
+		{name: "invalid - stop equals entry", order: WatchOrder{CryptoID: "bitcoin", EntryPrice: 100, StopLoss: 100}, wantErr: true},
+		{name: "invalid - target behind stop", order: WatchOrder{CryptoID: "bitcoin", EntryPrice: 100, StopLoss: 90, TakeProfit: 80}, wantErr: true},
+		{name: "invalid - empty ID", order: WatchOrder{EntryPrice: 100, StopLoss: 90}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.order.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WatchOrder.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWatchOrder_RMultiple(t *testing.T) {
+	long := WatchOrder{EntryPrice: 100, StopLoss: 90, TakeProfit: 130}
+	if got := long.RMultiple(115); got != 1.5 {
+		t.Errorf("Expected 1.5R, got %f", got)
+	}
+	if got := long.TargetRMultiple(); got != 3 {
+		t.Errorf("Expected target of 3R, got %f", got)
+	}
+
+	short := WatchOrder{EntryPrice: 100, StopLoss: 110}
+	if got := short.RMultiple(105); math.Abs(got+0.5) > 1e-9 {
+		t.Errorf("Expected -0.5R, got %f", got)
+	}
+	if got := short.TargetRMultiple(); got != 0 {
+		t.Errorf("Expected no target, got %f", got)
+	}
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultOwner owns the watchlists of requests that do not identify a session
+const DefaultOwner = "default"
+
+// Watchlist is a named, ordered list of coin IDs belonging to a user or session.
+// Coins of every watchlist that is not archived are tracked by the poller.
+type Watchlist struct {
+	ID       string   `json:"id"`
+	Owner    string   `json:"owner"`
+	Name     string   `json:"name"`
+	Coins    []string `json:"coins"`
+	Position int      `json:"position"`
+	Archived bool     `json:"archived"`
+	// Universe names a universe the watchlist follows instead of listing coins.
+	// Its coins are then resolved from the universe and not stored.
+	Universe string `json:"universe,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Normalize trims the name and lowercases the universe and coin IDs
+func (w *Watchlist) Normalize() {
+	w.Name = strings.TrimSpace(w.Name)
+	w.Owner = strings.TrimSpace(w.Owner)
+	w.Universe = strings.ToLower(strings.TrimSpace(w.Universe))
+	if w.Owner == "" {
+		w.Owner = DefaultOwner
+	}
+	for i, id := range w.Coins {
+		w.Coins[i] = strings.ToLower(strings.TrimSpace(id))
+	}
+}
+
+// Validate ensures that the Watchlist entity is valid
+func (w *Watchlist) Validate() error {
+	if w.Name == "" {
+		return errors.New("watchlist name cannot be empty")
+	}
+	if w.Universe != "" && len(w.Coins) > 0 {
+		return errors.New("watchlist cannot list coins and follow a universe")
+	}
+	seen := make(map[string]bool, len(w.Coins))
+	for _, id := range w.Coins {
+		if id == "" {
+			return errors.New("watchlist coin ID cannot be empty")
+		}
+		if seen[id] {
+			return fmt.Errorf("watchlist contains %s twice", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package models
+
+import "testing"
+
+func TestWatchlist_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		watchlist Watchlist
+		wantErr   bool
+	}{
+		{"valid", Watchlist{Name: "Majors", Coins: []string{"bitcoin", "ethereum"}}, false},
+		{"empty list", Watchlist{Name: "Empty"}, false},
+		{"missing name", Watchlist{Coins: []string{"bitcoin"}}, true},
+		{"empty coin", Watchlist{Name: "x", Coins: []string{""}}, true},
+		{"universe", Watchlist{Name: "Top", Universe: "top-100"}, false},
+		{"universe with coins", Watchlist{Name: "x", Universe: "top-100", Coins: []string{"bitcoin"}}, true},
+		{"duplicate coin", Watchlist{Name: "x", Coins: []string{"bitcoin", "bitcoin"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.watchlist.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWatchlist_Normalize(t *testing.T) {
+	w := Watchlist{Name: "  Majors ", Coins: []string{" Bitcoin", "ETHEREUM "}}
+	w.Normalize()
+	if w.Name != "Majors" || w.Owner != DefaultOwner {
+		t.Errorf("Expected trimmed name and default owner, got %q and %q", w.Name, w.Owner)
+	}
+	if w.Coins[0] != "bitcoin" || w.Coins[1] != "ethereum" {
+		t.Errorf("Expected lowercase coin IDs, got %v", w.Coins)
+	}
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+)
+
+// ErrCircuitOpen is returned without contacting upstream while the breaker is open
+var ErrCircuitOpen = errors.New("coingecko circuit breaker open")
+
+// BreakerState is the state of a circuit breaker
+type BreakerState string
+
+// Breaker states
+const (
+	// BreakerClosed lets every request through
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen rejects requests until the cooldown has passed
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a single probe through to test whether upstream recovered
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// Breaker stops calling upstream after consecutive failures. Once the cooldown
+// has passed it half-opens: the next request is a probe that closes the breaker
+// on success and reopens it on failure.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	clock     clock.Clock
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewBreaker creates a closed breaker that opens after threshold consecutive failures
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{threshold: max(threshold, 1), cooldown: cooldown, clock: clock.Real, state: BreakerClosed}
+}
+
+// SetClock replaces the system clock, for tests
+func (b *Breaker) SetClock(c clock.Clock) {
+	b.clock = c
+}
+
+// State returns the current state and, unless closed, when the breaker last opened
+func (b *Breaker) State() (BreakerState, time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen && b.clock.Now().Sub(b.openedAt) >= b.cooldown {
+		return BreakerHalfOpen, b.openedAt
+	}
+	if b.state == BreakerClosed {
+		return b.state, time.Time{}
+	}
+	return b.state, b.openedAt
+}
+
+// Allow reports whether a request may be sent. It returns ErrCircuitOpen while
+// the breaker is open or a half-open probe is already in flight.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerOpen:
+		if b.clock.Now().Sub(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = BreakerHalfOpen
+	case BreakerClosed:
+		return nil
+	}
+	if b.probing {
+		return ErrCircuitOpen
+	}
+	b.probing = true
+	return nil
+}
+
+// Record reports the outcome of an allowed request
+func (b *Breaker) Record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if success {
+		b.state, b.failures = BreakerClosed, 0
+		return
+	}
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state, b.openedAt = BreakerOpen, b.clock.Now()
+	}
+}
+
+// Transport wraps next so every request goes through the breaker. Transport
+// errors, rate limiting and server errors count as failures; other client
+// errors mean upstream is answering and count as successes.
+func (b *Breaker) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return breakerTransport{breaker: b, next: next}
+}
+
+type breakerTransport struct {
+	breaker *Breaker
+	next    http.RoundTripper
+}
+
+func (t breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.breaker.Allow(); err != nil {
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, err)
+	}
+	resp, err := t.next.RoundTrip(req)
+	t.breaker.Record(err == nil && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500)
+	return resp, err
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestBreaker_OpensAndHalfOpens(t *testing.T) {
+	b := NewBreaker(2, time.Minute)
+	now := time.Now()
+	clk := clock.NewFake(now)
+	b.SetClock(clk)
+
+	b.Allow()
+	b.Record(false)
+	if state, _ := b.State(); state != BreakerClosed {
+		t.Fatalf("Expected the breaker closed below the threshold, got %s", state)
+	}
+	b.Allow()
+	b.Record(false)
+	if state, openedAt := b.State(); state != BreakerOpen || !openedAt.Equal(now) {
+		t.Fatalf("Expected the breaker open, got %s since %v", state, openedAt)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen during the cooldown, got %v", err)
+	}
+
+	clk.Advance(time.Minute)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Expected a probe after the cooldown, got %v", err)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected a single probe at a time, got %v", err)
+	}
+	b.Record(false)
+	if state, _ := b.State(); state != BreakerOpen {
+		t.Fatalf("Expected a failed probe to reopen the breaker, got %s", state)
+	}
+
+	clk.Advance(time.Minute)
+	b.Allow()
+	b.Record(true)
+	if state, _ := b.State(); state != BreakerClosed {
+		t.Errorf("Expected a successful probe to close the breaker, got %s", state)
+	}
+}
+
+func TestWithBreaker_StopsCallingUpstream(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewCoinGeckoClient(WithBaseURL(server.URL), WithConcurrency(1), WithBreaker(NewBreaker(2, time.Hour)))
+	for range 3 {
+		client.FetchCryptoPrices([]string{"bitcoin"}, models.USD)
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("Expected upstream to be called until the breaker opened, got %d calls", calls.Load())
+	}
+	_, err := client.FetchCryptoPrices([]string{"bitcoin"}, models.USD)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+}
@@ -2,126 +2,798 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
 	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/pricehistory"
+	"crypto-dashboard/internal/clock"
 	"crypto-dashboard/internal/domain/models"
 )
 
+// defaultConcurrency is the number of workers used when none is configured
+const defaultConcurrency = 5
+
+// defaultBatchSize is the number of IDs sent per /simple/price request when none is configured
+const defaultBatchSize = 100
+
+// marketsPageSize is the largest page /coins/markets returns
+const marketsPageSize = 250
+
 // CoinGeckoClient handles communication with the CoinGecko API
 type CoinGeckoClient struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL     string
+	apiKey      string
+	httpClient  *http.Client
+	concurrency int
+	batchSize   int
+	partial     bool
+	breaker     *Breaker
+	fixtures    Fixtures
+	middlewares []Middleware
+	logger      *slog.Logger
+	clock       clock.Clock
+}
+
+// Option configures optional behaviour of the CoinGeckoClient
+type Option func(*CoinGeckoClient)
+
+// WithBaseURL overrides the CoinGecko API base URL
+func WithBaseURL(baseURL string) Option {
+	return func(c *CoinGeckoClient) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithAPIKey sends the given CoinGecko API key with every request
+func WithAPIKey(apiKey string) Option {
+	return func(c *CoinGeckoClient) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithTimeout sets the HTTP client timeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *CoinGeckoClient) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithTransport sets the HTTP transport requests are sent with; WithMiddleware
+// adds behaviour around it
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *CoinGeckoClient) {
+		c.httpClient.Transport = transport
+	}
+}
+
+// WithLogger sets the logger used to record upstream requests and failures
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *CoinGeckoClient) {
+		c.logger = logger
+	}
+}
+
+// WithClock replaces the system clock prices are stamped with
+func WithClock(c clock.Clock) Option {
+	return func(cl *CoinGeckoClient) {
+		cl.clock = c
+	}
+}
+
+// WithConcurrency limits the number of simultaneous requests made by
+// FetchCryptoPrices and GetTopNCryptos
+func WithConcurrency(n int) Option {
+	return func(c *CoinGeckoClient) {
+		c.concurrency = n
+	}
+}
+
+// WithBatchSize sets how many IDs FetchCryptoPrices sends per request
+func WithBatchSize(n int) Option {
+	return func(c *CoinGeckoClient) {
+		c.batchSize = n
+	}
+}
+
+// WithPartialResults makes FetchCryptoPrices return the prices it managed to
+// fetch together with the aggregated error instead of discarding them
+func WithPartialResults() Option {
+	return func(c *CoinGeckoClient) {
+		c.partial = true
+	}
+}
+
+// WithBreaker sends every request through the circuit breaker, wrapping any
+// transport set with WithTransport
+func WithBreaker(b *Breaker) Option {
+	return func(c *CoinGeckoClient) {
+		c.breaker = b
+	}
 }
 
 // NewCoinGeckoClient creates a new API client with timeout
-func NewCoinGeckoClient() *CoinGeckoClient {
-	return &CoinGeckoClient{
+func NewCoinGeckoClient(opts ...Option) *CoinGeckoClient {
+	client := &CoinGeckoClient{
 		baseURL: "https://api.coingecko.com/api/v3",
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		concurrency: defaultConcurrency,
+		batchSize:   defaultBatchSize,
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	if client.fixtures.Mode != FixturesOff {
+		client.httpClient.Transport = client.fixtures.Transport(client.httpClient.Transport)
+	}
+	if len(client.middlewares) > 0 {
+		client.httpClient.Transport = Chain(client.httpClient.Transport, client.middlewares...)
 	}
+	if client.breaker != nil {
+		client.httpClient.Transport = client.breaker.Transport(client.httpClient.Transport)
+	}
+	return client
 }
 
-// FetchCryptoPrices demonstrates concurrent API calls and error handling
-func (c *CoinGeckoClient) FetchCryptoPrices(cryptoIDs []string) ([]models.CryptoPrice, error) {
-	// Create a channel to receive results from goroutines
-	results := make(chan models.CryptoPrice, len(cryptoIDs))
-	errors := make(chan error, len(cryptoIDs))
+// FetchError aggregates every failure that happened during a FetchCryptoPrices call
+type FetchError struct {
+	// Failures maps each crypto ID that could not be fetched to its error
+	Failures map[string]error
+}
 
-	// Launch a goroutine for each crypto ID
-	// This demonstrates Go's concurrent execution model
-	for _, id := range cryptoIDs {
-		go func(cryptoID string) {
-			// Dealing with panic
-			defer func() {
-				if r := recover(); r != nil {
-					errors <- fmt.Errorf("panic occurred: %v", r)
-				}
-			}()
-
-			url := fmt.Sprintf("%s/simple/price?ids=%s&vs_currencies=usd", c.baseURL, cryptoID)
-			resp, err := c.httpClient.Get(url)
+// Error lists the failed IDs in a stable order
+func (e *FetchError) Error() string {
+	ids := make([]string, 0, len(e.Failures))
+	for id := range e.Failures {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("%s: %v", id, e.Failures[id])
+	}
+	return fmt.Sprintf("failed to fetch %d price(s): %s", len(ids), strings.Join(parts, "; "))
+}
+
+// Unwrap exposes the individual errors to errors.Is and errors.As
+func (e *FetchError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, err := range e.Failures {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Missing returns the IDs the provider does not know, e.g. because they were
+// delisted or renamed, as opposed to IDs whose request failed
+func (e *FetchError) Missing() []string {
+	var ids []string
+	for id, err := range e.Failures {
+		if errors.Is(err, ErrNotFound) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// FetchCryptoPrices fetches the price of each crypto ID in the given currency.
+// IDs are requested in batches of comma-separated IDs, with at most the
+// configured concurrency of batches in flight. Every worker is drained before
+// returning, so no goroutine outlives the call.
+// All failures are collected into a *FetchError; with WithPartialResults the
+// successfully fetched prices are returned alongside it.
+func (c *CoinGeckoClient) FetchCryptoPrices(cryptoIDs []string, currency models.Currency) ([]models.CryptoPrice, error) {
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+
+	type result struct {
+		price models.CryptoPrice
+		err   error
+	}
+
+	size := c.batchSize
+	if size <= 0 {
+		size = defaultBatchSize
+	}
+	batches := (len(cryptoIDs) + size - 1) / size
+
+	// Results are indexed like the IDs so they keep the input order
+	results := make([]result, len(cryptoIDs))
+	c.forEach(batches, func(b int) {
+		start := b * size
+		ids := cryptoIDs[start:min(start+size, len(cryptoIDs))]
+		prices, err := c.fetchSimplePrices(ids, currency)
+		for i, id := range ids {
 			if err != nil {
-				errors <- err
-				return
+				results[start+i] = result{err: err}
+				continue
 			}
-			defer resp.Body.Close()
-
-			// If status code is not 200, we'll panic to handle the panic
-			if resp.StatusCode != http.StatusOK {
-				panic(fmt.Sprintf("API returned status code: %d", resp.StatusCode))
+			price, ok := prices[id]
+			if !ok {
+				results[start+i] = result{err: fmt.Errorf("%w: no %s price returned for %s", ErrNotFound, currency, id)}
+				continue
 			}
+			results[start+i] = result{price: price}
+		}
+	})
 
-			var data map[string]map[string]float64
-			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				errors <- err
-				return
-			}
+	var prices []models.CryptoPrice
+	failures := make(map[string]error)
+	for i, r := range results {
+		if r.err != nil {
+			failures[cryptoIDs[i]] = r.err
+			continue
+		}
+		prices = append(prices, r.price)
+	}
 
-			price := models.CryptoPrice{
-				ID:           cryptoID,
-				CurrentPrice: data[cryptoID]["usd"],
-				LastUpdated:  time.Now().UTC().Format(time.RFC3339),
-			}
+	if len(failures) == 0 {
+		return prices, nil
+	}
+	fetchErr := &FetchError{Failures: failures}
+	c.log().Warn("price fetch failed", "failed", len(failures), "requested", len(cryptoIDs), "error", fetchErr)
+	if c.partial {
+		return prices, fetchErr
+	}
+	return nil, fetchErr
+}
+
+// fetchSimplePrices fetches the prices of a batch of IDs with one request,
+// turning panics into errors so a misbehaving response can never take down
+// the worker pool. IDs CoinGecko does not know are missing from the result.
+func (c *CoinGeckoClient) fetchSimplePrices(cryptoIDs []string, currency models.Currency) (prices map[string]models.CryptoPrice, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic occurred: %v", r)
+		}
+	}()
 
-			results <- price
-		}(id)
+	data, err := c.getSimplePrice(cryptoIDs, []models.Currency{currency}, true)
+	if err != nil {
+		return nil, err
 	}
 
-	// Collect results using a slice
-	var prices []models.CryptoPrice
-	for i := 0; i < len(cryptoIDs); i++ {
-		select {
-		case price := <-results:
-			prices = append(prices, price)
-		case err := <-errors:
-			return nil, err
+	now := c.now().UTC()
+	prices = make(map[string]models.CryptoPrice, len(data))
+	for _, id := range cryptoIDs {
+		quote, ok := data[id][string(currency)]
+		if !ok {
+			continue
 		}
+		// CoinGecko keeps quoting delisted coins with the time of their last update
+		updated := now
+		if at := data[id]["last_updated_at"]; at.IsPositive() {
+			updated = time.Unix(at.IntPart(), 0).UTC()
+		}
+		prices[id] = models.CryptoPrice{
+			ID:             id,
+			CurrentPrice:   quote,
+			Currency:       currency,
+			PriceChange24h: data[id][string(currency)+"_24h_change"].InexactFloat64(),
+			LastUpdated:    updated.Format(time.RFC3339),
+		}
+	}
+	return prices, nil
+}
+
+// forEach calls fn with every index below n on at most the configured number
+// of goroutines and waits for all of them
+func (c *CoinGeckoClient) forEach(n int, fn func(i int)) {
+	workers := c.concurrency
+	if workers <= 0 {
+		workers = defaultConcurrency
+	}
+	workers = min(workers, n)
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// FetchMultiCurrencyPrices fetches the price of every crypto ID in every currency with a single request.
+// The result is keyed by crypto ID and then by currency.
+func (c *CoinGeckoClient) FetchMultiCurrencyPrices(cryptoIDs []string, currencies []models.Currency) (map[string]map[models.Currency]float64, error) {
+	if len(cryptoIDs) == 0 || len(currencies) == 0 {
+		return nil, fmt.Errorf("at least one crypto ID and one currency are required")
+	}
+
+	data, err := c.getSimplePrice(cryptoIDs, currencies, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch multi-currency prices: %w", err)
 	}
 
+	prices := make(map[string]map[models.Currency]float64, len(data))
+	for id, quotes := range data {
+		prices[id] = make(map[models.Currency]float64, len(quotes))
+		for currency, quote := range quotes {
+			prices[id][models.Currency(currency)] = quote.InexactFloat64()
+		}
+	}
 	return prices, nil
 }
 
+// GetExchangeRates derives fiat exchange rates from bitcoin quoted in every requested currency
+func (c *CoinGeckoClient) GetExchangeRates(base models.Currency, currencies []models.Currency) (models.ExchangeRates, error) {
+	all := append([]models.Currency{base}, currencies...)
+	prices, err := c.FetchMultiCurrencyPrices([]string{"bitcoin"}, all)
+	if err != nil {
+		return models.ExchangeRates{}, err
+	}
+	return models.NewExchangeRates(base, prices["bitcoin"])
+}
+
+// coinHistory is the subset of /coins/{id}/history used by the client
+type coinHistory struct {
+	MarketData struct {
+		CurrentPrice map[string]float64 `json:"current_price"`
+	} `json:"market_data"`
+}
+
+// GetHistoricalExchangeRates derives the fiat exchange rates of a past day from
+// the bitcoin price CoinGecko recorded in every currency on that date
+func (c *CoinGeckoClient) GetHistoricalExchangeRates(date time.Time, base models.Currency, currencies []models.Currency) (models.ExchangeRates, error) {
+	url := fmt.Sprintf("%s/coins/bitcoin/history?date=%s&localization=false", c.baseURL, date.UTC().Format("02-01-2006"))
+	resp, err := c.get(url)
+	if err != nil {
+		return models.ExchangeRates{}, fmt.Errorf("failed to fetch historical rates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return models.ExchangeRates{}, err
+	}
+
+	var history coinHistory
+	if err := json.NewDecoder(resp.Body).Decode(&history); err != nil {
+		return models.ExchangeRates{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	quotes := make(map[models.Currency]float64, len(currencies)+1)
+	for _, currency := range append([]models.Currency{base}, currencies...) {
+		quote, ok := history.MarketData.CurrentPrice[string(currency)]
+		if !ok {
+			return models.ExchangeRates{}, fmt.Errorf("no %s quote on %s", currency, date.Format("2006-01-02"))
+		}
+		quotes[currency] = quote
+	}
+	return models.NewExchangeRates(base, quotes)
+}
+
+// GetPriceRange fetches the prices CoinGecko recorded for a coin between from and to.
+// The granularity is chosen upstream: 5-minutely up to a day, hourly up to 90 days, daily beyond.
+func (c *CoinGeckoClient) GetPriceRange(cryptoID string, currency models.Currency, from, to time.Time) ([]models.PricePoint, error) {
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+	url := fmt.Sprintf("%s/coins/%s/market_chart/range?vs_currency=%s&from=%d&to=%d",
+		c.baseURL, cryptoID, currency, from.Unix(), to.Unix())
+	resp, err := c.get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch price range: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var chart struct {
+		// Prices are [unix milliseconds, price] pairs
+		Prices [][2]float64 `json:"prices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&chart); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	points := make([]models.PricePoint, len(chart.Prices))
+	for i, p := range chart.Prices {
+		points[i] = models.PricePoint{Price: p[1], Time: time.UnixMilli(int64(p[0])).UTC()}
+	}
+	return points, nil
+}
+
+// GetPriceAt fetches the price CoinGecko recorded for a coin at 00:00 UTC of the given date.
+// It wraps pricehistory.ErrNoPrice when the coin had no market data yet.
+func (c *CoinGeckoClient) GetPriceAt(cryptoID string, date time.Time, currency models.Currency) (float64, error) {
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+	url := fmt.Sprintf("%s/coins/%s/history?date=%s&localization=false", c.baseURL, cryptoID, date.UTC().Format("02-01-2006"))
+	resp, err := c.get(url)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch historical price: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return 0, err
+	}
+
+	var history coinHistory
+	if err := json.NewDecoder(resp.Body).Decode(&history); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+	price, ok := history.MarketData.CurrentPrice[string(currency)]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s in %s on %s", pricehistory.ErrNoPrice, cryptoID, currency, date.Format(time.DateOnly))
+	}
+	return price, nil
+}
+
+// getSimplePrice calls the /simple/price endpoint for the given IDs and currencies.
+// With include24h the response also carries "<currency>_24h_change" and
+// "last_updated_at" keys.
+// Quotes are decoded as decimals so micro-cap prices keep every digit.
+func (c *CoinGeckoClient) getSimplePrice(cryptoIDs []string, currencies []models.Currency, include24h bool) (map[string]map[string]decimal.Decimal, error) {
+	codes := make([]string, len(currencies))
+	for i, currency := range currencies {
+		codes[i] = string(currency)
+	}
+
+	url := fmt.Sprintf("%s/simple/price?ids=%s&vs_currencies=%s",
+		c.baseURL, strings.Join(cryptoIDs, ","), strings.Join(codes, ","))
+	if include24h {
+		url += "&include_24hr_change=true&include_last_updated_at=true"
+	}
+	resp, err := c.get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var data map[string]map[string]decimal.Decimal
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// get performs a GET request, attaching the API key when one is configured
+func (c *CoinGeckoClient) get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("x-cg-demo-api-key", c.apiKey)
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.log().Warn("upstream request failed", "path", req.URL.Path, "duration", time.Since(start), "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrProviderUnavailable, err)
+	}
+	c.log().Debug("upstream request", "path", req.URL.Path, "status", resp.StatusCode, "duration", time.Since(start))
+	return resp, nil
+}
+
+// log returns the configured logger, falling back to the default one
+func (c *CoinGeckoClient) log() *slog.Logger {
+	if c.logger == nil {
+		return slog.Default()
+	}
+	return c.logger
+}
+
+func (c *CoinGeckoClient) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
+
 // MarketData represents the market data for a cryptocurrency
 type MarketData struct {
-	ID     string  `json:"id"`
-	Symbol string  `json:"symbol"`
-	Name   string  `json:"name"`
-	Price  float64 `json:"current_price"`
+	ID                string          `json:"id"`
+	Symbol            string          `json:"symbol"`
+	Name              string          `json:"name"`
+	Price             decimal.Decimal `json:"current_price"`
+	MarketCap         float64         `json:"market_cap"`
+	TotalVolume       float64         `json:"total_volume"`
+	PriceChange24h    float64         `json:"price_change_percentage_24h"`
+	PriceChange7d     float64         `json:"price_change_percentage_7d_in_currency"`
+	CirculatingSupply float64         `json:"circulating_supply"`
+	ATH               float64         `json:"ath"`
+	Image             string          `json:"image"`
 }
 
-// GetTopNCryptos fetches the top N cryptocurrencies by market cap
-func (c *CoinGeckoClient) GetTopNCryptos(n int) ([]models.CryptoPrice, error) {
-	url := fmt.Sprintf("%s/coins/markets?vs_currency=usd&order=market_cap_desc&per_page=%d&page=1", c.baseURL, n)
+// GetTopNCryptos fetches the top N cryptocurrencies by market cap priced in the
+// given currency. Unlike a universe, N is not capped: as many pages are
+// fetched as needed.
+func (c *CoinGeckoClient) GetTopNCryptos(n int, currency models.Currency) ([]models.CryptoPrice, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("n must be positive, got %d", n)
+	}
+	return c.markets(models.Universe{Name: "top", Kind: models.UniverseMarketCap, Size: n}, currency)
+}
+
+// GetUniverse fetches the coins of a universe with their market data. Ranked
+// universes above one page of /coins/markets are fetched page by page, at most
+// the configured concurrency of pages at a time. Custom universes keep the
+// order of their coins; coins CoinGecko does not know are left out.
+func (c *CoinGeckoClient) GetUniverse(u models.Universe, currency models.Currency) ([]models.CryptoPrice, error) {
+	if err := u.Validate(); err != nil {
+		return nil, err
+	}
+	return c.markets(u, currency)
+}
+
+// markets fetches the coins of a universe, which the caller validated, page by page
+func (c *CoinGeckoClient) markets(u models.Universe, currency models.Currency) ([]models.CryptoPrice, error) {
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+
+	query := url.Values{"vs_currency": {string(currency)}, "order": {"market_cap_desc"}}
+	size := u.Size
+	switch u.Kind {
+	case models.UniverseVolume:
+		query.Set("order", "volume_desc")
+	case models.UniverseCategory:
+		query.Set("category", u.Category)
+	case models.UniverseCustom:
+		size = len(u.Coins)
+	}
+
+	perPage := min(size, marketsPageSize)
+	pages := make([][]MarketData, (size+perPage-1)/perPage)
+	errs := make([]error, len(pages))
+	c.forEach(len(pages), func(i int) {
+		q := maps.Clone(query)
+		if u.Kind == models.UniverseCustom {
+			// Custom coins are requested by ID, one page of IDs per request
+			q.Set("ids", strings.Join(u.Coins[i*perPage:min((i+1)*perPage, size)], ","))
+			pages[i], errs[i] = c.getMarketsPage(q, perPage, 1)
+			return
+		}
+		pages[i], errs[i] = c.getMarketsPage(q, perPage, i+1)
+	})
 
-	resp, err := c.httpClient.Get(url)
+	var marketData []MarketData
+	for i, page := range pages {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		marketData = append(marketData, page...)
+		// A short page of a ranking is the last one CoinGecko has
+		if len(page) < perPage && u.Kind != models.UniverseCustom {
+			break
+		}
+	}
+	if u.Kind == models.UniverseCustom {
+		rank := make(map[string]int, len(u.Coins))
+		for i, id := range u.Coins {
+			rank[id] = i
+		}
+		slices.SortStableFunc(marketData, func(a, b MarketData) int { return rank[a.ID] - rank[b.ID] })
+	}
+	if len(marketData) > size {
+		marketData = marketData[:size]
+	}
+
+	now := c.now().UTC().Format(time.RFC3339)
+	cryptoPrices := make([]models.CryptoPrice, len(marketData))
+	for i, data := range marketData {
+		cryptoPrices[i] = models.CryptoPrice{
+			ID:                data.ID,
+			Symbol:            data.Symbol,
+			Name:              data.Name,
+			CurrentPrice:      data.Price,
+			Currency:          currency,
+			PriceChange24h:    data.PriceChange24h,
+			LastUpdated:       now,
+			MarketCap:         data.MarketCap,
+			Volume24h:         data.TotalVolume,
+			PriceChange7d:     data.PriceChange7d,
+			CirculatingSupply: data.CirculatingSupply,
+			ATH:               data.ATH,
+			Image:             data.Image,
+		}
+	}
+
+	return cryptoPrices, nil
+}
+
+// getMarketsPage fetches one page of /coins/markets for the query
+func (c *CoinGeckoClient) getMarketsPage(query url.Values, perPage, page int) ([]MarketData, error) {
+	query.Set("per_page", strconv.Itoa(perPage))
+	query.Set("page", strconv.Itoa(page))
+	query.Set("price_change_percentage", "7d")
+
+	resp, err := c.get(c.baseURL + "/coins/markets?" + query.Encode())
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch top cryptos: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status code: %d", resp.StatusCode)
+	if err := checkResponse(resp); err != nil {
+		return nil, err
 	}
 
 	var marketData []MarketData
 	if err := json.NewDecoder(resp.Body).Decode(&marketData); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
+	return marketData, nil
+}
 
-	cryptoPrices := make([]models.CryptoPrice, len(marketData))
-	for i, data := range marketData {
-		cryptoPrices[i] = models.CryptoPrice{
-			ID:           data.ID,
-			Symbol:       data.Symbol,
-			Name:         data.Name,
-			CurrentPrice: data.Price,
-			LastUpdated:  time.Now().UTC().Format(time.RFC3339),
+// Search looks coins up by name, ticker or ID
+func (c *CoinGeckoClient) Search(query string) ([]models.SearchResult, error) {
+	resp, err := c.get(fmt.Sprintf("%s/search?query=%s", c.baseURL, url.QueryEscape(query)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search coins: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Coins []struct {
+			ID            string `json:"id"`
+			Symbol        string `json:"symbol"`
+			Name          string `json:"name"`
+			MarketCapRank int    `json:"market_cap_rank"`
+			Thumb         string `json:"thumb"`
+		} `json:"coins"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	results := make([]models.SearchResult, len(data.Coins))
+	for i, coin := range data.Coins {
+		results[i] = models.SearchResult{
+			ID:            coin.ID,
+			Symbol:        coin.Symbol,
+			Name:          coin.Name,
+			MarketCapRank: coin.MarketCapRank,
+			Thumb:         coin.Thumb,
+		}
+	}
+	return results, nil
+}
+
+// coinDetails is the subset of the /coins/{id} response used for metadata
+type coinDetails struct {
+	ID          string   `json:"id"`
+	Symbol      string   `json:"symbol"`
+	Name        string   `json:"name"`
+	Categories  []string `json:"categories"`
+	Description struct {
+		EN string `json:"en"`
+	} `json:"description"`
+	Links struct {
+		Homepage []string `json:"homepage"`
+	} `json:"links"`
+	Image         models.Images `json:"image"`
+	GenesisDate   string        `json:"genesis_date"`
+	MarketCapRank int           `json:"market_cap_rank"`
+}
+
+// CoinInfo fetches the metadata of a coin. An unknown ID returns an error matching ErrNotFound.
+func (c *CoinGeckoClient) CoinInfo(id string) (models.CoinInfo, error) {
+	resp, err := c.get(fmt.Sprintf("%s/coins/%s?localization=false&tickers=false&market_data=false&community_data=false&developer_data=false&sparkline=false",
+		c.baseURL, url.PathEscape(id)))
+	if err != nil {
+		return models.CoinInfo{}, fmt.Errorf("failed to fetch coin info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return models.CoinInfo{}, err
+	}
+
+	var details coinDetails
+	if err := json.NewDecoder(resp.Body).Decode(&details); err != nil {
+		return models.CoinInfo{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	info := models.CoinInfo{
+		ID:            details.ID,
+		Symbol:        details.Symbol,
+		Name:          details.Name,
+		Description:   details.Description.EN,
+		Categories:    []string{},
+		Images:        details.Image,
+		GenesisDate:   details.GenesisDate,
+		MarketCapRank: details.MarketCapRank,
+	}
+	// CoinGecko pads the lists with empty strings
+	for _, category := range details.Categories {
+		if category != "" {
+			info.Categories = append(info.Categories, category)
 		}
 	}
+	for _, homepage := range details.Links.Homepage {
+		if homepage != "" {
+			info.Homepage = homepage
+			break
+		}
+	}
+	return info, nil
+}
 
-	return cryptoPrices, nil
+// GetGlobalData fetches the global market overview with totals in the given currency
+func (c *CoinGeckoClient) GetGlobalData(currency models.Currency) (models.GlobalMarket, error) {
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+	resp, err := c.get(c.baseURL + "/global")
+	if err != nil {
+		return models.GlobalMarket{}, fmt.Errorf("failed to fetch global data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return models.GlobalMarket{}, err
+	}
+
+	var body struct {
+		Data struct {
+			ActiveCryptocurrencies int                `json:"active_cryptocurrencies"`
+			Markets                int                `json:"markets"`
+			TotalMarketCap         map[string]float64 `json:"total_market_cap"`
+			TotalVolume            map[string]float64 `json:"total_volume"`
+			MarketCapPercentage    map[string]float64 `json:"market_cap_percentage"`
+			MarketCapChange24h     float64            `json:"market_cap_change_percentage_24h_usd"`
+			UpdatedAt              int64              `json:"updated_at"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return models.GlobalMarket{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	data := body.Data
+	marketCap, ok := data.TotalMarketCap[string(currency)]
+	if !ok {
+		return models.GlobalMarket{}, fmt.Errorf("no %s total market cap returned", currency)
+	}
+	return models.GlobalMarket{
+		Currency:           currency,
+		TotalMarketCap:     marketCap,
+		TotalVolume:        data.TotalVolume[string(currency)],
+		MarketCapChange24h: data.MarketCapChange24h,
+		BTCDominance:       data.MarketCapPercentage["btc"],
+		ETHDominance:       data.MarketCapPercentage["eth"],
+		ActiveCoins:        data.ActiveCryptocurrencies,
+		Markets:            data.Markets,
+		UpdatedAt:          time.Unix(data.UpdatedAt, 0).UTC(),
+	}, nil
 }
@@ -1,10 +1,27 @@
 package api
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/coins"
+	"crypto-dashboard/internal/application/pricehistory"
+	"crypto-dashboard/internal/domain/models"
 )
 
 func TestNewCoinGeckoClient(t *testing.T) {
@@ -33,7 +50,7 @@ func TestFetchCryptoPrices_Success(t *testing.T) {
 		httpClient: &http.Client{Timeout: 10 * time.Second},
 	}
 
-	prices, err := client.FetchCryptoPrices([]string{"bitcoin"})
+	prices, err := client.FetchCryptoPrices([]string{"bitcoin"}, models.USD)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -42,8 +59,27 @@ func TestFetchCryptoPrices_Success(t *testing.T) {
 		t.Errorf("Expected 1 price, got %d", len(prices))
 	}
 
-	if prices[0].ID != "bitcoin" || prices[0].CurrentPrice != 50000 {
-		t.Errorf("Expected bitcoin price to be 50000, got %f", prices[0].CurrentPrice)
+	if prices[0].ID != "bitcoin" || !prices[0].CurrentPrice.Equal(decimal.NewFromInt(50000)) {
+		t.Errorf("Expected bitcoin price to be 50000, got %s", prices[0].CurrentPrice)
+	}
+}
+
+func TestFetchCryptoPrices_KeepsMicroCapPrecision(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"pepe":{"usd":0.000012345678901234567,"usd_24h_change":-1.5}}`))
+	}))
+	defer server.Close()
+
+	client := NewCoinGeckoClient(WithBaseURL(server.URL))
+	prices, err := client.FetchCryptoPrices([]string{"pepe"}, models.USD)
+	if err != nil || len(prices) != 1 {
+		t.Fatalf("Expected one price, got %v (err %v)", prices, err)
+	}
+	if prices[0].CurrentPrice.String() != "0.000012345678901234567" {
+		t.Errorf("Expected every digit of the quote to be kept, got %s", prices[0].CurrentPrice)
+	}
+	if prices[0].PriceChange24h != -1.5 {
+		t.Errorf("Expected 24h change -1.5, got %f", prices[0].PriceChange24h)
 	}
 }
 
@@ -60,7 +96,7 @@ func TestFetchCryptoPrices_PanicRecovery(t *testing.T) {
 		httpClient: &http.Client{Timeout: 10 * time.Second},
 	}
 
-	_, err := client.FetchCryptoPrices([]string{"bitcoin"})
+	_, err := client.FetchCryptoPrices([]string{"bitcoin"}, models.USD)
 	if err == nil {
 		t.Error("Expected error from panic recovery, got nil")
 	}
@@ -78,8 +114,539 @@ func TestFetchCryptoPrices_Error(t *testing.T) {
 		httpClient: &http.Client{Timeout: 10 * time.Second},
 	}
 
-	_, err := client.FetchCryptoPrices([]string{"bitcoin"})
+	_, err := client.FetchCryptoPrices([]string{"bitcoin"}, models.USD)
 	if err == nil {
 		t.Error("Expected error from API call, got nil")
 	}
 }
+
+func TestFetchCryptoPrices_AggregatesErrors(t *testing.T) {
+	// Unknown IDs are left out of the /simple/price response
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"bitcoin":{"usd":50000}}`))
+	}))
+	defer server.Close()
+
+	client := &CoinGeckoClient{
+		baseURL:    server.URL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+
+	prices, err := client.FetchCryptoPrices([]string{"bitcoin", "foo", "bar"}, models.USD)
+	if prices != nil {
+		t.Errorf("Expected no prices in strict mode, got %v", prices)
+	}
+
+	var fetchErr *FetchError
+	if !errors.As(err, &fetchErr) {
+		t.Fatalf("Expected *FetchError, got %v", err)
+	}
+	if len(fetchErr.Failures) != 2 {
+		t.Errorf("Expected 2 failures, got %d", len(fetchErr.Failures))
+	}
+	if err, ok := fetchErr.Failures["foo"]; !ok || !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected a not found failure for 'foo', got %v", err)
+	}
+	if missing := fetchErr.Missing(); len(missing) != 2 || missing[0] != "bar" {
+		t.Errorf("Expected bar and foo to be missing, got %v", missing)
+	}
+}
+
+func TestFetchCryptoPrices_LastUpdatedAt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("include_last_updated_at") != "true" {
+			t.Errorf("Expected the last update time to be requested, got %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"terra-luna":{"usd":0.00012,"last_updated_at":1652400000}}`))
+	}))
+	defer server.Close()
+
+	client := NewCoinGeckoClient()
+	client.baseURL = server.URL
+
+	prices, err := client.FetchCryptoPrices([]string{"terra-luna"}, models.USD)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if prices[0].LastUpdated != "2022-05-13T00:00:00Z" {
+		t.Errorf("Expected the provider's update time, got %s", prices[0].LastUpdated)
+	}
+}
+
+func TestFetchCryptoPrices_PartialResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"bitcoin":{"usd":50000},"ethereum":{"usd":3000}}`))
+	}))
+	defer server.Close()
+
+	client := NewCoinGeckoClient(WithPartialResults(), WithConcurrency(2))
+	client.baseURL = server.URL
+
+	prices, err := client.FetchCryptoPrices([]string{"bitcoin", "broken", "ethereum"}, models.USD)
+	if err == nil {
+		t.Error("Expected error for failed coin, got nil")
+	}
+	if len(prices) != 2 {
+		t.Fatalf("Expected 2 prices, got %d", len(prices))
+	}
+	if prices[0].ID != "bitcoin" || prices[1].ID != "ethereum" {
+		t.Errorf("Expected prices in input order, got %s, %s", prices[0].ID, prices[1].ID)
+	}
+}
+
+func TestFetchCryptoPrices_BoundedConcurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			current := atomic.LoadInt32(&maxInFlight)
+			if n <= current || atomic.CompareAndSwapInt32(&maxInFlight, current, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		id := r.URL.Query().Get("ids")
+		w.Write([]byte(`{"` + id + `":{"usd":1}}`))
+	}))
+	defer server.Close()
+
+	client := NewCoinGeckoClient(WithConcurrency(2), WithBatchSize(1))
+	client.baseURL = server.URL
+
+	ids := []string{"a", "b", "c", "d", "e", "f"}
+	prices, err := client.FetchCryptoPrices(ids, models.USD)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(prices) != len(ids) {
+		t.Errorf("Expected %d prices, got %d", len(ids), len(prices))
+	}
+	if maxInFlight > 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got %d", maxInFlight)
+	}
+}
+
+func TestFetchCryptoPrices_Batches(t *testing.T) {
+	var mu sync.Mutex
+	var batches []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids := r.URL.Query().Get("ids")
+		mu.Lock()
+		batches = append(batches, ids)
+		mu.Unlock()
+		if strings.Contains(ids, "broken") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		quotes := make([]string, 0)
+		for _, id := range strings.Split(ids, ",") {
+			quotes = append(quotes, `"`+id+`":{"usd":1}`)
+		}
+		w.Write([]byte("{" + strings.Join(quotes, ",") + "}"))
+	}))
+	defer server.Close()
+
+	client := NewCoinGeckoClient(WithBaseURL(server.URL), WithBatchSize(3), WithPartialResults())
+	ids := []string{"a", "b", "c", "d", "broken", "f", "g"}
+	prices, err := client.FetchCryptoPrices(ids, models.USD)
+
+	sort.Strings(batches)
+	if want := []string{"a,b,c", "d,broken,f", "g"}; !slices.Equal(batches, want) {
+		t.Errorf("Expected batches %v, got %v", want, batches)
+	}
+	var fetchErr *FetchError
+	if !errors.As(err, &fetchErr) || len(fetchErr.Failures) != 3 {
+		t.Fatalf("Expected the failed batch to fail its 3 IDs, got %v", err)
+	}
+	if len(prices) != 4 || prices[3].ID != "g" {
+		t.Errorf("Expected the other batches in input order, got %v", prices)
+	}
+}
+
+func TestFetchCryptoPrices_Currency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("vs_currencies"); got != "brl" {
+			t.Errorf("Expected vs_currencies=brl, got %s", got)
+		}
+		w.Write([]byte(`{"bitcoin":{"brl":250000}}`))
+	}))
+	defer server.Close()
+
+	client := NewCoinGeckoClient()
+	client.baseURL = server.URL
+
+	prices, err := client.FetchCryptoPrices([]string{"bitcoin"}, models.BRL)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if prices[0].Currency != models.BRL || !prices[0].CurrentPrice.Equal(decimal.NewFromInt(250000)) {
+		t.Errorf("Expected 250000 BRL, got %s %s", prices[0].CurrentPrice, prices[0].Currency)
+	}
+}
+
+func TestFetchMultiCurrencyPrices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("ids"); got != "bitcoin,ethereum" {
+			t.Errorf("Expected ids=bitcoin,ethereum, got %s", got)
+		}
+		if got := r.URL.Query().Get("vs_currencies"); got != "usd,eur" {
+			t.Errorf("Expected vs_currencies=usd,eur, got %s", got)
+		}
+		w.Write([]byte(`{"bitcoin":{"usd":50000,"eur":45000},"ethereum":{"usd":3000,"eur":2700}}`))
+	}))
+	defer server.Close()
+
+	client := NewCoinGeckoClient()
+	client.baseURL = server.URL
+
+	prices, err := client.FetchMultiCurrencyPrices([]string{"bitcoin", "ethereum"}, []models.Currency{models.USD, models.EUR})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if prices["ethereum"][models.EUR] != 2700 {
+		t.Errorf("Expected ethereum price of 2700 EUR, got %f", prices["ethereum"][models.EUR])
+	}
+}
+
+func TestGetExchangeRates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"bitcoin":{"usd":50000,"eur":45000}}`))
+	}))
+	defer server.Close()
+
+	client := NewCoinGeckoClient()
+	client.baseURL = server.URL
+
+	rates, err := client.GetExchangeRates(models.USD, []models.Currency{models.EUR})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rates.Rates[models.EUR] != 0.9 {
+		t.Errorf("Expected USD->EUR rate of 0.9, got %f", rates.Rates[models.EUR])
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("x-cg-demo-api-key"); got != "secret" {
+			t.Errorf("Expected API key header 'secret', got %q", got)
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewCoinGeckoClient(
+		WithBaseURL(server.URL+"/"),
+		WithAPIKey("secret"),
+		WithTimeout(2*time.Second),
+	)
+
+	if client.baseURL != server.URL {
+		t.Errorf("Expected trailing slash to be trimmed, got %s", client.baseURL)
+	}
+	if client.httpClient.Timeout != 2*time.Second {
+		t.Errorf("Expected timeout of 2s, got %v", client.httpClient.Timeout)
+	}
+	if _, err := client.GetTopNCryptos(10, models.USD); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestGetTopNCryptos_MarketFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/coins/markets" {
+			t.Errorf("Expected /coins/markets, got %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("price_change_percentage"); got != "7d" {
+			t.Errorf("Expected 7d change to be requested, got %q", got)
+		}
+		w.Write([]byte(`[{"id":"bitcoin","symbol":"btc","name":"Bitcoin","current_price":60000,
+			"market_cap":1200000000000,"total_volume":30000000000,"price_change_percentage_24h":1.5,
+			"price_change_percentage_7d_in_currency":-2.25,"circulating_supply":19700000,"ath":73738,
+			"image":"https://assets.coingecko.com/coins/images/1/large/bitcoin.png"}]`))
+	}))
+	defer server.Close()
+
+	client := NewCoinGeckoClient(WithBaseURL(server.URL))
+	prices, err := client.GetTopNCryptos(1, models.EUR)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(prices) != 1 {
+		t.Fatalf("Expected 1 price, got %d", len(prices))
+	}
+
+	btc := prices[0]
+	if btc.Currency != models.EUR || btc.MarketCap != 1.2e12 || btc.Volume24h != 3e10 {
+		t.Errorf("Unexpected market data: %+v", btc)
+	}
+	if btc.PriceChange24h != 1.5 || btc.PriceChange7d != -2.25 {
+		t.Errorf("Expected 24h 1.5%% and 7d -2.25%%, got %f and %f", btc.PriceChange24h, btc.PriceChange7d)
+	}
+	if btc.CirculatingSupply != 19.7e6 || btc.ATH != 73738 {
+		t.Errorf("Expected supply and ATH to be populated, got %+v", btc)
+	}
+	if btc.Image != "https://assets.coingecko.com/coins/images/1/large/bitcoin.png" {
+		t.Errorf("Expected the logo URL, got %q", btc.Image)
+	}
+}
+
+func TestGetTopNCryptos_Paginates(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if got := r.URL.Query().Get("per_page"); got != "250" {
+			t.Errorf("Expected full pages of 250, got %s", got)
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		// CoinGecko lists 600 coins: pages 1 and 2 are full, page 3 has 100
+		count := 250
+		if page == 3 {
+			count = 100
+		}
+		coins := make([]string, count)
+		for i := range coins {
+			coins[i] = fmt.Sprintf(`{"id":"coin-%d","current_price":1}`, (page-1)*250+i+1)
+		}
+		w.Write([]byte("[" + strings.Join(coins, ",") + "]"))
+	}))
+	defer server.Close()
+
+	client := NewCoinGeckoClient(WithBaseURL(server.URL), WithConcurrency(2))
+
+	prices, err := client.GetTopNCryptos(300, models.USD)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(prices) != 300 || prices[0].ID != "coin-1" || prices[299].ID != "coin-300" {
+		t.Errorf("Expected the first 300 coins in rank order, got %d", len(prices))
+	}
+	if requests.Load() != 2 {
+		t.Errorf("Expected 2 pages to be requested, got %d", requests.Load())
+	}
+
+	// Top N is not capped like a universe
+	prices, err = client.GetTopNCryptos(models.MaxUniverseSize+500, models.USD)
+	if err != nil || len(prices) != 600 {
+		t.Errorf("Expected every listed coin when asking for more, got %d (err %v)", len(prices), err)
+	}
+
+	if _, err := client.GetTopNCryptos(0, models.USD); err == nil {
+		t.Error("Expected an error for n = 0")
+	}
+}
+
+func TestGetUniverse(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		// Markets come back in market cap order whatever the requested IDs
+		w.Write([]byte(`[{"id":"ethereum","current_price":3000},{"id":"bitcoin","current_price":60000}]`))
+	}))
+	defer server.Close()
+	client := NewCoinGeckoClient(WithBaseURL(server.URL))
+
+	if _, err := client.GetUniverse(models.Universe{Name: "liquid", Kind: models.UniverseVolume, Size: 2}, models.USD); err != nil {
+		t.Fatalf("Volume universe: %v", err)
+	}
+	if got := queries[0].Get("order"); got != "volume_desc" {
+		t.Errorf("Expected volume order, got %q", got)
+	}
+
+	if _, err := client.GetUniverse(models.Universe{Name: "defi", Kind: models.UniverseCategory, Size: 2, Category: "decentralized-finance-defi"}, models.USD); err != nil {
+		t.Fatalf("Category universe: %v", err)
+	}
+	if got := queries[1].Get("category"); got != "decentralized-finance-defi" || queries[1].Get("order") != "market_cap_desc" {
+		t.Errorf("Expected the category by market cap, got %v", queries[1])
+	}
+
+	prices, err := client.GetUniverse(models.Universe{Name: "majors", Kind: models.UniverseCustom, Coins: []string{"bitcoin", "ethereum"}}, models.USD)
+	if err != nil {
+		t.Fatalf("Custom universe: %v", err)
+	}
+	if got := queries[2].Get("ids"); got != "bitcoin,ethereum" {
+		t.Errorf("Expected the custom coins to be requested by ID, got %q", got)
+	}
+	if len(prices) != 2 || prices[0].ID != "bitcoin" {
+		t.Errorf("Expected the custom order to be kept, got %+v", prices)
+	}
+
+	if _, err := client.GetUniverse(models.Universe{Name: "bad", Kind: models.UniverseCustom}, models.USD); err == nil {
+		t.Error("Expected an invalid universe to be rejected")
+	}
+}
+
+func TestClientLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := NewCoinGeckoClient(
+		WithBaseURL(server.URL),
+		WithLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+	)
+	client.FetchCryptoPrices([]string{"bitcoin"}, models.USD)
+
+	out := buf.String()
+	for _, want := range []string{"upstream request", "path=/simple/price", "status=404", "price fetch failed", "failed=1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in logs, got %s", want, out)
+		}
+	}
+}
+
+func TestGetHistoricalExchangeRates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/coins/bitcoin/history" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("date"); got != "05-03-2024" {
+			t.Errorf("Expected date 05-03-2024, got %s", got)
+		}
+		w.Write([]byte(`{"market_data":{"current_price":{"usd":60000,"brl":300000,"eur":54000}}}`))
+	}))
+	defer server.Close()
+
+	client := NewCoinGeckoClient(WithBaseURL(server.URL))
+
+	date := time.Date(2024, 3, 5, 18, 0, 0, 0, time.UTC)
+	rates, err := client.GetHistoricalExchangeRates(date, models.USD, []models.Currency{models.BRL})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rates.Rates[models.BRL] != 5 {
+		t.Errorf("Expected USD->BRL rate of 5, got %f", rates.Rates[models.BRL])
+	}
+
+	if _, err := client.GetHistoricalExchangeRates(date, models.USD, []models.Currency{models.JPY}); err == nil {
+		t.Error("Expected error for missing currency, got nil")
+	}
+}
+
+func TestGetPriceRange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/coins/bitcoin/market_chart/range" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("vs_currency") != "eur" || q.Get("from") != "1704067200" || q.Get("to") != "1704153600" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"prices":[[1704067200000,40000.5],[1704153600000,41000]]}`))
+	}))
+	defer server.Close()
+
+	client := NewCoinGeckoClient(WithBaseURL(server.URL))
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	points, err := client.GetPriceRange("bitcoin", models.EUR, from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(points) != 2 || points[0].Price != 40000.5 || !points[0].Time.Equal(from) {
+		t.Errorf("Unexpected points: %+v", points)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search" || r.URL.Query().Get("query") != "sol" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"coins":[{"id":"solana","name":"Solana","api_symbol":"solana","symbol":"SOL","market_cap_rank":5,"thumb":"https://img/thumb.png"}],"exchanges":[]}`))
+	}))
+	defer server.Close()
+
+	results, err := NewCoinGeckoClient(WithBaseURL(server.URL)).Search("sol")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "solana" || results[0].MarketCapRank != 5 {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+}
+
+func TestCoinInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/coins/unknown" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"id":"bitcoin","symbol":"btc","name":"Bitcoin","categories":["Layer 1 (L1)",""],
+			"description":{"en":"The first cryptocurrency"},"links":{"homepage":["","http://www.bitcoin.org"]},
+			"image":{"thumb":"t","small":"s","large":"l"},"genesis_date":"2009-01-03","market_cap_rank":1}`))
+	}))
+	defer server.Close()
+	client := NewCoinGeckoClient(WithBaseURL(server.URL))
+
+	info, err := client.CoinInfo("bitcoin")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if info.Homepage != "http://www.bitcoin.org" || len(info.Categories) != 1 || info.Images.Large != "l" || info.GenesisDate != "2009-01-03" {
+		t.Errorf("Unexpected info: %+v", info)
+	}
+
+	if _, err := client.CoinInfo("unknown"); !errors.Is(err, coins.ErrNotFound) {
+		t.Errorf("Expected coins.ErrNotFound, got %v", err)
+	}
+}
+
+func TestGetPriceAt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/coins/bitcoin/history":
+			if got := r.URL.Query().Get("date"); got != "05-03-2024" {
+				t.Errorf("Expected date 05-03-2024, got %s", got)
+			}
+			w.Write([]byte(`{"id":"bitcoin","market_data":{"current_price":{"usd":68000,"eur":62000}}}`))
+		case "/coins/newcoin/history":
+			w.Write([]byte(`{"id":"newcoin"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewCoinGeckoClient(WithBaseURL(server.URL))
+	date := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	price, err := client.GetPriceAt("bitcoin", date, models.EUR)
+	if err != nil || price != 62000 {
+		t.Errorf("Expected 62000, got %f (%v)", price, err)
+	}
+	if _, err := client.GetPriceAt("newcoin", date, models.USD); !errors.Is(err, pricehistory.ErrNoPrice) {
+		t.Errorf("Expected ErrNoPrice, got %v", err)
+	}
+	if _, err := client.GetPriceAt("unknown", date, models.USD); !errors.Is(err, coins.ErrNotFound) {
+		t.Errorf("Expected coins.ErrNotFound, got %v", err)
+	}
+}
+
+func TestGetGlobalData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/global" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"data":{"active_cryptocurrencies":13000,"markets":1100,
+			"total_market_cap":{"usd":2.5e12,"eur":2.3e12},"total_volume":{"usd":9e10,"eur":8.3e10},
+			"market_cap_percentage":{"btc":52.1,"eth":16.9},"market_cap_change_percentage_24h_usd":-1.2,
+			"updated_at":1704067200}}`))
+	}))
+	defer server.Close()
+	client := NewCoinGeckoClient(WithBaseURL(server.URL))
+
+	global, err := client.GetGlobalData(models.EUR)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if global.TotalMarketCap != 2.3e12 || global.TotalVolume != 8.3e10 || global.BTCDominance != 52.1 ||
+		global.ActiveCoins != 13000 || global.UpdatedAt.Year() != 2024 {
+		t.Errorf("Unexpected overview: %+v", global)
+	}
+
+	if _, err := client.GetGlobalData(models.JPY); err == nil {
+		t.Error("Expected an error for a currency without totals")
+	}
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"crypto-dashboard/internal/application/coins"
+)
+
+var (
+	// ErrRateLimited is matched by errors of requests CoinGecko rejected with 429
+	ErrRateLimited = errors.New("coingecko rate limit exceeded")
+	// ErrNotFound is matched by errors of unknown coins. It is coins.ErrNotFound
+	// so callers of the coin service can match it too.
+	ErrNotFound = coins.ErrNotFound
+	// ErrProviderUnavailable is matched by errors of requests that did not get
+	// an answer: network failures, 5xx responses and an open circuit breaker
+	ErrProviderUnavailable = errors.New("coingecko unavailable")
+)
+
+// maxErrorBody is how much of an error response is read for its message
+const maxErrorBody = 4 << 10
+
+// APIError is returned for every non-200 response. It matches ErrRateLimited,
+// ErrNotFound or ErrProviderUnavailable depending on the status code.
+type APIError struct {
+	StatusCode int
+	// Path is the endpoint that failed, without the query
+	Path string
+	// Message is the error reported in the CoinGecko response body, if any
+	Message string
+	// Retry is the delay requested by a Retry-After header
+	Retry time.Duration
+}
+
+// Error describes the status and the upstream message
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("coingecko %s returned status code: %d", e.Path, e.StatusCode)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Is maps the status code to the sentinel errors
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrProviderUnavailable:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+// RetryAfter returns how long to wait before the next request, zero when
+// upstream did not say
+func (e *APIError) RetryAfter() time.Duration {
+	return e.Retry
+}
+
+// checkResponse returns nil for a 200 response and an *APIError otherwise
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	apiErr := &APIError{StatusCode: resp.StatusCode, Path: resp.Request.URL.Path}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.Retry = time.Duration(seconds) * time.Second
+	}
+
+	// CoinGecko reports errors either as {"error": "..."} or, on the
+	// public API, as {"status": {"error_code": 429, "error_message": "..."}}
+	var body struct {
+		Error  string `json:"error"`
+		Status struct {
+			ErrorMessage string `json:"error_message"`
+		} `json:"status"`
+	}
+	if data, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody)); err == nil && json.Unmarshal(data, &body) == nil {
+		apiErr.Message = body.Error
+		if apiErr.Message == "" {
+			apiErr.Message = body.Status.ErrorMessage
+		}
+	}
+	return apiErr
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/coins"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestAPIError_StatusMapping(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header string
+		body   string
+		want   error
+		msg    string
+		retry  time.Duration
+	}{
+		{
+			name:   "rate limited",
+			status: http.StatusTooManyRequests,
+			header: "30",
+			body:   `{"status":{"error_code":429,"error_message":"You've exceeded the Rate Limit"}}`,
+			want:   ErrRateLimited,
+			msg:    "You've exceeded the Rate Limit",
+			retry:  30 * time.Second,
+		},
+		{name: "not found", status: http.StatusNotFound, body: `{"error":"coin not found"}`, want: ErrNotFound, msg: "coin not found"},
+		{name: "unavailable", status: http.StatusServiceUnavailable, body: "<html>maintenance</html>", want: ErrProviderUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.header != "" {
+					w.Header().Set("Retry-After", tt.header)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewCoinGeckoClient(WithBaseURL(server.URL))
+			_, err := client.GetGlobalData(models.USD)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, err)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected an *APIError, got %T", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Path != "/global" || apiErr.Message != tt.msg || apiErr.RetryAfter() != tt.retry {
+				t.Errorf("Unexpected API error: %+v", apiErr)
+			}
+			for _, other := range []error{ErrRateLimited, ErrNotFound, ErrProviderUnavailable} {
+				if other != tt.want && errors.Is(err, other) {
+					t.Errorf("Expected %v not to match %v", err, other)
+				}
+			}
+		})
+	}
+}
+
+func TestAPIError_NotFoundMatchesCoinService(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"coin not found"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := NewCoinGeckoClient(WithBaseURL(server.URL)).CoinInfo("nope")
+	if !errors.Is(err, coins.ErrNotFound) {
+		t.Errorf("Expected coins.ErrNotFound, got %v", err)
+	}
+}
+
+func TestGet_NetworkErrorIsUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	_, err := NewCoinGeckoClient(WithBaseURL(server.URL)).Search("btc")
+	if !errors.Is(err, ErrProviderUnavailable) {
+		t.Errorf("Expected ErrProviderUnavailable, got %v", err)
+	}
+}
+
+func TestFetchCryptoPrices_UnknownCoinIsNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	_, err := NewCoinGeckoClient(WithBaseURL(server.URL)).FetchCryptoPrices([]string{"nope"}, models.USD)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FixtureMode selects what the fixture transport does with requests
+type FixtureMode string
+
+const (
+	// FixturesOff sends every request upstream
+	FixturesOff FixtureMode = ""
+	// FixturesRecord sends requests upstream and writes every response to the fixture directory
+	FixturesRecord FixtureMode = "record"
+	// FixturesReplay serves responses from the fixture directory without any network access
+	FixturesReplay FixtureMode = "replay"
+)
+
+// ParseFixtureMode validates a fixture mode name; "off" and "" disable fixtures
+func ParseFixtureMode(name string) (FixtureMode, error) {
+	switch mode := FixtureMode(strings.ToLower(name)); mode {
+	case FixturesOff, FixturesRecord, FixturesReplay:
+		return mode, nil
+	case "off":
+		return FixturesOff, nil
+	}
+	return "", fmt.Errorf("unknown fixture mode %q, expected off, record or replay", name)
+}
+
+// ErrNoFixture is returned in replay mode for requests nothing was recorded for
+var ErrNoFixture = errors.New("no recorded fixture")
+
+// Fixtures records CoinGecko responses to a directory and replays them, so
+// the interfaces can be developed and tested offline without using up the rate limit.
+//
+// Every response is stored as <path>-<query hash>.json. On replay an exact
+// match is preferred and <path>.json is the fallback, so hand-written canned
+// responses can serve any query of an endpoint.
+type Fixtures struct {
+	Dir  string
+	Mode FixtureMode
+}
+
+// WithFixtures records or replays responses as configured. It has no effect
+// when the mode is FixturesOff.
+func WithFixtures(f Fixtures) Option {
+	return func(c *CoinGeckoClient) {
+		c.fixtures = f
+	}
+}
+
+// fixture is a recorded response. JSON bodies are kept as JSON so fixtures
+// stay readable and editable; anything else is stored as text.
+type fixture struct {
+	URL         string          `json:"url"`
+	Status      int             `json:"status"`
+	ContentType string          `json:"content_type,omitempty"`
+	RetryAfter  string          `json:"retry_after,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+	Text        string          `json:"text,omitempty"`
+}
+
+// Transport wraps next according to the mode
+func (f Fixtures) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	switch f.Mode {
+	case FixturesRecord:
+		return recordTransport{dir: f.Dir, next: next}
+	case FixturesReplay:
+		return replayTransport{dir: f.Dir}
+	}
+	return next
+}
+
+// fixtureNames returns the exact and the fallback fixture file names of a request
+func fixtureNames(req *http.Request) (exact, fallback string) {
+	fallback = strings.ReplaceAll(strings.Trim(req.URL.Path, "/"), "/", "_")
+	if fallback == "" {
+		fallback = "root"
+	}
+	query := req.URL.Query().Encode()
+	if query == "" {
+		return fallback + ".json", fallback + ".json"
+	}
+	sum := sha256.Sum256([]byte(query))
+	return fallback + "-" + hex.EncodeToString(sum[:6]) + ".json", fallback + ".json"
+}
+
+type recordTransport struct {
+	dir  string
+	next http.RoundTripper
+}
+
+func (t recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// The API key is sent as a header, so the URL holds no secret
+	rec := fixture{
+		URL:         req.URL.String(),
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		RetryAfter:  resp.Header.Get("Retry-After"),
+	}
+	if json.Valid(body) {
+		rec.Body = body
+	} else {
+		rec.Text = string(body)
+	}
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	exact, _ := fixtureNames(req)
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to record fixture: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(t.dir, exact), append(data, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to record fixture: %w", err)
+	}
+	return resp, nil
+}
+
+type replayTransport struct {
+	dir string
+}
+
+func (t replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	exact, fallback := fixtureNames(req)
+	data, err := os.ReadFile(filepath.Join(t.dir, exact))
+	if errors.Is(err, os.ErrNotExist) {
+		data, err = os.ReadFile(filepath.Join(t.dir, fallback))
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w for %s (%s)", ErrNoFixture, req.URL.RequestURI(), exact)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rec fixture
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", exact, err)
+	}
+	body := []byte(rec.Body)
+	if len(body) == 0 {
+		body = []byte(rec.Text)
+	}
+	if rec.Status == 0 {
+		rec.Status = http.StatusOK
+	}
+	header := http.Header{}
+	if rec.ContentType != "" {
+		header.Set("Content-Type", rec.ContentType)
+	}
+	if rec.RetryAfter != "" {
+		header.Set("Retry-After", rec.RetryAfter)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		StatusCode:    rec.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
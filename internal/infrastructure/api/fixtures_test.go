@@ -0,0 +1,76 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestFixtures_RecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("query") == "limited" {
+			w.Header().Set("Retry-After", "5")
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"coins":[{"id":"bitcoin","symbol":"btc","name":"Bitcoin"}]}`))
+	}))
+
+	recorder := NewCoinGeckoClient(WithBaseURL(server.URL), WithFixtures(Fixtures{Dir: dir, Mode: FixturesRecord}))
+	if _, err := recorder.Search("btc"); err != nil {
+		t.Fatalf("Search while recording: %v", err)
+	}
+	recorder.Search("limited")
+	server.Close()
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Fatalf("Expected 2 recorded fixtures, got %d", len(entries))
+	}
+
+	replayer := NewCoinGeckoClient(WithBaseURL(server.URL), WithFixtures(Fixtures{Dir: dir, Mode: FixturesReplay}))
+	results, err := replayer.Search("btc")
+	if err != nil || len(results) != 1 || results[0].ID != "bitcoin" {
+		t.Fatalf("Expected the recorded search, got %v (%v)", results, err)
+	}
+	_, err = replayer.Search("limited")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, ErrRateLimited) || apiErr.RetryAfter().Seconds() != 5 {
+		t.Errorf("Expected the recorded rate limit, got %v", err)
+	}
+	if _, err := replayer.Search("eth"); !errors.Is(err, ErrNoFixture) {
+		t.Errorf("Expected ErrNoFixture for an unrecorded query, got %v", err)
+	}
+}
+
+// TestFixtures_Canned checks that the canned responses shipped for offline
+// development still decode
+func TestFixtures_Canned(t *testing.T) {
+	client := NewCoinGeckoClient(WithFixtures(Fixtures{Dir: "../../../testdata/coingecko", Mode: FixturesReplay}))
+
+	prices, err := client.FetchCryptoPrices([]string{"bitcoin", "dogecoin"}, models.EUR)
+	if err != nil || len(prices) != 2 || prices[1].CurrentPrice.String() != "0.1139" {
+		t.Errorf("Unexpected canned prices: %v (%v)", prices, err)
+	}
+	if top, err := client.GetTopNCryptos(10, models.USD); err != nil || len(top) != 4 {
+		t.Errorf("Unexpected canned markets: %d (%v)", len(top), err)
+	}
+	if global, err := client.GetGlobalData(models.USD); err != nil || global.BTCDominance != 52.5 {
+		t.Errorf("Unexpected canned global data: %+v (%v)", global, err)
+	}
+}
+
+func TestParseFixtureMode(t *testing.T) {
+	for name, want := range map[string]FixtureMode{"": FixturesOff, "off": FixturesOff, "Replay": FixturesReplay, "record": FixturesRecord} {
+		if got, err := ParseFixtureMode(name); err != nil || got != want {
+			t.Errorf("ParseFixtureMode(%q) = %q, %v", name, got, err)
+		}
+	}
+	if _, err := ParseFixtureMode("mock"); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+}
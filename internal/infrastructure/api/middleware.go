@@ -0,0 +1,71 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Middleware wraps a transport, e.g. to log, measure, cache or authenticate
+// requests. metrics.Metrics.InstrumentTransport is one.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to http.RoundTripper
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Chain wraps base, http.DefaultTransport when nil, in the middlewares. The
+// first middleware is the outermost and sees every request first. Clients of
+// other providers, which take an *http.Client, can share a chain through it.
+func Chain(base http.RoundTripper, middlewares ...Middleware) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		base = middlewares[i](base)
+	}
+	return base
+}
+
+// WithMiddleware adds middlewares around the client transport. They run in
+// the order given, inside the circuit breaker and around fixtures, so they see
+// replayed requests but not those the open breaker rejects.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *CoinGeckoClient) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// SetHeader sets a header on every request, e.g. Proxy-Authorization for a
+// corporate proxy. Requests are cloned rather than modified.
+func SetHeader(name, value string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.Header.Set(name, value)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// LogRequests logs every request with its status and duration at the level
+func LogRequests(logger *slog.Logger, level slog.Level) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			attrs := []any{"method", req.Method, "host", req.URL.Host, "path", req.URL.Path, "duration", time.Since(start)}
+			if err != nil {
+				attrs = append(attrs, "error", err)
+			} else {
+				attrs = append(attrs, "status", resp.StatusCode)
+			}
+			logger.Log(req.Context(), level, "http request", attrs...)
+			return resp, err
+		})
+	}
+}
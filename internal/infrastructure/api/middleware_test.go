@@ -0,0 +1,82 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// recordingMiddleware appends its name to calls on every request
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			*calls = append(*calls, name)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	var calls []string
+	base := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls = append(calls, "base:"+req.Header.Get("Proxy-Authorization"))
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	transport := Chain(base, recordingMiddleware("first", &calls), SetHeader("Proxy-Authorization", "Basic abc"), recordingMiddleware("last", &calls))
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := strings.Join(calls, ","); got != "first,last,base:Basic abc" {
+		t.Errorf("Expected the middlewares in order, got %s", got)
+	}
+	if req.Header.Get("Proxy-Authorization") != "" {
+		t.Error("Expected the caller's request to be left unchanged")
+	}
+}
+
+func TestWithMiddleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Team") != "desk" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"bitcoin": {"usd": 50000}}`))
+	}))
+	defer server.Close()
+
+	var calls []string
+	var logs bytes.Buffer
+	breaker := NewBreaker(1, time.Minute)
+	client := NewCoinGeckoClient(
+		WithBaseURL(server.URL),
+		WithMiddleware(recordingMiddleware("outer", &calls), SetHeader("X-Team", "desk")),
+		WithMiddleware(LogRequests(slog.New(slog.NewTextHandler(&logs, nil)), slog.LevelInfo)),
+		WithBreaker(breaker),
+	)
+
+	if _, err := client.FetchCryptoPrices([]string{"bitcoin"}, models.USD); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(calls) != 1 || !strings.Contains(logs.String(), "path=/simple/price") || !strings.Contains(logs.String(), "status=200") {
+		t.Errorf("Expected the request to go through every middleware, got %v and %q", calls, logs.String())
+	}
+
+	// Requests the open breaker rejects never reach the middlewares
+	breaker.Allow()
+	breaker.Record(false)
+	if _, err := client.FetchCryptoPrices([]string{"bitcoin"}, models.USD); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected the breaker to reject the request, got %v", err)
+	}
+	if len(calls) != 1 {
+		t.Errorf("Expected the rejected request to skip the middlewares, got %v", calls)
+	}
+}
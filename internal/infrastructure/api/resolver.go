@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+)
+
+// dialTimeout matches the connect timeout of http.DefaultTransport
+const dialTimeout = 30 * time.Second
+
+// hostLookup resolves host names; *net.Resolver is one
+type hostLookup interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// Resolver caches provider host lookups and tries fallback DNS servers when
+// the system resolver fails, so a brief DNS outage does not fail every poll.
+// When every resolver fails, the last known addresses are used for up to
+// maxStale after they expired.
+type Resolver struct {
+	ttl      time.Duration
+	maxStale time.Duration
+	lookups  []hostLookup
+	dialer   net.Dialer
+	clock    clock.Clock
+
+	mu    sync.Mutex
+	cache map[string]dnsEntry
+}
+
+// dnsEntry is the cached addresses of a host
+type dnsEntry struct {
+	addrs    []string
+	resolved time.Time
+}
+
+// NewResolver returns a resolver caching lookups for ttl, zero disabling the
+// cache, that falls back to the DNS servers (ip or ip:port) in order
+func NewResolver(ttl, maxStale time.Duration, fallbacks ...string) *Resolver {
+	r := &Resolver{
+		ttl:      ttl,
+		maxStale: maxStale,
+		lookups:  []hostLookup{net.DefaultResolver},
+		dialer:   net.Dialer{Timeout: dialTimeout, KeepAlive: 30 * time.Second},
+		clock:    clock.Real,
+		cache:    make(map[string]dnsEntry),
+	}
+	for _, server := range fallbacks {
+		r.lookups = append(r.lookups, dnsServer(server))
+	}
+	return r
+}
+
+// SetClock replaces the system clock, for tests
+func (r *Resolver) SetClock(c clock.Clock) {
+	r.clock = c
+}
+
+// dnsServer returns a resolver querying only the given server
+func dnsServer(server string) *net.Resolver {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, server)
+		},
+	}
+}
+
+// LookupHost returns the addresses of host from the cache or the first
+// resolver answering
+func (r *Resolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	r.mu.Lock()
+	entry, cached := r.cache[host]
+	r.mu.Unlock()
+	age := r.clock.Now().Sub(entry.resolved)
+	if cached && age < r.ttl {
+		return entry.addrs, nil
+	}
+
+	var errs []error
+	for _, lookup := range r.lookups {
+		addrs, err := lookup.LookupHost(ctx, host)
+		if err == nil && len(addrs) > 0 {
+			if r.ttl > 0 {
+				r.mu.Lock()
+				r.cache[host] = dnsEntry{addrs: addrs, resolved: r.clock.Now()}
+				r.mu.Unlock()
+			}
+			return addrs, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err == nil {
+			err = &net.DNSError{Err: "no addresses", Name: host, IsNotFound: true}
+		}
+		errs = append(errs, err)
+	}
+	if cached && age < r.ttl+r.maxStale {
+		return entry.addrs, nil
+	}
+	return nil, errors.Join(errs...)
+}
+
+// DialContext resolves the host of addr through the resolver and connects to
+// its addresses in turn. It can be used as http.Transport.DialContext.
+func (r *Resolver) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil {
+		return r.dialer.DialContext(ctx, network, addr)
+	}
+	addrs, err := r.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, ip := range addrs {
+		conn, err := r.dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+		if err == nil {
+			return conn, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// fakeLookup answers with addrs, or err when set, and counts the lookups
+type fakeLookup struct {
+	addrs []string
+	err   error
+	calls int
+}
+
+func (f *fakeLookup) LookupHost(context.Context, string) ([]string, error) {
+	f.calls++
+	return f.addrs, f.err
+}
+
+func TestResolver_CachesAndFallsBack(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	system := &fakeLookup{addrs: []string{"192.0.2.1"}}
+	fallback := &fakeLookup{addrs: []string{"192.0.2.2"}}
+	r := NewResolver(time.Minute, time.Hour)
+	r.lookups = []hostLookup{system, fallback}
+	clk := clock.NewFake(now)
+	r.SetClock(clk)
+
+	for range 3 {
+		if addrs, err := r.LookupHost(context.Background(), "api.coingecko.com"); err != nil || addrs[0] != "192.0.2.1" {
+			t.Fatalf("Expected the system answer, got %v and %v", addrs, err)
+		}
+	}
+	if system.calls != 1 {
+		t.Errorf("Expected lookups within the TTL to be cached, got %d lookups", system.calls)
+	}
+
+	clk.Advance(2 * time.Minute)
+	system.err = errors.New("server misbehaving")
+	if addrs, err := r.LookupHost(context.Background(), "api.coingecko.com"); err != nil || addrs[0] != "192.0.2.2" {
+		t.Errorf("Expected the fallback answer, got %v and %v", addrs, err)
+	}
+
+	// With every resolver down the expired addresses are used until maxStale
+	clk.Advance(2 * time.Minute)
+	fallback.err = errors.New("i/o timeout")
+	if addrs, err := r.LookupHost(context.Background(), "api.coingecko.com"); err != nil || addrs[0] != "192.0.2.2" {
+		t.Errorf("Expected the stale answer, got %v and %v", addrs, err)
+	}
+	clk.Advance(2 * time.Hour)
+	if _, err := r.LookupHost(context.Background(), "api.coingecko.com"); err == nil {
+		t.Error("Expected an error once the cached answer is too old")
+	}
+}
+
+func TestResolver_WithoutCache(t *testing.T) {
+	system := &fakeLookup{addrs: []string{"192.0.2.1"}}
+	r := NewResolver(0, time.Hour)
+	r.lookups = []hostLookup{system}
+	r.LookupHost(context.Background(), "api.coingecko.com")
+	system.err = errors.New("server misbehaving")
+	if _, err := r.LookupHost(context.Background(), "api.coingecko.com"); err == nil || system.calls != 2 {
+		t.Errorf("Expected a zero TTL to disable caching, got %v after %d lookups", err, system.calls)
+	}
+}
+
+func TestResolver_DialContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"bitcoin": {"usd": 50000}}`))
+	}))
+	defer server.Close()
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+
+	r := NewResolver(time.Minute, time.Hour)
+	r.lookups = []hostLookup{&fakeLookup{addrs: []string{"127.0.0.2", "127.0.0.1"}}}
+	transport, err := NewTransport(TransportConfig{Resolver: r})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client := NewCoinGeckoClient(WithBaseURL("http://coingecko.test:"+port), WithTransport(transport))
+	if _, err := client.FetchCryptoPrices([]string{"bitcoin"}, models.USD); err != nil {
+		t.Errorf("Expected the next address to be tried, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// TransportConfig configures the proxy and TLS settings of outbound provider
+// requests, for deployments behind corporate proxies or TLS-intercepting firewalls
+type TransportConfig struct {
+	// Proxy is an http, https, socks5 or socks5h URL. The HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY environment variables apply when it is empty.
+	Proxy string
+	// NoProxy lists the hosts, domains (.example.com) and CIDR ranges reached
+	// without the proxy
+	NoProxy []string
+	// CAFile is a PEM bundle of certificate authorities trusted in addition
+	// to the system ones
+	CAFile string
+	// CertFile and KeyFile are a PEM client certificate for mutual TLS
+	CertFile string
+	KeyFile  string
+	// MinVersion is the oldest TLS version accepted; zero keeps Go's default
+	MinVersion uint16
+	// InsecureSkipVerify disables certificate verification; only for debugging
+	InsecureSkipVerify bool
+	// Resolver resolves the hosts connected to, the proxy's included, when set
+	Resolver *Resolver
+}
+
+// ParseTLSVersion parses a TLS version like 1.2; "" is the zero version
+func ParseTLSVersion(name string) (uint16, error) {
+	switch name {
+	case "":
+		return 0, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("unsupported TLS version %q, expected 1.2 or 1.3", name)
+}
+
+// ParseProxyURL validates a proxy URL
+func ParseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q, expected http, https, socks5 or socks5h", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, errors.New("proxy URL has no host")
+	}
+	return u, nil
+}
+
+// NewTransport returns a copy of http.DefaultTransport with the proxy and TLS
+// settings applied
+func NewTransport(cfg TransportConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.Proxy != "" {
+		u, err := ParseProxyURL(cfg.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		proxy := (&httpproxy.Config{
+			HTTPProxy:  u.String(),
+			HTTPSProxy: u.String(),
+			NoProxy:    strings.Join(cfg.NoProxy, ","),
+		}).ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxy(req.URL)
+		}
+	}
+
+	if cfg.Resolver != nil {
+		transport.DialContext = cfg.Resolver.DialContext
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         cfg.MinVersion,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+)
+
+// usageDays is how many UTC days of usage are kept for reporting
+const usageDays = 31
+
+// UsageObserver is notified about provider calls and downloads, e.g. to export metrics
+type UsageObserver interface {
+	ProviderCall(provider string)
+	ProviderDownload(provider string, bytes int64)
+}
+
+// ProviderUsage is the traffic to a provider on a UTC day
+type ProviderUsage struct {
+	Provider string `json:"provider"`
+	Day      string `json:"day"`
+	Calls    int64  `json:"calls"`
+	Bytes    int64  `json:"bytes"`
+}
+
+// Budget is the daily call budget of a provider and what is left of it today
+type Budget struct {
+	Provider   string `json:"provider"`
+	DailyCalls int64  `json:"daily_calls"`
+	Remaining  int64  `json:"remaining"`
+	// Throttled is set once no more than the reserve is left, which is kept
+	// for the poller and user requests
+	Throttled bool `json:"throttled"`
+}
+
+type usageKey struct {
+	provider string
+	day      string
+}
+
+type budget struct {
+	calls   int64
+	reserve int64
+}
+
+// Usage counts the calls made to each provider and the bytes downloaded from
+// it per UTC day, and tracks their daily call budgets
+type Usage struct {
+	clock    clock.Clock
+	observer UsageObserver
+
+	mu      sync.Mutex
+	days    map[usageKey]*ProviderUsage
+	budgets map[string]budget
+}
+
+// NewUsage creates an empty usage tracker
+func NewUsage() *Usage {
+	return &Usage{clock: clock.Real, days: make(map[usageKey]*ProviderUsage), budgets: make(map[string]budget)}
+}
+
+// SetClock replaces the system clock, for tests
+func (u *Usage) SetClock(c clock.Clock) {
+	u.clock = c
+}
+
+// SetObserver registers an observer. It must be called before any request.
+func (u *Usage) SetObserver(observer UsageObserver) {
+	u.observer = observer
+}
+
+// SetBudget sets the daily call budget of a provider. Low-priority jobs are
+// throttled once the calls left today fall to the reserve, a fraction of the
+// budget.
+func (u *Usage) SetBudget(provider string, dailyCalls int64, reserve float64) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.budgets[provider] = budget{calls: dailyCalls, reserve: int64(float64(dailyCalls) * reserve)}
+}
+
+// Middleware counts the requests to the provider and the response bytes read
+func (u *Usage) Middleware(provider string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			entry := u.call(provider)
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				return nil, err
+			}
+			resp.Body = &countingBody{ReadCloser: resp.Body, count: func(n int64) { u.download(provider, entry, n) }}
+			return resp, nil
+		})
+	}
+}
+
+// Throttled reports whether the provider's calls today reached its reserve,
+// in which case low-priority jobs should skip their refresh
+func (u *Usage) Throttled(provider string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	b, ok := u.budgets[provider]
+	return ok && b.calls-u.today(provider).Calls <= b.reserve
+}
+
+// Days returns the usage of every provider, most recent day first
+func (u *Usage) Days() []ProviderUsage {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	days := make([]ProviderUsage, 0, len(u.days))
+	for _, d := range u.days {
+		days = append(days, *d)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		if days[i].Day != days[j].Day {
+			return days[i].Day > days[j].Day
+		}
+		return days[i].Provider < days[j].Provider
+	})
+	return days
+}
+
+// Budgets returns the budget of every provider that has one, by provider
+func (u *Usage) Budgets() []Budget {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	budgets := make([]Budget, 0, len(u.budgets))
+	for provider, b := range u.budgets {
+		remaining := max(b.calls-u.today(provider).Calls, 0)
+		budgets = append(budgets, Budget{Provider: provider, DailyCalls: b.calls, Remaining: remaining, Throttled: remaining <= b.reserve})
+	}
+	sort.Slice(budgets, func(i, j int) bool { return budgets[i].Provider < budgets[j].Provider })
+	return budgets
+}
+
+// call records a request and returns the day entry its download is added to
+func (u *Usage) call(provider string) *ProviderUsage {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	entry := u.today(provider)
+	if entry.Calls == 0 {
+		u.days[usageKey{provider, entry.Day}] = entry
+		u.prune()
+	}
+	entry.Calls++
+	if u.observer != nil {
+		u.observer.ProviderCall(provider)
+	}
+	return entry
+}
+
+func (u *Usage) download(provider string, entry *ProviderUsage, n int64) {
+	u.mu.Lock()
+	entry.Bytes += n
+	u.mu.Unlock()
+	if u.observer != nil {
+		u.observer.ProviderDownload(provider, n)
+	}
+}
+
+// today returns the provider's entry of the current UTC day, which is only
+// stored once a call is made. The caller must hold the lock.
+func (u *Usage) today(provider string) *ProviderUsage {
+	day := u.clock.Now().UTC().Format(time.DateOnly)
+	if entry, ok := u.days[usageKey{provider, day}]; ok {
+		return entry
+	}
+	return &ProviderUsage{Provider: provider, Day: day}
+}
+
+// prune drops the days older than the reporting window. The caller must hold the lock.
+func (u *Usage) prune() {
+	oldest := u.clock.Now().UTC().AddDate(0, 0, -usageDays+1).Format(time.DateOnly)
+	for key := range u.days {
+		if key.day < oldest {
+			delete(u.days, key)
+		}
+	}
+}
+
+// countingBody reports the bytes read from a response body
+type countingBody struct {
+	io.ReadCloser
+	count func(n int64)
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.count(int64(n))
+	}
+	return n, err
+}
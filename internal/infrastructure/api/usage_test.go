@@ -0,0 +1,65 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+)
+
+func TestUsage(t *testing.T) {
+	c := clock.NewFake(time.Date(2024, 3, 5, 23, 0, 0, 0, time.UTC))
+	u := NewUsage()
+	u.SetClock(c)
+	u.SetBudget("coingecko", 10, 0.2)
+	base := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("0123456789"))}, nil
+	})
+	transport := Chain(base, u.Middleware("coingecko"))
+	get := func() {
+		req, _ := http.NewRequest(http.MethodGet, "https://api.coingecko.com/api/v3/ping", nil)
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	for range 7 {
+		get()
+	}
+	if u.Throttled("coingecko") || u.Throttled("kraken") {
+		t.Error("Expected no throttling with three calls left above the reserve of two")
+	}
+	get()
+	if !u.Throttled("coingecko") {
+		t.Error("Expected low-priority jobs to be throttled at the reserve")
+	}
+	if got := u.Days(); len(got) != 1 || got[0].Calls != 8 || got[0].Bytes != 80 || got[0].Day != "2024-03-05" {
+		t.Errorf("Unexpected usage: %+v", got)
+	}
+
+	// The budget starts over with the UTC day
+	c.Advance(time.Hour)
+	get()
+	if u.Throttled("coingecko") {
+		t.Error("Expected the budget to reset on a new day")
+	}
+	days := u.Days()
+	if len(days) != 2 || days[0].Day != "2024-03-06" || days[0].Calls != 1 {
+		t.Errorf("Expected the days most recent first, got %+v", days)
+	}
+	if b := u.Budgets(); len(b) != 1 || b[0].Remaining != 9 || b[0].Throttled {
+		t.Errorf("Unexpected budgets: %+v", b)
+	}
+
+	c.Advance(usageDays * 24 * time.Hour)
+	get()
+	if days := u.Days(); len(days) != 1 {
+		t.Errorf("Expected days past the window to be pruned, got %+v", days)
+	}
+}
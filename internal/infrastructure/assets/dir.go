@@ -0,0 +1,68 @@
+package assets
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// coinID matches the coin IDs that are stored; anything else could escape the directory
+var coinID = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Dir stores original logos as files named after the coin
+type Dir struct {
+	path string
+}
+
+// NewDir creates the directory when missing
+func NewDir(path string) (*Dir, error) {
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return nil, err
+	}
+	return &Dir{path: path}, nil
+}
+
+// Icon implements icons.Repository
+func (d *Dir) Icon(cryptoID string) ([]byte, bool, error) {
+	name, err := d.file(cryptoID)
+	if err != nil {
+		return nil, false, err
+	}
+	data, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	return data, err == nil, err
+}
+
+// SaveIcon implements icons.Repository. The file is replaced atomically so
+// concurrent readers never see a partial image.
+func (d *Dir) SaveIcon(cryptoID string, data []byte) error {
+	name, err := d.file(cryptoID)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(d.path, ".icon-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+func (d *Dir) file(cryptoID string) (string, error) {
+	if !coinID.MatchString(cryptoID) {
+		return "", fmt.Errorf("invalid coin ID %q", cryptoID)
+	}
+	return filepath.Join(d.path, cryptoID+".img"), nil
+}
@@ -0,0 +1,36 @@
+package assets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDir(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "icons")
+	d, err := NewDir(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok, err := d.Icon("bitcoin"); ok || err != nil {
+		t.Fatalf("Expected no icon before it is saved, got %v", err)
+	}
+	if err := d.SaveIcon("bitcoin", []byte("png")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// A new store over the same directory serves the icon, e.g. after a restart
+	reopened, _ := NewDir(path)
+	if got, ok, err := reopened.Icon("bitcoin"); !ok || err != nil || string(got) != "png" {
+		t.Errorf("Expected the saved icon, got %q (%v)", got, err)
+	}
+	if entries, _ := os.ReadDir(path); len(entries) != 1 {
+		t.Errorf("Expected only the icon file, got %d entries", len(entries))
+	}
+
+	for _, id := range []string{"../secret", "", ".hidden", "a/b"} {
+		if err := d.SaveIcon(id, []byte("png")); err == nil {
+			t.Errorf("Expected %q to be rejected", id)
+		}
+	}
+}
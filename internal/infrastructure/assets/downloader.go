@@ -0,0 +1,60 @@
+// Package assets downloads coin logos and stores them on disk, so icons
+// survive restarts and are served while the provider is unreachable
+package assets
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MaxImageBytes bounds the size of a downloaded image
+const MaxImageBytes = 2 << 20
+
+var defaultClient = &http.Client{Timeout: 30 * time.Second}
+
+// Downloader fetches images over HTTP(S)
+type Downloader struct {
+	Client *http.Client
+}
+
+// Download implements icons.Downloader. Only HTTP(S) URLs answering with an
+// image of at most MaxImageBytes are accepted.
+func (d Downloader) Download(rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid image URL %q", rawURL)
+	}
+	client := d.Client
+	if client == nil {
+		client = defaultClient
+	}
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "image/*")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("image host returned status code: %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.HasPrefix(ct, "image/") {
+		return nil, fmt.Errorf("image host returned %s", ct)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxImageBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxImageBytes {
+		return nil, errors.New("image exceeds the size limit")
+	}
+	return data, nil
+}
@@ -0,0 +1,37 @@
+package assets
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDownloader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/logo.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("png"))
+		case "/page":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html>"))
+		case "/huge.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(bytes.Repeat([]byte{0}, MaxImageBytes+1))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	d := Downloader{Client: server.Client()}
+
+	if data, err := d.Download(server.URL + "/logo.png"); err != nil || string(data) != "png" {
+		t.Errorf("Expected the image, got %q (%v)", data, err)
+	}
+	for _, url := range []string{server.URL + "/page", server.URL + "/huge.png", server.URL + "/missing.png", "file:///etc/passwd"} {
+		if _, err := d.Download(url); err == nil {
+			t.Errorf("Expected %s to be rejected", url)
+		}
+	}
+}
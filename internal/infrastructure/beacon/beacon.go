@@ -0,0 +1,50 @@
+// Package beacon posts telemetry reports to a collection endpoint
+package beacon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"crypto-dashboard/internal/application/telemetry"
+)
+
+// defaultClient is used without an HTTP client
+var defaultClient = &http.Client{Timeout: 10 * time.Second}
+
+// Beacon sends each report as a JSON POST to URL
+type Beacon struct {
+	URL    string
+	Client *http.Client
+}
+
+// Send implements telemetry.Sender. Only the report is sent: no cookies,
+// credentials or headers beyond the content type.
+func (b Beacon) Send(ctx context.Context, r telemetry.Report) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := b.Client
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("telemetry endpoint returned status code: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/telemetry"
+)
+
+func TestBeacon_Send(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected a JSON POST, got %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		if r.Header.Get("Cookie") != "" || r.Header.Get("Authorization") != "" {
+			t.Error("Expected no credentials to be sent")
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	report := telemetry.Report{Version: "1.0", From: time.Unix(0, 0).UTC(), To: time.Unix(3600, 0).UTC(), Features: map[string]int64{"GET /lite": 3}}
+	if err := (Beacon{URL: srv.URL}).Send(context.Background(), report); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 4 || got["features"].(map[string]any)["GET /lite"] != float64(3) {
+		t.Errorf("Expected exactly the report to be sent, got %v", got)
+	}
+}
+
+func TestBeacon_SendFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	if err := (Beacon{URL: srv.URL}).Send(context.Background(), telemetry.Report{}); err == nil {
+		t.Error("Expected a non-2xx status to fail")
+	}
+}
@@ -0,0 +1,53 @@
+// Package coinseed bundles a snapshot of the largest coins so symbol
+// resolution and search work on first start, before the coin list is synced
+// with the provider, and while the provider is unreachable.
+package coinseed
+
+import (
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Regenerate the snapshot from the provider's current market cap ranking
+//go:generate sh -c "go run ../../../cmd/server export coins -top 500 -out coins.json.gz"
+
+//go:embed coins.json.gz
+var snapshot []byte
+
+// Load returns the bundled coins, ordered by market cap rank
+func Load() ([]models.SearchResult, error) {
+	return Read(bytes.NewReader(snapshot))
+}
+
+// Read decodes a gzipped JSON array of coins
+func Read(r io.Reader) ([]models.SearchResult, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid coin snapshot: %w", err)
+	}
+	defer zr.Close()
+	var coins []models.SearchResult
+	if err := json.NewDecoder(zr).Decode(&coins); err != nil {
+		return nil, fmt.Errorf("invalid coin snapshot: %w", err)
+	}
+	return coins, nil
+}
+
+// Write encodes coins in the snapshot format
+func Write(w io.Writer, coins []models.SearchResult) error {
+	zw, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(zw).Encode(coins); err != nil {
+		zw.Close()
+		return err
+	}
+	return zw.Close()
+}
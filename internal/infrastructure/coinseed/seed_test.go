@@ -0,0 +1,59 @@
+package coinseed
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"crypto-dashboard/internal/application/coins"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestLoad(t *testing.T) {
+	coins, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coins) < 100 || coins[0].ID != "bitcoin" || coins[0].Symbol != "BTC" || coins[0].MarketCapRank != 1 {
+		t.Errorf("Expected the largest coins ranked from bitcoin down, got %d coins starting with %+v", len(coins), coins[0])
+	}
+	seen := make(map[string]bool, len(coins))
+	for _, c := range coins {
+		if c.ID == "" || c.Symbol == "" || c.Name == "" || seen[c.ID] {
+			t.Errorf("Invalid or duplicate coin %+v", c)
+		}
+		seen[c.ID] = true
+	}
+}
+
+func TestLoad_WithinCatalog(t *testing.T) {
+	snapshot, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The catalog sync replaces the bundle wholesale, so it never needs more
+	// coins than the catalog keeps
+	if len(snapshot) > coins.CatalogSize {
+		t.Errorf("Snapshot has %d coins, more than the %d the catalog keeps", len(snapshot), coins.CatalogSize)
+	}
+	for _, c := range snapshot {
+		if c.Thumb != "" && !strings.HasPrefix(c.Thumb, "https://") {
+			t.Errorf("Expected an https logo for %s, got %q", c.ID, c.Thumb)
+		}
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	coins := []models.SearchResult{{ID: "bitcoin", Symbol: "BTC", Name: "Bitcoin", MarketCapRank: 1}}
+	var buf bytes.Buffer
+	if err := Write(&buf, coins); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read(&buf)
+	if err != nil || len(got) != 1 || got[0] != coins[0] {
+		t.Errorf("Expected the coins back, got %+v, %v", got, err)
+	}
+	if _, err := Read(bytes.NewReader([]byte("not gzip"))); err == nil {
+		t.Error("Expected an invalid snapshot to fail")
+	}
+}
@@ -0,0 +1,196 @@
+// Package envelope implements envelope encryption for stored values: each
+// file gets a random data key that encrypts its values, and only that key is
+// encrypted, or wrapped, with a master key. Rotating a master key re-wraps the
+// data key without touching the values.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the size in bytes of master and data keys (AES-256)
+const KeySize = 32
+
+// sealedPrefix marks an encrypted value and its format version
+const sealedPrefix = "enc:v1:"
+
+// ErrUnknownKey is returned for data keys wrapped with a master key that is not in the keyring
+var ErrUnknownKey = errors.New("unknown master key")
+
+// Header identifies the data key of a file: its ID is the master key that
+// wrapped it
+type Header struct {
+	KeyID      string `json:"key_id"`
+	WrappedKey []byte `json:"wrapped_key"`
+}
+
+// Keyring holds the master keys. The first one is active and wraps new data
+// keys; the others only unwrap the keys of files written before a rotation.
+type Keyring struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+// ParseKeyring parses master keys written as "id:base64-key", active key first
+func ParseKeyring(specs []string) (*Keyring, error) {
+	if len(specs) == 0 {
+		return nil, errors.New("keyring needs at least one master key")
+	}
+	k := &Keyring{keys: make(map[string]cipher.AEAD, len(specs))}
+	for i, spec := range specs {
+		id, encoded, ok := strings.Cut(spec, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("master key %d must be written as id:base64-key", i+1)
+		}
+		if _, dup := k.keys[id]; dup {
+			return nil, fmt.Errorf("duplicate master key %q", id)
+		}
+		secret, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(secret) != KeySize {
+			return nil, fmt.Errorf("master key %q must be %d base64-encoded bytes", id, KeySize)
+		}
+		aead, err := newAEAD(secret)
+		if err != nil {
+			return nil, err
+		}
+		k.keys[id] = aead
+		if i == 0 {
+			k.active = id
+		}
+	}
+	return k, nil
+}
+
+// Active returns the ID of the master key that wraps new data keys
+func (k *Keyring) Active() string {
+	return k.active
+}
+
+// Generate creates a data key wrapped with the active master key
+func (k *Keyring) Generate() (Header, *Cipher, error) {
+	secret := make([]byte, KeySize)
+	if _, err := rand.Read(secret); err != nil {
+		return Header{}, nil, err
+	}
+	c, err := newCipher(secret)
+	if err != nil {
+		return Header{}, nil, err
+	}
+	h, err := k.wrap(secret)
+	return h, c, err
+}
+
+// Open unwraps the data key of a header
+func (k *Keyring) Open(h Header) (*Cipher, error) {
+	secret, err := k.unwrap(h)
+	if err != nil {
+		return nil, err
+	}
+	return newCipher(secret)
+}
+
+// Rewrap wraps the data key of a header with the active master key
+func (k *Keyring) Rewrap(h Header) (Header, error) {
+	secret, err := k.unwrap(h)
+	if err != nil {
+		return Header{}, err
+	}
+	return k.wrap(secret)
+}
+
+func (k *Keyring) wrap(secret []byte) (Header, error) {
+	wrapped, err := seal(k.keys[k.active], secret, []byte("data-key/"+k.active))
+	if err != nil {
+		return Header{}, err
+	}
+	return Header{KeyID: k.active, WrappedKey: wrapped}, nil
+}
+
+func (k *Keyring) unwrap(h Header) ([]byte, error) {
+	aead, ok := k.keys[h.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, h.KeyID)
+	}
+	secret, err := open(aead, h.WrappedKey, []byte("data-key/"+h.KeyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap the data key with master key %q: %w", h.KeyID, err)
+	}
+	return secret, nil
+}
+
+// Cipher encrypts values with a data key
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+func newCipher(secret []byte) (*Cipher, error) {
+	aead, err := newAEAD(secret)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Seal encrypts a value as printable text. The context, e.g. the row and
+// column the value is stored in, must be given again to open it, so a value
+// copied to another place no longer decrypts.
+func (c *Cipher) Seal(value []byte, context string) (string, error) {
+	sealed, err := seal(c.aead, value, []byte(context))
+	if err != nil {
+		return "", err
+	}
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value sealed in the same context
+func (c *Cipher) Open(sealed, context string) ([]byte, error) {
+	encoded, ok := strings.CutPrefix(sealed, sealedPrefix)
+	if !ok {
+		return nil, errors.New("value is not sealed")
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sealed value: %w", err)
+	}
+	return open(c.aead, data, []byte(context))
+}
+
+// IsSealed reports whether a stored text is a sealed value
+func IsSealed(s string) bool {
+	return strings.HasPrefix(s, sealedPrefix)
+}
+
+func newAEAD(secret []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(secret)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts with a random nonce, which is prepended to the ciphertext
+func seal(aead cipher.AEAD, plaintext, additional []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additional), nil
+}
+
+func open(aead cipher.AEAD, data, additional []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("sealed value is too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additional)
+	if err != nil {
+		return nil, errors.New("failed to decrypt: wrong key or tampered value")
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,79 @@
+package envelope
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func key(id string, b byte) string {
+	return id + ":" + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, KeySize))
+}
+
+func TestParseKeyring(t *testing.T) {
+	k, err := ParseKeyring([]string{key("2026", 1), key("2025", 2)})
+	if err != nil || k.Active() != "2026" {
+		t.Fatalf("Expected the first key to be active, got %v, %v", k, err)
+	}
+	for _, specs := range [][]string{
+		nil,
+		{"no-separator"},
+		{":" + base64.StdEncoding.EncodeToString(make([]byte, KeySize))},
+		{"short:" + base64.StdEncoding.EncodeToString(make([]byte, 16))},
+		{"bad:not base64"},
+		{key("a", 1), key("a", 2)},
+	} {
+		if _, err := ParseKeyring(specs); err == nil {
+			t.Errorf("Expected %q to be rejected", specs)
+		}
+	}
+}
+
+func TestCipher_SealOpen(t *testing.T) {
+	k, _ := ParseKeyring([]string{key("k1", 1)})
+	h, c, err := k.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := c.Seal([]byte(`"0.5"`), "tx-1/quantity")
+	if err != nil || !IsSealed(sealed) || strings.Contains(sealed, "0.5") {
+		t.Fatalf("Expected an opaque sealed value, got %q, %v", sealed, err)
+	}
+
+	opened, err := k.Open(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := opened.Open(sealed, "tx-1/quantity"); err != nil || string(value) != `"0.5"` {
+		t.Errorf("Expected the value back, got %q, %v", value, err)
+	}
+	if _, err := opened.Open(sealed, "tx-2/quantity"); err == nil {
+		t.Error("Expected a value moved to another row not to open")
+	}
+}
+
+func TestKeyring_Rewrap(t *testing.T) {
+	old, _ := ParseKeyring([]string{key("k1", 1)})
+	h, c, _ := old.Generate()
+	sealed, _ := c.Seal([]byte("secret"), "ctx")
+
+	rotated, _ := ParseKeyring([]string{key("k2", 2), key("k1", 1)})
+	h2, err := rotated.Rewrap(h)
+	if err != nil || h2.KeyID != "k2" {
+		t.Fatalf("Expected the data key wrapped with k2, got %+v, %v", h2, err)
+	}
+
+	retired, _ := ParseKeyring([]string{key("k2", 2)})
+	c2, err := retired.Open(h2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := c2.Open(sealed, "ctx"); err != nil || string(value) != "secret" {
+		t.Errorf("Expected values to open after the rotation, got %q, %v", value, err)
+	}
+	if _, err := retired.Open(h); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected the retired key to be unknown, got %v", err)
+	}
+}
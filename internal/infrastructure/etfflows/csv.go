@@ -0,0 +1,150 @@
+// Package etfflows reads daily spot ETF flows from public data sources
+package etfflows
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// DefaultScale converts the millions of USD most flow tables publish to USD
+const DefaultScale = 1e6
+
+// dateLayouts are the date formats accepted in the first column
+var dateLayouts = []string{time.DateOnly, "02 Jan 2006", "2 Jan 2006", "01/02/2006"}
+
+var defaultClient = &http.Client{Timeout: 30 * time.Second}
+
+// CSV reads a flow table with a date column, one column per fund ticker and
+// an optional Total column. Rows whose first cell is not a date, such as
+// totals and averages, are skipped. Negative values may be written in
+// parentheses and missing ones as "-".
+type CSV struct {
+	URL    string
+	Client *http.Client
+	// Scale multiplies every value to get USD; zero uses DefaultScale
+	Scale float64
+}
+
+// Flows implements etf.Source
+func (c CSV) Flows(ctx context.Context, asset string) ([]models.ETFFlow, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/csv")
+	client := c.Client
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ETF flows: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ETF flow source returned status code: %d", resp.StatusCode)
+	}
+	return c.Parse(resp.Body, asset)
+}
+
+// Parse reads the flows of an asset from a CSV table
+func (c CSV) Parse(r io.Reader, asset string) ([]models.ETFFlow, error) {
+	scale := c.Scale
+	if scale == 0 {
+		scale = DefaultScale
+	}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ETF flow header: %w", err)
+	}
+	if len(header) < 2 {
+		return nil, errors.New("ETF flow table needs a date column and at least one fund column")
+	}
+
+	var flows []models.ETFFlow
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return flows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ETF flows: %w", err)
+		}
+		date, ok := parseDate(row[0])
+		if !ok {
+			continue
+		}
+
+		flow := models.ETFFlow{Asset: asset, Date: date, Funds: make(map[string]float64)}
+		total, hasTotal := 0.0, false
+		for i := 1; i < len(row) && i < len(header); i++ {
+			value, ok, err := parseAmount(row[i], scale)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s flow on %s: %w", header[i], row[0], err)
+			}
+			if !ok {
+				continue
+			}
+			name := strings.TrimSpace(header[i])
+			if strings.EqualFold(name, "total") {
+				total, hasTotal = value, true
+				continue
+			}
+			flow.Funds[strings.ToUpper(name)] = value
+			flow.NetFlow += value
+		}
+		if hasTotal {
+			flow.NetFlow = total
+		}
+		flows = append(flows, flow)
+	}
+}
+
+func parseDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseAmount reads a number like 1,234.5 or (12.3) and multiplies it by
+// scale; it reports false for empty cells
+func parseAmount(s string, scale float64) (float64, bool, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	if s == "" || s == "-" {
+		return 0, false, nil
+	}
+	negative := strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")")
+	if negative {
+		s = s[1 : len(s)-1]
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	if negative {
+		v = -v
+	}
+	// NaN and Inf parse as floats but are no amount, and would poison every sum
+	if v *= scale; math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false, fmt.Errorf("amount %q is not a finite number", s)
+	}
+	return v, true, nil
+}
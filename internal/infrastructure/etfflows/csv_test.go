@@ -0,0 +1,74 @@
+package etfflows
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const table = `Date,IBIT,FBTC,GBTC,Total
+11 Jan 2024,111.7,227.0,(95.1),243.6
+12 Jan 2024,386.0,-,"(484.1)",(98.1)
+Total,497.7,227.0,(579.2),145.5
+`
+
+func TestCSV_Parse(t *testing.T) {
+	flows, err := CSV{}.Parse(strings.NewReader(table), "BTC")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(flows) != 2 {
+		t.Fatalf("Expected the two dated rows, got %+v", flows)
+	}
+
+	first := flows[0]
+	if first.Asset != "BTC" || !first.Date.Equal(time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected flow: %+v", first)
+	}
+	if first.NetFlow != 243.6e6 || first.Funds["GBTC"] != -95.1e6 || len(first.Funds) != 3 {
+		t.Errorf("Unexpected amounts: %+v", first)
+	}
+	if second := flows[1]; second.NetFlow != -98.1e6 || len(second.Funds) != 2 {
+		t.Errorf("Expected the total and the two reported funds, got %+v", second)
+	}
+}
+
+func TestCSV_ParseSumsWithoutTotal(t *testing.T) {
+	flows, err := CSV{Scale: 1}.Parse(strings.NewReader("date,ETHA,FETH\n2024-07-23,266.5,71.3\n"), "ETH")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(flows) != 1 || flows[0].NetFlow != 337.8 {
+		t.Errorf("Expected the funds to be summed, got %+v", flows)
+	}
+}
+
+func TestCSV_ParseRejectsBadAmounts(t *testing.T) {
+	for _, amount := range []string{"abc", "NaN", "-Inf", "(Infinity)", "1e305"} {
+		if _, err := (CSV{}).Parse(strings.NewReader("Date,IBIT\n2024-01-11,"+amount+"\n"), "BTC"); err == nil {
+			t.Errorf("Expected an error for the amount %s", amount)
+		}
+	}
+}
+
+func TestCSV_Flows(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/btc.csv" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(table))
+	}))
+	defer server.Close()
+
+	flows, err := CSV{URL: server.URL + "/btc.csv"}.Flows(context.Background(), "BTC")
+	if err != nil || len(flows) != 2 {
+		t.Fatalf("Expected two flows, got %+v (%v)", flows, err)
+	}
+	if _, err := (CSV{URL: server.URL + "/eth.csv"}).Flows(context.Background(), "ETH"); err == nil {
+		t.Error("Expected an error for a missing table")
+	}
+}
@@ -0,0 +1,59 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// DefaultBinanceURL is the Binance spot API
+const DefaultBinanceURL = "https://api.binance.com"
+
+// Binance prices assets with the last trade of the Binance spot market
+type Binance struct {
+	// BaseURL defaults to DefaultBinanceURL
+	BaseURL string
+	Client  *http.Client
+}
+
+// Name implements compare.Source
+func (Binance) Name() string { return "binance" }
+
+// BinancePair returns the Binance symbol of an asset. Binance has no USD spot
+// market, so USD is quoted in the USDT stablecoin.
+func BinancePair(symbol string, currency models.Currency) string {
+	quote := strings.ToUpper(string(currency))
+	if currency == models.USD {
+		quote = "USDT"
+	}
+	return strings.ToUpper(symbol) + quote
+}
+
+// Quote implements compare.Source
+func (b Binance) Quote(ctx context.Context, symbol string, currency models.Currency) (models.SourceQuote, error) {
+	base := b.BaseURL
+	if base == "" {
+		base = DefaultBinanceURL
+	}
+	pair := BinancePair(symbol, currency)
+
+	var ticker struct {
+		Price decimal.Decimal `json:"price"`
+		Msg   string          `json:"msg"`
+	}
+	err := getJSON(ctx, b.Client, "binance", strings.TrimRight(base, "/")+"/api/v3/ticker/price?symbol="+url.QueryEscape(pair), &ticker)
+	if err != nil {
+		if ticker.Msg != "" {
+			return models.SourceQuote{}, fmt.Errorf("%w: %s", err, ticker.Msg)
+		}
+		return models.SourceQuote{}, err
+	}
+	return models.SourceQuote{Source: b.Name(), Pair: pair, Price: ticker.Price, At: time.Now().UTC()}, nil
+}
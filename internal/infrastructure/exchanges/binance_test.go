@@ -0,0 +1,48 @@
+package exchanges
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestBinancePair(t *testing.T) {
+	if got := BinancePair("btc", models.USD); got != "BTCUSDT" {
+		t.Errorf("Expected USD to be quoted in USDT, got %s", got)
+	}
+	if got := BinancePair("eth", models.EUR); got != "ETHEUR" {
+		t.Errorf("Expected ETHEUR, got %s", got)
+	}
+}
+
+func TestBinance_Quote(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/ticker/price" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("symbol") == "BTCUSDT" {
+			w.Write([]byte(`{"symbol":"BTCUSDT","price":"64012.35000000"}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code":-1121,"msg":"Invalid symbol."}`))
+	}))
+	defer srv.Close()
+	b := Binance{BaseURL: srv.URL, Client: srv.Client()}
+
+	q, err := b.Quote(context.Background(), "BTC", models.USD)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if q.Source != "binance" || q.Pair != "BTCUSDT" || q.Price.String() != "64012.35" {
+		t.Errorf("Unexpected quote: %+v", q)
+	}
+
+	if _, err := b.Quote(context.Background(), "NOPE", models.USD); err == nil || !strings.Contains(err.Error(), "Invalid symbol") {
+		t.Errorf("Expected the Binance message in the error, got %v", err)
+	}
+}
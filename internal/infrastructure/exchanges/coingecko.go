@@ -0,0 +1,76 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// CoinGeckoAPI is the part of the CoinGecko client the comparison needs
+type CoinGeckoAPI interface {
+	Search(query string) ([]models.SearchResult, error)
+	FetchCryptoPrices(cryptoIDs []string, currency models.Currency) ([]models.CryptoPrice, error)
+}
+
+// CoinGecko prices assets with the aggregated CoinGecko price. Symbols are
+// resolved to coin IDs by search, taking the highest ranked coin with that
+// ticker, and cached.
+type CoinGecko struct {
+	client CoinGeckoAPI
+
+	mu  sync.Mutex
+	ids map[string]string
+}
+
+// NewCoinGecko creates a CoinGecko source
+func NewCoinGecko(client CoinGeckoAPI) *CoinGecko {
+	return &CoinGecko{client: client, ids: make(map[string]string)}
+}
+
+// Name implements compare.Source
+func (*CoinGecko) Name() string { return "coingecko" }
+
+// Quote implements compare.Source. The client has no context support, so
+// the comparison's timeout only stops waiting for it.
+func (c *CoinGecko) Quote(ctx context.Context, symbol string, currency models.Currency) (models.SourceQuote, error) {
+	id, err := c.resolve(strings.ToUpper(symbol))
+	if err != nil {
+		return models.SourceQuote{}, err
+	}
+	prices, err := c.client.FetchCryptoPrices([]string{id}, currency)
+	if err != nil {
+		return models.SourceQuote{}, err
+	}
+	if len(prices) == 0 {
+		return models.SourceQuote{}, fmt.Errorf("coingecko returned no price for %s", id)
+	}
+	return models.SourceQuote{Source: c.Name(), Pair: id, Price: prices[0].CurrentPrice, At: time.Now().UTC()}, nil
+}
+
+func (c *CoinGecko) resolve(symbol string) (string, error) {
+	c.mu.Lock()
+	id, ok := c.ids[symbol]
+	c.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	results, err := c.client.Search(symbol)
+	if err != nil {
+		return "", err
+	}
+	// Results are ordered by market cap rank
+	for _, r := range results {
+		if strings.EqualFold(r.Symbol, symbol) {
+			c.mu.Lock()
+			c.ids[symbol] = r.ID
+			c.mu.Unlock()
+			return r.ID, nil
+		}
+	}
+	return "", fmt.Errorf("no coingecko coin with symbol %s", symbol)
+}
@@ -0,0 +1,49 @@
+package exchanges
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type fakeCoinGecko struct {
+	searches int
+}
+
+func (f *fakeCoinGecko) Search(query string) ([]models.SearchResult, error) {
+	f.searches++
+	return []models.SearchResult{
+		{ID: "bitcoin-cash", Symbol: "BCH"},
+		{ID: "bitcoin", Symbol: "BTC"},
+		{ID: "batcat", Symbol: "BTC"},
+	}, nil
+}
+
+func (f *fakeCoinGecko) FetchCryptoPrices(ids []string, currency models.Currency) ([]models.CryptoPrice, error) {
+	return []models.CryptoPrice{{ID: ids[0], CurrentPrice: decimal.NewFromInt(64000), Currency: currency}}, nil
+}
+
+func TestCoinGecko_Quote(t *testing.T) {
+	api := &fakeCoinGecko{}
+	c := NewCoinGecko(api)
+
+	for range 2 {
+		q, err := c.Quote(context.Background(), "btc", models.USD)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if q.Source != "coingecko" || q.Pair != "bitcoin" || !q.Price.Equal(decimal.NewFromInt(64000)) {
+			t.Errorf("Expected the highest ranked BTC coin, got %+v", q)
+		}
+	}
+	if api.searches != 1 {
+		t.Errorf("Expected the resolved ID to be cached, searched %d times", api.searches)
+	}
+
+	if _, err := c.Quote(context.Background(), "XYZ", models.USD); err == nil {
+		t.Error("Expected an error for an unknown symbol")
+	}
+}
@@ -0,0 +1,42 @@
+// Package exchanges prices assets on public exchange APIs. Every source
+// implements compare.Source so exchange prices can be compared with CoinGecko.
+package exchanges
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// defaultClient is used by sources without an HTTP client
+var defaultClient = &http.Client{Timeout: 10 * time.Second}
+
+// getJSON decodes the response of a GET request into v. Non-200 responses are
+// returned as errors; the body is still decoded into v first so sources can
+// report the exchange's own message.
+func getJSON(ctx context.Context, client *http.Client, exchange, endpoint string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query %s: %w", exchange, err)
+	}
+	defer resp.Body.Close()
+
+	decodeErr := json.NewDecoder(resp.Body).Decode(v)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status code: %d", exchange, resp.StatusCode)
+	}
+	if decodeErr != nil {
+		return fmt.Errorf("failed to decode %s response: %w", exchange, decodeErr)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package exchanges
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// DefaultKrakenURL is the Kraken public REST API
+const DefaultKrakenURL = "https://api.kraken.com"
+
+// krakenAssets maps ticker symbols to the legacy names Kraken still uses
+var krakenAssets = map[string]string{
+	"BTC":  "XBT",
+	"DOGE": "XDG",
+}
+
+// Kraken prices assets with the last trade of the Kraken spot market
+type Kraken struct {
+	// BaseURL defaults to DefaultKrakenURL
+	BaseURL string
+	Client  *http.Client
+}
+
+// Name implements compare.Source
+func (Kraken) Name() string { return "kraken" }
+
+// KrakenPair returns the Kraken pair name of an asset, e.g. XBTUSD for BTC in USD
+func KrakenPair(symbol string, currency models.Currency) string {
+	asset := strings.ToUpper(symbol)
+	if alias, ok := krakenAssets[asset]; ok {
+		asset = alias
+	}
+	return asset + strings.ToUpper(string(currency))
+}
+
+// Quote implements compare.Source
+func (k Kraken) Quote(ctx context.Context, symbol string, currency models.Currency) (models.SourceQuote, error) {
+	base := k.BaseURL
+	if base == "" {
+		base = DefaultKrakenURL
+	}
+	pair := KrakenPair(symbol, currency)
+
+	// Kraken answers 200 with a list of errors; results are keyed by the
+	// pair's internal name, e.g. XXBTZUSD, and "c" is the last trade [price, volume]
+	var ticker struct {
+		Error  []string `json:"error"`
+		Result map[string]struct {
+			Close []decimal.Decimal `json:"c"`
+		} `json:"result"`
+	}
+	if err := getJSON(ctx, k.Client, "kraken", strings.TrimRight(base, "/")+"/0/public/Ticker?pair="+url.QueryEscape(pair), &ticker); err != nil {
+		return models.SourceQuote{}, err
+	}
+	if len(ticker.Error) > 0 {
+		return models.SourceQuote{}, errors.New("kraken: " + strings.Join(ticker.Error, "; "))
+	}
+	for _, result := range ticker.Result {
+		if len(result.Close) > 0 {
+			return models.SourceQuote{Source: k.Name(), Pair: pair, Price: result.Close[0], At: time.Now().UTC()}, nil
+		}
+	}
+	return models.SourceQuote{}, errors.New("kraken returned no price for " + pair)
+}
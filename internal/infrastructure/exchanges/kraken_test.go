@@ -0,0 +1,47 @@
+package exchanges
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestKrakenPair(t *testing.T) {
+	if got := KrakenPair("btc", models.USD); got != "XBTUSD" {
+		t.Errorf("Expected XBTUSD, got %s", got)
+	}
+	if got := KrakenPair("ETH", models.EUR); got != "ETHEUR" {
+		t.Errorf("Expected ETHEUR, got %s", got)
+	}
+}
+
+func TestKraken_Quote(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/0/public/Ticker" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("pair") == "XBTUSD" {
+			w.Write([]byte(`{"error":[],"result":{"XXBTZUSD":{"a":["64020.1","1","1.000"],"c":["64015.20000","0.0021"]}}}`))
+			return
+		}
+		w.Write([]byte(`{"error":["EQuery:Unknown asset pair"]}`))
+	}))
+	defer srv.Close()
+	k := Kraken{BaseURL: srv.URL, Client: srv.Client()}
+
+	q, err := k.Quote(context.Background(), "BTC", models.USD)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if q.Source != "kraken" || q.Pair != "XBTUSD" || q.Price.String() != "64015.2" {
+		t.Errorf("Unexpected quote: %+v", q)
+	}
+
+	if _, err := k.Quote(context.Background(), "NOPE", models.USD); err == nil || !strings.Contains(err.Error(), "Unknown asset pair") {
+		t.Errorf("Expected the Kraken error, got %v", err)
+	}
+}
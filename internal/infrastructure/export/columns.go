@@ -0,0 +1,88 @@
+package export
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// PriceColumns are the columns available for price snapshots
+var PriceColumns = []Column[models.CryptoPrice]{
+	{"id", func(p models.CryptoPrice) any { return p.ID }},
+	{"symbol", func(p models.CryptoPrice) any { return p.Symbol }},
+	{"name", func(p models.CryptoPrice) any { return p.Name }},
+	{"price", func(p models.CryptoPrice) any { return p.CurrentPrice }},
+	{"currency", func(p models.CryptoPrice) any { return string(p.Currency) }},
+	{"change_24h", func(p models.CryptoPrice) any { return p.PriceChange24h }},
+	{"change_7d", func(p models.CryptoPrice) any { return p.PriceChange7d }},
+	{"market_cap", func(p models.CryptoPrice) any { return p.MarketCap }},
+	{"volume_24h", func(p models.CryptoPrice) any { return p.Volume24h }},
+	{"last_updated", func(p models.CryptoPrice) any { return p.LastUpdated }},
+}
+
+// CandleColumns are the columns available for stored candles
+var CandleColumns = []Column[models.Candle]{
+	{"id", func(c models.Candle) any { return c.CryptoID }},
+	{"open_time", func(c models.Candle) any { return c.OpenTime }},
+	{"close_time", func(c models.Candle) any { return c.CloseTime }},
+	{"open", func(c models.Candle) any { return c.Open }},
+	{"high", func(c models.Candle) any { return c.High }},
+	{"low", func(c models.Candle) any { return c.Low }},
+	{"close", func(c models.Candle) any { return c.Close }},
+	{"volume", func(c models.Candle) any { return c.Volume }},
+}
+
+// HistoryPoint is a historical price of a coin
+type HistoryPoint struct {
+	CryptoID string
+	Currency models.Currency
+	models.PricePoint
+}
+
+// HistoryColumns are the columns available for historical price ranges
+var HistoryColumns = []Column[HistoryPoint]{
+	{"id", func(p HistoryPoint) any { return p.CryptoID }},
+	{"time", func(p HistoryPoint) any { return p.Time }},
+	{"date", func(p HistoryPoint) any { return p.Time.UTC().Format(time.DateOnly) }},
+	{"price", func(p HistoryPoint) any { return p.Price }},
+	{"currency", func(p HistoryPoint) any { return string(p.Currency) }},
+}
+
+// Holding is a portfolio position valued at the current price
+type Holding struct {
+	CryptoID    string
+	Quantity    decimal.Decimal
+	AverageCost decimal.Decimal
+	CostBasis   decimal.Decimal
+	RealizedPnL decimal.Decimal
+	// Income is the value on receipt of the coins received as income
+	Income   decimal.Decimal
+	Price    decimal.Decimal
+	Currency models.Currency
+}
+
+// Value returns the market value of the holding
+func (h Holding) Value() decimal.Decimal {
+	return h.Quantity.Mul(h.Price)
+}
+
+// UnrealizedPnL returns the gain of the holding over its cost basis
+func (h Holding) UnrealizedPnL() decimal.Decimal {
+	return h.Value().Sub(h.CostBasis)
+}
+
+// HoldingColumns are the columns available for portfolio holdings
+var HoldingColumns = []Column[Holding]{
+	{"id", func(h Holding) any { return h.CryptoID }},
+	{"quantity", func(h Holding) any { return h.Quantity }},
+	{"average_cost", func(h Holding) any { return h.AverageCost }},
+	{"cost_basis", func(h Holding) any { return h.CostBasis }},
+	{"price", func(h Holding) any { return h.Price }},
+	{"value", func(h Holding) any { return h.Value() }},
+	{"unrealized_pnl", func(h Holding) any { return h.UnrealizedPnL() }},
+	{"realized_pnl", func(h Holding) any { return h.RealizedPnL }},
+	{"income", func(h Holding) any { return h.Income }},
+	{"currency", func(h Holding) any { return string(h.Currency) }},
+}
@@ -0,0 +1,138 @@
+// Package export writes prices, candles and holdings as CSV or JSON with a
+// selectable set of columns
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is an export file format
+type Format string
+
+// Supported formats
+const (
+	CSV  Format = "csv"
+	JSON Format = "json"
+)
+
+// ParseFormat validates a format name; an empty name selects CSV
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(name))); f {
+	case "":
+		return CSV, nil
+	case CSV, JSON:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown export format: %q", name)
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	if f == JSON {
+		return "application/json"
+	}
+	return "text/csv"
+}
+
+// Column extracts one named value from a record
+type Column[T any] struct {
+	Name  string
+	Value func(T) any
+}
+
+// SelectColumns picks the named columns in the given order. No names selects
+// every available column.
+func SelectColumns[T any](available []Column[T], names []string) ([]Column[T], error) {
+	if len(names) == 0 {
+		return available, nil
+	}
+	byName := make(map[string]Column[T], len(available))
+	for _, c := range available {
+		byName[c.Name] = c
+	}
+
+	selected := make([]Column[T], 0, len(names))
+	for _, name := range names {
+		c, ok := byName[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q, available: %s", name, strings.Join(ColumnNames(available), ", "))
+		}
+		selected = append(selected, c)
+	}
+	return selected, nil
+}
+
+// ColumnNames lists the names of the columns
+func ColumnNames[T any](columns []Column[T]) []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Write encodes the records. CSV output starts with a header row; JSON output
+// is an array of objects keyed by column name.
+func Write[T any](w io.Writer, format Format, columns []Column[T], records []T) error {
+	if format == JSON {
+		return writeJSON(w, columns, records)
+	}
+	return writeCSV(w, columns, records)
+}
+
+func writeCSV[T any](w io.Writer, columns []Column[T], records []T) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(ColumnNames(columns)); err != nil {
+		return err
+	}
+
+	row := make([]string, len(columns))
+	for _, record := range records {
+		for i, c := range columns {
+			row[i] = formatValue(c.Value(record))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeJSON[T any](w io.Writer, columns []Column[T], records []T) error {
+	rows := make([]map[string]any, len(records))
+	for i, record := range records {
+		row := make(map[string]any, len(columns))
+		for _, c := range columns {
+			row[c.Name] = c.Value(record)
+		}
+		rows[i] = row
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rows)
+}
+
+// formatValue renders a CSV cell; floats keep full precision so spreadsheets
+// can do their own rounding
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,84 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"": CSV, "csv": CSV, " JSON ": JSON} {
+		got, err := ParseFormat(name)
+		if err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("xlsx"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestSelectColumns(t *testing.T) {
+	columns, err := SelectColumns(PriceColumns, []string{"price", "id"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := strings.Join(ColumnNames(columns), ","); got != "price,id" {
+		t.Errorf("Expected columns in requested order, got %s", got)
+	}
+
+	all, _ := SelectColumns(PriceColumns, nil)
+	if len(all) != len(PriceColumns) {
+		t.Errorf("Expected every column without names, got %d", len(all))
+	}
+
+	if _, err := SelectColumns(PriceColumns, []string{"bogus"}); err == nil {
+		t.Error("Expected an error for an unknown column")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	prices := []models.CryptoPrice{
+		{ID: "bitcoin", CurrentPrice: decimal.NewFromFloat(50000.5), Currency: models.USD},
+		{ID: "ethereum", CurrentPrice: decimal.NewFromInt(3000), Currency: models.USD},
+	}
+	columns, _ := SelectColumns(PriceColumns, []string{"id", "price", "currency"})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Write(&buf, CSV, columns, prices); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		want := "id,price,currency\nbitcoin,50000.5,usd\nethereum,3000,usd\n"
+		if buf.String() != want {
+			t.Errorf("Expected\n%s\ngot\n%s", want, buf.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Write(&buf, JSON, columns, prices); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var rows []map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+		if len(rows) != 2 || rows[0]["id"] != "bitcoin" || rows[0]["price"] != "50000.5" || len(rows[0]) != 3 {
+			t.Errorf("Unexpected rows: %v", rows)
+		}
+	})
+
+	t.Run("empty json is an array", func(t *testing.T) {
+		var buf bytes.Buffer
+		Write(&buf, JSON, columns, nil)
+		if strings.TrimSpace(buf.String()) != "[]" {
+			t.Errorf("Expected an empty array, got %s", buf.String())
+		}
+	})
+}
@@ -0,0 +1,208 @@
+// Package grpcserver exposes the dashboard application services over gRPC for
+// programmatic consumers, alongside the HTTP API
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	dashboardv1 "crypto-dashboard/api/dashboard/v1"
+	"crypto-dashboard/internal/application/candles"
+	"crypto-dashboard/internal/application/poller"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// defaultCandleLimit is the number of candles returned when no limit is given
+const defaultCandleLimit = 200
+
+// Services bundles the application services exposed over gRPC
+type Services struct {
+	Poller         *poller.Poller
+	Candles        candles.Repository
+	CandleInterval time.Duration
+	// Logger records every unary call; the default logger is used when it is nil
+	Logger *slog.Logger
+}
+
+// Server implements the DashboardService
+type Server struct {
+	dashboardv1.UnimplementedDashboardServiceServer
+	services Services
+
+	// done is closed on shutdown to end the open streams, which would
+	// otherwise keep a graceful stop waiting for their clients
+	done     chan struct{}
+	shutdown sync.Once
+}
+
+// New creates the gRPC service
+func New(services Services) *Server {
+	if services.Logger == nil {
+		services.Logger = slog.Default()
+	}
+	return &Server{services: services, done: make(chan struct{})}
+}
+
+// Stop ends the open streams, then stops g gracefully once the unary calls in
+// flight are done
+func (s *Server) Stop(g *grpc.Server) {
+	s.shutdown.Do(func() { close(s.done) })
+	g.GracefulStop()
+}
+
+// Register adds the service to a gRPC server
+func (s *Server) Register(g *grpc.Server) {
+	dashboardv1.RegisterDashboardServiceServer(g, s)
+}
+
+// ListenAndServe serves gRPC on the port until the context is cancelled, then
+// stops gracefully so open streams end cleanly
+func (s *Server) ListenAndServe(ctx context.Context, port int) error {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return err
+	}
+	g := grpc.NewServer(grpc.ChainUnaryInterceptor(s.logUnary))
+	s.Register(g)
+
+	go func() {
+		<-ctx.Done()
+		s.Stop(g)
+	}()
+	return g.Serve(lis)
+}
+
+// GetPrices returns the tracked coins, or the requested ones served from the
+// poller cache when they are tracked
+func (s *Server) GetPrices(ctx context.Context, req *dashboardv1.GetPricesRequest) (*dashboardv1.GetPricesResponse, error) {
+	prices := s.services.Poller.Snapshot()
+	if len(req.GetIds()) > 0 {
+		var err error
+		prices, err = s.services.Poller.Prices(req.GetIds())
+		if err != nil && len(prices) == 0 {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+	}
+	return &dashboardv1.GetPricesResponse{
+		Currency: string(s.services.Poller.Currency()),
+		Prices:   toPrices(prices),
+	}, nil
+}
+
+// StreamPrices sends the current snapshot and then one update per poll until
+// the client goes away or the server stops. Updates are coalesced, so a slow
+// client only gets the latest.
+func (s *Server) StreamPrices(req *dashboardv1.StreamPricesRequest, stream dashboardv1.DashboardService_StreamPricesServer) error {
+	updates, cancel := s.services.Poller.Subscribe()
+	defer cancel()
+
+	send := func() error {
+		prices := s.services.Poller.Snapshot()
+		if ids := req.GetIds(); len(ids) > 0 {
+			prices = slices.DeleteFunc(prices, func(p models.CryptoPrice) bool { return !slices.Contains(ids, p.ID) })
+		}
+		return stream.Send(&dashboardv1.PriceUpdate{Prices: toPrices(prices), At: timestamppb.Now()})
+	}
+
+	if err := send(); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-s.done:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case <-updates:
+			if err := send(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// GetHistory returns the recent polled points and the closed candles of a coin
+func (s *Server) GetHistory(ctx context.Context, req *dashboardv1.GetHistoryRequest) (*dashboardv1.GetHistoryResponse, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	limit := int(req.GetLimit())
+	if limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit cannot be negative")
+	}
+	if limit == 0 {
+		limit = defaultCandleLimit
+	}
+
+	stored, err := s.services.Candles.Candles(req.GetId(), s.services.CandleInterval, limit)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &dashboardv1.GetHistoryResponse{
+		Id:       req.GetId(),
+		Currency: string(s.services.Poller.Currency()),
+		Interval: durationpb.New(s.services.CandleInterval),
+	}
+	for _, p := range s.services.Poller.History(req.GetId()) {
+		resp.Points = append(resp.Points, &dashboardv1.PricePoint{Price: p.Price, Time: timestamppb.New(p.Time)})
+	}
+	for _, c := range stored {
+		resp.Candles = append(resp.Candles, &dashboardv1.Candle{
+			OpenTime:  timestamppb.New(c.OpenTime),
+			CloseTime: timestamppb.New(c.CloseTime),
+			Open:      c.Open,
+			High:      c.High,
+			Low:       c.Low,
+			Close:     c.Close,
+			Volume:    c.Volume,
+		})
+	}
+	return resp, nil
+}
+
+// logUnary logs the method, code and duration of every unary call, failures at error level
+func (s *Server) logUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelError
+	}
+	s.services.Logger.LogAttrs(ctx, level, "grpc call",
+		slog.String("method", info.FullMethod),
+		slog.String("code", status.Code(err).String()),
+		slog.Duration("duration", time.Since(start)),
+	)
+	return resp, err
+}
+
+func toPrices(prices []models.CryptoPrice) []*dashboardv1.Price {
+	out := make([]*dashboardv1.Price, len(prices))
+	for i, p := range prices {
+		out[i] = &dashboardv1.Price{
+			Id:                        p.ID,
+			Symbol:                    p.Symbol,
+			Name:                      p.Name,
+			CurrentPrice:              p.CurrentPrice.String(),
+			Currency:                  string(p.Currency),
+			PriceChangePercentage_24H: p.PriceChange24h,
+			Stale:                     p.Stale,
+		}
+		if p.StaleSince != nil {
+			out[i].StaleSince = timestamppb.New(*p.StaleSince)
+		}
+	}
+	return out
+}
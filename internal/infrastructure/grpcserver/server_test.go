@@ -0,0 +1,163 @@
+package grpcserver
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	dashboardv1 "crypto-dashboard/api/dashboard/v1"
+	"crypto-dashboard/internal/application/poller"
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/repository/memory"
+)
+
+type stubPrices map[string]string
+
+func (s stubPrices) FetchCryptoPrices(ids []string, currency models.Currency) ([]models.CryptoPrice, error) {
+	var prices []models.CryptoPrice
+	for _, id := range ids {
+		prices = append(prices, models.CryptoPrice{ID: id, CurrentPrice: decimal.RequireFromString(s[id]), Currency: currency})
+	}
+	return prices, nil
+}
+
+// newTestClient serves the service over an in-memory listener
+func newTestClient(t *testing.T, p *poller.Poller) dashboardv1.DashboardServiceClient {
+	t.Helper()
+	client, _, _ := serve(t, p)
+	return client
+}
+
+// serve serves the service over an in-memory listener and returns the
+// service and the gRPC server too
+func serve(t *testing.T, p *poller.Poller) (dashboardv1.DashboardServiceClient, *Server, *grpc.Server) {
+	t.Helper()
+	candles := memory.NewCandleRepository()
+	candles.SaveCandle(time.Hour, models.Candle{CryptoID: "bitcoin", OpenTime: time.Unix(0, 0), CloseTime: time.Unix(3600, 0), Close: 50000})
+
+	lis := bufconn.Listen(1 << 20)
+	g := grpc.NewServer()
+	s := New(Services{
+		Poller:         p,
+		Candles:        candles,
+		CandleInterval: time.Hour,
+		Logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	s.Register(g)
+	go g.Serve(lis)
+	t.Cleanup(g.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return dashboardv1.NewDashboardServiceClient(conn), s, g
+}
+
+func TestGetPrices(t *testing.T) {
+	p := poller.New(stubPrices{"bitcoin": "55000", "shiba-inu": "0.00001234"}, time.Minute, models.USD, []string{"bitcoin", "shiba-inu"})
+	p.PollOnce()
+	client := newTestClient(t, p)
+
+	resp, err := client.GetPrices(context.Background(), &dashboardv1.GetPricesRequest{})
+	if err != nil {
+		t.Fatalf("GetPrices: %v", err)
+	}
+	if resp.Currency != "usd" || len(resp.Prices) != 2 || resp.Prices[1].CurrentPrice != "0.00001234" {
+		t.Errorf("Expected both tracked prices with full precision, got %v", resp)
+	}
+
+	resp, err = client.GetPrices(context.Background(), &dashboardv1.GetPricesRequest{Ids: []string{"bitcoin"}})
+	if err != nil || len(resp.Prices) != 1 || resp.Prices[0].Id != "bitcoin" {
+		t.Errorf("Expected only bitcoin, got %v (%v)", resp, err)
+	}
+}
+
+func TestStreamPrices(t *testing.T) {
+	prices := stubPrices{"bitcoin": "55000", "ethereum": "3000"}
+	p := poller.New(prices, time.Minute, models.USD, []string{"bitcoin", "ethereum"})
+	p.PollOnce()
+	client := newTestClient(t, p)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.StreamPrices(ctx, &dashboardv1.StreamPricesRequest{Ids: []string{"bitcoin"}})
+	if err != nil {
+		t.Fatalf("StreamPrices: %v", err)
+	}
+
+	first, err := stream.Recv()
+	if err != nil || len(first.Prices) != 1 || first.Prices[0].CurrentPrice != "55000" {
+		t.Fatalf("Expected the current bitcoin price first, got %v (%v)", first, err)
+	}
+
+	prices["bitcoin"] = "56000"
+	p.PollOnce()
+	next, err := stream.Recv()
+	if err != nil || next.Prices[0].CurrentPrice != "56000" {
+		t.Errorf("Expected the polled update, got %v (%v)", next, err)
+	}
+}
+
+func TestStop_EndsStreams(t *testing.T) {
+	p := poller.New(stubPrices{"bitcoin": "55000"}, time.Minute, models.USD, []string{"bitcoin"})
+	p.PollOnce()
+	client, s, g := serve(t, p)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.StreamPrices(ctx, &dashboardv1.StreamPricesRequest{})
+	if err != nil {
+		t.Fatalf("StreamPrices: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Recv: %v", err)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		s.Stop(g)
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Expected the graceful stop not to wait for the open stream")
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected the stream to end as unavailable, got %v", err)
+	}
+}
+
+func TestGetHistory(t *testing.T) {
+	p := poller.New(stubPrices{"bitcoin": "55000"}, time.Minute, models.USD, []string{"bitcoin"})
+	p.PollOnce()
+	client := newTestClient(t, p)
+
+	resp, err := client.GetHistory(context.Background(), &dashboardv1.GetHistoryRequest{Id: "bitcoin"})
+	if err != nil {
+		t.Fatalf("GetHistory: %v", err)
+	}
+	if len(resp.Points) != 1 || len(resp.Candles) != 1 || resp.Candles[0].Close != 50000 || resp.Interval.AsDuration() != time.Hour {
+		t.Errorf("Unexpected history: %v", resp)
+	}
+
+	_, err = client.GetHistory(context.Background(), &dashboardv1.GetHistoryRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument without an id, got %v", err)
+	}
+}
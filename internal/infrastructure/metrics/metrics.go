@@ -0,0 +1,258 @@
+// Package metrics exposes Prometheus metrics for the client, poller and cache
+package metrics
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "crypto_dashboard"
+
+// Metrics holds every collector registered by the dashboard
+type Metrics struct {
+	registry *prometheus.Registry
+
+	apiRequests    *prometheus.CounterVec
+	apiDuration    *prometheus.HistogramVec
+	apiErrors      *prometheus.CounterVec
+	cacheLookups   *prometheus.CounterVec
+	providerCalls  *prometheus.CounterVec
+	providerBytes  *prometheus.CounterVec
+	seriesLookups  *prometheus.CounterVec
+	seriesEvicted  prometheus.Counter
+	seriesBytes    prometheus.Gauge
+	polls          *prometheus.CounterVec
+	pollDuration   prometheus.Histogram
+	trackedCoins   prometheus.Gauge
+	lastSuccessful prometheus.Gauge
+	restarts       *prometheus.CounterVec
+}
+
+// New creates the collectors and registers them on a dedicated registry
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		apiRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "api",
+			Name:      "requests_total",
+			Help:      "Upstream API requests by endpoint and status code.",
+		}, []string{"endpoint", "code"}),
+		apiDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "api",
+			Name:      "request_duration_seconds",
+			Help:      "Upstream API request latency by endpoint.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"endpoint"}),
+		apiErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "api",
+			Name:      "errors_total",
+			Help:      "Failed upstream API requests by endpoint and reason (network, rate_limited, http_error).",
+		}, []string{"endpoint", "reason"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      "lookups_total",
+			Help:      "Price cache lookups by result (hit or miss).",
+		}, []string{"result"}),
+		providerCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "provider",
+			Name:      "calls_total",
+			Help:      "Outbound calls by provider, counted against its daily budget.",
+		}, []string{"provider"}),
+		providerBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "provider",
+			Name:      "downloaded_bytes_total",
+			Help:      "Response bytes downloaded by provider.",
+		}, []string{"provider"}),
+		seriesLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "history_cache",
+			Name:      "lookups_total",
+			Help:      "Daily history series lookups by result (hit or miss).",
+		}, []string{"result"}),
+		seriesEvicted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "history_cache",
+			Name:      "evictions_total",
+			Help:      "Coins whose history series were evicted to stay within the byte budget.",
+		}),
+		seriesBytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "history_cache",
+			Name:      "bytes",
+			Help:      "Approximate memory held by cached history series.",
+		}),
+		polls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "poller",
+			Name:      "polls_total",
+			Help:      "Completed poll cycles by result (success or error).",
+		}, []string{"result"}),
+		pollDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "poller",
+			Name:      "poll_duration_seconds",
+			Help:      "Duration of poll cycles.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		trackedCoins: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "poller",
+			Name:      "tracked_coins",
+			Help:      "Number of coins currently tracked by the poller.",
+		}),
+		lastSuccessful: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "poller",
+			Name:      "last_success_timestamp_seconds",
+			Help:      "Unix time of the last successful poll.",
+		}),
+		restarts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "watchdog",
+			Name:      "restarts_total",
+			Help:      "Components restarted because they stopped making progress, by component.",
+		}, []string{"component"}),
+	}
+
+	m.registry.MustRegister(
+		m.apiRequests, m.apiDuration, m.apiErrors,
+		m.cacheLookups,
+		m.providerCalls, m.providerBytes,
+		m.seriesLookups, m.seriesEvicted, m.seriesBytes,
+		m.polls, m.pollDuration, m.trackedCoins, m.lastSuccessful,
+		m.restarts,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Registry returns the registry so other packages can register their own collectors
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// PollCompleted implements poller.Observer
+func (m *Metrics) PollCompleted(tracked int, duration time.Duration, err error) {
+	m.trackedCoins.Set(float64(tracked))
+	m.pollDuration.Observe(duration.Seconds())
+	if err != nil {
+		m.polls.WithLabelValues("error").Inc()
+		return
+	}
+	m.polls.WithLabelValues("success").Inc()
+	m.lastSuccessful.SetToCurrentTime()
+}
+
+// CacheLookup implements poller.Observer
+func (m *Metrics) CacheLookup(hits, misses int) {
+	m.cacheLookups.WithLabelValues("hit").Add(float64(hits))
+	m.cacheLookups.WithLabelValues("miss").Add(float64(misses))
+}
+
+// ProviderCall implements api.UsageObserver
+func (m *Metrics) ProviderCall(provider string) {
+	m.providerCalls.WithLabelValues(provider).Inc()
+}
+
+// ProviderDownload implements api.UsageObserver
+func (m *Metrics) ProviderDownload(provider string, bytes int64) {
+	m.providerBytes.WithLabelValues(provider).Add(float64(bytes))
+}
+
+// SeriesLookup implements memory.SeriesObserver
+func (m *Metrics) SeriesLookup(hit bool) {
+	if hit {
+		m.seriesLookups.WithLabelValues("hit").Inc()
+		return
+	}
+	m.seriesLookups.WithLabelValues("miss").Inc()
+}
+
+// SeriesEvicted implements memory.SeriesObserver
+func (m *Metrics) SeriesEvicted() {
+	m.seriesEvicted.Inc()
+}
+
+// SeriesCacheSize implements memory.SeriesObserver
+func (m *Metrics) SeriesCacheSize(bytes int64) {
+	m.seriesBytes.Set(float64(bytes))
+}
+
+// ComponentRestarted implements watchdog.Observer
+func (m *Metrics) ComponentRestarted(name string) {
+	m.restarts.WithLabelValues(name).Inc()
+}
+
+// InstrumentTransport wraps an HTTP transport to record upstream request
+// counts, latencies and errors per endpoint
+func (m *Metrics) InstrumentTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		endpoint := Endpoint(req.URL.Path)
+		start := time.Now()
+
+		resp, err := next.RoundTrip(req)
+		m.apiDuration.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+
+		if err != nil {
+			m.apiRequests.WithLabelValues(endpoint, "error").Inc()
+			m.apiErrors.WithLabelValues(endpoint, "network").Inc()
+			return nil, err
+		}
+
+		m.apiRequests.WithLabelValues(endpoint, strconv.Itoa(resp.StatusCode)).Inc()
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			m.apiErrors.WithLabelValues(endpoint, "rate_limited").Inc()
+		case resp.StatusCode >= 400:
+			m.apiErrors.WithLabelValues(endpoint, "http_error").Inc()
+		}
+		return resp, nil
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+var (
+	// versionPrefix matches the base path up to the API version, e.g. /api/v3
+	versionPrefix = regexp.MustCompile(`^.*?/v\d+/`)
+	// coinPath matches per-coin endpoints such as /coins/bitcoin/history
+	coinPath = regexp.MustCompile(`^/coins/([^/]+)(/.*)?$`)
+)
+
+// Endpoint normalizes a request path into a low-cardinality label,
+// replacing coin IDs with {id} and dropping the API version prefix
+func Endpoint(path string) string {
+	path = versionPrefix.ReplaceAllString(path, "/")
+	if path == "/coins/markets" || path == "/coins/list" {
+		return path
+	}
+	if m := coinPath.FindStringSubmatch(path); m != nil {
+		return "/coins/{id}" + m[2]
+	}
+	return path
+}
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestEndpoint(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/api/v3/simple/price", want: "/simple/price"},
+		{path: "/api/v3/coins/markets", want: "/coins/markets"},
+		{path: "/api/v3/coins/bitcoin/history", want: "/coins/{id}/history"},
+		{path: "/coins/solana", want: "/coins/{id}"},
+		{path: "/global", want: "/global"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := Endpoint(tt.path); got != tt.want {
+				t.Errorf("Endpoint(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstrumentTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "markets") {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	m := New()
+	client := &http.Client{Transport: m.InstrumentTransport(nil)}
+
+	client.Get(server.URL + "/api/v3/simple/price?ids=bitcoin")
+	client.Get(server.URL + "/api/v3/coins/markets")
+
+	if got := testutil.ToFloat64(m.apiRequests.WithLabelValues("/simple/price", "200")); got != 1 {
+		t.Errorf("Expected 1 successful request, got %f", got)
+	}
+	if got := testutil.ToFloat64(m.apiErrors.WithLabelValues("/coins/markets", "rate_limited")); got != 1 {
+		t.Errorf("Expected 1 rate limited request, got %f", got)
+	}
+}
+
+func TestObserverAndHandler(t *testing.T) {
+	m := New()
+	m.PollCompleted(3, time.Second, nil)
+	m.PollCompleted(4, time.Second, errors.New("boom"))
+	m.CacheLookup(2, 1)
+	m.ProviderCall("coingecko")
+	m.ProviderDownload("coingecko", 512)
+	m.SeriesLookup(false)
+	m.SeriesEvicted()
+	m.SeriesCacheSize(4096)
+	m.ComponentRestarted("poller")
+
+	if got := testutil.ToFloat64(m.trackedCoins); got != 4 {
+		t.Errorf("Expected 4 tracked coins, got %f", got)
+	}
+	if got := testutil.ToFloat64(m.lastSuccessful); got == 0 {
+		t.Error("Expected last success timestamp to be set")
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		`crypto_dashboard_cache_lookups_total{result="hit"} 2`,
+		`crypto_dashboard_poller_polls_total{result="error"} 1`,
+		`crypto_dashboard_provider_calls_total{provider="coingecko"} 1`,
+		`crypto_dashboard_provider_downloaded_bytes_total{provider="coingecko"} 512`,
+		`crypto_dashboard_history_cache_lookups_total{result="miss"} 1`,
+		`crypto_dashboard_history_cache_evictions_total 1`,
+		`crypto_dashboard_history_cache_bytes 4096`,
+		`crypto_dashboard_watchdog_restarts_total{component="poller"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected metrics output to contain %q", want)
+		}
+	}
+}
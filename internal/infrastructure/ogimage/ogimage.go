@@ -0,0 +1,148 @@
+// Package ogimage renders the Open Graph preview cards that chat apps show
+// when a dashboard link is shared: a title, the price, its change and a
+// sparkline. Text uses a built-in bitmap font so no font files are needed.
+package ogimage
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Card size recommended by Open Graph consumers
+const (
+	Width  = 1200
+	Height = 630
+)
+
+// margin is the blank border around the content
+const margin = 60
+
+var (
+	background = color.RGBA{0x0f, 0x17, 0x2a, 0xff}
+	foreground = color.RGBA{0xf8, 0xfa, 0xfc, 0xff}
+	muted      = color.RGBA{0x94, 0xa3, 0xb8, 0xff}
+	up         = color.RGBA{0x22, 0xc5, 0x5e, 0xff}
+	down       = color.RGBA{0xef, 0x44, 0x44, 0xff}
+)
+
+// Card is the content of a preview
+type Card struct {
+	Title string
+	// Subtitle is a short line under the title, e.g. the ticker and range
+	Subtitle string
+	// Price is the formatted latest price
+	Price string
+	// Change is the change over the card's range in percent
+	Change float64
+	// Series are the prices drawn as a sparkline, oldest first
+	Series []float64
+	// Footer names the site, bottom right
+	Footer string
+}
+
+// Render draws the card and writes it as PNG
+func Render(w io.Writer, c Card) error {
+	return png.Encode(w, Draw(c))
+}
+
+// Draw draws the card
+func Draw(c Card) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	fill(img, img.Bounds(), background)
+
+	trend := up
+	if c.Change < 0 {
+		trend = down
+	}
+	text(img, margin, margin, 8, c.Title, foreground)
+	text(img, margin, 140, 4, c.Subtitle, muted)
+	text(img, margin, 200, 12, c.Price, foreground)
+	change := strconv.FormatFloat(c.Change, 'f', 2, 64) + "%"
+	if c.Change >= 0 {
+		change = "+" + change
+	}
+	text(img, margin, 310, 7, change, trend)
+	sparkline(img, image.Rect(margin, 390, Width-margin, Height-margin-20), c.Series, trend)
+	if c.Footer != "" {
+		width := utf8.RuneCountInString(c.Footer)*glyphAdvance*3 - 3
+		text(img, Width-margin-width, Height-margin+10, 3, c.Footer, muted)
+	}
+	return img
+}
+
+// text draws a line with each font pixel scaled to a square of scale pixels.
+// Lines wider than the card are cut with an ellipsis.
+func text(img *image.RGBA, x, y, scale int, s string, c color.RGBA) {
+	maxRunes := (Width - margin - x + scale) / (glyphAdvance * scale)
+	if utf8.RuneCountInString(s) > maxRunes {
+		s = string([]rune(s)[:max(maxRunes-3, 0)]) + "..."
+	}
+	for _, r := range s {
+		g := glyph(r)
+		for row, bits := range g {
+			for col := range glyphWidth {
+				if bits&(1<<(glyphWidth-1-col)) != 0 {
+					fill(img, image.Rect(x+col*scale, y+row*scale, x+(col+1)*scale, y+(row+1)*scale), c)
+				}
+			}
+		}
+		x += glyphAdvance * scale
+	}
+}
+
+// sparkline draws the series as a thick line over a faint area, scaled to
+// fill the rectangle. Fewer than two points draw nothing.
+func sparkline(img *image.RGBA, r image.Rectangle, series []float64, c color.RGBA) {
+	if len(series) < 2 {
+		return
+	}
+	low, high := series[0], series[0]
+	for _, v := range series {
+		low, high = min(low, v), max(high, v)
+	}
+	span := high - low
+	y := func(v float64) float64 {
+		if span == 0 {
+			return float64(r.Min.Y+r.Max.Y) / 2
+		}
+		return float64(r.Max.Y) - (v-low)/span*float64(r.Dy())
+	}
+	// at interpolates the line at column x
+	at := func(x int) float64 {
+		pos := float64(x-r.Min.X) / float64(r.Dx()-1) * float64(len(series)-1)
+		i := min(int(pos), len(series)-2)
+		frac := pos - float64(i)
+		return y(series[i])*(1-frac) + y(series[i+1])*frac
+	}
+
+	area := blend(background, c, 0.15)
+	for x := r.Min.X; x < r.Max.X; x++ {
+		fill(img, image.Rect(x, int(at(x)), x+1, r.Max.Y), area)
+	}
+	const thickness = 5
+	for x := r.Min.X; x < r.Max.X; x++ {
+		// Steep segments are filled between neighbouring columns so the line stays connected
+		y0, y1 := at(x), at(min(x+1, r.Max.X-1))
+		top, bottom := int(min(y0, y1))-thickness/2, int(max(y0, y1))+thickness/2+1
+		fill(img, image.Rect(x, top, x+1, bottom), c)
+	}
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	r = r.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// blend mixes a share of over into base
+func blend(base, over color.RGBA, share float64) color.RGBA {
+	mix := func(a, b uint8) uint8 { return uint8(float64(a)*(1-share) + float64(b)*share) }
+	return color.RGBA{mix(base.R, over.R), mix(base.G, over.G), mix(base.B, over.B), 0xff}
+}
@@ -0,0 +1,63 @@
+package ogimage
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// count returns the pixels of a color in the rectangle
+func count(img *image.RGBA, r image.Rectangle, c color.RGBA) int {
+	n := 0
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if img.RGBAAt(x, y) == c {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func TestRender(t *testing.T) {
+	card := Card{Title: "Bitcoin", Subtitle: "BTC - 24h", Price: "67000.50 USD", Change: 2.5, Series: []float64{1, 3, 2, 5}, Footer: "Crypto Dashboard"}
+	var buf bytes.Buffer
+	if err := Render(&buf, card); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg, err := png.DecodeConfig(&buf); err != nil || cfg.Width != Width || cfg.Height != Height {
+		t.Fatalf("Expected a %d×%d PNG, got %+v (%v)", Width, Height, cfg, err)
+	}
+
+	img := Draw(card)
+	if count(img, image.Rect(0, 0, Width, 130), foreground) == 0 {
+		t.Error("Expected the title to be drawn")
+	}
+	chart := image.Rect(margin, 390, Width-margin, Height-margin-20)
+	if count(img, chart, up) == 0 || count(img, chart, down) != 0 {
+		t.Error("Expected a rising sparkline to be green")
+	}
+	// The last point is the highest, so the line ends at the top of the chart
+	if got := img.RGBAAt(Width-margin-1, 391); got != up {
+		t.Errorf("Expected the line to end at the top right, got %v", got)
+	}
+
+	card.Change = -1
+	if img := Draw(card); count(img, chart, down) == 0 {
+		t.Error("Expected a falling sparkline to be red")
+	}
+}
+
+func TestText(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	text(img, 0, 0, 1, "I", foreground)
+	// The I of the bitmap font has a three pixel bar on top and bottom
+	if n := count(img, image.Rect(0, 0, glyphWidth, glyphHeight), foreground); n != 3+3+5 {
+		t.Errorf("Expected 11 pixels for I, got %d", n)
+	}
+	if glyph('€') != glyph('?') || glyph('b') != glyph('B') {
+		t.Error("Expected lowercase letters as capitals and unknown characters as ?")
+	}
+}
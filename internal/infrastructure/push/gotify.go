@@ -0,0 +1,40 @@
+package push
+
+import (
+	"context"
+	"net/http"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Gotify publishes alerts as messages of a Gotify application
+type Gotify struct {
+	// Server is the base URL of the Gotify server
+	Server string
+	// Token is the application token messages are sent with
+	Token  string
+	Client *http.Client
+}
+
+// GotifyPriority maps a severity to a Gotify priority. Clients only play a
+// sound from 4 upwards and treat 8 and above as high priority.
+func GotifyPriority(severity models.AlertSeverity) int {
+	switch severity {
+	case models.SeverityCritical:
+		return 10
+	case models.SeverityWarning:
+		return 7
+	}
+	return 4
+}
+
+// Notify implements alerts.Notifier
+func (g Gotify) Notify(ctx context.Context, alert models.Alert) error {
+	header := http.Header{}
+	header.Set("X-Gotify-Key", g.Token)
+	return postJSON(ctx, g.Client, "gotify", trimURL(g.Server)+"/message", header, map[string]any{
+		"title":    title(alert),
+		"message":  alert.Message,
+		"priority": GotifyPriority(alert.Severity),
+	})
+}
@@ -0,0 +1,44 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestGotify_Notify(t *testing.T) {
+	var path, key string
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, key = r.URL.Path, r.Header.Get("X-Gotify-Key")
+		json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer server.Close()
+
+	g := Gotify{Server: server.URL, Token: "app-token"}
+	err := g.Notify(context.Background(), models.Alert{
+		CryptoID: "ethereum",
+		Kind:     models.AlertGoldenCross,
+		Severity: models.SeverityInfo,
+		Message:  "golden cross",
+	})
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if path != "/message" || key != "app-token" {
+		t.Errorf("Expected a message with the app token, got %s %q", path, key)
+	}
+	if body["title"] != "ethereum: golden_cross" || body["priority"] != float64(4) {
+		t.Errorf("Unexpected message: %v", body)
+	}
+}
+
+func TestGotifyPriority(t *testing.T) {
+	if GotifyPriority(models.SeverityWarning) != 7 || GotifyPriority(models.SeverityCritical) != 10 {
+		t.Error("Unexpected gotify priority mapping")
+	}
+}
@@ -0,0 +1,117 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Matrix message types. Notices are meant for bots and do not notify by default.
+const (
+	matrixText   = "m.text"
+	matrixNotice = "m.notice"
+)
+
+// matrixTxn makes transaction IDs unique within the process; the start time
+// keeps them unique across restarts
+var (
+	matrixTxn   atomic.Uint64
+	matrixStart = time.Now().UnixNano()
+)
+
+// Matrix posts alerts and daily digests to a Matrix room
+type Matrix struct {
+	// Homeserver is the base URL of the client API, e.g. https://matrix.org
+	Homeserver string
+	// AccessToken belongs to the account messages are sent as, which must have joined the room
+	AccessToken string
+	RoomID      string
+	Client      *http.Client
+}
+
+// MatrixMsgType maps a severity to a message type: info alerts are sent as
+// notices, warnings and critical alerts as text so they notify room members
+func MatrixMsgType(severity models.AlertSeverity) string {
+	if severity == models.SeverityWarning || severity == models.SeverityCritical {
+		return matrixText
+	}
+	return matrixNotice
+}
+
+// Notify implements alerts.Notifier
+func (m Matrix) Notify(ctx context.Context, alert models.Alert) error {
+	heading := title(alert)
+	return m.send(ctx, MatrixMsgType(alert.Severity),
+		heading+"\n"+alert.Message,
+		"<strong>"+html.EscapeString(heading)+"</strong><br>"+html.EscapeString(alert.Message))
+}
+
+// SendDigest implements digest.Sender
+func (m Matrix) SendDigest(ctx context.Context, digest models.Digest) error {
+	code := strings.ToUpper(string(digest.Currency))
+	heading := fmt.Sprintf("Daily digest %s (%s)", digest.Date.Format(time.DateOnly), code)
+
+	var text, formatted strings.Builder
+	text.WriteString(heading + "\n")
+	formatted.WriteString("<h4>" + html.EscapeString(heading) + "</h4><ul>")
+	for _, p := range digest.Prices {
+		line := fmt.Sprintf("%s: %s %s (%+.2f%%)", p.ID, p.CurrentPrice.StringFixed(2), code, p.PriceChange24h)
+		text.WriteString(line + "\n")
+		formatted.WriteString("<li>" + html.EscapeString(line) + "</li>")
+	}
+	formatted.WriteString("</ul>")
+
+	if len(digest.Alerts) == 0 {
+		text.WriteString("No alerts in the last 24 hours.")
+		formatted.WriteString("<p>No alerts in the last 24 hours.</p>")
+	} else {
+		fmt.Fprintf(&text, "Alerts in the last 24 hours: %d\n", len(digest.Alerts))
+		fmt.Fprintf(&formatted, "<p>Alerts in the last 24 hours: %d</p><ul>", len(digest.Alerts))
+		for _, a := range digest.Alerts {
+			line := title(a) + ": " + a.Message
+			text.WriteString("- " + line + "\n")
+			formatted.WriteString("<li>" + html.EscapeString(line) + "</li>")
+		}
+		formatted.WriteString("</ul>")
+	}
+
+	if len(digest.ETFFlows) > 0 {
+		text.WriteString("\nSpot ETF net flows (USD)\n")
+		formatted.WriteString("<p>Spot ETF net flows (USD)</p><ul>")
+		for _, f := range digest.ETFFlows {
+			line := fmt.Sprintf("%s %s: %s, 7d %s, 30d %s", f.Asset, f.Date.Format(time.DateOnly),
+				compactUSD(f.Latest), compactUSD(f.Week), compactUSD(f.Month))
+			text.WriteString("- " + line + "\n")
+			formatted.WriteString("<li>" + html.EscapeString(line) + "</li>")
+		}
+		formatted.WriteString("</ul>")
+	}
+	return m.send(ctx, matrixNotice, strings.TrimSpace(text.String()), formatted.String())
+}
+
+// compactUSD formats a signed amount in millions, like +243.6M
+func compactUSD(v float64) string {
+	return fmt.Sprintf("%+.1fM", v/1e6)
+}
+
+// send puts a room message with a plain body and an HTML formatted body
+func (m Matrix) send(ctx context.Context, msgType, body, formatted string) error {
+	txn := fmt.Sprintf("dashboard-%d-%d", matrixStart, matrixTxn.Add(1))
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		trimURL(m.Homeserver), url.PathEscape(m.RoomID), txn)
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+m.AccessToken)
+	return sendJSON(ctx, m.Client, http.MethodPut, "matrix", endpoint, header, map[string]any{
+		"msgtype":        msgType,
+		"body":           body,
+		"format":         "org.matrix.custom.html",
+		"formatted_body": formatted,
+	})
+}
@@ -0,0 +1,106 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// matrixServer records the room messages it receives
+func matrixServer(t *testing.T) (*httptest.Server, *[]*http.Request, *[]map[string]any) {
+	t.Helper()
+	var requests []*http.Request
+	var bodies []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		requests, bodies = append(requests, r), append(bodies, body)
+		w.Write([]byte(`{"event_id":"$1"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests, &bodies
+}
+
+func TestMatrix_Notify(t *testing.T) {
+	server, requests, bodies := matrixServer(t)
+	m := Matrix{Homeserver: server.URL + "/", AccessToken: "secret", RoomID: "!room:example.org"}
+
+	alert := models.Alert{CryptoID: "bitcoin", Kind: models.AlertPriceBelow, Severity: models.SeverityCritical, Message: "below <60000>"}
+	if err := m.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if err := m.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	first, second := (*requests)[0], (*requests)[1]
+	if first.Method != http.MethodPut || first.Header.Get("Authorization") != "Bearer secret" {
+		t.Errorf("Expected an authenticated PUT, got %s %q", first.Method, first.Header.Get("Authorization"))
+	}
+	if !strings.HasPrefix(first.URL.EscapedPath(), "/_matrix/client/v3/rooms/%21room:example.org/send/m.room.message/") {
+		t.Errorf("Unexpected path: %s", first.URL.EscapedPath())
+	}
+	if first.URL.Path == second.URL.Path {
+		t.Error("Expected a new transaction ID per message")
+	}
+
+	body := (*bodies)[0]
+	if body["msgtype"] != "m.text" || body["body"] != "bitcoin: price_below\nbelow <60000>" {
+		t.Errorf("Unexpected message: %v", body)
+	}
+	if !strings.Contains(body["formatted_body"].(string), "below &lt;60000&gt;") {
+		t.Errorf("Expected the HTML body to be escaped, got %v", body["formatted_body"])
+	}
+}
+
+func TestMatrix_SendDigest(t *testing.T) {
+	server, _, bodies := matrixServer(t)
+	m := Matrix{Homeserver: server.URL, AccessToken: "secret", RoomID: "!room:example.org"}
+
+	err := m.SendDigest(context.Background(), models.Digest{
+		Date:     time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC),
+		Currency: models.DefaultCurrency,
+		Prices:   []models.CryptoPrice{{ID: "bitcoin", CurrentPrice: decimal.NewFromInt(60000), PriceChange24h: 1.5}},
+		Alerts:   []models.Alert{{CryptoID: "bitcoin", Kind: models.AlertPriceAbove, Message: "above 59000"}},
+	})
+	if err != nil {
+		t.Fatalf("SendDigest: %v", err)
+	}
+	body := (*bodies)[0]
+	want := "Daily digest 2024-03-02 (USD)\nbitcoin: 60000.00 USD (+1.50%)\nAlerts in the last 24 hours: 1\n- bitcoin: price_above: above 59000"
+	if body["msgtype"] != "m.notice" || body["body"] != want {
+		t.Errorf("Unexpected digest:\n%v", body["body"])
+	}
+}
+
+func TestMatrix_SendDigestWithFlows(t *testing.T) {
+	server, _, bodies := matrixServer(t)
+	m := Matrix{Homeserver: server.URL, AccessToken: "secret", RoomID: "!room:example.org"}
+
+	err := m.SendDigest(context.Background(), models.Digest{
+		Date:     time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC),
+		Currency: models.DefaultCurrency,
+		ETFFlows: []models.ETFFlowSummary{{Asset: "BTC", Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Latest: 243.6e6, Week: -98.1e6, Month: 1200e6}},
+	})
+	if err != nil {
+		t.Fatalf("SendDigest: %v", err)
+	}
+	want := "Daily digest 2024-03-02 (USD)\nNo alerts in the last 24 hours.\nSpot ETF net flows (USD)\n- BTC 2024-03-01: +243.6M, 7d -98.1M, 30d +1200.0M"
+	if body := (*bodies)[0]; body["body"] != want {
+		t.Errorf("Unexpected digest:\n%v", body["body"])
+	}
+}
+
+func TestMatrixMsgType(t *testing.T) {
+	if MatrixMsgType(models.SeverityInfo) != "m.notice" || MatrixMsgType(models.SeverityWarning) != "m.text" {
+		t.Error("Unexpected matrix message type mapping")
+	}
+}
@@ -0,0 +1,52 @@
+package push
+
+import (
+	"context"
+	"net/http"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Ntfy priorities (https://docs.ntfy.sh/publish/#message-priority)
+const (
+	ntfyDefault = 3
+	ntfyHigh    = 4
+	ntfyUrgent  = 5
+)
+
+// Ntfy publishes alerts to a topic of an ntfy server
+type Ntfy struct {
+	// Server is the base URL, e.g. https://ntfy.sh
+	Server string
+	Topic  string
+	// Token is an optional access token for protected topics
+	Token  string
+	Client *http.Client
+}
+
+// NtfyPriority maps a severity to an ntfy priority: info uses the default
+// level, warnings are high and critical alerts are urgent
+func NtfyPriority(severity models.AlertSeverity) int {
+	switch severity {
+	case models.SeverityCritical:
+		return ntfyUrgent
+	case models.SeverityWarning:
+		return ntfyHigh
+	}
+	return ntfyDefault
+}
+
+// Notify implements alerts.Notifier using ntfy's JSON publishing
+func (n Ntfy) Notify(ctx context.Context, alert models.Alert) error {
+	header := http.Header{}
+	if n.Token != "" {
+		header.Set("Authorization", "Bearer "+n.Token)
+	}
+	return postJSON(ctx, n.Client, "ntfy", trimURL(n.Server), header, map[string]any{
+		"topic":    n.Topic,
+		"title":    title(alert),
+		"message":  alert.Message,
+		"priority": NtfyPriority(alert.Severity),
+		"tags":     []string{string(alert.Kind), alert.CryptoID},
+	})
+}
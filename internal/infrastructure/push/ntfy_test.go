@@ -0,0 +1,55 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestNtfy_Notify(t *testing.T) {
+	var auth string
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer server.Close()
+
+	n := Ntfy{Server: server.URL + "/", Topic: "crypto", Token: "tk_secret"}
+	err := n.Notify(context.Background(), models.Alert{
+		CryptoID: "bitcoin",
+		Kind:     models.AlertPriceBelow,
+		Severity: models.SeverityCritical,
+		Message:  "bitcoin closed below 60000",
+	})
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if auth != "Bearer tk_secret" {
+		t.Errorf("Expected the access token, got %q", auth)
+	}
+	if body["topic"] != "crypto" || body["title"] != "bitcoin: price_below" || body["priority"] != float64(5) {
+		t.Errorf("Unexpected message: %v", body)
+	}
+}
+
+func TestNtfy_NotifyFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	if err := (Ntfy{Server: server.URL, Topic: "crypto"}).Notify(context.Background(), models.Alert{}); err == nil {
+		t.Error("Expected an error for a rejected message")
+	}
+}
+
+func TestNtfyPriority(t *testing.T) {
+	if NtfyPriority(models.SeverityInfo) != 3 || NtfyPriority(models.SeverityWarning) != 4 || NtfyPriority("") != 3 {
+		t.Error("Unexpected ntfy priority mapping")
+	}
+}
@@ -0,0 +1,62 @@
+// Package push delivers alerts to self-hosted push notification services.
+// Every notifier implements alerts.Notifier.
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// defaultClient is used by notifiers without an HTTP client
+var defaultClient = &http.Client{Timeout: 10 * time.Second}
+
+// title is the notification title of an alert, e.g. "bitcoin: price_above"
+func title(alert models.Alert) string {
+	return fmt.Sprintf("%s: %s", alert.CryptoID, alert.Kind)
+}
+
+// postJSON sends body as JSON and fails on any non-2xx response
+func postJSON(ctx context.Context, client *http.Client, service, endpoint string, header http.Header, body any) error {
+	return sendJSON(ctx, client, http.MethodPost, service, endpoint, header, body)
+}
+
+// sendJSON is postJSON for any method
+func sendJSON(ctx context.Context, client *http.Client, method, service, endpoint string, header http.Header, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to notify %s: %w", service, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s returned status code: %d", service, resp.StatusCode)
+	}
+	return nil
+}
+
+// trimURL removes trailing slashes so paths can be appended
+func trimURL(u string) string {
+	return strings.TrimRight(u, "/")
+}
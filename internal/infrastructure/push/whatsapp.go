@@ -0,0 +1,109 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// DefaultWhatsAppAPI is the Graph API version the WhatsApp Cloud API is called through
+const DefaultWhatsAppAPI = "https://graph.facebook.com/v20.0"
+
+// Recipients lists who opted in to a channel
+type Recipients interface {
+	Recipients(channel models.OptInChannel) ([]models.OptIn, error)
+}
+
+// WhatsApp sends alerts through the WhatsApp Cloud API to every opted-in
+// recipient. Business-initiated messages must use an approved template; it
+// receives the coin, the alert kind and the alert message as its three body
+// parameters, e.g. "Alerta {{1}} ({{2}}): {{3}}". Opt-in confirmation codes
+// are sent with an authentication template.
+type WhatsApp struct {
+	// API is the Graph API base URL; DefaultWhatsAppAPI is used when empty
+	API string
+	// PhoneNumberID is the ID of the business phone number messages are sent from
+	PhoneNumberID string
+	Token         string
+	Template      string
+	// CodeTemplate is the authentication template opt-in codes are sent with
+	CodeTemplate string
+	// Language is the template language used for recipients without their own
+	Language   string
+	Recipients Recipients
+	Client     *http.Client
+}
+
+// Notify implements alerts.Notifier. Every recipient gets their own request,
+// so one failed delivery does not stop the others.
+func (w WhatsApp) Notify(ctx context.Context, alert models.Alert) error {
+	recipients, err := w.Recipients.Recipients(models.ChannelWhatsApp)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, r := range recipients {
+		if err := w.post(ctx, w.message(r, alert)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.Recipient, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SendCode implements optin.CodeSender. Authentication templates take the
+// code both in the body and in their copy-code button.
+func (w WhatsApp) SendCode(ctx context.Context, r models.OptIn, code string) error {
+	if w.CodeTemplate == "" {
+		return errors.New("whatsapp: no code template configured")
+	}
+	return w.post(ctx, w.template(r, w.CodeTemplate, []map[string]any{
+		{"type": "body", "parameters": []map[string]string{whatsAppText(code)}},
+		{"type": "button", "sub_type": "url", "index": "0", "parameters": []map[string]string{whatsAppText(code)}},
+	}))
+}
+
+// post sends a message through the Cloud API
+func (w WhatsApp) post(ctx context.Context, message map[string]any) error {
+	api := w.API
+	if api == "" {
+		api = DefaultWhatsAppAPI
+	}
+	endpoint := fmt.Sprintf("%s/%s/messages", trimURL(api), url.PathEscape(w.PhoneNumberID))
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+w.Token)
+	return postJSON(ctx, w.Client, "whatsapp", endpoint, header, message)
+}
+
+// message is the template message of an alert for a recipient
+func (w WhatsApp) message(r models.OptIn, alert models.Alert) map[string]any {
+	return w.template(r, w.Template, []map[string]any{{
+		"type":       "body",
+		"parameters": []map[string]string{whatsAppText(alert.CryptoID), whatsAppText(string(alert.Kind)), whatsAppText(alert.Message)},
+	}})
+}
+
+// template is a template message for a recipient in their language
+func (w WhatsApp) template(r models.OptIn, name string, components []map[string]any) map[string]any {
+	language := r.Language
+	if language == "" {
+		language = w.Language
+	}
+	return map[string]any{
+		"messaging_product": "whatsapp",
+		"to":                r.Recipient,
+		"type":              "template",
+		"template": map[string]any{
+			"name":       name,
+			"language":   map[string]string{"code": language},
+			"components": components,
+		},
+	}
+}
+
+func whatsAppText(s string) map[string]string {
+	return map[string]string{"type": "text", "text": s}
+}
@@ -0,0 +1,99 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubRecipients []models.OptIn
+
+func (s stubRecipients) Recipients(channel models.OptInChannel) ([]models.OptIn, error) {
+	return s, nil
+}
+
+func TestWhatsApp_Notify(t *testing.T) {
+	var paths, auths []string
+	var bodies []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["to"] == "5521999990000" {
+			http.Error(w, `{"error":{"message":"Recipient not in allowed list"}}`, http.StatusBadRequest)
+			return
+		}
+		paths, auths, bodies = append(paths, r.URL.Path), append(auths, r.Header.Get("Authorization")), append(bodies, body)
+	}))
+	defer server.Close()
+
+	w := WhatsApp{
+		API:           server.URL,
+		PhoneNumberID: "1098765",
+		Token:         "secret",
+		Template:      "price_alert",
+		Language:      "pt_BR",
+		Recipients: stubRecipients{
+			{Channel: models.ChannelWhatsApp, Recipient: "5511987654321"},
+			{Channel: models.ChannelWhatsApp, Recipient: "5521999990000"},
+			{Channel: models.ChannelWhatsApp, Recipient: "14155550100", Language: "en_US"},
+		},
+	}
+	err := w.Notify(context.Background(), models.Alert{CryptoID: "bitcoin", Kind: models.AlertPriceBelow, Message: "below 300000"})
+	if err == nil || !strings.Contains(err.Error(), "5521999990000") {
+		t.Errorf("Expected the failed recipient to be reported, got %v", err)
+	}
+	if len(bodies) != 2 {
+		t.Fatalf("Expected the other recipients to be messaged, got %d", len(bodies))
+	}
+	if paths[0] != "/1098765/messages" || auths[0] != "Bearer secret" {
+		t.Errorf("Unexpected request: %s %q", paths[0], auths[0])
+	}
+
+	template := bodies[0]["template"].(map[string]any)
+	if bodies[0]["type"] != "template" || template["name"] != "price_alert" {
+		t.Errorf("Expected a template message, got %v", bodies[0])
+	}
+	if lang := template["language"].(map[string]any)["code"]; lang != "pt_BR" {
+		t.Errorf("Expected the default language, got %v", lang)
+	}
+	params := template["components"].([]any)[0].(map[string]any)["parameters"].([]any)
+	if len(params) != 3 || params[2].(map[string]any)["text"] != "below 300000" {
+		t.Errorf("Unexpected template parameters: %v", params)
+	}
+	if lang := bodies[1]["template"].(map[string]any)["language"].(map[string]any)["code"]; lang != "en_US" {
+		t.Errorf("Expected the recipient's language, got %v", lang)
+	}
+}
+
+func TestWhatsApp_SendCode(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer server.Close()
+
+	w := WhatsApp{API: server.URL, PhoneNumberID: "1098765", Token: "secret", Language: "pt_BR"}
+	recipient := models.OptIn{Channel: models.ChannelWhatsApp, Recipient: "5511987654321"}
+	if err := w.SendCode(context.Background(), recipient, "123456"); err == nil {
+		t.Error("Expected an error without a code template")
+	}
+
+	w.CodeTemplate = "optin_code"
+	if err := w.SendCode(context.Background(), recipient, "123456"); err != nil {
+		t.Fatalf("SendCode: %v", err)
+	}
+	template := body["template"].(map[string]any)
+	components := template["components"].([]any)
+	if body["to"] != "5511987654321" || template["name"] != "optin_code" || len(components) != 2 {
+		t.Fatalf("Unexpected code message: %v", body)
+	}
+	params := components[0].(map[string]any)["parameters"].([]any)
+	if params[0].(map[string]any)["text"] != "123456" {
+		t.Errorf("Expected the code as the body parameter, got %v", params)
+	}
+}
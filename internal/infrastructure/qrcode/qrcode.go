@@ -0,0 +1,95 @@
+// Package qrcode renders QR codes as PNG or SVG so the web UI needs no
+// client-side QR library.
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"rsc.io/qr"
+)
+
+// Format is an output image format
+type Format string
+
+// Supported formats
+const (
+	PNG Format = "png"
+	SVG Format = "svg"
+)
+
+// Scale bounds in pixels per module
+const (
+	DefaultScale = 8
+	MaxScale     = 32
+)
+
+// MaxTextLength caps the encoded text; longer payloads produce codes too dense to scan reliably
+const MaxTextLength = 1024
+
+// quietZone is the blank border in modules required around a code
+const quietZone = 4
+
+// ErrTooLong is returned for text longer than MaxTextLength
+var ErrTooLong = errors.New("qr text too long")
+
+// ParseFormat validates a format name; an empty name means PNG
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case "", PNG:
+		return PNG, nil
+	case SVG:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown qr format: %q", name)
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	if f == SVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// Render encodes text with medium error correction and writes the image.
+// Scale is the size of a module in pixels and is clamped to [1, MaxScale].
+func Render(w io.Writer, text string, format Format, scale int) error {
+	if len(text) > MaxTextLength {
+		return fmt.Errorf("%w: %d bytes, max %d", ErrTooLong, len(text), MaxTextLength)
+	}
+	code, err := qr.Encode(text, qr.M)
+	if err != nil {
+		return fmt.Errorf("encoding qr code: %w", err)
+	}
+	code.Scale = min(max(scale, 1), MaxScale)
+
+	if format == SVG {
+		return writeSVG(w, code)
+	}
+	_, err = w.Write(code.PNG())
+	return err
+}
+
+// writeSVG draws one path of unit squares in module coordinates and lets the
+// viewBox scale it, which keeps the document small and the edges crisp
+func writeSVG(w io.Writer, code *qr.Code) error {
+	side := code.Size + 2*quietZone
+	pixels := side * code.Scale
+
+	var path strings.Builder
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; x++ {
+			if code.Black(x, y) {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x+quietZone, y+quietZone)
+			}
+		}
+	}
+
+	_, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+
+		`<rect width="%d" height="%d" fill="#fff"/><path d="%s" fill="#000"/></svg>`,
+		pixels, pixels, side, side, side, side, path.String())
+	return err
+}
@@ -0,0 +1,57 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestRender_PNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, "bitcoin:bc1qexample", PNG, 4); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Expected a valid PNG: %v", err)
+	}
+	// Smallest version is 21 modules plus the quiet zone on both sides
+	if w := img.Bounds().Dx(); w%4 != 0 || w < (21+2*quietZone)*4 {
+		t.Errorf("Unexpected image width %d", w)
+	}
+}
+
+func TestRender_SVG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, "https://example.com/share/abc", SVG, 0); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	svg := buf.String()
+	if !strings.HasPrefix(svg, "<svg ") || !strings.HasSuffix(svg, "</svg>") {
+		t.Errorf("Expected an SVG document, got %.60s", svg)
+	}
+	if !strings.Contains(svg, `shape-rendering="crispEdges"`) || !strings.Contains(svg, "h1v1h-1z") {
+		t.Errorf("Expected drawn modules, got %.200s", svg)
+	}
+}
+
+func TestRender_TooLong(t *testing.T) {
+	err := Render(&bytes.Buffer{}, strings.Repeat("a", MaxTextLength+1), PNG, DefaultScale)
+	if !errors.Is(err, ErrTooLong) {
+		t.Errorf("Expected ErrTooLong, got %v", err)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat(""); err != nil || f != PNG {
+		t.Errorf("Expected PNG by default, got %q %v", f, err)
+	}
+	if f, err := ParseFormat("SVG"); err != nil || f.ContentType() != "image/svg+xml" {
+		t.Errorf("Expected SVG, got %q %v", f, err)
+	}
+	if _, err := ParseFormat("gif"); err == nil {
+		t.Error("Expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,250 @@
+// Package jsonfile stores repository data as JSON files
+package jsonfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/envelope"
+)
+
+// ErrEncrypted is returned when an encrypted ledger is read without a keyring
+var ErrEncrypted = errors.New("ledger is encrypted, configure database.encryption_keys to read it")
+
+// sealedColumns are the transaction fields encrypted in a sealed ledger, as
+// paths of JSON keys
+var sealedColumns = [][]string{{"quantity"}, {"price"}, {"fee", "amount"}}
+
+// sealedLedger is the file format of an encrypted ledger. The amounts of
+// each transaction are sealed strings; the other fields stay readable.
+type sealedLedger struct {
+	Encryption   *envelope.Header             `json:"encryption"`
+	Transactions []map[string]json.RawMessage `json:"transactions"`
+}
+
+// Ledger stores the transaction ledger as a JSON file. Without a keyring it
+// is a plain array of transactions; with one, the transaction amounts are
+// encrypted with a data key wrapped by the active master key. Both formats
+// are read transparently.
+type Ledger struct {
+	path string
+	keys *envelope.Keyring
+}
+
+// NewLedger creates a ledger stored at path. The keyring may be nil.
+func NewLedger(path string, keys *envelope.Keyring) *Ledger {
+	return &Ledger{path: path, keys: keys}
+}
+
+// Transactions reads every transaction of the ledger
+func (l *Ledger) Transactions() ([]models.Transaction, error) {
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		return nil, err
+	}
+	if !encrypted(data) {
+		var transactions []models.Transaction
+		if err := json.Unmarshal(data, &transactions); err != nil {
+			return nil, fmt.Errorf("failed to parse ledger: %w", err)
+		}
+		return transactions, nil
+	}
+
+	var file sealedLedger
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse ledger: %w", err)
+	}
+	if file.Encryption == nil {
+		return nil, errors.New("failed to parse ledger: missing encryption header")
+	}
+	if l.keys == nil {
+		return nil, ErrEncrypted
+	}
+	c, err := l.keys.Open(*file.Encryption)
+	if err != nil {
+		return nil, err
+	}
+	transactions := make([]models.Transaction, len(file.Transactions))
+	for i, row := range file.Transactions {
+		if err := transformColumns(row, rowID(row), func(value json.RawMessage, context string) (json.RawMessage, error) {
+			// Every amount of an encrypted ledger is sealed, so a plain one
+			// was written around the encryption and is not trusted
+			var sealed string
+			if json.Unmarshal(value, &sealed) != nil || !envelope.IsSealed(sealed) {
+				return nil, fmt.Errorf("%s is not encrypted", context)
+			}
+			return c.Open(sealed, context)
+		}); err != nil {
+			return nil, fmt.Errorf("failed to decrypt transaction %d: %w", i+1, err)
+		}
+		encoded, err := json.Marshal(row)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(encoded, &transactions[i]); err != nil {
+			return nil, fmt.Errorf("failed to parse transaction %d: %w", i+1, err)
+		}
+	}
+	return transactions, nil
+}
+
+// SaveTransactions replaces the ledger, encrypting it under a new data key
+// when there is a keyring
+func (l *Ledger) SaveTransactions(transactions []models.Transaction) error {
+	if l.keys == nil {
+		data, err := json.MarshalIndent(transactions, "", "  ")
+		if err != nil {
+			return err
+		}
+		return l.write(data)
+	}
+
+	header, c, err := l.keys.Generate()
+	if err != nil {
+		return err
+	}
+	file := sealedLedger{Encryption: &header, Transactions: make([]map[string]json.RawMessage, len(transactions))}
+	for i, tx := range transactions {
+		row, err := toRow(tx)
+		if err != nil {
+			return err
+		}
+		if err := transformColumns(row, tx.ID, func(value json.RawMessage, context string) (json.RawMessage, error) {
+			sealed, err := c.Seal(value, context)
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(sealed)
+		}); err != nil {
+			return err
+		}
+		file.Transactions[i] = row
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return l.write(data)
+}
+
+// Rotate wraps the data key of an encrypted ledger with the active master
+// key, which is enough to retire the previous one. With reencrypt, or for a
+// plain ledger, every amount is encrypted again under a new data key.
+func (l *Ledger) Rotate(reencrypt bool) error {
+	if l.keys == nil {
+		return errors.New("rotating the ledger key needs a keyring")
+	}
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		return err
+	}
+	if reencrypt || !encrypted(data) {
+		transactions, err := l.Transactions()
+		if err != nil {
+			return err
+		}
+		return l.SaveTransactions(transactions)
+	}
+
+	var file struct {
+		Encryption   *envelope.Header `json:"encryption"`
+		Transactions json.RawMessage  `json:"transactions"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse ledger: %w", err)
+	}
+	if file.Encryption == nil {
+		return errors.New("failed to parse ledger: missing encryption header")
+	}
+	header, err := l.keys.Rewrap(*file.Encryption)
+	if err != nil {
+		return err
+	}
+	file.Encryption = &header
+	if data, err = json.MarshalIndent(file, "", "  "); err != nil {
+		return err
+	}
+	return l.write(data)
+}
+
+// write replaces the file atomically so a failed write never loses the ledger
+func (l *Ledger) write(data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), ".ledger-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), l.path)
+}
+
+// encrypted reports whether the file is a sealed ledger rather than a plain array
+func encrypted(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+func toRow(tx models.Transaction) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(tx)
+	if err != nil {
+		return nil, err
+	}
+	var row map[string]json.RawMessage
+	return row, json.Unmarshal(data, &row)
+}
+
+func rowID(row map[string]json.RawMessage) string {
+	var id string
+	_ = json.Unmarshal(row["id"], &id)
+	return id
+}
+
+// transformColumns replaces the value of every sealed column present in the
+// row. The context passed along binds a value to its transaction and column.
+func transformColumns(row map[string]json.RawMessage, id string, transform func(value json.RawMessage, context string) (json.RawMessage, error)) error {
+	for _, path := range sealedColumns {
+		if err := transformColumn(row, path, id+"/"+strings.Join(path, "."), transform); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func transformColumn(row map[string]json.RawMessage, path []string, context string, transform func(json.RawMessage, string) (json.RawMessage, error)) error {
+	value, ok := row[path[0]]
+	if !ok {
+		return nil
+	}
+	if len(path) == 1 {
+		transformed, err := transform(value, context)
+		if err != nil {
+			return err
+		}
+		row[path[0]] = transformed
+		return nil
+	}
+	var nested map[string]json.RawMessage
+	if err := json.Unmarshal(value, &nested); err != nil {
+		return fmt.Errorf("%s must be an object: %w", path[0], err)
+	}
+	if err := transformColumn(nested, path[1:], context, transform); err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(nested)
+	if err != nil {
+		return err
+	}
+	row[path[0]] = encoded
+	return nil
+}
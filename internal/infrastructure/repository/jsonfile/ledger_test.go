@@ -0,0 +1,138 @@
+package jsonfile
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/envelope"
+)
+
+func keyring(t *testing.T, ids ...string) *envelope.Keyring {
+	t.Helper()
+	specs := make([]string, len(ids))
+	for i, id := range ids {
+		specs[i] = id + ":" + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte(id[:1]), envelope.KeySize))
+	}
+	k, err := envelope.ParseKeyring(specs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+var ledger = []models.Transaction{{
+	ID:        "tx-1",
+	CryptoID:  "bitcoin",
+	Type:      models.TransactionBuy,
+	Quantity:  decimal.RequireFromString("0.123456"),
+	Price:     decimal.RequireFromString("61234.5"),
+	Currency:  models.USD,
+	Fee:       models.Fee{Amount: decimal.RequireFromString("4.2")},
+	Exchange:  "kraken",
+	Timestamp: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+}}
+
+func TestLedger_Plain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transactions.json")
+	if err := os.WriteFile(path, []byte(`[{"id":"tx-1","crypto_id":"bitcoin","type":"buy","quantity":"2","price":"100"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// A keyring still reads plain ledgers
+	transactions, err := NewLedger(path, keyring(t, "a")).Transactions()
+	if err != nil || len(transactions) != 1 || !transactions[0].Quantity.Equal(decimal.NewFromInt(2)) {
+		t.Errorf("Expected the plain ledger to be read, got %+v, %v", transactions, err)
+	}
+}
+
+func TestLedger_Encrypted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transactions.json")
+	if err := NewLedger(path, keyring(t, "a")).SaveTransactions(ledger); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	for _, secret := range []string{"0.123456", "61234.5", "4.2"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Expected %s to be encrypted, got %s", secret, data)
+		}
+	}
+	if !strings.Contains(string(data), "kraken") {
+		t.Error("Expected the other columns to stay readable")
+	}
+
+	transactions, err := NewLedger(path, keyring(t, "a")).Transactions()
+	if err != nil || len(transactions) != 1 {
+		t.Fatalf("Expected the ledger back, got %+v, %v", transactions, err)
+	}
+	got := transactions[0]
+	if !got.Quantity.Equal(ledger[0].Quantity) || !got.Price.Equal(ledger[0].Price) || !got.Fee.Amount.Equal(ledger[0].Fee.Amount) || got.Exchange != "kraken" {
+		t.Errorf("Expected the decrypted transaction, got %+v", got)
+	}
+	if _, err := NewLedger(path, nil).Transactions(); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("Expected ErrEncrypted without a keyring, got %v", err)
+	}
+
+	// A plain amount slipped into the encrypted file is rejected
+	var file sealedLedger
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatal(err)
+	}
+	file.Transactions[0]["quantity"] = json.RawMessage(`"1000"`)
+	tampered, _ := json.Marshal(file)
+	if err := os.WriteFile(path, tampered, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewLedger(path, keyring(t, "a")).Transactions(); err == nil || !strings.Contains(err.Error(), "quantity is not encrypted") {
+		t.Errorf("Expected the unsealed quantity to be rejected, got %v", err)
+	}
+}
+
+func TestLedger_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transactions.json")
+	if err := NewLedger(path, keyring(t, "a")).SaveTransactions(ledger); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(path)
+
+	if err := NewLedger(path, keyring(t, "b", "a")).Rotate(false); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := os.ReadFile(path)
+	if !strings.Contains(string(after), `"key_id": "b"`) {
+		t.Errorf("Expected the data key wrapped with b, got %s", after)
+	}
+	// Re-wrapping leaves the sealed values untouched
+	if i := bytes.Index(before, []byte(`"transactions"`)); !bytes.Equal(before[i:], after[bytes.Index(after, []byte(`"transactions"`)):]) {
+		t.Error("Expected only the encryption header to change")
+	}
+
+	transactions, err := NewLedger(path, keyring(t, "b")).Transactions()
+	if err != nil || len(transactions) != 1 || !transactions[0].Quantity.Equal(ledger[0].Quantity) {
+		t.Errorf("Expected the ledger to open without the retired key, got %+v, %v", transactions, err)
+	}
+	if _, err := NewLedger(path, keyring(t, "a")).Transactions(); !errors.Is(err, envelope.ErrUnknownKey) {
+		t.Errorf("Expected the retired key to no longer open the ledger, got %v", err)
+	}
+}
+
+func TestLedger_RotateEncryptsPlainLedger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transactions.json")
+	if err := NewLedger(path, nil).SaveTransactions(ledger); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewLedger(path, keyring(t, "a")).Rotate(false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewLedger(path, nil).Transactions(); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("Expected the ledger to be encrypted, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"crypto-dashboard/internal/application/alerts"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// AlertRuleRepository stores alert rules in memory
+type AlertRuleRepository struct {
+	mu    sync.RWMutex
+	rules map[string]models.AlertRule
+}
+
+// NewAlertRuleRepository creates an empty repository
+func NewAlertRuleRepository() *AlertRuleRepository {
+	return &AlertRuleRepository{rules: make(map[string]models.AlertRule)}
+}
+
+// SaveRule stores the rule, assigning an ID when it has none
+func (r *AlertRuleRepository) SaveRule(rule models.AlertRule) (models.AlertRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rule.ID == "" {
+		rule.ID = newID()
+	}
+	r.rules[rule.ID] = rule
+	return rule, nil
+}
+
+// ListRules returns all rules sorted by creation time
+func (r *AlertRuleRepository) ListRules() ([]models.AlertRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	rules := make([]models.AlertRule, 0, len(r.rules))
+	for _, rule := range r.rules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].CreatedAt.Before(rules[j].CreatedAt)
+	})
+	return rules, nil
+}
+
+// DeleteRule removes a rule
+func (r *AlertRuleRepository) DeleteRule(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.rules[id]; !ok {
+		return alerts.ErrRuleNotFound
+	}
+	delete(r.rules, id)
+	return nil
+}
@@ -0,0 +1,30 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+
+	"crypto-dashboard/internal/application/alerts"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestAlertRuleRepository(t *testing.T) {
+	repo := NewAlertRuleRepository()
+
+	rule, _ := repo.SaveRule(models.AlertRule{CryptoID: "bitcoin", Kind: models.AlertGoldenCross})
+	if rule.ID == "" {
+		t.Fatal("Expected an ID to be assigned")
+	}
+
+	rules, _ := repo.ListRules()
+	if len(rules) != 1 {
+		t.Errorf("Expected 1 rule, got %d", len(rules))
+	}
+
+	if err := repo.DeleteRule(rule.ID); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := repo.DeleteRule(rule.ID); !errors.Is(err, alerts.ErrRuleNotFound) {
+		t.Errorf("Expected ErrRuleNotFound, got %v", err)
+	}
+}
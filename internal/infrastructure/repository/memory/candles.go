@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type candleKey struct {
+	cryptoID string
+	interval time.Duration
+}
+
+// CandleRepository stores candles in memory ordered by open time
+type CandleRepository struct {
+	mu      sync.RWMutex
+	candles map[candleKey][]models.Candle
+}
+
+// NewCandleRepository creates an empty repository
+func NewCandleRepository() *CandleRepository {
+	return &CandleRepository{candles: make(map[candleKey][]models.Candle)}
+}
+
+// SaveCandle inserts a candle, replacing any existing candle with the same open time
+func (r *CandleRepository) SaveCandle(interval time.Duration, candle models.Candle) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := candleKey{cryptoID: candle.CryptoID, interval: interval}
+	series := r.candles[key]
+	i := sort.Search(len(series), func(i int) bool {
+		return !series[i].OpenTime.Before(candle.OpenTime)
+	})
+	if i < len(series) && series[i].OpenTime.Equal(candle.OpenTime) {
+		series[i] = candle
+		return nil
+	}
+	series = append(series, models.Candle{})
+	copy(series[i+1:], series[i:])
+	series[i] = candle
+	r.candles[key] = series
+	return nil
+}
+
+// Candles returns the most recent candles, oldest first. A limit of 0 returns all of them.
+func (r *CandleRepository) Candles(cryptoID string, interval time.Duration, limit int) ([]models.Candle, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	series := r.candles[candleKey{cryptoID: cryptoID, interval: interval}]
+	if limit > 0 && len(series) > limit {
+		series = series[len(series)-limit:]
+	}
+	return append([]models.Candle(nil), series...), nil
+}
+
+// PruneCandles deletes the candles of a coin opened before the given time and returns how many were removed
+func (r *CandleRepository) PruneCandles(cryptoID string, interval time.Duration, before time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := candleKey{cryptoID: cryptoID, interval: interval}
+	series := r.candles[key]
+	i := sort.Search(len(series), func(i int) bool {
+		return !series[i].OpenTime.Before(before)
+	})
+	r.candles[key] = append([]models.Candle(nil), series[i:]...)
+	return i, nil
+}
@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestCandleRepository(t *testing.T) {
+	repo := NewCandleRepository()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, h := range []int{2, 0, 1} {
+		c := models.NewCandle("bitcoin", float64(h), start.Add(time.Duration(h)*time.Hour), time.Hour)
+		repo.SaveCandle(time.Hour, c)
+	}
+	// Replacing an existing candle keeps a single entry
+	repo.SaveCandle(time.Hour, models.NewCandle("bitcoin", 10, start.Add(time.Hour), time.Hour))
+
+	all, _ := repo.Candles("bitcoin", time.Hour, 0)
+	if len(all) != 3 {
+		t.Fatalf("Expected 3 candles, got %d", len(all))
+	}
+	if all[0].Close != 0 || all[1].Close != 10 || all[2].Close != 2 {
+		t.Errorf("Unexpected order or values: %v", models.Closes(all))
+	}
+
+	last, _ := repo.Candles("bitcoin", time.Hour, 2)
+	if len(last) != 2 || last[0].Close != 10 {
+		t.Errorf("Expected the 2 most recent candles, got %v", models.Closes(last))
+	}
+
+	if other, _ := repo.Candles("bitcoin", 24*time.Hour, 0); len(other) != 0 {
+		t.Errorf("Expected intervals to be stored separately, got %d", len(other))
+	}
+}
+
+func TestCandleRepository_Prune(t *testing.T) {
+	repo := NewCandleRepository()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for h := range 5 {
+		repo.SaveCandle(time.Hour, models.NewCandle("bitcoin", float64(h), start.Add(time.Duration(h)*time.Hour), time.Hour))
+	}
+
+	removed, err := repo.PruneCandles("bitcoin", time.Hour, start.Add(3*time.Hour))
+	if err != nil || removed != 3 {
+		t.Fatalf("Expected 3 pruned candles, got %d (%v)", removed, err)
+	}
+	left, _ := repo.Candles("bitcoin", time.Hour, 0)
+	if len(left) != 2 || left[0].Close != 3 {
+		t.Errorf("Expected the candles from 03:00 on to be kept, got %v", models.Closes(left))
+	}
+}
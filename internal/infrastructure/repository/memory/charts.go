@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"maps"
+	"sort"
+	"sync"
+
+	"crypto-dashboard/internal/application/chart"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// ChartRepository stores chart configurations in memory
+type ChartRepository struct {
+	mu     sync.RWMutex
+	charts map[string]models.ChartConfig
+}
+
+// NewChartRepository creates an empty repository
+func NewChartRepository() *ChartRepository {
+	return &ChartRepository{charts: make(map[string]models.ChartConfig)}
+}
+
+// Save stores the configuration, assigning an ID when it has none
+func (r *ChartRepository) Save(c models.ChartConfig) (models.ChartConfig, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c.ID == "" {
+		c.ID = newID()
+	}
+	c.Indicators = maps.Clone(c.Indicators)
+	r.charts[c.ID] = c
+	return c, nil
+}
+
+// Get returns a configuration by ID
+func (r *ChartRepository) Get(id string) (models.ChartConfig, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.charts[id]
+	if !ok {
+		return models.ChartConfig{}, chart.ErrNotFound
+	}
+	c.Indicators = maps.Clone(c.Indicators)
+	return c, nil
+}
+
+// List returns all configurations sorted by creation time
+func (r *ChartRepository) List() ([]models.ChartConfig, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	charts := make([]models.ChartConfig, 0, len(r.charts))
+	for _, c := range r.charts {
+		c.Indicators = maps.Clone(c.Indicators)
+		charts = append(charts, c)
+	}
+	sort.Slice(charts, func(i, j int) bool {
+		return charts[i].CreatedAt.Before(charts[j].CreatedAt)
+	})
+	return charts, nil
+}
+
+// Delete removes a configuration
+func (r *ChartRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.charts[id]; !ok {
+		return chart.ErrNotFound
+	}
+	delete(r.charts, id)
+	return nil
+}
@@ -0,0 +1,31 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+
+	"crypto-dashboard/internal/application/chart"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestChartRepository(t *testing.T) {
+	repo := NewChartRepository()
+	indicators := map[string]int{"sma": 50}
+	saved, err := repo.Save(models.ChartConfig{Name: "BTC", CryptoID: "bitcoin", Indicators: indicators})
+	if err != nil || saved.ID == "" {
+		t.Fatalf("Expected an assigned ID, got %+v (%v)", saved, err)
+	}
+
+	indicators["sma"] = 200
+	got, err := repo.Get(saved.ID)
+	if err != nil || got.Indicators["sma"] != 50 {
+		t.Errorf("Expected the stored indicators to be a copy, got %+v (%v)", got, err)
+	}
+
+	if err := repo.Delete(saved.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.Get(saved.ID); !errors.Is(err, chart.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after a delete, got %v", err)
+	}
+}
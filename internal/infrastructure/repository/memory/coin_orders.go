@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"slices"
+	"sync"
+
+	"crypto-dashboard/internal/application/preferences"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// CoinOrderRepository stores one coin order per owner in memory
+type CoinOrderRepository struct {
+	mu     sync.RWMutex
+	orders map[string]models.CoinOrder
+}
+
+// NewCoinOrderRepository creates an empty repository
+func NewCoinOrderRepository() *CoinOrderRepository {
+	return &CoinOrderRepository{orders: make(map[string]models.CoinOrder)}
+}
+
+// Get returns the coin order of an owner
+func (r *CoinOrderRepository) Get(owner string) (models.CoinOrder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	o, ok := r.orders[owner]
+	if !ok {
+		return models.CoinOrder{}, preferences.ErrNotFound
+	}
+	o.Pinned, o.Order = slices.Clone(o.Pinned), slices.Clone(o.Order)
+	return o, nil
+}
+
+// Save stores the coin order, replacing the owner's previous one
+func (r *CoinOrderRepository) Save(o models.CoinOrder) (models.CoinOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := o
+	stored.Pinned, stored.Order = slices.Clone(o.Pinned), slices.Clone(o.Order)
+	r.orders[o.Owner] = stored
+	return o, nil
+}
+
+// Delete removes the coin order of an owner
+func (r *CoinOrderRepository) Delete(owner string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.orders[owner]; !ok {
+		return preferences.ErrNotFound
+	}
+	delete(r.orders, owner)
+	return nil
+}
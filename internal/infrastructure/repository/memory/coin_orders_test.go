@@ -0,0 +1,31 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+
+	"crypto-dashboard/internal/application/preferences"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestCoinOrderRepository(t *testing.T) {
+	repo := NewCoinOrderRepository()
+	if _, err := repo.Get("alice"); !errors.Is(err, preferences.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+
+	pinned := []string{"solana"}
+	repo.Save(models.CoinOrder{Owner: "alice", Pinned: pinned})
+	pinned[0] = "dogecoin"
+	got, err := repo.Get("alice")
+	if err != nil || got.Pinned[0] != "solana" {
+		t.Errorf("Expected the stored order to be a copy, got %+v (%v)", got, err)
+	}
+
+	if err := repo.Delete("alice"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := repo.Delete("alice"); !errors.Is(err, preferences.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound on a second delete, got %v", err)
+	}
+}
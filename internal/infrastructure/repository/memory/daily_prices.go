@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type dailyPriceKey struct {
+	cryptoID string
+	currency models.Currency
+	day      string
+}
+
+// DailyPriceRepository stores historical daily prices in memory
+type DailyPriceRepository struct {
+	mu     sync.RWMutex
+	prices map[dailyPriceKey]float64
+}
+
+// NewDailyPriceRepository creates an empty repository
+func NewDailyPriceRepository() *DailyPriceRepository {
+	return &DailyPriceRepository{prices: make(map[dailyPriceKey]float64)}
+}
+
+// DailyPrice returns the stored price of a coin on the UTC day of day
+func (r *DailyPriceRepository) DailyPrice(cryptoID string, currency models.Currency, day time.Time) (float64, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	price, ok := r.prices[newDailyPriceKey(cryptoID, currency, day)]
+	return price, ok, nil
+}
+
+// SaveDailyPrice stores the price of a coin on the UTC day of day
+func (r *DailyPriceRepository) SaveDailyPrice(cryptoID string, currency models.Currency, day time.Time, price float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prices[newDailyPriceKey(cryptoID, currency, day)] = price
+	return nil
+}
+
+func newDailyPriceKey(cryptoID string, currency models.Currency, day time.Time) dailyPriceKey {
+	return dailyPriceKey{cryptoID: cryptoID, currency: currency, day: day.UTC().Format(time.DateOnly)}
+}
@@ -0,0 +1,25 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestDailyPriceRepository(t *testing.T) {
+	repo := NewDailyPriceRepository()
+	day := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	if _, ok, _ := repo.DailyPrice("bitcoin", models.USD, day); ok {
+		t.Fatal("Expected an empty repository")
+	}
+
+	repo.SaveDailyPrice("bitcoin", models.USD, day, 68000)
+	if price, ok, _ := repo.DailyPrice("bitcoin", models.USD, day.Add(20*time.Hour)); !ok || price != 68000 {
+		t.Errorf("Expected the price for any time of the day, got %f %v", price, ok)
+	}
+	if _, ok, _ := repo.DailyPrice("bitcoin", models.EUR, day); ok {
+		t.Error("Expected prices to be stored per currency")
+	}
+}
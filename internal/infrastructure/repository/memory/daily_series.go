@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"container/list"
+	"slices"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// pointSize approximates the memory held by a stored point: the price and a
+// time.Time of wall clock, monotonic reading and location
+const pointSize = 32
+
+// SeriesObserver is notified about series lookups and evictions, e.g. to export metrics
+type SeriesObserver interface {
+	SeriesLookup(hit bool)
+	SeriesEvicted()
+	SeriesCacheSize(bytes int64)
+}
+
+type dailySeries struct {
+	points  []models.PricePoint
+	through time.Time
+}
+
+// seriesCoin holds every stored series of a coin, one per currency
+type seriesCoin struct {
+	cryptoID string
+	series   map[models.Currency]dailySeries
+	size     int64
+}
+
+// DailySeriesRepository stores since-inception daily series in memory. With
+// a budget it becomes a cache: the coins viewed least recently are evicted
+// until the series fit, and are fetched again on their next view.
+type DailySeriesRepository struct {
+	mu       sync.Mutex
+	coins    map[string]*list.Element
+	recency  *list.List // of *seriesCoin, most recently viewed first
+	size     int64
+	budget   int64
+	observer SeriesObserver
+}
+
+// NewDailySeriesRepository creates an empty repository without a budget
+func NewDailySeriesRepository() *DailySeriesRepository {
+	return &DailySeriesRepository{coins: make(map[string]*list.Element), recency: list.New()}
+}
+
+// SetBudget bounds the approximate bytes held by the series; zero removes the
+// bound. The most recently viewed coin is always kept, even over budget.
+func (r *DailySeriesRepository) SetBudget(bytes int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.budget = bytes
+	r.evict()
+}
+
+// SetObserver registers an observer. It must be called before the repository is used.
+func (r *DailySeriesRepository) SetObserver(observer SeriesObserver) {
+	r.observer = observer
+}
+
+// DailySeries returns a copy of the stored series and the last day it covers
+func (r *DailySeriesRepository) DailySeries(cryptoID string, currency models.Currency) ([]models.PricePoint, time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var s dailySeries
+	var ok bool
+	if e, found := r.coins[cryptoID]; found {
+		r.recency.MoveToFront(e)
+		s, ok = e.Value.(*seriesCoin).series[currency]
+	}
+	if r.observer != nil {
+		r.observer.SeriesLookup(ok)
+	}
+	return slices.Clone(s.points), s.through, nil
+}
+
+// SaveDailySeries replaces the stored series and evicts other coins over budget
+func (r *DailySeriesRepository) SaveDailySeries(cryptoID string, currency models.Currency, points []models.PricePoint, through time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.coins[cryptoID]
+	if !ok {
+		e = r.recency.PushFront(&seriesCoin{cryptoID: cryptoID, series: make(map[models.Currency]dailySeries)})
+		r.coins[cryptoID] = e
+	}
+	r.recency.MoveToFront(e)
+	coin := e.Value.(*seriesCoin)
+
+	grown := int64(len(points)-len(coin.series[currency].points)) * pointSize
+	coin.size += grown
+	r.size += grown
+	coin.series[currency] = dailySeries{points: slices.Clone(points), through: through}
+	r.evict()
+	return nil
+}
+
+// evict drops the least recently viewed coins until the series fit the budget
+func (r *DailySeriesRepository) evict() {
+	for r.budget > 0 && r.size > r.budget && r.recency.Len() > 1 {
+		coin := r.recency.Remove(r.recency.Back()).(*seriesCoin)
+		delete(r.coins, coin.cryptoID)
+		r.size -= coin.size
+		if r.observer != nil {
+			r.observer.SeriesEvicted()
+		}
+	}
+	if r.observer != nil {
+		r.observer.SeriesCacheSize(r.size)
+	}
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestDailySeriesRepository(t *testing.T) {
+	repo := NewDailySeriesRepository()
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	if points, through, _ := repo.DailySeries("bitcoin", models.USD); points != nil || !through.IsZero() {
+		t.Fatalf("Expected an empty series, got %v through %s", points, through)
+	}
+
+	points := []models.PricePoint{{Price: 60000, Time: day}}
+	repo.SaveDailySeries("bitcoin", models.USD, points, day.AddDate(0, 0, 1))
+	points[0].Price = 1
+
+	got, through, err := repo.DailySeries("bitcoin", models.USD)
+	if err != nil || len(got) != 1 || got[0].Price != 60000 || !through.Equal(day.AddDate(0, 0, 1)) {
+		t.Errorf("Unexpected series: %v through %s (%v)", got, through, err)
+	}
+	if got, _, _ := repo.DailySeries("bitcoin", models.EUR); got != nil {
+		t.Errorf("Expected currencies to be stored apart, got %v", got)
+	}
+}
+
+// seriesStats counts series lookups and evictions
+type seriesStats struct {
+	hits, misses, evictions int
+	size                    int64
+}
+
+func (s *seriesStats) SeriesLookup(hit bool) {
+	if hit {
+		s.hits++
+	} else {
+		s.misses++
+	}
+}
+
+func (s *seriesStats) SeriesEvicted()              { s.evictions++ }
+func (s *seriesStats) SeriesCacheSize(bytes int64) { s.size = bytes }
+
+func TestDailySeriesRepository_Budget(t *testing.T) {
+	repo := NewDailySeriesRepository()
+	stats := &seriesStats{}
+	repo.SetObserver(stats)
+	repo.SetBudget(2 * 10 * pointSize)
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	series := make([]models.PricePoint, 10)
+
+	repo.SaveDailySeries("bitcoin", models.USD, series, day)
+	repo.SaveDailySeries("ethereum", models.USD, series, day)
+	repo.DailySeries("bitcoin", models.USD)
+	repo.SaveDailySeries("solana", models.USD, series, day)
+
+	if got, _, _ := repo.DailySeries("ethereum", models.USD); got != nil {
+		t.Error("Expected the least recently viewed coin to be evicted")
+	}
+	if got, _, _ := repo.DailySeries("bitcoin", models.USD); len(got) != 10 {
+		t.Error("Expected the recently viewed coin to be kept")
+	}
+	if stats.hits != 2 || stats.misses != 1 || stats.evictions != 1 || stats.size != 2*10*pointSize {
+		t.Errorf("Unexpected cache stats: %+v", *stats)
+	}
+
+	// A coin is evicted with every currency, and the latest one is kept over budget
+	repo.SaveDailySeries("bitcoin", models.EUR, make([]models.PricePoint, 30), day)
+	if got, _, _ := repo.DailySeries("bitcoin", models.EUR); len(got) != 30 || stats.evictions != 2 {
+		t.Errorf("Expected the coin over budget to be kept alone, got %d points after %d evictions", len(got), stats.evictions)
+	}
+	if got, _, _ := repo.DailySeries("solana", models.USD); got != nil {
+		t.Error("Expected the other coins to be evicted")
+	}
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// ETFFlowRepository stores daily ETF flows in memory
+type ETFFlowRepository struct {
+	mu    sync.RWMutex
+	flows map[string]map[time.Time]models.ETFFlow
+}
+
+// NewETFFlowRepository creates an empty repository
+func NewETFFlowRepository() *ETFFlowRepository {
+	return &ETFFlowRepository{flows: make(map[string]map[time.Time]models.ETFFlow)}
+}
+
+// SaveFlows stores the flows, replacing those of the same asset and day
+func (r *ETFFlowRepository) SaveFlows(flows []models.ETFFlow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, f := range flows {
+		byDay, ok := r.flows[f.Asset]
+		if !ok {
+			byDay = make(map[time.Time]models.ETFFlow)
+			r.flows[f.Asset] = byDay
+		}
+		byDay[f.Date.UTC()] = f
+	}
+	return nil
+}
+
+// Flows returns the flows of an asset between from and to inclusive, sorted by date
+func (r *ETFFlowRepository) Flows(asset string, from, to time.Time) ([]models.ETFFlow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var flows []models.ETFFlow
+	for day, f := range r.flows[asset] {
+		if !day.Before(from) && !day.After(to) {
+			flows = append(flows, f)
+		}
+	}
+	sort.Slice(flows, func(a, b int) bool {
+		return flows[a].Date.Before(flows[b].Date)
+	})
+	return flows, nil
+}
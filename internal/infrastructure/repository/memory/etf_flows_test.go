@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestETFFlowRepository(t *testing.T) {
+	repo := NewETFFlowRepository()
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	repo.SaveFlows([]models.ETFFlow{
+		{Asset: "BTC", Date: day(3), NetFlow: 30},
+		{Asset: "BTC", Date: day(1), NetFlow: 10},
+		{Asset: "BTC", Date: day(2), NetFlow: 20},
+		{Asset: "ETH", Date: day(2), NetFlow: -5},
+	})
+	// A revised figure replaces the first one of the day
+	repo.SaveFlows([]models.ETFFlow{{Asset: "BTC", Date: day(2), NetFlow: 25}})
+
+	flows, err := repo.Flows("BTC", day(2), day(3))
+	if err != nil {
+		t.Fatalf("Flows: %v", err)
+	}
+	if len(flows) != 2 || flows[0].NetFlow != 25 || flows[1].NetFlow != 30 {
+		t.Errorf("Unexpected flows: %+v", flows)
+	}
+	if flows, _ := repo.Flows("SOL", day(1), day(3)); len(flows) != 0 {
+		t.Errorf("Expected no flows for an unknown asset, got %+v", flows)
+	}
+}
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"crypto-dashboard/internal/application/calendar"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// EventRepository stores calendar events in memory
+type EventRepository struct {
+	mu     sync.RWMutex
+	events map[string]models.Event
+}
+
+// NewEventRepository creates an empty repository
+func NewEventRepository() *EventRepository {
+	return &EventRepository{events: make(map[string]models.Event)}
+}
+
+// Save stores the event, assigning an ID when it has none
+func (r *EventRepository) Save(e models.Event) (models.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e.ID == "" {
+		e.ID = newID()
+	}
+	r.events[e.ID] = e
+	return e, nil
+}
+
+// Get returns an event by ID
+func (r *EventRepository) Get(id string) (models.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.events[id]
+	if !ok {
+		return models.Event{}, calendar.ErrNotFound
+	}
+	return e, nil
+}
+
+// List returns all events sorted by start time
+func (r *EventRepository) List() ([]models.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	events := make([]models.Event, 0, len(r.events))
+	for _, e := range r.events {
+		events = append(events, e)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+	return events, nil
+}
+
+// Delete removes an event
+func (r *EventRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.events[id]; !ok {
+		return calendar.ErrNotFound
+	}
+	delete(r.events, id)
+	return nil
+}
@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/calendar"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestEventRepository(t *testing.T) {
+	repo := NewEventRepository()
+	now := time.Now()
+
+	later, _ := repo.Save(models.Event{Title: "Upgrade", Start: now.Add(time.Hour)})
+	sooner, _ := repo.Save(models.Event{Title: "Unlock", Start: now})
+	if later.ID == "" || later.ID == sooner.ID {
+		t.Fatalf("Expected unique IDs, got %q and %q", later.ID, sooner.ID)
+	}
+
+	if got, err := repo.Get(sooner.ID); err != nil || got.Title != "Unlock" {
+		t.Fatalf("Expected to get Unlock, got %+v (%v)", got, err)
+	}
+
+	events, _ := repo.List()
+	if len(events) != 2 || events[0].Title != "Unlock" {
+		t.Errorf("Expected events sorted by start, got %+v", events)
+	}
+
+	if err := repo.Delete(sooner.ID); err != nil {
+		t.Errorf("Unexpected error deleting: %v", err)
+	}
+	if _, err := repo.Get(sooner.ID); !errors.Is(err, calendar.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if err := repo.Delete(sooner.ID); !errors.Is(err, calendar.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
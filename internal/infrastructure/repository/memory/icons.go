@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"slices"
+	"sync"
+)
+
+// IconRepository stores coin logos in memory
+type IconRepository struct {
+	mu    sync.RWMutex
+	icons map[string][]byte
+}
+
+// NewIconRepository creates an empty repository
+func NewIconRepository() *IconRepository {
+	return &IconRepository{icons: make(map[string][]byte)}
+}
+
+// Icon returns a copy of the stored logo
+func (r *IconRepository) Icon(cryptoID string) ([]byte, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	data, ok := r.icons[cryptoID]
+	return slices.Clone(data), ok, nil
+}
+
+// SaveIcon stores the logo, replacing any previous one
+func (r *IconRepository) SaveIcon(cryptoID string, data []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.icons[cryptoID] = slices.Clone(data)
+	return nil
+}
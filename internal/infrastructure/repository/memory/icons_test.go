@@ -0,0 +1,17 @@
+package memory
+
+import "testing"
+
+func TestIconRepository(t *testing.T) {
+	repo := NewIconRepository()
+	if _, ok, _ := repo.Icon("bitcoin"); ok {
+		t.Fatal("Expected no icon before it is saved")
+	}
+
+	data := []byte("png")
+	repo.SaveIcon("bitcoin", data)
+	data[0] = 'x'
+	if got, ok, err := repo.Icon("bitcoin"); !ok || err != nil || string(got) != "png" {
+		t.Errorf("Expected the saved icon, got %q (%v)", got, err)
+	}
+}
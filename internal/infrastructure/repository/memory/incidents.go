@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"crypto-dashboard/internal/application/incident"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// MaxIncidents is the number of incidents kept; the earliest started are
+// dropped beyond it
+const MaxIncidents = 20
+
+// IncidentRepository stores incidents in memory, at most MaxIncidents
+type IncidentRepository struct {
+	mu        sync.RWMutex
+	incidents map[string]models.Incident
+}
+
+// NewIncidentRepository creates an empty repository
+func NewIncidentRepository() *IncidentRepository {
+	return &IncidentRepository{incidents: make(map[string]models.Incident)}
+}
+
+// Save stores the incident, assigning an ID when it has none
+func (r *IncidentRepository) Save(i models.Incident) (models.Incident, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i.ID == "" {
+		i.ID = newID()
+	}
+	r.incidents[i.ID] = i
+	for len(r.incidents) > MaxIncidents {
+		var earliest models.Incident
+		for _, kept := range r.incidents {
+			if earliest.ID == "" || kept.Started.Before(earliest.Started) {
+				earliest = kept
+			}
+		}
+		delete(r.incidents, earliest.ID)
+	}
+	return i, nil
+}
+
+// Get returns an incident by ID
+func (r *IncidentRepository) Get(id string) (models.Incident, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i, ok := r.incidents[id]
+	if !ok {
+		return models.Incident{}, incident.ErrNotFound
+	}
+	return i, nil
+}
+
+// List returns all incidents sorted by start
+func (r *IncidentRepository) List() ([]models.Incident, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	incidents := make([]models.Incident, 0, len(r.incidents))
+	for _, i := range r.incidents {
+		incidents = append(incidents, i)
+	}
+	sort.Slice(incidents, func(a, b int) bool {
+		return incidents[a].Started.Before(incidents[b].Started)
+	})
+	return incidents, nil
+}
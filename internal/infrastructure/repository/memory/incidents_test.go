@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/incident"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestIncidentRepository(t *testing.T) {
+	repo := NewIncidentRepository()
+	now := time.Now()
+
+	later, _ := repo.Save(models.Incident{Description: "Outage", Started: now.Add(time.Hour)})
+	sooner, _ := repo.Save(models.Incident{Description: "Bad prints", Started: now})
+	if later.ID == "" || later.ID == sooner.ID {
+		t.Fatalf("Expected unique IDs, got %q and %q", later.ID, sooner.ID)
+	}
+
+	resolved := now.Add(time.Minute)
+	sooner.Resolved = &resolved
+	repo.Save(sooner)
+	if got, err := repo.Get(sooner.ID); err != nil || got.Ongoing() {
+		t.Fatalf("Expected the resolved incident, got %+v (%v)", got, err)
+	}
+
+	incidents, _ := repo.List()
+	if len(incidents) != 2 || incidents[0].Description != "Bad prints" {
+		t.Errorf("Expected incidents sorted by start, got %+v", incidents)
+	}
+	if _, err := repo.Get("missing"); !errors.Is(err, incident.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestIncidentRepository_Bounded(t *testing.T) {
+	repo := NewIncidentRepository()
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	for n := range MaxIncidents + 5 {
+		repo.Save(models.Incident{Description: "Outage", Started: start.Add(time.Duration(n) * time.Hour)})
+	}
+	incidents, _ := repo.List()
+	if len(incidents) != MaxIncidents || !incidents[0].Started.Equal(start.Add(5*time.Hour)) {
+		t.Errorf("Expected the latest %d incidents, got %d from %s", MaxIncidents, len(incidents), incidents[0].Started)
+	}
+}
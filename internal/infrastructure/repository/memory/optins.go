@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"crypto-dashboard/internal/application/optin"
+	"crypto-dashboard/internal/domain/models"
+)
+
+type optInKey struct {
+	channel   models.OptInChannel
+	recipient string
+}
+
+// OptInRepository stores opt-ins in memory
+type OptInRepository struct {
+	mu     sync.RWMutex
+	optIns map[optInKey]models.OptIn
+}
+
+// NewOptInRepository creates an empty repository
+func NewOptInRepository() *OptInRepository {
+	return &OptInRepository{optIns: make(map[optInKey]models.OptIn)}
+}
+
+// Save stores the opt-in, replacing the recipient's previous one on the channel
+func (r *OptInRepository) Save(o models.OptIn) (models.OptIn, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.optIns[optInKey{channel: o.Channel, recipient: o.Recipient}] = o
+	return o, nil
+}
+
+// Get returns the opt-in of a recipient
+func (r *OptInRepository) Get(channel models.OptInChannel, recipient string) (models.OptIn, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	o, ok := r.optIns[optInKey{channel: channel, recipient: recipient}]
+	if !ok {
+		return models.OptIn{}, optin.ErrNotFound
+	}
+	return o, nil
+}
+
+// Delete removes the opt-in of a recipient
+func (r *OptInRepository) Delete(channel models.OptInChannel, recipient string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := optInKey{channel: channel, recipient: recipient}
+	if _, ok := r.optIns[key]; !ok {
+		return optin.ErrNotFound
+	}
+	delete(r.optIns, key)
+	return nil
+}
+
+// List returns the opt-ins of a channel ordered by recipient
+func (r *OptInRepository) List(channel models.OptInChannel) ([]models.OptIn, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var optIns []models.OptIn
+	for key, o := range r.optIns {
+		if key.channel == channel {
+			optIns = append(optIns, o)
+		}
+	}
+	sort.Slice(optIns, func(i, j int) bool { return optIns[i].Recipient < optIns[j].Recipient })
+	return optIns, nil
+}
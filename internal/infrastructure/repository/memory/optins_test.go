@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+
+	"crypto-dashboard/internal/application/optin"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestOptInRepository(t *testing.T) {
+	repo := NewOptInRepository()
+	repo.Save(models.OptIn{Channel: models.ChannelWhatsApp, Recipient: "5521999990000"})
+	repo.Save(models.OptIn{Channel: models.ChannelWhatsApp, Recipient: "5511987654321"})
+	repo.Save(models.OptIn{Channel: models.ChannelWhatsApp, Recipient: "5511987654321", Language: "en"})
+
+	got, _ := repo.List(models.ChannelWhatsApp)
+	if len(got) != 2 || got[0].Recipient != "5511987654321" || got[0].Language != "en" {
+		t.Fatalf("Expected 2 opt-ins ordered by recipient, got %+v", got)
+	}
+
+	if o, err := repo.Get(models.ChannelWhatsApp, "5511987654321"); err != nil || o.Language != "en" {
+		t.Errorf("Expected the latest opt-in, got %+v (err %v)", o, err)
+	}
+	if err := repo.Delete(models.ChannelWhatsApp, "5521999990000"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := repo.Delete(models.ChannelWhatsApp, "5521999990000"); !errors.Is(err, optin.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound on a second delete, got %v", err)
+	}
+	if _, err := repo.Get(models.ChannelWhatsApp, "5521999990000"); !errors.Is(err, optin.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after the delete, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"sync"
+
+	"crypto-dashboard/internal/application/theme"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// ThemeRepository stores one theme per owner in memory
+type ThemeRepository struct {
+	mu     sync.RWMutex
+	themes map[string]models.Theme
+}
+
+// NewThemeRepository creates an empty repository
+func NewThemeRepository() *ThemeRepository {
+	return &ThemeRepository{themes: make(map[string]models.Theme)}
+}
+
+// Get returns the theme of an owner
+func (r *ThemeRepository) Get(owner string) (models.Theme, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.themes[owner]
+	if !ok {
+		return models.Theme{}, theme.ErrNotFound
+	}
+	return t, nil
+}
+
+// Save stores the theme, replacing the owner's previous one
+func (r *ThemeRepository) Save(t models.Theme) (models.Theme, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.themes[t.Owner] = t
+	return t, nil
+}
+
+// Delete removes the theme of an owner
+func (r *ThemeRepository) Delete(owner string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.themes[owner]; !ok {
+		return theme.ErrNotFound
+	}
+	delete(r.themes, owner)
+	return nil
+}
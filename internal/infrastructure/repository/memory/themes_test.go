@@ -0,0 +1,30 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+
+	"crypto-dashboard/internal/application/theme"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestThemeRepository(t *testing.T) {
+	repo := NewThemeRepository()
+	if _, err := repo.Get("alice"); !errors.Is(err, theme.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+
+	repo.Save(models.Theme{Owner: "alice", Mode: models.ThemeLight})
+	repo.Save(models.Theme{Owner: "alice", Mode: models.ThemeHighContrast})
+	got, err := repo.Get("alice")
+	if err != nil || got.Mode != models.ThemeHighContrast {
+		t.Errorf("Expected the latest theme, got %+v (%v)", got, err)
+	}
+
+	if err := repo.Delete("alice"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := repo.Delete("alice"); !errors.Is(err, theme.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound on a second delete, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+// Package memory provides in-memory implementations of the repositories.
+// It is selected with the "memory://" database DSN and is safe for concurrent use.
+package memory
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+
+	"crypto-dashboard/internal/application/risk"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// WatchOrderRepository stores watch orders in memory
+type WatchOrderRepository struct {
+	mu     sync.RWMutex
+	orders map[string]models.WatchOrder
+}
+
+// NewWatchOrderRepository creates an empty repository
+func NewWatchOrderRepository() *WatchOrderRepository {
+	return &WatchOrderRepository{orders: make(map[string]models.WatchOrder)}
+}
+
+// Save stores the order, assigning an ID when it has none
+func (r *WatchOrderRepository) Save(order models.WatchOrder) (models.WatchOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if order.ID == "" {
+		order.ID = newID()
+	}
+	r.orders[order.ID] = order
+	return order, nil
+}
+
+// List returns all orders sorted by creation time
+func (r *WatchOrderRepository) List() ([]models.WatchOrder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	orders := make([]models.WatchOrder, 0, len(r.orders))
+	for _, o := range r.orders {
+		orders = append(orders, o)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+	})
+	return orders, nil
+}
+
+// Delete removes an order
+func (r *WatchOrderRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.orders[id]; !ok {
+		return risk.ErrNotFound
+	}
+	delete(r.orders, id)
+	return nil
+}
+
+// newID returns a random 16 character hexadecimal identifier
+func newID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
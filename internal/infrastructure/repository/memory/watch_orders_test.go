@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/risk"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestWatchOrderRepository(t *testing.T) {
+	repo := NewWatchOrderRepository()
+	now := time.Now()
+
+	second, _ := repo.Save(models.WatchOrder{CryptoID: "ethereum", CreatedAt: now.Add(time.Minute)})
+	first, _ := repo.Save(models.WatchOrder{CryptoID: "bitcoin", CreatedAt: now})
+	if first.ID == "" || first.ID == second.ID {
+		t.Fatalf("Expected unique IDs, got %q and %q", first.ID, second.ID)
+	}
+
+	orders, _ := repo.List()
+	if len(orders) != 2 || orders[0].CryptoID != "bitcoin" {
+		t.Errorf("Expected orders sorted by creation time, got %+v", orders)
+	}
+
+	if err := repo.Delete(first.ID); err != nil {
+		t.Errorf("Unexpected error deleting: %v", err)
+	}
+	if err := repo.Delete(first.ID); !errors.Is(err, risk.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
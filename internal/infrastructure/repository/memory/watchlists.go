@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"slices"
+	"sort"
+	"sync"
+
+	"crypto-dashboard/internal/application/watchlist"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// WatchlistRepository stores watchlists in memory
+type WatchlistRepository struct {
+	mu         sync.RWMutex
+	watchlists map[string]models.Watchlist
+}
+
+// NewWatchlistRepository creates an empty repository
+func NewWatchlistRepository() *WatchlistRepository {
+	return &WatchlistRepository{watchlists: make(map[string]models.Watchlist)}
+}
+
+// Save stores the watchlist, assigning an ID when it has none
+func (r *WatchlistRepository) Save(w models.Watchlist) (models.Watchlist, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if w.ID == "" {
+		w.ID = newID()
+	}
+	w.Coins = slices.Clone(w.Coins)
+	r.watchlists[w.ID] = w
+	return w, nil
+}
+
+// Get returns a watchlist by ID
+func (r *WatchlistRepository) Get(id string) (models.Watchlist, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	w, ok := r.watchlists[id]
+	if !ok {
+		return models.Watchlist{}, watchlist.ErrNotFound
+	}
+	w.Coins = slices.Clone(w.Coins)
+	return w, nil
+}
+
+// List returns all watchlists sorted by creation time
+func (r *WatchlistRepository) List() ([]models.Watchlist, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	lists := make([]models.Watchlist, 0, len(r.watchlists))
+	for _, w := range r.watchlists {
+		w.Coins = slices.Clone(w.Coins)
+		lists = append(lists, w)
+	}
+	sort.Slice(lists, func(i, j int) bool {
+		return lists[i].CreatedAt.Before(lists[j].CreatedAt)
+	})
+	return lists, nil
+}
+
+// Delete removes a watchlist
+func (r *WatchlistRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.watchlists[id]; !ok {
+		return watchlist.ErrNotFound
+	}
+	delete(r.watchlists, id)
+	return nil
+}
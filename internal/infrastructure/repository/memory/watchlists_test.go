@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/watchlist"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestWatchlistRepository(t *testing.T) {
+	repo := NewWatchlistRepository()
+	now := time.Now()
+
+	second, _ := repo.Save(models.Watchlist{Name: "Alts", CreatedAt: now.Add(time.Minute)})
+	first, _ := repo.Save(models.Watchlist{Name: "Majors", Coins: []string{"bitcoin"}, CreatedAt: now})
+	if first.ID == "" || first.ID == second.ID {
+		t.Fatalf("Expected unique IDs, got %q and %q", first.ID, second.ID)
+	}
+
+	got, err := repo.Get(first.ID)
+	if err != nil || got.Name != "Majors" {
+		t.Fatalf("Expected to get Majors, got %+v (%v)", got, err)
+	}
+	got.Coins[0] = "changed"
+	if again, _ := repo.Get(first.ID); again.Coins[0] != "bitcoin" {
+		t.Error("Expected stored coins to be isolated from callers")
+	}
+
+	lists, _ := repo.List()
+	if len(lists) != 2 || lists[0].Name != "Majors" {
+		t.Errorf("Expected lists sorted by creation time, got %+v", lists)
+	}
+
+	if err := repo.Delete(first.ID); err != nil {
+		t.Errorf("Unexpected error deleting: %v", err)
+	}
+	if _, err := repo.Get(first.ID); !errors.Is(err, watchlist.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if err := repo.Delete(first.ID); !errors.Is(err, watchlist.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
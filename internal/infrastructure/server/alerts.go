@@ -0,0 +1,50 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"crypto-dashboard/internal/application/alerts"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func (s *Server) handleListAlertRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := s.services.Alerts.Rules()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rules)
+}
+
+func (s *Server) handleCreateAlertRule(w http.ResponseWriter, r *http.Request) {
+	var rule models.AlertRule
+	if err := decodeJSON(r, &rule); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	created, err := s.services.Alerts.CreateRule(rule)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) handleDeleteAlertRule(w http.ResponseWriter, r *http.Request) {
+	err := s.services.Alerts.DeleteRule(r.PathValue("id"))
+	if errors.Is(err, alerts.ErrRuleNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleRecentAlerts(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.services.Alerts.RecentAlerts())
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestAlertRuleEndpoints(t *testing.T) {
+	s := newTestServer()
+
+	rec := do(t, s, http.MethodPost, "/api/v1/alerts/rules", `{"crypto_id":"bitcoin","kind":"rsi_above"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var created models.AlertRule
+	json.NewDecoder(rec.Body).Decode(&created)
+	if created.Period != 14 || created.Threshold != 70 {
+		t.Errorf("Expected RSI defaults to be applied, got %+v", created)
+	}
+
+	rec = do(t, s, http.MethodPost, "/api/v1/alerts/rules", `{"crypto_id":"bitcoin","kind":"unknown"}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422, got %d", rec.Code)
+	}
+
+	rec = do(t, s, http.MethodGet, "/api/v1/alerts/rules", "")
+	var rules []models.AlertRule
+	json.NewDecoder(rec.Body).Decode(&rules)
+	if len(rules) != 1 {
+		t.Errorf("Expected 1 rule, got %d", len(rules))
+	}
+
+	rec = do(t, s, http.MethodDelete, "/api/v1/alerts/rules/"+created.ID, "")
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", rec.Code)
+	}
+
+	rec = do(t, s, http.MethodGet, "/api/v1/alerts", "")
+	if rec.Code != http.StatusOK || rec.Body.String() != "[]\n" {
+		t.Errorf("Expected empty alert list, got %d %s", rec.Code, rec.Body.String())
+	}
+}
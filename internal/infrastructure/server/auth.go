@@ -0,0 +1,327 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+)
+
+// sessionCookie carries the signed web UI session
+const sessionCookie = "dashboard_session"
+
+// DefaultRateLimit is the number of requests per minute a token may make when
+// it sets no limit of its own
+const DefaultRateLimit = 120
+
+// LoginRateLimit is the number of login attempts per minute a client address
+// may make, so tokens cannot be guessed at speed
+const LoginRateLimit = 10
+
+// maxLimiterBuckets bounds the buckets a limiter keeps; beyond it the full
+// ones, which a new bucket would equal, are dropped
+const maxLimiterBuckets = 10000
+
+// DefaultSessionTTL is how long a web UI session lasts when no TTL is configured
+const DefaultSessionTTL = 24 * time.Hour
+
+var (
+	errUnauthorized = errors.New("authentication required")
+	errReadOnly     = errors.New("token is read-only")
+	errRateLimited  = errors.New("rate limit exceeded")
+)
+
+// Token is an API token accepted in the Authorization header as a bearer token
+type Token struct {
+	Name   string
+	Secret string
+	// ReadOnly tokens may only make GET and HEAD requests
+	ReadOnly bool
+	// RateLimit is the number of requests per minute; zero uses DefaultRateLimit
+	RateLimit int
+}
+
+// Auth protects the API, /lite and /metrics with static tokens. The web UI
+// exchanges a token for a signed session cookie. The static UI, /healthz and
+// /status stay public.
+type Auth struct {
+	Tokens []Token
+	// PublicRead lets unauthenticated clients make GET requests
+	PublicRead bool
+	// SessionKey signs session cookies; a random key is generated when it is
+	// empty, so sessions end on restart
+	SessionKey []byte
+	SessionTTL time.Duration
+	// Clock tells the time of sessions and rate limits; the system clock is
+	// used when it is nil
+	Clock clock.Clock
+
+	once    sync.Once
+	limiter *rateLimiter[*Token]
+	logins  *rateLimiter[string]
+}
+
+// identity is the authenticated caller of a request
+type identity struct {
+	Name     string `json:"name"`
+	ReadOnly bool   `json:"read_only"`
+	token    *Token
+}
+
+type identityKey struct{}
+
+// requestIdentity returns the authenticated caller, if any
+func requestIdentity(r *http.Request) (identity, bool) {
+	id, ok := r.Context().Value(identityKey{}).(identity)
+	return id, ok
+}
+
+func (a *Auth) init() {
+	a.once.Do(func() {
+		if len(a.SessionKey) == 0 {
+			a.SessionKey = make([]byte, 32)
+			if _, err := rand.Read(a.SessionKey); err != nil {
+				panic(err)
+			}
+		}
+		if a.SessionTTL <= 0 {
+			a.SessionTTL = DefaultSessionTTL
+		}
+		if a.Clock == nil {
+			a.Clock = clock.Real
+		}
+		a.limiter = newRateLimiter[*Token](a.Clock)
+		a.logins = newRateLimiter[string](a.Clock)
+	})
+}
+
+// protected reports whether the path requires authentication
+func protected(path string) bool {
+	return strings.HasPrefix(path, "/api/") || path == "/lite" || path == "/metrics"
+}
+
+// middleware authenticates requests to protected paths, enforces read-only
+// tokens and rate limits each token
+func (a *Auth) middleware(next http.Handler) http.Handler {
+	a.init()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !protected(r.URL.Path) || r.URL.Path == "/api/v1/session" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		readRequest := r.Method == http.MethodGet || r.Method == http.MethodHead
+
+		id, ok := a.authenticate(r)
+		if !ok {
+			if a.PublicRead && readRequest {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("WWW-Authenticate", `Bearer realm="crypto-dashboard"`)
+			writeError(w, http.StatusUnauthorized, errUnauthorized)
+			return
+		}
+		if id.ReadOnly && !readRequest {
+			writeError(w, http.StatusForbidden, errReadOnly)
+			return
+		}
+		if wait := a.limiter.allow(id.token, cmp.Or(id.token.RateLimit, DefaultRateLimit)); wait > 0 {
+			writeRateLimited(w, wait)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), identityKey{}, id)))
+	})
+}
+
+// authenticate reads a bearer token, an access_token query parameter on GET
+// requests for clients that cannot set headers, or a session cookie
+func (a *Auth) authenticate(r *http.Request) (identity, bool) {
+	secret, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found && r.Method == http.MethodGet {
+		secret = r.URL.Query().Get("access_token")
+	}
+	if secret != "" {
+		return a.lookup(strings.TrimSpace(secret))
+	}
+	if cookie, err := r.Cookie(sessionCookie); err == nil {
+		return a.verifySession(cookie.Value, a.Clock.Now())
+	}
+	return identity{}, false
+}
+
+// lookup finds the token with the given secret in constant time per token
+func (a *Auth) lookup(secret string) (identity, bool) {
+	for i := range a.Tokens {
+		t := &a.Tokens[i]
+		if subtle.ConstantTimeCompare([]byte(t.Secret), []byte(secret)) == 1 {
+			return identity{Name: t.Name, ReadOnly: t.ReadOnly, token: t}, true
+		}
+	}
+	return identity{}, false
+}
+
+// signSession returns a cookie value naming the token, valid until expires
+func (a *Auth) signSession(name string, expires time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(name + "|" + strconv.FormatInt(expires.Unix(), 10)))
+	return payload + "." + a.signature(payload)
+}
+
+// verifySession checks the signature and expiry of a session cookie. The
+// token's current permissions apply, so removing it ends its sessions.
+func (a *Auth) verifySession(value string, now time.Time) (identity, bool) {
+	payload, sig, ok := strings.Cut(value, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(a.signature(payload))) {
+		return identity{}, false
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return identity{}, false
+	}
+	name, expiry, ok := strings.Cut(string(raw), "|")
+	unix, err := strconv.ParseInt(expiry, 10, 64)
+	if !ok || err != nil || !now.Before(time.Unix(unix, 0)) {
+		return identity{}, false
+	}
+	for i := range a.Tokens {
+		if a.Tokens[i].Name == name {
+			return identity{Name: name, ReadOnly: a.Tokens[i].ReadOnly, token: &a.Tokens[i]}, true
+		}
+	}
+	return identity{}, false
+}
+
+func (a *Auth) signature(payload string) string {
+	mac := hmac.New(sha256.New, a.SessionKey)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// handleLogin exchanges a token for a session cookie used by the web UI
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Token string `json:"token"`
+	}
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	auth := s.services.Auth
+	auth.init()
+	if wait := auth.logins.allow(clientAddr(r), LoginRateLimit); wait > 0 {
+		writeRateLimited(w, wait)
+		return
+	}
+	id, ok := auth.lookup(strings.TrimSpace(body.Token))
+	if !ok {
+		writeError(w, http.StatusUnauthorized, errUnauthorized)
+		return
+	}
+
+	expires := auth.Clock.Now().Add(auth.SessionTTL)
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    auth.signSession(id.Name, expires),
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+	writeJSON(w, http.StatusOK, map[string]any{"name": id.Name, "read_only": id.ReadOnly, "expires": expires.UTC()})
+}
+
+// handleSession returns the caller of a session or token
+func (s *Server) handleSession(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.services.Auth.authenticate(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, errUnauthorized)
+		return
+	}
+	writeJSON(w, http.StatusOK, id)
+}
+
+// handleLogout clears the session cookie
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: "", Path: "/", MaxAge: -1, HttpOnly: true, SameSite: http.SameSiteStrictMode})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// clientAddr returns the IP address of the client, without its port
+func clientAddr(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// writeRateLimited rejects a request that may be retried after wait
+func writeRateLimited(w http.ResponseWriter, wait time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	writeError(w, http.StatusTooManyRequests, errRateLimited)
+}
+
+// rateLimiter is a token bucket per key, such as an API token or a client
+// address, refilled continuously
+type rateLimiter[K comparable] struct {
+	clock   clock.Clock
+	mu      sync.Mutex
+	buckets map[K]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter[K comparable](c clock.Clock) *rateLimiter[K] {
+	return &rateLimiter[K]{clock: c, buckets: make(map[K]*bucket)}
+}
+
+// allow takes a request from the key's bucket of perMinute requests,
+// returning how long to wait when it is empty
+func (l *rateLimiter[K]) allow(key K, perMinute int) time.Duration {
+	rate := float64(perMinute) / 60
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock.Now()
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= maxLimiterBuckets {
+			l.prune(now)
+		}
+		b = &bucket{tokens: float64(perMinute), last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = min(float64(perMinute), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	}
+	b.tokens--
+	return 0
+}
+
+// prune drops the buckets unused for a minute, which have refilled. The
+// caller must hold the lock.
+func (l *rateLimiter[K]) prune(now time.Time) {
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= time.Minute {
+			delete(l.buckets, key)
+		}
+	}
+}
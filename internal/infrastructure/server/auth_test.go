@@ -0,0 +1,195 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/clock"
+)
+
+const (
+	adminToken  = "admin-0123456789abcdef"
+	readerToken = "reader-0123456789abcdef"
+)
+
+func newAuthServer(auth *Auth) *Server {
+	services := newTestServer().services
+	services.Auth = auth
+	return New(0, services)
+}
+
+func doWith(t *testing.T, s *Server, method, path, body string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func bearer(token string) http.Header {
+	return http.Header{"Authorization": {"Bearer " + token}}
+}
+
+func TestAuth_RequiresToken(t *testing.T) {
+	s := newAuthServer(&Auth{Tokens: []Token{{Name: "admin", Secret: adminToken}}})
+
+	rec := do(t, s, http.MethodGet, "/api/v1/prices", "")
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+		t.Fatalf("Expected 401 with a challenge, got %d", rec.Code)
+	}
+	if rec := doWith(t, s, http.MethodGet, "/api/v1/prices", "", bearer("wrong-0123456789abcdef")); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for an unknown token, got %d", rec.Code)
+	}
+	if rec := doWith(t, s, http.MethodGet, "/api/v1/prices", "", bearer(adminToken)); rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 with a token, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(t, s, http.MethodGet, "/api/v1/prices?access_token="+adminToken, ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 with an access_token parameter, got %d", rec.Code)
+	}
+	if rec := do(t, s, http.MethodGet, "/healthz", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected /healthz to stay public, got %d", rec.Code)
+	}
+}
+
+func TestAuth_ReadOnlyAndPublicRead(t *testing.T) {
+	s := newAuthServer(&Auth{
+		Tokens:     []Token{{Name: "admin", Secret: adminToken}, {Name: "reader", Secret: readerToken, ReadOnly: true}},
+		PublicRead: true,
+	})
+	body := `{"name":"Majors","coins":["bitcoin"]}`
+
+	if rec := do(t, s, http.MethodGet, "/api/v1/prices", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected public reads, got %d", rec.Code)
+	}
+	if rec := do(t, s, http.MethodPost, "/api/v1/watchlists", body); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected anonymous writes to be rejected, got %d", rec.Code)
+	}
+	if rec := doWith(t, s, http.MethodPost, "/api/v1/watchlists", body, bearer(readerToken)); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected read-only token writes to be forbidden, got %d", rec.Code)
+	}
+	if rec := doWith(t, s, http.MethodPost, "/api/v1/watchlists", body, bearer(adminToken)); rec.Code != http.StatusCreated {
+		t.Errorf("Expected 201 for the admin token, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestAuth_RateLimit(t *testing.T) {
+	s := newAuthServer(&Auth{Tokens: []Token{{Name: "admin", Secret: adminToken, RateLimit: 2}, {Name: "reader", Secret: readerToken}}})
+
+	for i := range 2 {
+		if rec := doWith(t, s, http.MethodGet, "/api/v1/prices", "", bearer(adminToken)); rec.Code != http.StatusOK {
+			t.Fatalf("Request %d: expected 200, got %d", i, rec.Code)
+		}
+	}
+	rec := doWith(t, s, http.MethodGet, "/api/v1/prices", "", bearer(adminToken))
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "30" {
+		t.Errorf("Expected 429 retrying after 30s, got %d after %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if rec := doWith(t, s, http.MethodGet, "/api/v1/prices", "", bearer(readerToken)); rec.Code != http.StatusOK {
+		t.Errorf("Expected other tokens to keep their own budget, got %d", rec.Code)
+	}
+}
+
+func TestRateLimiter_Refills(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	l := newRateLimiter[*Token](clk)
+	token := &Token{RateLimit: 60}
+
+	for range 60 {
+		if wait := l.allow(token, token.RateLimit); wait != 0 {
+			t.Fatalf("Expected the full burst to pass, waiting %s", wait)
+		}
+	}
+	if wait := l.allow(token, token.RateLimit); wait != time.Second {
+		t.Errorf("Expected to wait 1s, got %s", wait)
+	}
+	clk.Advance(time.Second)
+	if wait := l.allow(token, token.RateLimit); wait != 0 {
+		t.Errorf("Expected a request after the refill, waiting %s", wait)
+	}
+}
+
+func TestAuth_LoginRateLimit(t *testing.T) {
+	s := newAuthServer(&Auth{Tokens: []Token{{Name: "admin", Secret: adminToken}}})
+	login := func(remote, token string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/session", strings.NewReader(`{"token":"`+token+`"}`))
+		req.RemoteAddr = remote
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for n := range LoginRateLimit {
+		if code := login("203.0.113.7:4000", fmt.Sprintf("guess-%d", n)); code != http.StatusUnauthorized {
+			t.Fatalf("Expected 401 for a wrong token, got %d", code)
+		}
+	}
+	// Another port of the same address shares the limit; the right token does not bypass it
+	if code := login("203.0.113.7:4001", adminToken); code != http.StatusTooManyRequests {
+		t.Errorf("Expected 429 after %d attempts, got %d", LoginRateLimit, code)
+	}
+	if code := login("198.51.100.2:4000", adminToken); code != http.StatusOK {
+		t.Errorf("Expected other clients to log in, got %d", code)
+	}
+}
+
+func TestAuth_SessionCookie(t *testing.T) {
+	s := newAuthServer(&Auth{Tokens: []Token{{Name: "reader", Secret: readerToken, ReadOnly: true}}})
+
+	if rec := do(t, s, http.MethodPost, "/api/v1/session", `{"token":"nope"}`); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected 401 for a bad token, got %d", rec.Code)
+	}
+	rec := do(t, s, http.MethodPost, "/api/v1/session", `{"token":"`+readerToken+`"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || !cookies[0].HttpOnly || cookies[0].SameSite != http.SameSiteStrictMode {
+		t.Fatalf("Expected an HttpOnly strict session cookie, got %+v", cookies)
+	}
+	session := http.Header{"Cookie": {cookies[0].Name + "=" + cookies[0].Value}}
+
+	rec = doWith(t, s, http.MethodGet, "/api/v1/session", "", session)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"name":"reader","read_only":true`) {
+		t.Errorf("Unexpected session: %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := doWith(t, s, http.MethodGet, "/api/v1/prices", "", session); rec.Code != http.StatusOK {
+		t.Errorf("Expected the cookie to authenticate, got %d", rec.Code)
+	}
+	if rec := doWith(t, s, http.MethodPost, "/api/v1/watchlists", `{"name":"x","coins":["bitcoin"]}`, session); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected the cookie to keep the read-only scope, got %d", rec.Code)
+	}
+
+	tampered := http.Header{"Cookie": {cookies[0].Name + "=x" + cookies[0].Value}}
+	if rec := doWith(t, s, http.MethodGet, "/api/v1/prices", "", tampered); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected a tampered cookie to be rejected, got %d", rec.Code)
+	}
+
+	rec = doWith(t, s, http.MethodDelete, "/api/v1/session", "", session)
+	if rec.Code != http.StatusNoContent || rec.Result().Cookies()[0].MaxAge >= 0 {
+		t.Errorf("Expected logout to clear the cookie, got %d", rec.Code)
+	}
+}
+
+func TestAuth_VerifySessionExpires(t *testing.T) {
+	a := &Auth{Tokens: []Token{{Name: "admin", Secret: adminToken}}, SessionKey: []byte("key")}
+	now := time.Now()
+	value := a.signSession("admin", now.Add(time.Hour))
+
+	if _, ok := a.verifySession(value, now); !ok {
+		t.Error("Expected a fresh session to verify")
+	}
+	if _, ok := a.verifySession(value, now.Add(2*time.Hour)); ok {
+		t.Error("Expected an expired session to be rejected")
+	}
+	a.Tokens = nil
+	if _, ok := a.verifySession(value, now); ok {
+		t.Error("Expected a session of a removed token to be rejected")
+	}
+}
@@ -0,0 +1,22 @@
+package server
+
+import (
+	"net/http"
+
+	"crypto-dashboard/internal/application/backtest"
+)
+
+func (s *Server) handleRebalancingBacktest(w http.ResponseWriter, r *http.Request) {
+	var in backtest.RebalancingInput
+	if err := decodeJSON(r, &in); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := s.services.Backtest.Rebalancing(in)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/backtest"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestHandleRebalancingBacktest(t *testing.T) {
+	s := newTestServer()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 48; i++ {
+		at := start.Add(time.Duration(i) * time.Hour)
+		s.services.Candles.SaveCandle(time.Hour, models.NewCandle("bitcoin", 100+float64(i%5), at, time.Hour))
+		s.services.Candles.SaveCandle(time.Hour, models.NewCandle("ethereum", 10+float64(i%3), at, time.Hour))
+	}
+
+	rec := do(t, s, http.MethodPost, "/api/v1/tools/backtest/rebalancing",
+		`{"allocation":{"bitcoin":60,"ethereum":40},"initial_value":1000,"frequency":"daily"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var result backtest.RebalancingResult
+	json.NewDecoder(rec.Body).Decode(&result)
+	if len(result.BuyAndHold.Equity) != 48 || result.Rebalancing.Metrics.Rebalances != 2 {
+		t.Errorf("Expected 48 points and 2 daily rebalances, got %d and %d",
+			len(result.BuyAndHold.Equity), result.Rebalancing.Metrics.Rebalances)
+	}
+
+	rec = do(t, s, http.MethodPost, "/api/v1/tools/backtest/rebalancing", `{"allocation":{"solana":1},"initial_value":1000}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 without history, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"crypto-dashboard/internal/application/chart"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// chartResponse is a chart configuration with the URLs that resolve it: the
+// dashboard permalink and the API paths a renderer draws it from
+type chartResponse struct {
+	models.ChartConfig
+	Links chartLinks `json:"links"`
+}
+
+type chartLinks struct {
+	Permalink  string `json:"permalink"`
+	History    string `json:"history"`
+	Indicators string `json:"indicators,omitempty"`
+}
+
+func newChartResponse(c models.ChartConfig) chartResponse {
+	return chartResponse{ChartConfig: c, Links: chartLinks{
+		Permalink:  "/?chart=" + url.QueryEscape(c.ID),
+		History:    c.HistoryPath(),
+		Indicators: c.IndicatorsPath(),
+	}}
+}
+
+func (s *Server) handleListCharts(w http.ResponseWriter, r *http.Request) {
+	charts, err := s.services.Charts.List(sessionOwner(r))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	resp := make([]chartResponse, len(charts))
+	for i, c := range charts {
+		resp[i] = newChartResponse(c)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleCreateChart(w http.ResponseWriter, r *http.Request) {
+	var c models.ChartConfig
+	if err := decodeJSON(r, &c); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	c.Owner = sessionOwner(r)
+
+	created, err := s.services.Charts.Create(c)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, newChartResponse(created))
+}
+
+// handleGetChart resolves a permalink. Charts are shared by ID, so any session can read them.
+func (s *Server) handleGetChart(w http.ResponseWriter, r *http.Request) {
+	c, err := s.services.Charts.Get(r.PathValue("id"))
+	if errors.Is(err, chart.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newChartResponse(c))
+}
+
+func (s *Server) handleUpdateChart(w http.ResponseWriter, r *http.Request) {
+	current, ok := s.ownedChart(w, r)
+	if !ok {
+		return
+	}
+	var c models.ChartConfig
+	if err := decodeJSON(r, &c); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	updated, err := s.services.Charts.Update(current.ID, c)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newChartResponse(updated))
+}
+
+func (s *Server) handleDeleteChart(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.ownedChart(w, r)
+	if !ok {
+		return
+	}
+	if err := s.services.Charts.Delete(c.ID); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ownedChart loads the chart of the path for a change, writing a 404 when it
+// does not exist and a 403 when it belongs to another session
+func (s *Server) ownedChart(w http.ResponseWriter, r *http.Request) (models.ChartConfig, bool) {
+	c, err := s.services.Charts.Get(r.PathValue("id"))
+	if errors.Is(err, chart.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return models.ChartConfig{}, false
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return models.ChartConfig{}, false
+	}
+	if c.Owner != sessionOwner(r) {
+		writeError(w, http.StatusForbidden, errors.New("chart belongs to another session"))
+		return models.ChartConfig{}, false
+	}
+	return c, true
+}
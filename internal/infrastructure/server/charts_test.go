@@ -0,0 +1,69 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestChartEndpoints(t *testing.T) {
+	s := newTestServer()
+
+	rec := doAs(t, s, "alice", http.MethodPost, "/api/v1/charts", `{"name":"BTC cycle","crypto_id":"bitcoin","range":"max","indicators":{"sma":200}}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var created chartResponse
+	json.NewDecoder(rec.Body).Decode(&created)
+	if created.Scale != "log" || created.Links.Permalink != "/?chart="+created.ID ||
+		created.Links.History != "/api/v1/coins/bitcoin/history?range=max" || created.Links.Indicators != "/api/v1/coins/bitcoin/indicators?sma=200" {
+		t.Errorf("Unexpected chart: %+v", created)
+	}
+	if rec := doAs(t, s, "alice", http.MethodPost, "/api/v1/charts", `{"name":"bad","crypto_id":"bitcoin","scale":"sqrt"}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 for an unknown scale, got %d", rec.Code)
+	}
+
+	// Permalinks resolve for every session, but only the owner may change them
+	path := "/api/v1/charts/" + created.ID
+	rec = doAs(t, s, "bob", http.MethodGet, path, "")
+	if rec.Code != http.StatusOK || containsOwner(rec.Body.Bytes()) {
+		t.Errorf("Expected the shared chart without its owner, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := doAs(t, s, "bob", http.MethodPut, path, `{"name":"mine","crypto_id":"dogecoin"}`); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for another session, got %d", rec.Code)
+	}
+	if rec := doAs(t, s, "bob", http.MethodDelete, path, ""); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for another session, got %d", rec.Code)
+	}
+
+	rec = doAs(t, s, "alice", http.MethodPut, path, `{"name":"BTC cycle","crypto_id":"bitcoin"}`)
+	var updated chartResponse
+	json.NewDecoder(rec.Body).Decode(&updated)
+	if rec.Code != http.StatusOK || updated.ID != created.ID || updated.Range != "recent" || updated.Links.Indicators != "" {
+		t.Errorf("Expected the settings replaced under the same ID, got %d: %+v", rec.Code, updated)
+	}
+
+	var listed []chartResponse
+	json.NewDecoder(doAs(t, s, "alice", http.MethodGet, "/api/v1/charts", "").Body).Decode(&listed)
+	if len(listed) != 1 {
+		t.Errorf("Expected alice's chart, got %+v", listed)
+	}
+	json.NewDecoder(doAs(t, s, "bob", http.MethodGet, "/api/v1/charts", "").Body).Decode(&listed)
+	if len(listed) != 0 {
+		t.Errorf("Expected no charts for bob, got %+v", listed)
+	}
+
+	if rec := doAs(t, s, "alice", http.MethodDelete, path, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", rec.Code)
+	}
+	if rec := do(t, s, http.MethodGet, path, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 after a delete, got %d", rec.Code)
+	}
+}
+
+func containsOwner(body []byte) bool {
+	var fields map[string]any
+	json.Unmarshal(body, &fields)
+	_, ok := fields["owner"]
+	return ok
+}
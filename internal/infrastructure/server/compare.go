@@ -0,0 +1,38 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"crypto-dashboard/internal/application/compare"
+	"crypto-dashboard/internal/application/format"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// handleCompare prices a symbol on every configured source and reports the spread
+func (s *Server) handleCompare(w http.ResponseWriter, r *http.Request) {
+	currency := s.services.Poller.Currency()
+	if v := r.URL.Query().Get("currency"); v != "" {
+		var err error
+		if currency, err = models.ParseCurrency(v); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	comparison, err := s.services.Compare.Compare(r.Context(), r.PathValue("symbol"), currency)
+	if errors.Is(err, compare.ErrNoQuotes) {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	hints := format.NewHints(comparison.Currency)
+	hints.AddPrice(comparison.Symbol, comparison.Median)
+	writeJSON(w, http.StatusOK, struct {
+		models.PriceComparison
+		Format format.Hints `json:"format"`
+	}{comparison, hints})
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/compare"
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubSource struct {
+	name  string
+	price string
+}
+
+func (s stubSource) Name() string { return s.name }
+
+func (s stubSource) Quote(ctx context.Context, symbol string, currency models.Currency) (models.SourceQuote, error) {
+	if s.price == "" {
+		return models.SourceQuote{}, errors.New("unavailable")
+	}
+	return models.SourceQuote{Pair: symbol + "-" + string(currency), Price: decimal.RequireFromString(s.price)}, nil
+}
+
+func TestCompare_NotServedWithoutService(t *testing.T) {
+	s := newTestServer()
+	if rec := do(t, s, http.MethodGet, "/api/v1/compare/BTC", ""); rec.Code == http.StatusOK {
+		t.Errorf("Expected no compare endpoint without a service, got %d", rec.Code)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	services := newTestServer().services
+	services.Compare = compare.NewService(time.Second,
+		stubSource{name: "a", price: "100"},
+		stubSource{name: "b", price: "101"},
+		stubSource{name: "c"},
+	)
+	s := New(0, services)
+
+	rec := do(t, s, http.MethodGet, "/api/v1/compare/btc?currency=EUR", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var got models.PriceComparison
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Symbol != "BTC" || got.Currency != models.EUR || len(got.Quotes) != 3 {
+		t.Errorf("Unexpected comparison: %+v", got)
+	}
+	if !got.Spread.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Expected a spread of 1, got %s", got.Spread)
+	}
+	if got.Quotes[2].Error != "unavailable" {
+		t.Errorf("Expected the failed source to be listed, got %+v", got.Quotes[2])
+	}
+
+	if rec := do(t, s, http.MethodGet, "/api/v1/compare/btc?currency=x", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid currency, got %d", rec.Code)
+	}
+}
+
+func TestCompare_NoQuotes(t *testing.T) {
+	services := newTestServer().services
+	services.Compare = compare.NewService(time.Second, stubSource{name: "a"})
+	s := New(0, services)
+
+	if rec := do(t, s, http.MethodGet, "/api/v1/compare/btc", ""); rec.Code != http.StatusBadGateway {
+		t.Errorf("Expected 502 when no source answers, got %d", rec.Code)
+	}
+}
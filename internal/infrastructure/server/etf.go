@@ -0,0 +1,52 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"crypto-dashboard/internal/application/etf"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// maxETFDays caps the length of a flow series query
+const maxETFDays = 365
+
+// handleETFSummaries summarizes the recent spot ETF flows of every tracked asset
+func (s *Server) handleETFSummaries(w http.ResponseWriter, r *http.Request) {
+	summaries, err := s.services.ETF.Summaries()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if summaries == nil {
+		summaries = []models.ETFFlowSummary{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"summaries": summaries})
+}
+
+// handleETFFlows returns the daily flow series of an asset over the last days
+func (s *Server) handleETFFlows(w http.ResponseWriter, r *http.Request) {
+	days, err := intParam(r, "days", etf.SummaryDays)
+	if err != nil || days > maxETFDays {
+		writeError(w, http.StatusBadRequest, errInvalidParam("days"))
+		return
+	}
+	flows, err := s.services.ETF.Flows(r.PathValue("asset"), days)
+	if errors.Is(err, etf.ErrUnknownAsset) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if flows == nil {
+		flows = []models.ETFFlow{}
+	}
+	resp := map[string]any{"flows": flows}
+	if len(flows) > 0 {
+		summary, _ := models.SummarizeETFFlows(flows[0].Asset, flows)
+		resp["summary"] = summary
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
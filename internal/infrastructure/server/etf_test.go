@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/etf"
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/repository/memory"
+)
+
+type stubFlowSource []models.ETFFlow
+
+func (s stubFlowSource) Flows(ctx context.Context, asset string) ([]models.ETFFlow, error) {
+	return s, nil
+}
+
+func TestETFFlows(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	service := etf.NewService(memory.NewETFFlowRepository(), time.Hour, map[string]etf.Source{
+		"BTC": stubFlowSource{{Date: today.AddDate(0, 0, -1), NetFlow: 100e6}, {Date: today, NetFlow: 50e6}},
+		"ETH": stubFlowSource{},
+	})
+	if err := service.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	services := newTestServer().services
+	services.ETF = service
+	s := New(0, services)
+
+	rec := do(t, s, http.MethodGet, "/api/v1/etf/flows", "")
+	var summaries struct {
+		Summaries []models.ETFFlowSummary `json:"summaries"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &summaries); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("Expected summaries, got %d: %s", rec.Code, rec.Body)
+	}
+	if len(summaries.Summaries) != 1 || summaries.Summaries[0].Week != 150e6 || summaries.Summaries[0].Streak != 2 {
+		t.Errorf("Unexpected summaries: %+v", summaries.Summaries)
+	}
+
+	rec = do(t, s, http.MethodGet, "/api/v1/etf/flows/btc?days=1", "")
+	var series struct {
+		Flows   []models.ETFFlow       `json:"flows"`
+		Summary *models.ETFFlowSummary `json:"summary"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &series); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("Expected a series, got %d: %s", rec.Code, rec.Body)
+	}
+	if len(series.Flows) != 1 || series.Summary == nil || series.Summary.Latest != 50e6 {
+		t.Errorf("Unexpected series: %+v", series)
+	}
+
+	if rec := do(t, s, http.MethodGet, "/api/v1/etf/flows/sol", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an untracked asset, got %d", rec.Code)
+	}
+	if rec := do(t, s, http.MethodGet, "/api/v1/etf/flows/btc?days=1000", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for too many days, got %d", rec.Code)
+	}
+	if rec := do(t, newTestServer(), http.MethodGet, "/api/v1/etf/flows", ""); rec.Code == http.StatusOK {
+		t.Errorf("Expected no ETF endpoint without a service, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"crypto-dashboard/internal/application/calendar"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// feedWindow is how far back the ICS feed reaches; calendar clients keep past
+// events they already synced
+const feedWindow = 90 * 24 * time.Hour
+
+func (s *Server) handleListEvents(w http.ResponseWriter, r *http.Request) {
+	from, err := timeParam(r, "from")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	to, err := timeParam(r, "to")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	events, err := s.services.Calendar.Events(sessionOwner(r), from, to)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, events)
+}
+
+func (s *Server) handleCreateEvent(w http.ResponseWriter, r *http.Request) {
+	var event models.Event
+	if err := decodeJSON(r, &event); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	event.Owner = sessionOwner(r)
+
+	created, err := s.services.Calendar.Create(event)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) handleDeleteEvent(w http.ResponseWriter, r *http.Request) {
+	event, err := s.services.Calendar.Get(r.PathValue("id"))
+	if err == nil && event.Owner != sessionOwner(r) {
+		err = calendar.ErrNotFound
+	}
+	if errors.Is(err, calendar.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err == nil {
+		err = s.services.Calendar.Delete(event.ID)
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleCalendarFeed serves the events as an ICS feed. Calendar applications
+// cannot send the session header, so the feed URL carries the session instead.
+func (s *Server) handleCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	owner := strings.TrimSpace(r.URL.Query().Get("session"))
+	if owner == "" {
+		owner = sessionOwner(r)
+	}
+
+	events, err := s.services.Calendar.Events(owner, s.services.Clock.Now().Add(-feedWindow), time.Time{})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="crypto-dashboard.ics"`)
+	calendar.WriteICS(w, "Crypto dashboard", events)
+}
+
+// timeParam parses an optional RFC 3339 timestamp or YYYY-MM-DD date query parameter
+func timeParam(r *http.Request, name string) (time.Time, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errInvalidParam(name)
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestEventEndpoints(t *testing.T) {
+	s := newTestServer()
+	start := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+
+	rec := do(t, s, http.MethodPost, "/api/v1/events", `{"kind":"unlock","title":"ARB unlock","crypto_id":"arbitrum","start":"`+start+`"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = doAs(t, s, "alice", http.MethodPost, "/api/v1/events", `{"kind":"reminder","title":"Rebalance","start":"`+start+`","remind_minutes":30}`)
+	var reminder models.Event
+	json.NewDecoder(rec.Body).Decode(&reminder)
+
+	if rec := do(t, s, http.MethodPost, "/api/v1/events", `{"title":""}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 for an invalid event, got %d", rec.Code)
+	}
+
+	var events []models.Event
+	json.NewDecoder(doAs(t, s, "alice", http.MethodGet, "/api/v1/events", "").Body).Decode(&events)
+	if len(events) != 2 {
+		t.Errorf("Expected alice to see the shared and their own event, got %+v", events)
+	}
+	json.NewDecoder(doAs(t, s, "bob", http.MethodGet, "/api/v1/events", "").Body).Decode(&events)
+	if len(events) != 1 || events[0].Title != "ARB unlock" {
+		t.Errorf("Expected bob to only see the shared event, got %+v", events)
+	}
+	if rec := do(t, s, http.MethodGet, "/api/v1/events?from=tomorrow", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid from, got %d", rec.Code)
+	}
+
+	rec = do(t, s, http.MethodGet, "/api/v1/calendar.ics?session=alice", "")
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/calendar") {
+		t.Fatalf("Expected an ICS feed, got %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	body := rec.Body.String()
+	if strings.Count(body, "BEGIN:VEVENT") != 2 || !strings.Contains(body, "TRIGGER:-PT30M") {
+		t.Errorf("Unexpected feed:\n%s", body)
+	}
+
+	if rec := doAs(t, s, "bob", http.MethodDelete, "/api/v1/events/"+reminder.ID, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 deleting another session's event, got %d", rec.Code)
+	}
+	if rec := doAs(t, s, "alice", http.MethodDelete, "/api/v1/events/"+reminder.ID, ""); rec.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"crypto-dashboard/internal/infrastructure/export"
+)
+
+// exportRequest holds the format and columns shared by every export endpoint
+type exportRequest struct {
+	format  export.Format
+	columns []string
+}
+
+func parseExportRequest(r *http.Request) (exportRequest, error) {
+	format, err := export.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		return exportRequest{}, err
+	}
+	var columns []string
+	if v := r.URL.Query().Get("columns"); v != "" {
+		columns = strings.Split(v, ",")
+	}
+	return exportRequest{format: format, columns: columns}, nil
+}
+
+// writeExport selects the requested columns and streams the records as a file download
+func writeExport[T any](w http.ResponseWriter, req exportRequest, name string, available []export.Column[T], records []T) {
+	columns, err := export.SelectColumns(available, req.columns)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	w.Header().Set("Content-Type", req.format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+"."+string(req.format)))
+	export.Write(w, req.format, columns, records)
+}
+
+// handleExportPrices exports the tracked coins, or the coins listed in the ids query parameter
+func (s *Server) handleExportPrices(w http.ResponseWriter, r *http.Request) {
+	req, err := parseExportRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	prices := s.services.Poller.Snapshot()
+	if ids := r.URL.Query().Get("ids"); ids != "" {
+		prices, err = s.services.Poller.Prices(strings.Split(ids, ","))
+		if err != nil && len(prices) == 0 {
+			writeUpstreamError(w, err)
+			return
+		}
+	}
+	writeExport(w, req, "prices", export.PriceColumns, prices)
+}
+
+// handleExportCandles exports the stored candles of a coin
+func (s *Server) handleExportCandles(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	req, err := parseExportRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	limit, err := intParam(r, "limit", defaultCandleLimit)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	candles, err := s.services.Candles.Candles(id, s.services.CandleInterval, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeExport(w, req, id+"-candles", export.CandleColumns, candles)
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestHandleExportPrices(t *testing.T) {
+	s := newTestServer()
+	s.services.Poller.PollOnce()
+
+	rec := do(t, s, http.MethodGet, "/api/v1/export/prices?format=csv&columns=id,price", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/csv" {
+		t.Errorf("Expected text/csv, got %s", ct)
+	}
+	if !strings.Contains(rec.Header().Get("Content-Disposition"), `filename="prices.csv"`) {
+		t.Errorf("Unexpected disposition %q", rec.Header().Get("Content-Disposition"))
+	}
+	if want := "id,price\nbitcoin,55000\n"; rec.Body.String() != want {
+		t.Errorf("Expected %q, got %q", want, rec.Body.String())
+	}
+
+	for _, query := range []string{"format=xlsx", "columns=id,bogus"} {
+		if rec := do(t, s, http.MethodGet, "/api/v1/export/prices?"+query, ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", query, rec.Code)
+		}
+	}
+}
+
+func TestHandleExportCandles(t *testing.T) {
+	s := newTestServer()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, price := range []float64{100, 110} {
+		s.services.Candles.SaveCandle(time.Hour, models.NewCandle("bitcoin", price, start.Add(time.Duration(i)*time.Hour), time.Hour))
+	}
+
+	rec := do(t, s, http.MethodGet, "/api/v1/export/coins/bitcoin/candles?format=json&columns=open_time,close", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var rows []map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&rows); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(rows) != 2 || rows[1]["close"] != 110.0 || rows[0]["open_time"] != "2024-01-01T00:00:00Z" {
+		t.Errorf("Unexpected rows: %v", rows)
+	}
+}
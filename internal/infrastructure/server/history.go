@@ -0,0 +1,197 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/analytics"
+	"crypto-dashboard/internal/application/format"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// defaultCandleLimit is the number of candles returned when no limit is given
+const defaultCandleLimit = 200
+
+// handleHistory returns the recent points and candles of a coin together with
+// the incidents overlapping them, so charts can shade unreliable ranges.
+// With range=max it returns the daily series since the coin's genesis instead.
+// A transform other than raw applies to the prices; candles are then reduced
+// to their transformed closes, as returns are defined on one price per period.
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	transform, err := analytics.ParseTransform(r.URL.Query().Get("transform"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	switch r.URL.Query().Get("range") {
+	case "":
+	case "max":
+		s.handleInceptionHistory(w, r, id, transform)
+		return
+	default:
+		writeError(w, http.StatusBadRequest, errInvalidParam("range"))
+		return
+	}
+
+	limit, err := intParam(r, "limit", defaultCandleLimit)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	candles, err := s.services.Candles.Candles(id, s.services.CandleInterval, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	points := s.services.Poller.History(id)
+
+	var from time.Time
+	if len(candles) > 0 {
+		from = candles[0].OpenTime
+	}
+	if len(points) > 0 && (from.IsZero() || points[0].Time.Before(from)) {
+		from = points[0].Time
+	}
+	incidents := []models.Incident{}
+	if !from.IsZero() {
+		overlapping, err := s.services.Incidents.Incidents(id, from, time.Time{})
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		incidents = append(incidents, overlapping...)
+	}
+
+	// The series grow with every poll, so they are only as fresh as the coin's latest price
+	tier := TierLive
+	if latest, ok := s.services.Poller.Latest(id); ok && latest.Stale {
+		tier = TierStale
+	}
+	if len(points) == 0 && len(candles) == 0 {
+		tier = TierUnavailable
+	}
+	if !s.acceptTier(w, EndpointHistory, tier) {
+		return
+	}
+
+	resp := map[string]any{
+		"id":        id,
+		"currency":  s.services.Poller.Currency(),
+		"interval":  s.services.CandleInterval.String(),
+		"points":    points,
+		"candles":   candles,
+		"incidents": incidents,
+		"meta":      Meta{Tier: tier},
+	}
+	if transform != analytics.TransformRaw {
+		closes := make([]models.PricePoint, len(candles))
+		for i, c := range candles {
+			closes[i] = models.PricePoint{Price: c.Close, Time: c.CloseTime}
+		}
+		if resp["points"], err = transformPoints(transform, points); err == nil {
+			resp["candles"], err = transformPoints(transform, closes)
+		}
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		resp["transform"] = transform
+	} else {
+		var latest float64
+		if len(candles) > 0 {
+			latest = candles[len(candles)-1].Close
+		}
+		if len(points) > 0 {
+			latest = points[len(points)-1].Price
+		}
+		resp["format"] = priceHints(s.services.Poller.Currency(), id, latest)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleInceptionHistory returns one price per day since the coin's genesis
+// with the bounds of the series, so charts can size a log-scale axis
+func (s *Server) handleInceptionHistory(w http.ResponseWriter, r *http.Request, id string, transform analytics.Transform) {
+	if s.services.Inception == nil {
+		writeError(w, http.StatusNotFound, errors.New("since-inception history is not available"))
+		return
+	}
+	// Unknown coins would cost years of range requests against the provider's quota
+	if !s.knownCoin(id) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%s is neither tracked nor in the coin list", id))
+		return
+	}
+	currency := s.services.Poller.Currency()
+	points, err := s.services.Inception.Series(id, currency)
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	if points, err = transformPoints(transform, points); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	resp := map[string]any{
+		"id":        id,
+		"currency":  currency,
+		"range":     "max",
+		"transform": transform,
+		"interval":  (24 * time.Hour).String(),
+		"points":    points,
+	}
+	if len(points) > 0 {
+		low, high := points[0].Price, points[0].Price
+		for _, p := range points {
+			low, high = min(low, p.Price), max(high, p.Price)
+		}
+		resp["from"], resp["to"] = points[0].Time, points[len(points)-1].Time
+		resp["min"], resp["max"] = low, high
+		if transform == analytics.TransformRaw {
+			resp["format"] = priceHints(currency, id, points[len(points)-1].Price)
+		}
+	} else {
+		resp["points"] = []models.PricePoint{}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// knownCoin reports whether the coin is tracked or in the coin catalog
+func (s *Server) knownCoin(id string) bool {
+	if slices.Contains(s.services.Poller.Coins(), id) {
+		return true
+	}
+	_, ok := s.services.Coins.Symbol(id)
+	return ok
+}
+
+// transformPoints applies a transform to the prices of a series
+func transformPoints(transform analytics.Transform, points []models.PricePoint) ([]models.PricePoint, error) {
+	prices := make([]float64, len(points))
+	for i, p := range points {
+		prices[i] = p.Price
+	}
+	values, err := transform.Apply(prices)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.PricePoint, len(points))
+	for i, p := range points {
+		out[i] = models.PricePoint{Price: values[i], Time: p.Time}
+	}
+	return out, nil
+}
+
+// priceHints returns the formatting hints of a series, sized for its latest price
+func priceHints(currency models.Currency, id string, latest float64) format.Hints {
+	hints := format.NewHints(currency)
+	hints.AddPrice(id, decimal.NewFromFloat(latest))
+	return hints
+}
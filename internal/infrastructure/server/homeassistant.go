@@ -0,0 +1,146 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// haDevice groups every sensor under one Home Assistant device
+var haDevice = map[string]any{
+	"identifiers":  []string{"crypto_dashboard"},
+	"name":         "Crypto Dashboard",
+	"manufacturer": "crypto-dashboard",
+	"model":        "Price poller",
+}
+
+// haSensor follows the field names of Home Assistant sensor discovery
+// payloads, with the current state and attributes inlined
+type haSensor struct {
+	UniqueID    string         `json:"unique_id"`
+	Name        string         `json:"name"`
+	State       string         `json:"state"`
+	Unit        string         `json:"unit_of_measurement"`
+	DeviceClass string         `json:"device_class"`
+	Icon        string         `json:"icon"`
+	Attributes  map[string]any `json:"attributes"`
+}
+
+// handleHASensors lists a price sensor per tracked coin and, when holdings
+// are configured, portfolio sensors. A single Home Assistant REST or MQTT
+// bridge can create every sensor from it instead of one YAML entry per coin.
+// In privacy mode the portfolio sensors only report percentages.
+func (s *Server) handleHASensors(w http.ResponseWriter, r *http.Request) {
+	unit := strings.ToUpper(string(s.services.Poller.Currency()))
+	sensors := []haSensor{}
+
+	for _, p := range s.services.Poller.Snapshot() {
+		name := p.Name
+		if name == "" {
+			name = p.ID
+		}
+		attrs := map[string]any{
+			"crypto_id":        p.ID,
+			"symbol":           strings.ToUpper(p.Symbol),
+			"price_change_24h": p.PriceChange24h,
+			"stale":            p.Stale,
+		}
+		if p.StaleSince != nil {
+			attrs["stale_since"] = p.StaleSince
+		}
+		sensors = append(sensors, haSensor{
+			UniqueID:    "crypto_dashboard_" + haID(p.ID) + "_price",
+			Name:        name + " price",
+			State:       p.CurrentPrice.String(),
+			Unit:        unit,
+			DeviceClass: "monetary",
+			Icon:        "mdi:currency-btc",
+			Attributes:  attrs,
+		})
+	}
+
+	if len(s.services.Holdings) > 0 && s.services.Themes.Private(pageOwner(r)) {
+		sensors = append(sensors, s.privateHASensors()...)
+	} else if len(s.services.Holdings) > 0 {
+		var total, cost decimal.Decimal
+		coins := map[string]string{}
+		for _, h := range s.pricedHoldings() {
+			total, cost = total.Add(h.Value()), cost.Add(h.CostBasis)
+			coins[h.CryptoID] = h.Value().StringFixed(2)
+		}
+		sensors = append(sensors,
+			haSensor{
+				UniqueID:    "crypto_dashboard_portfolio_value",
+				Name:        "Portfolio value",
+				State:       total.StringFixed(2),
+				Unit:        unit,
+				DeviceClass: "monetary",
+				Icon:        "mdi:wallet",
+				Attributes:  map[string]any{"cost_basis": cost.StringFixed(2), "holdings": coins},
+			},
+			haSensor{
+				UniqueID:    "crypto_dashboard_portfolio_unrealized",
+				Name:        "Portfolio unrealized P&L",
+				State:       total.Sub(cost).StringFixed(2),
+				Unit:        unit,
+				DeviceClass: "monetary",
+				Icon:        "mdi:chart-line",
+				Attributes:  map[string]any{},
+			},
+		)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"device": haDevice, "sensors": sensors})
+}
+
+// privateHASensors are the portfolio sensors of privacy mode: the value is
+// hidden, holdings are shares of the portfolio and the unrealized P&L is a
+// percentage of the cost basis
+func (s *Server) privateHASensors() []haSensor {
+	holdings := s.pricedHoldings()
+	var total, cost decimal.Decimal
+	for _, h := range holdings {
+		total, cost = total.Add(h.Value()), cost.Add(h.CostBasis)
+	}
+	shares := map[string]string{}
+	for _, h := range holdings {
+		shares[h.CryptoID] = haPercent(h.Value(), total)
+	}
+	return []haSensor{
+		{
+			UniqueID:   "crypto_dashboard_portfolio_value",
+			Name:       "Portfolio value",
+			State:      hiddenAmount,
+			Icon:       "mdi:wallet",
+			Attributes: map[string]any{"holdings": shares},
+		},
+		{
+			UniqueID:   "crypto_dashboard_portfolio_unrealized",
+			Name:       "Portfolio unrealized P&L",
+			State:      haPercent(total.Sub(cost), cost),
+			Unit:       "%",
+			Icon:       "mdi:chart-line",
+			Attributes: map[string]any{},
+		},
+	}
+}
+
+// haPercent formats part as a percentage of whole, or Home Assistant's
+// unknown state when whole is not positive
+func haPercent(part, whole decimal.Decimal) string {
+	if !whole.IsPositive() {
+		return "unknown"
+	}
+	return part.Div(whole).Mul(decimal.NewFromInt(100)).StringFixed(2)
+}
+
+// haID turns a coin ID into a Home Assistant object ID, e.g. usd-coin to usd_coin
+func haID(id string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(id))
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/infrastructure/export"
+)
+
+func TestHandleHASensors(t *testing.T) {
+	s := newTestServer()
+	s.services.Poller.PollOnce()
+
+	var body struct {
+		Device  map[string]any `json:"device"`
+		Sensors []haSensor     `json:"sensors"`
+	}
+	rec := do(t, s, http.MethodGet, "/api/ha/sensors", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if body.Device["name"] != "Crypto Dashboard" || len(body.Sensors) != 1 {
+		t.Fatalf("Expected the device and one price sensor, got %+v", body)
+	}
+	price := body.Sensors[0]
+	if price.UniqueID != "crypto_dashboard_bitcoin_price" || price.State != "55000" || price.Unit != "USD" || price.DeviceClass != "monetary" {
+		t.Errorf("Unexpected price sensor: %+v", price)
+	}
+
+	s.services.Holdings = []export.Holding{
+		{CryptoID: "bitcoin", Quantity: decimal.RequireFromString("0.5"), CostBasis: decimal.NewFromInt(20000)},
+	}
+	rec = do(t, s, http.MethodGet, "/api/ha/sensors", "")
+	json.NewDecoder(rec.Body).Decode(&body)
+	if len(body.Sensors) != 3 {
+		t.Fatalf("Expected portfolio sensors with holdings, got %d sensors", len(body.Sensors))
+	}
+	if value := body.Sensors[1]; value.UniqueID != "crypto_dashboard_portfolio_value" || value.State != "27500.00" {
+		t.Errorf("Unexpected portfolio value sensor: %+v", value)
+	}
+	if pnl := body.Sensors[2]; pnl.State != "7500.00" {
+		t.Errorf("Expected an unrealized P&L of 7500.00, got %s", pnl.State)
+	}
+}
+
+func TestHandleHASensors_PrivacyMode(t *testing.T) {
+	s := newTestServer()
+	s.services.Poller.PollOnce()
+	s.services.Holdings = []export.Holding{
+		{CryptoID: "bitcoin", Quantity: decimal.RequireFromString("0.5"), CostBasis: decimal.NewFromInt(20000)},
+	}
+	s.services.Themes.SetPrivacy("alice", true)
+
+	var body struct {
+		Sensors []haSensor `json:"sensors"`
+	}
+	json.NewDecoder(do(t, s, http.MethodGet, "/api/ha/sensors?session=alice", "").Body).Decode(&body)
+	if len(body.Sensors) != 3 {
+		t.Fatalf("Expected portfolio sensors with holdings, got %d sensors", len(body.Sensors))
+	}
+	value := body.Sensors[1]
+	if value.State != "hidden" || value.DeviceClass != "" || value.Attributes["cost_basis"] != nil {
+		t.Errorf("Expected the portfolio value hidden, got %+v", value)
+	}
+	if holdings, _ := value.Attributes["holdings"].(map[string]any); holdings["bitcoin"] != "100.00" {
+		t.Errorf("Expected holdings as shares of the portfolio, got %+v", value.Attributes)
+	}
+	if pnl := body.Sensors[2]; pnl.State != "37.50" || pnl.Unit != "%" || pnl.DeviceClass != "" {
+		t.Errorf("Expected the unrealized P&L as a percentage, got %+v", pnl)
+	}
+}
+
+func TestHAID(t *testing.T) {
+	if got := haID("USD-Coin.e"); got != "usd_coin_e" {
+		t.Errorf("Expected usd_coin_e, got %s", got)
+	}
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"crypto-dashboard/internal/application/coins"
+	"crypto-dashboard/internal/application/icons"
+)
+
+// handleIcon serves the logo of a coin as a square PNG of the requested size
+func (s *Server) handleIcon(w http.ResponseWriter, r *http.Request) {
+	id, ok := strings.CutSuffix(r.PathValue("file"), ".png")
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("icons are served as .png"))
+		return
+	}
+	size, err := intParam(r, "size", icons.DefaultSize)
+	if err != nil || size < icons.MinSize || size > icons.MaxSize {
+		writeError(w, http.StatusBadRequest, errInvalidParam("size"))
+		return
+	}
+
+	icon, err := s.services.Icons.Icon(id, size)
+	switch {
+	case errors.Is(err, coins.ErrNotFound) || errors.Is(err, icons.ErrNoIcon):
+		writeError(w, http.StatusNotFound, err)
+		return
+	case err != nil:
+		writeUpstreamError(w, err)
+		return
+	}
+	// Logos practically never change, so browsers keep them for a week
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=604800, immutable")
+	w.Write(icon)
+}
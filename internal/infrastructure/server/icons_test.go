@@ -0,0 +1,61 @@
+package server
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"testing"
+
+	"crypto-dashboard/internal/application/coins"
+	"crypto-dashboard/internal/application/icons"
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/repository/memory"
+)
+
+// iconDirectory has a logo for bitcoin only
+type iconDirectory struct{ stubDirectory }
+
+func (iconDirectory) CoinInfo(id string) (models.CoinInfo, error) {
+	if id != "bitcoin" {
+		return stubDirectory{}.CoinInfo(id)
+	}
+	return models.CoinInfo{ID: id, Images: models.Images{Large: "https://img/bitcoin.png"}}, nil
+}
+
+// logoDownloader serves a 100×100 PNG for every URL
+type logoDownloader struct{}
+
+func (logoDownloader) Download(url string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 100, 100)))
+	return buf.Bytes(), err
+}
+
+func TestHandleIcon(t *testing.T) {
+	services := newTestServer().services
+	services.Icons = icons.NewService(coins.NewService(iconDirectory{}), logoDownloader{}, memory.NewIconRepository())
+	s := New(0, services)
+
+	rec := do(t, s, http.MethodGet, "/assets/icons/bitcoin.png?size=32", "")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("Expected a PNG, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if cfg, err := png.DecodeConfig(rec.Body); err != nil || cfg.Width != 32 {
+		t.Errorf("Expected a 32 pixel icon, got %+v (%v)", cfg, err)
+	}
+	if cc := rec.Header().Get("Cache-Control"); cc != "public, max-age=604800, immutable" {
+		t.Errorf("Expected long cache headers, got %q", cc)
+	}
+
+	for path, want := range map[string]int{
+		"/assets/icons/bitcoin.png?size=1000": http.StatusBadRequest,
+		"/assets/icons/bitcoin.jpg":           http.StatusNotFound,
+		"/assets/icons/solana.png":            http.StatusNotFound,
+		"/assets/icons/dogecoin.png":          http.StatusNotFound,
+	} {
+		if rec := do(t, s, http.MethodGet, path, ""); rec.Code != want {
+			t.Errorf("GET %s: expected %d, got %d", path, want, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"crypto-dashboard/internal/application/incident"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// errIncidentsNeedAuth is returned for incident writes on a server without API tokens
+var errIncidentsNeedAuth = errors.New("recording incidents requires an API token, configure server.auth.tokens")
+
+// authorizedWrite reports whether the request comes from an authenticated
+// caller, which the auth middleware only lets through with a writable token.
+// Incidents are shown to every visitor of the status page, so an open server
+// does not accept them.
+func authorizedWrite(w http.ResponseWriter, r *http.Request) bool {
+	if _, ok := requestIdentity(r); !ok {
+		writeError(w, http.StatusForbidden, errIncidentsNeedAuth)
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleListIncidents(w http.ResponseWriter, r *http.Request) {
+	from, err := timeParam(r, "from")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	to, err := timeParam(r, "to")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	incidents, err := s.services.Incidents.Incidents(r.URL.Query().Get("crypto_id"), from, to)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if incidents == nil {
+		incidents = []models.Incident{}
+	}
+	writeJSON(w, http.StatusOK, incidents)
+}
+
+// handleRecordIncident annotates a range of unreliable prices, e.g. a data-quality issue
+func (s *Server) handleRecordIncident(w http.ResponseWriter, r *http.Request) {
+	if !authorizedWrite(w, r) {
+		return
+	}
+	var i models.Incident
+	if err := decodeJSON(r, &i); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	recorded, err := s.services.Incidents.Record(i)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, recorded)
+}
+
+// handleResolveIncident ends an incident now, or at the time given by the at parameter
+func (s *Server) handleResolveIncident(w http.ResponseWriter, r *http.Request) {
+	if !authorizedWrite(w, r) {
+		return
+	}
+	at, err := timeParam(r, "at")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	resolved, err := s.services.Incidents.Resolve(r.PathValue("id"), at)
+	if errors.Is(err, incident.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resolved)
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestIncidents(t *testing.T) {
+	s := newAuthServer(&Auth{Tokens: []Token{{Name: "admin", Secret: adminToken}}, PublicRead: true})
+	admin := bearer(adminToken)
+
+	rec := doWith(t, s, http.MethodPost, "/api/v1/incidents", `{"crypto_id":"bitcoin","description":"Stuck price","started":"2024-05-01T12:00:00Z"}`, admin)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+	var recorded models.Incident
+	json.NewDecoder(rec.Body).Decode(&recorded)
+	if recorded.ID == "" || recorded.Kind != models.IncidentDataQuality || !recorded.Ongoing() {
+		t.Errorf("Expected an ongoing data-quality incident, got %+v", recorded)
+	}
+
+	if rec := doWith(t, s, http.MethodPost, "/api/v1/incidents", `{"description":""}`, admin); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 without description, got %d", rec.Code)
+	}
+
+	rec = doWith(t, s, http.MethodPost, "/api/v1/incidents/"+recorded.ID+"/resolve?at=2024-05-01T13:00:00Z", "", admin)
+	var resolved models.Incident
+	json.NewDecoder(rec.Body).Decode(&resolved)
+	if rec.Code != http.StatusOK || resolved.Ongoing() || resolved.Resolved.Hour() != 13 {
+		t.Errorf("Expected the incident resolved at 13:00, got %d %+v", rec.Code, resolved)
+	}
+	if rec := doWith(t, s, http.MethodPost, "/api/v1/incidents/missing/resolve", "", admin); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown incident, got %d", rec.Code)
+	}
+
+	var listed []models.Incident
+	json.NewDecoder(do(t, s, http.MethodGet, "/api/v1/incidents?crypto_id=bitcoin&from=2024-05-01", "").Body).Decode(&listed)
+	if len(listed) != 1 {
+		t.Errorf("Expected the bitcoin incident, got %+v", listed)
+	}
+	json.NewDecoder(do(t, s, http.MethodGet, "/api/v1/incidents?from=2024-05-02", "").Body).Decode(&listed)
+	if len(listed) != 0 {
+		t.Errorf("Expected no incident after it was resolved, got %+v", listed)
+	}
+	if rec := do(t, s, http.MethodGet, "/api/v1/incidents?from=yesterday", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid from, got %d", rec.Code)
+	}
+}
+
+func TestIncidents_WritesNeedAuth(t *testing.T) {
+	s := newTestServer()
+	if rec := do(t, s, http.MethodPost, "/api/v1/incidents", `{"description":"Fake outage"}`); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 on a server without tokens, got %d", rec.Code)
+	}
+	if rec := do(t, s, http.MethodPost, "/api/v1/incidents/any/resolve", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 on a server without tokens, got %d", rec.Code)
+	}
+
+	authed := newAuthServer(&Auth{Tokens: []Token{{Name: "admin", Secret: adminToken}}, PublicRead: true})
+	if rec := do(t, authed, http.MethodPost, "/api/v1/incidents", `{"description":"Fake outage"}`); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a token, got %d", rec.Code)
+	}
+}
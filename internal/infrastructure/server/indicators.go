@@ -0,0 +1,68 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"crypto-dashboard/internal/application/analytics"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func (s *Server) handleIndicators(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	limit, err := intParam(r, "limit", defaultCandleLimit)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	cfg := analytics.DefaultIndicatorConfig()
+	for name, period := range map[string]*int{
+		"sma":       &cfg.SMAPeriod,
+		"ema":       &cfg.EMAPeriod,
+		"rsi":       &cfg.RSIPeriod,
+		"bollinger": &cfg.BollingerPeriod,
+	} {
+		if *period, err = boundedIntParam(r, name, *period, models.MaxIndicatorPeriod); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	// Indicators are computed over the full history so the returned window is warmed up
+	candles, err := s.services.Candles.Candles(id, s.services.CandleInterval, 0)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if len(candles) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no stored history for %s", id))
+		return
+	}
+
+	points, err := analytics.ComputeIndicators(candles, cfg)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(points) > limit {
+		points = points[len(points)-limit:]
+	}
+
+	// The tracker keeps the default periods up to date as candles close
+	latest := points[len(points)-1]
+	if t := s.services.Indicators; t != nil && t.Config() == cfg {
+		if live, ok := t.Latest(id); ok && !live.Time.Before(latest.Time) {
+			latest = live
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":       id,
+		"interval": s.services.CandleInterval.String(),
+		"config":   cfg,
+		"latest":   latest,
+		"points":   points,
+	})
+}
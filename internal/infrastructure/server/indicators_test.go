@@ -0,0 +1,55 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/analytics"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestHandleIndicators(t *testing.T) {
+	s := newTestServer()
+
+	rec := do(t, s, http.MethodGet, "/api/v1/coins/bitcoin/indicators", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 without history, got %d", rec.Code)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 40; i++ {
+		c := models.NewCandle("bitcoin", 100+float64(i), start.Add(time.Duration(i)*time.Hour), time.Hour)
+		s.services.Candles.SaveCandle(time.Hour, c)
+	}
+
+	rec = do(t, s, http.MethodGet, "/api/v1/coins/bitcoin/indicators?limit=5&sma=3", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var body struct {
+		Config analytics.IndicatorConfig  `json:"config"`
+		Latest analytics.IndicatorPoint   `json:"latest"`
+		Points []analytics.IndicatorPoint `json:"points"`
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if len(body.Points) != 5 || body.Config.SMAPeriod != 3 {
+		t.Fatalf("Expected 5 points with SMA3, got %d points and %+v", len(body.Points), body.Config)
+	}
+	// The last closes are 137, 138, 139
+	if body.Latest.SMA == nil || *body.Latest.SMA != 138 {
+		t.Errorf("Expected SMA3 of 138, got %v", body.Latest.SMA)
+	}
+	if body.Latest.MACD == nil || body.Latest.RSI == nil || body.Latest.Bollinger == nil {
+		t.Errorf("Expected every indicator to be warmed up, got %+v", body.Latest)
+	}
+
+	for _, query := range []string{"rsi=0", "sma=1000000000"} {
+		rec = do(t, s, http.MethodGet, "/api/v1/coins/bitcoin/indicators?"+query, "")
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", query, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package server
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/infrastructure/export"
+)
+
+// litePage is a script-free page for screen readers, slow connections and
+// terminal browsers. Changes are spelled out instead of only being colored.
+var litePage = template.Must(template.New("lite").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Crypto prices ({{.Currency}})</title>
+</head>
+<body>
+<main>
+<h1>Crypto prices ({{.Currency}})</h1>
+<p>Updated {{.Updated}}. <a href="/lite">Refresh</a> · <a href="/lite?format=text">Plain text</a> · <a href="/">Full dashboard</a></p>
+{{if .Stale}}<p><strong>Some prices could not be refreshed and show their last known value.</strong></p>{{end}}
+{{if .Inactive}}<p><strong>Some coins are no longer updated by the price provider and may have been delisted.</strong></p>{{end}}
+<table>
+<caption>Tracked coins</caption>
+<thead><tr><th scope="col">Coin</th><th scope="col">Price</th><th scope="col">24 hour change</th></tr></thead>
+<tbody>
+{{range .Prices}}<tr><th scope="row">{{.Name}}</th><td>{{.Price}}</td><td>{{.Change}}</td></tr>
+{{else}}<tr><td colspan="3">No prices yet.</td></tr>
+{{end}}</tbody>
+</table>
+{{if .Holdings}}
+<h2>Portfolio</h2>
+{{if .Private}}<p>Privacy mode is on: amounts are hidden and values are shown as shares of the portfolio.</p>{{end}}
+<table>
+<caption>Open positions</caption>
+<thead><tr><th scope="col">Coin</th><th scope="col">Quantity</th><th scope="col">Value</th><th scope="col">Unrealized</th></tr></thead>
+<tbody>
+{{range .Holdings}}<tr><th scope="row">{{.Coin}}</th><td>{{.Quantity}}</td><td>{{.Value}}</td><td>{{.Unrealized}}</td></tr>
+{{end}}</tbody>
+</table>
+<p>Total value {{.Total}}, cost basis {{.Cost}}, unrealized {{.Unrealized}}.</p>
+{{if .Income}}<p>Income received {{.Income}}, not counted as profit.</p>{{end}}
+{{end}}
+</main>
+</body>
+</html>
+`))
+
+// liteView is the pre-formatted content of the lite page
+type liteView struct {
+	Currency   string
+	Updated    string
+	Stale      bool
+	Inactive   bool
+	Private    bool
+	Prices     []litePrice
+	Holdings   []liteHolding
+	Total      string
+	Cost       string
+	Unrealized string
+	Income     string
+}
+
+type litePrice struct {
+	Name, Price, Change string
+}
+
+type liteHolding struct {
+	Coin, Quantity, Value, Unrealized string
+}
+
+// hiddenAmount replaces absolute portfolio amounts in privacy mode
+const hiddenAmount = "hidden"
+
+// handleLite renders prices and portfolio totals as plain HTML, or as text with format=text
+func (s *Server) handleLite(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "html" && format != "text" {
+		writeError(w, http.StatusBadRequest, errInvalidParam("format"))
+		return
+	}
+
+	view := s.liteView(s.services.Themes.Private(pageOwner(r)))
+	if format == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		writeLiteText(w, view)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	litePage.Execute(w, view)
+}
+
+// liteView formats the page content. In privacy mode holdings are shown as
+// shares of the portfolio and profits as percentages of the cost basis.
+func (s *Server) liteView(private bool) liteView {
+	code := strings.ToUpper(string(s.services.Poller.Currency()))
+	view := liteView{Currency: code, Updated: s.services.Clock.Now().UTC().Format("2006-01-02 15:04 UTC"), Private: private}
+
+	for _, p := range s.services.Poller.Snapshot() {
+		name := p.Name
+		if name == "" {
+			name = p.ID
+		}
+		price := liteAmount(p.CurrentPrice) + " " + code
+		if p.Inactive {
+			// A frozen price would read as current, so only its date is shown
+			price = "inactive"
+			if updated, ok := p.LastUpdatedTime(); ok {
+				price += " since " + updated.Format("2006-01-02")
+			}
+			view.Inactive = true
+		} else if p.Stale {
+			price += " (stale)"
+			view.Stale = true
+		}
+		view.Prices = append(view.Prices, litePrice{Name: name, Price: price, Change: liteChange(p.PriceChange24h)})
+	}
+
+	if len(s.services.Holdings) == 0 {
+		return view
+	}
+	holdings := s.pricedHoldings()
+	var total, cost, income decimal.Decimal
+	for _, h := range holdings {
+		total, cost = total.Add(h.Value()), cost.Add(h.CostBasis)
+		income = income.Add(h.Income)
+	}
+	for _, h := range holdings {
+		if private {
+			view.Holdings = append(view.Holdings, liteHolding{
+				Coin:       h.CryptoID,
+				Quantity:   hiddenAmount,
+				Value:      litePercent(h.Value(), total, false) + " of portfolio",
+				Unrealized: litePercent(h.UnrealizedPnL(), h.CostBasis, true),
+			})
+			continue
+		}
+		view.Holdings = append(view.Holdings, liteHolding{
+			Coin:       h.CryptoID,
+			Quantity:   h.Quantity.String(),
+			Value:      h.Value().StringFixed(2) + " " + code,
+			Unrealized: liteSigned(h.UnrealizedPnL()) + " " + code,
+		})
+	}
+	if private {
+		view.Total, view.Cost = hiddenAmount, hiddenAmount
+		view.Unrealized = litePercent(total.Sub(cost), cost, true)
+		return view
+	}
+	if income.IsPositive() {
+		view.Income = income.StringFixed(2) + " " + code
+	}
+	view.Total = total.StringFixed(2) + " " + code
+	view.Cost = cost.StringFixed(2) + " " + code
+	view.Unrealized = liteSigned(total.Sub(cost)) + " " + code
+	return view
+}
+
+// pricedHoldings values the configured holdings at the poller's prices.
+// Holdings whose price is unavailable keep a zero price.
+func (s *Server) pricedHoldings() []export.Holding {
+	ids := make([]string, len(s.services.Holdings))
+	for i, h := range s.services.Holdings {
+		ids[i] = h.CryptoID
+	}
+	prices, _ := s.services.Poller.Prices(ids)
+	byID := make(map[string]decimal.Decimal, len(prices))
+	for _, p := range prices {
+		byID[p.ID] = p.CurrentPrice
+	}
+
+	holdings := make([]export.Holding, len(s.services.Holdings))
+	for i, h := range s.services.Holdings {
+		h.Price = byID[h.CryptoID]
+		holdings[i] = h
+	}
+	return holdings
+}
+
+// writeLiteText writes the lite view as aligned plain text
+func writeLiteText(w io.Writer, view liteView) {
+	fmt.Fprintf(w, "Crypto prices (%s), updated %s\n\n", view.Currency, view.Updated)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "COIN\tPRICE\t24H")
+	for _, p := range view.Prices {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", p.Name, p.Price, p.Change)
+	}
+	tw.Flush()
+
+	if len(view.Holdings) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "\nPortfolio")
+	fmt.Fprintln(tw, "COIN\tQUANTITY\tVALUE\tUNREALIZED")
+	for _, h := range view.Holdings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", h.Coin, h.Quantity, h.Value, h.Unrealized)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\nTotal value %s, cost basis %s, unrealized %s\n", view.Total, view.Cost, view.Unrealized)
+	if view.Income != "" {
+		fmt.Fprintf(w, "Income received %s, not counted as profit\n", view.Income)
+	}
+}
+
+// liteAmount formats a price with two decimals, or up to eight decimals
+// below one so micro-cap prices do not round to zero
+func liteAmount(d decimal.Decimal) string {
+	if d.Abs().LessThan(decimal.NewFromInt(1)) && !d.IsZero() {
+		return d.Round(8).String()
+	}
+	return d.StringFixed(2)
+}
+
+// liteChange spells out the direction of a percentage change
+func liteChange(pct float64) string {
+	switch {
+	case pct > 0:
+		return fmt.Sprintf("up %.2f%%", pct)
+	case pct < 0:
+		return fmt.Sprintf("down %.2f%%", -pct)
+	}
+	return "unchanged"
+}
+
+// litePercent formats part as a percentage of whole, with an explicit sign when
+// signed is set, or n/a when whole is not positive
+func litePercent(part, whole decimal.Decimal, signed bool) string {
+	if !whole.IsPositive() {
+		return "n/a"
+	}
+	pct := liteSigned(part.Div(whole).Mul(decimal.NewFromInt(100)))
+	if !signed {
+		pct = strings.TrimPrefix(pct, "+")
+	}
+	return pct + "%"
+}
+
+// liteSigned formats an amount with two decimals and an explicit sign
+func liteSigned(d decimal.Decimal) string {
+	if d.IsNegative() {
+		return d.StringFixed(2)
+	}
+	return "+" + d.StringFixed(2)
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/infrastructure/export"
+)
+
+func TestHandleLite(t *testing.T) {
+	s := newTestServer()
+	s.services.Poller.PollOnce()
+	s.services.Holdings = []export.Holding{
+		{CryptoID: "bitcoin", Quantity: decimal.RequireFromString("0.5"), CostBasis: decimal.NewFromInt(20000), Income: decimal.NewFromInt(150)},
+	}
+
+	rec := do(t, s, http.MethodGet, "/lite", "")
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("Expected an HTML page, got %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	page := rec.Body.String()
+	if strings.Contains(page, "<script") {
+		t.Error("Expected no scripts on the lite page")
+	}
+	for _, want := range []string{
+		`<th scope="row">bitcoin</th><td>55000.00 USD</td><td>unchanged</td>`,
+		"Total value 27500.00 USD, cost basis 20000.00 USD, unrealized &#43;7500.00 USD.",
+		"Income received 150.00 USD, not counted as profit.",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("Expected %q in:\n%s", want, page)
+		}
+	}
+}
+
+func TestHandleLite_Text(t *testing.T) {
+	s := newTestServer()
+	s.services.Poller.PollOnce()
+
+	rec := do(t, s, http.MethodGet, "/lite?format=text", "")
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Fatalf("Expected plain text, got %s", ct)
+	}
+	if text := rec.Body.String(); !strings.Contains(text, "bitcoin  55000.00 USD  unchanged") || strings.Contains(text, "Portfolio") {
+		t.Errorf("Unexpected text view:\n%s", text)
+	}
+
+	if rec := do(t, s, http.MethodGet, "/lite?format=pdf", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown format, got %d", rec.Code)
+	}
+}
+
+func TestLiteFormatting(t *testing.T) {
+	if got := liteAmount(decimal.RequireFromString("0.0000123456789")); got != "0.00001235" {
+		t.Errorf("Expected micro-cap prices to keep their digits, got %s", got)
+	}
+	if got := liteChange(-1.234); got != "down 1.23%" {
+		t.Errorf("Expected a spelled out direction, got %s", got)
+	}
+}
+
+func TestHandleLite_PrivacyMode(t *testing.T) {
+	s := newTestServer()
+	s.services.Poller.PollOnce()
+	s.services.Holdings = []export.Holding{
+		{CryptoID: "bitcoin", Quantity: decimal.RequireFromString("0.5"), CostBasis: decimal.NewFromInt(20000), Income: decimal.NewFromInt(150)},
+	}
+	if _, err := s.services.Themes.SetPrivacy("alice", true); err != nil {
+		t.Fatal(err)
+	}
+
+	page := do(t, s, http.MethodGet, "/lite?session=alice", "").Body.String()
+	for _, want := range []string{
+		`<th scope="row">bitcoin</th><td>hidden</td><td>100.00% of portfolio</td><td>&#43;37.50%</td>`,
+		"Total value hidden, cost basis hidden, unrealized &#43;37.50%.",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("Expected %q in:\n%s", want, page)
+		}
+	}
+	if strings.Contains(page, "27500") || strings.Contains(page, "Income received") {
+		t.Errorf("Expected no absolute amounts in privacy mode:\n%s", page)
+	}
+
+	if page := do(t, s, http.MethodGet, "/lite", "").Body.String(); !strings.Contains(page, "27500.00 USD") {
+		t.Error("Expected other sessions to keep seeing amounts")
+	}
+}
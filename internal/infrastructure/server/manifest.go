@@ -0,0 +1,70 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"crypto-dashboard/internal/application/manifest"
+)
+
+// maxManifestSize bounds the manifests accepted by PUT /api/v1/manifest
+const maxManifestSize = 1 << 20
+
+// applyResponse is the plan of a manifest and whether it was applied
+type applyResponse struct {
+	manifest.Plan
+	DryRun bool `json:"dry_run"`
+	Prune  bool `json:"prune"`
+}
+
+// handleExportManifest writes the session's watchlists, alert rules, theme and charts as YAML
+func (s *Server) handleExportManifest(w http.ResponseWriter, r *http.Request) {
+	m, err := s.services.Manifest.Export(sessionOwner(r))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	data, err := manifest.Marshal(m)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Write(data)
+}
+
+// handleApplyManifest reconciles the session with a YAML manifest. With
+// dry_run=true it only returns the plan; with prune=true resources missing
+// from the declared sections are deleted. Changes are applied in order and a
+// failure leaves the earlier ones in place, so the manifest can be applied again.
+func (s *Server) handleApplyManifest(w http.ResponseWriter, r *http.Request) {
+	var flags [2]bool
+	for i, name := range []string{"dry_run", "prune"} {
+		if v := r.URL.Query().Get(name); v != "" {
+			var err error
+			if flags[i], err = strconv.ParseBool(v); err != nil {
+				writeError(w, http.StatusBadRequest, errInvalidParam(name))
+				return
+			}
+		}
+	}
+	dryRun, prune := flags[0], flags[1]
+
+	m, err := manifest.Parse(http.MaxBytesReader(w, r.Body, maxManifestSize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	plan, err := s.services.Manifest.Plan(sessionOwner(r), m, prune)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if !dryRun {
+		if err := plan.Apply(); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, applyResponse{Plan: plan, DryRun: dryRun, Prune: prune})
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"crypto-dashboard/internal/application/manifest"
+	"crypto-dashboard/internal/application/watchlist"
+)
+
+func TestManifestEndpoints(t *testing.T) {
+	if rec := do(t, newTestServer(), http.MethodGet, "/api/v1/manifest", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without the manifest service, got %d", rec.Code)
+	}
+
+	services := newTestServer().services
+	services.Manifest = manifest.NewService(services.Watchlists, services.Alerts, services.Themes, services.Charts)
+	s := New(0, services)
+
+	doc := `version: dashboard/v1
+watchlists:
+  - name: Majors
+    coins: [bitcoin, ethereum]
+alert_rules:
+  - crypto_id: bitcoin
+    kind: price_above
+    threshold: 70000
+`
+	rec := doAs(t, s, "alice", http.MethodPut, "/api/v1/manifest?dry_run=true", doc)
+	var plan applyResponse
+	json.NewDecoder(rec.Body).Decode(&plan)
+	if rec.Code != http.StatusOK || !plan.DryRun || len(plan.Changes) != 2 {
+		t.Fatalf("Expected a plan of two changes, got %d: %+v", rec.Code, plan)
+	}
+	if lists, _ := services.Watchlists.List("alice"); len(lists) != 0 {
+		t.Fatalf("Expected a dry run to change nothing, got %+v", lists)
+	}
+
+	if rec := doAs(t, s, "alice", http.MethodPut, "/api/v1/manifest", doc); rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = doAs(t, s, "alice", http.MethodGet, "/api/v1/manifest", "")
+	if ct := rec.Header().Get("Content-Type"); ct != "application/yaml" {
+		t.Errorf("Expected YAML, got %s", ct)
+	}
+	for _, want := range []string{"- name: Majors", "threshold: 70000", "mode: dark"} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("Expected %q in the export:\n%s", want, rec.Body.String())
+		}
+	}
+
+	// The export applies cleanly to itself
+	rec = doAs(t, s, "alice", http.MethodPut, "/api/v1/manifest?prune=true", rec.Body.String())
+	json.NewDecoder(rec.Body).Decode(&plan)
+	if len(plan.Changes) != 0 {
+		t.Errorf("Expected the export to be converged, got %+v", plan.Changes)
+	}
+
+	// Edits made outside the manifest show up as drift with the field they changed
+	lists, _ := services.Watchlists.List("alice")
+	services.Watchlists.Update(lists[0].ID, watchlist.Update{Coins: []string{"bitcoin"}})
+	rec = doAs(t, s, "alice", http.MethodPut, "/api/v1/manifest?dry_run=true", doc)
+	plan = applyResponse{}
+	json.NewDecoder(rec.Body).Decode(&plan)
+	if len(plan.Changes) != 1 || !plan.Changes[0].Drifted || len(plan.Changes[0].Diff) != 1 {
+		t.Fatalf("Expected one drifted watchlist, got %+v", plan.Changes)
+	}
+	if d := plan.Changes[0].Diff[0]; d.Field != "coins" || d.From != "[bitcoin]" || d.To != "[bitcoin ethereum]" {
+		t.Errorf("Unexpected diff: %+v", d)
+	}
+
+	if rec := doAs(t, s, "alice", http.MethodPut, "/api/v1/manifest", "version: dashboard/v1\nwatchlist: []\n"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown field, got %d", rec.Code)
+	}
+	if rec := doAs(t, s, "alice", http.MethodPut, "/api/v1/manifest", "version: dashboard/v1\nwatchlists: [{name: ''}]\n"); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 for an invalid watchlist, got %d", rec.Code)
+	}
+	if rec := doAs(t, s, "alice", http.MethodPut, "/api/v1/manifest?prune=maybe", doc); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid flag, got %d", rec.Code)
+	}
+}
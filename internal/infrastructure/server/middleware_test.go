@@ -0,0 +1,43 @@
+package server
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogRequests(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	handler := logRequests(logger, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+
+	tests := []struct {
+		path  string
+		level string
+		code  string
+	}{
+		{"/ok", "level=INFO", "status=200"},
+		{"/fail", "level=ERROR", "status=500"},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		line := buf.String()
+		for _, want := range []string{tt.level, "method=GET", "path=" + tt.path, tt.code, "duration="} {
+			if !strings.Contains(line, want) {
+				t.Errorf("Expected %q in log line, got %s", want, line)
+			}
+		}
+	}
+}
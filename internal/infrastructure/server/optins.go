@@ -0,0 +1,108 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"crypto-dashboard/internal/application/optin"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// channelParam reads the opt-in channel from the path
+func channelParam(w http.ResponseWriter, r *http.Request) (models.OptInChannel, bool) {
+	channel, err := models.ParseOptInChannel(r.PathValue("channel"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return "", false
+	}
+	return channel, true
+}
+
+// handleListOptIns lists the confirmed opt-ins the session requested
+func (s *Server) handleListOptIns(w http.ResponseWriter, r *http.Request) {
+	channel, ok := channelParam(w, r)
+	if !ok {
+		return
+	}
+	optIns, err := s.services.OptIns.List(sessionOwner(r), channel)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if optIns == nil {
+		optIns = []models.OptIn{}
+	}
+	writeJSON(w, http.StatusOK, optIns)
+}
+
+// handleOptIn sends a confirmation code to the recipient; the opt-in only
+// takes effect once the code is confirmed
+func (s *Server) handleOptIn(w http.ResponseWriter, r *http.Request) {
+	channel, ok := channelParam(w, r)
+	if !ok {
+		return
+	}
+	var o models.OptIn
+	if err := decodeJSON(r, &o); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	o.Channel = channel
+
+	pending, err := s.services.OptIns.Request(r.Context(), sessionOwner(r), o)
+	switch {
+	case errors.Is(err, optin.ErrTooSoon):
+		writeError(w, http.StatusTooManyRequests, err)
+	case errors.Is(err, optin.ErrNoSender):
+		writeError(w, http.StatusServiceUnavailable, err)
+	case errors.Is(err, optin.ErrSendFailed):
+		writeError(w, http.StatusBadGateway, err)
+	case err != nil:
+		writeError(w, http.StatusUnprocessableEntity, err)
+	default:
+		writeJSON(w, http.StatusAccepted, pending)
+	}
+}
+
+// handleConfirmOptIn activates a pending opt-in with the code sent to the recipient
+func (s *Server) handleConfirmOptIn(w http.ResponseWriter, r *http.Request) {
+	channel, ok := channelParam(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	saved, err := s.services.OptIns.Confirm(sessionOwner(r), channel, r.PathValue("recipient"), body.Code)
+	if errors.Is(err, optin.ErrInvalidCode) {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, saved)
+}
+
+// handleOptOut withdraws an opt-in the session requested
+func (s *Server) handleOptOut(w http.ResponseWriter, r *http.Request) {
+	channel, ok := channelParam(w, r)
+	if !ok {
+		return
+	}
+	err := s.services.OptIns.OptOut(sessionOwner(r), channel, r.PathValue("recipient"))
+	if errors.Is(err, optin.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"crypto-dashboard/internal/application/optin"
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/repository/memory"
+)
+
+// codeSender keeps the last code sent to each recipient
+type codeSender map[string]string
+
+func (c codeSender) SendCode(ctx context.Context, o models.OptIn, code string) error {
+	c[o.Recipient] = code
+	return nil
+}
+
+func TestOptInEndpoints(t *testing.T) {
+	if rec := do(t, newTestServer(), http.MethodPost, "/api/v1/optins/whatsapp", `{"recipient":"5511987654321"}`); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 without a code sender, got %d", rec.Code)
+	}
+
+	services := newTestServer().services
+	services.OptIns = optin.NewService(memory.NewOptInRepository())
+	sent := codeSender{}
+	services.OptIns.SetSender(sent)
+	s := New(0, services)
+
+	rec := doAs(t, s, "alice", http.MethodPost, "/api/v1/optins/whatsapp", `{"recipient":"+55 11 98765-4321","language":"pt_BR"}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := doAs(t, s, "alice", http.MethodPost, "/api/v1/optins/whatsapp", `{"recipient":"+55 11 98765-4321"}`); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429 for a resend, got %d", rec.Code)
+	}
+	if rec := do(t, s, http.MethodPost, "/api/v1/optins/whatsapp", `{"recipient":"nope"}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 for an invalid number, got %d", rec.Code)
+	}
+	if rec := do(t, s, http.MethodGet, "/api/v1/optins/pager", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown channel, got %d", rec.Code)
+	}
+
+	var optIns []models.OptIn
+	json.NewDecoder(doAs(t, s, "alice", http.MethodGet, "/api/v1/optins/whatsapp", "").Body).Decode(&optIns)
+	if len(optIns) != 0 {
+		t.Fatalf("Expected no opt-in before the confirmation, got %+v", optIns)
+	}
+	if rec := doAs(t, s, "alice", http.MethodPost, "/api/v1/optins/whatsapp/5511987654321/confirm", `{"code":"000000x"}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 for a wrong code, got %d", rec.Code)
+	}
+	confirm := `{"code":"` + sent["5511987654321"] + `"}`
+	if rec := doAs(t, s, "alice", http.MethodPost, "/api/v1/optins/whatsapp/5511987654321/confirm", confirm); rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	json.NewDecoder(doAs(t, s, "alice", http.MethodGet, "/api/v1/optins/whatsapp", "").Body).Decode(&optIns)
+	if len(optIns) != 1 || optIns[0].Recipient != "5511987654321" {
+		t.Fatalf("Expected the normalized recipient, got %+v", optIns)
+	}
+	json.NewDecoder(do(t, s, http.MethodGet, "/api/v1/optins/whatsapp", "").Body).Decode(&optIns)
+	if len(optIns) != 0 {
+		t.Errorf("Expected other sessions not to see the number, got %+v", optIns)
+	}
+	if rec := do(t, s, http.MethodDelete, "/api/v1/optins/whatsapp/5511987654321", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for another session's opt-in, got %d", rec.Code)
+	}
+
+	if rec := doAs(t, s, "alice", http.MethodDelete, "/api/v1/optins/whatsapp/5511987654321", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", rec.Code)
+	}
+	if rec := doAs(t, s, "alice", http.MethodDelete, "/api/v1/optins/whatsapp/5511987654321", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 after opting out, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"net/http"
+
+	"crypto-dashboard/internal/application/palette"
+)
+
+// Limits of the command palette results
+const (
+	defaultPaletteLimit = 10
+	maxPaletteLimit     = 50
+)
+
+// paletteCommands lists the pages and actions the command palette can find
+func (s *Server) paletteCommands() []palette.Entry {
+	commands := []palette.Entry{
+		{Kind: palette.KindPage, ID: "dashboard", Title: "Dashboard", Path: "/", Keywords: []string{"home", "prices"}},
+		{Kind: palette.KindPage, ID: "lite", Title: "Lite view", Hint: "Script-free prices and portfolio", Path: "/lite", Keywords: []string{"text", "accessible", "portfolio"}},
+		{Kind: palette.KindAction, ID: "add-alert", Title: "Add alert", Hint: "Notify when a price or indicator crosses a level", Path: "/api/v1/alerts/rules", Method: http.MethodPost, Keywords: []string{"notify", "rule"}},
+		{Kind: palette.KindAction, ID: "save-chart", Title: "Save chart", Hint: "Save the current chart and copy its permalink", Path: "/api/v1/charts", Method: http.MethodPost, Keywords: []string{"share", "permalink", "link"}},
+		{Kind: palette.KindAction, ID: "create-watchlist", Title: "Create watchlist", Path: "/api/v1/watchlists", Method: http.MethodPost, Keywords: []string{"new", "list"}},
+		{Kind: palette.KindAction, ID: "export-prices", Title: "Export prices as CSV", Path: "/api/v1/export/prices?format=csv", Method: http.MethodGet, Keywords: []string{"download", "spreadsheet"}},
+		{Kind: palette.KindAction, ID: "toggle-privacy", Title: "Toggle privacy mode", Hint: "Hide portfolio amounts while screen-sharing", Path: "/api/v1/privacy", Method: http.MethodPut, Keywords: []string{"hide", "screen", "share"}},
+		{Kind: palette.KindAction, ID: "change-theme", Title: "Change theme", Path: "/api/v1/theme", Method: http.MethodPut, Keywords: []string{"dark", "light", "colors", "appearance"}},
+	}
+	if s.services.Status != nil {
+		commands = append(commands, palette.Entry{Kind: palette.KindPage, ID: "status", Title: "Status", Hint: "Provider health and incidents", Path: "/status", Keywords: []string{"health", "uptime", "incidents"}})
+	}
+	return commands
+}
+
+// handleCommandPalette searches coins, pages and actions in one ranked list.
+// When the coin lookup fails the matching commands are returned as a partial result.
+func (s *Server) handleCommandPalette(w http.ResponseWriter, r *http.Request) {
+	limit, err := intParam(r, "limit", defaultPaletteLimit)
+	if err != nil || limit > maxPaletteLimit {
+		writeError(w, http.StatusBadRequest, errInvalidParam("limit"))
+		return
+	}
+
+	query := r.URL.Query().Get("q")
+	results, err := palette.Search(query, s.paletteCommands(), s.services.Coins, limit)
+	if err != nil && len(results) == 0 {
+		writeUpstreamError(w, err)
+		return
+	}
+	if results == nil {
+		results = []palette.Entry{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"query":   query,
+		"results": results,
+		"partial": err != nil,
+	})
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"crypto-dashboard/internal/application/palette"
+)
+
+func TestHandleCommandPalette(t *testing.T) {
+	s := newTestServer()
+
+	rec := do(t, s, http.MethodGet, "/api/v1/command-palette?q=sol", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Results []palette.Entry `json:"results"`
+		Partial bool            `json:"partial"`
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if len(body.Results) != 2 || body.Results[0].ID != "solana" || body.Results[0].Kind != palette.KindCoin || body.Partial {
+		t.Errorf("Expected the exact coin first, got %+v", body)
+	}
+
+	json.NewDecoder(do(t, s, http.MethodGet, "/api/v1/command-palette?q=export+csv", "").Body).Decode(&body)
+	if len(body.Results) == 0 || body.Results[0].ID != "export-prices" || body.Results[0].Method != http.MethodGet {
+		t.Errorf("Expected the CSV export action first, got %+v", body.Results)
+	}
+
+	json.NewDecoder(do(t, s, http.MethodGet, "/api/v1/command-palette?limit=3", "").Body).Decode(&body)
+	if len(body.Results) != 3 || body.Results[0].ID != "dashboard" {
+		t.Errorf("Expected the first three commands for an empty query, got %+v", body.Results)
+	}
+
+	if rec := do(t, s, http.MethodGet, "/api/v1/command-palette?limit=500", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a limit above %d, got %d", maxPaletteLimit, rec.Code)
+	}
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net/http"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func (s *Server) handleGetCoinOrder(w http.ResponseWriter, r *http.Request) {
+	o, err := s.services.Preferences.Order(sessionOwner(r))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, o)
+}
+
+// handleSetCoinOrder replaces both the pins and the manual order of the session
+func (s *Server) handleSetCoinOrder(w http.ResponseWriter, r *http.Request) {
+	var o models.CoinOrder
+	if err := decodeJSON(r, &o); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	saved, err := s.services.Preferences.SetOrder(sessionOwner(r), o)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, saved)
+}
+
+func (s *Server) handleResetCoinOrder(w http.ResponseWriter, r *http.Request) {
+	if err := s.services.Preferences.Reset(sessionOwner(r)); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handlePin(w http.ResponseWriter, r *http.Request) {
+	o, err := s.services.Preferences.Pin(sessionOwner(r), r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, o)
+}
+
+func (s *Server) handleUnpin(w http.ResponseWriter, r *http.Request) {
+	o, err := s.services.Preferences.Unpin(sessionOwner(r), r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, o)
+}
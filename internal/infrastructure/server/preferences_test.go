@@ -0,0 +1,60 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestCoinOrderEndpoints(t *testing.T) {
+	s := newTestServer()
+	s.services.Poller.SetCoins([]string{"bitcoin", "ethereum", "solana", "cardano"})
+	s.services.Poller.PollOnce()
+
+	priceIDs := func(session string) ([]string, []string) {
+		t.Helper()
+		var body struct {
+			Prices []models.CryptoPrice `json:"prices"`
+			Pinned []string             `json:"pinned"`
+		}
+		json.NewDecoder(doAs(t, s, session, http.MethodGet, "/api/v1/prices", "").Body).Decode(&body)
+		var ids []string
+		for _, p := range body.Prices {
+			ids = append(ids, p.ID)
+		}
+		return ids, body.Pinned
+	}
+
+	rec := doAs(t, s, "alice", http.MethodPut, "/api/v1/preferences/order", `{"order":["solana","ethereum"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := doAs(t, s, "alice", http.MethodPut, "/api/v1/preferences/order", `{"order":["solana","solana"]}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 for a duplicate coin, got %d", rec.Code)
+	}
+	if rec := doAs(t, s, "alice", http.MethodPut, "/api/v1/preferences/pins/cardano", ""); rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	ids, pinned := priceIDs("alice")
+	if want := []string{"cardano", "solana", "ethereum", "bitcoin"}; !slices.Equal(ids, want) || !slices.Equal(pinned, []string{"cardano"}) {
+		t.Errorf("Expected %v with cardano pinned, got %v and %v", want, ids, pinned)
+	}
+	if ids, _ := priceIDs("bob"); ids[0] != "bitcoin" {
+		t.Errorf("Expected other sessions to keep the default order, got %v", ids)
+	}
+
+	doAs(t, s, "alice", http.MethodDelete, "/api/v1/preferences/pins/cardano", "")
+	if ids, _ := priceIDs("alice"); ids[0] != "solana" {
+		t.Errorf("Expected cardano to be unpinned, got %v", ids)
+	}
+	if rec := doAs(t, s, "alice", http.MethodDelete, "/api/v1/preferences/order", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", rec.Code)
+	}
+	if ids, _ := priceIDs("alice"); ids[0] != "bitcoin" {
+		t.Errorf("Expected the default order after a reset, got %v", ids)
+	}
+}
@@ -0,0 +1,252 @@
+package server
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"html"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/chart"
+	"crypto-dashboard/internal/application/format"
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/ogimage"
+)
+
+// previewFooter names the dashboard on preview cards
+const previewFooter = "Crypto Dashboard"
+
+// errNotTracked is returned for previews of coins the poller does not track
+var errNotTracked = errors.New("coin is not tracked")
+
+// handleCoinPreview renders the Open Graph card of a tracked coin: its price,
+// 24h change and recent prices. Previews are public, like the dashboard page,
+// since chat apps fetch them without credentials.
+func (s *Server) handleCoinPreview(w http.ResponseWriter, r *http.Request) {
+	id, ok := strings.CutSuffix(r.PathValue("file"), ".png")
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("previews are served as .png"))
+		return
+	}
+	card, err := s.coinCard(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writePreview(w, card)
+}
+
+// handleChartPreview renders the Open Graph card of a shared chart, drawing
+// the daily series since inception for charts of that range
+func (s *Server) handleChartPreview(w http.ResponseWriter, r *http.Request) {
+	id, ok := strings.CutSuffix(r.PathValue("file"), ".png")
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("previews are served as .png"))
+		return
+	}
+	c, err := s.services.Charts.Get(id)
+	if errors.Is(err, chart.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	card, err := s.coinCard(c.CryptoID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	card.Title = cmp.Or(c.Name, card.Title)
+	if c.Range == models.ChartMax && s.services.Inception != nil {
+		points, err := s.services.Inception.Series(c.CryptoID, s.services.Poller.Currency())
+		if err == nil && len(points) > 1 && points[0].Price > 0 {
+			card.Series = prices(points)
+			card.Change = (points[len(points)-1].Price/points[0].Price - 1) * 100
+			card.Subtitle = fmt.Sprintf("%s - since %s", strings.ToUpper(c.CryptoID), points[0].Time.Format("Jan 2006"))
+		}
+	}
+	writePreview(w, card)
+}
+
+// coinCard returns the preview of a tracked coin. The sparkline shows the
+// recently polled prices, or the candle closes right after a restart.
+func (s *Server) coinCard(id string) (ogimage.Card, error) {
+	latest, ok := s.services.Poller.Latest(id)
+	if !ok {
+		return ogimage.Card{}, fmt.Errorf("%w: %s", errNotTracked, id)
+	}
+	series := prices(s.services.Poller.History(id))
+	if len(series) < 2 {
+		candles, err := s.services.Candles.Candles(id, s.services.CandleInterval, defaultCandleLimit)
+		if err == nil {
+			series = series[:0]
+			for _, c := range candles {
+				series = append(series, c.Close)
+			}
+		}
+	}
+	currency := s.services.Poller.Currency()
+	decimals := format.PriceDecimals(latest.CurrentPrice, format.NewHints(currency).Decimals)
+	return ogimage.Card{
+		Title:    cmp.Or(latest.Name, id),
+		Subtitle: strings.ToUpper(latest.Symbol) + " - 24h",
+		Price:    latest.CurrentPrice.StringFixed(int32(decimals)) + " " + strings.ToUpper(string(currency)),
+		Change:   latest.PriceChange24h,
+		Series:   series,
+		Footer:   previewFooter,
+	}, nil
+}
+
+// handlePortfolioPreview renders the Open Graph card of the ledger portfolio.
+// The card is public, so it shows only percentages, as privacy mode does: the
+// 24h change and the share of the largest holdings, never amounts.
+func (s *Server) handlePortfolioPreview(w http.ResponseWriter, r *http.Request) {
+	card, err := s.portfolioCard()
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writePreview(w, card)
+}
+
+// errNoPortfolio is returned for portfolio previews without a loaded ledger
+var errNoPortfolio = errors.New("no portfolio is loaded")
+
+func (s *Server) portfolioCard() (ogimage.Card, error) {
+	if len(s.services.Holdings) == 0 {
+		return ogimage.Card{}, errNoPortfolio
+	}
+	ids := make([]string, len(s.services.Holdings))
+	for i, h := range s.services.Holdings {
+		ids[i] = h.CryptoID
+	}
+	prices, _ := s.services.Poller.Prices(ids)
+	byID := make(map[string]models.CryptoPrice, len(prices))
+	for _, p := range prices {
+		byID[p.ID] = p
+	}
+
+	type share struct {
+		symbol string
+		value  decimal.Decimal
+	}
+	var shares []share
+	var total decimal.Decimal
+	for _, h := range s.services.Holdings {
+		p, ok := byID[h.CryptoID]
+		if !ok {
+			continue
+		}
+		value := h.Quantity.Mul(p.CurrentPrice)
+		total = total.Add(value)
+		shares = append(shares, share{symbol: widgetPrice(p).Symbol, value: value})
+	}
+	slices.SortStableFunc(shares, func(a, b share) int { return b.value.Cmp(a.value) })
+	var allocation []string
+	if total.IsPositive() {
+		for _, sh := range shares[:min(widgetTop, len(shares))] {
+			allocation = append(allocation, sh.symbol+" "+sh.value.Div(total).Mul(decimal.NewFromInt(100)).StringFixed(0)+"%")
+		}
+	}
+
+	return ogimage.Card{
+		Title:    "Portfolio",
+		Subtitle: "Allocation - 24h",
+		Price:    strings.Join(allocation, " "),
+		Change:   s.widgetSummary(true).Change24hPct,
+		Footer:   previewFooter,
+	}, nil
+}
+
+func writePreview(w http.ResponseWriter, card ogimage.Card) {
+	var buf bytes.Buffer
+	if err := ogimage.Render(&buf, card); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	// Chat apps cache previews themselves; a short lifetime keeps prices recent
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.Write(buf.Bytes())
+}
+
+func prices(points []models.PricePoint) []float64 {
+	series := make([]float64, len(points))
+	for i, p := range points {
+		series[i] = p.Price
+	}
+	return series
+}
+
+// handleIndex serves the dashboard page. Links to a chart, a coin or the
+// portfolio get Open Graph tags pointing at their preview card, so chat apps
+// unfurl them.
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	page, err := fs.ReadFile(webAssets, "web/index.html")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if tags := s.previewTags(r); tags != "" {
+		page = bytes.Replace(page, []byte("</head>"), []byte(tags+"</head>"), 1)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(page)
+}
+
+// previewTags returns the Open Graph tags of a shared link, or nothing when
+// the link has no preview
+func (s *Server) previewTags(r *http.Request) string {
+	var title, image string
+	query := r.URL.Query()
+	switch {
+	case query.Get("chart") != "":
+		c, err := s.services.Charts.Get(query.Get("chart"))
+		if err != nil {
+			return ""
+		}
+		title, image = cmp.Or(c.Name, c.CryptoID), "/og/charts/"+url.PathEscape(c.ID)+".png"
+	case query.Get("coin") != "":
+		latest, ok := s.services.Poller.Latest(query.Get("coin"))
+		if !ok {
+			return ""
+		}
+		title, image = cmp.Or(latest.Name, latest.ID), "/og/coins/"+url.PathEscape(latest.ID)+".png"
+	case query.Has("portfolio"):
+		if len(s.services.Holdings) == 0 {
+			return ""
+		}
+		title, image = "Portfolio", "/og/portfolio.png"
+	default:
+		return ""
+	}
+
+	// Crawlers need absolute URLs
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	base := scheme + "://" + r.Host
+	var b strings.Builder
+	for _, tag := range [][2]string{
+		{"og:title", title + " - " + previewFooter},
+		{"og:type", "website"},
+		{"og:url", base + r.URL.RequestURI()},
+		{"og:image", base + image},
+		{"og:image:width", fmt.Sprint(ogimage.Width)},
+		{"og:image:height", fmt.Sprint(ogimage.Height)},
+	} {
+		fmt.Fprintf(&b, "  <meta property=\"%s\" content=\"%s\">\n", tag[0], html.EscapeString(tag[1]))
+	}
+	b.WriteString("  <meta name=\"twitter:card\" content=\"summary_large_image\">\n")
+	return b.String()
+}
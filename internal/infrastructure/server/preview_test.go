@@ -0,0 +1,87 @@
+package server
+
+import (
+	"image/png"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/export"
+	"crypto-dashboard/internal/infrastructure/ogimage"
+)
+
+func TestPreviews(t *testing.T) {
+	s := newTestServer()
+	s.services.Poller.PollOnce()
+	created, err := s.services.Charts.Create(models.ChartConfig{Name: "BTC cycle", CryptoID: "bitcoin"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, path := range []string{"/og/coins/bitcoin.png", "/og/charts/" + created.ID + ".png"} {
+		rec := do(t, s, http.MethodGet, path, "")
+		if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" {
+			t.Fatalf("GET %s: expected a PNG, got %d: %s", path, rec.Code, rec.Body.String())
+		}
+		if cfg, err := png.DecodeConfig(rec.Body); err != nil || cfg.Width != ogimage.Width {
+			t.Errorf("GET %s: expected an Open Graph card, got %+v (%v)", path, cfg, err)
+		}
+	}
+	for _, path := range []string{"/og/coins/dogecoin.png", "/og/charts/missing.png", "/og/coins/bitcoin.jpg"} {
+		if rec := do(t, s, http.MethodGet, path, ""); rec.Code != http.StatusNotFound {
+			t.Errorf("GET %s: expected 404, got %d", path, rec.Code)
+		}
+	}
+}
+
+func TestIndexPreviewTags(t *testing.T) {
+	s := newTestServer()
+	s.services.Poller.PollOnce()
+	created, _ := s.services.Charts.Create(models.ChartConfig{Name: "BTC <cycle>", CryptoID: "bitcoin"})
+
+	rec := do(t, s, http.MethodGet, "/?chart="+created.ID, "")
+	body := rec.Body.String()
+	if rec.Code != http.StatusOK || !strings.Contains(body, `<meta property="og:image" content="http://example.com/og/charts/`+created.ID+`.png">`) {
+		t.Fatalf("Expected the chart preview tag, got %d: %s", rec.Code, body)
+	}
+	if !strings.Contains(body, `content="BTC &lt;cycle&gt; - Crypto Dashboard"`) {
+		t.Error("Expected the escaped chart name as title")
+	}
+	if body := do(t, s, http.MethodGet, "/?coin=bitcoin", "").Body.String(); !strings.Contains(body, "/og/coins/bitcoin.png") {
+		t.Error("Expected the coin preview tag")
+	}
+	if body := do(t, s, http.MethodGet, "/", "").Body.String(); strings.Contains(body, "og:image") || !strings.Contains(body, "</html>") {
+		t.Error("Expected the plain dashboard without a shared link")
+	}
+}
+
+func TestPortfolioPreview(t *testing.T) {
+	s := newTestServer()
+	s.services.Poller.PollOnce()
+	if rec := do(t, s, http.MethodGet, "/og/portfolio.png", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without a ledger, got %d", rec.Code)
+	}
+	if body := do(t, s, http.MethodGet, "/?portfolio", "").Body.String(); strings.Contains(body, "og:image") {
+		t.Error("Expected no preview tags without a ledger")
+	}
+
+	s.services.Holdings = []export.Holding{{CryptoID: "bitcoin", Quantity: decimal.NewFromInt(2)}}
+	card, err := s.portfolioCard()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The card is public: the allocation is shown, the 110000 held is not
+	if card.Price != "bitcoin 100%" || strings.Contains(card.Title+card.Subtitle+card.Price, "110") {
+		t.Errorf("Expected percentages only, got %+v", card)
+	}
+	rec := do(t, s, http.MethodGet, "/og/portfolio.png", "")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("Expected a PNG, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if body := do(t, s, http.MethodGet, "/?portfolio", "").Body.String(); !strings.Contains(body, "http://example.com/og/portfolio.png") {
+		t.Error("Expected the portfolio preview tag")
+	}
+}
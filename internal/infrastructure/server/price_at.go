@@ -0,0 +1,44 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"crypto-dashboard/internal/application/coins"
+	"crypto-dashboard/internal/application/pricehistory"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// handlePriceAt returns the daily price of a coin on the date query parameter
+func (s *Server) handlePriceAt(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	date, err := timeParam(r, "date")
+	if err != nil || date.IsZero() || date.After(s.services.Clock.Now()) {
+		writeError(w, http.StatusBadRequest, errInvalidParam("date"))
+		return
+	}
+	currency := s.services.Poller.Currency()
+	if v := r.URL.Query().Get("currency"); v != "" {
+		if currency, err = models.ParseCurrency(v); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	price, err := s.services.PriceHistory.PriceAt(id, currency, date)
+	if errors.Is(err, coins.ErrNotFound) || errors.Is(err, pricehistory.ErrNoPrice) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":       id,
+		"date":     pricehistory.Day(date).Format(time.DateOnly),
+		"currency": currency,
+		"price":    price,
+	})
+}
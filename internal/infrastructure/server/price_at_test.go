@@ -0,0 +1,51 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/pricehistory"
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubHistory struct{}
+
+func (stubHistory) GetPriceAt(cryptoID string, date time.Time, currency models.Currency) (float64, error) {
+	if cryptoID != "bitcoin" {
+		return 0, pricehistory.ErrNoPrice
+	}
+	if currency == models.EUR {
+		return 62000, nil
+	}
+	return 68000, nil
+}
+
+func TestHandlePriceAt(t *testing.T) {
+	s := newTestServer()
+
+	rec := do(t, s, http.MethodGet, "/api/v1/coins/bitcoin/price-at?date=2024-03-05&currency=eur", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Date  string  `json:"date"`
+		Price float64 `json:"price"`
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if body.Date != "2024-03-05" || body.Price != 62000 {
+		t.Errorf("Unexpected response: %+v", body)
+	}
+
+	for path, want := range map[string]int{
+		"/api/v1/coins/bitcoin/price-at":                             http.StatusBadRequest,
+		"/api/v1/coins/bitcoin/price-at?date=2999-01-01":             http.StatusBadRequest,
+		"/api/v1/coins/bitcoin/price-at?date=2024-03-05&currency=x1": http.StatusBadRequest,
+		"/api/v1/coins/newcoin/price-at?date=2024-03-05":             http.StatusNotFound,
+	} {
+		if rec := do(t, s, http.MethodGet, path, ""); rec.Code != want {
+			t.Errorf("%s: expected status %d, got %d", path, want, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"crypto-dashboard/internal/application/backtest"
+	"crypto-dashboard/internal/application/format"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// handlePrices returns the tracked coins, or the coins listed in the ids
+// query parameter (served from the poller cache when they are tracked),
+// arranged by the session's pins and manual order. With ledger holdings,
+// allocation gives each held or targeted coin's share of the portfolio and
+// its drift from the target. Stale is set when any price is a cached value
+// the last poll failed to refresh; inactive lists the tracked coins the
+// provider stopped updating.
+func (s *Server) handlePrices(w http.ResponseWriter, r *http.Request) {
+	prices := s.services.Poller.Snapshot()
+	if ids := r.URL.Query().Get("ids"); ids != "" {
+		var err error
+		prices, err = s.services.Poller.Prices(strings.Split(ids, ","))
+		if err != nil && len(prices) == 0 {
+			w.Header().Set(TierHeader, string(TierUnavailable))
+			writeUpstreamError(w, err)
+			return
+		}
+	}
+
+	stale := slices.ContainsFunc(prices, func(p models.CryptoPrice) bool { return p.Stale })
+	tier := TierLive
+	switch {
+	case len(prices) == 0:
+		tier = TierUnavailable
+	case stale:
+		tier = TierStale
+	}
+	if !s.acceptTier(w, EndpointPrices, tier) {
+		return
+	}
+	// Prices stay in the default order when the preferences cannot be loaded
+	order, err := s.services.Preferences.Order(sessionOwner(r))
+	if err != nil {
+		order = models.DefaultCoinOrder(sessionOwner(r))
+	}
+	currency := s.services.Poller.Currency()
+	body := map[string]any{
+		"currency": currency,
+		"prices":   order.Sort(prices),
+		"pinned":   order.Pinned,
+		"stale":    stale,
+		"inactive": s.services.Poller.Inactive(),
+		"format":   format.ForPrices(currency, prices),
+		"meta":     Meta{Tier: tier},
+	}
+	if allocation, ok := s.allocation(); ok {
+		body["allocation"] = allocation
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+// allocation weighs the holdings at the poller's prices. It is unavailable
+// without holdings or while none of them is priced.
+func (s *Server) allocation() (map[string]backtest.Allocation, bool) {
+	if len(s.services.Holdings) == 0 {
+		return nil, false
+	}
+	values := make(map[string]float64)
+	for _, h := range s.pricedHoldings() {
+		values[h.CryptoID] += h.Value().InexactFloat64()
+	}
+	allocation, err := backtest.Allocations(values, s.services.Targets, s.services.DriftBand)
+	return allocation, err == nil
+}
+
+// handleGlobal returns the global market overview with the compact notation of
+// its totals. The overview is cached between scheduled refreshes.
+func (s *Server) handleGlobal(w http.ResponseWriter, r *http.Request) {
+	global, err := s.services.Market.Global()
+	if err != nil {
+		w.Header().Set(TierHeader, string(TierUnavailable))
+		writeUpstreamError(w, err)
+		return
+	}
+	tier := TierCached
+	if global.Stale {
+		tier = TierStale
+	}
+	if !s.acceptTier(w, EndpointGlobal, tier) {
+		return
+	}
+	hints := format.NewHints(global.Currency)
+	hints.AddCompact("total_market_cap", global.TotalMarketCap)
+	hints.AddCompact("total_volume", global.TotalVolume)
+	writeJSON(w, http.StatusOK, struct {
+		models.GlobalMarket
+		Format format.Hints `json:"format"`
+		Meta   Meta         `json:"meta"`
+	}{global, hints, Meta{Tier: tier}})
+}
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/backtest"
+	"crypto-dashboard/internal/application/format"
+	"crypto-dashboard/internal/application/poller"
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/api"
+	"crypto-dashboard/internal/infrastructure/export"
+	"crypto-dashboard/internal/infrastructure/metrics"
+)
+
+func TestHandlePrices(t *testing.T) {
+	s := newTestServer()
+	s.services.Poller.PollOnce()
+
+	rec := do(t, s, http.MethodGet, "/api/v1/prices", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var body struct {
+		Currency models.Currency      `json:"currency"`
+		Prices   []models.CryptoPrice `json:"prices"`
+		Format   format.Hints         `json:"format"`
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if body.Currency != models.USD || len(body.Prices) != 1 || !body.Prices[0].CurrentPrice.Equal(decimal.NewFromInt(55000)) {
+		t.Errorf("Unexpected response: %+v", body)
+	}
+	if body.Format.Symbol != "$" || body.Format.Decimals != 2 || body.Format.PriceDecimals != nil {
+		t.Errorf("Unexpected format hints: %+v", body.Format)
+	}
+}
+
+func TestHandlePrices_Allocation(t *testing.T) {
+	services := newTestServer().services
+	services.Poller = poller.New(stubPrices{"bitcoin": 50000, "ethereum": 2500}, time.Minute, models.USD, []string{"bitcoin", "ethereum"})
+	services.Poller.PollOnce()
+	s := New(0, services)
+
+	var body struct {
+		Allocation map[string]backtest.Allocation `json:"allocation"`
+	}
+	json.NewDecoder(do(t, s, http.MethodGet, "/api/v1/prices", "").Body).Decode(&body)
+	if body.Allocation != nil {
+		t.Errorf("Expected no allocation without holdings, got %+v", body.Allocation)
+	}
+
+	// 35000 in bitcoin and 15000 in ether against a 60/40 target
+	services.Holdings = []export.Holding{
+		{CryptoID: "bitcoin", Quantity: decimal.RequireFromString("0.7")},
+		{CryptoID: "ethereum", Quantity: decimal.NewFromInt(6)},
+	}
+	services.Targets = map[string]float64{"bitcoin": 60, "ethereum": 40}
+	services.DriftBand = 5
+	s = New(0, services)
+	json.NewDecoder(do(t, s, http.MethodGet, "/api/v1/prices", "").Body).Decode(&body)
+	btc, eth := body.Allocation["bitcoin"], body.Allocation["ethereum"]
+	if math.Abs(btc.Weight-0.7) > 1e-9 || math.Abs(btc.Drift-10) > 1e-9 || !btc.Drifted {
+		t.Errorf("Expected bitcoin to drift 10 points over its target, got %+v", btc)
+	}
+	if math.Abs(eth.Weight-0.3) > 1e-9 || !eth.Drifted {
+		t.Errorf("Expected ether to drift under its target, got %+v", eth)
+	}
+}
+
+func TestHandlePrices_ByIDs(t *testing.T) {
+	s := newTestServer()
+
+	rec := do(t, s, http.MethodGet, "/api/v1/prices?ids=bitcoin", "")
+	var body struct {
+		Prices []models.CryptoPrice `json:"prices"`
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if len(body.Prices) != 1 || body.Prices[0].ID != "bitcoin" {
+		t.Errorf("Expected bitcoin to be fetched on a cache miss, got %+v", body.Prices)
+	}
+}
+
+func TestHandleGlobal(t *testing.T) {
+	s := newTestServer()
+
+	rec := do(t, s, http.MethodGet, "/api/v1/global", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var global struct {
+		models.GlobalMarket
+		Format format.Hints `json:"format"`
+	}
+	json.NewDecoder(rec.Body).Decode(&global)
+	if global.BTCDominance != 52 || global.Currency != models.USD {
+		t.Errorf("Unexpected overview: %+v", global)
+	}
+	if global.Format.Compact["total_market_cap"] != "2.00T" {
+		t.Errorf("Expected the compact market cap, got %+v", global.Format)
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	s := newTestServer()
+	if rec := do(t, s, http.MethodGet, "/metrics", ""); rec.Code == http.StatusOK {
+		t.Error("Expected /metrics to be disabled without a registry")
+	}
+
+	services := s.services
+	services.Metrics = metrics.New()
+	s = New(0, services)
+	rec := do(t, s, http.MethodGet, "/metrics", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "crypto_dashboard_poller_tracked_coins") {
+		t.Errorf("Expected metrics output, got %d", rec.Code)
+	}
+}
+
+type failingPrices struct {
+	prices stubPrices
+	err    error
+}
+
+func (f *failingPrices) FetchCryptoPrices(ids []string, currency models.Currency) ([]models.CryptoPrice, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.prices.FetchCryptoPrices(ids, currency)
+}
+
+func TestHandlePrices_ServesStaleCache(t *testing.T) {
+	s := newTestServer()
+	provider := &failingPrices{prices: stubPrices{"bitcoin": 55000}}
+	s.services.Poller = poller.New(provider, time.Minute, models.USD, []string{"bitcoin"})
+	s.services.Poller.SetLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	s.services.Poller.PollOnce()
+	provider.err = api.ErrCircuitOpen
+	s.services.Poller.PollOnce()
+
+	rec := do(t, s, http.MethodGet, "/api/v1/prices", "")
+	var body struct {
+		Prices []models.CryptoPrice `json:"prices"`
+		Stale  bool                 `json:"stale"`
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if rec.Code != http.StatusOK || !body.Stale || len(body.Prices) != 1 {
+		t.Fatalf("Expected the cached price flagged stale, got %d: %+v", rec.Code, body)
+	}
+	if price := body.Prices[0]; !price.Stale || price.StaleSince == nil || !price.CurrentPrice.Equal(decimal.NewFromInt(55000)) {
+		t.Errorf("Expected the last known bitcoin price with its staleness time, got %+v", price)
+	}
+}
+
+type frozenPrices struct{}
+
+func (frozenPrices) FetchCryptoPrices(ids []string, currency models.Currency) ([]models.CryptoPrice, error) {
+	return []models.CryptoPrice{{ID: "terra-luna", CurrentPrice: decimal.RequireFromString("0.0001"), Currency: currency, LastUpdated: "2022-05-13T00:00:00Z"}}, nil
+}
+
+func TestHandlePrices_FlagsInactiveCoins(t *testing.T) {
+	s := newTestServer()
+	s.services.Poller = poller.New(frozenPrices{}, time.Minute, models.USD, []string{"terra-luna"})
+	s.services.Poller.SetLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	s.services.Poller.PollOnce()
+
+	rec := do(t, s, http.MethodGet, "/api/v1/prices", "")
+	var body struct {
+		Prices   []models.CryptoPrice `json:"prices"`
+		Inactive []string             `json:"inactive"`
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if len(body.Inactive) != 1 || len(body.Prices) != 1 || !body.Prices[0].Inactive {
+		t.Errorf("Expected the frozen coin flagged inactive, got %+v", body)
+	}
+
+	if rec := do(t, s, http.MethodGet, "/lite?format=text", ""); !strings.Contains(rec.Body.String(), "inactive since 2022-05-13") {
+		t.Errorf("Expected the lite page to spell out the inactive coin, got %s", rec.Body)
+	}
+}
@@ -0,0 +1,22 @@
+package server
+
+import (
+	"net/http"
+
+	"crypto-dashboard/internal/application/projection"
+)
+
+func (s *Server) handleProjection(w http.ResponseWriter, r *http.Request) {
+	var req projection.Request
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := s.services.Projection.Project(req)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
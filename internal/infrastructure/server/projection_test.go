@@ -0,0 +1,44 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/projection"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestHandleProjection(t *testing.T) {
+	s := newTestServer()
+
+	rec := do(t, s, http.MethodPost, "/api/v1/tools/projection", `{"holdings":{"bitcoin":1},"years":2}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 without history, got %d", rec.Code)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, price := range []float64{50000, 51000, 50500, 52000} {
+		s.services.Candles.SaveCandle(time.Hour, models.NewCandle("bitcoin", price, start.Add(time.Duration(i)*time.Hour), time.Hour))
+	}
+
+	rec = do(t, s, http.MethodPost, "/api/v1/tools/projection", `{"holdings":{"bitcoin":1},"years":2,"simulations":200,"seed":3}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var p projection.Projection
+	json.NewDecoder(rec.Body).Decode(&p)
+	if p.InitialValue != 55000 {
+		t.Errorf("Expected initial value 55000, got %f", p.InitialValue)
+	}
+	if len(p.Bands) != 25 || len(p.Bands[0].Values) != len(p.Percentiles) {
+		t.Errorf("Expected 25 bands with every percentile, got %d", len(p.Bands))
+	}
+
+	rec = do(t, s, http.MethodPost, "/api/v1/tools/projection", `{"holdings":{"bitcoin":1},"years":2,"extra":1}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for unknown field, got %d", rec.Code)
+	}
+}
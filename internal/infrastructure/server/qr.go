@@ -0,0 +1,57 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/qrcode"
+)
+
+// handleQR renders any text, typically a share link, as a QR code
+func (s *Server) handleQR(w http.ResponseWriter, r *http.Request) {
+	text := r.URL.Query().Get("text")
+	if text == "" {
+		writeError(w, http.StatusBadRequest, errInvalidParam("text"))
+		return
+	}
+	writeQR(w, r, text)
+}
+
+// handleAddressQR renders the payment URI of a wallet address as a QR code
+func (s *Server) handleAddressQR(w http.ResponseWriter, r *http.Request) {
+	address := strings.TrimSpace(r.URL.Query().Get("address"))
+	if address == "" {
+		writeError(w, http.StatusBadRequest, errInvalidParam("address"))
+		return
+	}
+	writeQR(w, r, models.PaymentURI(r.PathValue("id"), address))
+}
+
+func writeQR(w http.ResponseWriter, r *http.Request, text string) {
+	format, err := qrcode.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidParam("format"))
+		return
+	}
+	scale, err := intParam(r, "scale", qrcode.DefaultScale)
+	if err != nil || scale > qrcode.MaxScale {
+		writeError(w, http.StatusBadRequest, errInvalidParam("scale"))
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := qrcode.Render(&buf, text, format, scale); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, qrcode.ErrTooLong) {
+			status = http.StatusBadRequest
+		}
+		writeError(w, status, err)
+		return
+	}
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Write(buf.Bytes())
+}
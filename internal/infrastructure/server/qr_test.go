@@ -0,0 +1,50 @@
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestHandleQR(t *testing.T) {
+	s := newTestServer()
+
+	rec := do(t, s, http.MethodGet, "/api/v1/qr?text="+url.QueryEscape("https://example.com/share/abc"), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "image/png" {
+		t.Errorf("Expected a PNG, got %s", ct)
+	}
+	if !strings.HasPrefix(rec.Body.String(), "\x89PNG") {
+		t.Error("Expected the PNG signature")
+	}
+
+	for _, path := range []string{
+		"/api/v1/qr",
+		"/api/v1/qr?text=x&format=gif",
+		"/api/v1/qr?text=x&scale=100",
+		"/api/v1/qr?text=" + strings.Repeat("a", 2000),
+	} {
+		if rec := do(t, s, http.MethodGet, path, ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", path, rec.Code)
+		}
+	}
+}
+
+func TestHandleAddressQR(t *testing.T) {
+	s := newTestServer()
+
+	rec := do(t, s, http.MethodGet, "/api/v1/coins/bitcoin/qr?address=bc1qexample&format=svg", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "image/svg+xml" {
+		t.Errorf("Expected an SVG, got %s", ct)
+	}
+
+	if rec := do(t, s, http.MethodGet, "/api/v1/coins/bitcoin/qr", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without an address, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,9 @@
+package server
+
+import "net/http"
+
+// handleDataQuality returns how far the secondary provider's prices were from
+// the primary's on the mirrored reads
+func (s *Server) handleDataQuality(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.services.Shadow.Report())
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/shadow"
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubQuotes struct{}
+
+func (stubQuotes) Name() string { return "binance" }
+
+func (stubQuotes) Quote(context.Context, string, models.Currency) (models.SourceQuote, error) {
+	return models.SourceQuote{Price: decimal.NewFromInt(56100)}, nil
+}
+
+type stubSymbols struct{}
+
+func (stubSymbols) Symbol(string) (string, bool) { return "BTC", true }
+
+func TestDataQuality(t *testing.T) {
+	if rec := do(t, newTestServer(), http.MethodGet, "/api/v1/admin/data-quality", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without shadowing, got %d", rec.Code)
+	}
+
+	monitor := shadow.NewMonitor(stubQuotes{}, stubSymbols{}, 1, 1, time.Second)
+	monitor.Mirror(context.Background(), []models.CryptoPrice{{ID: "bitcoin", CurrentPrice: decimal.NewFromInt(55000), Currency: models.USD}})
+	services := newTestServer().services
+	services.Shadow = monitor
+	rec := do(t, New(0, services), http.MethodGet, "/api/v1/admin/data-quality", "")
+	var report shadow.Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("Expected the report, got %d: %s", rec.Code, rec.Body)
+	}
+	if report.Secondary != "binance" || report.Discrepancies != 1 || len(report.Recent) != 1 || report.Recent[0].DeviationPct != 2 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"crypto-dashboard/internal/application/coins"
+	"crypto-dashboard/internal/application/search"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		writeError(w, http.StatusBadRequest, errInvalidParam("q"))
+		return
+	}
+
+	results, err := s.services.Coins.Search(query)
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"query": query,
+		"coins": results,
+	})
+}
+
+// Hits returned per kind by /api/v1/search/all
+const (
+	defaultSearchGroupLimit = 5
+	maxSearchGroupLimit     = 50
+)
+
+// searchAllResponse is the grouped full-text search result. Partial is set
+// when a source could not be indexed.
+type searchAllResponse struct {
+	search.Results
+	Partial bool `json:"partial"`
+}
+
+// handleSearchAll searches the text of calendar events, incidents and alerts
+// visible to the session, grouped by kind
+func (s *Server) handleSearchAll(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		writeError(w, http.StatusBadRequest, errInvalidParam("q"))
+		return
+	}
+	limit, err := intParam(r, "limit", defaultSearchGroupLimit)
+	if err != nil || limit > maxSearchGroupLimit {
+		writeError(w, http.StatusBadRequest, errInvalidParam("limit"))
+		return
+	}
+
+	results, err := s.services.Search.Search(sessionOwner(r), query, limit)
+	if err != nil {
+		if results.Total == 0 {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		s.services.Logger.Warn("partial search results", "error", err)
+	}
+	writeJSON(w, http.StatusOK, searchAllResponse{Results: results, Partial: err != nil})
+}
+
+func (s *Server) handleCoinInfo(w http.ResponseWriter, r *http.Request) {
+	info, err := s.services.Coins.Info(r.PathValue("id"))
+	if errors.Is(err, coins.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		w.Header().Set(TierHeader, string(TierUnavailable))
+		writeUpstreamError(w, err)
+		return
+	}
+	// Metadata is cached for a day; the bundled coin list stands in while the provider fails
+	tier := TierCached
+	if info.Stale {
+		tier = TierStale
+	}
+	if !s.acceptTier(w, EndpointCoinInfo, tier) {
+		return
+	}
+	writeJSON(w, http.StatusOK, struct {
+		models.CoinInfo
+		Meta Meta `json:"meta"`
+	}{info, Meta{Tier: tier}})
+}
+
+// explorerLinks is an explorer with its templates resolved for the requested address or transaction
+type explorerLinks struct {
+	models.Explorer
+	AddressLink string `json:"address_link,omitempty"`
+	TxLink      string `json:"tx_link,omitempty"`
+}
+
+// handleExplorers returns the explorer URL templates of a coin, resolved for
+// the optional address and tx query parameters
+func (s *Server) handleExplorers(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	explorers := models.ExplorersFor(id)
+	if len(explorers) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no explorers known for %s", id))
+		return
+	}
+
+	address, tx := r.URL.Query().Get("address"), r.URL.Query().Get("tx")
+	links := make([]explorerLinks, len(explorers))
+	for i, e := range explorers {
+		links[i] = explorerLinks{Explorer: e}
+		if address != "" {
+			links[i].AddressLink = e.AddressLink(address)
+		}
+		if tx != "" {
+			links[i].TxLink = e.TxLink(tx)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":        id,
+		"explorers": links,
+	})
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/coins"
+	"crypto-dashboard/internal/application/search"
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubDirectory struct{}
+
+func (stubDirectory) Search(query string) ([]models.SearchResult, error) {
+	return []models.SearchResult{
+		{ID: "solana-name-service", Symbol: "SNS", Name: "Solana Name Service"},
+		{ID: "solana", Symbol: "SOL", Name: "Solana", MarketCapRank: 5},
+	}, nil
+}
+
+func (stubDirectory) CoinInfo(id string) (models.CoinInfo, error) {
+	if id != "solana" {
+		return models.CoinInfo{}, fmt.Errorf("%w: %s", coins.ErrNotFound, id)
+	}
+	return models.CoinInfo{ID: "solana", Symbol: "sol", Name: "Solana", GenesisDate: "2020-03-16"}, nil
+}
+
+func TestHandleSearch(t *testing.T) {
+	s := newTestServer()
+
+	rec := do(t, s, http.MethodGet, "/api/v1/search?q=sol", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Coins []models.SearchResult `json:"coins"`
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if len(body.Coins) != 2 || body.Coins[0].ID != "solana" {
+		t.Errorf("Expected the exact ticker match first, got %+v", body.Coins)
+	}
+
+	if rec := do(t, s, http.MethodGet, "/api/v1/search", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without a query, got %d", rec.Code)
+	}
+}
+
+func TestHandleCoinInfo(t *testing.T) {
+	s := newTestServer()
+
+	rec := do(t, s, http.MethodGet, "/api/v1/coins/solana/info", "")
+	var info models.CoinInfo
+	json.NewDecoder(rec.Body).Decode(&info)
+	if rec.Code != http.StatusOK || info.GenesisDate != "2020-03-16" {
+		t.Errorf("Unexpected response %d: %+v", rec.Code, info)
+	}
+
+	if rec := do(t, s, http.MethodGet, "/api/v1/coins/nope/info", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown coin, got %d", rec.Code)
+	}
+}
+
+func TestHandleExplorers(t *testing.T) {
+	s := newTestServer()
+
+	rec := do(t, s, http.MethodGet, "/api/v1/coins/ethereum/explorers?address=0xabc&tx=0xdef", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Explorers []struct {
+			Name        string `json:"name"`
+			TxURL       string `json:"tx_url"`
+			AddressLink string `json:"address_link"`
+			TxLink      string `json:"tx_link"`
+		} `json:"explorers"`
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if len(body.Explorers) == 0 {
+		t.Fatal("Expected ethereum explorers")
+	}
+	e := body.Explorers[0]
+	if e.Name != "Etherscan" || e.TxURL != "https://etherscan.io/tx/{tx}" ||
+		e.AddressLink != "https://etherscan.io/address/0xabc" || e.TxLink != "https://etherscan.io/tx/0xdef" {
+		t.Errorf("Unexpected explorer: %+v", e)
+	}
+
+	if rec := do(t, s, http.MethodGet, "/api/v1/coins/some-token/explorers", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a coin without explorers, got %d", rec.Code)
+	}
+}
+
+func TestHandleSearchAll(t *testing.T) {
+	if rec := do(t, newTestServer(), http.MethodGet, "/api/v1/search/all?q=unlock", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without the search service, got %d", rec.Code)
+	}
+
+	services := newTestServer().services
+	docs := []search.Document{
+		{Kind: search.KindEvent, ID: "e1", Title: "ARB unlock"},
+		{Kind: search.KindEvent, ID: "e2", Owner: "alice", Title: "Sell before the unlock"},
+		{Kind: search.KindIncident, ID: "i1", Title: "data quality", Text: "Prices wrong around the unlock"},
+	}
+	services.Search = search.NewService(time.Minute, search.SourceFunc(func() ([]search.Document, error) { return docs, nil }))
+	s := New(0, services)
+
+	rec := doAs(t, s, "bob", http.MethodGet, "/api/v1/search/all?q=unlock&limit=1", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body searchAllResponse
+	json.NewDecoder(rec.Body).Decode(&body)
+	if body.Total != 2 || len(body.Groups) != 2 || body.Partial {
+		t.Fatalf("Expected the two shared documents in two groups, got %+v", body)
+	}
+	if g := body.Groups[0]; g.Kind != search.KindEvent || g.Hits[0].ID != "e1" {
+		t.Errorf("Expected the titled event first, got %+v", g)
+	}
+
+	rec = doAs(t, s, "alice", http.MethodGet, "/api/v1/search/all?q=unlock&limit=1", "")
+	json.NewDecoder(rec.Body).Decode(&body)
+	if body.Total != 3 || body.Groups[0].Total != 2 || len(body.Groups[0].Hits) != 1 {
+		t.Errorf("Expected alice to also find her own event, got %+v", body)
+	}
+
+	for _, path := range []string{"/api/v1/search/all", "/api/v1/search/all?q=x&limit=0", "/api/v1/search/all?q=x&limit=500"} {
+		if rec := do(t, s, http.MethodGet, path, ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", path, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"crypto-dashboard/internal/application/analytics"
+)
+
+func (s *Server) handleSeasonality(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	// Seasonality needs the full stored history, not just the recent window
+	candles, err := s.services.Candles.Candles(id, s.services.CandleInterval, 0)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if len(candles) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no stored history for %s", id))
+		return
+	}
+
+	seasonality, err := analytics.ComputeSeasonality(candles)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":         id,
+		"from":       candles[0].OpenTime,
+		"to":         candles[len(candles)-1].CloseTime,
+		"by_month":   seasonality.ByMonth,
+		"by_weekday": seasonality.ByWeekday,
+	})
+}
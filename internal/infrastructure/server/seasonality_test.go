@@ -0,0 +1,45 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/analytics"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestHandleSeasonality(t *testing.T) {
+	s := newTestServer()
+
+	rec := do(t, s, http.MethodGet, "/api/v1/coins/bitcoin/seasonality", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 without history, got %d", rec.Code)
+	}
+
+	start := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	for i, price := range []float64{100, 104, 110} {
+		c := models.NewCandle("bitcoin", price, start.Add(time.Duration(i)*time.Hour), time.Hour)
+		c.Update(price + 1)
+		s.services.Candles.SaveCandle(time.Hour, c)
+	}
+
+	rec = do(t, s, http.MethodGet, "/api/v1/coins/bitcoin/seasonality", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var body struct {
+		ByMonth   []analytics.SeasonalBucket `json:"by_month"`
+		ByWeekday []analytics.SeasonalBucket `json:"by_weekday"`
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if len(body.ByMonth) != 12 || len(body.ByWeekday) != 7 {
+		t.Fatalf("Expected 12 months and 7 weekdays, got %d and %d", len(body.ByMonth), len(body.ByWeekday))
+	}
+	october := body.ByMonth[time.October-1]
+	if october.Samples != 1 || october.AverageReturn < 10.99 || october.AverageReturn > 11.01 {
+		t.Errorf("Expected one October sample returning 11%%, got %+v", october)
+	}
+}
@@ -0,0 +1,386 @@
+// Package server exposes the dashboard application services over HTTP
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"crypto-dashboard/internal/application/alerts"
+	"crypto-dashboard/internal/application/analytics"
+	"crypto-dashboard/internal/application/backtest"
+	"crypto-dashboard/internal/application/calendar"
+	"crypto-dashboard/internal/application/candles"
+	"crypto-dashboard/internal/application/chart"
+	"crypto-dashboard/internal/application/coins"
+	"crypto-dashboard/internal/application/compare"
+	"crypto-dashboard/internal/application/etf"
+	"crypto-dashboard/internal/application/events"
+	"crypto-dashboard/internal/application/icons"
+	"crypto-dashboard/internal/application/incident"
+	"crypto-dashboard/internal/application/manifest"
+	"crypto-dashboard/internal/application/market"
+	"crypto-dashboard/internal/application/optin"
+	"crypto-dashboard/internal/application/poller"
+	"crypto-dashboard/internal/application/preferences"
+	"crypto-dashboard/internal/application/pricehistory"
+	"crypto-dashboard/internal/application/projection"
+	"crypto-dashboard/internal/application/risk"
+	"crypto-dashboard/internal/application/search"
+	"crypto-dashboard/internal/application/shadow"
+	"crypto-dashboard/internal/application/status"
+	"crypto-dashboard/internal/application/telemetry"
+	"crypto-dashboard/internal/application/theme"
+	"crypto-dashboard/internal/application/universe"
+	"crypto-dashboard/internal/application/watchlist"
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/infrastructure/api"
+	"crypto-dashboard/internal/infrastructure/export"
+	"crypto-dashboard/internal/infrastructure/metrics"
+	"crypto-dashboard/internal/infrastructure/websocket"
+)
+
+// Services bundles the application services exposed over HTTP
+type Services struct {
+	Poller         *poller.Poller
+	Risk           *risk.Service
+	Watchlists     *watchlist.Service
+	Alerts         *alerts.Engine
+	Projection     *projection.Service
+	Backtest       *backtest.Service
+	Calendar       *calendar.Service
+	Themes         *theme.Service
+	Preferences    *preferences.Service
+	Charts         *chart.Service
+	OptIns         *optin.Service
+	Incidents      *incident.Service
+	Coins          *coins.Service
+	PriceHistory   *pricehistory.Service
+	Market         *market.Service
+	Candles        candles.Repository
+	CandleInterval time.Duration
+	// Events is optional; /api/v1/stream and /api/v1/socket are only served when it is set
+	Events *events.Bus
+	// Socket configures compression and message size of /api/v1/socket; the
+	// server sets the origin check and idle timeout
+	Socket websocket.Options
+	// Compare is optional; /api/v1/compare/{symbol} is only served when it is set
+	Compare *compare.Service
+	// ETF is optional; /api/v1/etf/flows is only served when it is set
+	ETF *etf.Service
+	// Inception is optional; history with range=max is only served when it is set
+	Inception *pricehistory.Inception
+	// Universes is optional; /api/v1/universes is only served when it is set
+	Universes *universe.Service
+	// Manifest is optional; /api/v1/manifest is only served when it is set
+	Manifest *manifest.Service
+	// Search is optional; /api/v1/search/all is only served when it is set
+	Search *search.Service
+	// Status is optional; the public /status page is only served when it is set
+	Status *status.Tracker
+	// Indicators is optional; it serves the latest values of the default indicators
+	Indicators *analytics.Tracker
+	// Holdings are the open ledger positions, without prices, totalled by /lite
+	// and weighed in /api/v1/prices; optional
+	Holdings []export.Holding
+	// Targets are the target allocation weights of the holdings by coin ID;
+	// coins whose share is further than DriftBand percentage points from
+	// their target are flagged
+	Targets   map[string]float64
+	DriftBand float64
+	// Icons is optional; /assets/icons/{id}.png is only served when it is set
+	Icons *icons.Service
+	// Usage is optional; /api/v1/admin/usage is only served when it is set
+	Usage *api.Usage
+	// Telemetry is optional; requests are counted per route and
+	// /api/v1/admin/telemetry is only served when it is set
+	Telemetry *telemetry.Collector
+	// Shadow is optional; /api/v1/admin/data-quality is only served when it is set
+	Shadow *shadow.Monitor
+	// Tiers is the worst data tier each endpoint may serve; without it every
+	// endpoint serves whatever it has
+	Tiers TierPolicy
+	// Breaker is optional; its state is reported by /healthz when it is set
+	Breaker *api.Breaker
+	// Clock tells the time of the status page, /lite and the calendar feed;
+	// the system clock is used when it is nil
+	Clock clock.Clock
+	// Auth is optional; without it every endpoint is open
+	Auth *Auth
+	// Metrics is optional; /metrics is only served when it is set
+	Metrics *metrics.Metrics
+	// Logger records every request; the default logger is used when it is nil
+	Logger *slog.Logger
+}
+
+// Server is the dashboard HTTP API
+type Server struct {
+	port     int
+	services Services
+	mux      *http.ServeMux
+	// done is closed on shutdown to end the open event streams, which would
+	// otherwise hold the graceful shutdown until its timeout
+	done     chan struct{}
+	shutdown sync.Once
+}
+
+// New creates a server listening on the given port and registers all routes
+func New(port int, services Services) *Server {
+	if services.Logger == nil {
+		services.Logger = slog.Default()
+	}
+	if services.Clock == nil {
+		services.Clock = clock.Real
+	}
+	s := &Server{
+		port:     port,
+		services: services,
+		mux:      http.NewServeMux(),
+		done:     make(chan struct{}),
+	}
+	s.routes()
+	return s
+}
+
+// routes registers every endpoint on the mux
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	s.mux.Handle("GET /", webHandler())
+	s.mux.HandleFunc("GET /{$}", s.handleIndex)
+	s.mux.HandleFunc("GET /og/coins/{file}", s.handleCoinPreview)
+	s.mux.HandleFunc("GET /og/charts/{file}", s.handleChartPreview)
+	s.mux.HandleFunc("GET /og/portfolio.png", s.handlePortfolioPreview)
+	s.mux.HandleFunc("GET /lite", s.handleLite)
+	if s.services.Icons != nil {
+		s.mux.HandleFunc("GET /assets/icons/{file}", s.handleIcon)
+	}
+	s.mux.HandleFunc("GET /api/ha/sensors", s.handleHASensors)
+	if s.services.Metrics != nil {
+		s.mux.Handle("GET /metrics", s.services.Metrics.Handler())
+	}
+
+	if s.services.Events != nil {
+		s.mux.HandleFunc("GET /api/v1/stream", s.handleStream)
+		s.mux.HandleFunc("GET /api/v1/socket", s.handleSocket)
+	}
+	if s.services.Auth != nil {
+		s.mux.HandleFunc("POST /api/v1/session", s.handleLogin)
+		s.mux.HandleFunc("GET /api/v1/session", s.handleSession)
+		s.mux.HandleFunc("DELETE /api/v1/session", s.handleLogout)
+	}
+	if s.services.Status != nil {
+		s.mux.HandleFunc("GET /status", s.handleStatus)
+	}
+	if s.services.ETF != nil {
+		s.mux.HandleFunc("GET /api/v1/etf/flows", s.handleETFSummaries)
+		s.mux.HandleFunc("GET /api/v1/etf/flows/{asset}", s.handleETFFlows)
+	}
+	if s.services.Universes != nil {
+		s.mux.HandleFunc("GET /api/v1/universes", s.handleListUniverses)
+		s.mux.HandleFunc("GET /api/v1/universes/{name}", s.handleUniverse)
+	}
+	if s.services.Manifest != nil {
+		s.mux.HandleFunc("GET /api/v1/manifest", s.handleExportManifest)
+		s.mux.HandleFunc("PUT /api/v1/manifest", s.handleApplyManifest)
+	}
+	if s.services.Compare != nil {
+		s.mux.HandleFunc("GET /api/v1/compare/{symbol}", s.handleCompare)
+	}
+	if s.services.Usage != nil {
+		s.mux.HandleFunc("GET /api/v1/admin/usage", s.handleUsage)
+	}
+	if s.services.Telemetry != nil {
+		s.mux.HandleFunc("GET /api/v1/admin/telemetry", s.handleTelemetry)
+		s.mux.HandleFunc("DELETE /api/v1/admin/telemetry", s.handleDisableTelemetry)
+	}
+	if s.services.Shadow != nil {
+		s.mux.HandleFunc("GET /api/v1/admin/data-quality", s.handleDataQuality)
+	}
+
+	s.mux.HandleFunc("GET /api/v1/prices", s.handlePrices)
+	s.mux.HandleFunc("GET /api/v1/widget", s.handleWidget)
+	s.mux.HandleFunc("GET /api/v1/global", s.handleGlobal)
+	s.mux.HandleFunc("GET /api/v1/search", s.handleSearch)
+	if s.services.Search != nil {
+		s.mux.HandleFunc("GET /api/v1/search/all", s.handleSearchAll)
+	}
+	s.mux.HandleFunc("GET /api/v1/command-palette", s.handleCommandPalette)
+	s.mux.HandleFunc("GET /api/v1/qr", s.handleQR)
+	s.mux.HandleFunc("GET /api/v1/coins/{id}/info", s.handleCoinInfo)
+	s.mux.HandleFunc("GET /api/v1/coins/{id}/explorers", s.handleExplorers)
+	s.mux.HandleFunc("GET /api/v1/coins/{id}/qr", s.handleAddressQR)
+	s.mux.HandleFunc("GET /api/v1/coins/{id}/price-at", s.handlePriceAt)
+	s.mux.HandleFunc("GET /api/v1/coins/{id}/history", s.handleHistory)
+	s.mux.HandleFunc("GET /api/v1/coins/{id}/stats", s.handleCoinStats)
+	s.mux.HandleFunc("GET /api/v1/coins/{id}/seasonality", s.handleSeasonality)
+	s.mux.HandleFunc("GET /api/v1/coins/{id}/indicators", s.handleIndicators)
+
+	s.mux.HandleFunc("GET /api/v1/export/prices", s.handleExportPrices)
+	s.mux.HandleFunc("GET /api/v1/export/coins/{id}/candles", s.handleExportCandles)
+
+	s.mux.HandleFunc("GET /api/v1/watchlists", s.handleListWatchlists)
+	s.mux.HandleFunc("POST /api/v1/watchlists", s.handleCreateWatchlist)
+	s.mux.HandleFunc("PUT /api/v1/watchlists/order", s.handleReorderWatchlists)
+	s.mux.HandleFunc("GET /api/v1/watchlists/{id}", s.handleGetWatchlist)
+	s.mux.HandleFunc("PATCH /api/v1/watchlists/{id}", s.handleUpdateWatchlist)
+	s.mux.HandleFunc("DELETE /api/v1/watchlists/{id}", s.handleDeleteWatchlist)
+
+	s.mux.HandleFunc("GET /api/v1/charts", s.handleListCharts)
+	s.mux.HandleFunc("POST /api/v1/charts", s.handleCreateChart)
+	s.mux.HandleFunc("GET /api/v1/charts/{id}", s.handleGetChart)
+	s.mux.HandleFunc("PUT /api/v1/charts/{id}", s.handleUpdateChart)
+	s.mux.HandleFunc("DELETE /api/v1/charts/{id}", s.handleDeleteChart)
+
+	s.mux.HandleFunc("GET /api/v1/events", s.handleListEvents)
+	s.mux.HandleFunc("POST /api/v1/events", s.handleCreateEvent)
+	s.mux.HandleFunc("DELETE /api/v1/events/{id}", s.handleDeleteEvent)
+	s.mux.HandleFunc("GET /api/v1/calendar.ics", s.handleCalendarFeed)
+
+	s.mux.HandleFunc("GET /api/v1/themes", s.handleListThemeModes)
+	s.mux.HandleFunc("GET /api/v1/theme", s.handleGetTheme)
+	s.mux.HandleFunc("PUT /api/v1/theme", s.handleSetTheme)
+	s.mux.HandleFunc("DELETE /api/v1/theme", s.handleResetTheme)
+	s.mux.HandleFunc("GET /api/v1/theme.css", s.handleThemeCSS)
+	s.mux.HandleFunc("GET /api/v1/privacy", s.handleGetPrivacy)
+	s.mux.HandleFunc("PUT /api/v1/privacy", s.handleSetPrivacy)
+	s.mux.HandleFunc("GET /api/v1/preferences/order", s.handleGetCoinOrder)
+	s.mux.HandleFunc("PUT /api/v1/preferences/order", s.handleSetCoinOrder)
+	s.mux.HandleFunc("DELETE /api/v1/preferences/order", s.handleResetCoinOrder)
+	s.mux.HandleFunc("PUT /api/v1/preferences/pins/{id}", s.handlePin)
+	s.mux.HandleFunc("DELETE /api/v1/preferences/pins/{id}", s.handleUnpin)
+
+	s.mux.HandleFunc("GET /api/v1/optins/{channel}", s.handleListOptIns)
+	s.mux.HandleFunc("POST /api/v1/optins/{channel}", s.handleOptIn)
+	s.mux.HandleFunc("POST /api/v1/optins/{channel}/{recipient}/confirm", s.handleConfirmOptIn)
+	s.mux.HandleFunc("DELETE /api/v1/optins/{channel}/{recipient}", s.handleOptOut)
+
+	s.mux.HandleFunc("GET /api/v1/incidents", s.handleListIncidents)
+	s.mux.HandleFunc("POST /api/v1/incidents", s.handleRecordIncident)
+	s.mux.HandleFunc("POST /api/v1/incidents/{id}/resolve", s.handleResolveIncident)
+
+	s.mux.HandleFunc("GET /api/v1/alerts", s.handleRecentAlerts)
+	s.mux.HandleFunc("GET /api/v1/alerts/rules", s.handleListAlertRules)
+	s.mux.HandleFunc("POST /api/v1/alerts/rules", s.handleCreateAlertRule)
+	s.mux.HandleFunc("DELETE /api/v1/alerts/rules/{id}", s.handleDeleteAlertRule)
+
+	s.mux.HandleFunc("POST /api/v1/tools/position-size", s.handlePositionSize)
+	s.mux.HandleFunc("POST /api/v1/tools/projection", s.handleProjection)
+	s.mux.HandleFunc("POST /api/v1/tools/stress-test", s.handleStressTest)
+	s.mux.HandleFunc("POST /api/v1/tools/backtest/rebalancing", s.handleRebalancingBacktest)
+	s.mux.HandleFunc("GET /api/v1/watch-orders", s.handleListWatchOrders)
+	s.mux.HandleFunc("POST /api/v1/watch-orders", s.handleCreateWatchOrder)
+	s.mux.HandleFunc("DELETE /api/v1/watch-orders/{id}", s.handleDeleteWatchOrder)
+}
+
+// Handler returns the root HTTP handler, wrapped with request logging and,
+// when configured, authentication and feature counting
+func (s *Server) Handler() http.Handler {
+	var h http.Handler = s.mux
+	if s.services.Telemetry != nil {
+		h = countFeatures(s.services.Telemetry, h)
+	}
+	if s.services.Auth != nil {
+		h = s.services.Auth.middleware(h)
+	}
+	return logRequests(s.services.Logger, h)
+}
+
+// ListenAndServe serves HTTP until the context is cancelled, then shuts down gracefully
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", s.port),
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	srv.RegisterOnShutdown(func() {
+		s.shutdown.Do(func() { close(s.done) })
+	})
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+		if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
+
+// handleHealth reports whether the server is up. An open provider breaker only
+// degrades the status since cached prices are still served.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	health := map[string]any{"status": "ok"}
+	if s.services.Breaker != nil {
+		state, openedAt := s.services.Breaker.State()
+		health["provider"] = state
+		if state != api.BreakerClosed {
+			health["status"] = "degraded"
+			health["provider_down_since"] = openedAt
+		}
+	}
+	writeJSON(w, http.StatusOK, health)
+}
+
+// writeJSON encodes v as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error response in the {"error": "..."} format
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// writeUpstreamError maps a price provider error to a status: unknown coins
+// are 404, rate limits 429 with the upstream Retry-After, an unreachable
+// provider 503 and any other failure 502
+func writeUpstreamError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, api.ErrNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, api.ErrRateLimited):
+		var apiErr *api.APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter() > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(apiErr.RetryAfter().Seconds())))
+		}
+		writeError(w, http.StatusTooManyRequests, err)
+	case errors.Is(err, api.ErrProviderUnavailable):
+		writeError(w, http.StatusServiceUnavailable, err)
+	default:
+		writeError(w, http.StatusBadGateway, err)
+	}
+}
+
+// errInvalidParam reports an invalid query parameter
+func errInvalidParam(name string) error {
+	return fmt.Errorf("invalid %s parameter", name)
+}
+
+// decodeJSON decodes the request body into v, rejecting unknown fields
+func decodeJSON(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/infrastructure/api"
+)
+
+func TestHandleHealth_ReportsBreaker(t *testing.T) {
+	s := newTestServer()
+	var body map[string]any
+	json.NewDecoder(do(t, s, http.MethodGet, "/healthz", "").Body).Decode(&body)
+	if body["status"] != "ok" || body["provider"] != nil {
+		t.Errorf("Expected a plain ok without a breaker, got %v", body)
+	}
+
+	breaker := api.NewBreaker(1, time.Hour)
+	s.services.Breaker = breaker
+	breaker.Allow()
+	breaker.Record(false)
+
+	rec := do(t, s, http.MethodGet, "/healthz", "")
+	body = nil
+	json.NewDecoder(rec.Body).Decode(&body)
+	if rec.Code != http.StatusOK || body["status"] != "degraded" || body["provider"] != "open" || body["provider_down_since"] == nil {
+		t.Errorf("Expected a degraded status with the open breaker, got %d: %v", rec.Code, body)
+	}
+}
+
+func TestWriteUpstreamError(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		retry  string
+	}{
+		{&api.APIError{StatusCode: http.StatusNotFound}, http.StatusNotFound, ""},
+		{fmt.Errorf("poll: %w", &api.APIError{StatusCode: http.StatusTooManyRequests, Retry: time.Minute}), http.StatusTooManyRequests, "60"},
+		{&api.APIError{StatusCode: http.StatusBadGateway}, http.StatusServiceUnavailable, ""},
+		{fmt.Errorf("%w: dial tcp: refused", api.ErrProviderUnavailable), http.StatusServiceUnavailable, ""},
+		{errors.New("failed to decode response"), http.StatusBadGateway, ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		writeUpstreamError(rec, tt.err)
+		if rec.Code != tt.status || rec.Header().Get("Retry-After") != tt.retry {
+			t.Errorf("%v: expected %d with Retry-After %q, got %d with %q", tt.err, tt.status, tt.retry, rec.Code, rec.Header().Get("Retry-After"))
+		}
+	}
+}
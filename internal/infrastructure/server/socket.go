@@ -0,0 +1,367 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"crypto-dashboard/internal/application/events"
+	"crypto-dashboard/internal/infrastructure/websocket"
+)
+
+// Socket topics. Coin topics follow one coin, "coins:bitcoin", or every
+// tracked coin, "coins".
+const (
+	topicCoins     = "coins"
+	topicPortfolio = "portfolio"
+	topicAlerts    = "alerts"
+	topicGlobal    = "global"
+)
+
+// maxSocketTopics limits the topics of one connection
+const maxSocketTopics = 100
+
+// socketFlush is how often portfolio and global updates are sent. Portfolio
+// changes of one poll are coalesced into a single message.
+const socketFlush = time.Second
+
+// The server pings every socketHeartbeat and closes connections it has not
+// heard from for socketIdleTimeout, so a client must answer pings or send
+// requests of its own.
+const (
+	socketHeartbeat   = 30 * time.Second
+	socketIdleTimeout = 75 * time.Second
+)
+
+// socketWriteTimeout bounds every write, so a client that stops reading
+// cannot hold its connection open
+const socketWriteTimeout = 10 * time.Second
+
+// Request and message types. Ping and pong go both ways.
+const (
+	socketSubscribe   = "subscribe"
+	socketUnsubscribe = "unsubscribe"
+	socketPing        = "ping"
+	socketPong        = "pong"
+	socketHello       = "hello"
+	socketSubscribed  = "subscribed"
+	socketUpdate      = "update"
+	socketReload      = "reload"
+	socketError       = "error"
+)
+
+// socketRequest is a message from a client, e.g.
+// {"type":"subscribe","topics":["coins:bitcoin","alerts"]}. A reconnecting
+// client passes the resume token of the last update it received to first get
+// the updates of the topics it missed.
+type socketRequest struct {
+	Type   string   `json:"type"`
+	Topics []string `json:"topics"`
+	Resume string   `json:"resume,omitempty"`
+}
+
+// socketInput is a request read from a client, or why it could not be decoded
+type socketInput struct {
+	req socketRequest
+	err error
+}
+
+// socketMessage is a message to a client. Updates carry the topic they were
+// sent for, the kind of change and its data; updates of events also carry
+// their sequence number and resume token. Subscribed lists every topic of the
+// connection after a change. Hello opens the connection with the heartbeat
+// and idle timeout in seconds. Reload tells the client that missed updates
+// are no longer known and it should fetch the full state.
+type socketMessage struct {
+	Type        string   `json:"type"`
+	Topic       string   `json:"topic,omitempty"`
+	Kind        string   `json:"kind,omitempty"`
+	Data        any      `json:"data,omitempty"`
+	Seq         uint64   `json:"seq,omitempty"`
+	Resume      string   `json:"resume,omitempty"`
+	Topics      []string `json:"topics,omitempty"`
+	Heartbeat   int      `json:"heartbeat,omitempty"`
+	IdleTimeout int      `json:"idle_timeout,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// parseTopic validates a topic name and lowercases it
+func parseTopic(name string) (string, error) {
+	topic := strings.ToLower(strings.TrimSpace(name))
+	switch topic {
+	case topicCoins, topicPortfolio, topicAlerts, topicGlobal:
+		return topic, nil
+	}
+	if id, ok := strings.CutPrefix(topic, topicCoins+":"); ok && id != "" {
+		return topic, nil
+	}
+	return "", fmt.Errorf("unknown topic: %q", name)
+}
+
+// topicSet holds the topics a connection is subscribed to
+type topicSet map[string]bool
+
+// apply subscribes or unsubscribes the topics of a request. Nothing changes
+// when one of them is invalid.
+func (t topicSet) apply(req socketRequest) error {
+	topics := make([]string, len(req.Topics))
+	for i, name := range req.Topics {
+		topic, err := parseTopic(name)
+		if err != nil {
+			return err
+		}
+		topics[i] = topic
+	}
+	for _, topic := range topics {
+		if req.Type == socketUnsubscribe {
+			delete(t, topic)
+			continue
+		}
+		if !t[topic] && len(t) >= maxSocketTopics {
+			return fmt.Errorf("too many topics, at most %d are allowed", maxSocketTopics)
+		}
+		t[topic] = true
+	}
+	return nil
+}
+
+// coinTopic returns the topic an update of the coin is sent for, preferring
+// the coin's own topic over the one of every coin
+func (t topicSet) coinTopic(cryptoID string) (string, bool) {
+	if topic := topicCoins + ":" + cryptoID; t[topic] {
+		return topic, true
+	}
+	return topicCoins, t[topicCoins]
+}
+
+// message returns the update an event is sent as, and whether the connection
+// is subscribed to it
+func (t topicSet) message(event events.Event) (socketMessage, bool) {
+	var cryptoID string
+	switch ev := event.(type) {
+	case events.PriceUpdated:
+		cryptoID = ev.Price.ID
+	case events.CoinInactive:
+		cryptoID = ev.CryptoID
+	case events.CoinReactivated:
+		cryptoID = ev.CryptoID
+	case events.AlertTriggered:
+		msg := socketMessage{Type: socketUpdate, Topic: topicAlerts, Kind: string(ev.Kind()), Data: ev.Alert}
+		return msg, t[topicAlerts]
+	default:
+		return socketMessage{}, false
+	}
+	topic, ok := t.coinTopic(cryptoID)
+	return socketMessage{Type: socketUpdate, Topic: topic, Kind: string(event.Kind()), Data: event}, ok
+}
+
+func (t topicSet) list() []string {
+	topics := make([]string, 0, len(t))
+	for topic := range t {
+		topics = append(topics, topic)
+	}
+	slices.Sort(topics)
+	return topics
+}
+
+// handleSocket upgrades to a WebSocket on which clients subscribe to topics
+// and receive only their updates. Browsers may only connect from the
+// dashboard's own origin. Messages are compressed for clients that offer
+// permessage-deflate when Services.Socket enables it.
+func (s *Server) handleSocket(w http.ResponseWriter, r *http.Request) {
+	opts := s.services.Socket
+	opts.CheckOrigin = sameOrigin
+	opts.IdleTimeout = socketIdleTimeout
+	ws, err := websocket.Upgrade(w, r, opts)
+	if err != nil {
+		return
+	}
+	s.serveSocket(ws, pageOwner(r))
+}
+
+// sameOrigin accepts requests without an origin, from non-browser clients,
+// and those from the host they are sent to
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// serveSocket answers requests and forwards the updates of the subscribed
+// topics until the client disconnects
+func (s *Server) serveSocket(ws *websocket.Conn, owner string) {
+	defer ws.Close()
+	bus := s.services.Events
+	kinds := []events.Kind{events.KindPriceUpdated, events.KindCoinInactive, events.KindCoinReactivated, events.KindAlertTriggered}
+	updates, last, cancel := bus.SubscribeSequenced(kinds...)
+	defer cancel()
+
+	requests, done := make(chan socketInput), make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(requests)
+		for {
+			// Reading fails once the client goes quiet for socketIdleTimeout
+			_, data, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+			var req socketRequest
+			err = json.Unmarshal(data, &req)
+			select {
+			case requests <- socketInput{req: req, err: err}:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	held := make(map[string]bool, len(s.services.Holdings))
+	for _, h := range s.services.Holdings {
+		held[h.CryptoID] = true
+	}
+	topics := topicSet{}
+	var portfolioDirty bool
+	var globalSent time.Time
+	flush := time.NewTicker(socketFlush)
+	defer flush.Stop()
+	heartbeat := time.NewTicker(socketHeartbeat)
+	defer heartbeat.Stop()
+
+	send := func(msg socketMessage) bool {
+		ws.SetWriteDeadline(time.Now().Add(socketWriteTimeout))
+		return ws.WriteJSON(msg) == nil
+	}
+	sendEvent := func(e events.Sequenced) bool {
+		msg, subscribed := topics.message(e.Event)
+		if !subscribed {
+			return true
+		}
+		msg.Seq, msg.Resume = e.Seq, resumeToken(bus.Epoch(), e.Seq)
+		return send(msg)
+	}
+	// replay sends the updates of the subscribed topics published after the
+	// token and already passed over by this connection
+	replay := func(token string) bool {
+		seq, ok := parseResumeToken(bus.Epoch(), token)
+		var missed []events.Sequenced
+		if ok {
+			missed, ok = bus.Since(seq, last, kinds...)
+		}
+		if !ok {
+			return send(socketMessage{Type: socketReload, Resume: resumeToken(bus.Epoch(), last)})
+		}
+		for _, e := range missed {
+			if !sendEvent(e) {
+				return false
+			}
+		}
+		return true
+	}
+	// deliver sends an event and notes when it changes the portfolio
+	deliver := func(e events.Sequenced) bool {
+		if ev, isPrice := e.Event.(events.PriceUpdated); isPrice && topics[topicPortfolio] && held[ev.Price.ID] {
+			portfolioDirty = true
+		}
+		return sendEvent(e)
+	}
+	sendGlobal := func() bool {
+		global, ok := s.services.Market.Latest()
+		if !ok || !global.UpdatedAt.After(globalSent) {
+			return true
+		}
+		globalSent = global.UpdatedAt
+		return send(socketMessage{Type: socketUpdate, Topic: topicGlobal, Kind: topicGlobal, Data: global})
+	}
+	sendPortfolio := func() bool {
+		portfolioDirty = false
+		summary := s.widgetSummary(s.services.Themes.Private(owner))
+		return send(socketMessage{Type: socketUpdate, Topic: topicPortfolio, Kind: topicPortfolio, Data: summary})
+	}
+
+	ok := send(socketMessage{
+		Type:        socketHello,
+		Resume:      resumeToken(bus.Epoch(), last),
+		Heartbeat:   int(socketHeartbeat.Seconds()),
+		IdleTimeout: int(socketIdleTimeout.Seconds()),
+	})
+	for ok {
+		select {
+		case in, open := <-requests:
+			if !open {
+				return
+			}
+			if in.err != nil {
+				ok = send(socketMessage{Type: socketError, Error: "malformed request: " + in.err.Error()})
+				break
+			}
+			hadPortfolio, hadGlobal := topics[topicPortfolio], topics[topicGlobal]
+			ok = answerSocket(in.req, topics, send)
+			if ok && in.req.Type == socketSubscribe && in.req.Resume != "" {
+				ok = replay(in.req.Resume)
+			}
+			// New portfolio and global subscribers get the current state at once
+			if ok && !hadPortfolio && topics[topicPortfolio] {
+				ok = sendPortfolio()
+			}
+			if ok && !hadGlobal && topics[topicGlobal] {
+				globalSent = time.Time{}
+				ok = sendGlobal()
+			}
+		case e, open := <-updates:
+			if !open {
+				return
+			}
+			// Events of the subscribed kinds between the last one and this one
+			// were dropped by the bus because the connection fell behind; they
+			// are backfilled from the history, or the client reloads when the
+			// history no longer has them
+			if e.Seq > last+1 {
+				missed, known := bus.Since(last, e.Seq-1, kinds...)
+				if !known {
+					ok = send(socketMessage{Type: socketReload, Resume: resumeToken(bus.Epoch(), e.Seq-1)})
+				}
+				for _, m := range missed {
+					if ok = deliver(m); !ok {
+						break
+					}
+				}
+			}
+			last = e.Seq
+			ok = ok && deliver(e)
+		case <-flush.C:
+			if portfolioDirty {
+				ok = sendPortfolio()
+			}
+			if ok && topics[topicGlobal] {
+				ok = sendGlobal()
+			}
+		case <-heartbeat.C:
+			ok = send(socketMessage{Type: socketPing, Seq: last})
+		}
+	}
+}
+
+// answerSocket applies a client request and replies to it
+func answerSocket(req socketRequest, topics topicSet, send func(socketMessage) bool) bool {
+	switch req.Type {
+	case socketPing:
+		return send(socketMessage{Type: socketPong})
+	case socketPong:
+		// Answers a heartbeat; receiving it already renewed the idle timeout
+		return true
+	case socketSubscribe, socketUnsubscribe:
+		if err := topics.apply(req); err != nil {
+			return send(socketMessage{Type: socketError, Error: err.Error()})
+		}
+		return send(socketMessage{Type: socketSubscribed, Topics: topics.list()})
+	}
+	return send(socketMessage{Type: socketError, Error: fmt.Sprintf("unknown request type: %q", req.Type)})
+}
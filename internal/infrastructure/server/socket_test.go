@@ -0,0 +1,172 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/websocket"
+
+	"crypto-dashboard/internal/application/events"
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/export"
+)
+
+func dialSocket(t *testing.T, ts *httptest.Server, origin string) *websocket.Conn {
+	t.Helper()
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/api/v1/socket", "", origin)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ws.SetDeadline(time.Now().Add(2 * time.Second))
+	var hello socketMessage
+	if err := websocket.JSON.Receive(ws, &hello); err != nil || hello.Type != "hello" {
+		t.Fatalf("Expected a hello, got %+v and %v", hello, err)
+	}
+	return ws
+}
+
+func exchange(t *testing.T, ws *websocket.Conn, req socketRequest) socketMessage {
+	t.Helper()
+	if err := websocket.JSON.Send(ws, req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var msg socketMessage
+	if err := websocket.JSON.Receive(ws, &msg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return msg
+}
+
+func TestHandleSocket(t *testing.T) {
+	services := newTestServer().services
+	services.Holdings = []export.Holding{{CryptoID: "bitcoin", Quantity: decimal.NewFromInt(2)}}
+	s := New(0, services)
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+	ws := dialSocket(t, ts, ts.URL)
+	defer ws.Close()
+
+	msg := exchange(t, ws, socketRequest{Type: "subscribe", Topics: []string{"coins:ethereum", "Alerts"}})
+	if msg.Type != "subscribed" || strings.Join(msg.Topics, ",") != "alerts,coins:ethereum" {
+		t.Fatalf("Expected the subscribed topics, got %+v", msg)
+	}
+	if msg := exchange(t, ws, socketRequest{Type: "subscribe", Topics: []string{"news"}}); msg.Type != "error" {
+		t.Errorf("Expected an unknown topic to be rejected, got %+v", msg)
+	}
+	if msg := exchange(t, ws, socketRequest{Type: "ping"}); msg.Type != "pong" {
+		t.Errorf("Expected a pong, got %+v", msg)
+	}
+
+	bus := s.services.Events
+	bus.Publish(events.PriceUpdated{Price: models.CryptoPrice{ID: "bitcoin", CurrentPrice: decimal.NewFromInt(60000)}})
+	bus.Publish(events.PriceUpdated{Price: models.CryptoPrice{ID: "ethereum", CurrentPrice: decimal.NewFromInt(3000)}})
+	bus.Publish(events.AlertTriggered{Alert: models.Alert{CryptoID: "bitcoin", Kind: models.AlertPriceAbove}})
+
+	var update socketMessage
+	websocket.JSON.Receive(ws, &update)
+	if update.Topic != "coins:ethereum" || update.Kind != "price_updated" || update.Seq != 2 {
+		t.Errorf("Expected only the ethereum price, got %+v", update)
+	}
+	websocket.JSON.Receive(ws, &update)
+	if update.Topic != "alerts" || update.Kind != "alert_triggered" {
+		t.Errorf("Expected the alert, got %+v", update)
+	}
+
+	msg = exchange(t, ws, socketRequest{Type: "unsubscribe", Topics: []string{"alerts", "coins:ethereum"}})
+	if msg.Type != "subscribed" || len(msg.Topics) != 0 {
+		t.Fatalf("Expected no topics left, got %+v", msg)
+	}
+	if msg := exchange(t, ws, socketRequest{Type: "subscribe", Topics: []string{"portfolio"}}); msg.Type != "subscribed" {
+		t.Fatalf("Expected the portfolio to be subscribed, got %+v", msg)
+	}
+	websocket.JSON.Receive(ws, &update)
+	if update.Topic != "portfolio" || update.Data.(map[string]any)["total"] != "110000.00" {
+		t.Errorf("Expected the current portfolio on subscribing, got %+v", update)
+	}
+}
+
+func TestHandleSocket_Resumes(t *testing.T) {
+	s := newTestServer()
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+	bus := s.services.Events
+	bus.Publish(events.AlertTriggered{Alert: models.Alert{CryptoID: "bitcoin"}})
+	bus.Publish(events.PriceUpdated{Price: models.CryptoPrice{ID: "bitcoin"}})
+	bus.Publish(events.AlertTriggered{Alert: models.Alert{CryptoID: "ethereum"}})
+
+	ws := dialSocket(t, ts, ts.URL)
+	defer ws.Close()
+	msg := exchange(t, ws, socketRequest{Type: "subscribe", Topics: []string{"alerts"}, Resume: resumeToken(bus.Epoch(), 1)})
+	if msg.Type != "subscribed" {
+		t.Fatalf("Expected the subscription first, got %+v", msg)
+	}
+	var update socketMessage
+	websocket.JSON.Receive(ws, &update)
+	if update.Seq != 3 || update.Resume != resumeToken(bus.Epoch(), 3) || update.Data.(map[string]any)["crypto_id"] != "ethereum" {
+		t.Errorf("Expected the missed ethereum alert only, got %+v", update)
+	}
+
+	exchange(t, ws, socketRequest{Type: "subscribe", Topics: []string{"coins"}, Resume: "0badc0de-1"})
+	websocket.JSON.Receive(ws, &update)
+	if update.Type != "reload" {
+		t.Errorf("Expected a reload for a token of another epoch, got %+v", update)
+	}
+
+	// A pong answering a heartbeat gets no reply of its own
+	websocket.JSON.Send(ws, socketRequest{Type: "pong"})
+	if msg := exchange(t, ws, socketRequest{Type: "ping"}); msg.Type != "pong" {
+		t.Errorf("Expected the pong of the ping, got %+v", msg)
+	}
+}
+
+func TestHandleSocket_RejectsForeignOrigins(t *testing.T) {
+	ts := httptest.NewServer(newTestServer().Handler())
+	defer ts.Close()
+	_, err := websocket.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/api/v1/socket", "", "https://evil.example")
+	if err == nil {
+		t.Error("Expected a foreign origin to be rejected")
+	}
+}
+
+func TestHandleSocket_NegotiatesCompression(t *testing.T) {
+	services := newTestServer().services
+	services.Socket.Compression.Enabled = true
+	ts := httptest.NewServer(New(0, services).Handler())
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/api/v1/socket", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Sec-WebSocket-Extensions", "permessage-deflate; client_max_window_bits")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols || !strings.HasPrefix(resp.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate") {
+		t.Errorf("Expected permessage-deflate to be accepted, got %d %q", resp.StatusCode, resp.Header.Get("Sec-WebSocket-Extensions"))
+	}
+}
+
+func TestTopicSet(t *testing.T) {
+	topics := topicSet{}
+	if err := topics.apply(socketRequest{Type: "subscribe", Topics: []string{"coins", "global", "coins:"}}); err == nil || len(topics) != 0 {
+		t.Errorf("Expected an invalid topic to reject the whole request, got %v and %v", err, topics)
+	}
+	topics.apply(socketRequest{Type: "subscribe", Topics: []string{"coins", "coins:bitcoin"}})
+	if topic, ok := topics.coinTopic("bitcoin"); !ok || topic != "coins:bitcoin" {
+		t.Errorf("Expected the coin's own topic, got %s", topic)
+	}
+	if topic, ok := topics.coinTopic("solana"); !ok || topic != "coins" {
+		t.Errorf("Expected the topic of every coin, got %s", topic)
+	}
+	if _, ok := topics.message(events.AlertTriggered{}); ok {
+		t.Error("Expected alerts not to be sent to coin subscribers")
+	}
+}
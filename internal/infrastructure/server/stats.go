@@ -0,0 +1,92 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"crypto-dashboard/internal/application/analytics"
+)
+
+// defaultProfileBins is the number of price levels in the volume profile
+const defaultProfileBins = 24
+
+// maxProfileBins bounds the price levels a client may ask for
+const maxProfileBins = 1000
+
+// coinStats summarizes the stored candles of a coin
+type coinStats struct {
+	ID            string                   `json:"id"`
+	Interval      string                   `json:"interval"`
+	Candles       int                      `json:"candles"`
+	High          float64                  `json:"high"`
+	Low           float64                  `json:"low"`
+	Volume        float64                  `json:"volume"`
+	VolumeProfile *analytics.VolumeProfile `json:"volume_profile,omitempty"`
+}
+
+func (s *Server) handleCoinStats(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	limit, err := intParam(r, "limit", defaultCandleLimit)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	bins, err := boundedIntParam(r, "bins", defaultProfileBins, maxProfileBins)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	candles, err := s.services.Candles.Candles(id, s.services.CandleInterval, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if len(candles) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no stored history for %s", id))
+		return
+	}
+
+	stats := coinStats{
+		ID:       id,
+		Interval: s.services.CandleInterval.String(),
+		Candles:  len(candles),
+		High:     candles[0].High,
+		Low:      candles[0].Low,
+	}
+	for _, c := range candles {
+		stats.High = max(stats.High, c.High)
+		stats.Low = min(stats.Low, c.Low)
+		stats.Volume += c.Volume
+	}
+
+	profile, err := analytics.ComputeVolumeProfile(candles, bins)
+	if err == nil {
+		stats.VolumeProfile = &profile
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// intParam parses a positive integer query parameter, returning def when absent
+func intParam(r *http.Request, name string, def int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, errInvalidParam(name)
+	}
+	return n, nil
+}
+
+// boundedIntParam reads a positive integer query parameter of at most limit
+func boundedIntParam(r *http.Request, name string, def, limit int) (int, error) {
+	n, err := intParam(r, name, def)
+	if err == nil && n > limit {
+		return 0, errInvalidParam(name)
+	}
+	return n, err
+}
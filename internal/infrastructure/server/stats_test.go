@@ -0,0 +1,48 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestHandleCoinStats(t *testing.T) {
+	s := newTestServer()
+
+	rec := do(t, s, http.MethodGet, "/api/v1/coins/bitcoin/stats", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 without history, got %d", rec.Code)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, price := range []float64{100, 110, 105} {
+		c := models.NewCandle("bitcoin", price, start.Add(time.Duration(i)*time.Hour), time.Hour)
+		c.Update(price + 2)
+		c.Volume = 10
+		s.services.Candles.SaveCandle(time.Hour, c)
+	}
+
+	rec = do(t, s, http.MethodGet, "/api/v1/coins/bitcoin/stats?bins=4", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var stats coinStats
+	json.NewDecoder(rec.Body).Decode(&stats)
+	if stats.Candles != 3 || stats.High != 112 || stats.Low != 100 || stats.Volume != 30 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if stats.VolumeProfile == nil || len(stats.VolumeProfile.Bins) != 4 {
+		t.Errorf("Expected a 4 bin volume profile, got %+v", stats.VolumeProfile)
+	}
+
+	for _, bins := range []string{"-1", "1000000000"} {
+		rec = do(t, s, http.MethodGet, "/api/v1/coins/bitcoin/stats?bins="+bins, "")
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s bins, got %d", bins, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"html/template"
+	"net/http"
+	"time"
+
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/api"
+)
+
+// statusPage is the public status page, script-free like the lite page
+var statusPage = template.Must(template.New("status").Funcs(template.FuncMap{
+	"when": func(t time.Time) string {
+		if t.IsZero() {
+			return "never"
+		}
+		return t.UTC().Format("2006-01-02 15:04:05 UTC")
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Status: {{.Status}}</title>
+</head>
+<body>
+<main>
+<h1>Status: {{.Status}}</h1>
+<p><a href="/status?format=json">JSON</a> · <a href="/">Dashboard</a></p>
+<dl>
+<dt>Price provider</dt><dd>{{.Provider.State}}{{if .Provider.LastError}} ({{.Provider.LastError}}){{end}}</dd>
+<dt>Last successful refresh</dt><dd>{{when .Provider.LastSuccess}}</dd>
+<dt>Stale prices</dt><dd>{{.Provider.StaleCoins}} of {{.Provider.TrackedCoins}}</dd>
+<dt>Up since</dt><dd>{{when .StartedAt}} ({{.Uptime}})</dd>
+</dl>
+<h2>Recent incidents</h2>
+<table>
+<thead><tr><th scope="col">Started</th><th scope="col">Resolved</th><th scope="col">Duration</th><th scope="col">Kind</th><th scope="col">Description</th></tr></thead>
+<tbody>
+{{range .Incidents}}<tr><td>{{when .Started}}</td><td>{{if .Resolved}}{{when .Resolved}}{{else}}ongoing{{end}}</td><td>{{.Duration}}</td><td>{{.Kind}}{{with .CryptoID}} ({{.}}){{end}}</td><td>{{.Description}}</td></tr>
+{{else}}<tr><td colspan="5">No incidents.</td></tr>
+{{end}}</tbody>
+</table>
+</main>
+</body>
+</html>
+`))
+
+// statusReport is the content of /status
+type statusReport struct {
+	// Status is operational or degraded
+	Status        string           `json:"status"`
+	Provider      providerStatus   `json:"provider"`
+	StartedAt     time.Time        `json:"started_at"`
+	Uptime        string           `json:"uptime"`
+	UptimeSeconds int64            `json:"uptime_seconds"`
+	Incidents     []statusIncident `json:"incidents"`
+}
+
+type providerStatus struct {
+	// State is up, failing, or the breaker state when it is not closed
+	State        string    `json:"state"`
+	LastSuccess  time.Time `json:"last_success"`
+	LastError    string    `json:"last_error,omitempty"`
+	TrackedCoins int       `json:"tracked_coins"`
+	StaleCoins   int       `json:"stale_coins"`
+}
+
+type statusIncident struct {
+	models.Incident
+	Duration string `json:"duration"`
+}
+
+// handleStatus reports provider health, the last refresh, uptime and recent
+// incidents as HTML, or as JSON with format=json
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "html" && format != "json" {
+		writeError(w, http.StatusBadRequest, errInvalidParam("format"))
+		return
+	}
+
+	report, err := s.statusReport(s.services.Clock.Now())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	if format == "json" {
+		writeJSON(w, http.StatusOK, report)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	statusPage.Execute(w, report)
+}
+
+func (s *Server) statusReport(now time.Time) (statusReport, error) {
+	tracker := s.services.Status
+	incidents, ongoing, err := tracker.Incidents()
+	if err != nil {
+		return statusReport{}, err
+	}
+	uptime := tracker.Uptime()
+	report := statusReport{
+		Status:        "operational",
+		StartedAt:     tracker.Started(),
+		Uptime:        uptime.Round(time.Second).String(),
+		UptimeSeconds: int64(uptime.Seconds()),
+		Incidents:     []statusIncident{},
+		Provider: providerStatus{
+			State:        "up",
+			LastSuccess:  s.services.Poller.LastSuccess(),
+			TrackedCoins: len(s.services.Poller.Coins()),
+		},
+	}
+
+	for _, p := range s.services.Poller.Snapshot() {
+		if p.Stale {
+			report.Provider.StaleCoins++
+		}
+	}
+	if err := s.services.Poller.LastError(); err != nil {
+		report.Provider.State, report.Provider.LastError = "failing", err.Error()
+	}
+	if s.services.Breaker != nil {
+		if state, _ := s.services.Breaker.State(); state != api.BreakerClosed {
+			report.Provider.State = string(state)
+		}
+	}
+	if ongoing || report.Provider.State != "up" || report.Provider.StaleCoins > 0 {
+		report.Status = "degraded"
+	}
+
+	for _, incident := range incidents {
+		report.Incidents = append(report.Incidents, statusIncident{
+			Incident: incident,
+			Duration: incident.Duration(now).Round(time.Second).String(),
+		})
+	}
+	return report, nil
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/status"
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestHandleStatus(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	services := newTestServer().services
+	services.Status = status.NewTracker(services.Incidents)
+	services.Clock = clock.NewFake(now)
+	s := New(0, services)
+	s.services.Poller.PollOnce()
+
+	rec := do(t, s, http.MethodGet, "/status?format=json", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	var report statusReport
+	json.NewDecoder(rec.Body).Decode(&report)
+	if report.Status != "operational" || report.Provider.State != "up" || report.Provider.LastSuccess.IsZero() || report.Provider.TrackedCoins != 1 {
+		t.Errorf("Unexpected healthy report: %+v", report)
+	}
+
+	started := now.Add(-10 * time.Minute)
+	services.Incidents.Record(models.Incident{Kind: models.IncidentOutage, Description: "timeout", Started: started})
+	json.NewDecoder(do(t, s, http.MethodGet, "/status?format=json", "").Body).Decode(&report)
+	if report.Status != "degraded" || len(report.Incidents) != 1 || report.Incidents[0].Description != "timeout" || report.Incidents[0].Duration != "10m0s" {
+		t.Errorf("Expected an ongoing incident to degrade the status, got %+v", report)
+	}
+
+	rec = do(t, s, http.MethodGet, "/status", "")
+	page := rec.Body.String()
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") || !strings.Contains(page, "<h1>Status: degraded</h1>") || !strings.Contains(page, "ongoing") {
+		t.Errorf("Unexpected status page:\n%s", page)
+	}
+
+	if rec := do(t, s, http.MethodGet, "/status?format=xml", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown format, got %d", rec.Code)
+	}
+}
+
+func TestHandleStatus_NotServedWithoutTracker(t *testing.T) {
+	if rec := do(t, newTestServer(), http.MethodGet, "/status", ""); strings.Contains(rec.Body.String(), "Status:") {
+		t.Error("Expected no status page without a tracker")
+	}
+}
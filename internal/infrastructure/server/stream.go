@@ -0,0 +1,127 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"crypto-dashboard/internal/application/events"
+)
+
+// streamHeartbeat is how often an idle stream sends a comment so proxies keep it open
+const streamHeartbeat = 30 * time.Second
+
+// streamRetry is how long clients are told to wait before reconnecting
+const streamRetry = 3 * time.Second
+
+// resumeToken identifies an event of the bus, so a client reconnecting with it
+// receives the events published after it
+func resumeToken(epoch string, seq uint64) string {
+	return epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+// parseResumeToken returns the sequence number of a token of the bus epoch. It
+// reports false for tokens of another epoch, e.g. from before a restart.
+func parseResumeToken(epoch, token string) (uint64, bool) {
+	tokenEpoch, raw, found := strings.Cut(token, "-")
+	if !found || tokenEpoch != epoch {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(raw, 10, 64)
+	return seq, err == nil
+}
+
+// handleStream broadcasts bus events as server-sent events until the client
+// disconnects or the server shuts down. The optional kinds parameter is a comma separated list of event
+// kinds. Every event carries a resume token as its ID; clients reconnecting
+// with a Last-Event-ID header, or last_event_id parameter, first receive the
+// events they missed, or a reload event when those are no longer known.
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	var kinds []events.Kind
+	if raw := r.URL.Query().Get("kinds"); raw != "" {
+		for _, name := range strings.Split(raw, ",") {
+			kind, err := events.ParseKind(strings.TrimSpace(name))
+			if err != nil {
+				writeError(w, http.StatusBadRequest, errInvalidParam("kinds"))
+				return
+			}
+			kinds = append(kinds, kind)
+		}
+	}
+
+	bus := s.services.Events
+	updates, last, cancel := bus.SubscribeSequenced(kinds...)
+	defer cancel()
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("last_event_id")
+	}
+	if lastID != "" {
+		seq, ok := parseResumeToken(bus.Epoch(), lastID)
+		var missed []events.Sequenced
+		if ok {
+			missed, ok = bus.Since(seq, last, kinds...)
+		}
+		if !ok {
+			fmt.Fprintf(w, "id: %s\nevent: reload\ndata: {}\n\n", resumeToken(bus.Epoch(), last))
+		}
+		for _, e := range missed {
+			s.writeStreamEvent(w, bus.Epoch(), e)
+		}
+	}
+	rc.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case event, ok := <-updates:
+			if !ok {
+				return
+			}
+			// Events the bus dropped because the client fell behind are
+			// backfilled from the history, or the client reloads when the
+			// history no longer has them
+			if event.Seq > last+1 {
+				missed, known := bus.Since(last, event.Seq-1, kinds...)
+				if !known {
+					fmt.Fprintf(w, "id: %s\nevent: reload\ndata: {}\n\n", resumeToken(bus.Epoch(), event.Seq-1))
+				}
+				for _, e := range missed {
+					s.writeStreamEvent(w, bus.Epoch(), e)
+				}
+			}
+			last = event.Seq
+			s.writeStreamEvent(w, bus.Epoch(), event)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeStreamEvent writes an event with its resume token as ID
+func (s *Server) writeStreamEvent(w http.ResponseWriter, epoch string, e events.Sequenced) {
+	data, err := json.Marshal(e.Event)
+	if err != nil {
+		s.services.Logger.Error("failed to encode event", "kind", e.Event.Kind(), "error", err)
+		return
+	}
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", resumeToken(epoch, e.Seq), e.Event.Kind(), data)
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/events"
+)
+
+func TestHandleStream(t *testing.T) {
+	s := newTestServer()
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/v1/stream?kinds=threshold_crossed")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %q", ct)
+	}
+
+	bus := s.services.Events
+	for deadline := time.Now().Add(time.Second); bus.Subscribers() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the stream to subscribe to the bus")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	bus.Publish(events.PriceUpdated{})
+	bus.Publish(events.ThresholdCrossed{CryptoID: "bitcoin", Threshold: decimal.NewFromInt(70000), Direction: events.Up})
+
+	reader := bufio.NewReader(resp.Body)
+	if retry, _ := reader.ReadString('\n'); retry != "retry: 3000\n" {
+		t.Errorf("Expected a reconnect hint, got %q", retry)
+	}
+	reader.ReadString('\n')
+	id, _ := reader.ReadString('\n')
+	event, _ := reader.ReadString('\n')
+	data, _ := reader.ReadString('\n')
+	if id != "id: "+bus.Epoch()+"-2\n" {
+		t.Errorf("Expected the resume token of the second event, got %q", id)
+	}
+	if event != "event: threshold_crossed\n" {
+		t.Errorf("Expected only the crossing to be streamed, got %q", event)
+	}
+	if !strings.HasPrefix(data, "data: ") || !strings.Contains(data, `"threshold":"70000"`) {
+		t.Errorf("Expected the crossing as JSON, got %q", data)
+	}
+}
+
+func TestHandleStream_RejectsUnknownKinds(t *testing.T) {
+	rec := do(t, newTestServer(), http.MethodGet, "/api/v1/stream?kinds=bogus", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestHandleStream_Resumes(t *testing.T) {
+	s := newTestServer()
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+	bus := s.services.Events
+	bus.Publish(events.CoinInactive{CryptoID: "terra-luna"})
+	bus.Publish(events.CoinReactivated{CryptoID: "terra-luna"})
+	bus.Publish(events.PriceUpdated{})
+
+	read := func(lastID string) string {
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+"/api/v1/stream?kinds=coin_inactive,coin_reactivated", nil)
+		req.Header.Set("Last-Event-ID", lastID)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		reader := bufio.NewReader(resp.Body)
+		reader.ReadString('\n')
+		reader.ReadString('\n')
+		var lines []string
+		for range 3 {
+			line, _ := reader.ReadString('\n')
+			lines = append(lines, line)
+		}
+		return strings.Join(lines, "")
+	}
+
+	if got := read(bus.Epoch() + "-1"); !strings.HasPrefix(got, "id: "+bus.Epoch()+"-2\nevent: coin_reactivated\n") {
+		t.Errorf("Expected the missed reactivation, got %q", got)
+	}
+	if got := read("0badc0de-1"); !strings.HasPrefix(got, "id: "+bus.Epoch()+"-3\nevent: reload\n") {
+		t.Errorf("Expected a reload after a restart, got %q", got)
+	}
+}
+
+func TestListenAndServe_EndsStreams(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestServer()
+	s.port = l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	served := make(chan error, 1)
+	go func() { served <- s.ListenAndServe(ctx) }()
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/api/v1/stream", s.port)
+	var resp *http.Response
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(5 * time.Millisecond) {
+		if resp, err = http.Get(url); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	defer resp.Body.Close()
+
+	// An open stream must not hold the shutdown until its timeout
+	cancel()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("Expected a clean shutdown, got %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Expected the shutdown to end the open stream")
+	}
+}
+
+func TestResumeToken(t *testing.T) {
+	if seq, ok := parseResumeToken("abc", resumeToken("abc", 42)); !ok || seq != 42 {
+		t.Errorf("Expected the token to round-trip, got %d %v", seq, ok)
+	}
+	for _, token := range []string{"def-42", "abc", "abc-x", ""} {
+		if _, ok := parseResumeToken("abc", token); ok {
+			t.Errorf("Expected %q to be rejected", token)
+		}
+	}
+}
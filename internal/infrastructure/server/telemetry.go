@@ -0,0 +1,45 @@
+package server
+
+import (
+	"net/http"
+
+	"crypto-dashboard/internal/application/telemetry"
+)
+
+// untrackedRoutes are not counted as feature usage: static files, icons and
+// machine probes
+var untrackedRoutes = map[string]bool{
+	"GET /":                       true,
+	"GET /healthz":                true,
+	"GET /metrics":                true,
+	"GET /assets/icons/{file}":    true,
+	"GET /api/v1/admin/telemetry": true,
+}
+
+// countFeatures counts every request by the route pattern that served it, so
+// no path values, query parameters or client details reach the telemetry.
+// It must wrap the mux directly, which sets the pattern on the request.
+func countFeatures(collector *telemetry.Collector, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+		if r.Pattern != "" && !untrackedRoutes[r.Pattern] {
+			collector.Count(r.Pattern)
+		}
+	})
+}
+
+// handleTelemetry shows whether telemetry is on and exactly the report that
+// would be sent next
+func (s *Server) handleTelemetry(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"enabled":     s.services.Telemetry.Enabled(),
+		"next_report": s.services.Telemetry.Preview(),
+	})
+}
+
+// handleDisableTelemetry turns telemetry off until restart and drops the
+// counts not sent yet
+func (s *Server) handleDisableTelemetry(w http.ResponseWriter, r *http.Request) {
+	s.services.Telemetry.Disable()
+	w.WriteHeader(http.StatusNoContent)
+}
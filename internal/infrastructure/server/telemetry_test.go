@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/telemetry"
+)
+
+type discardSender struct{}
+
+func (discardSender) Send(context.Context, telemetry.Report) error { return nil }
+
+func TestTelemetry(t *testing.T) {
+	if rec := do(t, newTestServer(), http.MethodGet, "/api/v1/admin/telemetry", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without telemetry, got %d", rec.Code)
+	}
+
+	collector := telemetry.NewCollector("test")
+	collector.Enable(discardSender{}, time.Hour)
+	services := newTestServer().services
+	services.Telemetry = collector
+	s := New(0, services)
+
+	do(t, s, http.MethodGet, "/api/v1/coins/bitcoin/explorers", "")
+	do(t, s, http.MethodGet, "/api/v1/coins/ethereum/explorers", "")
+	do(t, s, http.MethodGet, "/healthz", "")
+	do(t, s, http.MethodGet, "/no-such-page", "")
+
+	rec := do(t, s, http.MethodGet, "/api/v1/admin/telemetry", "")
+	var body struct {
+		Enabled    bool             `json:"enabled"`
+		NextReport telemetry.Report `json:"next_report"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("Expected the telemetry preview, got %d: %s", rec.Code, rec.Body)
+	}
+	features := body.NextReport.Features
+	if !body.Enabled || len(features) != 1 || features["GET /api/v1/coins/{id}/explorers"] != 2 {
+		t.Errorf("Expected only the explorers route to be counted, without coin IDs, got %+v", body)
+	}
+
+	if rec := do(t, s, http.MethodDelete, "/api/v1/admin/telemetry", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", rec.Code)
+	}
+	do(t, s, http.MethodGet, "/api/v1/coins/bitcoin/explorers", "")
+	if collector.Enabled() || len(collector.Preview().Features) != 0 {
+		t.Errorf("Expected telemetry to be off with nothing counted, got %+v", collector.Preview())
+	}
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"crypto-dashboard/internal/application/theme"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// themeResponse is a theme together with the colors it resolves to
+type themeResponse struct {
+	models.Theme
+	Palette models.Palette `json:"palette"`
+}
+
+// modeResponse describes a built-in theme mode
+type modeResponse struct {
+	Mode    models.ThemeMode `json:"mode"`
+	Palette models.Palette   `json:"palette"`
+}
+
+func (s *Server) handleListThemeModes(w http.ResponseWriter, r *http.Request) {
+	modes := make([]modeResponse, len(models.ThemeModes))
+	for i, mode := range models.ThemeModes {
+		modes[i] = modeResponse{Mode: mode, Palette: models.BasePalette(mode)}
+	}
+	writeJSON(w, http.StatusOK, modes)
+}
+
+func (s *Server) handleGetTheme(w http.ResponseWriter, r *http.Request) {
+	t, err := s.services.Themes.Get(sessionOwner(r))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, themeResponse{Theme: t, Palette: t.Palette()})
+}
+
+func (s *Server) handleSetTheme(w http.ResponseWriter, r *http.Request) {
+	var t models.Theme
+	if err := decodeJSON(r, &t); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	saved, err := s.services.Themes.Set(sessionOwner(r), t)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, themeResponse{Theme: saved, Palette: saved.Palette()})
+}
+
+func (s *Server) handleResetTheme(w http.ResponseWriter, r *http.Request) {
+	if err := s.services.Themes.Reset(sessionOwner(r)); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// privacyResponse is the privacy mode of a session
+type privacyResponse struct {
+	Enabled bool `json:"enabled"`
+}
+
+func (s *Server) handleGetPrivacy(w http.ResponseWriter, r *http.Request) {
+	t, err := s.services.Themes.Get(sessionOwner(r))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, privacyResponse{Enabled: t.Privacy})
+}
+
+func (s *Server) handleSetPrivacy(w http.ResponseWriter, r *http.Request) {
+	var req privacyResponse
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	t, err := s.services.Themes.SetPrivacy(sessionOwner(r), req.Enabled)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, privacyResponse{Enabled: t.Privacy})
+}
+
+// pageOwner is the session of a request made by a page, stylesheet or embedded
+// widget. These cannot send headers, so the session may also be given as a parameter.
+func pageOwner(r *http.Request) string {
+	if owner := strings.TrimSpace(r.URL.Query().Get("session")); owner != "" {
+		return owner
+	}
+	return sessionOwner(r)
+}
+
+// handleThemeCSS serves the theme as CSS variables
+func (s *Server) handleThemeCSS(w http.ResponseWriter, r *http.Request) {
+	t, err := s.services.Themes.Get(pageOwner(r))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/css; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	theme.WriteCSS(w, t)
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestThemeEndpoints(t *testing.T) {
+	s := newTestServer()
+
+	rec := doAs(t, s, "alice", http.MethodGet, "/api/v1/theme", "")
+	var body themeResponse
+	json.NewDecoder(rec.Body).Decode(&body)
+	if rec.Code != http.StatusOK || body.Mode != "dark" || body.Palette["bg"] == "" {
+		t.Fatalf("Expected the default dark theme, got %d: %+v", rec.Code, body)
+	}
+
+	rec = doAs(t, s, "alice", http.MethodPut, "/api/v1/theme", `{"mode":"light","accent":"#FF8800"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := doAs(t, s, "alice", http.MethodPut, "/api/v1/theme", `{"mode":"sepia"}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 for an unknown mode, got %d", rec.Code)
+	}
+
+	rec = do(t, s, http.MethodGet, "/api/v1/theme.css?session=alice", "")
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/css") {
+		t.Errorf("Expected CSS, got %s", ct)
+	}
+	if css := rec.Body.String(); !strings.Contains(css, "--accent: #ff8800;") {
+		t.Errorf("Expected alice's accent in:\n%s", css)
+	}
+	if css := do(t, s, http.MethodGet, "/api/v1/theme.css", "").Body.String(); !strings.Contains(css, "/* dark theme */") {
+		t.Errorf("Expected other sessions to keep the default, got:\n%s", css)
+	}
+
+	if rec := doAs(t, s, "alice", http.MethodDelete, "/api/v1/theme", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", rec.Code)
+	}
+	rec = doAs(t, s, "alice", http.MethodGet, "/api/v1/theme", "")
+	json.NewDecoder(rec.Body).Decode(&body)
+	if body.Mode != "dark" || body.Accent != "" {
+		t.Errorf("Expected the default after a reset, got %+v", body)
+	}
+}
+
+func TestPrivacyEndpoints(t *testing.T) {
+	s := newTestServer()
+	doAs(t, s, "alice", http.MethodPut, "/api/v1/theme", `{"mode":"light"}`)
+
+	rec := doAs(t, s, "alice", http.MethodPut, "/api/v1/privacy", `{"enabled":true}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"enabled":true`) {
+		t.Fatalf("Expected privacy mode on, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := doAs(t, s, "alice", http.MethodPut, "/api/v1/privacy", `{"enabled":"yes"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a malformed toggle, got %d", rec.Code)
+	}
+
+	var body themeResponse
+	json.NewDecoder(doAs(t, s, "alice", http.MethodGet, "/api/v1/theme", "").Body).Decode(&body)
+	if body.Mode != "light" || !body.Privacy {
+		t.Errorf("Expected the light theme in privacy mode, got %+v", body)
+	}
+	if rec := doAs(t, s, "bob", http.MethodGet, "/api/v1/privacy", ""); !strings.Contains(rec.Body.String(), `"enabled":false`) {
+		t.Errorf("Expected other sessions to keep privacy mode off, got %s", rec.Body.String())
+	}
+}
+
+func TestHandleListThemeModes(t *testing.T) {
+	s := newTestServer()
+
+	rec := do(t, s, http.MethodGet, "/api/v1/themes", "")
+	var modes []modeResponse
+	json.NewDecoder(rec.Body).Decode(&modes)
+	if len(modes) != 3 || modes[2].Mode != "high-contrast" || modes[2].Palette["text"] == "" {
+		t.Errorf("Expected the three built-in modes, got %+v", modes)
+	}
+}
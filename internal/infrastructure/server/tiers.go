@@ -0,0 +1,74 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+// Tier is how degraded the data of a response is, from fresh to missing
+type Tier string
+
+// Tiers from best to worst
+const (
+	// TierLive is the latest data the dashboard polls on schedule or fetched for the request
+	TierLive Tier = "live"
+	// TierCached is data served from a cache within its lifetime
+	TierCached Tier = "cached"
+	// TierStale is data kept because refreshing it failed, or a fallback such as the bundled coin list
+	TierStale Tier = "stale"
+	// TierUnavailable means there is no data to serve
+	TierUnavailable Tier = "unavailable"
+)
+
+var tierOrder = []Tier{TierLive, TierCached, TierStale, TierUnavailable}
+
+// TierHeader carries the tier of every response of a tiered endpoint, errors included
+const TierHeader = "X-Data-Tier"
+
+// Tiered endpoints, as named by a TierPolicy
+const (
+	EndpointPrices   = "prices"
+	EndpointGlobal   = "global"
+	EndpointHistory  = "history"
+	EndpointCoinInfo = "coin_info"
+)
+
+// TierPolicy is the worst tier each endpoint may serve, by endpoint name.
+// Responses worse than that fail with 503 instead; endpoints without an
+// entry serve every tier.
+type TierPolicy map[string]Tier
+
+// Meta describes how a response was served; tiered endpoints include it as "meta"
+type Meta struct {
+	Tier Tier `json:"tier"`
+}
+
+// ParseTier parses a tier a policy can require: live, cached or stale
+func ParseTier(s string) (Tier, error) {
+	t := Tier(s)
+	if !slices.Contains(tierOrder[:3], t) {
+		return "", fmt.Errorf("unknown tier %q, expected live, cached or stale", s)
+	}
+	return t, nil
+}
+
+// worseThan reports whether t is more degraded than other
+func (t Tier) worseThan(other Tier) bool {
+	return slices.Index(tierOrder, t) > slices.Index(tierOrder, other)
+}
+
+// acceptTier sets the tier header and reports whether the endpoint's policy
+// accepts the tier. When it does not, a 503 naming the tier is written.
+func (s *Server) acceptTier(w http.ResponseWriter, endpoint string, tier Tier) bool {
+	w.Header().Set(TierHeader, string(tier))
+	worst, ok := s.services.Tiers[endpoint]
+	if !ok || !tier.worseThan(worst) {
+		return true
+	}
+	writeJSON(w, http.StatusServiceUnavailable, map[string]any{
+		"error": fmt.Sprintf("only %s data is available, the %s policy requires %s or better", tier, endpoint, worst),
+		"meta":  Meta{Tier: tier},
+	})
+	return false
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/poller"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// flakyPrices answers until it is switched off
+type flakyPrices struct{ down bool }
+
+func (f *flakyPrices) FetchCryptoPrices(ids []string, currency models.Currency) ([]models.CryptoPrice, error) {
+	if f.down {
+		return nil, errors.New("provider unavailable")
+	}
+	return []models.CryptoPrice{{ID: "bitcoin", CurrentPrice: decimal.NewFromInt(55000), Currency: currency}}, nil
+}
+
+func TestPrices_Tiers(t *testing.T) {
+	provider := &flakyPrices{}
+	services := newTestServer().services
+	services.Poller = poller.New(provider, time.Minute, models.USD, []string{"bitcoin"})
+	s := New(0, services)
+
+	tierOf := func(path string) (int, Tier) {
+		t.Helper()
+		rec := do(t, s, http.MethodGet, path, "")
+		var body struct {
+			Meta Meta `json:"meta"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &body)
+		if header := Tier(rec.Header().Get(TierHeader)); header != body.Meta.Tier {
+			t.Errorf("Expected the header to match meta.tier, got %q and %q", header, body.Meta.Tier)
+		}
+		return rec.Code, body.Meta.Tier
+	}
+
+	if code, tier := tierOf("/api/v1/prices"); code != http.StatusOK || tier != TierUnavailable {
+		t.Errorf("Expected unavailable before the first poll, got %d %q", code, tier)
+	}
+	services.Poller.PollOnce()
+	if code, tier := tierOf("/api/v1/prices"); code != http.StatusOK || tier != TierLive {
+		t.Errorf("Expected live prices, got %d %q", code, tier)
+	}
+	provider.down = true
+	services.Poller.PollOnce()
+	if code, tier := tierOf("/api/v1/prices"); code != http.StatusOK || tier != TierStale {
+		t.Errorf("Expected stale prices after a failed poll, got %d %q", code, tier)
+	}
+	if code, tier := tierOf("/api/v1/coins/bitcoin/history"); code != http.StatusOK || tier != TierStale {
+		t.Errorf("Expected the history to be as stale as the price, got %d %q", code, tier)
+	}
+
+	// A policy turns responses worse than its tier into errors
+	services.Tiers = TierPolicy{EndpointPrices: TierCached}
+	s = New(0, services)
+	if code, tier := tierOf("/api/v1/prices"); code != http.StatusServiceUnavailable || tier != TierStale {
+		t.Errorf("Expected 503 for stale prices under a cached policy, got %d %q", code, tier)
+	}
+	if code, tier := tierOf("/api/v1/global"); code != http.StatusOK || tier != TierCached {
+		t.Errorf("Expected endpoints without a policy to serve, got %d %q", code, tier)
+	}
+}
+
+func TestParseTier(t *testing.T) {
+	if tier, err := ParseTier("cached"); err != nil || tier != TierCached {
+		t.Errorf("Expected cached, got %q, %v", tier, err)
+	}
+	for _, name := range []string{"", "fresh", "unavailable"} {
+		if _, err := ParseTier(name); err == nil {
+			t.Errorf("Expected %q to be rejected", name)
+		}
+	}
+	if !TierStale.worseThan(TierCached) || TierLive.worseThan(TierLive) {
+		t.Error("Expected tiers to be ordered from live to unavailable")
+	}
+}
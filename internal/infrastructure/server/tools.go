@@ -0,0 +1,82 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"crypto-dashboard/internal/application/risk"
+	"crypto-dashboard/internal/domain/models"
+)
+
+func (s *Server) handlePositionSize(w http.ResponseWriter, r *http.Request) {
+	var input risk.PositionSizeInput
+	if err := decodeJSON(r, &input); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	size, err := risk.CalculatePositionSize(input)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, size)
+}
+
+// stressTestRequest is the body of the stress test endpoint
+type stressTestRequest struct {
+	Holdings  map[string]float64 `json:"holdings"`
+	Scenarios []risk.Scenario    `json:"scenarios"`
+}
+
+func (s *Server) handleStressTest(w http.ResponseWriter, r *http.Request) {
+	var req stressTestRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	results, err := s.services.Risk.StressTest(req.Holdings, req.Scenarios)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+func (s *Server) handleListWatchOrders(w http.ResponseWriter, r *http.Request) {
+	statuses, err := s.services.Risk.WatchOrderStatuses()
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, statuses)
+}
+
+func (s *Server) handleCreateWatchOrder(w http.ResponseWriter, r *http.Request) {
+	var order models.WatchOrder
+	if err := decodeJSON(r, &order); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	created, err := s.services.Risk.CreateWatchOrder(order)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) handleDeleteWatchOrder(w http.ResponseWriter, r *http.Request) {
+	err := s.services.Risk.DeleteWatchOrder(r.PathValue("id"))
+	if errors.Is(err, risk.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
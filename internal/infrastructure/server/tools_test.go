@@ -0,0 +1,152 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/alerts"
+	"crypto-dashboard/internal/application/backtest"
+	"crypto-dashboard/internal/application/calendar"
+	"crypto-dashboard/internal/application/chart"
+	"crypto-dashboard/internal/application/coins"
+	"crypto-dashboard/internal/application/events"
+	"crypto-dashboard/internal/application/incident"
+	"crypto-dashboard/internal/application/market"
+	"crypto-dashboard/internal/application/optin"
+	"crypto-dashboard/internal/application/poller"
+	"crypto-dashboard/internal/application/preferences"
+	"crypto-dashboard/internal/application/pricehistory"
+	"crypto-dashboard/internal/application/projection"
+	"crypto-dashboard/internal/application/risk"
+	"crypto-dashboard/internal/application/theme"
+	"crypto-dashboard/internal/application/watchlist"
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/repository/memory"
+)
+
+type stubPrices map[string]float64
+
+func (s stubPrices) FetchCryptoPrices(ids []string, currency models.Currency) ([]models.CryptoPrice, error) {
+	var prices []models.CryptoPrice
+	for _, id := range ids {
+		prices = append(prices, models.CryptoPrice{ID: id, CurrentPrice: decimal.NewFromFloat(s[id]), Currency: currency})
+	}
+	return prices, nil
+}
+
+func (s stubPrices) GetGlobalData(currency models.Currency) (models.GlobalMarket, error) {
+	return models.GlobalMarket{Currency: currency, TotalMarketCap: 2e12, BTCDominance: 52}, nil
+}
+
+func newTestServer() *Server {
+	prices := stubPrices{"bitcoin": 55000}
+	candleRepo := memory.NewCandleRepository()
+	p := poller.New(prices, time.Minute, models.USD, []string{"bitcoin"})
+	return New(0, Services{
+		Poller:         p,
+		Risk:           risk.NewService(memory.NewWatchOrderRepository(), prices, models.USD),
+		Watchlists:     watchlist.NewService(memory.NewWatchlistRepository(), p),
+		Alerts:         alerts.NewEngine(memory.NewAlertRuleRepository(), candleRepo, time.Hour, nil),
+		Projection:     projection.NewService(candleRepo, p, time.Hour, models.USD),
+		Backtest:       backtest.NewService(candleRepo, time.Hour),
+		Calendar:       calendar.NewService(memory.NewEventRepository()),
+		Themes:         theme.NewService(memory.NewThemeRepository()),
+		Preferences:    preferences.NewService(memory.NewCoinOrderRepository()),
+		Charts:         chart.NewService(memory.NewChartRepository()),
+		OptIns:         optin.NewService(memory.NewOptInRepository()),
+		Incidents:      incident.NewService(memory.NewIncidentRepository()),
+		Coins:          coins.NewService(stubDirectory{}),
+		Market:         market.NewService(prices, models.USD, time.Minute),
+		PriceHistory:   pricehistory.NewService(stubHistory{}, memory.NewDailyPriceRepository()),
+		Candles:        candleRepo,
+		CandleInterval: time.Hour,
+		Events:         events.NewBus(),
+		Logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+}
+
+func do(t *testing.T, s *Server, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlePositionSize(t *testing.T) {
+	s := newTestServer()
+
+	rec := do(t, s, http.MethodPost, "/api/v1/tools/position-size",
+		`{"account_size":10000,"risk_percent":1,"entry_price":50000,"stop_loss":48000}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var size risk.PositionSize
+	json.NewDecoder(rec.Body).Decode(&size)
+	if size.Quantity != 0.05 {
+		t.Errorf("Expected quantity 0.05, got %f", size.Quantity)
+	}
+
+	rec = do(t, s, http.MethodPost, "/api/v1/tools/position-size", `{"account_size":0}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422, got %d", rec.Code)
+	}
+}
+
+func TestHandleStressTest(t *testing.T) {
+	s := newTestServer()
+
+	rec := do(t, s, http.MethodPost, "/api/v1/tools/stress-test",
+		`{"holdings":{"bitcoin":2},"scenarios":[{"name":"crash","shocks":{"bitcoin":-50}}]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var results []risk.ScenarioResult
+	json.NewDecoder(rec.Body).Decode(&results)
+	if len(results) != 1 || results[0].Impact != -55000 || results[0].ImpactPercent != -50 {
+		t.Errorf("Expected a -55000 (-50%%) impact, got %+v", results)
+	}
+
+	rec = do(t, s, http.MethodPost, "/api/v1/tools/stress-test", `{"holdings":{}}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422, got %d", rec.Code)
+	}
+}
+
+func TestWatchOrderEndpoints(t *testing.T) {
+	s := newTestServer()
+
+	rec := do(t, s, http.MethodPost, "/api/v1/watch-orders",
+		`{"crypto_id":"bitcoin","entry_price":50000,"stop_loss":49000,"quantity":1}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var created models.WatchOrder
+	json.NewDecoder(rec.Body).Decode(&created)
+
+	rec = do(t, s, http.MethodGet, "/api/v1/watch-orders", "")
+	var statuses []risk.WatchOrderStatus
+	json.NewDecoder(rec.Body).Decode(&statuses)
+	if len(statuses) != 1 || statuses[0].RMultiple != 5 {
+		t.Errorf("Expected one order at 5R, got %+v", statuses)
+	}
+
+	rec = do(t, s, http.MethodDelete, "/api/v1/watch-orders/"+created.ID, "")
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", rec.Code)
+	}
+	rec = do(t, s, http.MethodDelete, "/api/v1/watch-orders/"+created.ID, "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", rec.Code)
+	}
+}
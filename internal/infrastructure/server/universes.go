@@ -0,0 +1,36 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"crypto-dashboard/internal/application/format"
+	"crypto-dashboard/internal/application/universe"
+)
+
+// handleListUniverses returns the definitions of the named universes
+func (s *Server) handleListUniverses(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"universes": s.services.Universes.List()})
+}
+
+// handleUniverse returns a universe with the current market data of its members in rank order
+func (s *Server) handleUniverse(w http.ResponseWriter, r *http.Request) {
+	u, err := s.services.Universes.Get(r.PathValue("name"))
+	if errors.Is(err, universe.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	members, err := s.services.Universes.Members(u.Name)
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+
+	currency := s.services.Poller.Currency()
+	writeJSON(w, http.StatusOK, map[string]any{
+		"universe": u,
+		"currency": currency,
+		"prices":   members,
+		"format":   format.ForPrices(currency, members),
+	})
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/universe"
+	"crypto-dashboard/internal/domain/models"
+)
+
+type stubUniverseSource struct{}
+
+func (stubUniverseSource) GetUniverse(u models.Universe, currency models.Currency) ([]models.CryptoPrice, error) {
+	return []models.CryptoPrice{{ID: "bitcoin", Currency: currency}, {ID: "ethereum", Currency: currency}}, nil
+}
+
+func TestUniverses(t *testing.T) {
+	universes, err := universe.NewService(stubUniverseSource{}, models.USD, time.Minute,
+		models.Universe{Name: "top-100", Kind: models.UniverseMarketCap, Size: 100},
+		models.Universe{Name: "top-volume", Kind: models.UniverseVolume, Size: 50},
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	services := newTestServer().services
+	services.Universes = universes
+	s := New(0, services)
+
+	rec := do(t, s, http.MethodGet, "/api/v1/universes", "")
+	var list struct {
+		Universes []models.Universe `json:"universes"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("Expected universes, got %d: %s", rec.Code, rec.Body)
+	}
+	if len(list.Universes) != 2 || list.Universes[1].Kind != models.UniverseVolume {
+		t.Errorf("Unexpected universes: %+v", list.Universes)
+	}
+
+	rec = do(t, s, http.MethodGet, "/api/v1/universes/top-volume", "")
+	var resp struct {
+		Universe models.Universe      `json:"universe"`
+		Prices   []models.CryptoPrice `json:"prices"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("Expected members, got %d: %s", rec.Code, rec.Body)
+	}
+	if resp.Universe.Name != "top-volume" || len(resp.Prices) != 2 || resp.Prices[0].ID != "bitcoin" {
+		t.Errorf("Unexpected universe: %+v", resp)
+	}
+
+	if rec := do(t, s, http.MethodGet, "/api/v1/universes/defi", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an undefined universe, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,12 @@
+package server
+
+import "net/http"
+
+// handleUsage returns the calls and bytes of each provider per UTC day, most
+// recent first, with what is left of today's call budgets
+func (s *Server) handleUsage(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"days":    s.services.Usage.Days(),
+		"budgets": s.services.Usage.Budgets(),
+	})
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"crypto-dashboard/internal/infrastructure/api"
+)
+
+func TestUsage(t *testing.T) {
+	if rec := do(t, newTestServer(), http.MethodGet, "/api/v1/admin/usage", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without usage tracking, got %d", rec.Code)
+	}
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"gecko_says":"(V3) To the Moon!"}`))
+	}))
+	defer upstream.Close()
+	usage := api.NewUsage()
+	usage.SetBudget("coingecko", 100, 0.1)
+	client := &http.Client{Transport: api.Chain(nil, usage.Middleware("coingecko"))}
+	resp, err := client.Get(upstream.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	services := newTestServer().services
+	services.Usage = usage
+	rec := do(t, New(0, services), http.MethodGet, "/api/v1/admin/usage", "")
+	var body struct {
+		Days    []api.ProviderUsage `json:"days"`
+		Budgets []api.Budget        `json:"budgets"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("Expected the usage, got %d: %s", rec.Code, rec.Body)
+	}
+	if len(body.Days) != 1 || body.Days[0].Calls != 1 || body.Days[0].Bytes != 34 {
+		t.Errorf("Unexpected days: %+v", body.Days)
+	}
+	if len(body.Budgets) != 1 || body.Budgets[0].Remaining != 99 || body.Budgets[0].Throttled {
+		t.Errorf("Unexpected budgets: %+v", body.Budgets)
+	}
+}
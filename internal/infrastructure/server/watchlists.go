@@ -0,0 +1,123 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"crypto-dashboard/internal/application/watchlist"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// sessionHeader identifies the user or session owning watchlists
+const sessionHeader = "X-Session-ID"
+
+// sessionOwner returns the watchlist owner of the request
+func sessionOwner(r *http.Request) string {
+	if owner := strings.TrimSpace(r.Header.Get(sessionHeader)); owner != "" {
+		return owner
+	}
+	return models.DefaultOwner
+}
+
+func (s *Server) handleListWatchlists(w http.ResponseWriter, r *http.Request) {
+	lists, err := s.services.Watchlists.List(sessionOwner(r))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, lists)
+}
+
+func (s *Server) handleCreateWatchlist(w http.ResponseWriter, r *http.Request) {
+	var list models.Watchlist
+	if err := decodeJSON(r, &list); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	list.Owner = sessionOwner(r)
+
+	created, err := s.services.Watchlists.Create(list)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) handleGetWatchlist(w http.ResponseWriter, r *http.Request) {
+	list, ok := s.ownedWatchlist(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) handleUpdateWatchlist(w http.ResponseWriter, r *http.Request) {
+	list, ok := s.ownedWatchlist(w, r)
+	if !ok {
+		return
+	}
+	var update watchlist.Update
+	if err := decodeJSON(r, &update); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	updated, err := s.services.Watchlists.Update(list.ID, update)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+func (s *Server) handleDeleteWatchlist(w http.ResponseWriter, r *http.Request) {
+	list, ok := s.ownedWatchlist(w, r)
+	if !ok {
+		return
+	}
+	if err := s.services.Watchlists.Delete(list.ID); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// reorderRequest is the body of the watchlist reorder endpoint
+type reorderRequest struct {
+	IDs []string `json:"ids"`
+}
+
+func (s *Server) handleReorderWatchlists(w http.ResponseWriter, r *http.Request) {
+	var req reorderRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	lists, err := s.services.Watchlists.Reorder(sessionOwner(r), req.IDs)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, lists)
+}
+
+// ownedWatchlist loads the watchlist of the path, writing a 404 when it does not
+// exist or belongs to another session
+func (s *Server) ownedWatchlist(w http.ResponseWriter, r *http.Request) (models.Watchlist, bool) {
+	list, err := s.services.Watchlists.Get(r.PathValue("id"))
+	if err == nil && list.Owner != sessionOwner(r) {
+		err = watchlist.ErrNotFound
+	}
+	if errors.Is(err, watchlist.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return models.Watchlist{}, false
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return models.Watchlist{}, false
+	}
+	return list, true
+}
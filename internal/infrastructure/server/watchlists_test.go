@@ -0,0 +1,80 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func doAs(t *testing.T, s *Server, session, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(sessionHeader, session)
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWatchlistEndpoints(t *testing.T) {
+	s := newTestServer()
+
+	rec := do(t, s, http.MethodPost, "/api/v1/watchlists", `{"name":"Majors","coins":["bitcoin","ethereum"]}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var majors models.Watchlist
+	json.NewDecoder(rec.Body).Decode(&majors)
+
+	rec = do(t, s, http.MethodPost, "/api/v1/watchlists", `{"name":"Alts","coins":["solana"]}`)
+	var alts models.Watchlist
+	json.NewDecoder(rec.Body).Decode(&alts)
+
+	if coins := s.services.Poller.Coins(); strings.Join(coins, ",") != "bitcoin,ethereum,solana" {
+		t.Errorf("Expected the poller to track every watchlist, got %v", coins)
+	}
+
+	rec = do(t, s, http.MethodPatch, "/api/v1/watchlists/"+alts.ID, `{"name":"Altcoins","archived":true}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if coins := s.services.Poller.Coins(); len(coins) != 2 {
+		t.Errorf("Expected archived watchlist to stop being tracked, got %v", coins)
+	}
+
+	rec = do(t, s, http.MethodPut, "/api/v1/watchlists/order", `{"ids":["`+alts.ID+`","`+majors.ID+`"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = do(t, s, http.MethodGet, "/api/v1/watchlists", "")
+	var lists []models.Watchlist
+	json.NewDecoder(rec.Body).Decode(&lists)
+	if len(lists) != 2 || lists[0].Name != "Altcoins" {
+		t.Errorf("Expected renamed Altcoins first, got %+v", lists)
+	}
+
+	// Other sessions neither see nor modify the lists
+	if rec := doAs(t, s, "bob", http.MethodGet, "/api/v1/watchlists", ""); strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Errorf("Expected no watchlists for another session, got %s", rec.Body.String())
+	}
+	if rec := doAs(t, s, "bob", http.MethodDelete, "/api/v1/watchlists/"+majors.ID, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for another session, got %d", rec.Code)
+	}
+
+	rec = do(t, s, http.MethodDelete, "/api/v1/watchlists/"+majors.ID, "")
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", rec.Code)
+	}
+	if coins := s.services.Poller.Coins(); len(coins) != 0 {
+		t.Errorf("Expected no tracked coins, got %v", coins)
+	}
+
+	rec = do(t, s, http.MethodPost, "/api/v1/watchlists", `{"coins":["bitcoin"]}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 without a name, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,22 @@
+package server
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// webAssets is the single-page dashboard compiled into the binary,
+// so no separate frontend build is needed
+//
+//go:embed web
+var webAssets embed.FS
+
+// webHandler serves the embedded dashboard
+func webHandler() http.Handler {
+	sub, err := fs.Sub(webAssets, "web")
+	if err != nil {
+		panic(err)
+	}
+	return http.FileServer(http.FS(sub))
+}
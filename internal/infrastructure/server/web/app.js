@@ -0,0 +1,460 @@
+// Minimal dashboard: polls the prices endpoint and draws the history of the selected coin.
+(function () {
+  "use strict";
+
+  const REFRESH_MS = 15000;
+  const GLOBAL_REFRESH_MS = 60000;
+  let selected = null;
+  let currency = "usd";
+  // Scale of a saved chart; null draws charts since inception on a log scale and the rest linear
+  let logScale = null;
+
+  const tbody = document.querySelector("#prices tbody");
+  const updated = document.getElementById("updated");
+  const global = document.getElementById("global");
+  const title = document.getElementById("chart-title");
+  const canvas = document.getElementById("chart");
+  const inception = document.getElementById("inception");
+
+  // Formatting hints of the last prices response; the server decides the decimals
+  let hints = null;
+
+  // Prices arrive as decimal strings; Intl formats them without a float round trip
+  function formatPrice(value, id) {
+    if (!hints) {
+      return new Intl.NumberFormat(undefined, {
+        style: "currency",
+        currency: currency.toUpperCase(),
+        maximumSignificantDigits: 8,
+      }).format(value);
+    }
+    const decimals = (hints.price_decimals || {})[id] ?? hints.decimals;
+    const amount = new Intl.NumberFormat(undefined, {
+      minimumFractionDigits: decimals,
+      maximumFractionDigits: decimals,
+    }).format(value);
+    return hints.symbol_position === "suffix" ? amount + " " + hints.symbol : hints.symbol + amount;
+  }
+
+  // When the API requires a token, ask for one once and trade it for a session cookie
+  let login = null;
+  function signIn() {
+    if (!login) {
+      login = (async () => {
+        const token = window.prompt("API token");
+        if (!token) {
+          return false;
+        }
+        const resp = await fetch("/api/v1/session", {
+          method: "POST",
+          headers: { "Content-Type": "application/json" },
+          body: JSON.stringify({ token: token }),
+        });
+        return resp.ok;
+      })().finally(() => { login = null; });
+    }
+    return login;
+  }
+
+  async function getJSON(url) {
+    let resp = await fetch(url);
+    if (resp.status === 401 && await signIn()) {
+      resp = await fetch(url);
+    }
+    if (!resp.ok) {
+      throw new Error(url + " returned " + resp.status);
+    }
+    return resp.json();
+  }
+
+  async function refreshPrices() {
+    try {
+      const data = await getJSON("/api/v1/prices");
+      currency = data.currency || "usd";
+      hints = data.format || null;
+      renderTable(data.prices || [], data.pinned || [], data.allocation || null);
+      updated.textContent = "Updated " + new Date().toLocaleTimeString();
+      if (!selected && data.prices && data.prices.length) {
+        selectCoin(data.prices[0].id);
+      } else if (selected) {
+        refreshChart();
+      }
+    } catch (err) {
+      updated.textContent = err.message;
+    }
+  }
+
+  // Compact amounts use the server's notation when the response carries it
+  function formatCompact(value, format, field) {
+    const compact = format && format.compact && format.compact[field];
+    if (compact) {
+      return format.symbol_position === "suffix" ? compact + " " + format.symbol : format.symbol + compact;
+    }
+    return new Intl.NumberFormat(undefined, {
+      style: "currency",
+      currency: currency.toUpperCase(),
+      notation: "compact",
+      maximumFractionDigits: 2,
+    }).format(value);
+  }
+
+  async function refreshGlobal() {
+    try {
+      const g = await getJSON("/api/v1/global");
+      const change = g.market_cap_change_percentage_24h || 0;
+      global.innerHTML =
+        "Market cap " + formatCompact(g.total_market_cap, g.format, "total_market_cap") +
+        ' <span class="' + (change >= 0 ? "up" : "down") + '">' + change.toFixed(2) + "%</span>" +
+        " · Volume 24h " + formatCompact(g.total_volume, g.format, "total_volume") +
+        " · BTC " + g.btc_dominance.toFixed(1) + "%" +
+        " · ETH " + g.eth_dominance.toFixed(1) + "%";
+    } catch (err) {
+      // The overview is optional context; keep the last one on failure
+    }
+  }
+
+  // ETF flows are only served when a source is configured; the panel stays hidden otherwise
+  const etfPanel = document.getElementById("etf-panel");
+  const etfBody = document.querySelector("#etf tbody");
+
+  function formatFlow(value) {
+    const text = new Intl.NumberFormat(undefined, {
+      style: "currency",
+      currency: "USD",
+      notation: "compact",
+      signDisplay: "always",
+      maximumFractionDigits: 1,
+    }).format(value);
+    return '<span class="' + (value >= 0 ? "up" : "down") + '">' + text + "</span>";
+  }
+
+  async function refreshETF() {
+    try {
+      const data = await getJSON("/api/v1/etf/flows");
+      etfBody.innerHTML = "";
+      data.summaries.forEach(function (f) {
+        const tr = document.createElement("tr");
+        tr.innerHTML =
+          "<td>" + f.asset + "</td>" +
+          "<td>" + f.date.slice(0, 10) + "</td>" +
+          '<td class="num">' + formatFlow(f.latest) + "</td>" +
+          '<td class="num">' + formatFlow(f.week) + "</td>" +
+          '<td class="num">' + formatFlow(f.month) + "</td>";
+        etfBody.appendChild(tr);
+      });
+      etfPanel.hidden = data.summaries.length === 0;
+    } catch (err) {
+      etfPanel.hidden = true;
+    }
+  }
+
+  // Pinned coins are listed first by the server; the star toggles the pin
+  async function togglePin(id, pinned) {
+    try {
+      await sendJSON(pinned ? "DELETE" : "PUT", "/api/v1/preferences/pins/" + encodeURIComponent(id));
+      refreshPrices();
+    } catch (err) {
+      updated.textContent = err.message;
+    }
+  }
+
+  // The share of each coin in the ledger portfolio, flagged when it drifted
+  // out of its band around the target allocation
+  const allocationHead = document.getElementById("allocation-head");
+  function allocationCell(a) {
+    if (!a) {
+      return '<td class="num"></td>';
+    }
+    const share = (a.weight * 100).toFixed(1) + "%";
+    if (!a.drifted) {
+      return '<td class="num">' + share + "</td>";
+    }
+    const target = (a.target * 100).toFixed(1) + "%";
+    return '<td class="num drifted" title="Target ' + target + ", drifted " +
+      (a.drift > 0 ? "+" : "") + a.drift.toFixed(1) + ' points">⚠ ' + share + "</td>";
+  }
+
+  function renderTable(prices, pinned, allocation) {
+    tbody.innerHTML = "";
+    allocationHead.hidden = !allocation;
+    prices.forEach(function (p, i) {
+      const tr = document.createElement("tr");
+      if (p.id === selected) {
+        tr.className = "selected";
+      }
+      const isPinned = pinned.includes(p.id);
+      const change = p.price_change_percentage_24h || 0;
+      tr.innerHTML =
+        '<td><button class="pin" title="' + (isPinned ? "Unpin" : "Pin to top") + '">' + (isPinned ? "★" : "☆") + "</button>" + (i + 1) + "</td>" +
+        // Logos come from the server's icon cache, never the provider's CDN
+        '<td><img class="icon" src="/assets/icons/' + encodeURIComponent(p.id) + '.png?size=32" alt="" loading="lazy">' +
+        (p.name || p.id) + "</td>" +
+        // Cached prices the server could not refresh are dimmed; coins the
+        // provider stopped updating show no price since it would be frozen
+        (p.inactive
+          ? '<td class="num stale" title="Last updated ' + new Date(p.last_updated).toLocaleString() + '">inactive</td>'
+          : (p.stale
+            ? '<td class="num stale" title="Stale since ' + new Date(p.stale_since).toLocaleString() + '">'
+            : '<td class="num">') +
+            formatPrice(p.current_price, p.id) + "</td>") +
+        '<td class="num ' + (change >= 0 ? "up" : "down") + '">' + change.toFixed(2) + "%</td>" +
+        (allocation ? allocationCell(allocation[p.id]) : "");
+      tr.querySelector("img.icon").addEventListener("error", function () { this.remove(); });
+      tr.querySelector("button.pin").addEventListener("click", function (event) {
+        event.stopPropagation();
+        togglePin(p.id, isPinned);
+      });
+      tr.addEventListener("click", function () { selectCoin(p.id); });
+      tbody.appendChild(tr);
+    });
+  }
+
+  function selectCoin(id) {
+    selected = id;
+    Array.from(tbody.rows).forEach(function (row) {
+      row.classList.toggle("selected", row.cells[1].textContent === id);
+    });
+    refreshChart();
+  }
+
+  async function refreshChart() {
+    try {
+      const url = "/api/v1/coins/" + encodeURIComponent(selected) + "/history";
+      if (inception.checked) {
+        // Daily closes since genesis span orders of magnitude, so they are drawn on a log scale
+        const max = await getJSON(url + "?range=max");
+        const daily = (max.points || []).map(function (p) {
+          return { t: new Date(p.time), v: p.price };
+        });
+        title.textContent = selected + " since " + (daily.length ? daily[0].t.toLocaleDateString() : "inception");
+        drawLine(daily, [], logScale === null ? true : logScale);
+        return;
+      }
+      const history = await getJSON(url);
+      // Prefer closed candles, fall back to the recent polled points
+      let series = (history.candles || []).map(function (c) {
+        return { t: new Date(c.close_time), v: c.close };
+      });
+      if (series.length < 2) {
+        series = (history.points || []).map(function (p) {
+          return { t: new Date(p.time), v: p.price };
+        });
+      }
+      title.textContent = selected + " (" + series.length + " points)";
+      drawLine(series, history.incidents || [], logScale === null ? false : logScale);
+    } catch (err) {
+      title.textContent = err.message;
+    }
+  }
+
+  // Incidents are shaded over the time range they affect; ongoing ones reach the right edge
+  function shadeIncidents(ctx, series, incidents, pad, w, h) {
+    const t0 = series[0].t.getTime(), t1 = series[series.length - 1].t.getTime();
+    const span = t1 - t0 || 1;
+    const xAt = function (t) { return pad + Math.min(Math.max((t - t0) / span, 0), 1) * (w - 2 * pad); };
+    ctx.fillStyle = "rgba(231, 76, 60, 0.15)";
+    incidents.forEach(function (incident) {
+      const from = xAt(new Date(incident.started).getTime());
+      const to = incident.resolved ? xAt(new Date(incident.resolved).getTime()) : w - pad;
+      if (to > from) {
+        ctx.fillRect(from, pad, to - from, h - 2 * pad);
+      }
+    });
+  }
+
+  function drawLine(series, incidents, logScale) {
+    const ctx = canvas.getContext("2d");
+    const w = canvas.width, h = canvas.height, pad = 40;
+    ctx.clearRect(0, 0, w, h);
+    if (series.length < 2) {
+      ctx.fillStyle = "#8a8f98";
+      ctx.fillText("Not enough data yet", pad, h / 2);
+      return;
+    }
+
+    const values = series.map(function (p) { return p.v; });
+    const min = Math.min.apply(null, values);
+    const max = Math.max.apply(null, values);
+    const scale = logScale ? Math.log10 : function (v) { return v; };
+    const range = scale(max) - scale(min) || 1;
+    const x = function (i) { return pad + (i / (series.length - 1)) * (w - 2 * pad); };
+    const y = function (v) { return h - pad - ((scale(v) - scale(min)) / range) * (h - 2 * pad); };
+    const mid = logScale ? Math.sqrt(min * max) : (min + max) / 2;
+
+    shadeIncidents(ctx, series, incidents, pad, w, h);
+
+    ctx.strokeStyle = "#262a33";
+    ctx.fillStyle = "#8a8f98";
+    ctx.font = "12px sans-serif";
+    [min, mid, max].forEach(function (v) {
+      ctx.beginPath();
+      ctx.moveTo(pad, y(v));
+      ctx.lineTo(w - pad, y(v));
+      ctx.stroke();
+      ctx.fillText(formatPrice(v, selected), 4, y(v) - 4);
+    });
+
+    ctx.strokeStyle = values[values.length - 1] >= values[0] ? "#2ecc71" : "#e74c3c";
+    ctx.lineWidth = 2;
+    ctx.beginPath();
+    series.forEach(function (p, i) {
+      if (i === 0) {
+        ctx.moveTo(x(i), y(p.v));
+      } else {
+        ctx.lineTo(x(i), y(p.v));
+      }
+    });
+    ctx.stroke();
+  }
+
+  // Command palette: Ctrl+K or Cmd+K searches coins, pages and actions in one list
+  const palette = document.getElementById("palette");
+  const paletteQuery = document.getElementById("palette-query");
+  const paletteList = document.getElementById("palette-results");
+  let paletteResults = [];
+  let paletteActive = 0;
+  let paletteTimer = null;
+
+  async function sendJSON(method, url, body) {
+    const init = { method: method, headers: { "Content-Type": "application/json" }, body: JSON.stringify(body) };
+    let resp = await fetch(url, init);
+    if (resp.status === 401 && await signIn()) {
+      resp = await fetch(url, init);
+    }
+    if (!resp.ok) {
+      throw new Error(url + " returned " + resp.status);
+    }
+    return resp.json();
+  }
+
+  async function searchPalette() {
+    try {
+      const data = await getJSON("/api/v1/command-palette?q=" + encodeURIComponent(paletteQuery.value));
+      paletteResults = data.results || [];
+    } catch (err) {
+      paletteResults = [];
+    }
+    paletteActive = 0;
+    renderPalette();
+  }
+
+  function renderPalette() {
+    paletteList.innerHTML = "";
+    paletteResults.forEach(function (entry, i) {
+      const li = document.createElement("li");
+      li.setAttribute("role", "option");
+      li.className = i === paletteActive ? "active" : "";
+      li.textContent = entry.title;
+      const hint = document.createElement("span");
+      hint.className = "muted";
+      hint.textContent = entry.hint || entry.kind;
+      li.appendChild(hint);
+      li.addEventListener("click", function () { runPalette(entry); });
+      paletteList.appendChild(li);
+    });
+  }
+
+  // Actions that need input ask for it; the rest navigate or call the API directly
+  async function runPalette(entry) {
+    palette.close();
+    try {
+      if (entry.kind === "coin") {
+        selectCoin(entry.id);
+      } else if (entry.kind === "page" || entry.method === "GET") {
+        window.location.href = entry.path;
+      } else if (entry.id === "toggle-privacy") {
+        const privacy = await getJSON(entry.path);
+        await sendJSON("PUT", entry.path, { enabled: !privacy.enabled });
+      } else if (entry.id === "change-theme") {
+        const mode = window.prompt("Theme (dark, light or high-contrast)", "dark");
+        if (mode) {
+          await sendJSON("PUT", entry.path, { mode: mode });
+          window.location.reload();
+        }
+      } else if (entry.id === "create-watchlist") {
+        const name = window.prompt("Watchlist name");
+        if (name) {
+          await sendJSON("POST", entry.path, { name: name });
+        }
+      } else if (entry.id === "save-chart" && selected) {
+        const name = window.prompt("Chart name", selected);
+        if (name) {
+          const chart = await sendJSON("POST", entry.path, {
+            name: name,
+            crypto_id: selected,
+            range: inception.checked ? "max" : "recent",
+            scale: logScale === null ? "" : (logScale ? "log" : "linear"),
+          });
+          window.history.replaceState(null, "", chart.links.permalink);
+          updated.textContent = "Chart saved, share " + window.location.href;
+        }
+      } else if (entry.id === "add-alert") {
+        const coin = selected || window.prompt("Coin ID");
+        const threshold = coin ? parseFloat(window.prompt("Alert when " + coin + " rises above")) : 0;
+        if (threshold > 0) {
+          await sendJSON("POST", entry.path, { crypto_id: coin, kind: "price_above", threshold: threshold });
+        }
+      }
+    } catch (err) {
+      updated.textContent = err.message;
+    }
+  }
+
+  document.addEventListener("keydown", function (e) {
+    if ((e.ctrlKey || e.metaKey) && e.key.toLowerCase() === "k") {
+      e.preventDefault();
+      paletteQuery.value = "";
+      palette.showModal();
+      searchPalette();
+    }
+  });
+
+  paletteQuery.addEventListener("input", function () {
+    clearTimeout(paletteTimer);
+    paletteTimer = setTimeout(searchPalette, 150);
+  });
+
+  paletteQuery.addEventListener("keydown", function (e) {
+    if (e.key === "ArrowDown" || e.key === "ArrowUp") {
+      e.preventDefault();
+      const step = e.key === "ArrowDown" ? 1 : -1;
+      paletteActive = (paletteActive + step + paletteResults.length) % (paletteResults.length || 1);
+      renderPalette();
+    } else if (e.key === "Enter" && paletteResults[paletteActive]) {
+      e.preventDefault();
+      runPalette(paletteResults[paletteActive]);
+    }
+  });
+
+  // Saved charts open from their permalink, /?chart=<id>
+  async function openChart(id) {
+    try {
+      const chart = await getJSON("/api/v1/charts/" + encodeURIComponent(id));
+      inception.checked = chart.range === "max";
+      logScale = chart.scale === "log";
+      selectCoin(chart.crypto_id);
+    } catch (err) {
+      title.textContent = err.message;
+    }
+  }
+
+  inception.addEventListener("change", function () {
+    logScale = null;
+    if (selected) {
+      refreshChart();
+    }
+  });
+
+  const shared = new URLSearchParams(window.location.search);
+  if (shared.get("chart")) {
+    openChart(shared.get("chart"));
+  } else if (shared.get("coin")) {
+    selectCoin(shared.get("coin"));
+  }
+  refreshPrices();
+  refreshGlobal();
+  refreshETF();
+  setInterval(refreshPrices, REFRESH_MS);
+  setInterval(refreshGlobal, GLOBAL_REFRESH_MS);
+  setInterval(refreshETF, GLOBAL_REFRESH_MS);
+})();
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"crypto-dashboard/internal/application/pricehistory"
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/repository/memory"
+)
+
+func TestWebAssets(t *testing.T) {
+	s := newTestServer()
+
+	tests := []struct {
+		path        string
+		contentType string
+	}{
+		{path: "/", contentType: "text/html"},
+		{path: "/app.js", contentType: "javascript"},
+		{path: "/style.css", contentType: "text/css"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := do(t, s, http.MethodGet, tt.path, "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", rec.Code)
+			}
+			if ct := rec.Header().Get("Content-Type"); !strings.Contains(ct, tt.contentType) {
+				t.Errorf("Expected content type containing %q, got %q", tt.contentType, ct)
+			}
+		})
+	}
+}
+
+func TestHandleHistory(t *testing.T) {
+	s := newTestServer()
+	s.services.Poller.PollOnce()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		s.services.Candles.SaveCandle(time.Hour, models.NewCandle("bitcoin", float64(i), start.Add(time.Duration(i)*time.Hour), time.Hour))
+	}
+	// Only the bitcoin incident during the returned candles is shaded
+	before := start.Add(30 * time.Minute)
+	s.services.Incidents.Record(models.Incident{Description: "earlier", Started: start, Resolved: &before})
+	s.services.Incidents.Record(models.Incident{CryptoID: "ethereum", Description: "other coin", Started: start.Add(2 * time.Hour)})
+	s.services.Incidents.Record(models.Incident{CryptoID: "bitcoin", Description: "bad print", Started: start.Add(90 * time.Minute)})
+
+	rec := do(t, s, http.MethodGet, "/api/v1/coins/bitcoin/history?limit=2", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var body struct {
+		Points    []models.PricePoint `json:"points"`
+		Candles   []models.Candle     `json:"candles"`
+		Incidents []models.Incident   `json:"incidents"`
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if len(body.Points) != 1 || len(body.Candles) != 2 {
+		t.Errorf("Expected 1 point and 2 candles, got %d and %d", len(body.Points), len(body.Candles))
+	}
+	if len(body.Incidents) != 1 || body.Incidents[0].Description != "bad print" {
+		t.Errorf("Expected the overlapping bitcoin incident, got %+v", body.Incidents)
+	}
+
+	rec = do(t, s, http.MethodGet, "/api/v1/coins/bitcoin/history?limit=abc", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
+
+type stubRange struct{}
+
+func (stubRange) GetPriceRange(cryptoID string, currency models.Currency, from, to time.Time) ([]models.PricePoint, error) {
+	var points []models.PricePoint
+	for t := from; !t.After(to); t = t.Add(24 * time.Hour) {
+		points = append(points, models.PricePoint{Price: float64(len(points)+1) * 100, Time: t})
+	}
+	return points, nil
+}
+
+func (stubRange) CoinInfo(id string) (models.CoinInfo, error) {
+	genesis := time.Now().UTC().AddDate(0, 0, -3)
+	return models.CoinInfo{ID: id, GenesisDate: genesis.Format(time.DateOnly)}, nil
+}
+
+func TestHandleHistory_SinceInception(t *testing.T) {
+	if rec := do(t, newTestServer(), http.MethodGet, "/api/v1/coins/bitcoin/history?range=max", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without the inception service, got %d", rec.Code)
+	}
+
+	services := newTestServer().services
+	services.Inception = pricehistory.NewInception(stubRange{}, memory.NewDailySeriesRepository())
+	s := New(0, services)
+
+	rec := do(t, s, http.MethodGet, "/api/v1/coins/bitcoin/history?range=max&transform=cumulative-returns", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var body struct {
+		Range  string              `json:"range"`
+		Points []models.PricePoint `json:"points"`
+		Min    float64             `json:"min"`
+		Max    float64             `json:"max"`
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if body.Range != "max" || len(body.Points) != 3 || body.Min != 0 || body.Max != 2 {
+		t.Errorf("Unexpected series: %+v", body)
+	}
+
+	if rec := do(t, s, http.MethodGet, "/api/v1/coins/no-such-coin/history?range=max", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a coin neither tracked nor listed, got %d", rec.Code)
+	}
+	if rec := do(t, s, http.MethodGet, "/api/v1/coins/bitcoin/history?range=week", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown range, got %d", rec.Code)
+	}
+}
+
+func TestHandleHistory_Transform(t *testing.T) {
+	s := newTestServer()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, price := range []float64{50, 100, 75} {
+		s.services.Candles.SaveCandle(time.Hour, models.NewCandle("bitcoin", price, start.Add(time.Duration(i)*time.Hour), time.Hour))
+	}
+
+	rec := do(t, s, http.MethodGet, "/api/v1/coins/bitcoin/history?transform=rebased", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var body struct {
+		Transform string              `json:"transform"`
+		Candles   []models.PricePoint `json:"candles"`
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if body.Transform != "rebased" || len(body.Candles) != 3 || body.Candles[1].Price != 200 || body.Candles[2].Price != 150 {
+		t.Errorf("Expected closes rebased to 100, got %+v", body)
+	}
+	if !body.Candles[0].Time.Equal(start.Add(time.Hour)) {
+		t.Errorf("Expected closes at the candle close time, got %s", body.Candles[0].Time)
+	}
+
+	if rec := do(t, s, http.MethodGet, "/api/v1/coins/bitcoin/history?transform=zscore", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown transform, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/format"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// widgetTop is the number of holdings, or coins without holdings, in the widget
+const widgetTop = 3
+
+// widgetMaxAge is how long widgets may cache the summary, in seconds
+const widgetMaxAge = "60"
+
+// widgetSummary is the payload of /api/v1/widget, small enough for home-screen
+// widgets. Amounts are strings with two decimals so scripts can show them as is.
+type widgetSummary struct {
+	Currency     string       `json:"currency"`
+	Total        string       `json:"total"`
+	Change24h    string       `json:"change_24h"`
+	Change24hPct float64      `json:"change_24h_pct"`
+	Top          []widgetItem `json:"top"`
+	Stale        bool         `json:"stale,omitempty"`
+	// Private is set in privacy mode, where amounts are hidden and only percentages remain
+	Private bool         `json:"private,omitempty"`
+	Format  format.Hints `json:"format"`
+}
+
+type widgetItem struct {
+	ID           string  `json:"id"`
+	Symbol       string  `json:"symbol"`
+	Price        string  `json:"price"`
+	Value        string  `json:"value,omitempty"`
+	Change24hPct float64 `json:"change_24h_pct"`
+}
+
+// handleWidget returns the portfolio total and its largest holdings, or the
+// first tracked coins when no ledger is loaded. Responses carry an ETag so
+// polling widgets get a 304 while nothing changed.
+func (s *Server) handleWidget(w http.ResponseWriter, r *http.Request) {
+	body, _ := json.Marshal(s.widgetSummary(s.services.Themes.Private(pageOwner(r))))
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	w.Header().Set("Cache-Control", "private, max-age="+widgetMaxAge)
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+func (s *Server) widgetSummary(private bool) widgetSummary {
+	currency := s.services.Poller.Currency()
+	summary := widgetSummary{Currency: strings.ToUpper(string(currency)), Top: []widgetItem{}, Format: format.NewHints(currency)}
+
+	if len(s.services.Holdings) == 0 {
+		snapshot := s.services.Poller.Snapshot()
+		for _, p := range snapshot[:min(widgetTop, len(snapshot))] {
+			summary.Top = append(summary.Top, widgetPrice(p))
+			summary.Stale = summary.Stale || p.Stale
+		}
+		summary.Total, summary.Change24h = "0.00", "0.00"
+		return summary
+	}
+
+	ids := make([]string, len(s.services.Holdings))
+	for i, h := range s.services.Holdings {
+		ids[i] = h.CryptoID
+	}
+	prices, _ := s.services.Poller.Prices(ids)
+	byID := make(map[string]models.CryptoPrice, len(prices))
+	for _, p := range prices {
+		byID[p.ID] = p
+	}
+
+	type valued struct {
+		item  widgetItem
+		value decimal.Decimal
+	}
+	var items []valued
+	// compared is the current value of the holdings whose value 24h ago is
+	// known, so the change only covers holdings on both sides
+	var total, compared, previous decimal.Decimal
+	for _, h := range s.services.Holdings {
+		p, ok := byID[h.CryptoID]
+		if !ok {
+			summary.Stale = true
+			continue
+		}
+		value := h.Quantity.Mul(p.CurrentPrice)
+		total = total.Add(value)
+		// The value 24h ago is recovered from the percentage change, which
+		// cannot be done for a coin that lost everything
+		if p.PriceChange24h > -100 {
+			compared = compared.Add(value)
+			previous = previous.Add(value.Div(decimal.NewFromFloat(1 + p.PriceChange24h/100)))
+		}
+		summary.Stale = summary.Stale || p.Stale
+
+		item := widgetPrice(p)
+		item.Value = value.StringFixed(2)
+		items = append(items, valued{item: item, value: value})
+	}
+
+	slices.SortStableFunc(items, func(a, b valued) int { return b.value.Cmp(a.value) })
+	for _, v := range items[:min(widgetTop, len(items))] {
+		summary.Top = append(summary.Top, v.item)
+	}
+	if previous.IsPositive() {
+		summary.Change24hPct = compared.Sub(previous).Div(previous).Mul(decimal.NewFromInt(100)).Round(2).InexactFloat64()
+	}
+	if private {
+		summary.Private = true
+		summary.Total, summary.Change24h = hiddenAmount, hiddenAmount
+		for i := range summary.Top {
+			summary.Top[i].Value = hiddenAmount
+		}
+		return summary
+	}
+	summary.Total = total.StringFixed(2)
+	summary.Format.AddCompact("total", total.InexactFloat64())
+	summary.Change24h = compared.Sub(previous).StringFixed(2)
+	return summary
+}
+
+func widgetPrice(p models.CryptoPrice) widgetItem {
+	symbol := strings.ToUpper(p.Symbol)
+	if symbol == "" {
+		symbol = p.ID
+	}
+	return widgetItem{ID: p.ID, Symbol: symbol, Price: liteAmount(p.CurrentPrice), Change24hPct: p.PriceChange24h}
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/application/poller"
+	"crypto-dashboard/internal/domain/models"
+	"crypto-dashboard/internal/infrastructure/export"
+)
+
+// movingPrices quotes every coin at 100 with its configured 24h change
+type movingPrices map[string]float64
+
+func (m movingPrices) FetchCryptoPrices(ids []string, currency models.Currency) ([]models.CryptoPrice, error) {
+	var prices []models.CryptoPrice
+	for _, id := range ids {
+		prices = append(prices, models.CryptoPrice{ID: id, Symbol: id[:3], CurrentPrice: decimal.NewFromInt(100), PriceChange24h: m[id], Currency: currency})
+	}
+	return prices, nil
+}
+
+func TestHandleWidget(t *testing.T) {
+	s := newTestServer()
+	s.services.Poller = poller.New(movingPrices{"bitcoin": 25, "ethereum": -20, "solana": 0, "dogecoin": 0}, time.Minute, models.USD,
+		[]string{"bitcoin", "ethereum", "solana", "dogecoin"})
+	s.services.Poller.PollOnce()
+	s.services.Holdings = []export.Holding{
+		{CryptoID: "bitcoin", Quantity: decimal.NewFromInt(10)},
+		{CryptoID: "ethereum", Quantity: decimal.NewFromInt(20)},
+		{CryptoID: "solana", Quantity: decimal.NewFromInt(1)},
+		{CryptoID: "dogecoin", Quantity: decimal.NewFromInt(5)},
+	}
+
+	rec := do(t, s, http.MethodGet, "/api/v1/widget", "")
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == "" || rec.Header().Get("Cache-Control") == "" {
+		t.Fatalf("Expected a cacheable response, got %d %v", rec.Code, rec.Header())
+	}
+	var summary widgetSummary
+	json.NewDecoder(rec.Body).Decode(&summary)
+	// 24h ago: bitcoin 800, ethereum 2500, solana 100, dogecoin 500 = 3900; now 3600
+	if summary.Total != "3600.00" || summary.Change24h != "-300.00" || summary.Change24hPct != -7.69 {
+		t.Errorf("Unexpected totals: %+v", summary)
+	}
+	if len(summary.Top) != 3 || summary.Top[0].ID != "ethereum" || summary.Top[1].ID != "bitcoin" || summary.Top[2].ID != "dogecoin" {
+		t.Errorf("Expected the three largest holdings, got %+v", summary.Top)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/widget", nil)
+	req.Header.Set("If-None-Match", rec.Header().Get("ETag"))
+	cached := httptest.NewRecorder()
+	s.Handler().ServeHTTP(cached, req)
+	if cached.Code != http.StatusNotModified || cached.Body.Len() != 0 {
+		t.Errorf("Expected 304 for an unchanged summary, got %d", cached.Code)
+	}
+}
+
+func TestHandleWidget_WipedOutCoin(t *testing.T) {
+	s := newTestServer()
+	s.services.Poller = poller.New(movingPrices{"bitcoin": 25, "terra-luna": -100}, time.Minute, models.USD, []string{"bitcoin", "terra-luna"})
+	s.services.Poller.PollOnce()
+	s.services.Holdings = []export.Holding{
+		{CryptoID: "bitcoin", Quantity: decimal.NewFromInt(10)},
+		{CryptoID: "terra-luna", Quantity: decimal.NewFromInt(10)},
+	}
+
+	var summary widgetSummary
+	json.NewDecoder(do(t, s, http.MethodGet, "/api/v1/widget", "").Body).Decode(&summary)
+	// The coin without a value 24h ago counts in the total but not in the change
+	if summary.Total != "2000.00" || summary.Change24h != "200.00" || summary.Change24hPct != 25 {
+		t.Errorf("Unexpected totals: %+v", summary)
+	}
+}
+
+func TestHandleWidget_PrivacyMode(t *testing.T) {
+	s := newTestServer()
+	s.services.Poller = poller.New(movingPrices{"bitcoin": 25}, time.Minute, models.USD, []string{"bitcoin"})
+	s.services.Poller.PollOnce()
+	s.services.Holdings = []export.Holding{{CryptoID: "bitcoin", Quantity: decimal.NewFromInt(10)}}
+	s.services.Themes.SetPrivacy("alice", true)
+
+	var summary widgetSummary
+	json.NewDecoder(do(t, s, http.MethodGet, "/api/v1/widget?session=alice", "").Body).Decode(&summary)
+	if !summary.Private || summary.Total != "hidden" || summary.Change24h != "hidden" || summary.Change24hPct != 25 {
+		t.Errorf("Expected hidden amounts with the percentage kept, got %+v", summary)
+	}
+	if len(summary.Top) != 1 || summary.Top[0].Value != "hidden" || summary.Top[0].Price != "100.00" {
+		t.Errorf("Expected prices but no holding values, got %+v", summary.Top)
+	}
+}
+
+func TestHandleWidget_WithoutHoldings(t *testing.T) {
+	s := newTestServer()
+	s.services.Poller.PollOnce()
+
+	var summary widgetSummary
+	json.NewDecoder(do(t, s, http.MethodGet, "/api/v1/widget", "").Body).Decode(&summary)
+	if summary.Total != "0.00" || len(summary.Top) != 1 || summary.Top[0].Price != "55000.00" {
+		t.Errorf("Expected the tracked coins without holdings, got %+v", summary)
+	}
+}
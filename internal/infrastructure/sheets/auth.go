@@ -0,0 +1,140 @@
+// Package sheets appends dashboard data to Google Sheets using a service account
+package sheets
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// scope grants read and write access to spreadsheets
+const scope = "https://www.googleapis.com/auth/spreadsheets"
+
+// defaultTokenURI is used when the credentials do not specify one
+const defaultTokenURI = "https://oauth2.googleapis.com/token"
+
+// ServiceAccount holds the fields of a Google service account key file used for authentication
+type ServiceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// LoadServiceAccount reads a service account JSON key file
+func LoadServiceAccount(path string) (ServiceAccount, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ServiceAccount{}, fmt.Errorf("failed to read service account key: %w", err)
+	}
+	var sa ServiceAccount
+	if err := json.Unmarshal(data, &sa); err != nil {
+		return ServiceAccount{}, fmt.Errorf("failed to parse service account key: %w", err)
+	}
+	if sa.ClientEmail == "" || sa.PrivateKey == "" {
+		return ServiceAccount{}, errors.New("service account key must contain client_email and private_key")
+	}
+	if sa.TokenURI == "" {
+		sa.TokenURI = defaultTokenURI
+	}
+	return sa, nil
+}
+
+// tokenSource exchanges signed JWT assertions for access tokens and caches them until shortly before expiry
+type tokenSource struct {
+	account    ServiceAccount
+	key        *rsa.PrivateKey
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func newTokenSource(account ServiceAccount, httpClient *http.Client) (*tokenSource, error) {
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return nil, errors.New("service account private key is not PEM encoded")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse service account private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("service account private key is not an RSA key")
+	}
+	return &tokenSource{account: account, key: key, httpClient: httpClient}, nil
+}
+
+// Token returns a valid access token, requesting a new one when needed
+func (ts *tokenSource) Token() (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	now := time.Now()
+	if ts.token != "" && now.Before(ts.expires.Add(-time.Minute)) {
+		return ts.token, nil
+	}
+
+	assertion, err := ts.assertion(now)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	resp, err := ts.httpClient.Post(ts.account.TokenURI, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to request access token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned status code: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode access token: %w", err)
+	}
+	ts.token = body.AccessToken
+	ts.expires = now.Add(time.Duration(body.ExpiresIn) * time.Second)
+	return ts.token, nil
+}
+
+// assertion builds the RS256 signed JWT exchanged for an access token
+func (ts *tokenSource) assertion(now time.Time) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]any{
+		"iss":   ts.account.ClientEmail,
+		"scope": scope,
+		"aud":   ts.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, ts.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token assertion: %w", err)
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
@@ -0,0 +1,92 @@
+package sheets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client appends rows to the sheets of a single spreadsheet
+type Client struct {
+	baseURL       string
+	spreadsheetID string
+	httpClient    *http.Client
+	tokens        *tokenSource
+}
+
+// Option configures optional behaviour of the Client
+type Option func(*Client)
+
+// WithBaseURL overrides the Sheets API base URL
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for token and API requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// NewClient creates a client authenticated as the service account. The
+// spreadsheet must be shared with the service account's email address.
+func NewClient(account ServiceAccount, spreadsheetID string, opts ...Option) (*Client, error) {
+	c := &Client{
+		baseURL:       "https://sheets.googleapis.com/v4",
+		spreadsheetID: spreadsheetID,
+		httpClient:    &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	tokens, err := newTokenSource(account, c.httpClient)
+	if err != nil {
+		return nil, err
+	}
+	c.tokens = tokens
+	return c, nil
+}
+
+// Append adds rows after the last row of the named sheet
+func (c *Client) Append(ctx context.Context, sheet string, rows [][]any) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	token, err := c.tokens.Token()
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]any{"values": rows})
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/spreadsheets/%s/values/%s:append?valueInputOption=USER_ENTERED&insertDataOption=INSERT_ROWS",
+		c.baseURL, url.PathEscape(c.spreadsheetID), url.PathEscape(sheet+"!A1"))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to append to sheet %s: %w", sheet, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("sheets API returned status code: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package sheets
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func testAccount(t *testing.T, tokenURI string) ServiceAccount {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	return ServiceAccount{
+		ClientEmail: "dashboard@example.iam.gserviceaccount.com",
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		TokenURI:    tokenURI,
+	}
+}
+
+func TestClient_Append(t *testing.T) {
+	tokenRequests := 0
+	var appended [][]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		r.ParseForm()
+		if parts := strings.Split(r.PostForm.Get("assertion"), "."); len(parts) != 3 {
+			t.Errorf("Expected a signed JWT assertion, got %q", r.PostForm.Get("assertion"))
+		}
+		w.Write([]byte(`{"access_token":"abc","expires_in":3600}`))
+	})
+	mux.HandleFunc("POST /spreadsheets/sheet-id/values/{range}", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer abc" {
+			t.Errorf("Expected bearer token, got %q", got)
+		}
+		if got := r.PathValue("range"); got != "Prices!A1:append" {
+			t.Errorf("Expected append to Prices!A1, got %q", got)
+		}
+		var body struct {
+			Values [][]any `json:"values"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		appended = append(appended, body.Values...)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(testAccount(t, server.URL+"/token"), "sheet-id", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := client.Append(context.Background(), "Prices", [][]any{{"2024-01-01", "bitcoin", 50000}}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if tokenRequests != 1 {
+		t.Errorf("Expected the access token to be cached, got %d token requests", tokenRequests)
+	}
+	if len(appended) != 2 || appended[0][1] != "bitcoin" {
+		t.Errorf("Unexpected appended rows: %v", appended)
+	}
+}
+
+func TestNewClient_InvalidKey(t *testing.T) {
+	if _, err := NewClient(ServiceAccount{ClientEmail: "x", PrivateKey: "not a key"}, "id"); err == nil {
+		t.Error("Expected error for invalid private key")
+	}
+}
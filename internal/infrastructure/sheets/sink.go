@@ -0,0 +1,128 @@
+package sheets
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/clock"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Appender appends rows to a sheet
+type Appender interface {
+	Append(ctx context.Context, sheet string, rows [][]any) error
+}
+
+// PriceSource provides the latest polled prices
+type PriceSource interface {
+	Snapshot() []models.CryptoPrice
+}
+
+// Sink periodically appends price snapshots, or the valuation of fixed
+// holdings when they are set, to a sheet
+type Sink struct {
+	appender Appender
+	sheet    string
+	prices   PriceSource
+	interval time.Duration
+	holdings map[string]float64
+	logger   *slog.Logger
+	clock    clock.Clock
+}
+
+// NewSink creates a sink appending to the named sheet every interval
+func NewSink(appender Appender, sheet string, prices PriceSource, interval time.Duration) *Sink {
+	return &Sink{appender: appender, sheet: sheet, prices: prices, interval: interval, logger: slog.Default(), clock: clock.Real}
+}
+
+// SetHoldings switches the sink from price snapshots to portfolio valuations
+func (s *Sink) SetHoldings(holdings map[string]float64) {
+	s.holdings = holdings
+}
+
+// SetLogger replaces the default logger used to report failed appends
+func (s *Sink) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
+// SetClock replaces the system clock, for tests
+func (s *Sink) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Run appends a row batch on every interval until the context is cancelled
+func (s *Sink) Run(ctx context.Context) {
+	ticker := s.clock.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			if err := s.Flush(ctx); err != nil {
+				s.logger.Warn("sheets append failed", "sheet", s.sheet, "error", err)
+			}
+		}
+	}
+}
+
+// Flush appends the current snapshot immediately
+func (s *Sink) Flush(ctx context.Context) error {
+	return s.appender.Append(ctx, s.sheet, s.rows(s.clock.Now().UTC()))
+}
+
+// rows builds one row per coin: time, coin, price, currency, 24h change for
+// snapshots and time, coin, quantity, price, value, currency for valuations,
+// followed by a total row
+func (s *Sink) rows(now time.Time) [][]any {
+	stamp := now.Format(time.RFC3339)
+	prices := s.prices.Snapshot()
+
+	var rows [][]any
+	if len(s.holdings) == 0 {
+		for _, p := range prices {
+			rows = append(rows, []any{stamp, p.ID, p.PriceFloat(), string(p.Currency), p.PriceChange24h})
+		}
+		return rows
+	}
+
+	sort.Slice(prices, func(i, j int) bool { return prices[i].ID < prices[j].ID })
+	total := decimal.Zero
+	var currency models.Currency
+	for _, p := range prices {
+		qty, ok := s.holdings[p.ID]
+		if !ok {
+			continue
+		}
+		value := decimal.NewFromFloat(qty).Mul(p.CurrentPrice)
+		total = total.Add(value)
+		currency = p.Currency
+		rows = append(rows, []any{stamp, p.ID, qty, p.PriceFloat(), value.InexactFloat64(), string(currency)})
+	}
+	if len(rows) > 0 {
+		rows = append(rows, []any{stamp, "TOTAL", "", "", total.InexactFloat64(), string(currency)})
+	}
+	return rows
+}
+
+// AlertNotifier appends every triggered alert to a sheet. It implements alerts.Notifier.
+type AlertNotifier struct {
+	Appender Appender
+	Sheet    string
+}
+
+// Notify appends the alert as time, rule, coin, kind, price, message
+func (n AlertNotifier) Notify(ctx context.Context, alert models.Alert) error {
+	return n.Appender.Append(ctx, n.Sheet, [][]any{{
+		alert.TriggeredAt.UTC().Format(time.RFC3339),
+		alert.RuleID,
+		alert.CryptoID,
+		string(alert.Kind),
+		alert.Price,
+		alert.Message,
+	}})
+}
@@ -0,0 +1,62 @@
+package sheets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type recordingAppender struct {
+	sheet string
+	rows  [][]any
+}
+
+func (r *recordingAppender) Append(ctx context.Context, sheet string, rows [][]any) error {
+	r.sheet = sheet
+	r.rows = append(r.rows, rows...)
+	return nil
+}
+
+type stubSnapshot []models.CryptoPrice
+
+func (s stubSnapshot) Snapshot() []models.CryptoPrice { return s }
+
+func TestSink_Rows(t *testing.T) {
+	prices := stubSnapshot{
+		{ID: "ethereum", CurrentPrice: decimal.NewFromInt(3000), Currency: models.USD, PriceChange24h: -1},
+		{ID: "bitcoin", CurrentPrice: decimal.NewFromInt(50000), Currency: models.USD, PriceChange24h: 2},
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	sink := NewSink(&recordingAppender{}, "Prices", prices, time.Hour)
+	rows := sink.rows(now)
+	if len(rows) != 2 || rows[0][0] != "2024-05-01T12:00:00Z" || rows[0][1] != "ethereum" || rows[0][2] != 3000.0 {
+		t.Errorf("Unexpected snapshot rows: %v", rows)
+	}
+
+	sink.SetHoldings(map[string]float64{"bitcoin": 0.5, "ethereum": 2})
+	rows = sink.rows(now)
+	if len(rows) != 3 {
+		t.Fatalf("Expected two positions and a total, got %v", rows)
+	}
+	if rows[0][1] != "bitcoin" || rows[0][4] != 25000.0 {
+		t.Errorf("Expected bitcoin valued at 25000, got %v", rows[0])
+	}
+	if rows[2][1] != "TOTAL" || rows[2][4] != 31000.0 {
+		t.Errorf("Expected a total of 31000, got %v", rows[2])
+	}
+}
+
+func TestAlertNotifier(t *testing.T) {
+	appender := &recordingAppender{}
+	n := AlertNotifier{Appender: appender, Sheet: "Alerts"}
+	n.Notify(context.Background(), models.Alert{RuleID: "r1", CryptoID: "bitcoin", Kind: models.AlertPriceAbove, Price: 60000})
+
+	if appender.sheet != "Alerts" || len(appender.rows) != 1 || appender.rows[0][2] != "bitcoin" {
+		t.Errorf("Unexpected alert row: %v", appender.rows)
+	}
+}
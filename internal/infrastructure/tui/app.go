@@ -0,0 +1,202 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+// Source is the live data the dashboard displays. It is satisfied by *poller.Poller,
+// the same abstraction the HTTP server reads from.
+type Source interface {
+	Snapshot() []models.CryptoPrice
+	History(id string) []models.PricePoint
+	Currency() models.Currency
+	LastError() error
+	AddCoin(id string)
+	RemoveCoin(id string)
+	Subscribe() (<-chan struct{}, func())
+}
+
+// MarketSource provides the global market overview without blocking.
+// It is satisfied by *market.Service.
+type MarketSource interface {
+	Latest() (models.GlobalMarket, bool)
+}
+
+// App is the interactive terminal dashboard
+type App struct {
+	source Source
+	market MarketSource
+	in     io.Reader
+	out    io.Writer
+
+	sortKey    SortKey
+	descending bool
+	selected   int
+	inputMode  bool
+	input      string
+	status     string
+}
+
+// NewApp creates a dashboard reading keys from in and drawing to out.
+// The caller is responsible for putting the terminal in raw mode.
+func NewApp(source Source, in io.Reader, out io.Writer) *App {
+	return &App{source: source, in: in, out: out}
+}
+
+// SetMarket shows the global market overview in the header
+func (a *App) SetMarket(market MarketSource) {
+	a.market = market
+}
+
+// Run redraws on every update and handles key presses until q, Ctrl-C or context cancellation
+func (a *App) Run(ctx context.Context) error {
+	updates, cancel := a.source.Subscribe()
+	defer cancel()
+
+	keys := make(chan []byte)
+	go a.readKeys(ctx, keys)
+
+	// Hide the cursor while running and restore it on exit
+	fmt.Fprint(a.out, "\x1b[?25l")
+	defer fmt.Fprint(a.out, "\x1b[?25h\x1b[H\x1b[2J")
+
+	a.draw()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-updates:
+			a.draw()
+		case key, ok := <-keys:
+			if !ok || a.handleKey(key) {
+				return nil
+			}
+			a.draw()
+		}
+	}
+}
+
+func (a *App) readKeys(ctx context.Context, keys chan<- []byte) {
+	defer close(keys)
+	buf := make([]byte, 16)
+	for {
+		n, err := a.in.Read(buf)
+		if err != nil {
+			return
+		}
+		key := append([]byte(nil), buf[:n]...)
+		select {
+		case keys <- key:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// handleKey applies a key press and reports whether the app should quit
+func (a *App) handleKey(key []byte) bool {
+	k := string(key)
+
+	if a.inputMode {
+		switch {
+		case k == "\r" || k == "\n":
+			if id := strings.ToLower(strings.TrimSpace(a.input)); id != "" {
+				a.source.AddCoin(id)
+				a.status = fmt.Sprintf("Added %s", id)
+			}
+			a.inputMode, a.input = false, ""
+		case k == "\x1b":
+			a.inputMode, a.input = false, ""
+		case k == "\x7f" || k == "\b":
+			if len(a.input) > 0 {
+				a.input = a.input[:len(a.input)-1]
+			}
+		case k == "\x03":
+			return true
+		default:
+			a.input += strings.Map(func(r rune) rune {
+				if r >= 32 && r < 127 {
+					return r
+				}
+				return -1
+			}, k)
+		}
+		return false
+	}
+
+	rows := a.rows()
+	switch k {
+	case "q", "\x03":
+		return true
+	case "j", "\x1b[B":
+		if a.selected < len(rows)-1 {
+			a.selected++
+		}
+	case "k", "\x1b[A":
+		if a.selected > 0 {
+			a.selected--
+		}
+	case "s":
+		a.sortKey = a.sortKey.next()
+	case "r":
+		a.descending = !a.descending
+	case "a":
+		a.inputMode = true
+		a.status = ""
+	case "d":
+		if a.selected < len(rows) {
+			id := rows[a.selected].Price.ID
+			a.source.RemoveCoin(id)
+			a.status = fmt.Sprintf("Removed %s", id)
+			if a.selected > 0 && a.selected >= len(rows)-1 {
+				a.selected--
+			}
+		}
+	}
+	return false
+}
+
+// rows builds the sorted table rows from the source
+func (a *App) rows() []Row {
+	snapshot := a.source.Snapshot()
+	rows := make([]Row, len(snapshot))
+	for i, price := range snapshot {
+		points := a.source.History(price.ID)
+		history := make([]float64, len(points))
+		for j, p := range points {
+			history[j] = p.Price
+		}
+		rows[i] = Row{Price: price, History: history}
+	}
+	SortRows(rows, a.sortKey, a.descending)
+	return rows
+}
+
+func (a *App) draw() {
+	status := a.status
+	if err := a.source.LastError(); err != nil {
+		status = "Last poll failed: " + err.Error()
+	}
+	var global *models.GlobalMarket
+	if a.market != nil {
+		if g, ok := a.market.Latest(); ok {
+			global = &g
+		}
+	}
+	fmt.Fprint(a.out, Render(View{
+		Global:     global,
+		Rows:       a.rows(),
+		Currency:   a.source.Currency(),
+		SortKey:    a.sortKey,
+		Descending: a.descending,
+		Selected:   a.selected,
+		Input:      a.input,
+		InputMode:  a.inputMode,
+		Status:     status,
+	}))
+}
@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"testing"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+type fakeSource struct {
+	prices []models.CryptoPrice
+	added  []string
+}
+
+func (f *fakeSource) Snapshot() []models.CryptoPrice        { return f.prices }
+func (f *fakeSource) History(id string) []models.PricePoint { return nil }
+func (f *fakeSource) Currency() models.Currency             { return models.USD }
+func (f *fakeSource) LastError() error                      { return nil }
+func (f *fakeSource) Subscribe() (<-chan struct{}, func())  { return nil, func() {} }
+func (f *fakeSource) AddCoin(id string)                     { f.added = append(f.added, id) }
+func (f *fakeSource) RemoveCoin(id string) {
+	for i, p := range f.prices {
+		if p.ID == id {
+			f.prices = append(f.prices[:i], f.prices[i+1:]...)
+			return
+		}
+	}
+}
+
+func TestApp_HandleKey(t *testing.T) {
+	source := &fakeSource{prices: []models.CryptoPrice{{ID: "bitcoin"}, {ID: "ethereum"}}}
+	app := NewApp(source, nil, nil)
+
+	for _, key := range []string{"a", "S", "o", "l", "\x7f", "l", "\r"} {
+		app.handleKey([]byte(key))
+	}
+	if len(source.added) != 1 || source.added[0] != "sol" {
+		t.Errorf("Expected 'sol' to be added, got %v", source.added)
+	}
+
+	app.handleKey([]byte("j"))
+	app.handleKey([]byte("d"))
+	if len(source.prices) != 1 || source.prices[0].ID != "bitcoin" {
+		t.Errorf("Expected ethereum to be removed, got %+v", source.prices)
+	}
+	if app.selected != 0 {
+		t.Errorf("Expected selection to move back to 0, got %d", app.selected)
+	}
+
+	if !app.handleKey([]byte("q")) {
+		t.Error("Expected q to quit")
+	}
+}
@@ -0,0 +1,183 @@
+// Package tui renders the live terminal dashboard
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"crypto-dashboard/internal/application/format"
+	"crypto-dashboard/internal/domain/models"
+)
+
+// SortKey selects the column the table is sorted by
+type SortKey int
+
+const (
+	// SortTracked keeps the order in which coins are tracked
+	SortTracked SortKey = iota
+	// SortName sorts alphabetically by coin ID
+	SortName
+	// SortPrice sorts by current price
+	SortPrice
+	// SortChange sorts by 24h change
+	SortChange
+)
+
+var sortKeyNames = map[SortKey]string{
+	SortTracked: "tracked",
+	SortName:    "name",
+	SortPrice:   "price",
+	SortChange:  "24h %",
+}
+
+// String returns the column name of the sort key
+func (k SortKey) String() string {
+	return sortKeyNames[k]
+}
+
+// next cycles to the following sort key
+func (k SortKey) next() SortKey {
+	return (k + 1) % SortKey(len(sortKeyNames))
+}
+
+// Row is a coin rendered in the table
+type Row struct {
+	Price   models.CryptoPrice
+	History []float64
+}
+
+// View is everything needed to draw one frame
+type View struct {
+	Rows       []Row
+	Currency   models.Currency
+	SortKey    SortKey
+	Descending bool
+	Selected   int
+	Input      string
+	InputMode  bool
+	Status     string
+	// Global is the market overview shown under the title, when known
+	Global *models.GlobalMarket
+}
+
+const sparkWidth = 30
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws the last width values as a line of block characters
+func Sparkline(values []float64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if len(values) == 0 {
+		return ""
+	}
+
+	low, high := values[0], values[0]
+	for _, v := range values {
+		low = min(low, v)
+		high = max(high, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if high > low {
+			level = int((v - low) / (high - low) * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// SortRows orders rows in place by the given key
+func SortRows(rows []Row, key SortKey, descending bool) {
+	if key == SortTracked {
+		if descending {
+			for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+				rows[i], rows[j] = rows[j], rows[i]
+			}
+		}
+		return
+	}
+
+	less := func(a, b Row) bool {
+		switch key {
+		case SortPrice:
+			return a.Price.CurrentPrice.LessThan(b.Price.CurrentPrice)
+		case SortChange:
+			return a.Price.PriceChange24h < b.Price.PriceChange24h
+		default:
+			return a.Price.ID < b.Price.ID
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if descending {
+			return less(rows[j], rows[i])
+		}
+		return less(rows[i], rows[j])
+	})
+}
+
+// Render draws a full frame. Lines end with \r\n because the terminal is in raw mode.
+func Render(v View) string {
+	const (
+		reset   = "\x1b[0m"
+		bold    = "\x1b[1m"
+		reverse = "\x1b[7m"
+		green   = "\x1b[32m"
+		red     = "\x1b[31m"
+	)
+
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+
+	direction := "asc"
+	if v.Descending {
+		direction = "desc"
+	}
+	fmt.Fprintf(&b, "%sCrypto Dashboard%s  (%s, sorted by %s %s)\r\n",
+		bold, reset, strings.ToUpper(string(v.Currency)), v.SortKey, direction)
+	if g := v.Global; g != nil {
+		color := green
+		if g.MarketCapChange24h < 0 {
+			color = red
+		}
+		fmt.Fprintf(&b, "Market cap %s %s%+.2f%%%s  Volume 24h %s  BTC %.1f%%  ETH %.1f%%\r\n",
+			format.Compact(g.TotalMarketCap), color, g.MarketCapChange24h, reset,
+			format.Compact(g.TotalVolume), g.BTCDominance, g.ETHDominance)
+	}
+	b.WriteString("\r\n")
+	fmt.Fprintf(&b, "%s%-4s %-20s %16s %9s  %-*s%s\r\n",
+		bold, "#", "Coin", "Price", "24h %", sparkWidth, "Trend", reset)
+
+	for i, row := range v.Rows {
+		color := green
+		if row.Price.PriceChange24h < 0 {
+			color = red
+		}
+		line := fmt.Sprintf("%-4d %-20.20s %16.4f %s%+8.2f%%%s  %s",
+			i+1, row.Price.ID, row.Price.PriceFloat(),
+			color, row.Price.PriceChange24h, reset,
+			Sparkline(row.History, sparkWidth))
+		if i == v.Selected {
+			line = reverse + line + reset
+		}
+		b.WriteString(line + "\r\n")
+	}
+	if len(v.Rows) == 0 {
+		b.WriteString("  waiting for prices...\r\n")
+	}
+
+	b.WriteString("\r\n")
+	if v.InputMode {
+		fmt.Fprintf(&b, "Add coin ID: %s█\r\n", v.Input)
+	} else {
+		b.WriteString("j/k move  s sort  r reverse  a add  d remove  q quit\r\n")
+	}
+	if v.Status != "" {
+		b.WriteString(v.Status + "\r\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"crypto-dashboard/internal/domain/models"
+)
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline([]float64{1, 2, 3, 4, 5, 6, 7, 8}, 10); got != "▁▂▃▄▅▆▇█" {
+		t.Errorf("Unexpected sparkline: %s", got)
+	}
+	if got := Sparkline([]float64{5, 5, 5}, 10); got != "▁▁▁" {
+		t.Errorf("Expected flat sparkline, got %s", got)
+	}
+	if got := Sparkline([]float64{1, 2, 3, 4}, 2); len([]rune(got)) != 2 {
+		t.Errorf("Expected sparkline truncated to width 2, got %s", got)
+	}
+	if got := Sparkline(nil, 10); got != "" {
+		t.Errorf("Expected empty sparkline, got %s", got)
+	}
+}
+
+func TestSortRows(t *testing.T) {
+	rows := func() []Row {
+		return []Row{
+			{Price: models.CryptoPrice{ID: "ethereum", CurrentPrice: decimal.NewFromInt(3000), PriceChange24h: 5}},
+			{Price: models.CryptoPrice{ID: "bitcoin", CurrentPrice: decimal.NewFromInt(50000), PriceChange24h: -2}},
+			{Price: models.CryptoPrice{ID: "solana", CurrentPrice: decimal.NewFromInt(100), PriceChange24h: 1}},
+		}
+	}
+
+	tests := []struct {
+		key        SortKey
+		descending bool
+		wantFirst  string
+	}{
+		{key: SortTracked, wantFirst: "ethereum"},
+		{key: SortTracked, descending: true, wantFirst: "solana"},
+		{key: SortName, wantFirst: "bitcoin"},
+		{key: SortPrice, descending: true, wantFirst: "bitcoin"},
+		{key: SortChange, wantFirst: "bitcoin"},
+		{key: SortChange, descending: true, wantFirst: "ethereum"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key.String(), func(t *testing.T) {
+			r := rows()
+			SortRows(r, tt.key, tt.descending)
+			if r[0].Price.ID != tt.wantFirst {
+				t.Errorf("Expected %s first, got %s", tt.wantFirst, r[0].Price.ID)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	frame := Render(View{
+		Rows: []Row{
+			{Price: models.CryptoPrice{ID: "bitcoin", CurrentPrice: decimal.NewFromInt(50000), PriceChange24h: -1.5}, History: []float64{1, 2}},
+		},
+		Currency:  models.EUR,
+		InputMode: true,
+		Input:     "sol",
+	})
+
+	for _, want := range []string{"EUR", "bitcoin", "-1.50%", "Add coin ID: sol"} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("Expected frame to contain %q", want)
+		}
+	}
+}
+
+func TestRender_Global(t *testing.T) {
+	frame := Render(View{
+		Currency: models.USD,
+		Global:   &models.GlobalMarket{TotalMarketCap: 2.5e12, TotalVolume: 9e10, MarketCapChange24h: -1.2, BTCDominance: 52.1, ETHDominance: 16.9},
+	})
+	for _, want := range []string{"Market cap 2.50T", "-1.20%", "Volume 24h 90.00B", "BTC 52.1%", "ETH 16.9%"} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("Expected frame to contain %q, got %q", want, frame)
+		}
+	}
+}
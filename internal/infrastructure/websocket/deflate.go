@@ -0,0 +1,135 @@
+package websocket
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+)
+
+// DefaultCompressionThreshold is the smallest message compressed when
+// Compression sets no threshold. Smaller messages barely shrink and the
+// deflate block overhead can make them larger.
+const DefaultCompressionThreshold = 256
+
+// deflateResponse accepts permessage-deflate without context takeover in
+// either direction. Every message is compressed on its own, so no connection
+// keeps a compressor or a 32 KiB window between messages and the compressors
+// are shared through a pool.
+const deflateResponse = "permessage-deflate; server_no_context_takeover; client_no_context_takeover"
+
+// deflateTail is the empty stored block ending a flushed deflate stream,
+// which RFC 7692 removes from every message
+var deflateTail = []byte{0x00, 0x00, 0xff, 0xff}
+
+var errCorruptMessage = errors.New("websocket: corrupt compressed message")
+
+// Compression configures permessage-deflate
+type Compression struct {
+	Enabled bool
+	// Threshold is the smallest message, in bytes, that is compressed;
+	// DefaultCompressionThreshold when zero
+	Threshold int
+	// Level is the flate level, from flate.BestSpeed to flate.BestCompression;
+	// flate.BestSpeed when zero. Higher levels cost more CPU for smaller messages.
+	Level int
+}
+
+func (c Compression) threshold() int {
+	if c.Threshold <= 0 {
+		return DefaultCompressionThreshold
+	}
+	return c.Threshold
+}
+
+func (c Compression) level() int {
+	if c.Level < flate.BestSpeed || c.Level > flate.BestCompression {
+		return flate.BestSpeed
+	}
+	return c.Level
+}
+
+// acceptDeflate reports whether one of the offered extensions is a
+// permessage-deflate the server can accept. Offers that limit the server
+// window are declined since compress/flate always uses a 32 KiB window.
+func acceptDeflate(offers []string) bool {
+	for _, header := range offers {
+		for _, offer := range strings.Split(header, ",") {
+			params := strings.Split(offer, ";")
+			if strings.TrimSpace(params[0]) != "permessage-deflate" {
+				continue
+			}
+			acceptable := true
+			for _, p := range params[1:] {
+				name, value, _ := strings.Cut(strings.TrimSpace(p), "=")
+				switch name {
+				case "server_no_context_takeover", "client_no_context_takeover":
+				case "client_max_window_bits":
+					// Any client window can be inflated
+				case "server_max_window_bits":
+					acceptable = acceptable && strings.Trim(value, `"`) == "15"
+				default:
+					acceptable = false
+				}
+			}
+			if acceptable {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Compressors and decompressors are reused across messages and connections
+var (
+	writerPools [flate.BestCompression + 1]sync.Pool
+	readerPool  sync.Pool
+)
+
+// deflate compresses a message and strips the trailing empty block
+func deflate(data []byte, level int) ([]byte, error) {
+	var buf bytes.Buffer
+	w, ok := writerPools[level].Get().(*flate.Writer)
+	if ok {
+		w.Reset(&buf)
+	} else {
+		var err error
+		if w, err = flate.NewWriter(&buf, level); err != nil {
+			return nil, err
+		}
+	}
+	defer writerPools[level].Put(w)
+
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), deflateTail), nil
+}
+
+// inflate decompresses a message, failing with ErrMessageTooLarge when it
+// would exceed limit bytes
+func inflate(data []byte, limit int64) ([]byte, error) {
+	src := io.MultiReader(bytes.NewReader(data), bytes.NewReader(deflateTail))
+	r, ok := readerPool.Get().(io.ReadCloser)
+	if ok {
+		r.(flate.Resetter).Reset(src, nil)
+	} else {
+		r = flate.NewReader(src)
+	}
+	defer readerPool.Put(r)
+
+	out, err := io.ReadAll(io.LimitReader(r, limit+1))
+	// The stream has no final block, so reaching the end of the input is expected
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, errCorruptMessage
+	}
+	if int64(len(out)) > limit {
+		return nil, ErrMessageTooLarge
+	}
+	return out, nil
+}
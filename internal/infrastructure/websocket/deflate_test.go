@@ -0,0 +1,96 @@
+package websocket
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// watchlistUpdate is a price update of a large watchlist, the payload
+// compression is meant for
+func watchlistUpdate(coins int) []byte {
+	type price struct {
+		ID        string  `json:"id"`
+		Symbol    string  `json:"symbol"`
+		Price     string  `json:"current_price"`
+		Change24h float64 `json:"price_change_percentage_24h"`
+		Updated   string  `json:"last_updated"`
+	}
+	prices := make([]price, coins)
+	for i := range prices {
+		prices[i] = price{
+			ID:        fmt.Sprintf("coin-%d", i),
+			Symbol:    fmt.Sprintf("c%d", i),
+			Price:     fmt.Sprintf("%d.%04d", 1000+i*37, i*7919%10000),
+			Change24h: float64(i%13) - 6.5,
+			Updated:   "2026-10-16T12:00:00Z",
+		}
+	}
+	data, _ := json.Marshal(map[string]any{"type": "update", "topic": "coins", "data": prices})
+	return data
+}
+
+func TestDeflate_RoundTrip(t *testing.T) {
+	msg := watchlistUpdate(200)
+	compressed, err := deflate(msg, flate.BestSpeed)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if bytes.HasSuffix(compressed, deflateTail) {
+		t.Error("Expected the trailing empty block to be stripped")
+	}
+	if len(compressed) > len(msg)/3 {
+		t.Errorf("Expected a watchlist update to shrink, got %d of %d bytes", len(compressed), len(msg))
+	}
+
+	inflated, err := inflate(compressed, int64(len(msg)))
+	if err != nil || !bytes.Equal(inflated, msg) {
+		t.Fatalf("Expected the message back, got %d bytes and %v", len(inflated), err)
+	}
+	if _, err := inflate(compressed, int64(len(msg)-1)); !errors.Is(err, ErrMessageTooLarge) {
+		t.Errorf("Expected the limit to apply to the inflated size, got %v", err)
+	}
+	if _, err := inflate([]byte{0xff, 0xff, 0xff}, 1024); !errors.Is(err, errCorruptMessage) {
+		t.Errorf("Expected corrupt data to be rejected, got %v", err)
+	}
+}
+
+// BenchmarkWriteMessage compares the bytes sent and the cost of writing a
+// 200 coin watchlist update without compression and at several flate levels
+func BenchmarkWriteMessage(b *testing.B) {
+	msg := watchlistUpdate(200)
+	for _, bc := range []struct {
+		name        string
+		compression Compression
+	}{
+		{"plain", Compression{}},
+		{"deflate-fastest", Compression{Enabled: true, Level: flate.BestSpeed}},
+		{"deflate-6", Compression{Enabled: true, Level: 6}},
+		{"deflate-smallest", Compression{Enabled: true, Level: flate.BestCompression}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			counting := &countingConn{}
+			conn := newConn(counting, nil, true, bc.compression.Enabled, Options{Compression: bc.compression})
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				conn.WriteMessage(TextMessage, msg)
+			}
+			b.ReportMetric(float64(counting.written.Load())/float64(b.N), "wire-bytes/op")
+			b.ReportMetric(float64(len(msg)), "message-bytes")
+		})
+	}
+}
+
+// BenchmarkInflate measures reading a compressed watchlist update
+func BenchmarkInflate(b *testing.B) {
+	msg := watchlistUpdate(200)
+	compressed, _ := deflate(msg, flate.BestSpeed)
+	b.ReportAllocs()
+	for range b.N {
+		inflate(compressed, DefaultMaxMessageSize)
+	}
+}
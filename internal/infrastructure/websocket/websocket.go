@@ -0,0 +1,419 @@
+// Package websocket is a small RFC 6455 server with per-message compression
+// (permessage-deflate, RFC 7692). It answers control frames and joins
+// fragments, so handlers only read and write whole messages.
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Message types
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+)
+
+// Frame opcodes besides the message types
+const (
+	opContinuation = 0
+	opClose        = 8
+	opPing         = 9
+	opPong         = 10
+)
+
+// Close codes sent when the connection ends
+const (
+	closeNormal          = 1000
+	closeProtocolError   = 1002
+	closeInvalidPayload  = 1007
+	closeMessageTooLarge = 1009
+)
+
+// DefaultMaxMessageSize limits received messages when Options sets no limit
+const DefaultMaxMessageSize = 64 << 10
+
+// acceptGUID is appended to the client key to compute Sec-WebSocket-Accept
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+var (
+	// ErrMessageTooLarge is returned when a received message, once
+	// decompressed, exceeds the maximum message size
+	ErrMessageTooLarge = errors.New("websocket: message too large")
+	// ErrClosed is returned by ReadMessage once the peer closed the connection
+	ErrClosed = errors.New("websocket: connection closed")
+
+	errForbiddenOrigin = errors.New("websocket: origin not allowed")
+)
+
+// protocolError is a frame the peer should not have sent
+type protocolError string
+
+func (e protocolError) Error() string { return "websocket: " + string(e) }
+
+// Options configure an upgrade
+type Options struct {
+	// CheckOrigin accepts or rejects the origin of a handshake; every origin
+	// is accepted when it is nil
+	CheckOrigin func(r *http.Request) bool
+	// Compression negotiates permessage-deflate with clients that offer it
+	Compression Compression
+	// MaxMessageSize limits received messages after decompression, in bytes;
+	// DefaultMaxMessageSize is used when it is zero
+	MaxMessageSize int64
+	// IdleTimeout ends ReadMessage when no frame, pongs included, arrives for
+	// this long; zero waits forever
+	IdleTimeout time.Duration
+}
+
+// Conn is an upgraded connection. Reads must come from one goroutine; writes
+// may come from several.
+type Conn struct {
+	conn        net.Conn
+	br          *bufio.Reader
+	server      bool
+	deflate     bool
+	compression Compression
+	maxSize     int64
+	idle        time.Duration
+
+	wmu       sync.Mutex
+	closeOnce sync.Once
+}
+
+// Upgrade completes the WebSocket handshake of a request. On failure it has
+// already answered the request with an HTTP error.
+func Upgrade(w http.ResponseWriter, r *http.Request, opts Options) (*Conn, error) {
+	if r.Method != http.MethodGet || !headerHas(r.Header, "Connection", "upgrade") || !headerHas(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "expected a websocket upgrade", http.StatusBadRequest)
+		return nil, errors.New("websocket: not an upgrade request")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported websocket version", http.StatusUpgradeRequired)
+		return nil, errors.New("websocket: unsupported version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		http.Error(w, "invalid websocket key", http.StatusBadRequest)
+		return nil, errors.New("websocket: invalid key")
+	}
+	if opts.CheckOrigin != nil && !opts.CheckOrigin(r) {
+		http.Error(w, errForbiddenOrigin.Error(), http.StatusForbidden)
+		return nil, errForbiddenOrigin
+	}
+
+	deflate := opts.Compression.Enabled && acceptDeflate(r.Header.Values("Sec-WebSocket-Extensions"))
+	netConn, brw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		http.Error(w, "websocket upgrade not supported", http.StatusInternalServerError)
+		return nil, fmt.Errorf("websocket: %w", err)
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n"
+	if deflate {
+		response += "Sec-WebSocket-Extensions: " + deflateResponse + "\r\n"
+	}
+	if _, err := brw.WriteString(response + "\r\n"); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	if err := brw.Flush(); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	// Handlers may have set a deadline on the request; the connection outlives it
+	netConn.SetDeadline(time.Time{})
+	return newConn(netConn, brw.Reader, true, deflate, opts), nil
+}
+
+func newConn(netConn net.Conn, br *bufio.Reader, server, deflate bool, opts Options) *Conn {
+	maxSize := opts.MaxMessageSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxMessageSize
+	}
+	return &Conn{
+		conn:        netConn,
+		br:          br,
+		server:      server,
+		deflate:     deflate,
+		compression: opts.Compression,
+		maxSize:     maxSize,
+		idle:        opts.IdleTimeout,
+	}
+}
+
+// acceptKey computes the Sec-WebSocket-Accept value of a client key
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// headerHas reports whether a comma separated header lists the token
+func headerHas(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, v := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(v), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Compressed reports whether permessage-deflate was negotiated
+func (c *Conn) Compressed() bool {
+	return c.deflate
+}
+
+// RemoteAddr returns the address of the peer
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// ReadMessage returns the next text or binary message. Pings are answered and
+// pongs skipped on the way. After the peer closes the connection it returns ErrClosed.
+func (c *Conn) ReadMessage() (int, []byte, error) {
+	var (
+		msgType    int
+		compressed bool
+		payload    []byte
+	)
+	for {
+		if c.idle > 0 {
+			c.conn.SetReadDeadline(time.Now().Add(c.idle))
+		}
+		fin, rsv1, op, data, err := c.readFrame()
+		if err != nil {
+			return 0, nil, c.fail(err)
+		}
+
+		switch op {
+		case opPing:
+			if err := c.writeFrame(opPong, false, data); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			code := closeNormal
+			if len(data) >= 2 {
+				code = int(binary.BigEndian.Uint16(data))
+			}
+			c.closeWith(code)
+			return 0, nil, ErrClosed
+		case opContinuation:
+			if msgType == 0 || rsv1 {
+				return 0, nil, c.fail(protocolError("unexpected continuation frame"))
+			}
+		case TextMessage, BinaryMessage:
+			if msgType != 0 {
+				return 0, nil, c.fail(protocolError("new message before the last one ended"))
+			}
+			if rsv1 && !c.deflate {
+				return 0, nil, c.fail(protocolError("compressed frame without permessage-deflate"))
+			}
+			msgType, compressed = int(op), rsv1
+		default:
+			return 0, nil, c.fail(protocolError(fmt.Sprintf("unknown opcode %d", op)))
+		}
+
+		if int64(len(payload)+len(data)) > c.maxSize {
+			return 0, nil, c.fail(ErrMessageTooLarge)
+		}
+		payload = append(payload, data...)
+		if !fin {
+			continue
+		}
+
+		if compressed {
+			if payload, err = inflate(payload, c.maxSize); err != nil {
+				return 0, nil, c.fail(err)
+			}
+		}
+		if msgType == TextMessage && !utf8.Valid(payload) {
+			return 0, nil, c.fail(protocolError("text message is not valid UTF-8"))
+		}
+		return msgType, payload, nil
+	}
+}
+
+// readFrame reads one frame and unmasks its payload
+func (c *Conn) readFrame() (fin, rsv1 bool, op byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(c.br, header[:]); err != nil {
+		return
+	}
+	fin, rsv1, op = header[0]&0x80 != 0, header[0]&0x40 != 0, header[0]&0x0f
+	if header[0]&0x30 != 0 {
+		err = protocolError("reserved bits set")
+		return
+	}
+	masked := header[1]&0x80 != 0
+	if masked != c.server {
+		err = protocolError("frame masking does not match the peer role")
+		return
+	}
+
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if op >= opClose && (!fin || length > 125) {
+		err = protocolError("invalid control frame")
+		return
+	}
+	if length > uint64(c.maxSize) {
+		err = ErrMessageTooLarge
+		return
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+			return
+		}
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return
+	}
+	if masked {
+		maskBytes(mask, payload)
+	}
+	return
+}
+
+// fail closes the connection with the code matching a read error
+func (c *Conn) fail(err error) error {
+	var protoErr protocolError
+	switch {
+	case errors.Is(err, ErrMessageTooLarge):
+		c.closeWith(closeMessageTooLarge)
+	case errors.Is(err, errCorruptMessage):
+		c.closeWith(closeInvalidPayload)
+	case errors.As(err, &protoErr):
+		c.closeWith(closeProtocolError)
+	default:
+		c.conn.Close()
+	}
+	return err
+}
+
+// WriteMessage sends a text or binary message, compressed when
+// permessage-deflate was negotiated and it reaches the threshold
+func (c *Conn) WriteMessage(msgType int, data []byte) error {
+	if msgType != TextMessage && msgType != BinaryMessage {
+		return fmt.Errorf("websocket: invalid message type %d", msgType)
+	}
+	if c.deflate && len(data) >= c.compression.threshold() {
+		compressed, err := deflate(data, c.compression.level())
+		if err != nil {
+			return err
+		}
+		return c.writeFrame(byte(msgType), true, compressed)
+	}
+	return c.writeFrame(byte(msgType), false, data)
+}
+
+// WriteJSON sends v encoded as JSON in a text message
+func (c *Conn) WriteJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.WriteMessage(TextMessage, data)
+}
+
+// Ping sends a ping frame; the peer answers with a pong that renews the idle timeout
+func (c *Conn) Ping() error {
+	return c.writeFrame(opPing, false, nil)
+}
+
+// SetWriteDeadline limits how long writes may block
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
+
+// Close sends a normal close frame and closes the connection
+func (c *Conn) Close() error {
+	c.closeWith(closeNormal)
+	return nil
+}
+
+// closeWith sends a close frame with the code, once, and closes the connection
+func (c *Conn) closeWith(code int) {
+	c.closeOnce.Do(func() {
+		var payload [2]byte
+		binary.BigEndian.PutUint16(payload[:], uint16(code))
+		c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+		c.writeFrame(opClose, false, payload[:])
+		c.conn.Close()
+	})
+}
+
+// writeFrame writes one final frame, masked when the connection is a client's
+func (c *Conn) writeFrame(op byte, rsv1 bool, payload []byte) error {
+	header := make([]byte, 2, 14)
+	header[0] = 0x80 | op
+	if rsv1 {
+		header[0] |= 0x40
+	}
+	switch n := len(payload); {
+	case n <= 125:
+		header[1] = byte(n)
+	case n <= 0xffff:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	if !c.server {
+		var mask [4]byte
+		rand.Read(mask[:])
+		header[1] |= 0x80
+		header = append(header, mask[:]...)
+		payload = append([]byte(nil), payload...)
+		maskBytes(mask, payload)
+	}
+
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if _, err := c.conn.Write(append(header, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func maskBytes(mask [4]byte, b []byte) {
+	for i := range b {
+		b[i] ^= mask[i%4]
+	}
+}
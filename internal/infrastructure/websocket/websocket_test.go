@@ -0,0 +1,199 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingConn counts the bytes written to the connection
+type countingConn struct {
+	net.Conn
+	written atomic.Int64
+}
+
+func (c *countingConn) Write(b []byte) (int, error) {
+	c.written.Add(int64(len(b)))
+	if c.Conn == nil {
+		return len(b), nil
+	}
+	return c.Conn.Write(b)
+}
+
+// dial opens a client connection offering the extensions, if any
+func dial(t *testing.T, url, extensions string) (*Conn, *http.Response) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	if extensions != "" {
+		req.Header.Set("Sec-WebSocket-Extensions", extensions)
+	}
+
+	netConn, err := net.Dial("tcp", req.URL.Host)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { netConn.Close() })
+	netConn.SetDeadline(time.Now().Add(2 * time.Second))
+	req.Write(netConn)
+	br := bufio.NewReader(netConn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	deflate := resp.Header.Get("Sec-WebSocket-Extensions") != ""
+	return newConn(netConn, br, false, deflate, Options{}), resp
+}
+
+// echoServer upgrades with the options and echoes every message
+func echoServer(t *testing.T, opts Options, errs chan<- error) string {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r, opts)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			msgType, data, err := conn.ReadMessage()
+			if err != nil {
+				if errs != nil {
+					errs <- err
+				}
+				return
+			}
+			conn.WriteMessage(msgType, data)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts.URL
+}
+
+func TestUpgrade_EchoesMessages(t *testing.T) {
+	conn, resp := dial(t, echoServer(t, Options{}, nil), "")
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Unexpected handshake: %d %v", resp.StatusCode, resp.Header)
+	}
+	if conn.Compressed() {
+		t.Error("Expected no compression without an offer")
+	}
+
+	for _, msg := range []string{"hello", strings.Repeat("x", 1000), strings.Repeat("y", 40000)} {
+		if err := conn.WriteMessage(TextMessage, []byte(msg)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		msgType, data, err := conn.ReadMessage()
+		if err != nil || msgType != TextMessage || string(data) != msg {
+			t.Fatalf("Expected the message echoed, got %d bytes and %v", len(data), err)
+		}
+	}
+}
+
+func TestUpgrade_NegotiatesDeflate(t *testing.T) {
+	url := echoServer(t, Options{Compression: Compression{Enabled: true, Threshold: 64}}, nil)
+	conn, resp := dial(t, url, "permessage-deflate; client_max_window_bits")
+	if got := resp.Header.Get("Sec-WebSocket-Extensions"); got != deflateResponse {
+		t.Fatalf("Expected permessage-deflate to be accepted, got %q", got)
+	}
+	counting := &countingConn{Conn: conn.conn}
+	conn.conn = counting
+
+	msg := []byte(strings.Repeat(`{"id":"bitcoin","price":"64210.12"},`, 100))
+	conn.WriteMessage(TextMessage, msg)
+	if _, data, err := conn.ReadMessage(); err != nil || !bytes.Equal(data, msg) {
+		t.Fatalf("Expected the message echoed, got %d bytes and %v", len(data), err)
+	}
+	if n := counting.written.Load(); n > int64(len(msg))/10 {
+		t.Errorf("Expected a compressed message, %d bytes were written for %d", n, len(msg))
+	}
+
+	// Below the threshold messages are sent as is
+	conn.WriteMessage(TextMessage, []byte("short"))
+	if _, data, _ := conn.ReadMessage(); string(data) != "short" {
+		t.Errorf("Expected the short message echoed, got %q", data)
+	}
+}
+
+func TestUpgrade_DeclinesDeflateWhenDisabled(t *testing.T) {
+	_, resp := dial(t, echoServer(t, Options{}, nil), "permessage-deflate")
+	if got := resp.Header.Get("Sec-WebSocket-Extensions"); got != "" {
+		t.Errorf("Expected no extension, got %q", got)
+	}
+}
+
+func TestReadMessage_LimitsDecompressedSize(t *testing.T) {
+	errs := make(chan error, 1)
+	url := echoServer(t, Options{Compression: Compression{Enabled: true}, MaxMessageSize: 1024}, errs)
+	conn, _ := dial(t, url, "permessage-deflate")
+
+	// A megabyte of zeros compresses to about a kilobyte
+	conn.WriteMessage(BinaryMessage, make([]byte, 1<<20))
+	if err := <-errs; !errors.Is(err, ErrMessageTooLarge) {
+		t.Errorf("Expected the message to be rejected, got %v", err)
+	}
+	if _, _, err := conn.ReadMessage(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected the server to close the connection, got %v", err)
+	}
+}
+
+func TestReadMessage_IdleTimeout(t *testing.T) {
+	errs := make(chan error, 1)
+	conn, _ := dial(t, echoServer(t, Options{IdleTimeout: 50 * time.Millisecond}, errs), "")
+	defer conn.Close()
+
+	var timeout net.Error
+	if err := <-errs; !errors.As(err, &timeout) || !timeout.Timeout() {
+		t.Errorf("Expected an idle timeout, got %v", err)
+	}
+}
+
+func TestUpgrade_Rejects(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Upgrade(w, r, Options{CheckOrigin: func(r *http.Request) bool { return r.Header.Get("Origin") == "" }})
+	}))
+	defer ts.Close()
+
+	if resp, err := http.Get(ts.URL); err != nil || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected a plain request to be rejected, got %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Origin", "https://evil.example")
+	if resp, err := http.DefaultClient.Do(req); err != nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected a foreign origin to be rejected, got %v", err)
+	}
+}
+
+func TestAcceptDeflate(t *testing.T) {
+	tests := []struct {
+		offers []string
+		want   bool
+	}{
+		{[]string{"permessage-deflate"}, true},
+		{[]string{"permessage-deflate; client_max_window_bits; server_no_context_takeover"}, true},
+		{[]string{"permessage-deflate; server_max_window_bits=10"}, false},
+		{[]string{"permessage-deflate; server_max_window_bits=10, permessage-deflate"}, true},
+		{[]string{"x-webkit-deflate-frame"}, false},
+		{[]string{"permessage-deflate; unknown_param"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := acceptDeflate(tt.offers); got != tt.want {
+			t.Errorf("acceptDeflate(%q) = %v, want %v", tt.offers, got, tt.want)
+		}
+	}
+}